@@ -7,6 +7,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/golang/geo/r3"
 	"github.com/pkg/errors"
 
 	"go.viam.com/rdk/components/arm"
@@ -37,12 +38,23 @@ var dofbotjson []byte
 type Config struct {
 	ArmModel      string `json:"arm-model,omitempty"`
 	ModelFilePath string `json:"model-path,omitempty"`
+
+	// When set, the arm simulates moving between joint positions at the given maximum joint speed rather than teleporting.
+	// Revolute joints interpret these values in degrees and prismatic joints interpret them in mm.
+	MaxJointSpeedDegsPerSec  float64 `json:"max-joint-speed-degs-per-sec,omitempty"`
+	MaxJointAccelDegsPerSec2 float64 `json:"max-joint-accel-degs-per-sec2,omitempty"`
 }
 
 // Validate ensures all parts of the config are valid.
 func (conf *Config) Validate(path string) ([]string, error) {
 	var err error
 	switch {
+	case conf.MaxJointSpeedDegsPerSec < 0:
+		err = errors.New("max-joint-speed-degs-per-sec cannot be negative")
+	case conf.MaxJointAccelDegsPerSec2 < 0:
+		err = errors.New("max-joint-accel-degs-per-sec2 cannot be negative")
+	case conf.MaxJointAccelDegsPerSec2 > 0 && conf.MaxJointSpeedDegsPerSec == 0:
+		err = errors.New("max-joint-accel-degs-per-sec2 requires max-joint-speed-degs-per-sec to be set")
 	case conf.ArmModel != "" && conf.ModelFilePath != "":
 		err = errAttrCfgPopulation
 	case conf.ArmModel != "" && conf.ModelFilePath == "":
@@ -103,6 +115,16 @@ type Arm struct {
 	mu     sync.RWMutex
 	joints []referenceframe.Input
 	model  referenceframe.Model
+
+	// per joint velocity and acceleration limits in the units of the model's inputs, nil limits disable simulation
+	maxVel []float64
+	maxAcc []float64
+	// payload slows simulated moves to suit its load, and is nil when the arm carries none
+	payload *referenceframe.Payload
+
+	// cancelMove and moveDone are set for the duration of a simulated move
+	cancelMove context.CancelFunc
+	moveDone   chan struct{}
 }
 
 // Reconfigure atomically reconfigures this arm in place based on the new config.
@@ -124,10 +146,18 @@ func (a *Arm) Reconfigure(ctx context.Context, deps resource.Dependencies, conf
 			"the arm-model and model-path from attributes")
 	}
 
+	// a simulated move in progress was planned for the old model and must not outlive it
+	a.stopMove()
+
 	a.mu.Lock()
 	defer a.mu.Unlock()
 	a.joints = referenceframe.FloatsToInputs(make([]float64, dof))
 	a.model = model
+	a.maxVel, a.maxAcc = nil, nil
+	if newConf.MaxJointSpeedDegsPerSec > 0 {
+		a.maxVel = jointLimits(model, newConf.MaxJointSpeedDegsPerSec)
+		a.maxAcc = jointLimits(model, newConf.MaxJointAccelDegsPerSec2)
+	}
 
 	return nil
 }
@@ -153,54 +183,69 @@ func (a *Arm) EndPosition(ctx context.Context, extra map[string]interface{}) (sp
 // MoveToPosition sets the position.
 func (a *Arm) MoveToPosition(ctx context.Context, pose spatialmath.Pose, extra map[string]interface{}) error {
 	a.mu.RLock()
-	defer a.mu.RUnlock()
-
 	model := a.model
-	_, err := model.Transform(a.joints)
+	joints := a.joints
+	a.mu.RUnlock()
+
+	_, err := model.Transform(joints)
 	if err != nil && strings.Contains(err.Error(), referenceframe.OOBErrString) {
 		return errors.New("cannot move arm: " + err.Error())
 	} else if err != nil {
 		return err
 	}
 
-	plan, err := motionplan.PlanFrameMotion(ctx, a.logger, pose, model, a.joints, nil, nil)
+	plan, err := motionplan.PlanFrameMotion(ctx, a.logger, pose, model, joints, nil, nil)
 	if err != nil {
 		return err
 	}
-	copy(a.joints, plan[len(plan)-1])
-	return nil
+	if !a.simulationEnabled() {
+		plan = plan[len(plan)-1:]
+	}
+	// waypoints produced by the planner already respect the model's limits so they are not checked again
+	return a.moveThrough(ctx, plan, nil, false)
 }
 
 // MoveToJointPositions sets the joints.
 func (a *Arm) MoveToJointPositions(ctx context.Context, joints []referenceframe.Input, extra map[string]interface{}) error {
-	if err := arm.CheckDesiredJointPositions(ctx, a, joints); err != nil {
-		return err
-	}
-	a.mu.RLock()
-	defer a.mu.RUnlock()
-	_, err := a.model.Transform(joints)
-	if err != nil {
-		return err
-	}
-	copy(a.joints, joints)
-	return nil
+	return a.moveThrough(ctx, [][]referenceframe.Input{joints}, nil, true)
 }
 
-// MoveThroughJointPositions moves the fake arm through the given inputs.
+// MoveThroughJointPositions moves the fake arm through the given inputs. When simulation is enabled, the velocity and acceleration
+// limits of the options lower those the arm is configured with.
 func (a *Arm) MoveThroughJointPositions(
 	ctx context.Context,
 	positions [][]referenceframe.Input,
-	_ *arm.MoveOptions,
+	options *arm.MoveOptions,
 	_ map[string]interface{},
 ) error {
-	for _, goal := range positions {
-		if err := a.MoveToJointPositions(ctx, goal, nil); err != nil {
-			return err
-		}
+	return a.moveThrough(ctx, positions, options, true)
+}
+
+// SetPayload sets the payload the fake arm carries, which slows its simulated moves.
+func (a *Arm) SetPayload(ctx context.Context, mass float64, centerOfMass r3.Vector, extra map[string]interface{}) error {
+	if mass < 0 {
+		return errors.Errorf("payload mass cannot be negative, got %f", mass)
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.payload = nil
+	if mass > 0 {
+		a.payload = &referenceframe.Payload{Mass: mass, CenterOfMass: centerOfMass}
 	}
 	return nil
 }
 
+// Payload returns the payload the fake arm carries.
+func (a *Arm) Payload(ctx context.Context, extra map[string]interface{}) (*referenceframe.Payload, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if a.payload == nil {
+		return nil, nil
+	}
+	payload := *a.payload
+	return &payload, nil
+}
+
 // JointPositions returns joints.
 func (a *Arm) JointPositions(ctx context.Context, extra map[string]interface{}) ([]referenceframe.Input, error) {
 	a.mu.RLock()
@@ -208,14 +253,17 @@ func (a *Arm) JointPositions(ctx context.Context, extra map[string]interface{})
 	return a.joints, nil
 }
 
-// Stop doesn't do anything for a fake arm.
+// Stop interrupts any simulated motion, leaving the arm at its current position.
 func (a *Arm) Stop(ctx context.Context, extra map[string]interface{}) error {
+	a.stopMove()
 	return nil
 }
 
-// IsMoving is always false for a fake arm.
+// IsMoving returns whether the fake arm is currently executing a move. Moves are only observable when simulation is enabled.
 func (a *Arm) IsMoving(ctx context.Context) (bool, error) {
-	return false, nil
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.cancelMove != nil, nil
 }
 
 // CurrentInputs returns the current inputs of the fake arm.
@@ -230,8 +278,9 @@ func (a *Arm) GoToInputs(ctx context.Context, inputSteps ...[]referenceframe.Inp
 	return a.MoveThroughJointPositions(ctx, inputSteps, nil, nil)
 }
 
-// Close does nothing.
+// Close stops any simulated motion.
 func (a *Arm) Close(ctx context.Context) error {
+	a.stopMove()
 	a.mu.Lock()
 	defer a.mu.Unlock()
 	a.CloseCount++
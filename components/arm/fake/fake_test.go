@@ -4,12 +4,18 @@ import (
 	"context"
 	"math"
 	"testing"
+	"time"
 
+	"github.com/golang/geo/r3"
 	"go.viam.com/test"
+	"go.viam.com/utils/testutils"
 
+	"go.viam.com/rdk/components/arm"
 	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/operation"
 	"go.viam.com/rdk/referenceframe"
 	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/spatialmath"
 )
 
 func TestReconfigure(t *testing.T) {
@@ -131,3 +137,232 @@ func TestJointPositions(t *testing.T) {
 	test.That(t, err, test.ShouldBeNil)
 	test.That(t, sampleInputs, test.ShouldResemble, inputs)
 }
+
+func TestJointLimits(t *testing.T) {
+	model := referenceframe.NewSimpleModel("mixed")
+	rotational, err := referenceframe.NewRotationalFrame("shoulder", spatialmath.R4AA{RZ: 1}, referenceframe.Limit{Min: -math.Pi, Max: math.Pi})
+	test.That(t, err, test.ShouldBeNil)
+	translational, err := referenceframe.NewTranslationalFrame("rail", r3.Vector{X: 1}, referenceframe.Limit{Min: -1000, Max: 1000})
+	test.That(t, err, test.ShouldBeNil)
+	model.OrdTransforms = []referenceframe.Frame{rotational, translational}
+
+	limits := jointLimits(model, 90)
+	test.That(t, limits[0], test.ShouldAlmostEqual, math.Pi/2)
+	test.That(t, limits[1], test.ShouldAlmostEqual, 90)
+}
+
+func TestMotionProfile(t *testing.T) {
+	start := []referenceframe.Input{{0}, {0}}
+	goal := []referenceframe.Input{{2}, {-1}}
+
+	// constant velocity
+	profile := newMotionProfile(start, goal, []float64{1, 1}, []float64{0, 0})
+	test.That(t, profile.duration, test.ShouldEqual, 2*time.Second)
+	test.That(t, profile.inputsAt(time.Second), test.ShouldResemble, []referenceframe.Input{{1}, {-0.5}})
+
+	// the slower joint leads even though it has the smaller displacement
+	profile = newMotionProfile(start, goal, []float64{1, 0.25}, []float64{0, 0})
+	test.That(t, profile.duration, test.ShouldEqual, 4*time.Second)
+	test.That(t, profile.inputsAt(2*time.Second), test.ShouldResemble, []referenceframe.Input{{1}, {-0.5}})
+
+	// trapezoidal, one second accelerating, one second cruising and one second decelerating
+	profile = newMotionProfile(start, goal, []float64{1, 1}, []float64{1, 1})
+	test.That(t, profile.duration, test.ShouldEqual, 3*time.Second)
+	test.That(t, profile.traveled(time.Second), test.ShouldAlmostEqual, 0.5)
+	test.That(t, profile.traveled(2*time.Second), test.ShouldAlmostEqual, 1.5)
+	test.That(t, profile.inputsAt(3*time.Second), test.ShouldResemble, goal)
+
+	// triangular, the velocity limit is never reached
+	profile = newMotionProfile(start, goal, []float64{10, 10}, []float64{2, 2})
+	test.That(t, profile.duration, test.ShouldEqual, 2*time.Second)
+	test.That(t, profile.traveled(time.Second), test.ShouldAlmostEqual, 1)
+
+	// no motion
+	profile = newMotionProfile(goal, goal, []float64{1, 1}, []float64{1, 1})
+	test.That(t, profile.duration, test.ShouldEqual, 0)
+	test.That(t, profile.inputsAt(0), test.ShouldResemble, goal)
+}
+
+func newSimulatedArm(t *testing.T, speed, accel float64) arm.Arm {
+	t.Helper()
+	cfg := resource.Config{
+		Name: "testArm",
+		ConvertedAttributes: &Config{
+			MaxJointSpeedDegsPerSec:  speed,
+			MaxJointAccelDegsPerSec2: accel,
+		},
+	}
+	a, err := NewArm(context.Background(), nil, cfg, logging.NewTestLogger(t))
+	test.That(t, err, test.ShouldBeNil)
+	return a
+}
+
+func waitUntilMoving(t *testing.T, a arm.Arm) {
+	t.Helper()
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		moving, err := a.IsMoving(context.Background())
+		test.That(tb, err, test.ShouldBeNil)
+		test.That(tb, moving, test.ShouldBeTrue)
+	})
+}
+
+func TestSimulatedMotion(t *testing.T) {
+	ctx := context.Background()
+	a := newSimulatedArm(t, 90, 0)
+
+	// a half second move completes at the goal
+	goal := []referenceframe.Input{{math.Pi / 4}}
+	start := time.Now()
+	test.That(t, a.MoveToJointPositions(ctx, goal, nil), test.ShouldBeNil)
+	test.That(t, time.Since(start), test.ShouldBeGreaterThanOrEqualTo, 500*time.Millisecond)
+	positions, err := a.JointPositions(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, positions, test.ShouldResemble, goal)
+	moving, err := a.IsMoving(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, moving, test.ShouldBeFalse)
+
+	// a one second move can be stopped part way through
+	moveErr := make(chan error, 1)
+	go func() {
+		moveErr <- a.MoveToJointPositions(ctx, []referenceframe.Input{{-math.Pi / 4}}, nil)
+	}()
+	waitUntilMoving(t, a)
+	test.That(t, a.Stop(ctx, nil), test.ShouldBeNil)
+	test.That(t, <-moveErr, test.ShouldBeNil)
+	moving, err = a.IsMoving(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, moving, test.ShouldBeFalse)
+	positions, err = a.JointPositions(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, positions[0].Value, test.ShouldBeLessThan, math.Pi/4)
+	test.That(t, positions[0].Value, test.ShouldBeGreaterThan, -math.Pi/4)
+
+	// cancelling the caller's context is reported as an error
+	cancelCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	err = a.MoveToJointPositions(cancelCtx, []referenceframe.Input{{-math.Pi / 2}}, nil)
+	test.That(t, err, test.ShouldBeError, context.DeadlineExceeded)
+}
+
+func TestSimulatedMotionWithAcceleration(t *testing.T) {
+	ctx := context.Background()
+	a := newSimulatedArm(t, 90, 180)
+
+	// accelerating to and from 90 degs/sec each take half a second on top of the half second it takes to cruise 45 degrees
+	goal := []referenceframe.Input{{math.Pi / 2}}
+	start := time.Now()
+	moveErr := make(chan error, 1)
+	go func() {
+		moveErr <- a.MoveToJointPositions(ctx, goal, nil)
+	}()
+	waitUntilMoving(t, a)
+
+	// a quarter second into the move the arm has only covered the distance of its acceleration ramp
+	time.Sleep(250*time.Millisecond - time.Since(start))
+	positions, err := a.JointPositions(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, positions[0].Value, test.ShouldBeLessThan, math.Pi/8)
+
+	test.That(t, <-moveErr, test.ShouldBeNil)
+	test.That(t, time.Since(start), test.ShouldBeGreaterThanOrEqualTo, time.Second)
+	positions, err = a.JointPositions(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, positions, test.ShouldResemble, goal)
+}
+
+func TestSimulatedMotionWithMoveOptions(t *testing.T) {
+	ctx := context.Background()
+	a := newSimulatedArm(t, 90, 0)
+
+	// capped at 45 degs/sec, the quarter turn which would take half a second takes a whole one
+	start := time.Now()
+	options := &arm.MoveOptions{MaxVelRads: math.Pi / 4}
+	test.That(t, a.MoveThroughJointPositions(ctx, [][]referenceframe.Input{{{math.Pi / 4}}}, options, nil), test.ShouldBeNil)
+	test.That(t, time.Since(start), test.ShouldBeGreaterThanOrEqualTo, time.Second)
+
+	// options faster than the arm are limited by its own limits
+	start = time.Now()
+	options = &arm.MoveOptions{MaxVelRads: math.Pi}
+	test.That(t, a.MoveThroughJointPositions(ctx, [][]referenceframe.Input{{{0}}}, options, nil), test.ShouldBeNil)
+	elapsed := time.Since(start)
+	test.That(t, elapsed, test.ShouldBeGreaterThanOrEqualTo, 500*time.Millisecond)
+	test.That(t, elapsed, test.ShouldBeLessThan, time.Second)
+}
+
+func TestSimulatedMotionWithOperation(t *testing.T) {
+	// callers such as the motion service tag their contexts with operations, which must not hide the arm's own moves
+	ctx, done := operation.NewSingleOperationManager().New(context.Background())
+	defer done()
+	a := newSimulatedArm(t, 90, 0)
+
+	moveErr := make(chan error, 1)
+	go func() {
+		moveErr <- a.MoveThroughJointPositions(ctx, [][]referenceframe.Input{{{math.Pi / 2}}, {{0}}}, nil, nil)
+	}()
+	waitUntilMoving(t, a)
+	test.That(t, a.Stop(ctx, nil), test.ShouldBeNil)
+	test.That(t, <-moveErr, test.ShouldBeNil)
+	moving, err := a.IsMoving(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, moving, test.ShouldBeFalse)
+}
+
+func TestSimulatedMotionReconfigure(t *testing.T) {
+	ctx := context.Background()
+	a := newSimulatedArm(t, 90, 0)
+
+	moveErr := make(chan error, 1)
+	go func() {
+		moveErr <- a.MoveToJointPositions(ctx, []referenceframe.Input{{math.Pi}}, nil)
+	}()
+	waitUntilMoving(t, a)
+
+	// reconfiguring to a model with a different number of joints interrupts the move
+	conf := resource.Config{
+		Name: "testArm",
+		ConvertedAttributes: &Config{
+			ArmModel:                "ur5e",
+			MaxJointSpeedDegsPerSec: 90,
+		},
+	}
+	test.That(t, a.Reconfigure(ctx, nil, conf), test.ShouldBeNil)
+	test.That(t, <-moveErr, test.ShouldBeNil)
+	positions, err := a.JointPositions(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, positions, test.ShouldResemble, make([]referenceframe.Input, 6))
+	_, err = a.EndPosition(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+
+	test.That(t, a.Close(ctx), test.ShouldBeNil)
+	moving, err := a.IsMoving(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, moving, test.ShouldBeFalse)
+}
+
+func TestPayload(t *testing.T) {
+	ctx := context.Background()
+	a := newSimulatedArm(t, 90, 0)
+	carrier, ok := a.(arm.PayloadCarrier)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	payload, err := carrier.Payload(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, payload, test.ShouldBeNil)
+	test.That(t, carrier.SetPayload(ctx, -1, r3.Vector{}, nil), test.ShouldNotBeNil)
+
+	test.That(t, carrier.SetPayload(ctx, 2, r3.Vector{Z: 50}, nil), test.ShouldBeNil)
+	payload, err = carrier.Payload(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, payload, test.ShouldResemble, &referenceframe.Payload{Mass: 2, CenterOfMass: r3.Vector{Z: 50}})
+
+	// the links of the fake model have no mass to compare the payload to, so its moves are no slower
+	start := time.Now()
+	test.That(t, a.MoveToJointPositions(ctx, []referenceframe.Input{{math.Pi / 4}}, nil), test.ShouldBeNil)
+	test.That(t, time.Since(start), test.ShouldBeLessThan, 2*time.Second)
+
+	test.That(t, carrier.SetPayload(ctx, 0, r3.Vector{}, nil), test.ShouldBeNil)
+	payload, err = carrier.Payload(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, payload, test.ShouldBeNil)
+}
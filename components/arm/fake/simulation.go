@@ -0,0 +1,258 @@
+package fake
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/pkg/errors"
+	pb "go.viam.com/api/component/arm/v1"
+
+	"go.viam.com/rdk/components/arm"
+	"go.viam.com/rdk/referenceframe"
+)
+
+// simulationStepRate is how often the simulated joint positions are updated while the arm is moving.
+const simulationStepRate = 10 * time.Millisecond
+
+// errReconfiguredDuringMove is returned when a simulated move is overtaken by a change of the arm's model.
+var errReconfiguredDuringMove = errors.New("fake arm was reconfigured during a simulated move")
+
+// jointLimits converts a limit given in degrees for revolute joints and mm for prismatic joints into the units of the model's
+// inputs by treating it as a joint position.
+func jointLimits(model referenceframe.Model, limit float64) []float64 {
+	values := make([]float64, len(model.DoF()))
+	for i := range values {
+		values[i] = limit
+	}
+	return referenceframe.InputsToFloats(model.InputFromProtobuf(&pb.JointPositions{Values: values}))
+}
+
+// capLimits returns a copy of the given limits, lowered to the given cap if it is positive. A zero limit is unlimited, so it is
+// replaced by the cap.
+func capLimits(limits []float64, limit float64) []float64 {
+	capped := append([]float64{}, limits...)
+	if limit <= 0 {
+		return capped
+	}
+	for i, l := range capped {
+		if l <= 0 || l > limit {
+			capped[i] = limit
+		}
+	}
+	return capped
+}
+
+// motionProfile describes a synchronized trapezoidal velocity profile that moves every joint from its start position to its goal
+// position. The joint which takes the longest to reach its goal sets the timing, and every other joint is scaled to begin and end
+// its motion at the same time.
+type motionProfile struct {
+	start []float64
+	goal  []float64
+
+	// the displacement and limits of the leading joint
+	distance float64
+	maxVel   float64
+	maxAcc   float64
+	duration time.Duration
+}
+
+// profileDuration returns how long a single joint takes to travel the given distance. A zero maxAcc means the joint instantly
+// reaches maxVel.
+func profileDuration(distance, maxVel, maxAcc float64) float64 {
+	switch {
+	case distance == 0:
+		return 0
+	case maxAcc <= 0:
+		return distance / maxVel
+	case distance >= maxVel*maxVel/maxAcc:
+		// trapezoidal profile, the joint cruises at maxVel for some portion of the move
+		return distance/maxVel + maxVel/maxAcc
+	default:
+		// triangular profile, the joint never reaches maxVel before it must begin to decelerate
+		return 2 * math.Sqrt(distance/maxAcc)
+	}
+}
+
+func newMotionProfile(start, goal []referenceframe.Input, maxVel, maxAcc []float64) *motionProfile {
+	p := &motionProfile{
+		start: referenceframe.InputsToFloats(start),
+		goal:  referenceframe.InputsToFloats(goal),
+	}
+	var seconds float64
+	for i := range p.start {
+		distance := math.Abs(p.goal[i] - p.start[i])
+		if jointSeconds := profileDuration(distance, maxVel[i], maxAcc[i]); jointSeconds > seconds || p.distance == 0 {
+			seconds = jointSeconds
+			p.distance, p.maxVel, p.maxAcc = distance, maxVel[i], maxAcc[i]
+		}
+	}
+	p.duration = time.Duration(seconds * float64(time.Second))
+	return p
+}
+
+// traveled returns the distance the leading joint has moved after the given elapsed time.
+func (p *motionProfile) traveled(elapsed time.Duration) float64 {
+	if elapsed >= p.duration {
+		return p.distance
+	}
+	t := elapsed.Seconds()
+	if p.maxAcc <= 0 {
+		return p.maxVel * t
+	}
+
+	// peak velocity is either maxVel or the velocity reached at the midpoint of a triangular profile
+	peakVel := math.Min(p.maxVel, math.Sqrt(p.distance*p.maxAcc))
+	accelTime := peakVel / p.maxAcc
+	total := p.duration.Seconds()
+	switch {
+	case t < accelTime:
+		return 0.5 * p.maxAcc * t * t
+	case t < total-accelTime:
+		return 0.5*peakVel*accelTime + peakVel*(t-accelTime)
+	default:
+		remaining := total - t
+		return p.distance - 0.5*p.maxAcc*remaining*remaining
+	}
+}
+
+// inputsAt returns the interpolated joint positions of the arm after the given elapsed time.
+func (p *motionProfile) inputsAt(elapsed time.Duration) []referenceframe.Input {
+	if p.distance == 0 || elapsed >= p.duration {
+		return referenceframe.FloatsToInputs(p.goal)
+	}
+	frac := p.traveled(elapsed) / p.distance
+	positions := make([]float64, len(p.start))
+	for i := range p.start {
+		positions[i] = p.start[i] + frac*(p.goal[i]-p.start[i])
+	}
+	return referenceframe.FloatsToInputs(positions)
+}
+
+// simulationEnabled returns whether the arm has been configured to simulate its execution dynamics.
+func (a *Arm) simulationEnabled() bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.maxVel != nil
+}
+
+// moveThrough moves the arm through each of the given positions in turn, optionally validating each against the joint limits of
+// the arm before moving to it. When simulation is disabled the arm teleports to each position, and the options are ignored.
+func (a *Arm) moveThrough(ctx context.Context, positions [][]referenceframe.Input, options *arm.MoveOptions, check bool) error {
+	if !a.simulationEnabled() {
+		for _, goal := range positions {
+			if err := a.validateGoal(ctx, goal, check); err != nil {
+				return err
+			}
+			a.mu.Lock()
+			copy(a.joints, goal)
+			a.mu.Unlock()
+		}
+		return nil
+	}
+
+	moveCtx, done := a.startMove(ctx)
+	defer done()
+	for _, goal := range positions {
+		if err := a.validateGoal(ctx, goal, check); err != nil {
+			return err
+		}
+		if err := a.simulateSegment(moveCtx, goal, options); err != nil {
+			if ctx.Err() == nil && errors.Is(err, context.Canceled) {
+				// the move was interrupted by a call to Stop
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *Arm) validateGoal(ctx context.Context, goal []referenceframe.Input, check bool) error {
+	if check {
+		if err := arm.CheckDesiredJointPositions(ctx, a, goal); err != nil {
+			return err
+		}
+	}
+	_, err := a.ModelFrame().Transform(goal)
+	return err
+}
+
+// startMove stops any simulated move already in progress and begins tracking a new one. The returned function must be called
+// once the move is complete.
+func (a *Arm) startMove(ctx context.Context) (context.Context, func()) {
+	a.stopMove()
+	moveCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	a.mu.Lock()
+	for a.cancelMove != nil {
+		// another move started in between stopping the previous move and acquiring the lock
+		a.mu.Unlock()
+		a.stopMove()
+		a.mu.Lock()
+	}
+	a.cancelMove = cancel
+	a.moveDone = done
+	a.mu.Unlock()
+
+	return moveCtx, func() {
+		cancel()
+		a.mu.Lock()
+		a.cancelMove = nil
+		a.moveDone = nil
+		a.mu.Unlock()
+		close(done)
+	}
+}
+
+// stopMove cancels any simulated move in progress and waits for it to finish.
+func (a *Arm) stopMove() {
+	a.mu.RLock()
+	cancel, done := a.cancelMove, a.moveDone
+	a.mu.RUnlock()
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+}
+
+// simulateSegment interpolates the joints of the arm towards the goal subject to the configured velocity and acceleration limits,
+// which are lowered to suit the payload the arm carries where it starts the segment, and to the limits of the options if given.
+func (a *Arm) simulateSegment(ctx context.Context, goal []referenceframe.Input, options *arm.MoveOptions) error {
+	a.mu.RLock()
+	maxVel, maxAcc, err := arm.PayloadLimits(a.model, a.joints, a.payload, a.maxVel, a.maxAcc)
+	if err != nil {
+		// the loads on models whose joints are not understood cannot be estimated, so their payloads are ignored
+		maxVel, maxAcc = a.maxVel, a.maxAcc
+	}
+	if options != nil {
+		maxVel, maxAcc = capLimits(maxVel, options.MaxVelRads), capLimits(maxAcc, options.MaxAccRads)
+	}
+	profile := newMotionProfile(a.joints, goal, maxVel, maxAcc)
+	a.mu.RUnlock()
+
+	ticker := time.NewTicker(simulationStepRate)
+	defer ticker.Stop()
+	startTime := time.Now()
+	for {
+		elapsed := time.Since(startTime)
+		a.mu.Lock()
+		if len(a.joints) != len(goal) {
+			a.mu.Unlock()
+			return errReconfiguredDuringMove
+		}
+		a.joints = profile.inputsAt(elapsed)
+		a.mu.Unlock()
+		if elapsed >= profile.duration {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,335 @@
+// Package jogguard implements an arm which guards another arm against being driven into collisions, such as by a user jogging it from
+// the web UI.
+package jogguard
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/components/arm"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/operation"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot/framesystem"
+	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// clampIterations is the number of times the range of fractions of a colliding move which the arm may make is halved when clamping
+// the move, which stops the arm within 1/1024th of the move of the collision.
+const clampIterations = 10
+
+// Model is the name of the jog guard arm model.
+var Model = resource.DefaultModelFamily.WithModel("jog_guard")
+
+// Config is used for converting config attributes.
+type Config struct {
+	ArmName string `json:"arm-name"`
+
+	// Obstacles are geometries in the world frame which the arm must not be driven into, in addition to the geometries of the frame
+	// system.
+	Obstacles []*spatialmath.GeometryConfig `json:"obstacles,omitempty"`
+
+	// CollisionBufferMM is the distance within which geometries are considered to collide.
+	CollisionBufferMM float64 `json:"collision-buffer-mm,omitempty"`
+
+	// When set, commands which would collide move the arm as far as it can go towards their goal without colliding, rather than being
+	// rejected.
+	Clamp bool `json:"clamp,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (cfg *Config) Validate(path string) ([]string, error) {
+	if cfg.ArmName == "" {
+		return nil, resource.NewConfigValidationFieldRequiredError(path, "arm-name")
+	}
+	if cfg.CollisionBufferMM < 0 {
+		return nil, resource.NewConfigValidationError(path, errors.New("collision-buffer-mm cannot be negative"))
+	}
+	for _, obstacle := range cfg.Obstacles {
+		if _, err := obstacle.ParseConfig(); err != nil {
+			return nil, resource.NewConfigValidationError(path, err)
+		}
+	}
+	return []string{cfg.ArmName, framesystem.InternalServiceName.String()}, nil
+}
+
+func init() {
+	resource.RegisterComponent(arm.API, Model, resource.Registration[arm.Arm, *Config]{
+		Constructor: NewJogGuard,
+	})
+}
+
+// Arm passes commands through to another arm once it has checked that they do not drive the arm into a collision with the rest of
+// the frame system or with its obstacles. Collisions which are present before a command is given are not checked, so that an arm
+// which has already collided may be driven clear. The guarded arm should be in the frame system, and the guard itself should not.
+type Arm struct {
+	resource.Named
+	resource.TriviallyCloseable
+	logger logging.Logger
+	opMgr  *operation.SingleOperationManager
+
+	mu                sync.RWMutex
+	actual            arm.Arm
+	fs                framesystem.Service
+	obstacles         []spatialmath.Geometry
+	collisionBufferMM float64
+	clamp             bool
+}
+
+// NewJogGuard returns an arm which guards another arm against collisions.
+func NewJogGuard(ctx context.Context, deps resource.Dependencies, conf resource.Config, logger logging.Logger) (arm.Arm, error) {
+	a := &Arm{
+		Named:  conf.ResourceName().AsNamed(),
+		logger: logger,
+		opMgr:  operation.NewSingleOperationManager(),
+	}
+	if err := a.Reconfigure(ctx, deps, conf); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Reconfigure atomically reconfigures this arm in place based on the new config.
+func (guard *Arm) Reconfigure(ctx context.Context, deps resource.Dependencies, conf resource.Config) error {
+	newConf, err := resource.NativeConfig[*Config](conf)
+	if err != nil {
+		return err
+	}
+	actual, err := arm.FromDependencies(deps, newConf.ArmName)
+	if err != nil {
+		return err
+	}
+	fs, err := framesystem.FromDependencies(deps)
+	if err != nil {
+		return err
+	}
+	obstacles := make([]spatialmath.Geometry, 0, len(newConf.Obstacles))
+	for _, obstacleCfg := range newConf.Obstacles {
+		obstacle, err := obstacleCfg.ParseConfig()
+		if err != nil {
+			return err
+		}
+		obstacles = append(obstacles, obstacle)
+	}
+
+	guard.mu.Lock()
+	defer guard.mu.Unlock()
+	guard.actual = actual
+	guard.fs = fs
+	guard.obstacles = obstacles
+	guard.collisionBufferMM = newConf.CollisionBufferMM
+	guard.clamp = newConf.Clamp
+	return nil
+}
+
+// guardPositions returns the positions the arm may move through of those given. If moving through them would collide and the guard
+// clamps commands, the positions end where the arm must stop short of the collision; otherwise an error is returned.
+func (guard *Arm) guardPositions(ctx context.Context, positions [][]referenceframe.Input) ([][]referenceframe.Input, error) {
+	guard.mu.RLock()
+	actual, fsService, obstacles := guard.actual, guard.fs, guard.obstacles
+	collisionBufferMM, clamp := guard.collisionBufferMM, guard.clamp
+	guard.mu.RUnlock()
+
+	fs, err := fsService.FrameSystem(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	start, _, err := fsService.CurrentInputs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	name := actual.Name().ShortName()
+	frame := fs.Frame(name)
+	if frame == nil {
+		return nil, fmt.Errorf("cannot guard arm %s, which is not in the frame system", name)
+	}
+	worldState, err := referenceframe.NewWorldState(
+		[]*referenceframe.GeometriesInFrame{referenceframe.NewGeometriesInFrame(referenceframe.World, obstacles)}, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	options := map[string]interface{}{}
+	if collisionBufferMM > 0 {
+		options["collision_buffer_mm"] = collisionBufferMM
+	}
+	// collides returns whether moving the arm between the given positions collides
+	collides := func(from, to []referenceframe.Input) (bool, error) {
+		fromInputs, toInputs := withInputs(start, name, from), withInputs(start, name, to)
+		request := &motionplan.PlanRequest{
+			Logger:      guard.logger,
+			FrameSystem: fs,
+			StartState:  motionplan.NewPlanState(nil, fromInputs),
+			Goals:       []*motionplan.PlanState{motionplan.NewPlanState(nil, toInputs)},
+			WorldState:  worldState,
+			Options:     options,
+		}
+		plan := motionplan.NewSimplePlan(nil, motionplan.Trajectory{fromInputs, toInputs})
+		violations, err := motionplan.CheckConstraints(request, plan)
+		if err != nil {
+			return false, err
+		}
+		for _, violation := range violations {
+			// collisions which are present before the move are not the fault of the command
+			if violation.Step >= 0 {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	from := start[name]
+	for i, to := range positions {
+		collision, err := collides(from, to)
+		if err != nil {
+			return nil, err
+		}
+		if !collision {
+			from = to
+			continue
+		}
+		if !clamp {
+			return nil, fmt.Errorf("moving arm %s to %v would collide", name, to)
+		}
+		// the arm may move as far towards the colliding position as it can without colliding
+		safe, unsafe := 0., 1.
+		for j := 0; j < clampIterations; j++ {
+			mid := (safe + unsafe) / 2
+			midInputs, err := frame.Interpolate(from, to, mid)
+			if err != nil {
+				return nil, err
+			}
+			if collision, err = collides(from, midInputs); err != nil {
+				return nil, err
+			}
+			if collision {
+				unsafe = mid
+			} else {
+				safe = mid
+			}
+		}
+		clamped, err := frame.Interpolate(from, to, safe)
+		if err != nil {
+			return nil, err
+		}
+		guard.logger.CInfof(ctx, "stopping arm %s short of a collision on its way to %v", name, to)
+		return append(append([][]referenceframe.Input{}, positions[:i]...), clamped), nil
+	}
+	return positions, nil
+}
+
+// withInputs returns a copy of the inputs of a frame system with the inputs of the named frame replaced.
+func withInputs(inputs referenceframe.FrameSystemInputs, name string, frameInputs []referenceframe.Input) referenceframe.FrameSystemInputs {
+	replaced := referenceframe.FrameSystemInputs{}
+	for frameName, frameInput := range inputs {
+		replaced[frameName] = frameInput
+	}
+	replaced[name] = frameInputs
+	return replaced
+}
+
+// ModelFrame returns the dynamic frame of the guarded arm.
+func (guard *Arm) ModelFrame() referenceframe.Model {
+	guard.mu.RLock()
+	defer guard.mu.RUnlock()
+	return guard.actual.ModelFrame()
+}
+
+// EndPosition returns the position of the guarded arm.
+func (guard *Arm) EndPosition(ctx context.Context, extra map[string]interface{}) (spatialmath.Pose, error) {
+	guard.mu.RLock()
+	defer guard.mu.RUnlock()
+	return guard.actual.EndPosition(ctx, extra)
+}
+
+// MoveToPosition plans a move of the arm to the given position, which is checked for collisions before the arm moves.
+func (guard *Arm) MoveToPosition(ctx context.Context, pos spatialmath.Pose, extra map[string]interface{}) error {
+	ctx, done := guard.opMgr.New(ctx)
+	defer done()
+	return motion.MoveArm(ctx, guard.logger, guard, pos)
+}
+
+// MoveToJointPositions moves the arm to the given joint positions if the move does not collide.
+func (guard *Arm) MoveToJointPositions(ctx context.Context, joints []referenceframe.Input, extra map[string]interface{}) error {
+	if err := arm.CheckDesiredJointPositions(ctx, guard, joints); err != nil {
+		return err
+	}
+	ctx, done := guard.opMgr.New(ctx)
+	defer done()
+
+	positions, err := guard.guardPositions(ctx, [][]referenceframe.Input{joints})
+	if err != nil {
+		return err
+	}
+	guard.mu.RLock()
+	defer guard.mu.RUnlock()
+	return guard.actual.MoveToJointPositions(ctx, positions[len(positions)-1], extra)
+}
+
+// MoveThroughJointPositions moves the arm through the given joint positions if the moves between them do not collide.
+func (guard *Arm) MoveThroughJointPositions(
+	ctx context.Context,
+	positions [][]referenceframe.Input,
+	options *arm.MoveOptions,
+	extra map[string]interface{},
+) error {
+	for _, goal := range positions {
+		if err := arm.CheckDesiredJointPositions(ctx, guard, goal); err != nil {
+			return err
+		}
+	}
+	guarded, err := guard.guardPositions(ctx, positions)
+	if err != nil {
+		return err
+	}
+	guard.mu.RLock()
+	defer guard.mu.RUnlock()
+	return guard.actual.MoveThroughJointPositions(ctx, guarded, options, extra)
+}
+
+// JointPositions returns the joint positions of the guarded arm.
+func (guard *Arm) JointPositions(ctx context.Context, extra map[string]interface{}) ([]referenceframe.Input, error) {
+	guard.mu.RLock()
+	defer guard.mu.RUnlock()
+	return guard.actual.JointPositions(ctx, extra)
+}
+
+// Stop stops the guarded arm.
+func (guard *Arm) Stop(ctx context.Context, extra map[string]interface{}) error {
+	ctx, done := guard.opMgr.New(ctx)
+	defer done()
+
+	guard.mu.RLock()
+	defer guard.mu.RUnlock()
+	return guard.actual.Stop(ctx, extra)
+}
+
+// IsMoving returns whether the guarded arm is moving.
+func (guard *Arm) IsMoving(ctx context.Context) (bool, error) {
+	guard.mu.RLock()
+	defer guard.mu.RUnlock()
+	return guard.actual.IsMoving(ctx)
+}
+
+// CurrentInputs returns the current inputs of the guarded arm.
+func (guard *Arm) CurrentInputs(ctx context.Context) ([]referenceframe.Input, error) {
+	return guard.JointPositions(ctx, nil)
+}
+
+// GoToInputs moves the arm through the given inputs if the moves between them do not collide.
+func (guard *Arm) GoToInputs(ctx context.Context, inputSteps ...[]referenceframe.Input) error {
+	return guard.MoveThroughJointPositions(ctx, inputSteps, nil, nil)
+}
+
+// Geometries returns the geometries of the guarded arm.
+func (guard *Arm) Geometries(ctx context.Context, extra map[string]interface{}) ([]spatialmath.Geometry, error) {
+	guard.mu.RLock()
+	defer guard.mu.RUnlock()
+	return guard.actual.Geometries(ctx, extra)
+}
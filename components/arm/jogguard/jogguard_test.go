@@ -0,0 +1,137 @@
+package jogguard
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/arm"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot/framesystem"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestValidate(t *testing.T) {
+	cfg := &Config{}
+	_, err := cfg.Validate("path")
+	test.That(t, err, test.ShouldBeError, resource.NewConfigValidationFieldRequiredError("path", "arm-name"))
+
+	cfg = &Config{ArmName: "arm", CollisionBufferMM: -1}
+	_, err = cfg.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+
+	cfg = &Config{ArmName: "arm", Obstacles: []*spatialmath.GeometryConfig{{Type: "box", X: 100, Y: 100, Z: 100}}}
+	deps, err := cfg.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, deps, test.ShouldResemble, []string{"arm", framesystem.InternalServiceName.String()})
+}
+
+func TestJogGuard(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+
+	// an arm which swings a box 500mm out from its base around the z axis, into an obstacle a quarter turn from where it starts
+	modelCfg := &referenceframe.ModelConfig{
+		Name: "arm",
+		Links: []referenceframe.LinkConfig{
+			{ID: "base"},
+			{ID: "link", Parent: "joint", Translation: r3.Vector{X: 500}},
+			// the geometry of a link sits at its start, so the box is carried by a link after the translation
+			{ID: "tip", Parent: "link", Geometry: &spatialmath.GeometryConfig{Type: "box", X: 100, Y: 100, Z: 100}},
+		},
+		Joints: []referenceframe.JointConfig{
+			{ID: "joint", Type: referenceframe.RevoluteJoint, Parent: "base", Axis: spatialmath.AxisConfig{Z: 1}, Max: 360, Min: -360},
+		},
+	}
+	model, err := modelCfg.ParseConfig("arm")
+	test.That(t, err, test.ShouldBeNil)
+	frameSys := referenceframe.NewEmptyFrameSystem("test")
+	test.That(t, frameSys.AddFrame(model, frameSys.World()), test.ShouldBeNil)
+
+	joints := []referenceframe.Input{{0}}
+	var moved [][]referenceframe.Input
+	actual := inject.NewArm("arm")
+	actual.ModelFrameFunc = func() referenceframe.Model { return model }
+	actual.JointPositionsFunc = func(ctx context.Context, extra map[string]interface{}) ([]referenceframe.Input, error) {
+		return joints, nil
+	}
+	actual.MoveToJointPositionsFunc = func(ctx context.Context, positions []referenceframe.Input, extra map[string]interface{}) error {
+		moved = append(moved, positions)
+		return nil
+	}
+	actual.MoveThroughJointPositionsFunc = func(
+		ctx context.Context, positions [][]referenceframe.Input, options *arm.MoveOptions, extra map[string]interface{},
+	) error {
+		moved = append(moved, positions...)
+		return nil
+	}
+
+	fsService := inject.NewFrameSystemService("builtin")
+	fsService.FrameSystemFunc = func(
+		ctx context.Context, additionalTransforms []*referenceframe.LinkInFrame,
+	) (referenceframe.FrameSystem, error) {
+		return frameSys, nil
+	}
+	fsService.CurrentInputsFunc = func(ctx context.Context) (
+		referenceframe.FrameSystemInputs, map[string]framesystem.InputEnabled, error,
+	) {
+		return referenceframe.FrameSystemInputs{"arm": joints}, nil, nil
+	}
+
+	deps := resource.Dependencies{actual.Name(): actual, framesystem.InternalServiceName: fsService}
+	newGuard := func(clamp bool) arm.Arm {
+		conf := resource.Config{
+			Name: "guard",
+			ConvertedAttributes: &Config{
+				ArmName: "arm",
+				Obstacles: []*spatialmath.GeometryConfig{{
+					Type: "box", X: 100, Y: 100, Z: 100, TranslationOffset: r3.Vector{Y: 500},
+				}},
+				Clamp: clamp,
+			},
+		}
+		guard, err := NewJogGuard(ctx, deps, conf, logger)
+		test.That(t, err, test.ShouldBeNil)
+		return guard
+	}
+
+	t.Run("moves which do not collide are passed through", func(t *testing.T) {
+		moved = nil
+		guard := newGuard(false)
+		goal := []referenceframe.Input{{-math.Pi / 2}}
+		test.That(t, guard.MoveToJointPositions(ctx, goal, nil), test.ShouldBeNil)
+		test.That(t, moved, test.ShouldResemble, [][]referenceframe.Input{goal})
+	})
+
+	t.Run("moves which collide are rejected", func(t *testing.T) {
+		moved = nil
+		guard := newGuard(false)
+		test.That(t, guard.MoveToJointPositions(ctx, []referenceframe.Input{{math.Pi / 2}}, nil), test.ShouldNotBeNil)
+		err := guard.MoveThroughJointPositions(ctx, [][]referenceframe.Input{{{-0.1}}, {{math.Pi / 2}}}, nil, nil)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, moved, test.ShouldBeEmpty)
+	})
+
+	t.Run("moves which collide are clamped short of the collision", func(t *testing.T) {
+		moved = nil
+		guard := newGuard(true)
+		test.That(t, guard.MoveToJointPositions(ctx, []referenceframe.Input{{math.Pi / 2}}, nil), test.ShouldBeNil)
+		test.That(t, len(moved), test.ShouldEqual, 1)
+		test.That(t, moved[0][0].Value, test.ShouldBeGreaterThan, 0)
+		test.That(t, moved[0][0].Value, test.ShouldBeLessThan, math.Pi/2)
+
+		// positions beyond the collision are dropped
+		moved = nil
+		positions := [][]referenceframe.Input{{{-0.1}}, {{math.Pi / 2}}, {{0}}}
+		test.That(t, guard.MoveThroughJointPositions(ctx, positions, nil, nil), test.ShouldBeNil)
+		test.That(t, len(moved), test.ShouldEqual, 2)
+		test.That(t, moved[0], test.ShouldResemble, positions[0])
+		test.That(t, moved[1][0].Value, test.ShouldBeLessThan, math.Pi/2)
+	})
+}
@@ -0,0 +1,61 @@
+package arm
+
+import (
+	"context"
+	"math"
+
+	"github.com/golang/geo/r3"
+
+	"go.viam.com/rdk/referenceframe"
+)
+
+// A PayloadCarrier is an arm which can be told the payload it carries, such as an object held by its gripper, so that it slows its
+// motion to suit the heavier load on its joints. The motion service keeps the loads on the joints of such an arm within the limits of
+// its model while it carries a payload.
+type PayloadCarrier interface {
+	// SetPayload sets the mass, in kg, of the payload the arm carries, and its center of mass, in mm, in the frame of the end effector
+	// of the arm. A mass of zero means the arm carries no payload.
+	SetPayload(ctx context.Context, mass float64, centerOfMass r3.Vector, extra map[string]interface{}) error
+
+	// Payload returns the payload the arm carries, or nil if it carries none.
+	Payload(ctx context.Context, extra map[string]interface{}) (*referenceframe.Payload, error)
+}
+
+// PayloadLimits scales the given velocity and acceleration limits of the joints of a model, which hold for the model alone, so that
+// its joints take no more torque to accelerate it at the given inputs while it carries the payload. Each acceleration limit is scaled
+// by how much of the inertia moved by its joint is the model's own, and each velocity limit by the square root of that, so that joints
+// still reach their top speed over the same distance. Joints which move no mass of the model, as in models whose links have none, are
+// left as they are, since the torque they may exert is unknown.
+func PayloadLimits(
+	model referenceframe.Model,
+	inputs []referenceframe.Input,
+	payload *referenceframe.Payload,
+	maxVel, maxAcc []float64,
+) ([]float64, []float64, error) {
+	scaledVel := append([]float64{}, maxVel...)
+	scaledAcc := append([]float64{}, maxAcc...)
+	if payload == nil || payload.Mass == 0 {
+		return scaledVel, scaledAcc, nil
+	}
+	unloaded, err := referenceframe.ComputeJointLoads(model, inputs, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	loaded, err := referenceframe.ComputeJointLoads(model, inputs, payload)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range loaded.Inertia {
+		if unloaded.Inertia[i] <= 0 {
+			continue
+		}
+		scale := unloaded.Inertia[i] / loaded.Inertia[i]
+		if i < len(scaledVel) {
+			scaledVel[i] *= math.Sqrt(scale)
+		}
+		if i < len(scaledAcc) {
+			scaledAcc[i] *= scale
+		}
+	}
+	return scaledVel, scaledAcc, nil
+}
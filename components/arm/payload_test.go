@@ -0,0 +1,49 @@
+package arm_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/arm"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+func TestPayloadLimits(t *testing.T) {
+	// a single joint pitching a 1 kg link, at whose end a payload of the same mass doubles the inertia the joint moves
+	cfg := &referenceframe.ModelConfig{
+		Name: "pendulum",
+		Links: []referenceframe.LinkConfig{
+			{ID: "base"},
+			{ID: "arm", Parent: "shoulder", Translation: r3.Vector{X: 1000}, Mass: 1},
+		},
+		Joints: []referenceframe.JointConfig{
+			{ID: "shoulder", Type: referenceframe.RevoluteJoint, Parent: "base", Axis: spatialmath.AxisConfig{Y: 1}, Max: 180, Min: -180},
+		},
+	}
+	model, err := cfg.ParseConfig("")
+	test.That(t, err, test.ShouldBeNil)
+	inputs := []referenceframe.Input{{0}}
+
+	maxVel, maxAcc, err := arm.PayloadLimits(model, inputs, &referenceframe.Payload{Mass: 1}, []float64{2}, []float64{4})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, maxVel[0], test.ShouldAlmostEqual, 2/math.Sqrt(2))
+	test.That(t, maxAcc[0], test.ShouldAlmostEqual, 2)
+
+	maxVel, maxAcc, err = arm.PayloadLimits(model, inputs, nil, []float64{2}, []float64{4})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, maxVel, test.ShouldResemble, []float64{2})
+	test.That(t, maxAcc, test.ShouldResemble, []float64{4})
+
+	// without the mass of its links, there is nothing to compare the payload to
+	cfg.Links[1].Mass = 0
+	massless, err := cfg.ParseConfig("")
+	test.That(t, err, test.ShouldBeNil)
+	maxVel, maxAcc, err = arm.PayloadLimits(massless, inputs, &referenceframe.Payload{Mass: 1}, []float64{2}, []float64{4})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, maxVel, test.ShouldResemble, []float64{2})
+	test.That(t, maxAcc, test.ShouldResemble, []float64{4})
+}
@@ -4,6 +4,7 @@ package register
 import (
 	// register arms.
 	_ "go.viam.com/rdk/components/arm/fake"
+	_ "go.viam.com/rdk/components/arm/jogguard"
 	_ "go.viam.com/rdk/components/arm/universalrobots"
 	_ "go.viam.com/rdk/components/arm/wrapper"
 )
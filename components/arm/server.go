@@ -74,6 +74,9 @@ func (s *serviceServer) GetJointPositions(ctx context.Context, req *pb.GetJointP
 
 // MoveToPosition returns the position of the arm specified.
 func (s *serviceServer) MoveToPosition(ctx context.Context, req *pb.MoveToPositionRequest) (*pb.MoveToPositionResponse, error) {
+	if err := resource.CheckReservation(ctx, Named(req.Name), req.Extra.AsMap()); err != nil {
+		return nil, err
+	}
 	operation.CancelOtherWithLabel(ctx, req.Name)
 	arm, err := s.coll.Resource(req.Name)
 	if err != nil {
@@ -91,6 +94,9 @@ func (s *serviceServer) MoveToJointPositions(
 	ctx context.Context,
 	req *pb.MoveToJointPositionsRequest,
 ) (*pb.MoveToJointPositionsResponse, error) {
+	if err := resource.CheckReservation(ctx, Named(req.Name), req.Extra.AsMap()); err != nil {
+		return nil, err
+	}
 	operation.CancelOtherWithLabel(ctx, req.Name)
 	arm, err := s.coll.Resource(req.Name)
 	if err != nil {
@@ -108,6 +114,9 @@ func (s *serviceServer) MoveThroughJointPositions(
 	ctx context.Context,
 	req *pb.MoveThroughJointPositionsRequest,
 ) (*pb.MoveThroughJointPositionsResponse, error) {
+	if err := resource.CheckReservation(ctx, Named(req.Name), req.Extra.AsMap()); err != nil {
+		return nil, err
+	}
 	operation.CancelOtherWithLabel(ctx, req.Name)
 	arm, err := s.coll.Resource(req.Name)
 	if err != nil {
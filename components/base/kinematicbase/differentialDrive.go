@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/golang/geo/r3"
+	"go.opencensus.io/trace"
 	utils "go.viam.com/utils"
 
 	"go.viam.com/rdk/components/base"
@@ -55,10 +56,17 @@ func wrapWithDifferentialDriveKinematics(
 	ddk.mutex.Lock()
 	defer ddk.mutex.Unlock()
 
+	footprint, err := options.footprint(b.Name().ShortName())
+	if err != nil {
+		return nil, err
+	}
 	geometries, err := b.Geometries(ctx, nil)
 	if err != nil {
 		return nil, err
 	}
+	if footprint != nil {
+		geometries = []spatialmath.Geometry{footprint}
+	}
 	// RSDK-4131 will update this so it is no longer necessary
 	var geometry, boundingSphere spatialmath.Geometry
 	if len(geometries) > 1 {
@@ -81,7 +89,12 @@ func wrapWithDifferentialDriveKinematics(
 		}
 	}
 
-	ddk.localizationFrame, err = referenceframe.New2DMobileModelFrame(b.Name().ShortName(), limits, boundingSphere)
+	// a footprint is turned with the heading of the base, so it is only approximated by its bounding sphere when heading is not planned
+	localizationGeometry := boundingSphere
+	if footprint != nil {
+		localizationGeometry = footprint
+	}
+	ddk.localizationFrame, err = referenceframe.New2DMobileModelFrame(b.Name().ShortName(), limits, localizationGeometry)
 	if err != nil {
 		return nil, err
 	}
@@ -143,6 +156,9 @@ func (ddk *differentialDriveKinematics) CurrentInputs(ctx context.Context) ([]re
 }
 
 func (ddk *differentialDriveKinematics) GoToInputs(ctx context.Context, desiredSteps ...[]referenceframe.Input) error {
+	ctx, span := trace.StartSpan(ctx, "kinematicbase::differentialDriveKinematics::GoToInputs")
+	defer span.End()
+
 	ddk.mutex.Lock()
 	ddk.currentTrajectory = desiredSteps
 	ddk.mutex.Unlock()
@@ -155,7 +171,31 @@ func (ddk *differentialDriveKinematics) GoToInputs(ctx context.Context, desiredS
 			return err
 		}
 	}
-	return nil
+	if len(desiredSteps) == 0 {
+		return nil
+	}
+	return ddk.turnToHeading(ctx, desiredSteps[len(desiredSteps)-1])
+}
+
+// turnToHeading spins the base in place to face the heading of the desired inputs, if it is not already within the heading threshold
+// of it. As the base is only driven to the position of each of its steps, this is how it reaches the heading at the end of a plan.
+// Bases planned for without heading, or which cannot localize themselves, are left as they are.
+func (ddk *differentialDriveKinematics) turnToHeading(ctx context.Context, desired []referenceframe.Input) error {
+	if ddk.options.PositionOnlyMode || ddk.Localizer == nil || len(desired) < 3 {
+		return nil
+	}
+	current, err := ddk.CurrentInputs(ctx)
+	if err != nil {
+		return err
+	}
+	_, headingErr, err := ddk.inputDiff(current, desired)
+	if err != nil {
+		return err
+	}
+	if math.Abs(headingErr) <= ddk.options.HeadingThresholdDegrees {
+		return nil
+	}
+	return ddk.Spin(ctx, headingErr, ddk.options.AngularVelocityDegsPerSec, nil)
 }
 
 func (ddk *differentialDriveKinematics) goToInputs(ctx context.Context, desired []referenceframe.Input) error {
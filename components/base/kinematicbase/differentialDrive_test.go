@@ -152,6 +152,42 @@ func TestInputDiff(t *testing.T) {
 	test.That(t, headingErr, test.ShouldAlmostEqual, 30)
 }
 
+func TestTurnToHeading(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+
+	slam := inject.NewSLAMService("the slammer")
+	slam.PositionFunc = func(ctx context.Context) (spatialmath.Pose, error) {
+		return spatialmath.NewZeroPose(), nil
+	}
+	ddk, err := buildTestDDK(ctx, testConfig(), true, defaultLinearVelocityMMPerSec, defaultAngularVelocityDegsPerSec, logger)
+	test.That(t, err, test.ShouldBeNil)
+	ddk.Localizer = motion.NewSLAMLocalizer(slam)
+	spins := []float64{}
+	injectBase := inject.NewBase("base")
+	injectBase.SpinFunc = func(ctx context.Context, angleDeg, degsPerSec float64, extra map[string]interface{}) error {
+		spins = append(spins, angleDeg)
+		return nil
+	}
+	ddk.Base = injectBase
+	heading := func(degrees float64) []referenceframe.Input {
+		return []referenceframe.Input{{Value: 0}, {Value: 0}, {Value: utils.DegToRad(degrees)}}
+	}
+
+	// bases planned for without heading stay as they are
+	ddk.options.PositionOnlyMode = true
+	test.That(t, ddk.turnToHeading(ctx, heading(90)), test.ShouldBeNil)
+	test.That(t, spins, test.ShouldBeEmpty)
+
+	// otherwise they turn to the heading of the end of their plans unless already within the threshold of it
+	ddk.options.PositionOnlyMode = false
+	test.That(t, ddk.turnToHeading(ctx, heading(ddk.options.HeadingThresholdDegrees/2)), test.ShouldBeNil)
+	test.That(t, spins, test.ShouldBeEmpty)
+	test.That(t, ddk.turnToHeading(ctx, heading(-90)), test.ShouldBeNil)
+	test.That(t, spins, test.ShouldHaveLength, 1)
+	test.That(t, spins[0], test.ShouldAlmostEqual, -90)
+}
+
 func buildTestDDK(
 	ctx context.Context,
 	cfg resource.Config,
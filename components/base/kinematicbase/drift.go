@@ -0,0 +1,98 @@
+//go:build !no_cgo
+
+package kinematicbase
+
+import (
+	"context"
+	"math"
+
+	"github.com/golang/geo/r3"
+
+	"go.viam.com/rdk/utils"
+)
+
+// DriftSource returns the velocity in mm/s, in the frame of a base, at which the medium it moves through, such as wind or water,
+// carries it over the ground.
+type DriftSource func(ctx context.Context) (r3.Vector, error)
+
+// DriftOptions configures feedforward compensation for the drift of a base which the medium it moves through constantly carries, such
+// as a boat in a current or a drone in the wind. While driving the arcs of a plan, the velocities commanded to the base are biased
+// against the drift so that it moves over the ground as planned. Drift along the heading of the base is compensated by changing its
+// speed. Drift across it is compensated by commanding the base sideways, or, for bases which cannot move sideways, by turning the base
+// into the drift so that it crabs along its path. Drift compensation is not used by the local planner, which chooses its own
+// velocities.
+type DriftOptions struct {
+	// Drift returns the drift to compensate for. It may be set after the base is wrapped, but before GoToInputs is called.
+	Drift DriftSource
+
+	// Gain is the fraction of the drift which is compensated for. Estimates of drift are noisy, so compensating for less than all of
+	// it leaves course correction to take up the rest.
+	Gain float64
+
+	// Crab, if true, compensates for drift across the heading of the base by turning its heading into the drift rather than by
+	// commanding it sideways.
+	Crab bool
+}
+
+// NewDriftOptions creates a struct with values used to compensate for the drift returned by the given source.
+// all other values are pre-set to reasonable default values and can be changed if desired.
+func NewDriftOptions(drift DriftSource) *DriftOptions {
+	return &DriftOptions{
+		Drift: drift,
+		Gain:  1,
+		Crab:  true,
+	}
+}
+
+// compensate returns the velocities to command a base with so that, carried by the given drift in its frame, it moves over the ground
+// with the given linear and angular velocities. crabDegs is the angle the base is turned into the drift by, counterclockwise from its
+// path, which is turned to a new angle over the given number of seconds. The new angle is returned.
+func (opts *DriftOptions) compensate(linear, angular, drift r3.Vector, crabDegs, seconds float64) (r3.Vector, r3.Vector, float64) {
+	drift = drift.Mul(opts.Gain)
+	if !opts.Crab {
+		return linear.Sub(drift), angular, crabDegs
+	}
+
+	// the drift is measured by the base, which is turned away from its path by the crab angle
+	crab := utils.DegToRad(crabDegs)
+	alongPath := r3.Vector{
+		X: drift.X*math.Cos(crab) - drift.Y*math.Sin(crab),
+		Y: drift.X*math.Sin(crab) + drift.Y*math.Cos(crab),
+		Z: drift.Z,
+	}
+	forward := linear.Y - alongPath.Y
+	newCrab := crab
+	// while spinning in place the base holds its heading, and so only holds its position along it
+	if linear.Y != 0 && forward != 0 {
+		newCrab = math.Atan(alongPath.X / forward)
+		forward /= math.Cos(newCrab)
+	}
+	newCrabDegs := utils.RadToDeg(newCrab)
+	if seconds > 0 {
+		angular.Z += (newCrabDegs - crabDegs) / seconds
+	}
+	return r3.Vector{Y: forward, Z: linear.Z - alongPath.Z}, angular, newCrabDegs
+}
+
+// drifting returns whether the base compensates for drift.
+func (ptgk *ptgBaseKinematics) drifting() bool {
+	opts := ptgk.opts.Drift
+	return opts != nil && opts.Drift != nil
+}
+
+// velocities returns the velocities to command the base with to drive the given step with its velocities scaled by scale,
+// compensated for drift if configured to. crabDegs is the angle the base is turned into the drift by, which is updated as the base is
+// turned further. If the drift cannot be read, the base is commanded as though there were none, holding its crab angle.
+func (ptgk *ptgBaseKinematics) velocities(ctx context.Context, step arcStep, scale float64, crabDegs *float64) (r3.Vector, r3.Vector) {
+	linear, angular := step.linVelMMps.Mul(scale), step.angVelDegps.Mul(scale)
+	if !ptgk.drifting() {
+		return linear, angular
+	}
+	drift, err := ptgk.opts.Drift.Drift(ctx)
+	if err != nil {
+		ptgk.logger.CDebugf(ctx, "could not get drift to compensate for, ignoring it: %v", err)
+		return linear, angular
+	}
+	linear, angular, *crabDegs = ptgk.opts.Drift.compensate(linear, angular, drift, *crabDegs, ptgk.opts.UpdateStepSeconds)
+	return linear, angular
+}
@@ -0,0 +1,52 @@
+package kinematicbase
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+)
+
+func TestDriftCompensation(t *testing.T) {
+	opts := NewDriftOptions(nil)
+	test.That(t, opts.Gain, test.ShouldEqual, 1)
+	forward := r3.Vector{Y: 200}
+
+	t.Run("bases which move sideways are commanded against the drift", func(t *testing.T) {
+		sideways := &DriftOptions{Gain: 0.5}
+		linear, angular, crab := sideways.compensate(forward, r3.Vector{Z: 10}, r3.Vector{X: 50, Y: -30, Z: 20}, 0, 0.5)
+		test.That(t, linear.X, test.ShouldAlmostEqual, -25)
+		test.That(t, linear.Y, test.ShouldAlmostEqual, 215)
+		test.That(t, linear.Z, test.ShouldAlmostEqual, -10)
+		test.That(t, angular.Z, test.ShouldEqual, 10)
+		test.That(t, crab, test.ShouldEqual, 0)
+	})
+
+	t.Run("other bases crab into the drift", func(t *testing.T) {
+		drift := r3.Vector{X: 100}
+		linear, angular, crab := opts.compensate(forward, r3.Vector{}, drift, 0, 0.5)
+		crabRad := math.Atan(0.5)
+		test.That(t, crab, test.ShouldAlmostEqual, crabRad*180/math.Pi)
+		test.That(t, angular.Z, test.ShouldAlmostEqual, 2*crab)
+		test.That(t, linear.X, test.ShouldEqual, 0)
+		test.That(t, linear.Y, test.ShouldAlmostEqual, math.Hypot(200, 100))
+		// turned into the drift, the base is carried back onto its path
+		test.That(t, drift.X-linear.Y*math.Sin(crabRad), test.ShouldAlmostEqual, 0)
+		test.That(t, linear.Y*math.Cos(crabRad), test.ShouldAlmostEqual, 200)
+
+		// once crabbed, the base measures the same drift turned away from it, and holds its heading
+		turned := r3.Vector{X: 100 * math.Cos(crabRad), Y: -100 * math.Sin(crabRad)}
+		linear, angular, newCrab := opts.compensate(forward, r3.Vector{}, turned, crab, 0.5)
+		test.That(t, newCrab, test.ShouldAlmostEqual, crab)
+		test.That(t, angular.Z, test.ShouldAlmostEqual, 0)
+		test.That(t, linear.Y, test.ShouldAlmostEqual, math.Hypot(200, 100))
+	})
+
+	t.Run("spinning bases only hold their position along their heading", func(t *testing.T) {
+		linear, angular, crab := opts.compensate(r3.Vector{}, r3.Vector{Z: 30}, r3.Vector{X: 100, Y: 40}, 5, 0.5)
+		test.That(t, crab, test.ShouldAlmostEqual, 5)
+		test.That(t, angular.Z, test.ShouldAlmostEqual, 30)
+		test.That(t, linear.Y, test.ShouldAlmostEqual, -(100*math.Sin(5*math.Pi/180) + 40*math.Cos(5*math.Pi/180)))
+	})
+}
@@ -10,8 +10,8 @@ import (
 	"time"
 
 	"github.com/golang/geo/r3"
+	"go.opencensus.io/trace"
 	"go.uber.org/multierr"
-	"go.viam.com/utils"
 
 	"go.viam.com/rdk/motionplan"
 	"go.viam.com/rdk/motionplan/ik"
@@ -67,6 +67,9 @@ type courseCorrectionGoal struct {
 }
 
 func (ptgk *ptgBaseKinematics) GoToInputs(ctx context.Context, inputSteps ...[]referenceframe.Input) error {
+	ctx, span := trace.StartSpan(ctx, "kinematicbase::ptgBaseKinematics::GoToInputs")
+	defer span.End()
+
 	var err error
 	// Cancel any prior GoToInputs calls
 	if ptgk.cancelFunc != nil {
@@ -104,7 +107,11 @@ func (ptgk *ptgBaseKinematics) GoToInputs(ctx context.Context, inputSteps ...[]r
 	ptgk.inputLock.Lock()
 	ptgk.currentState.currentExecutingSteps = arcSteps
 	ptgk.inputLock.Unlock()
+	if ptgk.opts.LocalPlanner != nil && ptgk.Localizer != nil {
+		return tryStop(ptgk.followWithLocalPlanner(ctx, arcSteps))
+	}
 	updateDuration := ptgk.opts.UpdateStepSeconds
+	crabDegs := 0. // how far the base is turned into any drift it compensates for
 
 	for i := 0; i < len(arcSteps); i++ {
 		if ctx.Err() != nil {
@@ -118,26 +125,31 @@ func (ptgk *ptgBaseKinematics) GoToInputs(ctx context.Context, inputSteps ...[]r
 
 		ptgk.logger.Debugf("step, i %d \n %s", i, step.String())
 
-		err = ptgk.Base.SetVelocity(
-			ctx,
-			step.linVelMMps,
-			step.angVelDegps,
-			nil,
-		)
+		// The velocities of the step are scaled down near obstacles and within safety zones, so that the step takes longer to drive.
+		// Progress through the step is tracked in the time it would take at full speed, which is what its inputs and duration are in
+		// terms of.
+		scale, err := ptgk.stepScale(ctx, step)
+		if err != nil {
+			return tryStop(err)
+		}
+		linVel, angVel := ptgk.velocities(ctx, step, scale, &crabDegs)
+		err = ptgk.Base.SetVelocity(ctx, linVel, angVel, nil)
 		if err != nil {
 			return tryStop(err)
 		}
 
-		arcStartTime := time.Now()
+		arcStartTime := ptgk.clock.Now()
 		// Now we are moving. We need to do several things simultaneously:
 		// - move until we think we have finished the arc, then move on to the next step
 		// - update our CurrentInputs tracking where we are through the arc
 		// - Check where we are relative to where we think we are, and tweak velocities accordingly
-		stepDuration := time.Duration(step.durationSeconds*1000) * time.Millisecond
+		// - Scale our velocities by how near we are to obstacles, and cap them within safety zones
+		// - Bias our velocities against any drift of the medium we move through
+		// - Stop in place while we are held, and resume once released
 
 		// Check if this arc is shorter than our typical check time; if so just run that and do not course correct.
 		if step.durationSeconds < updateDuration {
-			utils.SelectContextOrWait(ctx, stepDuration)
+			selectContextOrWait(ctx, ptgk.clock, scaledDuration(step.durationSeconds, scale))
 			if ctx.Err() != nil {
 				return tryStop(ctx.Err())
 			}
@@ -145,20 +157,24 @@ func (ptgk *ptgBaseKinematics) GoToInputs(ctx context.Context, inputSteps ...[]r
 			continue
 		}
 		courseCorrected := false // used to distinguish between a break due to course correction, or running out the loop
+		progressSeconds := 0.    // how far through the step we are, in seconds at full speed
+		scaledSeconds := 0.      // how long it should have taken to get that far through the step at the speeds we have driven
 
 		for timeElapsedSeconds := updateDuration; timeElapsedSeconds <= step.durationSeconds; timeElapsedSeconds += updateDuration {
 			if ctx.Err() != nil {
 				return ctx.Err()
 			}
+			scaledSeconds += (timeElapsedSeconds - progressSeconds) / scale
+			progressSeconds = timeElapsedSeconds
 
 			// Account for 1) timeElapsedSeconds being inputUpdateStepSeconds ahead of actual elapsed time, and the fact that the loop takes
 			// nonzero time to run especially when using the localizer.
-			actualTimeElapsed := time.Since(arcStartTime)
+			actualTimeElapsed := ptgk.clock.Since(arcStartTime)
 			// Time durations are ints, not floats. 0.9 * time.Second is zero. Thus we use microseconds for math.
-			remainingTimeStep := time.Duration(microsecondsPerSecond*timeElapsedSeconds)*time.Microsecond - actualTimeElapsed
+			remainingTimeStep := scaledDuration(scaledSeconds, 1) - actualTimeElapsed
 
 			if remainingTimeStep > 0 {
-				utils.SelectContextOrWait(ctx, remainingTimeStep)
+				selectContextOrWait(ctx, ptgk.clock, remainingTimeStep)
 				if ctx.Err() != nil {
 					return tryStop(ctx.Err())
 				}
@@ -197,9 +213,33 @@ func (ptgk *ptgBaseKinematics) GoToInputs(ctx context.Context, inputSteps ...[]r
 					break
 				}
 			}
+
+			// Time spent holding is not progress through the step, so the step is treated as having started that much later.
+			if ptgk.held(ctx) {
+				heldFor, err := ptgk.hold(ctx, step, scale)
+				if err != nil {
+					return tryStop(err)
+				}
+				arcStartTime = arcStartTime.Add(heldFor)
+			}
+
+			// Drift changes continually, so velocities compensated for it are commanded anew at every update.
+			newScale, err := ptgk.stepScale(ctx, step)
+			if err != nil {
+				return tryStop(err)
+			}
+			if newScale != scale || ptgk.drifting() {
+				scale = newScale
+				linVel, angVel := ptgk.velocities(ctx, step, scale, &crabDegs)
+				err = ptgk.Base.SetVelocity(ctx, linVel, angVel, nil)
+				if err != nil {
+					return tryStop(err)
+				}
+			}
 		}
-		if time.Since(arcStartTime) < stepDuration && !courseCorrected {
-			utils.SelectContextOrWait(ctx, stepDuration-time.Since(arcStartTime))
+		stepDuration := scaledDuration(scaledSeconds+(step.durationSeconds-progressSeconds)/scale, 1)
+		if ptgk.clock.Since(arcStartTime) < stepDuration && !courseCorrected {
+			selectContextOrWait(ctx, ptgk.clock, stepDuration-ptgk.clock.Since(arcStartTime))
 			if ctx.Err() != nil {
 				return tryStop(ctx.Err())
 			}
@@ -0,0 +1,286 @@
+//go:build !no_cgo
+
+// Package fake implements a simulated kinematic base which drives through a synthetic world, allowing motion planning and
+// replanning to be exercised without hardware or a SLAM service.
+package fake
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	goutils "go.viam.com/utils"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/components/base/kinematicbase"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/spatialmath"
+	rdkutils "go.viam.com/rdk/utils"
+	viz "go.viam.com/rdk/vision"
+)
+
+const (
+	// defaultWidthMeters is the width reported by a simulated base which has no box geometry.
+	defaultWidthMeters = 0.6
+	// collisionStepMM and collisionStepDegs are the furthest a base may drive and turn between the poses it is checked for
+	// collisions at, so that it cannot pass through thin obstacles between updates.
+	collisionStepMM   = 10.
+	collisionStepDegs = 5.
+)
+
+// Base is a base whose motion is simulated by integrating the velocities it is commanded with over time. It localizes itself within
+// a World, reporting its position through the World's noise model, and records any obstacles it collides with.
+type Base struct {
+	resource.Named
+	resource.TriviallyReconfigurable
+	resource.TriviallyCloseable
+
+	world               *World
+	geometry            spatialmath.Geometry
+	turningRadiusMeters float64
+	widthMeters         float64
+	logger              logging.Logger
+
+	mu          sync.Mutex
+	pose        spatialmath.Pose
+	linVelMMps  float64
+	angVelDegps float64
+	lastUpdate  time.Time
+	collisions  []string
+}
+
+// NewBase returns a simulated base within the world, starting at the given world frame pose. The geometry, which may be nil, is
+// specified in the frame of the base and is used for collision checking against the world's obstacles.
+func NewBase(
+	name resource.Name,
+	world *World,
+	start spatialmath.Pose,
+	geometry spatialmath.Geometry,
+	turningRadiusMeters float64,
+	logger logging.Logger,
+) *Base {
+	if world == nil {
+		world = &World{}
+	}
+	widthMeters := defaultWidthMeters
+	if geometry != nil {
+		if box := geometry.ToProtobuf().GetBox(); box != nil {
+			widthMeters = box.GetDimsMm().GetX() * 0.001
+		}
+	}
+	return &Base{
+		Named:               name.AsNamed(),
+		world:               world,
+		geometry:            geometry,
+		turningRadiusMeters: turningRadiusMeters,
+		widthMeters:         widthMeters,
+		logger:              logger,
+		pose:                start,
+		lastUpdate:          world.clock().Now(),
+	}
+}
+
+// WrapWithKinematics wraps the simulated base with kinematics, using the base itself as the localizer.
+func (b *Base) WrapWithKinematics(
+	ctx context.Context,
+	limits []referenceframe.Limit,
+	options kinematicbase.Options,
+) (kinematicbase.KinematicBase, error) {
+	return kinematicbase.WrapWithKinematics(ctx, b, b.logger, b, limits, options)
+}
+
+// TruePose returns the world frame pose of the base without any localization noise.
+func (b *Base) TruePose() spatialmath.Pose {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.advance()
+	return b.pose
+}
+
+// Collisions returns the labels of every obstacle the base has collided with, in the order they were first hit.
+func (b *Base) Collisions() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.advance()
+	return append([]string{}, b.collisions...)
+}
+
+// CurrentPosition returns the pose of the base in the world frame as perturbed by the world's localization noise.
+func (b *Base) CurrentPosition(ctx context.Context) (*referenceframe.PoseInFrame, error) {
+	pose := b.TruePose()
+	if b.world.LocalizationNoise != nil {
+		pose = b.world.LocalizationNoise.Perturb(pose)
+	}
+	return referenceframe.NewPoseInFrame(referenceframe.World, pose), nil
+}
+
+// Confidence returns the standard deviations of the world's localization noise if it is gaussian, and an unknown confidence otherwise.
+func (b *Base) Confidence(ctx context.Context) (motion.LocalizerConfidence, error) {
+	if noise, ok := b.world.LocalizationNoise.(*GaussianPoseNoise); ok {
+		return motion.LocalizerConfidence{PositionStdDevMM: noise.PositionStdDevMM, HeadingStdDevDeg: noise.HeadingStdDevDeg}, nil
+	}
+	return motion.LocalizerConfidence{}, nil
+}
+
+// Detections returns the obstacles of the world within rangeMM of the base as objects in the frame of the base, in the form
+// returned by a vision service's GetObjectPointClouds.
+func (b *Base) Detections(ctx context.Context, rangeMM float64) ([]*viz.Object, error) {
+	objects := []*viz.Object{}
+	for _, g := range b.world.Detections(b.TruePose(), rangeMM) {
+		objects = append(objects, &viz.Object{PointCloud: pointcloud.New(), Geometry: g})
+	}
+	return objects, nil
+}
+
+// MoveStraight drives the base forwards or backwards for the given distance, blocking until the move is complete.
+func (b *Base) MoveStraight(ctx context.Context, distanceMm int, mmPerSec float64, extra map[string]interface{}) error {
+	if mmPerSec == 0 {
+		return errors.New("cannot move straight with a speed of zero")
+	}
+	speed := math.Copysign(math.Abs(mmPerSec), float64(distanceMm))
+	duration := time.Duration(math.Abs(float64(distanceMm)/mmPerSec) * float64(time.Second))
+	return b.runFor(ctx, speed, 0, duration)
+}
+
+// Spin turns the base in place by the given angle, blocking until the turn is complete.
+func (b *Base) Spin(ctx context.Context, angleDeg, degsPerSec float64, extra map[string]interface{}) error {
+	if degsPerSec == 0 {
+		return errors.New("cannot spin with a speed of zero")
+	}
+	if b.turningRadiusMeters > 0 {
+		return errors.New("cannot spin in place with a nonzero turning radius")
+	}
+	speed := math.Copysign(math.Abs(degsPerSec), angleDeg)
+	duration := time.Duration(math.Abs(angleDeg/degsPerSec) * float64(time.Second))
+	return b.runFor(ctx, 0, speed, duration)
+}
+
+// SetPower is not supported by the simulated base, which has no notion of maximum speed.
+func (b *Base) SetPower(ctx context.Context, linear, angular r3.Vector, extra map[string]interface{}) error {
+	return errors.New("simulated base does not support SetPower")
+}
+
+// SetVelocity sets the forward velocity in mm/s and counterclockwise angular velocity in deg/s the base drives at until it is
+// next commanded.
+func (b *Base) SetVelocity(ctx context.Context, linear, angular r3.Vector, extra map[string]interface{}) error {
+	b.setVelocity(linear.Y, angular.Z)
+	return nil
+}
+
+// Stop stops the base.
+func (b *Base) Stop(ctx context.Context, extra map[string]interface{}) error {
+	b.setVelocity(0, 0)
+	return nil
+}
+
+// IsMoving returns whether the base is being driven with a nonzero velocity.
+func (b *Base) IsMoving(ctx context.Context) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.linVelMMps != 0 || b.angVelDegps != 0, nil
+}
+
+// Properties returns the base's properties.
+func (b *Base) Properties(ctx context.Context, extra map[string]interface{}) (base.Properties, error) {
+	return base.Properties{
+		TurningRadiusMeters: b.turningRadiusMeters,
+		WidthMeters:         b.widthMeters,
+	}, nil
+}
+
+// Geometries returns the geometry of the base in its own frame.
+func (b *Base) Geometries(ctx context.Context, extra map[string]interface{}) ([]spatialmath.Geometry, error) {
+	if b.geometry == nil {
+		return []spatialmath.Geometry{}, nil
+	}
+	return []spatialmath.Geometry{b.geometry}, nil
+}
+
+func (b *Base) runFor(ctx context.Context, linVelMMps, angVelDegps float64, duration time.Duration) error {
+	b.setVelocity(linVelMMps, angVelDegps)
+	defer b.setVelocity(0, 0)
+	timer := b.world.clock().Timer(duration)
+	defer timer.Stop()
+	if !goutils.SelectContextOrWaitChan(ctx, timer.C) {
+		return ctx.Err()
+	}
+	return nil
+}
+
+func (b *Base) setVelocity(linVelMMps, angVelDegps float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.advance()
+	b.linVelMMps = linVelMMps
+	b.angVelDegps = angVelDegps
+}
+
+// advance integrates the commanded velocities since the last update into the pose of the base, checking for collisions at steps
+// along the arc it drives. It must be called with mu held.
+func (b *Base) advance() {
+	now := b.world.clock().Now()
+	dt := now.Sub(b.lastUpdate).Seconds()
+	b.lastUpdate = now
+	if dt <= 0 || (b.linVelMMps == 0 && b.angVelDegps == 0) {
+		return
+	}
+	steps := 1
+	if b.geometry != nil {
+		steps = int(math.Max(
+			math.Ceil(math.Abs(b.linVelMMps*dt)/collisionStepMM),
+			math.Ceil(math.Abs(b.angVelDegps*dt)/collisionStepDegs),
+		))
+	}
+	step := arcPose(b.linVelMMps, b.angVelDegps, dt/float64(steps))
+	for i := 0; i < steps; i++ {
+		b.pose = spatialmath.Compose(b.pose, step)
+		b.checkCollisions()
+	}
+}
+
+// checkCollisions records any obstacles that the base currently collides with. It must be called with mu held.
+func (b *Base) checkCollisions() {
+	if b.geometry == nil {
+		return
+	}
+	hit, err := b.world.Collisions([]spatialmath.Geometry{b.geometry.Transform(b.pose)})
+	if err != nil {
+		b.logger.Debugf("unable to check simulated base for collisions: %v", err)
+		return
+	}
+	for _, label := range hit {
+		seen := false
+		for _, existing := range b.collisions {
+			if existing == label {
+				seen = true
+				break
+			}
+		}
+		if !seen {
+			b.logger.Debugf("simulated base collided with obstacle %q", label)
+			b.collisions = append(b.collisions, label)
+		}
+	}
+}
+
+// arcPose returns the displacement, in the frame of the base, of a base driving forward along +Y at the given velocities for dt
+// seconds. Positive angular velocities turn to the left.
+func arcPose(linVelMMps, angVelDegps, dt float64) spatialmath.Pose {
+	dTheta := angVelDegps * dt
+	if angVelDegps == 0 {
+		return spatialmath.NewPoseFromPoint(r3.Vector{Y: linVelMMps * dt})
+	}
+	radius := linVelMMps / rdkutils.DegToRad(angVelDegps)
+	dThetaRad := rdkutils.DegToRad(dTheta)
+	return spatialmath.NewPose(
+		r3.Vector{X: -radius * (1 - math.Cos(dThetaRad)), Y: radius * math.Sin(dThetaRad)},
+		&spatialmath.OrientationVectorDegrees{OZ: 1, Theta: dTheta},
+	)
+}
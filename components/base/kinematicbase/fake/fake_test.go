@@ -0,0 +1,175 @@
+//go:build !no_cgo
+
+package fake
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/components/base/kinematicbase"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+func TestArcPose(t *testing.T) {
+	straight := arcPose(100, 0, 2)
+	test.That(t, spatialmath.PoseAlmostCoincident(straight, spatialmath.NewPoseFromPoint(r3.Vector{Y: 200})), test.ShouldBeTrue)
+
+	// a quarter turn to the left ends up forward and to the left of the start
+	quarter := arcPose(100, 90, 1)
+	radius := 100 / (0.5 * math.Pi)
+	expected := spatialmath.NewPose(r3.Vector{X: -radius, Y: radius}, &spatialmath.OrientationVectorDegrees{OZ: 1, Theta: 90})
+	test.That(t, spatialmath.PoseAlmostEqualEps(quarter, expected, 1e-6), test.ShouldBeTrue)
+}
+
+func TestSimulatedBase(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	name := base.Named("simbase")
+
+	geometry, err := spatialmath.NewBox(spatialmath.NewZeroPose(), r3.Vector{X: 200, Y: 200, Z: 200}, "simbase")
+	test.That(t, err, test.ShouldBeNil)
+	wall, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(r3.Vector{Y: 400}), r3.Vector{X: 1000, Y: 100, Z: 100}, "wall")
+	test.That(t, err, test.ShouldBeNil)
+
+	t.Run("MoveStraight and Spin", func(t *testing.T) {
+		b := NewBase(name, &World{}, spatialmath.NewZeroPose(), geometry, 0, logger)
+		test.That(t, b.MoveStraight(ctx, 100, 1000, nil), test.ShouldBeNil)
+		test.That(t, b.TruePose().Point().Y, test.ShouldAlmostEqual, 100, 10)
+		moving, err := b.IsMoving(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, moving, test.ShouldBeFalse)
+
+		test.That(t, b.Spin(ctx, 90, 900, nil), test.ShouldBeNil)
+		test.That(t, b.TruePose().Orientation().OrientationVectorDegrees().Theta, test.ShouldAlmostEqual, 90, 10)
+		test.That(t, b.TruePose().Point().Y, test.ShouldAlmostEqual, 100, 10)
+	})
+
+	t.Run("collisions and detections", func(t *testing.T) {
+		b := NewBase(name, &World{Obstacles: []spatialmath.Geometry{wall}}, spatialmath.NewZeroPose(), geometry, 0, logger)
+		detections, err := b.Detections(ctx, 1000)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(detections), test.ShouldEqual, 1)
+		test.That(t, detections[0].Geometry.Pose().Point(), test.ShouldResemble, r3.Vector{Y: 400})
+		detections, err = b.Detections(ctx, 100)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, detections, test.ShouldBeEmpty)
+
+		test.That(t, b.Collisions(), test.ShouldBeEmpty)
+		test.That(t, b.MoveStraight(ctx, 400, 2000, nil), test.ShouldBeNil)
+		test.That(t, b.Collisions(), test.ShouldResemble, []string{"wall"})
+	})
+
+	t.Run("localization noise", func(t *testing.T) {
+		world := &World{LocalizationNoise: NewGaussianPoseNoise(50, 5, 1)}
+		b := NewBase(name, world, spatialmath.NewZeroPose(), geometry, 0, logger)
+		pif, err := b.CurrentPosition(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, pif.Parent(), test.ShouldEqual, referenceframe.World)
+		test.That(t, spatialmath.PoseAlmostCoincident(pif.Pose(), b.TruePose()), test.ShouldBeFalse)
+
+		// the same seed produces the same noise
+		other := NewBase(name, &World{LocalizationNoise: NewGaussianPoseNoise(50, 5, 1)}, spatialmath.NewZeroPose(), geometry, 0, logger)
+		otherPif, err := other.CurrentPosition(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, spatialmath.PoseAlmostEqual(pif.Pose(), otherPif.Pose()), test.ShouldBeTrue)
+	})
+
+	t.Run("cancelled moves stop the base", func(t *testing.T) {
+		b := NewBase(name, &World{}, spatialmath.NewZeroPose(), geometry, 0, logger)
+		cancelCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		err := b.MoveStraight(cancelCtx, 10000, 1000, nil)
+		test.That(t, err, test.ShouldBeError, context.DeadlineExceeded)
+		moving, err := b.IsMoving(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, moving, test.ShouldBeFalse)
+		test.That(t, b.TruePose().Point().Y, test.ShouldBeLessThan, 1000)
+	})
+
+	t.Run("mock clock", func(t *testing.T) {
+		clk := clock.NewMock()
+		world := &World{Clock: clk}
+		b := NewBase(name, world, spatialmath.NewZeroPose(), geometry, 0, logger)
+		test.That(t, b.SetVelocity(ctx, r3.Vector{Y: 100}, r3.Vector{}, nil), test.ShouldBeNil)
+		test.That(t, b.TruePose().Point().Y, test.ShouldEqual, 0)
+		clk.Add(2 * time.Second)
+		test.That(t, b.TruePose().Point().Y, test.ShouldAlmostEqual, 200)
+		test.That(t, b.Stop(ctx, nil), test.ShouldBeNil)
+
+		// obstacles added once the base is driving are detected and collided with
+		test.That(t, b.Collisions(), test.ShouldBeEmpty)
+		world.AddObstacles(wall)
+		detections, err := b.Detections(ctx, 0)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(detections), test.ShouldEqual, 1)
+		test.That(t, detections[0].Geometry.Pose().Point(), test.ShouldResemble, r3.Vector{Y: 200})
+		test.That(t, b.SetVelocity(ctx, r3.Vector{Y: 100}, r3.Vector{}, nil), test.ShouldBeNil)
+		clk.Add(time.Second)
+		test.That(t, b.Collisions(), test.ShouldResemble, []string{"wall"})
+	})
+
+	t.Run("obstacles passed through between updates are collided with", func(t *testing.T) {
+		clk := clock.NewMock()
+		b := NewBase(name, &World{Clock: clk, Obstacles: []spatialmath.Geometry{wall}}, spatialmath.NewZeroPose(), geometry, 0, logger)
+		test.That(t, b.SetVelocity(ctx, r3.Vector{Y: 1000}, r3.Vector{}, nil), test.ShouldBeNil)
+		clk.Add(2 * time.Second)
+		test.That(t, b.TruePose().Point().Y, test.ShouldAlmostEqual, 2000)
+		test.That(t, b.Collisions(), test.ShouldResemble, []string{"wall"})
+	})
+}
+
+func TestSimulatedKinematicBase(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+
+	geometry, err := spatialmath.NewBox(spatialmath.NewZeroPose(), r3.Vector{X: 200, Y: 200, Z: 200}, "simbase")
+	test.That(t, err, test.ShouldBeNil)
+	b := NewBase(base.Named("simbase"), &World{}, spatialmath.NewZeroPose(), geometry, 0, logger)
+
+	opts := kinematicbase.NewKinematicBaseOptions()
+	opts.LinearVelocityMMPerSec = 1000
+	opts.AngularVelocityDegsPerSec = 180
+	opts.UpdateStepSeconds = 0.1
+	kb, err := b.WrapWithKinematics(ctx, nil, opts)
+	test.That(t, err, test.ShouldBeNil)
+
+	fs := referenceframe.NewEmptyFrameSystem("test")
+	f := kb.Kinematics()
+	test.That(t, fs.AddFrame(f, fs.World()), test.ShouldBeNil)
+
+	goal := spatialmath.NewPoseFromPoint(r3.Vector{X: -500, Y: 1000})
+	plan, err := motionplan.PlanMotion(ctx, &motionplan.PlanRequest{
+		Logger: logger,
+		Goals: []*motionplan.PlanState{
+			motionplan.NewPlanState(referenceframe.FrameSystemPoses{f.Name(): referenceframe.NewPoseInFrame(referenceframe.World, goal)}, nil),
+		},
+		StartState: motionplan.NewPlanState(
+			referenceframe.FrameSystemPoses{f.Name(): referenceframe.NewZeroPoseInFrame(referenceframe.World)},
+			referenceframe.NewZeroInputs(fs),
+		),
+		FrameSystem: fs,
+	})
+	test.That(t, err, test.ShouldBeNil)
+
+	steps := [][]referenceframe.Input{}
+	for _, inputs := range plan.Trajectory() {
+		steps = append(steps, inputs[f.Name()])
+	}
+	test.That(t, kb.GoToInputs(ctx, steps...), test.ShouldBeNil)
+
+	// the simulated base should have driven to within the goal radius of the planner
+	test.That(t, b.TruePose().Point().Distance(goal.Point()), test.ShouldBeLessThan, opts.GoalRadiusMM)
+	moving, err := kb.IsMoving(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, moving, test.ShouldBeFalse)
+}
@@ -0,0 +1,214 @@
+//go:build !no_cgo
+
+package fake
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"go.uber.org/multierr"
+
+	"go.viam.com/rdk/components/base/kinematicbase"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// TraceSample is the state of a simulated base at a point in time during the execution of a plan.
+type TraceSample struct {
+	ElapsedMS    float64 `json:"elapsed_ms"`
+	LinVelMMps   float64 `json:"linear_mm_per_sec"`
+	AngVelDegps  float64 `json:"angular_degs_per_sec"`
+	XMM          float64 `json:"x_mm"`
+	YMM          float64 `json:"y_mm"`
+	ThetaDegrees float64 `json:"theta_degs"`
+}
+
+// Trace is a record of a simulated base executing a plan: the inputs it was commanded to go to, and its true pose and commanded
+// velocities sampled over the course of the execution.
+type Trace struct {
+	Waypoints [][]float64   `json:"waypoints"`
+	Samples   []TraceSample `json:"samples"`
+}
+
+// TraceTolerance is how far a trace may differ from a golden trace before it is considered a regression.
+type TraceTolerance struct {
+	// PositionMM is the furthest the base may be from its golden position at the same point in time.
+	PositionMM float64
+	// HeadingDeg is the largest difference allowed between the base's heading and its golden heading at the same point in time.
+	HeadingDeg float64
+	// Duration is the largest difference allowed between the durations of the executions.
+	Duration time.Duration
+}
+
+// RecordTrace executes the waypoints on a kinematic base wrapping the simulated base, sampling the true pose and commanded
+// velocities of the base at the given interval until the execution completes.
+func RecordTrace(
+	ctx context.Context,
+	b *Base,
+	kb kinematicbase.KinematicBase,
+	waypoints [][]referenceframe.Input,
+	interval time.Duration,
+) (*Trace, error) {
+	trace := &Trace{}
+	for _, inputs := range waypoints {
+		trace.Waypoints = append(trace.Waypoints, referenceframe.InputsToFloats(inputs))
+	}
+
+	start := time.Now()
+	var mu sync.Mutex
+	sample := func() {
+		mu.Lock()
+		defer mu.Unlock()
+		trace.Samples = append(trace.Samples, b.traceSample(time.Since(start)))
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				sample()
+			}
+		}
+	}()
+
+	sample()
+	err := kb.GoToInputs(ctx, waypoints...)
+	close(done)
+	wg.Wait()
+	if err != nil {
+		return nil, err
+	}
+	sample()
+	return trace, nil
+}
+
+func (b *Base) traceSample(elapsed time.Duration) TraceSample {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.advance()
+	return TraceSample{
+		ElapsedMS:    float64(elapsed) / float64(time.Millisecond),
+		LinVelMMps:   b.linVelMMps,
+		AngVelDegps:  b.angVelDegps,
+		XMM:          b.pose.Point().X,
+		YMM:          b.pose.Point().Y,
+		ThetaDegrees: b.pose.Orientation().OrientationVectorDegrees().Theta,
+	}
+}
+
+// Duration returns how long the traced execution took.
+func (t *Trace) Duration() time.Duration {
+	if len(t.Samples) == 0 {
+		return 0
+	}
+	return time.Duration(t.Samples[len(t.Samples)-1].ElapsedMS * float64(time.Millisecond))
+}
+
+// PoseAt returns the pose of the base at the given time into the execution, linearly interpolating between samples. Times outside
+// of the trace return the first or last sampled pose.
+func (t *Trace) PoseAt(elapsed time.Duration) spatialmath.Pose {
+	if len(t.Samples) == 0 {
+		return spatialmath.NewZeroPose()
+	}
+	elapsedMS := float64(elapsed) / float64(time.Millisecond)
+	i := sort.Search(len(t.Samples), func(i int) bool { return t.Samples[i].ElapsedMS >= elapsedMS })
+	switch {
+	case i == 0:
+		return t.Samples[0].pose()
+	case i == len(t.Samples):
+		return t.Samples[len(t.Samples)-1].pose()
+	}
+	before, after := t.Samples[i-1], t.Samples[i]
+	by := 0.
+	if span := after.ElapsedMS - before.ElapsedMS; span > 0 {
+		by = (elapsedMS - before.ElapsedMS) / span
+	}
+	return spatialmath.Interpolate(before.pose(), after.pose(), by)
+}
+
+func (s TraceSample) pose() spatialmath.Pose {
+	return spatialmath.NewPose(r3.Vector{X: s.XMM, Y: s.YMM}, &spatialmath.OrientationVectorDegrees{OZ: 1, Theta: s.ThetaDegrees})
+}
+
+// CompareTraces returns an error describing every way in which the trace differs from the golden trace by more than the tolerance.
+// The commanded waypoints must match exactly, and the pose of the base is compared at the time of every golden sample.
+func CompareTraces(golden, actual *Trace, tolerance TraceTolerance) error {
+	var errs error
+	if len(golden.Waypoints) != len(actual.Waypoints) {
+		errs = multierr.Combine(errs, fmt.Errorf("expected %d waypoints, got %d", len(golden.Waypoints), len(actual.Waypoints)))
+	} else {
+		for i := range golden.Waypoints {
+			if !floatsAlmostEqual(golden.Waypoints[i], actual.Waypoints[i]) {
+				errs = multierr.Combine(errs, fmt.Errorf("waypoint %d: expected %v, got %v", i, golden.Waypoints[i], actual.Waypoints[i]))
+			}
+		}
+	}
+
+	if diff := actual.Duration() - golden.Duration(); diff > tolerance.Duration || diff < -tolerance.Duration {
+		errs = multierr.Combine(errs, fmt.Errorf("execution took %v, expected %v", actual.Duration(), golden.Duration()))
+	}
+
+	for _, s := range golden.Samples {
+		elapsed := time.Duration(s.ElapsedMS * float64(time.Millisecond))
+		expected, got := s.pose(), actual.PoseAt(elapsed)
+		if dist := expected.Point().Distance(got.Point()); dist > tolerance.PositionMM {
+			errs = multierr.Combine(errs, fmt.Errorf("at %v the base was %.0fmm from its golden position", elapsed, dist))
+		}
+		theta := got.Orientation().OrientationVectorDegrees().Theta
+		if diff := math.Abs(math.Mod(theta-s.ThetaDegrees+540, 360) - 180); diff > tolerance.HeadingDeg {
+			errs = multierr.Combine(errs, fmt.Errorf("at %v the base heading was %.1f degrees from its golden heading", elapsed, diff))
+		}
+	}
+	return errs
+}
+
+// WriteTrace writes the trace to a JSON file at the given path.
+func WriteTrace(path string, trace *Trace) error {
+	data, err := json.MarshalIndent(trace, "", "  ")
+	if err != nil {
+		return err
+	}
+	//nolint:gosec
+	return os.WriteFile(path, data, 0o644)
+}
+
+// ReadTrace reads a trace from a JSON file at the given path.
+func ReadTrace(path string) (*Trace, error) {
+	//nolint:gosec
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	trace := &Trace{}
+	if err := json.Unmarshal(data, trace); err != nil {
+		return nil, err
+	}
+	return trace, nil
+}
+
+func floatsAlmostEqual(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if math.Abs(a[i]-b[i]) > 1e-6 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,73 @@
+//go:build !no_cgo
+
+package fake
+
+import (
+	"context"
+	"flag"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/components/base/kinematicbase"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+var updateGolden = flag.Bool("update-golden", false, "rewrite the golden execution traces of the simulated base")
+
+func TestGoldenTrace(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	goldenPath := filepath.Join("testdata", "straight_turn_straight.json")
+	tolerance := TraceTolerance{PositionMM: 100, HeadingDeg: 10, Duration: 500 * time.Millisecond}
+
+	record := func(t *testing.T) *Trace {
+		t.Helper()
+		geometry, err := spatialmath.NewBox(spatialmath.NewZeroPose(), r3.Vector{X: 200, Y: 200, Z: 200}, "simbase")
+		test.That(t, err, test.ShouldBeNil)
+		b := NewBase(base.Named("simbase"), &World{}, spatialmath.NewZeroPose(), geometry, 0, logger)
+		opts := kinematicbase.NewKinematicBaseOptions()
+		opts.LinearVelocityMMPerSec = 1000
+		opts.AngularVelocityDegsPerSec = 180
+		opts.UpdateStepSeconds = 0.1
+		kb, err := b.WrapWithKinematics(ctx, nil, opts)
+		test.That(t, err, test.ShouldBeNil)
+
+		// drive forwards, then turn left in place and drive forwards again using the differential drive PTG
+		waypoints := [][]referenceframe.Input{
+			referenceframe.FloatsToInputs([]float64{0, 0, 0, 0}),
+			referenceframe.FloatsToInputs([]float64{0, 0, 0, 600}),
+			referenceframe.FloatsToInputs([]float64{0, math.Pi / 2, 0, 400}),
+		}
+		trace, err := RecordTrace(ctx, b, kb, waypoints, 50*time.Millisecond)
+		test.That(t, err, test.ShouldBeNil)
+		return trace
+	}
+
+	trace := record(t)
+	if *updateGolden {
+		test.That(t, WriteTrace(goldenPath, trace), test.ShouldBeNil)
+	}
+	golden, err := ReadTrace(goldenPath)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, CompareTraces(golden, trace, tolerance), test.ShouldBeNil)
+
+	t.Run("deviations from the golden trace are reported", func(t *testing.T) {
+		shifted := &Trace{Waypoints: trace.Waypoints[:2]}
+		for _, s := range trace.Samples {
+			s.XMM += 2 * tolerance.PositionMM
+			shifted.Samples = append(shifted.Samples, s)
+		}
+		err := CompareTraces(golden, shifted, tolerance)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "expected 3 waypoints")
+		test.That(t, err.Error(), test.ShouldContainSubstring, "from its golden position")
+	})
+}
@@ -0,0 +1,117 @@
+//go:build !no_cgo
+
+package fake
+
+import (
+	"sync"
+
+	"github.com/benbjohnson/clock"
+	"github.com/golang/geo/r3"
+
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+// World is a synthetic environment that a simulated kinematic base drives through. Obstacles are specified in the world frame,
+// which is also the frame the simulated base localizes itself in.
+type World struct {
+	// Obstacles are the geometries the base may collide with and which are reported as detections. Once a base is driving through
+	// the world, obstacles may only be added with AddObstacles.
+	Obstacles []spatialmath.Geometry
+	// LocalizationNoise perturbs the true pose of the base before it is reported by CurrentPosition. If nil, localization is perfect.
+	LocalizationNoise NoiseModel
+	// Clock is the clock by which the motion of bases in the world is simulated. If nil, the wall clock is used.
+	Clock clock.Clock
+
+	mu sync.RWMutex
+}
+
+// AddObstacles adds obstacles to the world, which bases driving through it may collide with and detect from then on.
+func (w *World) AddObstacles(obstacles ...spatialmath.Geometry) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.Obstacles = append(w.Obstacles, obstacles...)
+}
+
+func (w *World) obstacles() []spatialmath.Geometry {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.Obstacles
+}
+
+func (w *World) clock() clock.Clock {
+	if w.Clock == nil {
+		return clock.New()
+	}
+	return w.Clock
+}
+
+// NoiseModel describes how the reported pose of a simulated base differs from its true pose.
+type NoiseModel interface {
+	Perturb(spatialmath.Pose) spatialmath.Pose
+}
+
+// GaussianPoseNoise is a NoiseModel which adds zero-mean gaussian noise to the position and heading of a pose.
+type GaussianPoseNoise struct {
+	PositionStdDevMM float64
+	HeadingStdDevDeg float64
+
+	mu     sync.Mutex
+	normal inject.NoiseDistribution
+}
+
+// NewGaussianPoseNoise returns a GaussianPoseNoise model whose samples are deterministic for a given seed.
+func NewGaussianPoseNoise(positionStdDevMM, headingStdDevDeg float64, seed int64) *GaussianPoseNoise {
+	return &GaussianPoseNoise{
+		PositionStdDevMM: positionStdDevMM,
+		HeadingStdDevDeg: headingStdDevDeg,
+		normal:           inject.NewGaussianNoise(1, seed),
+	}
+}
+
+// Perturb returns the pose offset in x, y and heading by normally distributed amounts.
+func (n *GaussianPoseNoise) Perturb(pose spatialmath.Pose) spatialmath.Pose {
+	n.mu.Lock()
+	dx := n.normal.Sample() * n.PositionStdDevMM
+	dy := n.normal.Sample() * n.PositionStdDevMM
+	dTheta := n.normal.Sample() * n.HeadingStdDevDeg
+	n.mu.Unlock()
+
+	theta := pose.Orientation().OrientationVectorDegrees().Theta
+	return spatialmath.NewPose(
+		pose.Point().Add(r3.Vector{X: dx, Y: dy}),
+		&spatialmath.OrientationVectorDegrees{OZ: 1, Theta: theta + dTheta},
+	)
+}
+
+// Collisions returns the labels of the obstacles that collide with any of the given world frame geometries.
+func (w *World) Collisions(geometries []spatialmath.Geometry) ([]string, error) {
+	labels := []string{}
+	for _, obstacle := range w.obstacles() {
+		for _, g := range geometries {
+			collides, err := g.CollidesWith(obstacle, 0)
+			if err != nil {
+				return nil, err
+			}
+			if collides {
+				labels = append(labels, obstacle.Label())
+				break
+			}
+		}
+	}
+	return labels, nil
+}
+
+// Detections returns the obstacles whose centers are within rangeMM of the observer, expressed in the frame of the observer.
+// A non-positive range returns every obstacle.
+func (w *World) Detections(observer spatialmath.Pose, rangeMM float64) []spatialmath.Geometry {
+	toObserver := spatialmath.PoseInverse(observer)
+	detections := []spatialmath.Geometry{}
+	for _, obstacle := range w.obstacles() {
+		if rangeMM > 0 && obstacle.Pose().Point().Distance(observer.Point()) > rangeMM {
+			continue
+		}
+		detections = append(detections, obstacle.Transform(toObserver))
+	}
+	return detections
+}
@@ -0,0 +1,314 @@
+//go:build !no_cgo
+
+package kinematicbase
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+
+	"github.com/golang/geo/r3"
+	"go.opencensus.io/trace"
+	"go.uber.org/multierr"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// defaultMaxFlightHeightMM is the default ceiling of flying bases, the height under which small drones must commonly keep.
+const defaultMaxFlightHeightMM = 120000.
+
+// FlightOptions configures the kinematics of bases which fly, such as drones, moving freely in three dimensions and turning only about
+// the vertical axis. They are planned for in three dimensions, so that they may climb over obstacles, and are flown straight to each
+// step of a plan with SetVelocity, commanding velocities in any direction. Flying bases require a localizer, which must report their
+// height.
+type FlightOptions struct {
+	// MinHeightMM is the lowest the base may be planned to fly, in the frame of its localizer.
+	MinHeightMM float64
+
+	// MaxHeightMM is the highest the base may be planned to fly, in the frame of its localizer.
+	MaxHeightMM float64
+}
+
+// NewFlightOptions creates a struct with values used to plan for and fly a flying base.
+// all values are pre-set to reasonable default values and can be changed if desired.
+func NewFlightOptions() *FlightOptions {
+	return &FlightOptions{MaxHeightMM: defaultMaxFlightHeightMM}
+}
+
+// wrapWithFlightKinematics takes a base which flies and adds a kinematic model so that it can be planned for in three dimensions.
+// The limits are those of its x, y and optionally heading, to which the limits of its height are added.
+func wrapWithFlightKinematics(
+	ctx context.Context,
+	b base.Base,
+	logger logging.Logger,
+	localizer motion.Localizer,
+	limits []referenceframe.Limit,
+	options Options,
+) (KinematicBase, error) {
+	if localizer == nil {
+		return nil, errors.New("flying bases must have a localizer")
+	}
+	if len(limits) < 2 {
+		return nil, errors.New("flying bases must have limits for at least their x and y")
+	}
+	if options.Flight.MaxHeightMM <= options.Flight.MinHeightMM {
+		return nil, errors.New("the maximum height of a flying base must be above its minimum height")
+	}
+
+	geometries, err := b.Geometries(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	var geometry spatialmath.Geometry
+	if len(geometries) > 0 {
+		geometry = geometries[0]
+	} else {
+		logger.CWarnf(
+			ctx, "base %s not configured with a geometry, will be considered a 300mm sphere for collision detection purposes.",
+			b.Name().Name,
+		)
+		if geometry, err = spatialmath.NewSphere(spatialmath.NewZeroPose(), 150., b.Name().ShortName()); err != nil {
+			return nil, err
+		}
+	}
+
+	heightLimit := referenceframe.Limit{Min: options.Flight.MinHeightMM, Max: options.Flight.MaxHeightMM}
+	headingLimit := referenceframe.Limit{Min: -2 * math.Pi, Max: 2 * math.Pi}
+	if len(limits) > 2 {
+		headingLimit = limits[2]
+	}
+	fk := &flightKinematics{
+		Base:      b,
+		Localizer: localizer,
+		logger:    logger,
+		options:   options,
+	}
+	fk.localizationFrame, err = referenceframe.New3DMobileModelFrame(
+		b.Name().ShortName(), []referenceframe.Limit{limits[0], limits[1], heightLimit, headingLimit}, geometry,
+	)
+	if err != nil {
+		return nil, err
+	}
+	fk.planningFrame = fk.localizationFrame
+	if options.PositionOnlyMode {
+		// the heading of the base is not planned, so its geometry is approximated by the sphere it sweeps as it turns
+		boundingSphere, err := spatialmath.BoundingSphere(geometry)
+		if err != nil {
+			return nil, err
+		}
+		fk.planningFrame, err = referenceframe.New3DMobileModelFrame(
+			b.Name().ShortName(), []referenceframe.Limit{limits[0], limits[1], heightLimit}, boundingSphere,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return fk, nil
+}
+
+type flightKinematics struct {
+	base.Base
+	motion.Localizer
+	logger                           logging.Logger
+	planningFrame, localizationFrame referenceframe.Frame
+	options                          Options
+
+	mutex sync.RWMutex
+	// start is the pose the base was at when it was given its current trajectory
+	start             spatialmath.Pose
+	currentTrajectory [][]referenceframe.Input
+	currentIdx        int
+}
+
+func (fk *flightKinematics) Kinematics() referenceframe.Frame {
+	return fk.planningFrame
+}
+
+func (fk *flightKinematics) LocalizationFrame() referenceframe.Frame {
+	return fk.localizationFrame
+}
+
+// CurrentInputs returns the inputs of the planning frame of the base at its current position.
+func (fk *flightKinematics) CurrentInputs(ctx context.Context) ([]referenceframe.Input, error) {
+	pif, err := fk.CurrentPosition(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pt := pif.Pose().Point()
+	theta := math.Mod(pif.Pose().Orientation().EulerAngles().Yaw, 2*math.Pi)
+	inputs := []referenceframe.Input{{Value: pt.X}, {Value: pt.Y}, {Value: pt.Z}, {Value: theta}}
+	return inputs[:len(fk.planningFrame.DoF())], nil
+}
+
+func (fk *flightKinematics) GoToInputs(ctx context.Context, desiredSteps ...[]referenceframe.Input) error {
+	ctx, span := trace.StartSpan(ctx, "kinematicbase::flightKinematics::GoToInputs")
+	defer span.End()
+
+	current, err := fk.CurrentInputs(ctx)
+	if err != nil {
+		return err
+	}
+	// the base may have been flown outside of its limits, which does not keep it from flying back within them
+	start, err := fk.planningFrame.Transform(current)
+	if start == nil {
+		return err
+	}
+	fk.mutex.Lock()
+	fk.start = start
+	fk.currentTrajectory = desiredSteps
+	fk.mutex.Unlock()
+
+	for i, desired := range desiredSteps {
+		fk.mutex.Lock()
+		fk.currentIdx = i
+		fk.mutex.Unlock()
+		if err := fk.flyTo(ctx, current, desired); err != nil {
+			return multierr.Combine(err, fk.Base.Stop(context.Background(), nil))
+		}
+		current = desired
+	}
+	return fk.Base.Stop(ctx, nil)
+}
+
+// flyTo flies the base straight from the position of the inputs it started from to that of the desired inputs, turning it to their
+// heading if they have one, until it is within the goal radius and heading threshold of them.
+func (fk *flightKinematics) flyTo(ctx context.Context, from, desired []referenceframe.Input) error {
+	clk := fk.options.clock()
+	stepSeconds := fk.options.UpdateStepSeconds
+	startPoint := r3.Vector{X: from[0].Value, Y: from[1].Value, Z: from[2].Value}
+	goalPoint := r3.Vector{X: desired[0].Value, Y: desired[1].Value, Z: desired[2].Value}
+
+	lastMoved := clk.Now()
+	var lastPoint *r3.Vector
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		pif, err := fk.CurrentPosition(ctx)
+		if err != nil {
+			return err
+		}
+		pose := pif.Pose()
+		goal := spatialmath.NewPose(goalPoint, pose.Orientation())
+		if len(desired) > 3 {
+			goal = spatialmath.NewPose(goalPoint, &spatialmath.OrientationVector{OZ: 1, Theta: desired[3].Value})
+		}
+		linear, angular, done := fk.velocities(pose, goal)
+		if done {
+			return nil
+		}
+		if spatialmath.DistToLineSegment(startPoint, goalPoint, pose.Point()) > fk.options.PlanDeviationThresholdMM {
+			return errors.New("base has deviated too far from path")
+		}
+
+		if lastPoint == nil || lastPoint.Distance(pose.Point()) > fk.options.MinimumMovementThresholdMM {
+			point := pose.Point()
+			lastPoint = &point
+			lastMoved = clk.Now()
+		} else if clk.Since(lastMoved) > fk.options.Timeout {
+			return errMovementTimeout
+		}
+
+		if drift := fk.options.Drift; drift != nil && drift.Drift != nil {
+			if velocity, err := drift.Drift(ctx); err == nil {
+				// flying bases may be commanded sideways, so are never crabbed into the drift
+				sideways := *drift
+				sideways.Crab = false
+				linear, angular, _ = sideways.compensate(linear, angular, velocity, 0, stepSeconds)
+			} else {
+				fk.logger.CDebugf(ctx, "could not get drift to compensate for, ignoring it: %v", err)
+			}
+		}
+		if err := fk.Base.SetVelocity(ctx, linear, angular, nil); err != nil {
+			return err
+		}
+		if !selectContextOrWait(ctx, clk, scaledDuration(stepSeconds, 1)) {
+			return ctx.Err()
+		}
+	}
+}
+
+// velocities returns the linear and angular velocities, in the frame of the base at the given pose, with which it flies towards the
+// goal, slowing as it nears it so as not to overshoot it within an update step. It returns true instead if the base has reached the
+// goal.
+func (fk *flightKinematics) velocities(pose, goal spatialmath.Pose) (r3.Vector, r3.Vector, bool) {
+	delta := spatialmath.PoseBetween(pose, goal)
+	distance := delta.Point().Norm()
+	headingErr := math.Mod(delta.Orientation().OrientationVectorDegrees().Theta, 360)
+	if headingErr > 180 {
+		headingErr -= 360
+	} else if headingErr < -180 {
+		headingErr += 360
+	}
+	if distance <= fk.options.GoalRadiusMM && math.Abs(headingErr) <= fk.options.HeadingThresholdDegrees {
+		return r3.Vector{}, r3.Vector{}, true
+	}
+
+	stepSeconds := fk.options.UpdateStepSeconds
+	var linear r3.Vector
+	if distance > fk.options.GoalRadiusMM {
+		linear = delta.Point().Normalize().Mul(math.Min(fk.options.LinearVelocityMMPerSec, distance/stepSeconds))
+	}
+	var angular r3.Vector
+	if math.Abs(headingErr) > fk.options.HeadingThresholdDegrees {
+		maxDegsPerSec := fk.options.AngularVelocityDegsPerSec
+		angular.Z = math.Max(-maxDegsPerSec, math.Min(maxDegsPerSec, headingErr/stepSeconds))
+	}
+	return linear, angular, false
+}
+
+// ExecutionState returns the state of the base flying its trajectory. The inputs of each step of the plan it returns describe its
+// motion relative to the end of the prior step, as with other kinematic bases, and the first step is where it started.
+func (fk *flightKinematics) ExecutionState(ctx context.Context) (motionplan.ExecutionState, error) {
+	actualPIF, err := fk.CurrentPosition(ctx)
+	if err != nil {
+		return motionplan.ExecutionState{}, err
+	}
+	current, err := fk.CurrentInputs(ctx)
+	if err != nil {
+		return motionplan.ExecutionState{}, err
+	}
+
+	fk.mutex.RLock()
+	start, trajectory, currentIdx := fk.start, fk.currentTrajectory, fk.currentIdx
+	fk.mutex.RUnlock()
+
+	name := fk.planningFrame.Name()
+	var path motionplan.Path
+	var traj motionplan.Trajectory
+	if len(trajectory) > 0 {
+		path = append(path, referenceframe.FrameSystemPoses{name: referenceframe.NewPoseInFrame(actualPIF.Parent(), start)})
+		traj = append(traj, referenceframe.FrameSystemInputs{name: make([]referenceframe.Input, len(fk.planningFrame.DoF()))})
+		last := start
+		for _, step := range trajectory {
+			pose, err := fk.planningFrame.Transform(step)
+			if err != nil {
+				return motionplan.ExecutionState{}, err
+			}
+			path = append(path, referenceframe.FrameSystemPoses{name: referenceframe.NewPoseInFrame(actualPIF.Parent(), pose)})
+			traj = append(traj, referenceframe.FrameSystemInputs{name: relativeFlightInputs(last, pose, len(step))})
+			last = pose
+		}
+	}
+
+	return motionplan.NewExecutionState(
+		motionplan.NewSimplePlan(path, traj),
+		currentIdx+1,
+		referenceframe.FrameSystemInputs{name: current},
+		map[string]*referenceframe.PoseInFrame{fk.localizationFrame.Name(): actualPIF},
+	)
+}
+
+// relativeFlightInputs returns the inputs which move a flying base from one pose to another.
+func relativeFlightInputs(from, to spatialmath.Pose, dof int) []referenceframe.Input {
+	delta := spatialmath.PoseBetween(from, to)
+	pt := delta.Point()
+	inputs := []referenceframe.Input{{Value: pt.X}, {Value: pt.Y}, {Value: pt.Z}, {Value: delta.Orientation().EulerAngles().Yaw}}
+	return inputs[:dof]
+}
@@ -0,0 +1,148 @@
+package kinematicbase
+
+import (
+	"context"
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+// testDrone is a base which flies at the velocities it is set to, advancing by one update step each time its position is read.
+type testDrone struct {
+	*inject.Base
+	mu               sync.Mutex
+	pose             spatialmath.Pose
+	linear, angular  r3.Vector
+	stepSeconds      float64
+	velocitiesIssued int
+}
+
+func newTestDrone(stepSeconds float64) *testDrone {
+	drone := &testDrone{Base: inject.NewBase("drone"), pose: spatialmath.NewZeroPose(), stepSeconds: stepSeconds}
+	drone.SetVelocityFunc = func(ctx context.Context, linear, angular r3.Vector, extra map[string]interface{}) error {
+		drone.mu.Lock()
+		defer drone.mu.Unlock()
+		drone.linear, drone.angular = linear, angular
+		drone.velocitiesIssued++
+		return nil
+	}
+	drone.StopFunc = func(ctx context.Context, extra map[string]interface{}) error {
+		drone.mu.Lock()
+		defer drone.mu.Unlock()
+		drone.linear, drone.angular = r3.Vector{}, r3.Vector{}
+		return nil
+	}
+	drone.GeometriesFunc = func(ctx context.Context) ([]spatialmath.Geometry, error) {
+		return nil, nil
+	}
+	return drone
+}
+
+func (d *testDrone) CurrentPosition(ctx context.Context) (*referenceframe.PoseInFrame, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.pose = spatialmath.Compose(d.pose, spatialmath.NewPose(
+		d.linear.Mul(d.stepSeconds),
+		&spatialmath.OrientationVectorDegrees{OZ: 1, Theta: d.angular.Z * d.stepSeconds},
+	))
+	return referenceframe.NewPoseInFrame(referenceframe.World, d.pose), nil
+}
+
+func (d *testDrone) Confidence(ctx context.Context) (motion.LocalizerConfidence, error) {
+	return motion.LocalizerConfidence{}, nil
+}
+
+func TestFlightKinematics(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	limits := []referenceframe.Limit{{Min: -5000, Max: 5000}, {Min: -5000, Max: 5000}, {Min: -2 * math.Pi, Max: 2 * math.Pi}}
+	options := NewKinematicBaseOptions()
+	options.Flight = NewFlightOptions()
+	options.UpdateStepSeconds = 0.01
+	options.LinearVelocityMMPerSec = 10000
+	options.AngularVelocityDegsPerSec = 3600
+	options.PositionOnlyMode = false
+
+	t.Run("wrapping", func(t *testing.T) {
+		drone := newTestDrone(options.UpdateStepSeconds)
+		_, err := WrapWithKinematics(ctx, drone, logger, nil, limits, options)
+		test.That(t, err, test.ShouldNotBeNil)
+
+		kb, err := WrapWithKinematics(ctx, drone, logger, drone, limits, options)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, kb.Kinematics().DoF(), test.ShouldHaveLength, 4)
+		test.That(t, kb.Kinematics().DoF()[2], test.ShouldResemble, referenceframe.Limit{Min: 0, Max: defaultMaxFlightHeightMM})
+
+		positionOnly := options
+		positionOnly.PositionOnlyMode = true
+		kb, err = WrapWithKinematics(ctx, drone, logger, drone, limits, positionOnly)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, kb.Kinematics().DoF(), test.ShouldHaveLength, 3)
+		test.That(t, kb.LocalizationFrame().DoF(), test.ShouldHaveLength, 4)
+		inputs, err := kb.CurrentInputs(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, inputs, test.ShouldHaveLength, 3)
+
+		grounded := options
+		grounded.Flight = &FlightOptions{MinHeightMM: 100, MaxHeightMM: 100}
+		_, err = WrapWithKinematics(ctx, drone, logger, drone, limits, grounded)
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("slowing near the goal", func(t *testing.T) {
+		fk := &flightKinematics{options: options}
+		linear, _, done := fk.velocities(spatialmath.NewZeroPose(), spatialmath.NewPoseFromPoint(r3.Vector{Z: 50000}))
+		test.That(t, done, test.ShouldBeFalse)
+		test.That(t, linear.Z, test.ShouldAlmostEqual, options.LinearVelocityMMPerSec)
+		// the base slows once it would otherwise pass the goal within an update step
+		fast := options
+		fast.LinearVelocityMMPerSec = 100000
+		fk = &flightKinematics{options: fast}
+		linear, _, done = fk.velocities(spatialmath.NewZeroPose(), spatialmath.NewPoseFromPoint(r3.Vector{Z: 400}))
+		test.That(t, done, test.ShouldBeFalse)
+		test.That(t, linear.Z, test.ShouldAlmostEqual, 400/options.UpdateStepSeconds)
+		_, _, done = fk.velocities(spatialmath.NewZeroPose(), spatialmath.NewPoseFromPoint(r3.Vector{Z: 100}))
+		test.That(t, done, test.ShouldBeTrue)
+	})
+
+	t.Run("flying a plan over an obstacle", func(t *testing.T) {
+		drone := newTestDrone(options.UpdateStepSeconds)
+		kb, err := WrapWithKinematics(ctx, drone, logger, drone, limits, options)
+		test.That(t, err, test.ShouldBeNil)
+		steps := [][]referenceframe.Input{
+			referenceframe.FloatsToInputs([]float64{0, 0, 2000, 0}),
+			referenceframe.FloatsToInputs([]float64{3000, 0, 2000, math.Pi / 2}),
+			referenceframe.FloatsToInputs([]float64{3000, 0, 0, math.Pi / 2}),
+		}
+		test.That(t, kb.GoToInputs(ctx, steps...), test.ShouldBeNil)
+		test.That(t, drone.velocitiesIssued, test.ShouldBeGreaterThan, 0)
+		pif, err := drone.CurrentPosition(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, pif.Pose().Point().Distance(r3.Vector{X: 3000}), test.ShouldBeLessThanOrEqualTo, options.GoalRadiusMM)
+		heading := pif.Pose().Orientation().OrientationVectorDegrees().Theta
+		test.That(t, math.Abs(heading-90), test.ShouldBeLessThanOrEqualTo, options.HeadingThresholdDegrees)
+
+		// the plan of the execution state starts where the base did, with each step relative to the last
+		state, err := kb.ExecutionState(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		path := state.Plan().Path()
+		test.That(t, path, test.ShouldHaveLength, 4)
+		test.That(t, spatialmath.R3VectorAlmostEqual(path[2][kb.Name().ShortName()].Pose().Point(), r3.Vector{X: 3000, Z: 2000}, 1e-6),
+			test.ShouldBeTrue)
+		relative := state.Plan().Trajectory()[3][kb.Name().ShortName()]
+		test.That(t, relative[2].Value, test.ShouldAlmostEqual, -2000)
+		errorState, err := motionplan.CalculateFrameErrorStateFromPath(state, kb.Kinematics(), kb.LocalizationFrame())
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, errorState.Point().Norm(), test.ShouldBeLessThanOrEqualTo, options.GoalRadiusMM)
+	})
+}
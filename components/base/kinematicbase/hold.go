@@ -0,0 +1,43 @@
+//go:build !no_cgo
+
+package kinematicbase
+
+import (
+	"context"
+	"time"
+)
+
+// HoldOptions lets the driving of the arcs of a plan be held, such as while waiting for an obstacle to clear. While held, the base is
+// stopped in place and progress through the plan is paused; once released, the base resumes driving the arc it was on.
+type HoldOptions struct {
+	// Held returns whether the base should currently be holding. It may be set after the base is wrapped, but before GoToInputs is
+	// called.
+	Held func(ctx context.Context) bool
+}
+
+// held returns whether the driving of the plan is currently held.
+func (ptgk *ptgBaseKinematics) held(ctx context.Context) bool {
+	opts := ptgk.opts.Hold
+	return opts != nil && opts.Held != nil && opts.Held(ctx)
+}
+
+// hold stops the base until it is no longer held, then resumes driving the given step at the given scale. It returns how long the
+// base was held for, which the caller should not count as progress through the step.
+func (ptgk *ptgBaseKinematics) hold(ctx context.Context, step arcStep, scale float64) (time.Duration, error) {
+	holdStart := ptgk.clock.Now()
+	if err := ptgk.Base.Stop(ctx, nil); err != nil {
+		return 0, err
+	}
+	ptgk.logger.Debug("holding the base in place")
+	updateDuration := scaledDuration(ptgk.opts.UpdateStepSeconds, 1)
+	for ptgk.held(ctx) {
+		if !selectContextOrWait(ctx, ptgk.clock, updateDuration) {
+			return 0, ctx.Err()
+		}
+	}
+	ptgk.logger.Debug("released the base, resuming the plan")
+	if err := ptgk.Base.SetVelocity(ctx, step.linVelMMps.Mul(scale), step.angVelDegps.Mul(scale), nil); err != nil {
+		return 0, err
+	}
+	return ptgk.clock.Since(holdStart), nil
+}
@@ -0,0 +1,60 @@
+package kinematicbase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestHold(t *testing.T) {
+	ctx := context.Background()
+	b := inject.NewBase("base")
+	stops := 0
+	b.StopFunc = func(ctx context.Context, extra map[string]interface{}) error {
+		stops++
+		return nil
+	}
+	var linear, angular r3.Vector
+	b.SetVelocityFunc = func(ctx context.Context, lin, ang r3.Vector, extra map[string]interface{}) error {
+		linear, angular = lin, ang
+		return nil
+	}
+	opts := NewKinematicBaseOptions()
+	opts.UpdateStepSeconds = 0.01
+	ptgk := &ptgBaseKinematics{Base: b, logger: logging.NewTestLogger(t), opts: opts, clock: clock.New()}
+
+	test.That(t, ptgk.held(ctx), test.ShouldBeFalse)
+	ptgk.opts.Hold = &HoldOptions{}
+	test.That(t, ptgk.held(ctx), test.ShouldBeFalse)
+
+	polls := 0
+	ptgk.opts.Hold.Held = func(context.Context) bool {
+		polls++
+		return polls <= 3
+	}
+	test.That(t, ptgk.held(ctx), test.ShouldBeTrue)
+
+	step := arcStep{linVelMMps: r3.Vector{Y: 400}, angVelDegps: r3.Vector{Z: 20}}
+	heldFor, err := ptgk.hold(ctx, step, 0.5)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, stops, test.ShouldEqual, 1)
+	test.That(t, heldFor, test.ShouldBeGreaterThanOrEqualTo, 20*time.Millisecond)
+	// the base resumes the step at the speed it was driving it
+	test.That(t, linear, test.ShouldResemble, r3.Vector{Y: 200})
+	test.That(t, angular, test.ShouldResemble, r3.Vector{Z: 10})
+
+	t.Run("holding stops when the context is cancelled", func(t *testing.T) {
+		ptgk.opts.Hold.Held = func(context.Context) bool { return true }
+		cancelCtx, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err := ptgk.hold(cancelCtx, step, 1)
+		test.That(t, err, test.ShouldBeError, context.Canceled)
+	})
+}
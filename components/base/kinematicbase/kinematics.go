@@ -9,12 +9,18 @@ import (
 	"errors"
 	"time"
 
+	"github.com/benbjohnson/clock"
+	"github.com/golang/geo/r2"
+	"go.viam.com/utils"
+
 	"go.viam.com/rdk/components/base"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/motionplan/tpspace"
 	"go.viam.com/rdk/referenceframe"
 	"go.viam.com/rdk/robot/framesystem"
 	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/spatialmath"
 )
 
 // KinematicBase is an interface for Bases that also satisfy the ModelFramer and InputEnabled interfaces.
@@ -76,6 +82,10 @@ const (
 
 	// Update CurrentInputs (and check deviation if supported) every this many seconds.
 	defaultUpdateStepSeconds = 0.35
+
+	// defaultOdometryDriftPerMeterMM is how much the position error of odometry is expected to grow for each meter driven, matching
+	// the default of wheeled odometry movement sensors.
+	defaultOdometryDriftPerMeterMM = 20.
 )
 
 // Options contains values used for execution of base movement.
@@ -124,6 +134,58 @@ type Options struct {
 
 	// Update CurrentInputs (and check deviation if supported) every this many seconds.
 	UpdateStepSeconds float64
+
+	// LocalPlanner, if set, follows plans with PTG kinematics using a local planner which avoids obstacles, rather than driving
+	// their arcs and course correcting. Only used if the base has a localizer.
+	LocalPlanner *LocalPlannerOptions
+
+	// SpeedScaling, if set, slows bases with PTG kinematics as they near obstacles while driving the arcs of a plan. Only used if the
+	// base has a localizer.
+	SpeedScaling *SpeedScalingOptions
+
+	// SafetyZones, if set, returns the regions which bases with PTG kinematics drive through no faster than each allows, or stop in,
+	// while driving the arcs of a plan. Only used if the base has a localizer.
+	SafetyZones SafetyZoneSource
+
+	// Drift, if set, compensates bases with PTG kinematics for the drift of the medium they move through, such as wind or water,
+	// while driving the arcs of a plan.
+	Drift *DriftOptions
+
+	// Hold, if set, lets bases with PTG kinematics be stopped in place while driving the arcs of a plan, and later resume them.
+	Hold *HoldOptions
+
+	// OdometryDriftPerMeterMM is how much the position error of a base localized by odometry alone, with NewOdometryLocalizer, is
+	// expected to grow for each meter it drives. Wheel slip and uneven ground make odometry drift without bound, so moves localized
+	// by it should be kept short; the default of 20mm per meter leaves a base about 40mm off after a 2m move.
+	OdometryDriftPerMeterMM float64
+
+	// MaxOdometryDriftMM, if positive, bounds the expected position error of a base localized by odometry alone. Once its drift is
+	// expected to exceed it, the localizer errors rather than letting the base follow a plan from a position it cannot trust.
+	MaxOdometryDriftMM float64
+
+	// Footprint, if set, is the outline of the base in the XY plane of its frame, which need not be convex, such as that of a forklift
+	// with its tines or of a base towing a trailer. Extruded by FootprintHeightMM, it is checked for collisions in place of the
+	// geometries configured for the base.
+	Footprint []r2.Point
+
+	// FootprintHeightMM is the height of the base, centered on the XY plane of its frame, over which its Footprint is extruded.
+	FootprintHeightMM float64
+
+	// Flight, if set, wraps the base with the kinematics of a base which flies, such as a drone, in place of those of a base on the
+	// ground. The base must have a localizer.
+	Flight *FlightOptions
+
+	// Trailer, if set, is a trailer towed by a base with PTG kinematics, which is then wrapped as an ArticulatedKinematicBase. The
+	// base must have a localizer. Planning with it also requires the HitchConstraint of the trailer.
+	Trailer *TrailerOptions
+
+	// PTGLibrary, if set, is the set of PTGs a base with PTG kinematics plans with, in place of the default set for its turning radius.
+	// None of its PTGs may turn more tightly than the base can.
+	PTGLibrary *tpspace.PTGLibrary
+
+	// Clock times the execution of plans by PTG kinematics. If nil, the wall clock is used. Simulations use a mock clock so that how
+	// far a base drives does not depend on how fast the machine running them is.
+	Clock clock.Clock
 }
 
 // NewKinematicBaseOptions creates a struct with values used for execution of base movement.
@@ -143,10 +205,34 @@ func NewKinematicBaseOptions() Options {
 		UsePTGs:                    defaultUsePTGs,
 		NoSkidSteer:                defaultNoSkidSteer,
 		UpdateStepSeconds:          defaultUpdateStepSeconds,
+		OdometryDriftPerMeterMM:    defaultOdometryDriftPerMeterMM,
 	}
 	return options
 }
 
+// footprint returns the footprint of the base as a geometry, or nil if it has none.
+func (options Options) footprint(name string) (spatialmath.Geometry, error) {
+	if len(options.Footprint) == 0 {
+		return nil, nil
+	}
+	return spatialmath.NewPolygon(spatialmath.NewZeroPose(), options.Footprint, options.FootprintHeightMM, name)
+}
+
+// clock returns the clock which times the execution of plans, defaulting to the wall clock.
+func (options Options) clock() clock.Clock {
+	if options.Clock == nil {
+		return clock.New()
+	}
+	return options.Clock
+}
+
+// selectContextOrWait is utils.SelectContextOrWait, waiting on the given clock.
+func selectContextOrWait(ctx context.Context, clk clock.Clock, dur time.Duration) bool {
+	timer := clk.Timer(dur)
+	defer timer.Stop()
+	return utils.SelectContextOrWaitChan(ctx, timer.C)
+}
+
 // WrapWithKinematics will wrap a Base with the appropriate type of kinematics, allowing it to provide a Frame which can be planned with
 // and making it InputEnabled.
 func WrapWithKinematics(
@@ -161,6 +247,10 @@ func WrapWithKinematics(
 		return kb, nil
 	}
 
+	if options.Flight != nil {
+		return wrapWithFlightKinematics(ctx, b, logger, localizer, limits, options)
+	}
+
 	properties, err := b.Properties(ctx, nil)
 	if err != nil {
 		return nil, err
@@ -172,5 +262,8 @@ func WrapWithKinematics(
 		}
 		return nil, errors.New("must use PTGs with nonzero turning radius")
 	}
+	if options.Trailer != nil {
+		return wrapWithTrailer(ctx, b, logger, localizer, options)
+	}
 	return wrapWithPTGKinematics(ctx, b, logger, localizer, options)
 }
@@ -0,0 +1,421 @@
+//go:build !no_cgo
+
+package kinematicbase
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/golang/geo/r3"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+	rdkutils "go.viam.com/rdk/utils"
+)
+
+const (
+	defaultLocalPlannerRateHz            = 15.
+	defaultLocalPlannerHorizonSeconds    = 1.5
+	defaultLocalPlannerLinearAccel       = 400. // mm/s^2
+	defaultLocalPlannerAngularAccel      = 180. // deg/s^2
+	defaultLocalPlannerLookaheadMM       = 600.
+	defaultLocalPlannerClearanceMM       = 300.
+	defaultLocalPlannerGoalToleranceMM   = 50.
+	defaultLocalPlannerBlockedTimeout    = 2 * time.Second
+	localPlannerLinearSamples            = 7
+	localPlannerAngularSamples           = 15
+	localPlannerSimStepSeconds           = 0.1
+	localPlannerPathSpacingMM            = 50. // The global plan is followed through points spaced this far apart.
+	localPlannerHeadingWeightMMPerRadian = 100.
+	localPlannerDeviationWeight          = 0.5
+	localPlannerClearanceWeight          = 0.5
+	localPlannerSightStepMM              = 100. // The line of sight to the target is checked at points spaced this far apart.
+)
+
+// ErrLocalPlanBlocked is returned by GoToInputs when the local planner cannot find a velocity which makes progress along the global
+// plan without hitting an obstacle, so that a new global plan is needed.
+var ErrLocalPlanBlocked = errors.New("local planner is blocked by obstacles, the global plan must be replanned")
+
+// ObstacleSource returns the obstacles around a base, in the frame of its localizer.
+type ObstacleSource func(ctx context.Context) ([]spatialmath.Geometry, error)
+
+// LocalPlannerOptions configures the local planner of a base, which follows the global plan given to GoToInputs while reactively
+// avoiding obstacles using the dynamic window approach. The local planner requires a localizer and PTG kinematics.
+type LocalPlannerOptions struct {
+	// Obstacles returns the obstacles to avoid. It may be set after the base is wrapped, but before GoToInputs is called.
+	Obstacles ObstacleSource
+
+	// RateHz is how often the local planner chooses a new velocity.
+	RateHz float64
+
+	// HorizonSeconds is how far ahead each candidate velocity is simulated when checking it for collisions.
+	HorizonSeconds float64
+
+	// LinearAccelMMPerSec2 limits how quickly the linear velocity of the base may change between choices.
+	LinearAccelMMPerSec2 float64
+
+	// AngularAccelDegsPerSec2 limits how quickly the angular velocity of the base may change between choices.
+	AngularAccelDegsPerSec2 float64
+
+	// LookaheadMM is how far along the global plan the point the base drives towards is.
+	LookaheadMM float64
+
+	// ClearanceMM is the distance from obstacles beyond which more clearance is not preferred.
+	ClearanceMM float64
+
+	// GoalToleranceMM is how close to the end of the global plan the base must get to have followed it.
+	GoalToleranceMM float64
+
+	// BlockedTimeout is how long the base waits for a blocked path to clear before GoToInputs returns ErrLocalPlanBlocked.
+	BlockedTimeout time.Duration
+}
+
+// NewLocalPlannerOptions creates a struct with values used by the local planner to avoid the given obstacles.
+// all values are pre-set to reasonable default values and can be changed if desired.
+func NewLocalPlannerOptions(obstacles ObstacleSource) *LocalPlannerOptions {
+	return &LocalPlannerOptions{
+		Obstacles:               obstacles,
+		RateHz:                  defaultLocalPlannerRateHz,
+		HorizonSeconds:          defaultLocalPlannerHorizonSeconds,
+		LinearAccelMMPerSec2:    defaultLocalPlannerLinearAccel,
+		AngularAccelDegsPerSec2: defaultLocalPlannerAngularAccel,
+		LookaheadMM:             defaultLocalPlannerLookaheadMM,
+		ClearanceMM:             defaultLocalPlannerClearanceMM,
+		GoalToleranceMM:         defaultLocalPlannerGoalToleranceMM,
+		BlockedTimeout:          defaultLocalPlannerBlockedTimeout,
+	}
+}
+
+// pathPoint is a point along the global plan, along with the inputs which reach it from the start of its arc.
+type pathPoint struct {
+	pose    spatialmath.Pose
+	distMM  float64
+	stepIdx int
+	inputs  []referenceframe.Input
+}
+
+// pose2D is the position and heading of a base in the plane, where a heading of zero drives along the x axis.
+type pose2D struct {
+	x, y, heading float64
+}
+
+func newPose2D(pose spatialmath.Pose) pose2D {
+	// bases drive along their y axis
+	forward := spatialmath.Compose(pose, spatialmath.NewPoseFromPoint(r3.Vector{Y: 1})).Point().Sub(pose.Point())
+	return pose2D{x: pose.Point().X, y: pose.Point().Y, heading: math.Atan2(forward.Y, forward.X)}
+}
+
+// angleBetween returns the counterclockwise angle in radians, between -pi and pi, from one heading to another.
+func angleBetween(from, to float64) float64 {
+	return math.Remainder(to-from, 2*math.Pi)
+}
+
+func (p pose2D) toPose(z float64) spatialmath.Pose {
+	return spatialmath.NewPose(r3.Vector{X: p.x, Y: p.y, Z: z}, &spatialmath.OrientationVector{OZ: 1, Theta: p.heading - math.Pi/2})
+}
+
+// localPlanner chooses the velocities which follow a global plan while avoiding obstacles.
+type localPlanner struct {
+	opts LocalPlannerOptions
+
+	maxLinVelMMps   float64
+	maxAngVelDegps  float64
+	minTurnRadiusMM float64 // zero if the base can spin in place
+	maxDeviationMM  float64
+	geometries      []spatialmath.Geometry
+
+	path     []pathPoint
+	progress int
+
+	// the most recently chosen velocities
+	linVelMMps  float64
+	angVelDegps float64
+}
+
+func (ptgk *ptgBaseKinematics) newLocalPlanner(arcSteps []arcStep) (*localPlanner, error) {
+	lp := &localPlanner{
+		opts:            *ptgk.opts.LocalPlanner,
+		maxLinVelMMps:   ptgk.linVelocityMMPerSecond,
+		maxAngVelDegps:  ptgk.angVelocityDegsPerSecond,
+		minTurnRadiusMM: ptgk.baseTurningRadiusMeters * 1000,
+		maxDeviationMM:  ptgk.opts.PlanDeviationThresholdMM,
+		geometries:      ptgk.geometries,
+	}
+	for i, step := range arcSteps {
+		for j, trajPt := range step.subTraj {
+			inputs := []referenceframe.Input{
+				step.arcSegment.StartConfiguration[ptgIndex],
+				step.arcSegment.StartConfiguration[trajectoryAlphaWithinPTG],
+				step.arcSegment.StartConfiguration[startDistanceAlongTrajectoryIndex],
+				{Value: trajPt.Dist},
+			}
+			arcPose, err := ptgk.Kinematics().Transform(inputs)
+			if err != nil {
+				return nil, err
+			}
+			pose := spatialmath.Compose(step.arcSegment.StartPosition, arcPose)
+			distMM := 0.
+			if len(lp.path) > 0 {
+				last := lp.path[len(lp.path)-1]
+				distMM = last.distMM + pose.Point().Distance(last.pose.Point())
+				// keep the last point of the plan so that the base drives all the way to the end
+				isLast := i == len(arcSteps)-1 && j == len(step.subTraj)-1
+				if distMM-last.distMM < localPlannerPathSpacingMM && !isLast {
+					continue
+				}
+			}
+			lp.path = append(lp.path, pathPoint{pose: pose, distMM: distMM, stepIdx: i, inputs: inputs})
+		}
+	}
+	if len(lp.path) == 0 {
+		return nil, errors.New("cannot follow an empty plan with the local planner")
+	}
+	return lp, nil
+}
+
+// updateProgress advances the point along the global plan which the base has reached to the one nearest to the given pose, looking
+// no further ahead than the lookahead distance.
+func (lp *localPlanner) updateProgress(pose spatialmath.Pose) {
+	best := lp.progress
+	bestDist := pose.Point().Distance(lp.path[best].pose.Point())
+	maxDistMM := lp.path[lp.progress].distMM + lp.opts.LookaheadMM
+	for i := lp.progress + 1; i < len(lp.path) && lp.path[i].distMM <= maxDistMM; i++ {
+		if dist := pose.Point().Distance(lp.path[i].pose.Point()); dist < bestDist {
+			best = i
+			bestDist = dist
+		}
+	}
+	lp.progress = best
+}
+
+// target returns the index of the point along the global plan which the base drives towards.
+func (lp *localPlanner) target() int {
+	targetDistMM := lp.path[lp.progress].distMM + lp.opts.LookaheadMM
+	for i := lp.progress; i < len(lp.path); i++ {
+		if lp.path[i].distMM >= targetDistMM {
+			return i
+		}
+	}
+	return len(lp.path) - 1
+}
+
+// reached returns whether the base at the given pose has followed the global plan to its end.
+func (lp *localPlanner) reached(pose spatialmath.Pose) bool {
+	end := lp.path[len(lp.path)-1].pose
+	return lp.target() == len(lp.path)-1 && pose.Point().Distance(end.Point()) <= lp.opts.GoalToleranceMM
+}
+
+// deviation returns the distance from the given point to the nearest point of the global plan ahead of the base.
+func (lp *localPlanner) deviation(pt r3.Vector) float64 {
+	deviation := math.Inf(1)
+	maxDistMM := lp.path[lp.progress].distMM + lp.opts.LookaheadMM + lp.maxLinVelMMps*lp.opts.HorizonSeconds
+	for i := lp.progress; i < len(lp.path) && lp.path[i].distMM <= maxDistMM; i++ {
+		deviation = math.Min(deviation, pt.Distance(lp.path[i].pose.Point()))
+	}
+	return deviation
+}
+
+// clearance returns the distance from the base at the given pose to the nearest obstacle, which is negative if they collide.
+func (lp *localPlanner) clearance(pose spatialmath.Pose, obstacles []spatialmath.Geometry) float64 {
+	return clearance(lp.geometries, pose, obstacles, lp.opts.ClearanceMM)
+}
+
+// clearance returns the distance from the geometries of a base at the given pose to the nearest obstacle, which is negative if they
+// collide. Obstacles which cannot measure their distance from the base, such as pointclouds, are taken to be exactly farFromMM away
+// unless they are closer.
+func clearance(geometries []spatialmath.Geometry, pose spatialmath.Pose, obstacles []spatialmath.Geometry, farFromMM float64) float64 {
+	nearest := math.Inf(1)
+	for _, geometry := range geometries {
+		placed := geometry.Transform(pose)
+		for _, obstacle := range obstacles {
+			dist, err := placed.DistanceFrom(obstacle)
+			if err != nil {
+				// not every geometry, such as a pointcloud, can measure its distance from others
+				collides, err := placed.CollidesWith(obstacle, farFromMM)
+				if err != nil || collides {
+					dist = -1
+				} else {
+					dist = farFromMM
+				}
+			}
+			nearest = math.Min(nearest, dist)
+		}
+	}
+	return nearest
+}
+
+// choose returns the linear and angular velocities, in mm/s and deg/s, which best follow the global plan from the given pose without
+// hitting any of the obstacles, choosing among those the base can reach before the next choice. It returns false if every velocity
+// would hit an obstacle or stray too far from the plan.
+func (lp *localPlanner) choose(pose spatialmath.Pose, obstacles []spatialmath.Geometry) (float64, float64, bool) {
+	dt := 1 / lp.opts.RateHz
+	start := newPose2D(pose)
+	targetIdx := lp.target()
+	target := lp.steerTarget(start, targetIdx, obstacles)
+	finalTarget := targetIdx == len(lp.path)-1
+
+	minLin := math.Max(0, lp.linVelMMps-lp.opts.LinearAccelMMPerSec2*dt)
+	maxLin := math.Min(lp.maxLinVelMMps, lp.linVelMMps+lp.opts.LinearAccelMMPerSec2*dt)
+	minAng := math.Max(-lp.maxAngVelDegps, lp.angVelDegps-lp.opts.AngularAccelDegsPerSec2*dt)
+	maxAng := math.Min(lp.maxAngVelDegps, lp.angVelDegps+lp.opts.AngularAccelDegsPerSec2*dt)
+
+	bestCost := math.Inf(1)
+	var bestLin, bestAng float64
+	for i := 0; i < localPlannerLinearSamples; i++ {
+		linVel := minLin + (maxLin-minLin)*float64(i)/(localPlannerLinearSamples-1)
+		for j := 0; j < localPlannerAngularSamples; j++ {
+			angVel := minAng + (maxAng-minAng)*float64(j)/(localPlannerAngularSamples-1)
+			// bases which cannot spin in place cannot turn more tightly than their turning radius
+			if lp.minTurnRadiusMM > 0 && math.Abs(rdkutils.DegToRad(angVel))*lp.minTurnRadiusMM > linVel+1e-6 {
+				continue
+			}
+			end, clearance := lp.simulate(start, pose.Point().Z, linVel, angVel, obstacles)
+			if clearance < 0 {
+				continue
+			}
+			endPt := r3.Vector{X: end.x, Y: end.y, Z: pose.Point().Z}
+			deviation := lp.deviation(endPt)
+			if deviation > lp.maxDeviationMM {
+				continue
+			}
+			cost := endPt.Distance(target) + localPlannerDeviationWeight*deviation +
+				localPlannerClearanceWeight*(lp.opts.ClearanceMM-math.Min(clearance, lp.opts.ClearanceMM))
+			if !finalTarget || endPt.Distance(target) > lp.opts.GoalToleranceMM {
+				bearing := math.Atan2(target.Y-end.y, target.X-end.x)
+				cost += localPlannerHeadingWeightMMPerRadian * math.Abs(angleBetween(end.heading, bearing))
+			}
+			if cost < bestCost {
+				bestCost = cost
+				bestLin, bestAng = linVel, angVel
+			}
+		}
+	}
+	if math.IsInf(bestCost, 1) {
+		lp.linVelMMps, lp.angVelDegps = 0, 0
+		return 0, 0, false
+	}
+	lp.linVelMMps, lp.angVelDegps = bestLin, bestAng
+	return bestLin, bestAng, true
+}
+
+// steerTarget returns the point the base at the given pose drives towards to reach the given point of the global plan. If obstacles
+// block the way straight to that point, the base drives towards the nearest point beside it with a clear way instead, so that it
+// steers around the obstacles rather than waiting in front of them.
+func (lp *localPlanner) steerTarget(start pose2D, targetIdx int, obstacles []spatialmath.Geometry) r3.Vector {
+	target := lp.path[targetIdx].pose.Point()
+	if len(obstacles) == 0 || lp.sightClearance(start, target, obstacles) >= 0 {
+		return target
+	}
+	direction := target.Sub(r3.Vector{X: start.x, Y: start.y, Z: target.Z})
+	if targetIdx > 0 {
+		direction = target.Sub(lp.path[targetIdx-1].pose.Point())
+	}
+	beside := r3.Vector{X: -direction.Y, Y: direction.X}.Normalize()
+	for offsetMM := localPlannerSightStepMM; offsetMM <= lp.maxDeviationMM; offsetMM += localPlannerSightStepMM {
+		for _, side := range []float64{1, -1} {
+			candidate := target.Add(beside.Mul(side * offsetMM))
+			if lp.sightClearance(start, candidate, obstacles) >= 0 {
+				return candidate
+			}
+		}
+	}
+	return target
+}
+
+// sightClearance returns the least clearance from the obstacles of the base driving straight from the given pose to the target.
+func (lp *localPlanner) sightClearance(start pose2D, target r3.Vector, obstacles []spatialmath.Geometry) float64 {
+	dist := math.Hypot(target.X-start.x, target.Y-start.y)
+	current := pose2D{x: start.x, y: start.y, heading: math.Atan2(target.Y-start.y, target.X-start.x)}
+	clearance := math.Inf(1)
+	for d := localPlannerSightStepMM; d < dist; d += localPlannerSightStepMM {
+		current.x = start.x + d*math.Cos(current.heading)
+		current.y = start.y + d*math.Sin(current.heading)
+		clearance = math.Min(clearance, lp.clearance(current.toPose(target.Z), obstacles))
+	}
+	return clearance
+}
+
+// simulate drives the base from the given pose at the given velocities for the planning horizon, returning where it ends up and its
+// least clearance from the obstacles along the way.
+func (lp *localPlanner) simulate(start pose2D, z, linVel, angVel float64, obstacles []spatialmath.Geometry) (pose2D, float64) {
+	current := start
+	clearance := lp.clearance(current.toPose(z), obstacles)
+	for t := 0.; t < lp.opts.HorizonSeconds && clearance >= 0; t += localPlannerSimStepSeconds {
+		current.heading += rdkutils.DegToRad(angVel) * localPlannerSimStepSeconds
+		current.x += linVel * math.Cos(current.heading) * localPlannerSimStepSeconds
+		current.y += linVel * math.Sin(current.heading) * localPlannerSimStepSeconds
+		clearance = math.Min(clearance, lp.clearance(current.toPose(z), obstacles))
+	}
+	return current, clearance
+}
+
+// followWithLocalPlanner drives the base along the given arc steps, choosing velocities with the local planner which avoid the
+// obstacles around the base.
+func (ptgk *ptgBaseKinematics) followWithLocalPlanner(ctx context.Context, arcSteps []arcStep) error {
+	lp, err := ptgk.newLocalPlanner(arcSteps)
+	if err != nil {
+		return err
+	}
+	period := time.Duration(float64(time.Second) / lp.opts.RateHz)
+	var blockedSince time.Time
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		tickStart := ptgk.clock.Now()
+		poseInFrame, err := ptgk.Localizer.CurrentPosition(ctx)
+		if err != nil {
+			return err
+		}
+		pose := poseInFrame.Pose()
+		lp.updateProgress(pose)
+		reachedPt := lp.path[lp.progress]
+		ptgk.inputLock.Lock()
+		ptgk.currentState.currentIdx = reachedPt.stepIdx
+		ptgk.currentState.currentInputs = reachedPt.inputs
+		ptgk.inputLock.Unlock()
+		if lp.reached(pose) {
+			break
+		}
+
+		var obstacles []spatialmath.Geometry
+		if lp.opts.Obstacles != nil {
+			if obstacles, err = lp.opts.Obstacles(ctx); err != nil {
+				return err
+			}
+		}
+		linVel, angVel, ok := lp.choose(pose, obstacles)
+		if !ok || linVel == 0 {
+			if blockedSince.IsZero() {
+				blockedSince = tickStart
+			}
+			if ptgk.clock.Since(blockedSince) > lp.opts.BlockedTimeout {
+				return ErrLocalPlanBlocked
+			}
+		} else {
+			blockedSince = time.Time{}
+		}
+		if err := ptgk.Base.SetVelocity(ctx, r3.Vector{Y: linVel}, r3.Vector{Z: angVel}, nil); err != nil {
+			return err
+		}
+		if !selectContextOrWait(ctx, ptgk.clock, period-ptgk.clock.Since(tickStart)) {
+			return ctx.Err()
+		}
+	}
+
+	// the local planner only follows the position of the plan, so the base turns to its final heading if it can spin in place
+	if ptgk.opts.PositionOnlyMode || lp.minTurnRadiusMM > 0 {
+		return nil
+	}
+	poseInFrame, err := ptgk.Localizer.CurrentPosition(ctx)
+	if err != nil {
+		return err
+	}
+	turnDegs := rdkutils.RadToDeg(angleBetween(newPose2D(poseInFrame.Pose()).heading, newPose2D(lp.path[len(lp.path)-1].pose).heading))
+	if math.Abs(turnDegs) < ptgk.opts.HeadingThresholdDegrees {
+		return nil
+	}
+	return ptgk.Base.Spin(ctx, turnDegs, ptgk.angVelocityDegsPerSecond, nil)
+}
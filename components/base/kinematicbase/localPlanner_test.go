@@ -0,0 +1,105 @@
+package kinematicbase
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/spatialmath"
+	rdkutils "go.viam.com/rdk/utils"
+)
+
+func TestLocalPlanner(t *testing.T) {
+	// newStraightPlanner returns a local planner following a straight 3m plan along the y axis, which is the direction the base drives
+	newStraightPlanner := func(t *testing.T) *localPlanner {
+		t.Helper()
+		sphere, err := spatialmath.NewSphere(spatialmath.NewZeroPose(), 150, "base")
+		test.That(t, err, test.ShouldBeNil)
+		lp := &localPlanner{
+			opts:           *NewLocalPlannerOptions(nil),
+			maxLinVelMMps:  200,
+			maxAngVelDegps: 60,
+			maxDeviationMM: 600,
+			geometries:     []spatialmath.Geometry{sphere},
+		}
+		for dist := 0.; dist <= 3000; dist += localPlannerPathSpacingMM {
+			lp.path = append(lp.path, pathPoint{pose: spatialmath.NewPoseFromPoint(r3.Vector{Y: dist}), distMM: dist})
+		}
+		return lp
+	}
+
+	// drive follows the local planner from the start of the plan for at most the given number of choices, returning the poses the
+	// base passed through and whether it reached the end of the plan.
+	drive := func(lp *localPlanner, obstacles []spatialmath.Geometry, choices int) ([]spatialmath.Pose, bool) {
+		current := newPose2D(spatialmath.NewZeroPose())
+		poses := []spatialmath.Pose{current.toPose(0)}
+		dt := 1 / lp.opts.RateHz
+		for i := 0; i < choices; i++ {
+			pose := current.toPose(0)
+			lp.updateProgress(pose)
+			if lp.reached(pose) {
+				return poses, true
+			}
+			linVel, angVel, ok := lp.choose(pose, obstacles)
+			if !ok {
+				return poses, false
+			}
+			current.heading += rdkutils.DegToRad(angVel) * dt
+			current.x += linVel * math.Cos(current.heading) * dt
+			current.y += linVel * math.Sin(current.heading) * dt
+			poses = append(poses, current.toPose(0))
+		}
+		return poses, false
+	}
+
+	t.Run("the base follows the plan when nothing is in the way", func(t *testing.T) {
+		lp := newStraightPlanner(t)
+		poses, reached := drive(lp, nil, 1000)
+		test.That(t, reached, test.ShouldBeTrue)
+		for _, pose := range poses {
+			test.That(t, math.Abs(pose.Point().X), test.ShouldBeLessThan, 10)
+		}
+	})
+
+	t.Run("the base drives around small obstacles", func(t *testing.T) {
+		lp := newStraightPlanner(t)
+		obstacle, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(r3.Vector{X: 50, Y: 1200}), r3.Vector{X: 200, Y: 200, Z: 200}, "")
+		test.That(t, err, test.ShouldBeNil)
+		poses, reached := drive(lp, []spatialmath.Geometry{obstacle}, 1000)
+		test.That(t, reached, test.ShouldBeTrue)
+		maxDeviation := 0.
+		for _, pose := range poses {
+			test.That(t, lp.clearance(pose, []spatialmath.Geometry{obstacle}), test.ShouldBeGreaterThanOrEqualTo, 0)
+			maxDeviation = math.Max(maxDeviation, math.Abs(pose.Point().X))
+		}
+		test.That(t, maxDeviation, test.ShouldBeGreaterThan, 100)
+	})
+
+	t.Run("the base stops when the plan is blocked", func(t *testing.T) {
+		lp := newStraightPlanner(t)
+		wall, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(r3.Vector{Y: 1200}), r3.Vector{X: 3000, Y: 100, Z: 200}, "")
+		test.That(t, err, test.ShouldBeNil)
+		poses, reached := drive(lp, []spatialmath.Geometry{wall}, 300)
+		test.That(t, reached, test.ShouldBeFalse)
+		for _, pose := range poses {
+			test.That(t, lp.clearance(pose, []spatialmath.Geometry{wall}), test.ShouldBeGreaterThanOrEqualTo, 0)
+		}
+		// the base has stopped driving towards the wall
+		linVel, _, _ := lp.choose(poses[len(poses)-1], []spatialmath.Geometry{wall})
+		test.That(t, linVel, test.ShouldEqual, 0)
+	})
+
+	t.Run("bases which cannot spin in place turn no tighter than their turning radius", func(t *testing.T) {
+		lp := newStraightPlanner(t)
+		lp.minTurnRadiusMM = 1000
+		lp.linVelMMps = 200
+		// the base faces away from the plan, which it would turn towards as tightly as it could
+		pose := spatialmath.NewPoseFromOrientation(&spatialmath.OrientationVectorDegrees{OZ: 1, Theta: 90})
+		linVel, angVel, ok := lp.choose(pose, nil)
+		test.That(t, ok, test.ShouldBeTrue)
+		test.That(t, angVel, test.ShouldBeLessThan, 0)
+		test.That(t, math.Abs(rdkutils.DegToRad(angVel))*lp.minTurnRadiusMM, test.ShouldBeLessThanOrEqualTo, linVel+1e-6)
+	})
+}
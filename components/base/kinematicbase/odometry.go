@@ -0,0 +1,109 @@
+//go:build !no_cgo
+
+package kinematicbase
+
+import (
+	"context"
+	"math"
+	"sync"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// odometryRelativePositionExtraKey is the key of extra through which wheeled odometry movement sensors are asked for their position
+// in meters relative to where they started, rather than as a GPS coordinate.
+const odometryRelativePositionExtraKey = "return_relative_pos_m"
+
+// odometryLocalizer localizes a base by wheeled odometry alone.
+type odometryLocalizer struct {
+	ms                          movementsensor.MovementSensor
+	driftPerMeterMM, maxDriftMM float64
+
+	mu sync.Mutex
+	// origin is the odometry pose of the base when the localizer was created
+	origin     spatialmath.Pose
+	last       spatialmath.Pose
+	traveledMM float64
+}
+
+// NewOdometryLocalizer returns a localizer for bases with neither SLAM nor GPS, from a movement sensor which tracks the encoders of
+// their wheels, such as a wheeled_odometry sensor. Positions are relative to where the base was when the localizer was created, with
+// +Y ahead of it, so a base wrapped with it may be moved relative to where it is, such as 2m forward and 1m left. Odometry drifts as
+// the base drives, at the rate and within the bound set by the odometry fields of the options.
+func NewOdometryLocalizer(ctx context.Context, ms movementsensor.MovementSensor, options Options) (motion.Localizer, error) {
+	properties, err := ms.Properties(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !properties.PositionSupported || !properties.OrientationSupported {
+		return nil, errors.Errorf("movement sensor %s must support both position and orientation to localize by odometry", ms.Name())
+	}
+	o := &odometryLocalizer{
+		ms:              ms,
+		driftPerMeterMM: options.OdometryDriftPerMeterMM,
+		maxDriftMM:      options.MaxOdometryDriftMM,
+	}
+	if o.origin, err = o.odometryPose(ctx); err != nil {
+		return nil, err
+	}
+	o.last = o.origin
+	return o, nil
+}
+
+// odometryPose returns the pose of the base as tracked by the odometry sensor since it started.
+func (o *odometryLocalizer) odometryPose(ctx context.Context) (spatialmath.Pose, error) {
+	position, _, err := o.ms.Position(ctx, map[string]interface{}{odometryRelativePositionExtraKey: true})
+	if err != nil {
+		return nil, err
+	}
+	orientation, err := o.ms.Orientation(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	// relative positions are returned as a point whose latitude is Y and longitude is X, in meters
+	return spatialmath.NewPose(r3.Vector{X: position.Lng() * 1000, Y: position.Lat() * 1000}, orientation), nil
+}
+
+// CurrentPosition returns the pose of the base relative to where it was when the localizer was created. It errors if odometry is
+// expected to have drifted beyond the maximum set by the options.
+func (o *odometryLocalizer) CurrentPosition(ctx context.Context) (*referenceframe.PoseInFrame, error) {
+	pose, err := o.odometryPose(ctx)
+	if err != nil {
+		return nil, err
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.traveledMM += pose.Point().Sub(o.last.Point()).Norm()
+	o.last = pose
+	if drift := o.driftMM(); o.maxDriftMM > 0 && drift > o.maxDriftMM {
+		return nil, errors.Errorf(
+			"odometry is expected to have drifted %.0fmm after driving %.0fmm, beyond the maximum of %.0fmm",
+			drift, o.traveledMM, o.maxDriftMM,
+		)
+	}
+	return referenceframe.NewPoseInFrame(referenceframe.World, spatialmath.PoseBetween(o.origin, pose)), nil
+}
+
+// Confidence reports the expected drift of odometry as the standard deviation of the position, or the uncertainty reported by the
+// movement sensor if it is larger. The uncertainty of the heading is unknown.
+func (o *odometryLocalizer) Confidence(ctx context.Context) (motion.LocalizerConfidence, error) {
+	o.mu.Lock()
+	positionStdDevMM := o.driftMM()
+	o.mu.Unlock()
+	if acc, err := o.ms.Accuracy(ctx, nil); err == nil && acc != nil {
+		if reported, ok := acc.AccuracyMap[movementsensor.PositionStdDevMMKey]; ok {
+			positionStdDevMM = math.Max(positionStdDevMM, float64(reported))
+		}
+	}
+	return motion.LocalizerConfidence{PositionStdDevMM: positionStdDevMM}, nil
+}
+
+func (o *odometryLocalizer) driftMM() float64 {
+	return o.traveledMM / 1000 * o.driftPerMeterMM
+}
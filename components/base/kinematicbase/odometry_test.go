@@ -0,0 +1,82 @@
+package kinematicbase
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	geo "github.com/kellydunn/golang-geo"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestOdometryLocalizer(t *testing.T) {
+	ctx := context.Background()
+	// the base starts 1m right of and 2m ahead of where the odometry sensor started, facing left
+	x, y, yaw := 1., 2., math.Pi/2
+	var stdDevMM float32
+	ms := inject.NewMovementSensor("odometry")
+	ms.PropertiesFunc = func(ctx context.Context, extra map[string]interface{}) (*movementsensor.Properties, error) {
+		return &movementsensor.Properties{PositionSupported: true, OrientationSupported: true}, nil
+	}
+	ms.PositionFunc = func(ctx context.Context, extra map[string]interface{}) (*geo.Point, float64, error) {
+		test.That(t, extra[odometryRelativePositionExtraKey], test.ShouldBeTrue)
+		return geo.NewPoint(y, x), 0, nil
+	}
+	ms.OrientationFunc = func(ctx context.Context, extra map[string]interface{}) (spatialmath.Orientation, error) {
+		return &spatialmath.OrientationVector{OZ: 1, Theta: yaw}, nil
+	}
+	ms.AccuracyFunc = func(ctx context.Context, extra map[string]interface{}) (*movementsensor.Accuracy, error) {
+		acc := movementsensor.UnimplementedOptionalAccuracies()
+		acc.AccuracyMap = map[string]float32{movementsensor.PositionStdDevMMKey: stdDevMM}
+		return acc, nil
+	}
+
+	opts := NewKinematicBaseOptions()
+	localizer, err := NewOdometryLocalizer(ctx, ms, opts)
+	test.That(t, err, test.ShouldBeNil)
+	pif, err := localizer.CurrentPosition(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatialmath.PoseAlmostEqual(pif.Pose(), spatialmath.NewZeroPose()), test.ShouldBeTrue)
+
+	// driving 2m forward then turning right puts the base 2m ahead of where the localizer was created, facing right
+	x, yaw = -1, 0
+	pif, err = localizer.CurrentPosition(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatialmath.R3VectorAlmostEqual(pif.Pose().Point(), r3.Vector{Y: 2000}, 1e-6), test.ShouldBeTrue)
+	test.That(t, pif.Pose().Orientation().OrientationVectorDegrees().Theta, test.ShouldAlmostEqual, -90)
+
+	conf, err := localizer.Confidence(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, conf.PositionStdDevMM, test.ShouldAlmostEqual, 40)
+	stdDevMM = 55
+	conf, err = localizer.Confidence(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, conf.PositionStdDevMM, test.ShouldAlmostEqual, 55)
+
+	t.Run("errors beyond the maximum drift", func(t *testing.T) {
+		x, y, yaw = 0, 0, 0
+		opts.MaxOdometryDriftMM = 30
+		localizer, err := NewOdometryLocalizer(ctx, ms, opts)
+		test.That(t, err, test.ShouldBeNil)
+		y = 1
+		_, err = localizer.CurrentPosition(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		y = 2
+		_, err = localizer.CurrentPosition(ctx)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "beyond the maximum of 30mm")
+	})
+
+	t.Run("requires position and orientation", func(t *testing.T) {
+		ms.PropertiesFunc = func(ctx context.Context, extra map[string]interface{}) (*movementsensor.Properties, error) {
+			return &movementsensor.Properties{PositionSupported: true}, nil
+		}
+		_, err := NewOdometryLocalizer(ctx, ms, opts)
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
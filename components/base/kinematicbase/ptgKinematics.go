@@ -7,8 +7,12 @@ package kinematicbase
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math"
 	"sync"
 
+	"github.com/benbjohnson/clock"
+
 	"go.viam.com/rdk/components/base"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/motionplan"
@@ -38,6 +42,7 @@ type ptgBaseKinematics struct {
 	courseCorrectionIdx              int
 	linVelocityMMPerSecond           float64
 	angVelocityDegsPerSecond         float64
+	baseTurningRadiusMeters          float64
 	nonzeroBaseTurningRadiusMeters   float64
 
 	// All changeable state of the base is here
@@ -47,6 +52,7 @@ type ptgBaseKinematics struct {
 	origin     spatialmath.Pose
 	geometries []spatialmath.Geometry
 	cancelFunc context.CancelFunc
+	clock      clock.Clock
 }
 
 type baseState struct {
@@ -79,9 +85,19 @@ func wrapWithPTGKinematics(
 		return nil, errors.New("can only wrap with PTG kinematics if turning radius is greater than or equal to zero")
 	}
 
+	turningRadiusMeters := baseTurningRadiusMeters
+	if options.Trailer != nil {
+		// arcs any tighter than those the trailer can follow without jackknifing are never planned
+		minRadiusMM, err := options.Trailer.HitchConstraint(b.Name().ShortName()).MinTurningRadiusMM()
+		if err != nil {
+			return nil, err
+		}
+		turningRadiusMeters = math.Max(turningRadiusMeters, minRadiusMM/1000)
+	}
+
 	angVelocityDegsPerSecond, err := correctAngularVelocityWithTurnRadius(
 		logger,
-		baseTurningRadiusMeters,
+		turningRadiusMeters,
 		linVelocityMMPerSecond,
 		options.AngularVelocityDegsPerSec,
 	)
@@ -96,7 +112,14 @@ func wrapWithPTGKinematics(
 		baseTurningRadiusMeters,
 	)
 
+	footprint, err := options.footprint(b.Name().ShortName())
+	if err != nil {
+		return nil, err
+	}
 	geometries, err := b.Geometries(ctx, nil)
+	if footprint != nil {
+		geometries, err = []spatialmath.Geometry{footprint}, nil
+	}
 	if len(geometries) == 0 || err != nil {
 		logger.CWarnf(
 			ctx, "base %s not configured with a geometry, will be considered a 300mm sphere for collision detection purposes.",
@@ -110,15 +133,36 @@ func wrapWithPTGKinematics(
 	}
 
 	nonzeroBaseTurningRadiusMeters := (linVelocityMMPerSecond / rdkutils.DegToRad(angVelocityDegsPerSecond)) / 1000.
-	planningFrame, err := tpspace.NewPTGFrameFromKinematicOptions(
-		b.Name().ShortName(),
-		logger,
-		nonzeroBaseTurningRadiusMeters,
-		0, // If zero, will use default trajectory count on the receiver end.
-		geometries,
-		options.NoSkidSteer,
-		baseTurningRadiusMeters == 0,
-	)
+	var planningFrame referenceframe.Frame
+	if options.PTGLibrary != nil {
+		for i, ptg := range options.PTGLibrary.PTGs {
+			if ptg.TurningRadiusMM != 0 && ptg.TurningRadiusMM < turningRadiusMeters*1000 {
+				return nil, fmt.Errorf(
+					"ptg %d of the ptg library turns with radius %fmm, more tightly than base %s can",
+					i, ptg.TurningRadiusMM, b.Name().ShortName(),
+				)
+			}
+		}
+		planningFrame, err = tpspace.NewPTGFrameFromLibrary(
+			b.Name().ShortName(),
+			logger,
+			nonzeroBaseTurningRadiusMeters,
+			options.PTGLibrary,
+			0, // If zero, will use default trajectory count on the receiver end.
+			geometries,
+		)
+	} else {
+		planningFrame, err = tpspace.NewPTGFrameFromKinematicOptions(
+			b.Name().ShortName(),
+			logger,
+			nonzeroBaseTurningRadiusMeters,
+			0, // If zero, will use default trajectory count on the receiver end.
+			geometries,
+			options.NoSkidSteer,
+			// a base towing a trailer would jackknife it by rotating in place
+			baseTurningRadiusMeters == 0 && options.Trailer == nil,
+		)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -160,10 +204,12 @@ func wrapWithPTGKinematics(
 		courseCorrectionIdx:            courseCorrectionIdx,
 		linVelocityMMPerSecond:         linVelocityMMPerSecond,
 		angVelocityDegsPerSecond:       angVelocityDegsPerSecond,
+		baseTurningRadiusMeters:        baseTurningRadiusMeters,
 		nonzeroBaseTurningRadiusMeters: nonzeroBaseTurningRadiusMeters,
 		currentState:                   startingState,
 		origin:                         origin,
 		geometries:                     geometries,
+		clock:                          options.clock(),
 	}, nil
 }
 
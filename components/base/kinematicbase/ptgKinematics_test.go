@@ -7,6 +7,7 @@ import (
 	"math"
 	"testing"
 
+	"github.com/golang/geo/r2"
 	"github.com/golang/geo/r3"
 	geo "github.com/kellydunn/golang-geo"
 	"go.viam.com/test"
@@ -419,6 +420,49 @@ func TestPTGKinematicsSimpleInputs(t *testing.T) {
 	test.That(t, err, test.ShouldBeNil)
 }
 
+func TestPTGKinematicsFootprint(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx := context.Background()
+
+	name := resource.Name{API: resource.NewAPI("is", "a", "fakebase"), Name: "fakebase"}
+	sphere, err := spatialmath.NewSphere(spatialmath.NewZeroPose(), 150., name.Name)
+	test.That(t, err, test.ShouldBeNil)
+	b := &fake.Base{
+		Named:         name.AsNamed(),
+		Geometry:      []spatialmath.Geometry{sphere},
+		WidthMeters:   0.2,
+		TurningRadius: 0,
+	}
+
+	// a forklift whose tines reach forward along +Y, between which a pallet may be picked up
+	kbo := NewKinematicBaseOptions()
+	kbo.Footprint = []r2.Point{{-300, -500}, {300, -500}, {300, 500}, {150, 500}, {150, 0}, {-150, 0}, {-150, 500}, {-300, 500}}
+	kbo.FootprintHeightMM = 200
+	kb, err := WrapWithKinematics(ctx, b, logger, nil, nil, kbo)
+	test.That(t, err, test.ShouldBeNil)
+
+	// the footprint is checked for collisions in place of the configured geometry
+	geoms, err := kb.Geometries(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(geoms), test.ShouldEqual, 1)
+	gifs, err := kb.Kinematics().Geometries(make([]referenceframe.Input, len(kb.Kinematics().DoF())))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(gifs.Geometries()), test.ShouldEqual, 1)
+	pallet, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(r3.Vector{Y: 300}), r3.Vector{X: 200, Y: 300, Z: 100}, "pallet")
+	test.That(t, err, test.ShouldBeNil)
+	collides, err := gifs.Geometries()[0].CollidesWith(pallet, 0)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, collides, test.ShouldBeFalse)
+	collides, err = gifs.Geometries()[0].CollidesWith(pallet.Transform(spatialmath.NewPoseFromPoint(r3.Vector{X: 200})), 0)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, collides, test.ShouldBeTrue)
+
+	// footprints must be extruded
+	kbo.FootprintHeightMM = 0
+	_, err = WrapWithKinematics(ctx, b, logger, nil, nil, kbo)
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
 func TestCopyArcStep(t *testing.T) {
 	step := &arcStep{
 		linVelMMps:      r3.Vector{1, 2, 3},
@@ -0,0 +1,103 @@
+//go:build !no_cgo
+
+package kinematicbase
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"go.viam.com/rdk/spatialmath"
+)
+
+// A SafetyZone is a region which a base drives through slowly, or not at all, such as an area where people work alongside robots.
+// Whenever any geometry of the base intersects the zone, its speed is capped, or the base is stopped, in the manner of speed and
+// separation monitoring.
+type SafetyZone struct {
+	// Name identifies the zone in errors and logs.
+	Name string
+
+	// Geometry is the extent of the zone, in the frame the base is localized in.
+	Geometry spatialmath.Geometry
+
+	// MaxSpeedMMPerSec caps the linear speed of the base while it is in the zone.
+	MaxSpeedMMPerSec float64
+
+	// Stop, if true, stops the base once it is in the zone, failing the move rather than driving on through it.
+	Stop bool
+}
+
+// SafetyZoneSource returns the safety zones, in the frame a base is localized in, which it drives through at the moment it is called.
+// It is called at every update while the base drives, so that zones placed relative to parts of the robot which move follow them.
+type SafetyZoneSource func(ctx context.Context) ([]SafetyZone, error)
+
+// SafetyZoneError is returned when a base is stopped by a zone it may not drive in.
+type SafetyZoneError struct {
+	Zone string
+}
+
+func (e *SafetyZoneError) Error() string {
+	return fmt.Sprintf("stopped in safety zone %s", e.Zone)
+}
+
+// zoneScale returns the factor by which the velocities of a base with the given geometries at the given pose should be scaled so
+// that it drives at the given speed no faster than any zone it is in, or is within lookaheadMM of, allows, or 1 if there are none
+// which cap its speed. Zones are capped ahead of the base so that it has slowed by the time it enters them, and so never exceeds their
+// speed within them. An error is returned if it is in a zone which stops it.
+func zoneScale(
+	zones []SafetyZone,
+	geometries []spatialmath.Geometry,
+	pose spatialmath.Pose,
+	speedMMPerSec, lookaheadMM float64,
+) (float64, error) {
+	scale := 1.
+	for _, zone := range zones {
+		distance := clearance(geometries, pose, []spatialmath.Geometry{zone.Geometry}, 0)
+		if zone.Stop {
+			if distance <= 0 {
+				return 0, &SafetyZoneError{Zone: zone.Name}
+			}
+			continue
+		}
+		if distance > lookaheadMM {
+			continue
+		}
+		if zone.MaxSpeedMMPerSec > 0 && speedMMPerSec > zone.MaxSpeedMMPerSec {
+			scale = math.Min(scale, zone.MaxSpeedMMPerSec/speedMMPerSec)
+		}
+	}
+	return scale, nil
+}
+
+// stepScale returns the factor by which the velocities of the base should be scaled to drive the given step, slowed near obstacles
+// and capped by the safety zones the base is in or could reach at full speed before the next update. If the position of the base
+// cannot be read, it is capped as though it were in every zone which caps its speed.
+func (ptgk *ptgBaseKinematics) stepScale(ctx context.Context, step arcStep) (float64, error) {
+	scale := ptgk.speedScale(ctx)
+	speed := step.linVelMMps.Norm()
+	if ptgk.opts.SafetyZones == nil || ptgk.Localizer == nil || speed == 0 {
+		return scale, nil
+	}
+	zones, err := ptgk.opts.SafetyZones(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if len(zones) == 0 {
+		return scale, nil
+	}
+	pif, err := ptgk.CurrentPosition(ctx)
+	if err != nil {
+		ptgk.logger.CDebugf(ctx, "could not get position to check safety zones, slowing down: %v", err)
+		for _, zone := range zones {
+			if zone.MaxSpeedMMPerSec > 0 {
+				scale = math.Min(scale, zone.MaxSpeedMMPerSec/speed)
+			}
+		}
+		return scale, nil
+	}
+	capped, err := zoneScale(zones, ptgk.geometries, pif.Pose(), speed, speed*ptgk.opts.UpdateStepSeconds)
+	if err != nil {
+		return 0, err
+	}
+	return math.Min(scale, capped), nil
+}
@@ -0,0 +1,71 @@
+package kinematicbase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/spatialmath"
+)
+
+func TestZoneScale(t *testing.T) {
+	base, err := spatialmath.NewSphere(spatialmath.NewZeroPose(), 100, "base")
+	test.That(t, err, test.ShouldBeNil)
+	slowBox, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(r3.Vector{X: 1000}), r3.Vector{X: 1000, Y: 1000, Z: 1000}, "slow")
+	test.That(t, err, test.ShouldBeNil)
+	slowerBox, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(r3.Vector{X: 1400}), r3.Vector{X: 400, Y: 400, Z: 400}, "slower")
+	test.That(t, err, test.ShouldBeNil)
+	stopBox, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(r3.Vector{X: 5000}), r3.Vector{X: 1000, Y: 1000, Z: 1000}, "stop")
+	test.That(t, err, test.ShouldBeNil)
+	zones := []SafetyZone{
+		{Name: "slow", Geometry: slowBox, MaxSpeedMMPerSec: 250},
+		{Name: "slower", Geometry: slowerBox, MaxSpeedMMPerSec: 100},
+		{Name: "stop", Geometry: stopBox, Stop: true},
+	}
+	geometries := []spatialmath.Geometry{base}
+
+	t.Run("outside every zone", func(t *testing.T) {
+		scale, err := zoneScale(zones, geometries, spatialmath.NewZeroPose(), 500, 0)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, scale, test.ShouldEqual, 1)
+	})
+
+	t.Run("partly within a zone", func(t *testing.T) {
+		scale, err := zoneScale(zones, geometries, spatialmath.NewPoseFromPoint(r3.Vector{X: 450}), 500, 0)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, scale, test.ShouldAlmostEqual, 0.5)
+	})
+
+	t.Run("already slower than the zone allows", func(t *testing.T) {
+		scale, err := zoneScale(zones, geometries, spatialmath.NewPoseFromPoint(r3.Vector{X: 1000}), 200, 0)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, scale, test.ShouldEqual, 1)
+	})
+
+	t.Run("the slowest zone caps the speed", func(t *testing.T) {
+		scale, err := zoneScale(zones, geometries, spatialmath.NewPoseFromPoint(r3.Vector{X: 1400}), 500, 0)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, scale, test.ShouldAlmostEqual, 0.2)
+	})
+
+	t.Run("zones within the lookahead are capped before they are entered", func(t *testing.T) {
+		scale, err := zoneScale(zones, geometries, spatialmath.NewPoseFromPoint(r3.Vector{X: 300}), 500, 150)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, scale, test.ShouldAlmostEqual, 0.5)
+		scale, err = zoneScale(zones, geometries, spatialmath.NewPoseFromPoint(r3.Vector{X: 300}), 500, 50)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, scale, test.ShouldEqual, 1)
+		// the base is only stopped once it is within a zone which stops it
+		_, err = zoneScale(zones, geometries, spatialmath.NewPoseFromPoint(r3.Vector{X: 4350}), 500, 150)
+		test.That(t, err, test.ShouldBeNil)
+	})
+
+	t.Run("within a stop zone", func(t *testing.T) {
+		_, err := zoneScale(zones, geometries, spatialmath.NewPoseFromPoint(r3.Vector{X: 5000}), 500, 0)
+		var zoneErr *SafetyZoneError
+		test.That(t, errors.As(err, &zoneErr), test.ShouldBeTrue)
+		test.That(t, zoneErr.Zone, test.ShouldEqual, "stop")
+	})
+}
@@ -0,0 +1,74 @@
+//go:build !no_cgo
+
+package kinematicbase
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+const defaultMinSpeedScale = 0.25
+
+// SpeedScalingOptions configures the slowing of a base near obstacles while it drives the arcs of a plan. Velocities are scaled down
+// linearly from full speed when the nearest obstacle is SlowdownDistanceMM away, to MinSpeedScale of full speed when it touches the
+// base, and each arc takes correspondingly longer to drive. Speed scaling requires a localizer, and is not used by the local planner,
+// which chooses its own velocities around obstacles.
+type SpeedScalingOptions struct {
+	// Obstacles returns the obstacles to slow down near. It may be set after the base is wrapped, but before GoToInputs is called.
+	Obstacles ObstacleSource
+
+	// SlowdownDistanceMM is the distance from the nearest obstacle within which the base slows down.
+	SlowdownDistanceMM float64
+
+	// MinSpeedScale is the fraction of full speed the base drives at when touching an obstacle. It must be positive so that the base
+	// always makes progress; stopping for obstacles is left to replanning.
+	MinSpeedScale float64
+}
+
+// NewSpeedScalingOptions creates a struct with values used to slow a base within slowdownDistanceMM of the given obstacles.
+// all other values are pre-set to reasonable default values and can be changed if desired.
+func NewSpeedScalingOptions(obstacles ObstacleSource, slowdownDistanceMM float64) *SpeedScalingOptions {
+	return &SpeedScalingOptions{
+		Obstacles:          obstacles,
+		SlowdownDistanceMM: slowdownDistanceMM,
+		MinSpeedScale:      defaultMinSpeedScale,
+	}
+}
+
+// scaleAt returns the factor by which velocities are scaled when the nearest obstacle is the given distance away.
+func (opts *SpeedScalingOptions) scaleAt(distMM float64) float64 {
+	if opts.SlowdownDistanceMM <= 0 || distMM >= opts.SlowdownDistanceMM {
+		return 1
+	}
+	return opts.MinSpeedScale + (1-opts.MinSpeedScale)*math.Max(0, distMM)/opts.SlowdownDistanceMM
+}
+
+// speedScale returns the factor by which the velocities of the base should be scaled given the obstacles around it, or 1 if speed
+// scaling is not configured. If the obstacles or the position of the base cannot be read, the base is slowed as though it were
+// touching an obstacle.
+func (ptgk *ptgBaseKinematics) speedScale(ctx context.Context) float64 {
+	opts := ptgk.opts.SpeedScaling
+	if opts == nil || opts.Obstacles == nil || ptgk.Localizer == nil {
+		return 1
+	}
+	obstacles, err := opts.Obstacles(ctx)
+	if err != nil {
+		ptgk.logger.CDebugf(ctx, "could not get obstacles to scale speed by, slowing down: %v", err)
+		return opts.MinSpeedScale
+	}
+	if len(obstacles) == 0 {
+		return 1
+	}
+	pif, err := ptgk.CurrentPosition(ctx)
+	if err != nil {
+		ptgk.logger.CDebugf(ctx, "could not get position to scale speed by, slowing down: %v", err)
+		return opts.MinSpeedScale
+	}
+	return opts.scaleAt(clearance(ptgk.geometries, pif.Pose(), obstacles, opts.SlowdownDistanceMM))
+}
+
+// scaledDuration returns how long it takes to drive for the given duration at full speed when velocities are scaled by scale.
+func scaledDuration(seconds, scale float64) time.Duration {
+	return time.Duration(microsecondsPerSecond*seconds/scale) * time.Microsecond
+}
@@ -0,0 +1,43 @@
+package kinematicbase
+
+import (
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/spatialmath"
+)
+
+func TestSpeedScaling(t *testing.T) {
+	opts := NewSpeedScalingOptions(nil, 1000)
+	test.That(t, opts.MinSpeedScale, test.ShouldEqual, defaultMinSpeedScale)
+
+	t.Run("velocities scale linearly within the slowdown distance", func(t *testing.T) {
+		test.That(t, opts.scaleAt(2000), test.ShouldEqual, 1)
+		test.That(t, opts.scaleAt(1000), test.ShouldEqual, 1)
+		test.That(t, opts.scaleAt(500), test.ShouldAlmostEqual, 0.625)
+		test.That(t, opts.scaleAt(0), test.ShouldAlmostEqual, defaultMinSpeedScale)
+		// colliding obstacles have a negative distance
+		test.That(t, opts.scaleAt(-100), test.ShouldAlmostEqual, defaultMinSpeedScale)
+	})
+
+	t.Run("the nearest obstacle determines the scale", func(t *testing.T) {
+		base, err := spatialmath.NewSphere(spatialmath.NewZeroPose(), 100, "base")
+		test.That(t, err, test.ShouldBeNil)
+		near, err := spatialmath.NewSphere(spatialmath.NewPoseFromPoint(r3.Vector{Y: 900}), 100, "near")
+		test.That(t, err, test.ShouldBeNil)
+		far, err := spatialmath.NewSphere(spatialmath.NewPoseFromPoint(r3.Vector{X: 3000}), 100, "far")
+		test.That(t, err, test.ShouldBeNil)
+
+		pose := spatialmath.NewPoseFromPoint(r3.Vector{Y: 200})
+		dist := clearance([]spatialmath.Geometry{base}, pose, []spatialmath.Geometry{far, near}, opts.SlowdownDistanceMM)
+		test.That(t, dist, test.ShouldAlmostEqual, 500)
+		test.That(t, opts.scaleAt(dist), test.ShouldAlmostEqual, 0.625)
+	})
+
+	t.Run("scaled durations are longer", func(t *testing.T) {
+		test.That(t, scaledDuration(1, 1).Seconds(), test.ShouldAlmostEqual, 1)
+		test.That(t, scaledDuration(1, 0.5).Seconds(), test.ShouldAlmostEqual, 2)
+	})
+}
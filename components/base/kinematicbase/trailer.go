@@ -0,0 +1,143 @@
+//go:build !no_cgo
+
+package kinematicbase
+
+import (
+	"context"
+	"math"
+	"sync"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// TrailerOptions describe a trailer towed by a base with PTG kinematics from a hitch behind it. A base towing a trailer is planned
+// for only with forward arcs wide enough that the trailer cannot swing beyond MaxHitchAngleDegs, and it tracks the angle of its hitch
+// from its localizer as it drives, stopping with an error if the trailer swings beyond it anyway.
+type TrailerOptions struct {
+	// HitchOffsetMM is how far behind the center of rotation of the base the trailer is hitched.
+	HitchOffsetMM float64
+
+	// LengthMM is the distance from the hitch to the axle of the trailer.
+	LengthMM float64
+
+	// MaxHitchAngleDegs is how far the trailer may swing from straight behind the base before it is considered to be jackknifing.
+	MaxHitchAngleDegs float64
+}
+
+// HitchConstraint returns the constraint which plans for the named frame of a base towing the trailer so that it cannot jackknife.
+func (t TrailerOptions) HitchConstraint(frame string) motionplan.HitchConstraint {
+	return motionplan.HitchConstraint{
+		Frame:             frame,
+		HitchOffsetMM:     t.HitchOffsetMM,
+		TrailerLengthMM:   t.LengthMM,
+		MaxHitchAngleDegs: t.MaxHitchAngleDegs,
+	}
+}
+
+// ArticulatedKinematicBase is a KinematicBase which tows a trailer, tracking the angle of its hitch as it moves.
+type ArticulatedKinematicBase interface {
+	KinematicBase
+
+	// HitchAngle returns the angle in radians of the base relative to its trailer, positive when the base is turned counterclockwise
+	// from it. The trailer is assumed to be straight behind the base when the base is wrapped.
+	HitchAngle() float64
+}
+
+// hitchState tracks the angle of the hitch of a trailer from the poses of the base towing it. As the base moves, the axle of the
+// trailer is dragged along the line between it and the hitch, as the wheels of the trailer cannot slip sideways.
+type hitchState struct {
+	trailer TrailerOptions
+
+	mu sync.Mutex
+	// axle is the position of the axle of the trailer, and is nil until the first pose of the base is known
+	axle  *r3.Vector
+	angle float64
+}
+
+// update moves the trailer along with the base to the given pose, returning the new hitch angle.
+func (h *hitchState) update(pose spatialmath.Pose) float64 {
+	heading := spatialmath.Compose(pose, spatialmath.NewPoseFromPoint(r3.Vector{Y: 1})).Point().Sub(pose.Point())
+	hitch := pose.Point().Sub(heading.Mul(h.trailer.HitchOffsetMM))
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	towing := heading
+	if h.axle != nil {
+		towing = hitch.Sub(*h.axle)
+	}
+	if towing.X == 0 && towing.Y == 0 {
+		towing = heading
+	}
+	towing = r3.Vector{X: towing.X, Y: towing.Y}.Normalize()
+	axle := hitch.Sub(towing.Mul(h.trailer.LengthMM))
+	h.axle = &axle
+	h.angle = math.Atan2(towing.X*heading.Y-towing.Y*heading.X, towing.X*heading.X+towing.Y*heading.Y)
+	return h.angle
+}
+
+// hitchAngle returns the most recently tracked hitch angle.
+func (h *hitchState) hitchAngle() float64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.angle
+}
+
+// hitchLocalizer tracks the hitch angle of a base towing a trailer each time the base is localized.
+type hitchLocalizer struct {
+	motion.Localizer
+	hitch *hitchState
+}
+
+// CurrentPosition returns the pose of the base, erroring if its trailer has swung beyond the maximum hitch angle.
+func (l *hitchLocalizer) CurrentPosition(ctx context.Context) (*referenceframe.PoseInFrame, error) {
+	pif, err := l.Localizer.CurrentPosition(ctx)
+	if err != nil {
+		return nil, err
+	}
+	angle := l.hitch.update(pif.Pose())
+	if maxAngle := l.hitch.trailer.MaxHitchAngleDegs; math.Abs(angle)*180/math.Pi > maxAngle {
+		return nil, errors.Errorf(
+			"trailer is jackknifing, hitch angle of %.1f degrees is beyond the maximum of %.1f", angle*180/math.Pi, maxAngle,
+		)
+	}
+	return pif, nil
+}
+
+type articulatedKinematics struct {
+	KinematicBase
+	hitch *hitchState
+}
+
+// wrapWithTrailer wraps a base towing a trailer with PTG kinematics which track the angle of its hitch.
+func wrapWithTrailer(
+	ctx context.Context,
+	b base.Base,
+	logger logging.Logger,
+	localizer motion.Localizer,
+	options Options,
+) (KinematicBase, error) {
+	if localizer == nil {
+		return nil, errors.Errorf("base %s must be localized to track the hitch angle of its trailer", b.Name().ShortName())
+	}
+	if options.NoSkidSteer {
+		return nil, errors.New("a base towing a trailer cannot be planned for using only rotations in place and straight lines")
+	}
+	hitch := &hitchState{trailer: *options.Trailer}
+	kb, err := wrapWithPTGKinematics(ctx, b, logger, &hitchLocalizer{Localizer: localizer, hitch: hitch}, options)
+	if err != nil {
+		return nil, err
+	}
+	return &articulatedKinematics{KinematicBase: kb, hitch: hitch}, nil
+}
+
+func (ak *articulatedKinematics) HitchAngle() float64 {
+	return ak.hitch.hitchAngle()
+}
@@ -0,0 +1,73 @@
+package kinematicbase
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/spatialmath"
+)
+
+type fixedLocalizer struct {
+	pose spatialmath.Pose
+}
+
+func (l *fixedLocalizer) CurrentPosition(context.Context) (*referenceframe.PoseInFrame, error) {
+	return referenceframe.NewPoseInFrame(referenceframe.World, l.pose), nil
+}
+
+func (l *fixedLocalizer) Confidence(context.Context) (motion.LocalizerConfidence, error) {
+	return motion.LocalizerConfidence{}, nil
+}
+
+// circlePose returns the pose of a base which has driven the given distance counterclockwise around a circle of the given radius,
+// starting at the origin heading along +Y.
+func circlePose(radiusMM, distMM float64) spatialmath.Pose {
+	turn := distMM / radiusMM
+	return spatialmath.NewPose(
+		r3.Vector{X: radiusMM * (math.Cos(turn) - 1), Y: radiusMM * math.Sin(turn)},
+		&spatialmath.OrientationVector{OZ: 1, Theta: turn},
+	)
+}
+
+func TestHitchState(t *testing.T) {
+	trailer := TrailerOptions{HitchOffsetMM: 200, LengthMM: 1000, MaxHitchAngleDegs: 45}
+
+	t.Run("driving straight keeps the trailer straight", func(t *testing.T) {
+		hitch := &hitchState{trailer: trailer}
+		for dist := 0.; dist <= 5000; dist += 50 {
+			test.That(t, hitch.update(spatialmath.NewPoseFromPoint(r3.Vector{Y: dist})), test.ShouldAlmostEqual, 0)
+		}
+	})
+
+	t.Run("circling settles at the steady state hitch angle", func(t *testing.T) {
+		hitch := &hitchState{trailer: trailer}
+		for dist := 0.; dist <= 20000; dist += 5 {
+			hitch.update(circlePose(3000, dist))
+		}
+		expected, ok := trailer.HitchConstraint("tractor").SteadyStateHitchAngle(3000)
+		test.That(t, ok, test.ShouldBeTrue)
+		test.That(t, hitch.hitchAngle(), test.ShouldAlmostEqual, expected, 0.01)
+	})
+
+	t.Run("jackknifing errors", func(t *testing.T) {
+		inner := &fixedLocalizer{pose: spatialmath.NewZeroPose()}
+		localizer := &hitchLocalizer{Localizer: inner, hitch: &hitchState{trailer: trailer}}
+		_, err := localizer.CurrentPosition(context.Background())
+		test.That(t, err, test.ShouldBeNil)
+
+		// spinning in place swings the trailer by as much as the base turns
+		inner.pose = spatialmath.NewPoseFromOrientation(&spatialmath.OrientationVectorDegrees{OZ: 1, Theta: 30})
+		_, err = localizer.CurrentPosition(context.Background())
+		test.That(t, err, test.ShouldBeNil)
+		inner.pose = spatialmath.NewPoseFromOrientation(&spatialmath.OrientationVectorDegrees{OZ: 1, Theta: 60})
+		_, err = localizer.CurrentPosition(context.Background())
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "jackknifing")
+	})
+}
@@ -37,6 +37,9 @@ func (s *serviceServer) MoveStraight(
 	ctx context.Context,
 	req *pb.MoveStraightRequest,
 ) (*pb.MoveStraightResponse, error) {
+	if err := resource.CheckReservation(ctx, Named(req.GetName()), req.Extra.AsMap()); err != nil {
+		return nil, err
+	}
 	operation.CancelOtherWithLabel(ctx, req.GetName())
 	base, err := s.coll.Resource(req.GetName())
 	if err != nil {
@@ -56,6 +59,9 @@ func (s *serviceServer) Spin(
 	ctx context.Context,
 	req *pb.SpinRequest,
 ) (*pb.SpinResponse, error) {
+	if err := resource.CheckReservation(ctx, Named(req.GetName()), req.Extra.AsMap()); err != nil {
+		return nil, err
+	}
 	operation.CancelOtherWithLabel(ctx, req.GetName())
 	base, err := s.coll.Resource(req.GetName())
 	if err != nil {
@@ -73,6 +79,9 @@ func (s *serviceServer) SetPower(
 	ctx context.Context,
 	req *pb.SetPowerRequest,
 ) (*pb.SetPowerResponse, error) {
+	if err := resource.CheckReservation(ctx, Named(req.GetName()), req.Extra.AsMap()); err != nil {
+		return nil, err
+	}
 	operation.CancelOtherWithLabel(ctx, req.GetName())
 	base, err := s.coll.Resource(req.GetName())
 	if err != nil {
@@ -95,6 +104,9 @@ func (s *serviceServer) SetVelocity(
 	ctx context.Context,
 	req *pb.SetVelocityRequest,
 ) (*pb.SetVelocityResponse, error) {
+	if err := resource.CheckReservation(ctx, Named(req.GetName()), req.Extra.AsMap()); err != nil {
+		return nil, err
+	}
 	operation.CancelOtherWithLabel(ctx, req.GetName())
 	base, err := s.coll.Resource(req.GetName())
 	if err != nil {
@@ -9,6 +9,7 @@ import (
 	pbcommon "go.viam.com/api/common/v1"
 	pb "go.viam.com/api/component/base/v1"
 	"go.viam.com/test"
+	"google.golang.org/protobuf/types/known/structpb"
 
 	"go.viam.com/rdk/components/base"
 	"go.viam.com/rdk/resource"
@@ -223,4 +224,41 @@ func TestServer(t *testing.T) {
 		test.That(t, resp, test.ShouldBeNil)
 		test.That(t, err, test.ShouldBeError, base.ErrGeometriesNil(failBaseName))
 	})
+
+	t.Run("Reserved", func(t *testing.T) {
+		reservations := resource.NewReservations()
+		reservation := reservations.Reserve(base.Named(testBaseName), "motion execution 1")
+		defer reservation.Release()
+		ctx := resource.ContextWithReservations(context.Background(), reservations)
+
+		stopped := false
+		workingBase.StopFunc = func(ctx context.Context, extra map[string]interface{}) error {
+			stopped = true
+			return nil
+		}
+		workingBase.SetPowerFunc = func(ctx context.Context, linear, angular r3.Vector, extra map[string]interface{}) error {
+			return nil
+		}
+
+		// commanding a reserved base is refused
+		_, err := server.SetPower(ctx, &pb.SetPowerRequest{Name: testBaseName})
+		test.That(t, resource.IsReservedError(err), test.ShouldBeTrue)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "busy by motion execution 1")
+
+		// by the robot which reserved it, but not by another robot with a base of the same name
+		otherCtx := resource.ContextWithReservations(context.Background(), resource.NewReservations())
+		_, err = server.SetPower(otherCtx, &pb.SetPowerRequest{Name: testBaseName})
+		test.That(t, err, test.ShouldBeNil)
+
+		// unless the reservation is overridden
+		extra, err := structpb.NewStruct(map[string]interface{}{resource.ReservationOverrideKey: true})
+		test.That(t, err, test.ShouldBeNil)
+		_, err = server.SetPower(ctx, &pb.SetPowerRequest{Name: testBaseName, Extra: extra})
+		test.That(t, err, test.ShouldBeNil)
+
+		// a reserved base can always be stopped
+		_, err = server.Stop(ctx, &pb.StopRequest{Name: testBaseName})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, stopped, test.ShouldBeTrue)
+	})
 }
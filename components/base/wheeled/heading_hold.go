@@ -0,0 +1,205 @@
+package wheeled
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+
+	"go.viam.com/rdk/components/motor"
+	"go.viam.com/rdk/components/movementsensor"
+	rdkutils "go.viam.com/rdk/utils"
+)
+
+// HeadingHoldConfig configures holding the heading of the base while it drives straight, with MoveStraight or with SetVelocity
+// without an angular velocity. The heading is measured by the gyro of a movement sensor mounted on the base, or from its
+// orientation if it has no gyro, and the rpms of the sides are made to differ in proportion to how far the base has drifted from
+// the heading it started at, rather than leaving it to veer with any mismatch between its motors.
+type HeadingHoldConfig struct {
+	// MovementSensor is the name of the movement sensor measuring the heading of the base.
+	MovementSensor string `json:"movement_sensor"`
+	// Kp is how many rpm faster one side is driven than the other for each degree the base has drifted from its heading.
+	Kp float64 `json:"kp"`
+	// FrequencyHz is how often the heading is measured and corrected. Defaults to 20Hz.
+	FrequencyHz float64 `json:"frequency_hz,omitempty"`
+}
+
+// Validate ensures the movement sensor, gain, and frequency of the heading hold are usable.
+func (cfg *HeadingHoldConfig) Validate() error {
+	if cfg.MovementSensor == "" {
+		return errors.New("heading_hold requires a movement_sensor")
+	}
+	if cfg.Kp <= 0 {
+		return errors.New("heading_hold kp must be positive")
+	}
+	if cfg.FrequencyHz < 0 {
+		return errors.New("heading_hold frequency_hz may not be negative")
+	}
+	return nil
+}
+
+// headingHold holds the heading of the base using a movement sensor.
+type headingHold struct {
+	cfg     *HeadingHoldConfig
+	sensor  movementsensor.MovementSensor
+	useGyro bool
+}
+
+// newHeadingHold returns a heading hold measuring the heading of the base with the movement sensor, preferring its gyro.
+func newHeadingHold(
+	ctx context.Context,
+	cfg *HeadingHoldConfig,
+	sensor movementsensor.MovementSensor,
+) (*headingHold, error) {
+	properties, err := sensor.Properties(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !properties.AngularVelocitySupported && !properties.OrientationSupported {
+		return nil, fmt.Errorf(
+			"heading_hold requires a movement sensor with angular velocity or orientation, %s has neither", cfg.MovementSensor,
+		)
+	}
+	return &headingHold{cfg: cfg, sensor: sensor, useGyro: properties.AngularVelocitySupported}, nil
+}
+
+func (hh *headingHold) period() time.Duration {
+	hz := hh.cfg.FrequencyHz
+	if hz == 0 {
+		hz = defaultFeedbackFrequencyHz
+	}
+	return time.Duration(float64(time.Second) / hz)
+}
+
+// offset returns the rpm to drive the left side faster, and the right side slower, than they are commanded to turn a base which
+// has drifted counterclockwise by the given degrees back to its heading. It is bounded by the mean rpm of the sides, so that
+// neither is driven against the direction the base is driving in.
+func (hh *headingHold) offset(driftDegrees, meanRPM float64) float64 {
+	offset := hh.cfg.Kp * driftDegrees
+	limit := math.Abs(meanRPM)
+	return math.Max(-limit, math.Min(offset, limit))
+}
+
+// start returns the heading the base is at, to be held.
+func (hh *headingHold) start(ctx context.Context) (*heldHeading, error) {
+	held := &heldHeading{hold: hh}
+	if hh.useGyro {
+		return held, nil
+	}
+	yaw, err := hh.yaw(ctx)
+	if err != nil {
+		return nil, err
+	}
+	held.startYaw = yaw
+	return held, nil
+}
+
+// yaw returns the counterclockwise heading of the base in degrees, from the orientation of the movement sensor.
+func (hh *headingHold) yaw(ctx context.Context) (float64, error) {
+	orientation, err := hh.sensor.Orientation(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	return rdkutils.RadToDeg(orientation.EulerAngles().Yaw), nil
+}
+
+// heldHeading tracks how far the base has drifted from the heading it is holding.
+type heldHeading struct {
+	hold     *headingHold
+	startYaw float64
+	gyroYaw  float64
+}
+
+// drift returns how many degrees counterclockwise, in the range [-180, 180], the base has turned from the heading it is holding,
+// integrating the gyro, in degrees per second, over the given number of seconds since it was last measured.
+func (h *heldHeading) drift(ctx context.Context, dt float64) (float64, error) {
+	if h.hold.useGyro {
+		angular, err := h.hold.sensor.AngularVelocity(ctx, nil)
+		if err != nil {
+			return 0, err
+		}
+		h.gyroYaw += angular.Z * dt
+		return math.Remainder(h.gyroYaw, 360), nil
+	}
+	yaw, err := h.hold.yaw(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return math.Remainder(yaw-h.startYaw, 360), nil
+}
+
+// holdsHeading returns whether the base holds its heading while driving straight.
+func (wb *wheeledBase) holdsHeading() bool {
+	wb.mu.Lock()
+	defer wb.mu.Unlock()
+	return wb.headingHold != nil
+}
+
+// moveStraightHoldingHeading drives the base at the rpm until its wheels have turned the given number of revolutions, holding its
+// heading, then stops it. How far the wheels have turned is measured by the encoders of their motors if all of them report their
+// positions, and otherwise estimated from how long they have been driven. Callers must register an operation via `wb.opMgr.New`.
+func (wb *wheeledBase) moveStraightHoldingHeading(ctx context.Context, rpm, rotations float64) error {
+	if rotations < 0 {
+		rpm, rotations = -rpm, -rotations
+	}
+	wb.mu.Lock()
+	motors := append(append([]motor.Motor{}, wb.left...), wb.right...)
+	period := wb.headingHold.period()
+	wb.mu.Unlock()
+	start, err := meanPosition(ctx, motors)
+	usePositions := err == nil
+	// traveled returns how many revolutions the wheels have turned since the start
+	traveled := func() (float64, error) {
+		position, err := meanPosition(ctx, motors)
+		return math.Abs(position - start), err
+	}
+	duration := time.Duration(rotations / math.Abs(rpm) * float64(time.Minute))
+
+	if err := wb.runAllSetRPM(ctx, rpm, rpm); err != nil {
+		return err
+	}
+	corrections, err := wb.startCorrections(ctx, rpm, rpm, true)
+	if err != nil {
+		return multierr.Combine(err, wb.Stop(ctx, nil))
+	}
+	if corrections == nil {
+		// the move was cancelled as it started
+		return wb.Stop(context.Background(), nil)
+	}
+	started := time.Now()
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			// the motors are stopped with a fresh context, as that of the move is done
+			wb.logger.CWarn(ctx, "Context cancelled during MoveStraight ", ctx.Err())
+			return wb.Stop(context.Background(), nil)
+		case <-corrections.Done():
+			// the base was stopped or given another command
+			return nil
+		case now := <-ticker.C:
+			if !usePositions {
+				if now.Sub(started) >= duration {
+					return wb.Stop(ctx, nil)
+				}
+				continue
+			}
+			revolutions, err := traveled()
+			if err != nil {
+				return multierr.Combine(err, wb.Stop(ctx, nil))
+			}
+			if revolutions >= rotations {
+				return wb.Stop(ctx, nil)
+			}
+		}
+	}
+}
+
+// meanPosition returns the mean of the positions, in revolutions, reported by the motors.
+func meanPosition(ctx context.Context, motors []motor.Motor) (float64, error) {
+	return (&wheelSide{motors: motors}).position(ctx)
+}
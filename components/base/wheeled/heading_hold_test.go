@@ -0,0 +1,147 @@
+package wheeled
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+	"go.viam.com/utils/testutils"
+
+	"go.viam.com/rdk/components/motor"
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestHeadingHoldOffset(t *testing.T) {
+	hold := &headingHold{cfg: &HeadingHoldConfig{MovementSensor: "imu", Kp: 2}}
+	test.That(t, hold.offset(3, 60), test.ShouldEqual, 6)
+	test.That(t, hold.offset(-3, -60), test.ShouldEqual, -6)
+	// neither side is driven backwards to turn the base back
+	test.That(t, hold.offset(90, 60), test.ShouldEqual, 60)
+	test.That(t, hold.offset(-90, -60), test.ShouldEqual, -60)
+
+	test.That(t, hold.cfg.Validate(), test.ShouldBeNil)
+	test.That(t, (&HeadingHoldConfig{Kp: 2}).Validate(), test.ShouldNotBeNil)
+	test.That(t, (&HeadingHoldConfig{MovementSensor: "imu"}).Validate(), test.ShouldNotBeNil)
+	test.That(t, (&HeadingHoldConfig{MovementSensor: "imu", Kp: 2, FrequencyHz: -1}).Validate(), test.ShouldNotBeNil)
+}
+
+func TestHeldHeadingDrift(t *testing.T) {
+	ctx := context.Background()
+	var yawDeg, gyroDegsPerSec float64
+	sensor := inject.NewMovementSensor("imu")
+	sensor.OrientationFunc = func(ctx context.Context, extra map[string]interface{}) (spatialmath.Orientation, error) {
+		return &spatialmath.OrientationVectorDegrees{OZ: 1, Theta: yawDeg}, nil
+	}
+	sensor.AngularVelocityFunc = func(ctx context.Context, extra map[string]interface{}) (spatialmath.AngularVelocity, error) {
+		return spatialmath.AngularVelocity{Z: gyroDegsPerSec}, nil
+	}
+
+	// headings held from the orientation of the sensor are relative to where the base started, wrapping around
+	yawDeg = 170
+	held, err := (&headingHold{sensor: sensor}).start(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	yawDeg = -175
+	drift, err := held.drift(ctx, 0.1)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, drift, test.ShouldAlmostEqual, 15)
+
+	// headings held with the gyro integrate it
+	held, err = (&headingHold{sensor: sensor, useGyro: true}).start(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	gyroDegsPerSec = -20
+	for i := 0; i < 3; i++ {
+		drift, err = held.drift(ctx, 0.1)
+		test.That(t, err, test.ShouldBeNil)
+	}
+	test.That(t, drift, test.ShouldAlmostEqual, -6)
+}
+
+func TestHeadingHold(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	conf := newTestCfg()
+	conf.ConvertedAttributes.(*Config).HeadingHold = &HeadingHoldConfig{MovementSensor: "imu", Kp: 1, FrequencyHz: 100}
+	deps, err := conf.Validate("path", resource.APITypeComponentName)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, deps, test.ShouldContain, "imu")
+
+	// a base veering counterclockwise at 10 degrees per second
+	sensor := inject.NewMovementSensor("imu")
+	sensor.PropertiesFunc = func(ctx context.Context, extra map[string]interface{}) (*movementsensor.Properties, error) {
+		return &movementsensor.Properties{AngularVelocitySupported: true}, nil
+	}
+	sensor.AngularVelocityFunc = func(ctx context.Context, extra map[string]interface{}) (spatialmath.AngularVelocity, error) {
+		return spatialmath.AngularVelocity{Z: 10}, nil
+	}
+	var mu sync.Mutex
+	rpms := map[string]float64{}
+	resourceDeps := resource.Dependencies{movementsensor.Named("imu"): sensor}
+	for _, name := range []string{"fl-m", "bl-m", "fr-m", "br-m"} {
+		m := inject.NewMotor(name)
+		name := name
+		m.SetRPMFunc = func(ctx context.Context, rpm float64, extra map[string]interface{}) error {
+			mu.Lock()
+			defer mu.Unlock()
+			rpms[name] = rpm
+			return nil
+		}
+		m.StopFunc = func(ctx context.Context, extra map[string]interface{}) error {
+			mu.Lock()
+			defer mu.Unlock()
+			rpms[name] = 0
+			return nil
+		}
+		m.PositionFunc = func(ctx context.Context, extra map[string]interface{}) (float64, error) {
+			return 0, motor.NewPropertyUnsupportedError(motor.Properties{}, name)
+		}
+		resourceDeps[motor.Named(name)] = m
+	}
+	b, err := createWheeledBase(ctx, resourceDeps, conf, logger)
+	test.That(t, err, test.ShouldBeNil)
+
+	t.Run("straight velocities hold the heading", func(t *testing.T) {
+		test.That(t, b.SetVelocity(ctx, r3.Vector{Y: 100}, r3.Vector{}, nil), test.ShouldBeNil)
+		testutils.WaitForAssertion(t, func(tb testing.TB) {
+			mu.Lock()
+			defer mu.Unlock()
+			test.That(tb, rpms["fl-m"], test.ShouldBeGreaterThan, rpms["fr-m"])
+			test.That(tb, rpms["bl-m"], test.ShouldBeGreaterThan, rpms["br-m"])
+		})
+		test.That(t, b.Stop(ctx, nil), test.ShouldBeNil)
+
+		// turning is left to the caller
+		test.That(t, b.SetVelocity(ctx, r3.Vector{Y: 100}, r3.Vector{Z: 10}, nil), test.ShouldBeNil)
+		time.Sleep(50 * time.Millisecond)
+		left, right := b.(*wheeledBase).velocityMath(100, 10)
+		mu.Lock()
+		test.That(t, rpms["fl-m"], test.ShouldEqual, left)
+		test.That(t, rpms["fr-m"], test.ShouldEqual, right)
+		mu.Unlock()
+		test.That(t, b.Stop(ctx, nil), test.ShouldBeNil)
+	})
+
+	t.Run("straight moves hold the heading for as long as they take", func(t *testing.T) {
+		start := time.Now()
+		// 100mm at 1000mm/s over wheels without encoders
+		test.That(t, b.MoveStraight(ctx, 100, 1000, nil), test.ShouldBeNil)
+		test.That(t, time.Since(start), test.ShouldBeGreaterThanOrEqualTo, 100*time.Millisecond)
+		mu.Lock()
+		test.That(t, rpms["fl-m"], test.ShouldEqual, 0)
+		test.That(t, rpms["fr-m"], test.ShouldEqual, 0)
+		mu.Unlock()
+	})
+
+	t.Run("heading is held with sensors which can measure it", func(t *testing.T) {
+		sensor.PropertiesFunc = func(ctx context.Context, extra map[string]interface{}) (*movementsensor.Properties, error) {
+			return &movementsensor.Properties{LinearVelocitySupported: true}, nil
+		}
+		test.That(t, b.Reconfigure(ctx, resourceDeps, conf), test.ShouldNotBeNil)
+	})
+}
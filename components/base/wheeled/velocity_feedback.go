@@ -0,0 +1,226 @@
+package wheeled
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/pkg/errors"
+	goutils "go.viam.com/utils"
+
+	"go.viam.com/rdk/components/motor"
+)
+
+// defaultFeedbackFrequencyHz is how often wheel velocities are measured and corrected if no frequency is configured.
+const defaultFeedbackFrequencyHz = 20.
+
+// VelocityFeedbackConfig configures closed-loop control of the velocities SetVelocity drives the wheels at. The velocity of each
+// side is measured from the positions reported by its motors' encoders, and the rpm commanded to it is corrected by a PI
+// controller, so that the base tracks the velocities it is given on carpet and ramps where the motors alone fall short.
+type VelocityFeedbackConfig struct {
+	// Kp is the rpm the commanded rpm of a side is raised by for each rpm its wheels are measured below the target.
+	Kp float64 `json:"kp"`
+	// Ki is the rpm the commanded rpm of a side is raised by for each revolution its wheels have fallen behind the target.
+	Ki float64 `json:"ki"`
+	// FrequencyHz is how often the velocities of the wheels are measured and corrected. Defaults to 20Hz.
+	FrequencyHz float64 `json:"frequency_hz,omitempty"`
+}
+
+// Validate ensures the gains and frequency of the velocity feedback are usable.
+func (cfg *VelocityFeedbackConfig) Validate() error {
+	if cfg.Kp < 0 || cfg.Ki < 0 {
+		return errors.New("velocity_feedback gains may not be negative")
+	}
+	if cfg.Kp == 0 && cfg.Ki == 0 {
+		return errors.New("velocity_feedback needs a nonzero kp or ki")
+	}
+	if cfg.FrequencyHz < 0 {
+		return errors.New("velocity_feedback frequency_hz may not be negative")
+	}
+	return nil
+}
+
+func (cfg *VelocityFeedbackConfig) period() time.Duration {
+	hz := cfg.FrequencyHz
+	if hz == 0 {
+		hz = defaultFeedbackFrequencyHz
+	}
+	return time.Duration(float64(time.Second) / hz)
+}
+
+// wheelSide tracks the velocity of the motors on one side of the base against the rpm they are meant to turn at.
+type wheelSide struct {
+	motors []motor.Motor
+	// baseRPM is the rpm the side is commanded at, and targetRPM that rpm with any correction to hold the heading of the base
+	baseRPM      float64
+	targetRPM    float64
+	commandedRPM float64
+	integral     float64
+	revolutions  float64
+}
+
+// position returns the mean of the positions, in revolutions, reported by the motors of the side.
+func (s *wheelSide) position(ctx context.Context) (float64, error) {
+	var sum float64
+	for _, m := range s.motors {
+		revolutions, err := m.Position(ctx, nil)
+		if err != nil {
+			return 0, err
+		}
+		sum += revolutions
+	}
+	return sum / float64(len(s.motors)), nil
+}
+
+// correct returns the rpm to command the side at after its wheels have turned to the given position over the given number of
+// seconds. The correction is bounded by the target, so that the side is never driven backwards nor at more than twice the target;
+// while it is saturated the integral does not grow.
+func (s *wheelSide) correct(revolutions, dt float64, cfg *VelocityFeedbackConfig) float64 {
+	measuredRPM := (revolutions - s.revolutions) / dt * 60
+	s.revolutions = revolutions
+	rpmErr := s.targetRPM - measuredRPM
+	integral := s.integral + rpmErr*dt/60
+	correction := cfg.Kp*rpmErr + cfg.Ki*integral
+	limit := math.Abs(s.targetRPM)
+	if math.Abs(correction) > limit {
+		correction = math.Copysign(limit, correction)
+	} else {
+		s.integral = integral
+	}
+	s.commandedRPM = s.targetRPM + correction
+	return s.commandedRPM
+}
+
+// checkVelocityFeedback returns an error if any of the motors cannot report the positions velocity feedback needs.
+func checkVelocityFeedback(ctx context.Context, motors []motor.Motor) error {
+	for _, m := range motors {
+		properties, err := m.Properties(ctx, nil)
+		if err != nil {
+			return err
+		}
+		if !properties.PositionReporting {
+			return fmt.Errorf("velocity_feedback requires motors which report their position, %s does not", m.Name().ShortName())
+		}
+	}
+	return nil
+}
+
+// startCorrections corrects the rpms of the sides in the background, from the measured velocities of the wheels if velocity feedback
+// is configured and to hold the heading of the base if holdHeading is set and heading hold is configured, until stopCorrections is
+// called. The returned context, if any, is done once the corrections are stopped.
+func (wb *wheeledBase) startCorrections(
+	ctx context.Context,
+	leftRPM, rightRPM float64,
+	holdHeading bool,
+) (context.Context, error) {
+	wb.mu.Lock()
+	feedback := wb.velocityFeedback
+	hold := wb.headingHold
+	sides := []*wheelSide{
+		{motors: wb.left, baseRPM: leftRPM, targetRPM: leftRPM, commandedRPM: leftRPM},
+		{motors: wb.right, baseRPM: rightRPM, targetRPM: rightRPM, commandedRPM: rightRPM},
+	}
+	wb.mu.Unlock()
+	if !holdHeading {
+		hold = nil
+	}
+	// a cancelled command leaves nothing to correct
+	if (feedback == nil && hold == nil) || ctx.Err() != nil {
+		return nil, nil
+	}
+	if feedback != nil {
+		for _, side := range sides {
+			revolutions, err := side.position(ctx)
+			if err != nil {
+				return nil, err
+			}
+			side.revolutions = revolutions
+		}
+	}
+	var heading *heldHeading
+	if hold != nil {
+		var err error
+		if heading, err = hold.start(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	correctionCtx, cancel := context.WithCancel(context.Background())
+	wb.correctionMu.Lock()
+	wb.correctionCancel = cancel
+	wb.correctionMu.Unlock()
+	wb.correctionWorkers.Add(1)
+	goutils.ManagedGo(func() {
+		wb.runCorrections(correctionCtx, feedback, heading, sides)
+	}, wb.correctionWorkers.Done)
+	return correctionCtx, nil
+}
+
+// runCorrections corrects the rpm of each side until the context is done, or until the motors or movement sensor fail, in which
+// case the motors are left at the rpms last commanded.
+func (wb *wheeledBase) runCorrections(ctx context.Context, feedback *VelocityFeedbackConfig, heading *heldHeading, sides []*wheelSide) {
+	period := time.Duration(float64(time.Second) / defaultFeedbackFrequencyHz)
+	if feedback != nil {
+		period = feedback.period()
+	} else if heading != nil {
+		period = heading.hold.period()
+	}
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	last := time.Now()
+	stop := func(err error) {
+		if ctx.Err() == nil {
+			wb.logger.CWarnf(ctx, "stopping corrections of base %s: %v", wb.Name().ShortName(), err)
+		}
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			dt := now.Sub(last).Seconds()
+			last = now
+			// a base which has drifted counterclockwise is turned back by driving its left side faster than its right
+			var offset float64
+			if heading != nil {
+				drift, err := heading.drift(ctx, dt)
+				if err != nil {
+					stop(err)
+					return
+				}
+				offset = heading.hold.offset(drift, (sides[0].baseRPM+sides[1].baseRPM)/2)
+			}
+			sides[0].targetRPM = sides[0].baseRPM + offset
+			sides[1].targetRPM = sides[1].baseRPM - offset
+			for _, side := range sides {
+				rpm := side.targetRPM
+				if feedback != nil {
+					revolutions, err := side.position(ctx)
+					if err != nil {
+						stop(err)
+						return
+					}
+					rpm = side.correct(revolutions, dt, feedback)
+				}
+				for _, m := range side.motors {
+					if err := m.SetRPM(ctx, rpm, nil); err != nil {
+						stop(err)
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+// stopCorrections stops correcting the rpms of the sides, if they are being corrected, and waits for the correction to end.
+func (wb *wheeledBase) stopCorrections() {
+	wb.correctionMu.Lock()
+	if wb.correctionCancel != nil {
+		wb.correctionCancel()
+		wb.correctionCancel = nil
+	}
+	wb.correctionMu.Unlock()
+	wb.correctionWorkers.Wait()
+}
@@ -0,0 +1,147 @@
+package wheeled
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+	"go.viam.com/utils/testutils"
+
+	"go.viam.com/rdk/components/motor"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+// slippingMotor is a motor on carpet, whose wheel turns at only half the rpm it is commanded.
+type slippingMotor struct {
+	mu          sync.Mutex
+	rpm         float64
+	revolutions float64
+	at          time.Time
+	setRPMs     int
+}
+
+func (sm *slippingMotor) advance() {
+	now := time.Now()
+	if !sm.at.IsZero() {
+		sm.revolutions += sm.rpm / 2 * now.Sub(sm.at).Minutes()
+	}
+	sm.at = now
+}
+
+func (sm *slippingMotor) inject(name string, positionReporting bool) *inject.Motor {
+	m := inject.NewMotor(name)
+	m.PropertiesFunc = func(ctx context.Context, extra map[string]interface{}) (motor.Properties, error) {
+		return motor.Properties{PositionReporting: positionReporting}, nil
+	}
+	m.PositionFunc = func(ctx context.Context, extra map[string]interface{}) (float64, error) {
+		sm.mu.Lock()
+		defer sm.mu.Unlock()
+		sm.advance()
+		return sm.revolutions, nil
+	}
+	m.SetRPMFunc = func(ctx context.Context, rpm float64, extra map[string]interface{}) error {
+		sm.mu.Lock()
+		defer sm.mu.Unlock()
+		sm.advance()
+		sm.rpm = rpm
+		sm.setRPMs++
+		return nil
+	}
+	m.StopFunc = func(ctx context.Context, extra map[string]interface{}) error {
+		sm.mu.Lock()
+		defer sm.mu.Unlock()
+		sm.advance()
+		sm.rpm = 0
+		return nil
+	}
+	return m
+}
+
+func TestWheelSideCorrect(t *testing.T) {
+	cfg := &VelocityFeedbackConfig{Kp: 0.5, Ki: 1}
+	side := &wheelSide{targetRPM: 60}
+
+	// wheels turning at the target are left alone
+	test.That(t, side.correct(1, 1, cfg), test.ShouldAlmostEqual, 60)
+
+	// wheels slipping to half the target are driven harder, more so the longer they fall behind
+	test.That(t, side.correct(1.5, 1, cfg), test.ShouldAlmostEqual, 60+0.5*30+0.5)
+	test.That(t, side.correct(2, 1, cfg), test.ShouldAlmostEqual, 60+0.5*30+1)
+
+	// but never at more than twice the target, nor backwards when they overshoot
+	side = &wheelSide{targetRPM: 60}
+	stiff := &VelocityFeedbackConfig{Kp: 2, Ki: 1}
+	test.That(t, side.correct(0, 1, stiff), test.ShouldAlmostEqual, 120)
+	test.That(t, side.integral, test.ShouldEqual, 0)
+	test.That(t, side.correct(5, 1, stiff), test.ShouldAlmostEqual, 0)
+
+	// reversing sides are corrected the other way
+	side = &wheelSide{targetRPM: -60}
+	test.That(t, side.correct(-0.5, 1, cfg), test.ShouldAlmostEqual, -60-0.5*30-0.5)
+}
+
+func TestVelocityFeedbackValidate(t *testing.T) {
+	test.That(t, (&VelocityFeedbackConfig{Kp: 1}).Validate(), test.ShouldBeNil)
+	test.That(t, (&VelocityFeedbackConfig{Ki: 1, FrequencyHz: 50}).Validate(), test.ShouldBeNil)
+	test.That(t, (&VelocityFeedbackConfig{}).Validate(), test.ShouldNotBeNil)
+	test.That(t, (&VelocityFeedbackConfig{Kp: -1, Ki: 1}).Validate(), test.ShouldNotBeNil)
+	test.That(t, (&VelocityFeedbackConfig{Kp: 1, FrequencyHz: -1}).Validate(), test.ShouldNotBeNil)
+
+	cfg := newTestCfg().ConvertedAttributes.(*Config)
+	cfg.VelocityFeedback = &VelocityFeedbackConfig{}
+	_, err := cfg.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestVelocityFeedback(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	conf := newTestCfg()
+	conf.ConvertedAttributes.(*Config).VelocityFeedback = &VelocityFeedbackConfig{Kp: 0.5, Ki: 2, FrequencyHz: 100}
+	motors := map[string]*slippingMotor{}
+	deps := resource.Dependencies{}
+	for _, name := range []string{"fl-m", "bl-m", "fr-m", "br-m"} {
+		motors[name] = &slippingMotor{}
+		deps[motor.Named(name)] = motors[name].inject(name, true)
+	}
+
+	b, err := createWheeledBase(ctx, deps, conf, logger)
+	test.That(t, err, test.ShouldBeNil)
+	wb := b.(*wheeledBase)
+	leftRPM, rightRPM := wb.velocityMath(100, 0)
+
+	test.That(t, b.SetVelocity(ctx, r3.Vector{Y: 100}, r3.Vector{}, nil), test.ShouldBeNil)
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		for name, sm := range motors {
+			sm.mu.Lock()
+			rpm := sm.rpm
+			sm.mu.Unlock()
+			target := leftRPM
+			if name == "fr-m" || name == "br-m" {
+				target = rightRPM
+			}
+			test.That(tb, rpm, test.ShouldBeGreaterThan, target*1.2)
+		}
+	})
+
+	// the next command to the base ends the feedback
+	test.That(t, b.Stop(ctx, nil), test.ShouldBeNil)
+	sm := motors["fl-m"]
+	sm.mu.Lock()
+	setRPMs := sm.setRPMs
+	sm.mu.Unlock()
+	time.Sleep(50 * time.Millisecond)
+	sm.mu.Lock()
+	test.That(t, sm.setRPMs, test.ShouldEqual, setRPMs)
+	test.That(t, sm.rpm, test.ShouldEqual, 0)
+	sm.mu.Unlock()
+
+	// motors without encoders cannot give feedback
+	deps[motor.Named("br-m")] = (&slippingMotor{}).inject("br-m", false)
+	test.That(t, wb.Reconfigure(ctx, deps, conf), test.ShouldNotBeNil)
+}
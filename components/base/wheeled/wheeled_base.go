@@ -13,6 +13,13 @@ package wheeled
    Adding a movementsensor that supports Orientation provides feedback to a Spin command to correct the heading. As of
    June 2023, this feature is experimental.
 
+   Configuring velocity_feedback for motors with encoders closes the loop on SetVelocity, correcting the rpm of each side from
+   the velocity its wheels are measured at, so that the base keeps to its trajectories on carpet and ramps.
+
+   Configuring heading_hold with a movement sensor that has a gyro or orientation holds the heading of the base while it drives
+   straight, with MoveStraight or with SetVelocity without an angular velocity, by driving one side faster than the other as the
+   base drifts.
+
    Configuring a base with a frame will create a kinematic base that can be used by Viam's motion service to plan paths
    when a SLAM service is also present. As of June 2023 This feature is experimental.
    Example Config:
@@ -43,6 +50,7 @@ import (
 
 	"go.viam.com/rdk/components/base"
 	"go.viam.com/rdk/components/motor"
+	"go.viam.com/rdk/components/movementsensor"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/operation"
 	"go.viam.com/rdk/resource"
@@ -60,6 +68,13 @@ type Config struct {
 	SpinSlipFactor       float64  `json:"spin_slip_factor,omitempty"`
 	Left                 []string `json:"left"`
 	Right                []string `json:"right"`
+
+	// VelocityFeedback, if set, corrects the velocities SetVelocity drives the wheels at using the positions reported by the
+	// encoders of the motors, all of which must report their positions.
+	VelocityFeedback *VelocityFeedbackConfig `json:"velocity_feedback,omitempty"`
+
+	// HeadingHold, if set, holds the heading of the base with a movement sensor while it drives straight.
+	HeadingHold *HeadingHoldConfig `json:"heading_hold,omitempty"`
 }
 
 // Validate ensures all parts of the config are valid.
@@ -87,6 +102,19 @@ func (cfg *Config) Validate(path string) ([]string, error) {
 				len(cfg.Left), len(cfg.Right)))
 	}
 
+	if cfg.VelocityFeedback != nil {
+		if err := cfg.VelocityFeedback.Validate(); err != nil {
+			return nil, resource.NewConfigValidationError(path, err)
+		}
+	}
+
+	if cfg.HeadingHold != nil {
+		if err := cfg.HeadingHold.Validate(); err != nil {
+			return nil, resource.NewConfigValidationError(path, err)
+		}
+		deps = append(deps, cfg.HeadingHold.MovementSensor)
+	}
+
 	deps = append(deps, cfg.Left...)
 	deps = append(deps, cfg.Right...)
 
@@ -113,10 +141,19 @@ type wheeledBase struct {
 
 	mu   sync.Mutex
 	name string
+
+	// velocityFeedback and headingHold, if set, configure the correction of the rpms the base drives at, which runs in the background
+	// until the next command to the base
+	velocityFeedback  *VelocityFeedbackConfig
+	headingHold       *headingHold
+	correctionMu      sync.Mutex
+	correctionCancel  context.CancelFunc
+	correctionWorkers sync.WaitGroup
 }
 
 // Reconfigure reconfigures the base atomically and in place.
 func (wb *wheeledBase) Reconfigure(ctx context.Context, deps resource.Dependencies, conf resource.Config) error {
+	wb.stopCorrections()
 	wb.mu.Lock()
 	defer wb.mu.Unlock()
 
@@ -194,6 +231,26 @@ func (wb *wheeledBase) Reconfigure(ctx context.Context, deps resource.Dependenci
 	wb.allMotors = append(wb.allMotors, wb.left...)
 	wb.allMotors = append(wb.allMotors, wb.right...)
 
+	if newConf.VelocityFeedback != nil {
+		for _, motors := range [][]motor.Motor{wb.left, wb.right} {
+			if err := checkVelocityFeedback(ctx, motors); err != nil {
+				return err
+			}
+		}
+	}
+	wb.velocityFeedback = newConf.VelocityFeedback
+
+	wb.headingHold = nil
+	if newConf.HeadingHold != nil {
+		sensor, err := movementsensor.FromDependencies(deps, newConf.HeadingHold.MovementSensor)
+		if err != nil {
+			return errors.Wrapf(err, "no movement sensor named (%s)", newConf.HeadingHold.MovementSensor)
+		}
+		if wb.headingHold, err = newHeadingHold(ctx, newConf.HeadingHold, sensor); err != nil {
+			return err
+		}
+	}
+
 	if wb.widthMm != newConf.WidthMM {
 		wb.widthMm = newConf.WidthMM
 	}
@@ -236,6 +293,7 @@ func createWheeledBase(
 
 // Spin commands a base to turn about its center at a angular speed and for a specific angle.
 func (wb *wheeledBase) Spin(ctx context.Context, angleDeg, degsPerSec float64, extra map[string]interface{}) error {
+	wb.stopCorrections()
 	ctx, done := wb.opMgr.New(ctx)
 	defer done()
 	wb.logger.CDebugf(ctx, "received a Spin with angleDeg:%.2f, degsPerSec:%.2f", angleDeg, degsPerSec)
@@ -276,8 +334,12 @@ func (wb *wheeledBase) MoveStraight(ctx context.Context, distanceMm int, mmPerSe
 	rpm, rotations := wb.straightDistanceToMotorInputs(distanceMm, mmPerSec)
 
 	// start new operation after all calculations are made
+	wb.stopCorrections()
 	ctx, done := wb.opMgr.New(ctx)
 	defer done()
+	if wb.holdsHeading() {
+		return wb.moveStraightHoldingHeading(ctx, rpm, rotations)
+	}
 	return wb.runAllGoFor(ctx, rpm, rotations, rpm, rotations)
 }
 
@@ -422,13 +484,20 @@ func (wb *wheeledBase) SetVelocity(ctx context.Context, linear, angular r3.Vecto
 	leftRPM, rightRPM := wb.velocityMath(linear.Y, angular.Z)
 
 	// start new operation after all calculations are made
+	wb.stopCorrections()
 	ctx, done := wb.opMgr.New(ctx)
 	defer done()
-	return wb.runAllSetRPM(ctx, leftRPM, rightRPM)
+	if err := wb.runAllSetRPM(ctx, leftRPM, rightRPM); err != nil {
+		return err
+	}
+	// the rpms are corrected until the next command, holding the heading of the base if it is to drive straight
+	_, err := wb.startCorrections(ctx, leftRPM, rightRPM, angular.Z == 0)
+	return err
 }
 
 // SetPower commands the base motors to run at powers corresponding to input linear and angular powers.
 func (wb *wheeledBase) SetPower(ctx context.Context, linear, angular r3.Vector, extra map[string]interface{}) error {
+	wb.stopCorrections()
 	wb.opMgr.CancelRunning(ctx)
 
 	wb.logger.CDebugf(ctx,
@@ -513,6 +582,7 @@ func (wb *wheeledBase) straightDistanceToMotorInputs(distanceMm int, mmPerSec fl
 
 // Stop commands the base to stop moving.
 func (wb *wheeledBase) Stop(ctx context.Context, extra map[string]interface{}) error {
+	wb.stopCorrections()
 	stopFuncs := func() []rdkutils.SimpleFunc {
 		ret := []rdkutils.SimpleFunc{}
 
@@ -0,0 +1,130 @@
+package transformpipeline
+
+import (
+	"context"
+	"image"
+
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/rimage"
+	"go.viam.com/rdk/rimage/transform"
+	"go.viam.com/rdk/utils"
+)
+
+// depthToPointCloudConfig are the attributes for a depth to point cloud transform.
+type depthToPointCloudConfig struct {
+	// Decimation keeps every Nth pixel of each row and column of the depth map, trading density for speed.
+	Decimation int `json:"decimation,omitempty"`
+	// MinRangeMM and MaxRangeMM drop the points closer and further than them, where a MaxRangeMM of 0 keeps every distant point.
+	MinRangeMM float64 `json:"min_range_mm,omitempty"`
+	MaxRangeMM float64 `json:"max_range_mm,omitempty"`
+}
+
+// depthToPointCloudSource passes depth images through unchanged, and projects them into point clouds with the intrinsics of
+// the source camera.
+type depthToPointCloudSource struct {
+	src        camera.VideoSource
+	intrinsics *transform.PinholeCameraIntrinsics
+	decimation int
+	minRange   rimage.Depth
+	maxRange   rimage.Depth
+}
+
+// newDepthToPointCloudTransform creates a new depth to point cloud transform.
+func newDepthToPointCloudTransform(
+	ctx context.Context, source camera.VideoSource, stream camera.ImageType, am utils.AttributeMap,
+) (camera.VideoSource, camera.ImageType, error) {
+	if stream == camera.ColorStream {
+		return nil, camera.UnspecifiedStream, errors.New("depth_to_pointcloud transform requires a depth stream")
+	}
+	conf, err := resource.TransformAttributeMap[*depthToPointCloudConfig](am)
+	if err != nil {
+		return nil, camera.UnspecifiedStream, err
+	}
+	if conf.Decimation < 0 || conf.MinRangeMM < 0 || conf.MaxRangeMM < 0 {
+		return nil, camera.UnspecifiedStream, errors.New("decimation, min_range_mm and max_range_mm cannot be negative")
+	}
+	if conf.MaxRangeMM != 0 && conf.MaxRangeMM <= conf.MinRangeMM {
+		return nil, camera.UnspecifiedStream, errors.New("max_range_mm must be greater than min_range_mm")
+	}
+	if conf.Decimation == 0 {
+		conf.Decimation = 1
+	}
+
+	props, err := propsFromVideoSource(ctx, source)
+	if err != nil {
+		return nil, camera.UnspecifiedStream, err
+	}
+	if props.IntrinsicParams == nil {
+		return nil, camera.UnspecifiedStream,
+			transform.NewNoIntrinsicsError("depth_to_pointcloud transform projects the depth images of its source")
+	}
+	var cameraModel transform.PinholeCameraModel
+	cameraModel.PinholeCameraIntrinsics = props.IntrinsicParams
+
+	if props.DistortionParams != nil {
+		cameraModel.Distortion = props.DistortionParams
+	}
+	reader := &depthToPointCloudSource{
+		src:        source,
+		intrinsics: props.IntrinsicParams,
+		decimation: conf.Decimation,
+		minRange:   rimage.Depth(conf.MinRangeMM),
+		maxRange:   rimage.Depth(conf.MaxRangeMM),
+	}
+	src, err := camera.NewVideoSourceFromReader(ctx, reader, &cameraModel, camera.DepthStream)
+	if err != nil {
+		return nil, camera.UnspecifiedStream, err
+	}
+	return src, camera.DepthStream, err
+}
+
+// Read returns the depth image of the source.
+func (ds *depthToPointCloudSource) Read(ctx context.Context) (image.Image, func(), error) {
+	ctx, span := trace.StartSpan(ctx, "camera::transformpipeline::depth_to_pointcloud::Read")
+	defer span.End()
+	return camera.ReadImage(ctx, ds.src)
+}
+
+// NextPointCloud projects the pixels of the next depth image which are within range into a point cloud.
+func (ds *depthToPointCloudSource) NextPointCloud(ctx context.Context) (pointcloud.PointCloud, error) {
+	ctx, span := trace.StartSpan(ctx, "camera::transformpipeline::depth_to_pointcloud::NextPointCloud")
+	defer span.End()
+	img, release, err := camera.ReadImage(ctx, ds.src)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	dm, err := rimage.ConvertImageToDepthMap(ctx, img)
+	if err != nil {
+		return nil, errors.Wrap(err, "depth_to_pointcloud transform requires depth images")
+	}
+	cols := (dm.Width() + ds.decimation - 1) / ds.decimation
+	rows := (dm.Height() + ds.decimation - 1) / ds.decimation
+	cloud := pointcloud.NewWithPrealloc(cols * rows)
+	for y := 0; y < dm.Height(); y += ds.decimation {
+		for x := 0; x < dm.Width(); x += ds.decimation {
+			d := dm.GetDepth(x, y)
+			// a depth of 0 is a pixel the camera could not measure
+			if d == 0 || d < ds.minRange || (ds.maxRange != 0 && d > ds.maxRange) {
+				continue
+			}
+			pt, err := ds.intrinsics.ImagePointTo3DPoint(image.Pt(x, y), d)
+			if err != nil {
+				return nil, err
+			}
+			if err := cloud.Set(pt, nil); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return cloud, nil
+}
+
+func (ds *depthToPointCloudSource) Close(ctx context.Context) error {
+	return nil
+}
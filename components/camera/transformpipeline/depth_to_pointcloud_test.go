@@ -0,0 +1,114 @@
+package transformpipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"github.com/pion/mediadevices/pkg/prop"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/components/camera/fake"
+	"go.viam.com/rdk/gostream"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/rimage"
+	"go.viam.com/rdk/rimage/transform"
+	"go.viam.com/rdk/testutils/inject"
+	"go.viam.com/rdk/utils"
+)
+
+func TestDepthToPointCloud(t *testing.T) {
+	ctx := context.Background()
+	intrinsics := &transform.PinholeCameraIntrinsics{Width: 4, Height: 4, Fx: 1, Fy: 1, Ppx: 0, Ppy: 0}
+	// a 4x4 depth map whose depth increases along each row, with one pixel that could not be measured
+	dm := rimage.NewEmptyDepthMap(4, 4)
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			dm.Set(x, y, rimage.Depth(1000*(x+1)))
+		}
+	}
+	dm.Set(0, 0, 0)
+	source := gostream.NewVideoSource(&fake.StaticSource{DepthImg: dm}, prop.Video{})
+	defer func() {
+		test.That(t, source.Close(ctx), test.ShouldBeNil)
+	}()
+	src, err := camera.WrapVideoSourceWithProjector(ctx, source,
+		&transform.PinholeCameraModel{PinholeCameraIntrinsics: intrinsics}, camera.DepthStream)
+	test.That(t, err, test.ShouldBeNil)
+	noIntrinsics, err := camera.WrapVideoSourceWithProjector(ctx, source, nil, camera.DepthStream)
+	test.That(t, err, test.ShouldBeNil)
+
+	for _, am := range []utils.AttributeMap{
+		{"decimation": -1},
+		{"min_range_mm": -1},
+		{"min_range_mm": 2000, "max_range_mm": 1000},
+	} {
+		_, _, err := newDepthToPointCloudTransform(ctx, src, camera.DepthStream, am)
+		test.That(t, err, test.ShouldNotBeNil)
+	}
+	_, _, err = newDepthToPointCloudTransform(ctx, src, camera.ColorStream, utils.AttributeMap{})
+	test.That(t, err, test.ShouldNotBeNil)
+	_, _, err = newDepthToPointCloudTransform(ctx, noIntrinsics, camera.DepthStream, utils.AttributeMap{})
+	test.That(t, err, test.ShouldWrap, transform.ErrNoIntrinsics)
+
+	nextPointCloud := func(t *testing.T, am utils.AttributeMap) pointcloud.PointCloud {
+		t.Helper()
+		pcSrc, stream, err := newDepthToPointCloudTransform(ctx, src, camera.DepthStream, am)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, stream, test.ShouldEqual, camera.DepthStream)
+		// depth images are passed through unchanged
+		out, _, err := camera.ReadImage(ctx, pcSrc)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, out.Bounds().Dx(), test.ShouldEqual, 4)
+		cloud, err := pcSrc.NextPointCloud(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, pcSrc.Close(ctx), test.ShouldBeNil)
+		return cloud
+	}
+
+	cloud := nextPointCloud(t, utils.AttributeMap{})
+	test.That(t, cloud.Size(), test.ShouldEqual, 15)
+	_, ok := cloud.At(3*4000, 2*4000, 4000)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	// every other pixel of every other row is kept
+	cloud = nextPointCloud(t, utils.AttributeMap{"decimation": 2})
+	test.That(t, cloud.Size(), test.ShouldEqual, 3)
+	for _, p := range []r3.Vector{{X: 6000, Y: 0, Z: 3000}, {X: 0, Y: 2000, Z: 1000}, {X: 6000, Y: 6000, Z: 3000}} {
+		_, ok := cloud.At(p.X, p.Y, p.Z)
+		test.That(t, ok, test.ShouldBeTrue)
+	}
+
+	cloud = nextPointCloud(t, utils.AttributeMap{"min_range_mm": 1500, "max_range_mm": 3000})
+	test.That(t, cloud.Size(), test.ShouldEqual, 8)
+}
+
+func TestDepthToPointCloudPipeline(t *testing.T) {
+	ctx := context.Background()
+	intrinsics := &transform.PinholeCameraIntrinsics{Width: 4, Height: 4, Fx: 1, Fy: 1, Ppx: 0, Ppy: 0}
+	dm := rimage.NewEmptyDepthMap(4, 4)
+	dm.Set(1, 1, 500)
+	source := gostream.NewVideoSource(&fake.StaticSource{DepthImg: dm}, prop.Video{})
+	defer func() {
+		test.That(t, source.Close(ctx), test.ShouldBeNil)
+	}()
+	src, err := camera.WrapVideoSourceWithProjector(ctx, source,
+		&transform.PinholeCameraModel{PinholeCameraIntrinsics: intrinsics}, camera.DepthStream)
+	test.That(t, err, test.ShouldBeNil)
+
+	transformConf := &transformConfig{
+		CameraParameters: intrinsics,
+		Source:           "source",
+		Pipeline:         []Transformation{{Type: "depth_to_pointcloud", Attributes: utils.AttributeMap{}}},
+	}
+	cam, err := newTransformPipeline(ctx, videoSourceFromCamera(ctx, src), nil, transformConf, &inject.Robot{}, logging.NewTestLogger(t))
+	test.That(t, err, test.ShouldBeNil)
+	cloud, err := cam.NextPointCloud(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cloud.Size(), test.ShouldEqual, 1)
+	_, ok := cloud.At(500, 500, 500)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, cam.Close(ctx), test.ShouldBeNil)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"image"
 	"image/color"
+	"math"
 
 	"github.com/disintegration/imaging"
 	"github.com/pkg/errors"
@@ -297,3 +298,184 @@ func (cs *cropSource) Read(ctx context.Context) (image.Image, func(), error) {
 func (cs *cropSource) Close(ctx context.Context) error {
 	return nil
 }
+
+// flipConfig are the attributes for a flip transform.
+type flipConfig struct {
+	Horizontal bool `json:"horizontal"`
+	Vertical   bool `json:"vertical"`
+}
+
+type flipSource struct {
+	src        camera.VideoSource
+	stream     camera.ImageType
+	horizontal bool
+	vertical   bool
+}
+
+// newFlipTransform creates a new flip transform.
+func newFlipTransform(
+	ctx context.Context, source camera.VideoSource, stream camera.ImageType, am utils.AttributeMap,
+) (camera.VideoSource, camera.ImageType, error) {
+	conf, err := resource.TransformAttributeMap[*flipConfig](am)
+	if err != nil {
+		return nil, camera.UnspecifiedStream, err
+	}
+	if !conf.Horizontal && !conf.Vertical {
+		return nil, camera.UnspecifiedStream, errors.New("flip transform must flip the image horizontally, vertically or both")
+	}
+
+	reader := &flipSource{source, stream, conf.Horizontal, conf.Vertical}
+	src, err := camera.NewVideoSourceFromReader(ctx, reader, nil, stream)
+	if err != nil {
+		return nil, camera.UnspecifiedStream, err
+	}
+	return src, stream, err
+}
+
+// Read mirrors the 2D image depending on the stream type.
+func (fs *flipSource) Read(ctx context.Context) (image.Image, func(), error) {
+	ctx, span := trace.StartSpan(ctx, "camera::transformpipeline::flip::Read")
+	defer span.End()
+	orig, release, err := camera.ReadImage(ctx, fs.src)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch fs.stream {
+	case camera.ColorStream, camera.UnspecifiedStream:
+		var flipped image.Image = orig
+		if fs.horizontal {
+			flipped = imaging.FlipH(flipped)
+		}
+		if fs.vertical {
+			flipped = imaging.FlipV(flipped)
+		}
+		return flipped, release, nil
+	case camera.DepthStream:
+		dm, err := rimage.ConvertImageToDepthMap(ctx, orig)
+		if err != nil {
+			return nil, nil, err
+		}
+		width, height := dm.Width(), dm.Height()
+		flipped := rimage.NewEmptyDepthMap(width, height)
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				srcX, srcY := x, y
+				if fs.horizontal {
+					srcX = width - 1 - x
+				}
+				if fs.vertical {
+					srcY = height - 1 - y
+				}
+				flipped.Set(x, y, dm.GetDepth(srcX, srcY))
+			}
+		}
+		return flipped, release, nil
+	default:
+		return nil, nil, camera.NewUnsupportedImageTypeError(fs.stream)
+	}
+}
+
+func (fs *flipSource) Close(ctx context.Context) error {
+	return nil
+}
+
+// perspectiveConfig are the attributes for a perspective transform.
+type perspectiveConfig struct {
+	// Corners are the [x, y] pixels of the top left, top right, bottom right and bottom left corners of the region to straighten.
+	Corners [][]float64 `json:"corners_px"`
+	// Height and Width are the size of the output image, which default to the lengths of the edges of the region.
+	Height int `json:"height_px,omitempty"`
+	Width  int `json:"width_px,omitempty"`
+}
+
+type perspectiveSource struct {
+	src     camera.VideoSource
+	stream  camera.ImageType
+	warp    rimage.TransformationMatrix
+	outSize image.Point
+}
+
+// newPerspectiveTransform creates a new perspective transform, which maps a four sided region of the image onto a rectangle.
+func newPerspectiveTransform(
+	ctx context.Context, source camera.VideoSource, stream camera.ImageType, am utils.AttributeMap,
+) (camera.VideoSource, camera.ImageType, error) {
+	conf, err := resource.TransformAttributeMap[*perspectiveConfig](am)
+	if err != nil {
+		return nil, camera.UnspecifiedStream, err
+	}
+	if len(conf.Corners) != 4 {
+		return nil, camera.UnspecifiedStream,
+			errors.Errorf("perspective transform needs the 4 corners of the region to straighten, got %d", len(conf.Corners))
+	}
+	// corners are ordered top left, top right, bottom left, bottom right to match the corners of the output image
+	corners := make([]image.Point, 0, 4)
+	for _, i := range []int{0, 1, 3, 2} {
+		corner := conf.Corners[i]
+		if len(corner) != 2 {
+			return nil, camera.UnspecifiedStream, errors.Errorf("perspective transform corner %d must be an [x, y] pair", i)
+		}
+		if corner[0] < 0 || corner[1] < 0 {
+			return nil, camera.UnspecifiedStream, errors.Errorf("perspective transform corner %d cannot be negative", i)
+		}
+		corners = append(corners, image.Pt(int(math.Round(corner[0])), int(math.Round(corner[1]))))
+	}
+	if conf.Width < 0 || conf.Height < 0 {
+		return nil, camera.UnspecifiedStream, errors.New("perspective transform width_px and height_px cannot be negative")
+	}
+	edge := func(a, b image.Point) float64 {
+		return math.Hypot(float64(b.X-a.X), float64(b.Y-a.Y))
+	}
+	if conf.Width == 0 {
+		conf.Width = int(math.Round(math.Max(edge(corners[0], corners[1]), edge(corners[2], corners[3]))))
+	}
+	if conf.Height == 0 {
+		conf.Height = int(math.Round(math.Max(edge(corners[0], corners[2]), edge(corners[1], corners[3]))))
+	}
+	if conf.Width < 2 || conf.Height < 2 {
+		return nil, camera.UnspecifiedStream, errors.New("perspective transform corners must span a region at least 2 pixels wide and tall")
+	}
+	outCorners := []image.Point{
+		{0, 0},
+		{conf.Width - 1, 0},
+		{0, conf.Height - 1},
+		{conf.Width - 1, conf.Height - 1},
+	}
+
+	reader := &perspectiveSource{
+		src:     source,
+		stream:  stream,
+		warp:    rimage.GetPerspectiveTransform(corners, outCorners),
+		outSize: image.Pt(conf.Width, conf.Height),
+	}
+	src, err := camera.NewVideoSourceFromReader(ctx, reader, nil, stream)
+	if err != nil {
+		return nil, camera.UnspecifiedStream, err
+	}
+	return src, stream, err
+}
+
+// Read straightens the region of the 2D image depending on the stream type.
+func (ps *perspectiveSource) Read(ctx context.Context) (image.Image, func(), error) {
+	ctx, span := trace.StartSpan(ctx, "camera::transformpipeline::perspective::Read")
+	defer span.End()
+	orig, release, err := camera.ReadImage(ctx, ps.src)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch ps.stream {
+	case camera.ColorStream, camera.UnspecifiedStream:
+		return rimage.WarpImage(orig, ps.warp, ps.outSize), release, nil
+	case camera.DepthStream:
+		dm, err := rimage.ConvertImageToDepthMap(ctx, orig)
+		if err != nil {
+			return nil, nil, err
+		}
+		return dm.Warp(ps.warp, ps.outSize), release, nil
+	default:
+		return nil, nil, camera.NewUnsupportedImageTypeError(ps.stream)
+	}
+}
+
+func (ps *perspectiveSource) Close(ctx context.Context) error {
+	return nil
+}
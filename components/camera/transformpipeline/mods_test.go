@@ -3,6 +3,7 @@ package transformpipeline
 import (
 	"context"
 	"image"
+	"image/color"
 	"testing"
 
 	"github.com/pion/mediadevices/pkg/prop"
@@ -226,6 +227,105 @@ func TestResizeDepth(t *testing.T) {
 	test.That(t, source.Close(context.Background()), test.ShouldBeNil)
 }
 
+func TestFlip(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 3))
+	img.Set(0, 0, color.NRGBA{R: 255, A: 255})
+	dm := rimage.NewEmptyDepthMap(4, 3)
+	dm.Set(0, 0, 100)
+
+	_, _, err := newFlipTransform(context.Background(), nil, camera.ColorStream, utils.AttributeMap{})
+	test.That(t, err, test.ShouldNotBeNil)
+
+	// test color source
+	source, err := camera.NewVideoSourceFromReader(context.Background(), &fake.StaticSource{ColorImg: img}, nil, camera.UnspecifiedStream)
+	test.That(t, err, test.ShouldBeNil)
+	am := utils.AttributeMap{"horizontal": true}
+	rs, stream, err := newFlipTransform(context.Background(), source, camera.ColorStream, am)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, stream, test.ShouldEqual, camera.ColorStream)
+	out, _, err := camera.ReadImage(context.Background(), rs)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, out.Bounds().Dx(), test.ShouldEqual, 4)
+	test.That(t, out.Bounds().Dy(), test.ShouldEqual, 3)
+	r, _, _, _ := out.At(3, 0).RGBA()
+	test.That(t, r, test.ShouldEqual, 0xffff)
+	r, _, _, _ = out.At(0, 0).RGBA()
+	test.That(t, r, test.ShouldEqual, 0)
+	test.That(t, rs.Close(context.Background()), test.ShouldBeNil)
+	test.That(t, source.Close(context.Background()), test.ShouldBeNil)
+
+	// test depth source
+	source, err = camera.NewVideoSourceFromReader(context.Background(), &fake.StaticSource{DepthImg: dm}, nil, camera.UnspecifiedStream)
+	test.That(t, err, test.ShouldBeNil)
+	am = utils.AttributeMap{"horizontal": true, "vertical": true}
+	rs, stream, err = newFlipTransform(context.Background(), source, camera.DepthStream, am)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, stream, test.ShouldEqual, camera.DepthStream)
+	out, _, err = camera.ReadImage(context.Background(), rs)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, out, test.ShouldHaveSameTypeAs, &rimage.DepthMap{})
+	flipped := out.(*rimage.DepthMap)
+	test.That(t, flipped.GetDepth(3, 2), test.ShouldEqual, 100)
+	test.That(t, flipped.GetDepth(0, 0), test.ShouldEqual, 0)
+	test.That(t, rs.Close(context.Background()), test.ShouldBeNil)
+	test.That(t, source.Close(context.Background()), test.ShouldBeNil)
+}
+
+func TestPerspective(t *testing.T) {
+	img, err := rimage.NewImageFromFile(artifact.MustPath("rimage/board1_small.png"))
+	test.That(t, err, test.ShouldBeNil)
+	dm, err := rimage.NewDepthMapFromFile(
+		context.Background(), artifact.MustPath("rimage/board1_gray_small.png"))
+	test.That(t, err, test.ShouldBeNil)
+
+	for _, am := range []utils.AttributeMap{
+		{},
+		{"corners_px": [][]float64{{0, 0}, {10, 0}, {10, 10}}},
+		{"corners_px": [][]float64{{0, 0}, {10, 0}, {10}, {0, 10}}},
+		{"corners_px": [][]float64{{-1, 0}, {10, 0}, {10, 10}, {0, 10}}},
+		{"corners_px": [][]float64{{0, 0}, {10, 0}, {10, 10}, {0, 10}}, "width_px": -5},
+		{"corners_px": [][]float64{{5, 5}, {5, 5}, {5, 5}, {5, 5}}},
+	} {
+		_, _, err := newPerspectiveTransform(context.Background(), nil, camera.ColorStream, am)
+		test.That(t, err, test.ShouldNotBeNil)
+	}
+
+	// a skewed region whose output size defaults to the lengths of its longest edges
+	am := utils.AttributeMap{"corners_px": [][]float64{{10, 10}, {50, 15}, {50, 45}, {10, 40}}}
+	source, err := camera.NewVideoSourceFromReader(context.Background(), &fake.StaticSource{ColorImg: img}, nil, camera.UnspecifiedStream)
+	test.That(t, err, test.ShouldBeNil)
+	rs, stream, err := newPerspectiveTransform(context.Background(), source, camera.ColorStream, am)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, stream, test.ShouldEqual, camera.ColorStream)
+	out, _, err := camera.ReadImage(context.Background(), rs)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, out.Bounds().Dx(), test.ShouldEqual, 40)
+	test.That(t, out.Bounds().Dy(), test.ShouldEqual, 30)
+	// the top left corner of the region becomes the top left of the output
+	test.That(t, rimage.NewColorFromColor(out.At(0, 0)).Distance(img.GetXY(10, 10)), test.ShouldBeLessThan, 1)
+	test.That(t, rs.Close(context.Background()), test.ShouldBeNil)
+	test.That(t, source.Close(context.Background()), test.ShouldBeNil)
+
+	// the whole depth map straightened into an image of a given size
+	am = utils.AttributeMap{
+		"corners_px": [][]float64{{0, 0}, {127, 0}, {127, 71}, {0, 71}},
+		"width_px":   64,
+		"height_px":  36,
+	}
+	source, err = camera.NewVideoSourceFromReader(context.Background(), &fake.StaticSource{DepthImg: dm}, nil, camera.UnspecifiedStream)
+	test.That(t, err, test.ShouldBeNil)
+	rs, stream, err = newPerspectiveTransform(context.Background(), source, camera.DepthStream, am)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, stream, test.ShouldEqual, camera.DepthStream)
+	out, _, err = camera.ReadImage(context.Background(), rs)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, out, test.ShouldHaveSameTypeAs, &rimage.DepthMap{})
+	test.That(t, out.Bounds().Dx(), test.ShouldEqual, 64)
+	test.That(t, out.Bounds().Dy(), test.ShouldEqual, 36)
+	test.That(t, rs.Close(context.Background()), test.ShouldBeNil)
+	test.That(t, source.Close(context.Background()), test.ShouldBeNil)
+}
+
 func TestRotateColorSource(t *testing.T) {
 	img, err := rimage.NewImageFromFile(artifact.MustPath("rimage/board1_small.png"))
 	test.That(t, err, test.ShouldBeNil)
@@ -0,0 +1,104 @@
+package transformpipeline
+
+import (
+	"context"
+	"image"
+	"time"
+
+	"go.opencensus.io/trace"
+	goutils "go.viam.com/utils"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/rimage/transform"
+)
+
+// maxPrefetchedFrameAge is how long a frame prepared ahead of time by a stage of a parallel pipeline may wait to be read before
+// it is too stale to return, such as when nothing has read from the pipeline for a while.
+const maxPrefetchedFrameAge = 200 * time.Millisecond
+
+type prefetchedFrame struct {
+	img     image.Image
+	release func()
+	err     error
+	readAt  time.Time
+}
+
+// prefetchSource runs a stage of a parallel pipeline in its own goroutine, which prepares the next frame of the stage while the
+// stages after it process the previous one, so that a frame is produced every time the slowest stage finishes instead of every
+// time all of the stages finish.
+type prefetchSource struct {
+	src     camera.VideoSource
+	frames  chan prefetchedFrame
+	workers *goutils.StoppableWorkers
+}
+
+// newPrefetchSource starts preparing frames of the given stage ahead of their reads.
+func newPrefetchSource(ctx context.Context, src camera.VideoSource, stream camera.ImageType) (camera.VideoSource, *prefetchSource, error) {
+	props, err := propsFromVideoSource(ctx, src)
+	if err != nil {
+		return nil, nil, err
+	}
+	var cameraModel transform.PinholeCameraModel
+	cameraModel.PinholeCameraIntrinsics = props.IntrinsicParams
+
+	if props.DistortionParams != nil {
+		cameraModel.Distortion = props.DistortionParams
+	}
+	ps := &prefetchSource{src: src, frames: make(chan prefetchedFrame)}
+	vs, err := camera.NewVideoSourceFromReader(ctx, ps, &cameraModel, stream)
+	if err != nil {
+		return nil, nil, err
+	}
+	ps.workers = goutils.NewBackgroundStoppableWorkers(ps.prefetch)
+	return vs, ps, nil
+}
+
+// prefetch reads each frame of the stage as soon as the previous one has been taken.
+func (ps *prefetchSource) prefetch(ctx context.Context) {
+	for ctx.Err() == nil {
+		img, release, err := camera.ReadImage(ctx, ps.src)
+		frame := prefetchedFrame{img: img, release: release, err: err, readAt: time.Now()}
+		select {
+		case ps.frames <- frame:
+		case <-ctx.Done():
+			if release != nil {
+				release()
+			}
+			return
+		}
+	}
+}
+
+// Read returns the next frame prepared by the stage, skipping any frame which went stale waiting to be read.
+func (ps *prefetchSource) Read(ctx context.Context) (image.Image, func(), error) {
+	ctx, span := trace.StartSpan(ctx, "camera::transformpipeline::prefetch::Read")
+	defer span.End()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case frame := <-ps.frames:
+			if time.Since(frame.readAt) > maxPrefetchedFrameAge {
+				if frame.release != nil {
+					frame.release()
+				}
+				continue
+			}
+			if frame.err != nil {
+				return nil, nil, frame.err
+			}
+			return frame.img, frame.release, nil
+		}
+	}
+}
+
+// NextPointCloud returns the next point cloud of the stage, which is not prepared ahead of time.
+func (ps *prefetchSource) NextPointCloud(ctx context.Context) (pointcloud.PointCloud, error) {
+	return ps.src.NextPointCloud(ctx)
+}
+
+func (ps *prefetchSource) Close(ctx context.Context) error {
+	ps.workers.Stop()
+	return nil
+}
@@ -0,0 +1,80 @@
+package transformpipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pion/mediadevices/pkg/prop"
+	"go.viam.com/test"
+	"go.viam.com/utils/artifact"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/components/camera/fake"
+	"go.viam.com/rdk/gostream"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/rimage"
+	"go.viam.com/rdk/testutils/inject"
+	"go.viam.com/rdk/utils"
+)
+
+func TestParallelPipeline(t *testing.T) {
+	ctx := context.Background()
+	img, err := rimage.NewImageFromFile(artifact.MustPath("rimage/board1_small.png"))
+	test.That(t, err, test.ShouldBeNil)
+	source := gostream.NewVideoSource(&fake.StaticSource{ColorImg: img}, prop.Video{})
+	src, err := camera.WrapVideoSourceWithProjector(ctx, source, nil, camera.ColorStream)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, source.Close(ctx), test.ShouldBeNil)
+	}()
+
+	transforms := []Transformation{
+		{Type: "rotate", Attributes: utils.AttributeMap{"angle_degs": 90}},
+		{Type: "resize", Attributes: utils.AttributeMap{"height_px": 20, "width_px": 10}},
+	}
+	read := func(t *testing.T, parallel bool) *rimage.Image {
+		t.Helper()
+		conf := &transformConfig{Source: "source", Pipeline: transforms, Parallel: parallel}
+		cam, err := newTransformPipeline(ctx, videoSourceFromCamera(ctx, src), nil, conf, &inject.Robot{}, logging.NewTestLogger(t))
+		test.That(t, err, test.ShouldBeNil)
+		defer func() {
+			test.That(t, cam.Close(ctx), test.ShouldBeNil)
+		}()
+		var out *rimage.Image
+		// the frames after the first are prepared while the previous ones are read
+		for i := 0; i < 3; i++ {
+			frame, _, err := camera.ReadImage(ctx, cam)
+			test.That(t, err, test.ShouldBeNil)
+			out = rimage.ConvertImage(frame)
+		}
+		return out
+	}
+	sequential := read(t, false)
+	parallel := read(t, true)
+	test.That(t, parallel.Bounds(), test.ShouldResemble, sequential.Bounds())
+	test.That(t, parallel.At(3, 5), test.ShouldResemble, sequential.At(3, 5))
+
+	t.Run("changes keep the pipeline parallel", func(t *testing.T) {
+		conf := &transformConfig{Source: "source", Pipeline: transforms, Parallel: true}
+		cam, err := newTransformPipeline(ctx, videoSourceFromCamera(ctx, src), nil, conf, &inject.Robot{}, logging.NewTestLogger(t))
+		test.That(t, err, test.ShouldBeNil)
+		defer func() {
+			test.That(t, cam.Close(ctx), test.ShouldBeNil)
+		}()
+		tp := cam.(*pipelineCamera).tp
+		test.That(t, tp.prefetchers, test.ShouldHaveLength, 2)
+		replaced := tp.prefetchers
+
+		_, err = cam.DoCommand(ctx, map[string]interface{}{doRemoveTransform: 0})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, tp.prefetchers, test.ShouldHaveLength, 1)
+		// the stages of the replaced pipeline are closed
+		for _, ps := range replaced {
+			test.That(t, ps.workers.Context().Err(), test.ShouldNotBeNil)
+		}
+		out, _, err := camera.ReadImage(ctx, cam)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, out.Bounds().Dx(), test.ShouldEqual, 10)
+		test.That(t, out.Bounds().Dy(), test.ShouldEqual, 20)
+	})
+}
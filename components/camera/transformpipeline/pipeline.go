@@ -7,9 +7,11 @@ import (
 	"context"
 	"fmt"
 	"image"
+	"sync"
 
 	"github.com/pkg/errors"
 	"go.opencensus.io/trace"
+	"go.uber.org/multierr"
 
 	"go.viam.com/rdk/components/camera"
 	"go.viam.com/rdk/gostream"
@@ -65,6 +67,8 @@ type transformConfig struct {
 	DistortionParameters *transform.BrownConrady            `json:"distortion_parameters,omitempty"`
 	Source               string                             `json:"source"`
 	Pipeline             []Transformation                   `json:"pipeline"`
+	// Parallel runs each transform in its own goroutine, so that the transforms of consecutive frames overlap.
+	Parallel bool `json:"parallel,omitempty"`
 }
 
 // Validate ensures all parts of the config are valid.
@@ -140,50 +144,117 @@ func newTransformPipeline(
 	} else {
 		streamType = camera.ColorStream
 	}
-	// loop through the pipeline and create the image flow
-	pipeline := make([]camera.VideoSource, 0, len(cfg.Pipeline))
+	pipeline, lastSource, outStreamType, prefetchers, err := buildPipeline(ctx, r, source, streamType, cfg.Pipeline, cfg.Parallel)
+	if err != nil {
+		return nil, err
+	}
+	tp := &transformPipeline{
+		Named:               named,
+		r:                   r,
+		source:              source,
+		sourceStreamType:    streamType,
+		streamType:          outStreamType,
+		intrinsicParameters: cfg.CameraParameters,
+		logger:              logger,
+		parallel:            cfg.Parallel,
+		transforms:          cfg.Pipeline,
+		pipeline:            pipeline,
+		src:                 lastSource,
+		prefetchers:         prefetchers,
+	}
+	cameraModel := camera.NewPinholeModelWithBrownConradyDistortion(cfg.CameraParameters, cfg.DistortionParameters)
+	vs, err := camera.NewVideoSourceFromReader(ctx, tp, &cameraModel, outStreamType)
+	if err != nil {
+		return nil, multierr.Combine(err, closeSources(ctx, pipeline))
+	}
+	return &pipelineCamera{VideoSource: vs, tp: tp}, nil
+}
+
+// buildPipeline loops through the transforms and creates the image flow from the source, whose sources must be closed once the
+// pipeline is done. In a parallel pipeline, the frames of each transform are prepared ahead of their reads by the returned
+// prefetchers, which are stopped when the sources are closed. The sources built so far are closed if any transform cannot be built.
+func buildPipeline(
+	ctx context.Context,
+	r robot.Robot,
+	source camera.VideoSource,
+	streamType camera.ImageType,
+	transforms []Transformation,
+	parallel bool,
+) ([]camera.VideoSource, camera.VideoSource, camera.ImageType, []*prefetchSource, error) {
+	if len(transforms) == 0 {
+		return nil, nil, camera.UnspecifiedStream, nil, errors.New("pipeline has no transforms in it")
+	}
+	pipeline := make([]camera.VideoSource, 0, len(transforms))
+	var prefetchers []*prefetchSource
 	lastSource := videoSourceFromCamera(ctx, source)
-	for _, tr := range cfg.Pipeline {
+	for _, tr := range transforms {
 		src, newStreamType, err := buildTransform(ctx, r, lastSource, streamType, tr)
 		if err != nil {
-			return nil, err
+			return nil, nil, camera.UnspecifiedStream, nil, multierr.Combine(err, closeSources(ctx, pipeline))
 		}
 		streamSrc := videoSourceFromCamera(ctx, src)
+		if parallel {
+			prefetchSrc, prefetcher, err := newPrefetchSource(ctx, streamSrc, newStreamType)
+			if err != nil {
+				return nil, nil, camera.UnspecifiedStream, nil, multierr.Combine(err, closeSources(ctx, append(pipeline, streamSrc)))
+			}
+			prefetchers = append(prefetchers, prefetcher)
+			streamSrc = prefetchSrc
+		}
 		pipeline = append(pipeline, streamSrc)
 		lastSource = streamSrc
 		streamType = newStreamType
 	}
-	cameraModel := camera.NewPinholeModelWithBrownConradyDistortion(cfg.CameraParameters, cfg.DistortionParameters)
-	return camera.NewVideoSourceFromReader(
-		ctx,
-		transformPipeline{named, pipeline, lastSource, cfg.CameraParameters, logger},
-		&cameraModel,
-		streamType,
-	)
+	return pipeline, lastSource, streamType, prefetchers, nil
+}
+
+// closeSources closes the sources of a pipeline, but not the source camera they read from.
+func closeSources(ctx context.Context, sources []camera.VideoSource) error {
+	var errs error
+	for _, src := range sources {
+		errs = multierr.Combine(errs, src.Close(ctx))
+	}
+	return errs
 }
 
 type transformPipeline struct {
 	resource.Named
-	pipeline            []camera.VideoSource
-	src                 camera.Camera
+	r                   robot.Robot
+	source              camera.VideoSource
+	sourceStreamType    camera.ImageType
+	streamType          camera.ImageType
 	intrinsicParameters *transform.PinholeCameraIntrinsics
 	logger              logging.Logger
+	parallel            bool
+
+	// mu guards the transforms, which may be changed while the pipeline is streaming
+	mu          sync.RWMutex
+	transforms  []Transformation
+	pipeline    []camera.VideoSource
+	src         camera.Camera
+	prefetchers []*prefetchSource
 }
 
-func (tp transformPipeline) Read(ctx context.Context) (image.Image, func(), error) {
+func (tp *transformPipeline) Read(ctx context.Context) (image.Image, func(), error) {
 	ctx, span := trace.StartSpan(ctx, "camera::transformpipeline::Read")
 	defer span.End()
-	img, err := camera.DecodeImageFromCamera(ctx, "", nil, tp.src)
+	tp.mu.RLock()
+	src := tp.src
+	tp.mu.RUnlock()
+	img, err := camera.DecodeImageFromCamera(ctx, "", nil, src)
 	if err != nil {
 		return nil, func() {}, err
 	}
 	return img, func() {}, nil
 }
 
-func (tp transformPipeline) NextPointCloud(ctx context.Context) (pointcloud.PointCloud, error) {
+func (tp *transformPipeline) NextPointCloud(ctx context.Context) (pointcloud.PointCloud, error) {
 	ctx, span := trace.StartSpan(ctx, "camera::transformpipeline::NextPointCloud")
 	defer span.End()
-	if lastElem, ok := tp.pipeline[len(tp.pipeline)-1].(camera.PointCloudSource); ok {
+	tp.mu.RLock()
+	lastSource := tp.pipeline[len(tp.pipeline)-1]
+	tp.mu.RUnlock()
+	if lastElem, ok := lastSource.(camera.PointCloudSource); ok {
 		pc, err := lastElem.NextPointCloud(ctx)
 		if err != nil {
 			return nil, errors.Wrap(err, "function NextPointCloud not defined for last videosource in transform pipeline")
@@ -193,6 +264,11 @@ func (tp transformPipeline) NextPointCloud(ctx context.Context) (pointcloud.Poin
 	return nil, errors.New("function NextPointCloud not defined for last videosource in transform pipeline")
 }
 
-func (tp transformPipeline) Close(ctx context.Context) error {
-	return nil
+func (tp *transformPipeline) Close(ctx context.Context) error {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	err := closeSources(ctx, tp.pipeline)
+	tp.pipeline = nil
+	tp.prefetchers = nil
+	return err
 }
@@ -0,0 +1,154 @@
+package transformpipeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+
+	"go.viam.com/rdk/components/camera"
+)
+
+// keys of the DoCommand commands with which the transforms of a pipeline are changed while it runs, so that they can be tuned while
+// watching the stream. Every command replies with the resulting pipeline under the key "pipeline".
+const (
+	// doGetPipeline returns the transforms of the pipeline.
+	doGetPipeline = "get_pipeline"
+	// doSetPipeline replaces the transforms of the pipeline with the given list of transforms.
+	doSetPipeline = "set_pipeline"
+	// doAddTransform adds the given transform to the end of the pipeline, or at its optional "index".
+	doAddTransform = "add_transform"
+	// doRemoveTransform removes the transform at the given index.
+	doRemoveTransform = "remove_transform"
+	// doMoveTransform moves the transform at the index "from" to the index "to".
+	doMoveTransform = "move_transform"
+)
+
+// pipelineCamera is a transform pipeline camera whose transforms can be changed through DoCommand.
+type pipelineCamera struct {
+	camera.VideoSource
+	tp *transformPipeline
+}
+
+func (pc *pipelineCamera) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	return pc.tp.DoCommand(ctx, cmd)
+}
+
+func (pc *pipelineCamera) Close(ctx context.Context) error {
+	return multierr.Combine(pc.VideoSource.Close(ctx), pc.tp.Close(ctx))
+}
+
+// DoCommand changes the transforms of the pipeline, which are rebuilt from the source camera. The pipeline is left as it was if
+// any of the new transforms cannot be built.
+func (tp *transformPipeline) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	transforms := append([]Transformation{}, tp.transforms...)
+	changed := false
+
+	if raw, ok := cmd[doSetPipeline]; ok {
+		if err := decodeTransforms(raw, &transforms); err != nil {
+			return nil, errors.Wrapf(err, "cannot parse %s", doSetPipeline)
+		}
+		changed = true
+	}
+	if raw, ok := cmd[doAddTransform]; ok {
+		var add struct {
+			Transformation
+			Index *int `json:"index"`
+		}
+		if err := decodeTransforms(raw, &add); err != nil {
+			return nil, errors.Wrapf(err, "cannot parse %s", doAddTransform)
+		}
+		index := len(transforms)
+		if add.Index != nil {
+			index = *add.Index
+		}
+		if index < 0 || index > len(transforms) {
+			return nil, fmt.Errorf("cannot add a transform at index %d of a pipeline of %d transforms", index, len(transforms))
+		}
+		transforms = append(transforms[:index], append([]Transformation{add.Transformation}, transforms[index:]...)...)
+		changed = true
+	}
+	if raw, ok := cmd[doRemoveTransform]; ok {
+		index, err := transformIndex(raw, len(transforms))
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot parse %s", doRemoveTransform)
+		}
+		transforms = append(transforms[:index], transforms[index+1:]...)
+		changed = true
+	}
+	if raw, ok := cmd[doMoveTransform]; ok {
+		move, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected %s to be a map with the keys from and to, got %T", doMoveTransform, raw)
+		}
+		from, err := transformIndex(move["from"], len(transforms))
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot parse %s from", doMoveTransform)
+		}
+		to, err := transformIndex(move["to"], len(transforms))
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot parse %s to", doMoveTransform)
+		}
+		moved := transforms[from]
+		transforms = append(transforms[:from], transforms[from+1:]...)
+		transforms = append(transforms[:to], append([]Transformation{moved}, transforms[to:]...)...)
+		changed = true
+	}
+
+	if changed {
+		pipeline, lastSource, streamType, prefetchers, err := buildPipeline(
+			ctx, tp.r, tp.source, tp.sourceStreamType, transforms, tp.parallel)
+		if err != nil {
+			return nil, err
+		}
+		// the stream type of the camera is fixed when it is created
+		if streamType != tp.streamType {
+			err := fmt.Errorf("pipeline changes cannot change the stream type from %q to %q", tp.streamType, streamType)
+			return nil, multierr.Combine(err, closeSources(ctx, pipeline))
+		}
+		old := tp.pipeline
+		tp.transforms = transforms
+		tp.pipeline = pipeline
+		tp.src = lastSource
+		tp.prefetchers = prefetchers
+		if err := closeSources(ctx, old); err != nil {
+			tp.logger.CWarnw(ctx, "failed to close the sources of the replaced transform pipeline", "error", err)
+		}
+		tp.logger.CInfof(ctx, "transform pipeline changed to %d transforms", len(transforms))
+	} else if _, ok := cmd[doGetPipeline]; !ok {
+		return nil, errors.Errorf("unknown command, expected one of %s, %s, %s, %s or %s",
+			doGetPipeline, doSetPipeline, doAddTransform, doRemoveTransform, doMoveTransform)
+	}
+
+	var pipeline []interface{}
+	if err := decodeTransforms(tp.transforms, &pipeline); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"pipeline": pipeline}, nil
+}
+
+// decodeTransforms converts between the DoCommand and the config representations of transforms through their JSON form.
+func decodeTransforms(from, to interface{}) error {
+	data, err := json.Marshal(from)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, to)
+}
+
+// transformIndex parses the index of a transform in a pipeline of the given length.
+func transformIndex(raw interface{}, length int) (int, error) {
+	f, ok := raw.(float64)
+	if !ok || f != float64(int(f)) {
+		return 0, fmt.Errorf("expected the index of a transform, got %v", raw)
+	}
+	index := int(f)
+	if index < 0 || index >= length {
+		return 0, fmt.Errorf("index %d is out of range of a pipeline of %d transforms", index, length)
+	}
+	return index, nil
+}
@@ -0,0 +1,122 @@
+package transformpipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pion/mediadevices/pkg/prop"
+	"go.viam.com/test"
+	"go.viam.com/utils/artifact"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/components/camera/fake"
+	"go.viam.com/rdk/gostream"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/rimage"
+	"go.viam.com/rdk/testutils/inject"
+	"go.viam.com/rdk/utils"
+)
+
+func TestReconfigurePipeline(t *testing.T) {
+	ctx := context.Background()
+	transformConf := &transformConfig{
+		Source: "source",
+		Pipeline: []Transformation{
+			{Type: "resize", Attributes: utils.AttributeMap{"height_px": 20, "width_px": 10}},
+		},
+	}
+	img, err := rimage.NewImageFromFile(artifact.MustPath("rimage/board1_small.png"))
+	test.That(t, err, test.ShouldBeNil)
+	source := gostream.NewVideoSource(&fake.StaticSource{ColorImg: img}, prop.Video{})
+	src, err := camera.WrapVideoSourceWithProjector(ctx, source, nil, camera.ColorStream)
+	test.That(t, err, test.ShouldBeNil)
+	cam, err := newTransformPipeline(ctx, videoSourceFromCamera(ctx, src), nil, transformConf, &inject.Robot{}, logging.NewTestLogger(t))
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, cam.Close(ctx), test.ShouldBeNil)
+		test.That(t, source.Close(ctx), test.ShouldBeNil)
+	}()
+
+	checkSize := func(t *testing.T, width, height int) {
+		t.Helper()
+		out, _, err := camera.ReadImage(ctx, cam)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, out.Bounds().Dx(), test.ShouldEqual, width)
+		test.That(t, out.Bounds().Dy(), test.ShouldEqual, height)
+	}
+	types := func(t *testing.T, resp map[string]interface{}) []string {
+		t.Helper()
+		pipeline, ok := resp["pipeline"].([]interface{})
+		test.That(t, ok, test.ShouldBeTrue)
+		var out []string
+		for _, tr := range pipeline {
+			out = append(out, tr.(map[string]interface{})["type"].(string))
+		}
+		return out
+	}
+	checkSize(t, 10, 20)
+
+	resp, err := cam.DoCommand(ctx, map[string]interface{}{doGetPipeline: true})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, types(t, resp), test.ShouldResemble, []string{"resize"})
+
+	t.Run("add, move and remove transforms", func(t *testing.T) {
+		resp, err := cam.DoCommand(ctx, map[string]interface{}{doAddTransform: map[string]interface{}{
+			"type":       "crop",
+			"attributes": map[string]interface{}{"x_min_px": 0, "y_min_px": 0, "x_max_px": 5, "y_max_px": 8},
+		}})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, types(t, resp), test.ShouldResemble, []string{"resize", "crop"})
+		checkSize(t, 5, 8)
+
+		resp, err = cam.DoCommand(ctx, map[string]interface{}{doAddTransform: map[string]interface{}{
+			"type":       "flip",
+			"index":      0.,
+			"attributes": map[string]interface{}{"horizontal": true},
+		}})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, types(t, resp), test.ShouldResemble, []string{"flip", "resize", "crop"})
+		checkSize(t, 5, 8)
+
+		// cropping before resizing leaves the size of the resize
+		resp, err = cam.DoCommand(ctx, map[string]interface{}{doMoveTransform: map[string]interface{}{"from": 2., "to": 0.}})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, types(t, resp), test.ShouldResemble, []string{"crop", "flip", "resize"})
+		checkSize(t, 10, 20)
+
+		resp, err = cam.DoCommand(ctx, map[string]interface{}{doRemoveTransform: 0.})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, types(t, resp), test.ShouldResemble, []string{"flip", "resize"})
+		checkSize(t, 10, 20)
+	})
+
+	t.Run("set the pipeline", func(t *testing.T) {
+		resp, err := cam.DoCommand(ctx, map[string]interface{}{doSetPipeline: []interface{}{
+			map[string]interface{}{"type": "resize", "attributes": map[string]interface{}{"height_px": 30, "width_px": 40}},
+		}})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, types(t, resp), test.ShouldResemble, []string{"resize"})
+		checkSize(t, 40, 30)
+	})
+
+	t.Run("invalid changes leave the pipeline as it was", func(t *testing.T) {
+		for _, cmd := range []map[string]interface{}{
+			{"spin": true},
+			{doRemoveTransform: 0.},
+			{doRemoveTransform: 3.},
+			{doRemoveTransform: 0.5},
+			{doMoveTransform: 1.},
+			{doAddTransform: map[string]interface{}{"type": "resize", "attributes": map[string]interface{}{"height_px": 0}}},
+			{doAddTransform: map[string]interface{}{"type": "sharpen"}},
+			{doAddTransform: map[string]interface{}{"type": "rotate", "index": 5.}},
+			{doSetPipeline: []interface{}{}},
+		} {
+			_, err := cam.DoCommand(ctx, cmd)
+			test.That(t, err, test.ShouldNotBeNil)
+		}
+		resp, err := cam.DoCommand(ctx, map[string]interface{}{doGetPipeline: true})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, types(t, resp), test.ShouldResemble, []string{"resize"})
+		checkSize(t, 40, 30)
+	})
+}
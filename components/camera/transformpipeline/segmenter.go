@@ -0,0 +1,107 @@
+package transformpipeline
+
+import (
+	"context"
+	"fmt"
+	"image"
+
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/rimage/transform"
+	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/services/vision"
+	"go.viam.com/rdk/utils"
+	"go.viam.com/rdk/vision/segmentation"
+)
+
+// segmenterConfig is the attribute struct for segmenters (their name as found in the vision service), along with the camera
+// whose point clouds they segment.
+type segmenterConfig struct {
+	SegmenterName string `json:"segmenter_name"`
+	CameraName    string `json:"camera_name"`
+}
+
+// segmenterSource takes an image from the camera, and overlays the objects segmented by the segmenter.
+type segmenterSource struct {
+	src           camera.VideoSource
+	segmenterName string
+	cameraName    string
+	intrinsics    *transform.PinholeCameraIntrinsics
+	r             robot.Robot
+}
+
+func newSegmentationsTransform(
+	ctx context.Context,
+	source camera.VideoSource,
+	r robot.Robot,
+	am utils.AttributeMap,
+) (camera.VideoSource, camera.ImageType, error) {
+	conf, err := resource.TransformAttributeMap[*segmenterConfig](am)
+	if err != nil {
+		return nil, camera.UnspecifiedStream, err
+	}
+	if conf.SegmenterName == "" {
+		return nil, camera.UnspecifiedStream, errors.New("segmentations transform requires a segmenter_name")
+	}
+	if conf.CameraName == "" {
+		return nil, camera.UnspecifiedStream, errors.New("segmentations transform requires the camera_name of the camera to segment")
+	}
+
+	props, err := propsFromVideoSource(ctx, source)
+	if err != nil {
+		return nil, camera.UnspecifiedStream, err
+	}
+	if props.IntrinsicParams == nil {
+		return nil, camera.UnspecifiedStream,
+			transform.NewNoIntrinsicsError("segmentations transform projects segmented objects onto the image of its source")
+	}
+	var cameraModel transform.PinholeCameraModel
+	cameraModel.PinholeCameraIntrinsics = props.IntrinsicParams
+
+	if props.DistortionParams != nil {
+		cameraModel.Distortion = props.DistortionParams
+	}
+	segmenter := &segmenterSource{
+		source,
+		conf.SegmenterName,
+		conf.CameraName,
+		props.IntrinsicParams,
+		r,
+	}
+	src, err := camera.NewVideoSourceFromReader(ctx, segmenter, &cameraModel, camera.ColorStream)
+	if err != nil {
+		return nil, camera.UnspecifiedStream, err
+	}
+	return src, camera.ColorStream, err
+}
+
+// Read returns the image overlaid with the masks and labels of the segmented objects.
+func (ss *segmenterSource) Read(ctx context.Context) (image.Image, func(), error) {
+	ctx, span := trace.StartSpan(ctx, "camera::transformpipeline::segmenter::Read")
+	defer span.End()
+	srv, err := vision.FromRobot(ss.r, ss.segmenterName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("source_segmenter cant find vision service: %w", err)
+	}
+	// get image from source camera
+	img, release, err := camera.ReadImage(ctx, ss.src)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not get next source image: %w", err)
+	}
+	objects, err := srv.GetObjectPointClouds(ctx, ss.cameraName, map[string]interface{}{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not get segmented objects: %w", err)
+	}
+	res, err := segmentation.Overlay(img, objects, ss.intrinsics)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not overlay segmented objects: %w", err)
+	}
+	return res, release, nil
+}
+
+func (ss *segmenterSource) Close(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,92 @@
+package transformpipeline
+
+import (
+	"context"
+	"image"
+	"testing"
+
+	"github.com/pion/mediadevices/pkg/prop"
+	"go.viam.com/test"
+	"go.viam.com/utils/artifact"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/components/camera/fake"
+	"go.viam.com/rdk/gostream"
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/rimage"
+	"go.viam.com/rdk/rimage/transform"
+	"go.viam.com/rdk/services/vision"
+	"go.viam.com/rdk/testutils/inject"
+	"go.viam.com/rdk/utils"
+	viz "go.viam.com/rdk/vision"
+)
+
+func TestSegmentationsTransform(t *testing.T) {
+	ctx := context.Background()
+	img, err := rimage.NewImageFromFile(artifact.MustPath("rimage/board1_small.png"))
+	test.That(t, err, test.ShouldBeNil)
+	intrinsics := &transform.PinholeCameraIntrinsics{Width: 128, Height: 72, Fx: 100, Fy: 100, Ppx: 64, Ppy: 36}
+
+	// a square a meter in front of the camera, which covers the pixels around the center of the image
+	cloud := pointcloud.New()
+	for x := -50.; x <= 50; x += 10 {
+		for y := -50.; y <= 50; y += 10 {
+			test.That(t, cloud.Set(pointcloud.NewVector(x, y, 1000), nil), test.ShouldBeNil)
+		}
+	}
+	obj, err := viz.NewObjectWithLabel(cloud, "square", nil)
+	test.That(t, err, test.ShouldBeNil)
+	segmenter := inject.NewVisionService("segmenter")
+	var segmentedCamera string
+	segmenter.GetObjectPointCloudsFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]*viz.Object, error) {
+		segmentedCamera = cameraName
+		return []*viz.Object{obj}, nil
+	}
+	r := &inject.Robot{}
+	r.ResourceByNameFunc = func(n resource.Name) (resource.Resource, error) {
+		if n == vision.Named("segmenter") {
+			return segmenter, nil
+		}
+		return nil, resource.NewNotFoundError(n)
+	}
+
+	source := gostream.NewVideoSource(&fake.StaticSource{ColorImg: img}, prop.Video{})
+	defer func() {
+		test.That(t, source.Close(ctx), test.ShouldBeNil)
+	}()
+	withIntrinsics, err := camera.WrapVideoSourceWithProjector(ctx, source,
+		&transform.PinholeCameraModel{PinholeCameraIntrinsics: intrinsics}, camera.ColorStream)
+	test.That(t, err, test.ShouldBeNil)
+	withoutIntrinsics, err := camera.WrapVideoSourceWithProjector(ctx, source, nil, camera.ColorStream)
+	test.That(t, err, test.ShouldBeNil)
+
+	am := utils.AttributeMap{"segmenter_name": "segmenter", "camera_name": "depth_cam"}
+	_, _, err = newSegmentationsTransform(ctx, withIntrinsics, r, utils.AttributeMap{"camera_name": "depth_cam"})
+	test.That(t, err, test.ShouldNotBeNil)
+	_, _, err = newSegmentationsTransform(ctx, withIntrinsics, r, utils.AttributeMap{"segmenter_name": "segmenter"})
+	test.That(t, err, test.ShouldNotBeNil)
+	_, _, err = newSegmentationsTransform(ctx, withoutIntrinsics, r, am)
+	test.That(t, err, test.ShouldWrap, transform.ErrNoIntrinsics)
+
+	segmented, stream, err := newSegmentationsTransform(ctx, withIntrinsics, r, am)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, stream, test.ShouldEqual, camera.ColorStream)
+	out, _, err := camera.ReadImage(ctx, segmented)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, segmentedCamera, test.ShouldEqual, "depth_cam")
+	test.That(t, out.Bounds(), test.ShouldResemble, image.Rect(0, 0, 128, 72))
+	// the center of the image is masked while the corners are left as they were
+	test.That(t, rimage.NewColorFromColor(out.At(64, 36)), test.ShouldNotResemble, img.GetXY(64, 36))
+	test.That(t, rimage.NewColorFromColor(out.At(0, 71)), test.ShouldResemble, img.GetXY(0, 71))
+	test.That(t, segmented.Close(ctx), test.ShouldBeNil)
+
+	// the overlay fails along with the segmenter
+	r.ResourceByNameFunc = func(n resource.Name) (resource.Resource, error) {
+		return nil, resource.NewNotFoundError(n)
+	}
+	segmented, _, err = newSegmentationsTransform(ctx, withIntrinsics, r, am)
+	test.That(t, err, test.ShouldBeNil)
+	_, _, err = camera.ReadImage(ctx, segmented)
+	test.That(t, err, test.ShouldNotBeNil)
+}
@@ -16,12 +16,16 @@ type transformType string
 
 // the allowed transforms.
 const (
-	transformTypeUnspecified     = transformType("")
-	transformTypeRotate          = transformType("rotate")
-	transformTypeResize          = transformType("resize")
-	transformTypeCrop            = transformType("crop")
-	transformTypeDetections      = transformType("detections")
-	transformTypeClassifications = transformType("classifications")
+	transformTypeUnspecified       = transformType("")
+	transformTypeRotate            = transformType("rotate")
+	transformTypeResize            = transformType("resize")
+	transformTypeCrop              = transformType("crop")
+	transformTypeFlip              = transformType("flip")
+	transformTypePerspective       = transformType("perspective")
+	transformTypeDetections        = transformType("detections")
+	transformTypeClassifications   = transformType("classifications")
+	transformTypeSegmentations     = transformType("segmentations")
+	transformTypeDepthToPointCloud = transformType("depth_to_pointcloud")
 )
 
 // transformRegistration holds pertinent information regarding the available transforms.
@@ -46,7 +50,17 @@ var registeredTransformConfigs = map[transformType]*transformRegistration{
 	transformTypeCrop: {
 		string(transformTypeCrop),
 		&cropConfig{},
-		"Crop the image to the specified rectangle in pixels",
+		"Crop the image to the specified rectangle in pixels, or in fractions of the image size when every bound is between 0 and 1",
+	},
+	transformTypeFlip: {
+		string(transformTypeFlip),
+		&flipConfig{},
+		"Mirrors the image horizontally, vertically or both. Used when the camera image is mirrored.",
+	},
+	transformTypePerspective: {
+		string(transformTypePerspective),
+		&perspectiveConfig{},
+		"Straightens the region within the four specified corners in pixels into a rectangular image",
 	},
 	transformTypeDetections: {
 		string(transformTypeDetections),
@@ -58,6 +72,16 @@ var registeredTransformConfigs = map[transformType]*transformRegistration{
 		&classifierConfig{},
 		"Overlays image classifications on the image. Can use any classifier registered in the vision service.",
 	},
+	transformTypeSegmentations: {
+		string(transformTypeSegmentations),
+		&segmenterConfig{},
+		"Overlays the objects found by a 3D segmenter on the image. Can use any segmenter registered in the vision service.",
+	},
+	transformTypeDepthToPointCloud: {
+		string(transformTypeDepthToPointCloud),
+		&depthToPointCloudConfig{},
+		"Projects depth images into point clouds with the intrinsics of the source camera, keeping the points within range",
+	},
 }
 
 // Transformation states the type of transformation and the attributes that are specific to the given type.
@@ -98,10 +122,18 @@ func buildTransform(
 		return newResizeTransform(ctx, source, stream, tr.Attributes)
 	case transformTypeCrop:
 		return newCropTransform(ctx, source, stream, tr.Attributes)
+	case transformTypeFlip:
+		return newFlipTransform(ctx, source, stream, tr.Attributes)
+	case transformTypePerspective:
+		return newPerspectiveTransform(ctx, source, stream, tr.Attributes)
 	case transformTypeDetections:
 		return newDetectionsTransform(ctx, source, r, tr.Attributes)
 	case transformTypeClassifications:
 		return newClassificationsTransform(ctx, source, r, tr.Attributes)
+	case transformTypeSegmentations:
+		return newSegmentationsTransform(ctx, source, r, tr.Attributes)
+	case transformTypeDepthToPointCloud:
+		return newDepthToPointCloudTransform(ctx, source, stream, tr.Attributes)
 	default:
 		return nil, camera.UnspecifiedStream, fmt.Errorf("do not  know camera transform of type %q", tr.Type)
 	}
@@ -37,6 +37,10 @@ type Accuracy struct {
 	CompassDegreeError float32
 }
 
+// PositionStdDevMMKey is the key of the AccuracyMap through which movement sensors which estimate the uncertainty of their position
+// directly, rather than through a dilution of precision, report its standard deviation in millimeters.
+const PositionStdDevMMKey = "position_std_dev_mm"
+
 // ProtoFeaturesToAccuracy converts a GetAccuracyResponse from a protocol buffer (protobuf)
 // into an Accuracy struct.
 // used by the client.
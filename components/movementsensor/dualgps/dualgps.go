@@ -0,0 +1,227 @@
+// Package dualgps implements a movementsensor which derives true heading from the positions of two GPS antennas
+// mounted a known distance apart, as in dual antenna and moving base RTK setups.
+package dualgps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/golang/geo/r3"
+	geo "github.com/kellydunn/golang-geo"
+
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/spatialmath"
+	rdkutils "go.viam.com/rdk/utils"
+)
+
+const (
+	// defaultPositionAccuracyMM is the horizontal accuracy of an RTK fixed position.
+	defaultPositionAccuracyMM = 20.
+	// defaultBaselineTolerance is the fraction of the baseline by which the measured distance between the antennas may differ
+	// from the configured baseline before the positions are considered too inaccurate to derive a heading from.
+	defaultBaselineTolerance = 0.1
+)
+
+// Model is the name of the dual_gps movement sensor model.
+var Model = resource.DefaultModelFamily.WithModel("dual_gps")
+
+// Config is the config of the dual_gps movement sensor model.
+type Config struct {
+	// FirstGPS is the primary antenna, whose position is reported as the position of the sensor.
+	FirstGPS string `json:"first_gps"`
+	// SecondGPS is the antenna the heading is measured towards.
+	SecondGPS string `json:"second_gps"`
+	// BaselineMM is the distance between the antennas.
+	BaselineMM float64 `json:"baseline_mm"`
+	// BaselineToleranceMM is how far the measured distance between the antennas may differ from the baseline.
+	BaselineToleranceMM float64 `json:"baseline_tolerance_mm,omitempty"`
+	// MountingOffsetDegs is the heading of the line from the first to the second antenna relative to the front of the vehicle,
+	// clockwise. For instance, antennas mounted from left to right across the vehicle have an offset of 90 degrees.
+	MountingOffsetDegs float64 `json:"mounting_offset_degs,omitempty"`
+	// PositionAccuracyMM is the horizontal accuracy of the position reported by each antenna.
+	PositionAccuracyMM float64 `json:"position_accuracy_mm,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (cfg *Config) Validate(path string) ([]string, error) {
+	if cfg.FirstGPS == "" {
+		return nil, resource.NewConfigValidationFieldRequiredError(path, "first_gps")
+	}
+	if cfg.SecondGPS == "" {
+		return nil, resource.NewConfigValidationFieldRequiredError(path, "second_gps")
+	}
+	if cfg.FirstGPS == cfg.SecondGPS {
+		return nil, resource.NewConfigValidationError(path, errors.New("first_gps and second_gps must be different antennas"))
+	}
+	if cfg.BaselineMM <= 0 {
+		return nil, resource.NewConfigValidationError(path, errors.New("baseline_mm must be positive"))
+	}
+	if cfg.BaselineToleranceMM < 0 || cfg.PositionAccuracyMM < 0 {
+		return nil, resource.NewConfigValidationError(path, errors.New("baseline_tolerance_mm and position_accuracy_mm may not be negative"))
+	}
+	return []string{cfg.FirstGPS, cfg.SecondGPS}, nil
+}
+
+type dualGPS struct {
+	resource.Named
+	resource.AlwaysRebuild
+	resource.TriviallyCloseable
+
+	first, second      movementsensor.MovementSensor
+	baselineMM         float64
+	toleranceMM        float64
+	mountingOffset     float64
+	positionAccuracyMM float64
+
+	logger logging.Logger
+}
+
+func init() {
+	resource.RegisterComponent(
+		movementsensor.API,
+		Model,
+		resource.Registration[movementsensor.MovementSensor, *Config]{Constructor: newDualGPS})
+}
+
+func newDualGPS(ctx context.Context, deps resource.Dependencies, conf resource.Config, logger logging.Logger) (
+	movementsensor.MovementSensor, error,
+) {
+	newConf, err := resource.NativeConfig[*Config](conf)
+	if err != nil {
+		return nil, err
+	}
+	first, err := movementsensor.FromDependencies(deps, newConf.FirstGPS)
+	if err != nil {
+		return nil, err
+	}
+	second, err := movementsensor.FromDependencies(deps, newConf.SecondGPS)
+	if err != nil {
+		return nil, err
+	}
+	for _, antenna := range []movementsensor.MovementSensor{first, second} {
+		props, err := antenna.Properties(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		if !props.PositionSupported {
+			return nil, fmt.Errorf("movement sensor %s does not support position", antenna.Name().ShortName())
+		}
+	}
+
+	d := &dualGPS{
+		Named:              conf.ResourceName().AsNamed(),
+		first:              first,
+		second:             second,
+		baselineMM:         newConf.BaselineMM,
+		toleranceMM:        newConf.BaselineToleranceMM,
+		mountingOffset:     newConf.MountingOffsetDegs,
+		positionAccuracyMM: newConf.PositionAccuracyMM,
+		logger:             logger,
+	}
+	if d.toleranceMM == 0 {
+		d.toleranceMM = defaultBaselineTolerance * d.baselineMM
+	}
+	if d.positionAccuracyMM == 0 {
+		d.positionAccuracyMM = defaultPositionAccuracyMM
+	}
+	return d, nil
+}
+
+// antennas returns the positions of both antennas and the measured distance between them, failing if the distance differs from
+// the baseline by more than the tolerance, since at least one of the positions must then be inaccurate.
+func (d *dualGPS) antennas(ctx context.Context, extra map[string]interface{}) (*geo.Point, *geo.Point, float64, error) {
+	first, _, err := d.first.Position(ctx, extra)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	second, _, err := d.second.Position(ctx, extra)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	measuredMM := first.GreatCircleDistance(second) * 1e6
+	if math.Abs(measuredMM-d.baselineMM) > d.toleranceMM {
+		return nil, nil, 0, fmt.Errorf(
+			"measured antenna baseline of %.0fmm differs from the configured %.0fmm by more than %.0fmm",
+			measuredMM, d.baselineMM, d.toleranceMM,
+		)
+	}
+	return first, second, measuredMM, nil
+}
+
+func (d *dualGPS) Position(ctx context.Context, extra map[string]interface{}) (*geo.Point, float64, error) {
+	return d.first.Position(ctx, extra)
+}
+
+// CompassHeading returns the heading of the vehicle in degrees clockwise from north, derived from the bearing between the antennas.
+func (d *dualGPS) CompassHeading(ctx context.Context, extra map[string]interface{}) (float64, error) {
+	first, second, _, err := d.antennas(ctx, extra)
+	if err != nil {
+		return math.NaN(), err
+	}
+	return headingFromBearing(first.BearingTo(second), d.mountingOffset), nil
+}
+
+// headingFromBearing converts the bearing from the first to the second antenna into the heading of the vehicle in [0, 360).
+func headingFromBearing(bearing, mountingOffset float64) float64 {
+	heading := math.Mod(bearing-mountingOffset, 360)
+	if heading < 0 {
+		heading += 360
+	}
+	return heading
+}
+
+func (d *dualGPS) Orientation(ctx context.Context, extra map[string]interface{}) (spatialmath.Orientation, error) {
+	return spatialmath.NewOrientationVector(), movementsensor.ErrMethodUnimplementedOrientation
+}
+
+func (d *dualGPS) LinearVelocity(ctx context.Context, extra map[string]interface{}) (r3.Vector, error) {
+	return r3.Vector{}, movementsensor.ErrMethodUnimplementedLinearVelocity
+}
+
+func (d *dualGPS) AngularVelocity(ctx context.Context, extra map[string]interface{}) (spatialmath.AngularVelocity, error) {
+	return spatialmath.AngularVelocity{}, movementsensor.ErrMethodUnimplementedAngularVelocity
+}
+
+func (d *dualGPS) LinearAcceleration(ctx context.Context, extra map[string]interface{}) (r3.Vector, error) {
+	return r3.Vector{}, movementsensor.ErrMethodUnimplementedLinearAcceleration
+}
+
+// Accuracy reports the position accuracy of the first antenna and the heading error implied by the position accuracy of both
+// antennas over the baseline. Shorter baselines give less accurate headings.
+func (d *dualGPS) Accuracy(ctx context.Context, extra map[string]interface{}) (*movementsensor.Accuracy, error) {
+	acc, err := d.first.Accuracy(ctx, extra)
+	if err != nil {
+		return nil, err
+	}
+	withHeading := *acc
+	withHeading.CompassDegreeError = float32(headingError(d.positionAccuracyMM, d.baselineMM))
+	return &withHeading, nil
+}
+
+// headingError returns the error in degrees of a heading measured between two positions with the given accuracy.
+func headingError(positionAccuracyMM, baselineMM float64) float64 {
+	return rdkutils.RadToDeg(math.Atan2(math.Sqrt2*positionAccuracyMM, baselineMM))
+}
+
+func (d *dualGPS) Properties(ctx context.Context, extra map[string]interface{}) (*movementsensor.Properties, error) {
+	return &movementsensor.Properties{
+		PositionSupported:       true,
+		CompassHeadingSupported: true,
+	}, nil
+}
+
+// Readings includes the measured distance between the antennas, so that a mismatch with the configured baseline can be diagnosed.
+func (d *dualGPS) Readings(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+	readings, err := movementsensor.DefaultAPIReadings(ctx, d, extra)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, measuredMM, err := d.antennas(ctx, extra); err == nil {
+		readings["baseline_mm"] = measuredMM
+	}
+	return readings, nil
+}
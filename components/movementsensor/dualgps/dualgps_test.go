@@ -0,0 +1,97 @@
+package dualgps
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	geo "github.com/kellydunn/golang-geo"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func newAntenna(name string, pos *geo.Point) *inject.MovementSensor {
+	antenna := inject.NewMovementSensor(name)
+	antenna.PositionFunc = func(ctx context.Context, extra map[string]interface{}) (*geo.Point, float64, error) {
+		return pos, 0, nil
+	}
+	antenna.PropertiesFunc = func(ctx context.Context, extra map[string]interface{}) (*movementsensor.Properties, error) {
+		return &movementsensor.Properties{PositionSupported: true}, nil
+	}
+	antenna.AccuracyFunc = func(ctx context.Context, extra map[string]interface{}) (*movementsensor.Accuracy, error) {
+		return &movementsensor.Accuracy{Hdop: 0.5, NmeaFix: 4}, nil
+	}
+	return antenna
+}
+
+func TestDualGPS(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+
+	origin := geo.NewPoint(40, -74)
+	// the second antenna is 1m east of the first
+	east := origin.PointAtDistanceAndBearing(1e-3, 90)
+	deps := resource.Dependencies{
+		movementsensor.Named("first"):  newAntenna("first", origin),
+		movementsensor.Named("second"): newAntenna("second", east),
+	}
+	newSensor := func(t *testing.T, cfg *Config) movementsensor.MovementSensor {
+		t.Helper()
+		_, err := cfg.Validate("path")
+		test.That(t, err, test.ShouldBeNil)
+		ms, err := newDualGPS(ctx, deps, resource.Config{Name: "dual", ConvertedAttributes: cfg}, logger)
+		test.That(t, err, test.ShouldBeNil)
+		return ms
+	}
+
+	t.Run("heading is the bearing between the antennas adjusted by the mounting offset", func(t *testing.T) {
+		ms := newSensor(t, &Config{FirstGPS: "first", SecondGPS: "second", BaselineMM: 1000})
+		heading, err := ms.CompassHeading(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, heading, test.ShouldAlmostEqual, 90, 0.1)
+
+		// antennas mounted from left to right across the vehicle face north when the second antenna is east of the first
+		ms = newSensor(t, &Config{FirstGPS: "first", SecondGPS: "second", BaselineMM: 1000, MountingOffsetDegs: 90})
+		heading, err = ms.CompassHeading(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, math.Mod(heading+180, 360)-180, test.ShouldAlmostEqual, 0, 0.1)
+
+		pos, _, err := ms.Position(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, pos, test.ShouldResemble, origin)
+		props, err := ms.Properties(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, props.CompassHeadingSupported, test.ShouldBeTrue)
+	})
+
+	t.Run("heading accuracy depends on the baseline", func(t *testing.T) {
+		ms := newSensor(t, &Config{FirstGPS: "first", SecondGPS: "second", BaselineMM: 1000})
+		acc, err := ms.Accuracy(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, acc.NmeaFix, test.ShouldEqual, 4)
+		test.That(t, acc.CompassDegreeError, test.ShouldAlmostEqual, headingError(defaultPositionAccuracyMM, 1000), 1e-6)
+		test.That(t, headingError(20, 500), test.ShouldBeGreaterThan, headingError(20, 1000))
+	})
+
+	t.Run("antenna positions which do not match the baseline give no heading", func(t *testing.T) {
+		ms := newSensor(t, &Config{FirstGPS: "first", SecondGPS: "second", BaselineMM: 2000})
+		_, err := ms.CompassHeading(ctx, nil)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "measured antenna baseline of 1000mm")
+	})
+
+	t.Run("invalid configs", func(t *testing.T) {
+		for _, cfg := range []*Config{
+			{SecondGPS: "second", BaselineMM: 1000},
+			{FirstGPS: "first", SecondGPS: "first", BaselineMM: 1000},
+			{FirstGPS: "first", SecondGPS: "second"},
+		} {
+			_, err := cfg.Validate("path")
+			test.That(t, err, test.ShouldNotBeNil)
+		}
+	})
+}
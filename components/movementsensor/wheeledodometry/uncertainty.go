@@ -0,0 +1,83 @@
+package wheeledodometry
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/resource"
+)
+
+const (
+	// defaultPositionErrorPerMeterMM is the growth of the position standard deviation per meter traveled without slip.
+	defaultPositionErrorPerMeterMM = 20.
+	// defaultSlipThresholdDegsPerSec is how far the turn rate measured by the wheels may differ from the IMU before the wheels are
+	// considered to be slipping.
+	defaultSlipThresholdDegsPerSec = 15.
+)
+
+// configureUncertainty sets up the optional IMU used to detect wheel slip, and the parameters of the position uncertainty model.
+// Must be called while holding the mutex.
+func (o *odometry) configureUncertainty(ctx context.Context, deps resource.Dependencies, conf *Config) error {
+	o.positionErrorPerMeterMM = conf.PositionErrorPerMeterMM
+	if o.positionErrorPerMeterMM == 0 {
+		o.positionErrorPerMeterMM = defaultPositionErrorPerMeterMM
+	}
+	o.slipThresholdDegsPerSec = conf.SlipThresholdDegsPerSec
+	if o.slipThresholdDegsPerSec == 0 {
+		o.slipThresholdDegsPerSec = defaultSlipThresholdDegsPerSec
+	}
+
+	o.imu = nil
+	if conf.IMU == "" {
+		return nil
+	}
+	imu, err := movementsensor.FromDependencies(deps, conf.IMU)
+	if err != nil {
+		return err
+	}
+	props, err := imu.Properties(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if !props.AngularVelocitySupported {
+		return fmt.Errorf("movement sensor %s cannot be used to detect wheel slip because it does not report angular velocity", conf.IMU)
+	}
+	o.imu = imu
+	o.logger.Debugf("using movement sensor %v to detect wheel slip", conf.IMU)
+	return nil
+}
+
+// imuYawRate returns the turn rate of the base measured by the IMU in degrees per second, and whether it could be read.
+func (o *odometry) imuYawRate(ctx context.Context) (float64, bool) {
+	if o.imu == nil {
+		return 0, false
+	}
+	angVel, err := o.imu.AngularVelocity(ctx, nil)
+	if err != nil {
+		o.logger.CDebugf(ctx, "could not read angular velocity to detect wheel slip: %v", err)
+		return 0, false
+	}
+	return angVel.Z, true
+}
+
+// updateUncertainty grows the standard deviation of the position by the distance the wheels have traveled. The wheels are
+// considered to be slipping when the turn rate they measure differs from the one measured by the IMU, in which case the distance
+// they report cannot be trusted at all and is added to the standard deviation in full. Slip which does not change the turn rate of
+// the base, such as both wheels spinning in place, cannot be detected. Must be called while holding the mutex.
+func (o *odometry) updateUncertainty(centerDistM, imuYawRate float64, haveIMU bool) {
+	distMM := math.Abs(centerDistM) * 1000
+	o.positionStdDevMM += distMM * o.positionErrorPerMeterMM / 1000
+
+	slipping := haveIMU && math.Abs(o.angularVelocity.Z-imuYawRate) > o.slipThresholdDegsPerSec
+	if slipping {
+		o.positionStdDevMM += distMM
+		if !o.slipping {
+			o.slipCount++
+			o.logger.Warnf("wheel slip detected: the wheels measure a turn rate of %.1f degs/sec but the IMU measures %.1f degs/sec",
+				o.angularVelocity.Z, imuYawRate)
+		}
+	}
+	o.slipping = slipping
+}
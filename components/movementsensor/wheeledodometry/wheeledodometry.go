@@ -46,6 +46,10 @@ type Config struct {
 	RightMotors       []string `json:"right_motors"`
 	Base              string   `json:"base"`
 	TimeIntervalMSecs float64  `json:"time_interval_msecs,omitempty"`
+	// IMU is an optional movement sensor reporting angular velocity, used to detect wheel slip.
+	IMU                     string  `json:"imu,omitempty"`
+	SlipThresholdDegsPerSec float64 `json:"slip_threshold_degs_per_sec,omitempty"`
+	PositionErrorPerMeterMM float64 `json:"position_error_per_meter_mm,omitempty"`
 }
 
 type motorPair struct {
@@ -77,6 +81,13 @@ type odometry struct {
 	useCompass bool
 	shiftPos   bool
 
+	imu                     movementsensor.MovementSensor
+	slipThresholdDegsPerSec float64
+	positionErrorPerMeterMM float64
+	positionStdDevMM        float64
+	slipping                bool
+	slipCount               int
+
 	workers *goutils.StoppableWorkers
 	mu      sync.Mutex
 	logger  logging.Logger
@@ -117,6 +128,13 @@ func (cfg *Config) Validate(path string) ([]string, error) {
 		return nil, errors.New("wheeled odometry only supports one left and right motor each")
 	}
 
+	if cfg.SlipThresholdDegsPerSec < 0 || cfg.PositionErrorPerMeterMM < 0 {
+		return nil, errors.New("slip threshold and position error per meter may not be negative")
+	}
+	if cfg.IMU != "" {
+		deps = append(deps, cfg.IMU)
+	}
+
 	return deps, nil
 }
 
@@ -169,6 +187,10 @@ func (o *odometry) Reconfigure(ctx context.Context, deps resource.Dependencies,
 	o.base = newBase
 	o.logger.Debugf("using base %v for wheeled_odometry sensor", newBase.Name().ShortName())
 
+	if err := o.configureUncertainty(ctx, deps, newConf); err != nil {
+		return err
+	}
+
 	// check if new motors have been added, or the existing motors have been changed, and update the motorPairs accorodingly
 	for i := range newConf.LeftMotors {
 		var motorLeft, motorRight motor.Motor
@@ -322,13 +344,21 @@ func (o *odometry) Readings(ctx context.Context, extra map[string]interface{}) (
 	defer o.mu.Unlock()
 	readings["position_meters_X"] = o.position.X
 	readings["position_meters_Y"] = o.position.Y
+	readings["position_std_dev_mm"] = o.positionStdDevMM
+	readings["wheel_slip"] = o.slipping
+	readings["wheel_slip_count"] = o.slipCount
 
 	return readings, nil
 }
 
+// Accuracy reports the standard deviation of the position, which grows with the distance traveled and with wheel slip.
 func (o *odometry) Accuracy(ctx context.Context, extra map[string]interface{}) (*movementsensor.Accuracy, error,
 ) {
-	return movementsensor.UnimplementedOptionalAccuracies(), nil
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	acc := movementsensor.UnimplementedOptionalAccuracies()
+	acc.AccuracyMap = map[string]float32{movementsensor.PositionStdDevMMKey: float32(o.positionStdDevMM)}
+	return acc, nil
 }
 
 func (o *odometry) Properties(ctx context.Context, extra map[string]interface{}) (*movementsensor.Properties, error) {
@@ -410,6 +440,9 @@ func (o *odometry) trackPosition() {
 			left := positions[0]
 			right := positions[1]
 
+			// Read the IMU before locking, so that a slow IMU does not block the other APIs.
+			imuYawRate, haveIMU := o.imuYawRate(ctx)
+
 			// Base properties need to be checked every time because dependent components reconfiguring does not trigger
 			// the parent component to reconfigure. In this case, that means if the base properties change, the wheeled
 			// odometry movement sensor will not be aware of these changes and will continue to use the old values
@@ -454,6 +487,7 @@ func (o *odometry) trackPosition() {
 			// Update the linear and angular velocity values using the provided time interval.
 			o.linearVelocity.Y = centerDist / (o.timeIntervalMSecs / 1000)
 			o.angularVelocity.Z = centerAngle * (180 / math.Pi) / (o.timeIntervalMSecs / 1000)
+			o.updateUncertainty(centerDist, imuYawRate, haveIMU)
 
 			o.mu.Unlock()
 		}
@@ -481,6 +515,7 @@ func (o *odometry) DoCommand(ctx context.Context,
 		o.position.X = 0
 		o.position.Y = 0
 		o.orientation.Yaw = 0
+		o.positionStdDevMM = 0
 
 		resp[resetShift] = fmt.Sprintf("resetting position and setting shift to %v", reset)
 	}
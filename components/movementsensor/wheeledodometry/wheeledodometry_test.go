@@ -14,8 +14,10 @@ import (
 
 	"go.viam.com/rdk/components/base"
 	"go.viam.com/rdk/components/motor"
+	"go.viam.com/rdk/components/movementsensor"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/spatialmath"
 	"go.viam.com/rdk/testutils/inject"
 )
 
@@ -508,3 +510,85 @@ func TestVelocities(t *testing.T) {
 	test.That(t, angVel.Z, test.ShouldAlmostEqual, 0, 0.1)
 	test.That(t, od.Close(context.Background()), test.ShouldBeNil)
 }
+
+func TestWheelSlip(t *testing.T) {
+	ctx := context.Background()
+	newOdometry := func() *odometry {
+		return &odometry{
+			logger:                  logging.NewTestLogger(t),
+			positionErrorPerMeterMM: 20,
+			slipThresholdDegsPerSec: 15,
+		}
+	}
+
+	t.Run("position uncertainty grows with distance traveled and slip", func(t *testing.T) {
+		od := newOdometry()
+		od.updateUncertainty(10, 0, true)
+		test.That(t, od.slipping, test.ShouldBeFalse)
+		test.That(t, od.positionStdDevMM, test.ShouldAlmostEqual, 200)
+
+		// the wheels measure a turn the IMU does not
+		od.angularVelocity.Z = 90
+		od.updateUncertainty(1, 0, true)
+		test.That(t, od.slipping, test.ShouldBeTrue)
+		test.That(t, od.positionStdDevMM, test.ShouldAlmostEqual, 1220)
+		od.updateUncertainty(1, 0, true)
+		test.That(t, od.slipCount, test.ShouldEqual, 1)
+
+		// slip cannot be detected without an IMU
+		od.updateUncertainty(1, 0, false)
+		test.That(t, od.slipping, test.ShouldBeFalse)
+
+		acc, err := od.Accuracy(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, acc.AccuracyMap[movementsensor.PositionStdDevMMKey], test.ShouldAlmostEqual, od.positionStdDevMM, 1e-3)
+	})
+
+	t.Run("slip is detected while tracking position", func(t *testing.T) {
+		left := createFakeMotor(true)
+		right := createFakeMotor(false)
+		_ = left.ResetZeroPosition(ctx, 0, nil)
+		_ = right.ResetZeroPosition(ctx, 0, nil)
+		imu := inject.NewMovementSensor("imu")
+		imu.AngularVelocityFunc = func(ctx context.Context, extra map[string]interface{}) (spatialmath.AngularVelocity, error) {
+			return spatialmath.AngularVelocity{}, nil
+		}
+
+		od := newOdometry()
+		od.wheelCircumference = 0.2
+		od.baseWidth = 0.2
+		od.base = createFakeBase(0.2, 0.2, 0.1)
+		od.timeIntervalMSecs = 500
+		od.originCoord = geo.NewPoint(0, 0)
+		od.imu = imu
+		od.motors = append(od.motors, motorPair{left, right})
+		od.trackPosition()
+		defer func() { test.That(t, od.Close(ctx), test.ShouldBeNil) }()
+
+		// the wheels turn the base 90 degrees while the IMU measures no rotation
+		setPositions(-1*(math.Pi/4), 1*(math.Pi/4))
+		time.Sleep(time.Duration(od.timeIntervalMSecs*1.15) * time.Millisecond)
+
+		readings, err := od.Readings(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, readings["wheel_slip"], test.ShouldBeTrue)
+		test.That(t, readings["wheel_slip_count"], test.ShouldEqual, 1)
+	})
+
+	t.Run("invalid uncertainty configs", func(t *testing.T) {
+		cfg := Config{
+			LeftMotors:              []string{leftMotorName},
+			RightMotors:             []string{rightMotorName},
+			Base:                    baseName,
+			PositionErrorPerMeterMM: -1,
+		}
+		_, err := cfg.Validate("path")
+		test.That(t, err, test.ShouldNotBeNil)
+
+		cfg.PositionErrorPerMeterMM = 0
+		cfg.IMU = "imu"
+		deps, err := cfg.Validate("path")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, deps, test.ShouldContain, "imu")
+	})
+}
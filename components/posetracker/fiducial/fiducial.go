@@ -0,0 +1,188 @@
+// Package fiducial implements a pose tracker locating fiducial tags, such as AprilTags or ArUco markers, seen by a camera
+package fiducial
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/components/posetracker"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/rimage/transform"
+	"go.viam.com/rdk/services/vision"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+var model = resource.DefaultModelFamily.WithModel("fiducial")
+
+// Config is the config of the fiducial pose_tracker model.
+type Config struct {
+	// CameraName is the camera which sees the tags, whose frame the poses of the tags are reported in.
+	CameraName string `json:"camera_name"`
+	// DetectorName is the vision service which detects the tags in the images of the camera, labelling each detection
+	// with the ID of its tag, which is the name of the body of the tag.
+	DetectorName string `json:"detector_name"`
+	// TagSizeMM is the length of the sides of the tags, in millimeters.
+	TagSizeMM float64 `json:"tag_size_mm"`
+	// TagSizesMM overrides the length of the sides of the tags with the given IDs, in millimeters.
+	TagSizesMM map[string]float64 `json:"tag_sizes_mm,omitempty"`
+	// MinConfidence is the lowest score of the detections of tags which are reported.
+	MinConfidence float64 `json:"min_confidence,omitempty"`
+}
+
+// Validate validates the fiducial model's configuration.
+func (cfg *Config) Validate(path string) ([]string, error) {
+	if cfg.CameraName == "" {
+		return nil, resource.NewConfigValidationFieldRequiredError(path, "camera_name")
+	}
+	if cfg.DetectorName == "" {
+		return nil, resource.NewConfigValidationFieldRequiredError(path, "detector_name")
+	}
+	if cfg.TagSizeMM <= 0 {
+		return nil, resource.NewConfigValidationError(path, errors.New("tag_size_mm must be greater than 0"))
+	}
+	for id, size := range cfg.TagSizesMM {
+		if size <= 0 {
+			return nil, resource.NewConfigValidationError(path, fmt.Errorf("the size of tag %q must be greater than 0", id))
+		}
+	}
+	if cfg.MinConfidence < 0 || cfg.MinConfidence > 1 {
+		return nil, resource.NewConfigValidationError(path, errors.New("min_confidence must be between 0 and 1"))
+	}
+	return []string{cfg.CameraName, vision.Named(cfg.DetectorName).String()}, nil
+}
+
+type fiducial struct {
+	resource.Named
+	resource.TriviallyCloseable
+	logger logging.Logger
+
+	mu       sync.Mutex
+	cam      camera.Camera
+	detector vision.Service
+	conf     *Config
+}
+
+func init() {
+	resource.RegisterComponent(
+		posetracker.API, model,
+		resource.Registration[posetracker.PoseTracker, *Config]{
+			Constructor: newFiducialModel,
+		})
+}
+
+func newFiducialModel(ctx context.Context, deps resource.Dependencies, conf resource.Config, logger logging.Logger) (
+	posetracker.PoseTracker, error,
+) {
+	f := &fiducial{
+		Named:  conf.ResourceName().AsNamed(),
+		logger: logger,
+	}
+	if err := f.Reconfigure(ctx, deps, conf); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *fiducial) Reconfigure(ctx context.Context, deps resource.Dependencies, conf resource.Config) error {
+	newConf, err := resource.NativeConfig[*Config](conf)
+	if err != nil {
+		return err
+	}
+	cam, err := camera.FromDependencies(deps, newConf.CameraName)
+	if err != nil {
+		return err
+	}
+	detector, err := vision.FromDependencies(deps, newConf.DetectorName)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.cam = cam
+	f.detector = detector
+	f.conf = newConf
+	return nil
+}
+
+// Poses returns the poses of the tags detected in the next image of the camera, in the frame of the camera. A tag is located
+// from the size of its detection and the intrinsics of the camera, which cannot recover how it is turned, so every tag is
+// reported as facing the camera.
+func (f *fiducial) Poses(
+	ctx context.Context, bodyNames []string, extra map[string]interface{},
+) (referenceframe.FrameSystemPoses, error) {
+	f.mu.Lock()
+	cam, detector, conf := f.cam, f.detector, f.conf
+	f.mu.Unlock()
+
+	props, err := cam.Properties(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if props.IntrinsicParams == nil {
+		return nil, transform.NewNoIntrinsicsError(fmt.Sprintf("camera %q", conf.CameraName))
+	}
+	detections, err := detector.DetectionsFromCamera(ctx, conf.CameraName, extra)
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[string]bool, len(bodyNames))
+	for _, name := range bodyNames {
+		wanted[name] = true
+	}
+	poses := referenceframe.FrameSystemPoses{}
+	for _, det := range detections {
+		id := det.Label()
+		if (len(wanted) > 0 && !wanted[id]) || det.Score() < conf.MinConfidence {
+			continue
+		}
+		if box := det.BoundingBox(); box == nil || box.Empty() {
+			continue
+		}
+		pose := tagPose(det, props.IntrinsicParams, conf.tagSize(id))
+		if prev, ok := poses[id]; ok {
+			f.logger.CDebugw(ctx, "tag detected more than once, keeping its nearest detection", "id", id)
+			if prev.Pose().Point().Norm() < pose.Point().Norm() {
+				continue
+			}
+		}
+		poses[id] = referenceframe.NewPoseInFrame(conf.CameraName, pose)
+	}
+	return poses, nil
+}
+
+// tagSize returns the length of the sides of the tag with the given ID, in millimeters.
+func (cfg *Config) tagSize(id string) float64 {
+	if size, ok := cfg.TagSizesMM[id]; ok {
+		return size
+	}
+	return cfg.TagSizeMM
+}
+
+// tagPose locates a tag of the given size from its detection, using the pinhole model of the camera: the tag is as far from the
+// camera as makes its size in the image match the size of its detection, and is along the ray through the center of its
+// detection.
+func tagPose(det objectdetection.Detection, intrinsics *transform.PinholeCameraIntrinsics, sizeMM float64) spatialmath.Pose {
+	box := det.BoundingBox()
+	width, height := float64(box.Dx()), float64(box.Dy())
+	// average the distances given by the width and the height of the detection, which differ when the tag is tilted
+	depth := (intrinsics.Fx*sizeMM/width + intrinsics.Fy*sizeMM/height) / 2
+	centerX := float64(box.Min.X) + width/2
+	centerY := float64(box.Min.Y) + height/2
+	point := r3.Vector{
+		X: (centerX - intrinsics.Ppx) * depth / intrinsics.Fx,
+		Y: (centerY - intrinsics.Ppy) * depth / intrinsics.Fy,
+		Z: depth,
+	}
+	// the tag faces back toward the camera, which looks along +Z
+	return spatialmath.NewPose(point, &spatialmath.OrientationVectorDegrees{OZ: -1})
+}
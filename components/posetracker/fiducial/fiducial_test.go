@@ -0,0 +1,95 @@
+package fiducial
+
+import (
+	"context"
+	"image"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/components/posetracker"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/rimage/transform"
+	"go.viam.com/rdk/services/vision"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+func TestValidate(t *testing.T) {
+	cfg := Config{DetectorName: "tags", TagSizeMM: 50}
+	_, err := cfg.Validate("path")
+	test.That(t, err, test.ShouldBeError, resource.NewConfigValidationFieldRequiredError("path", "camera_name"))
+
+	cfg.CameraName = "cam"
+	cfg.TagSizesMM = map[string]float64{"3": -1}
+	_, err = cfg.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `tag "3"`)
+
+	cfg.TagSizesMM = map[string]float64{"3": 100}
+	deps, err := cfg.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, deps, test.ShouldResemble, []string{"cam", vision.Named("tags").String()})
+}
+
+func TestPoses(t *testing.T) {
+	ctx := context.Background()
+	intrinsics := &transform.PinholeCameraIntrinsics{Width: 640, Height: 480, Fx: 500, Fy: 500, Ppx: 320, Ppy: 240}
+	cam := inject.NewCamera("cam")
+	cam.PropertiesFunc = func(ctx context.Context) (camera.Properties, error) {
+		return camera.Properties{IntrinsicParams: intrinsics}, nil
+	}
+	detector := inject.NewVisionService("tags")
+	detector.DetectionsFromCameraFunc = func(ctx context.Context, cameraName string, extra map[string]interface{},
+	) ([]objectdetection.Detection, error) {
+		test.That(t, cameraName, test.ShouldEqual, "cam")
+		return []objectdetection.Detection{
+			// a 50mm tag which appears 50px wide is 500mm away, here centered in the image
+			objectdetection.NewDetection(image.Rect(295, 215, 345, 265), 0.9, "1"),
+			// a 100mm tag which appears 50px wide is 1000mm away, here 100px right of the center
+			objectdetection.NewDetection(image.Rect(395, 215, 445, 265), 0.9, "2"),
+			objectdetection.NewDetection(image.Rect(0, 0, 10, 10), 0.1, "3"),
+		}, nil
+	}
+	deps := resource.Dependencies{cam.Name(): cam, detector.Name(): detector}
+	tracker, err := newFiducialModel(ctx, deps, resource.Config{
+		Name:  "tags",
+		API:   posetracker.API,
+		Model: model,
+		ConvertedAttributes: &Config{
+			CameraName:    "cam",
+			DetectorName:  "tags",
+			TagSizeMM:     50,
+			TagSizesMM:    map[string]float64{"2": 100},
+			MinConfidence: 0.5,
+		},
+	}, logging.NewTestLogger(t))
+	test.That(t, err, test.ShouldBeNil)
+
+	poses, err := tracker.Poses(ctx, nil, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, poses, test.ShouldHaveLength, 2)
+	test.That(t, poses["1"].Parent(), test.ShouldEqual, "cam")
+	test.That(t, spatialmath.OrientationAlmostEqual(poses["1"].Pose().Orientation(),
+		&spatialmath.OrientationVectorDegrees{OZ: -1}), test.ShouldBeTrue)
+	test.That(t, poses["1"].Pose().Point().X, test.ShouldAlmostEqual, 0)
+	test.That(t, poses["1"].Pose().Point().Z, test.ShouldAlmostEqual, 500)
+	test.That(t, poses["2"].Pose().Point().X, test.ShouldAlmostEqual, 200)
+	test.That(t, poses["2"].Pose().Point().Z, test.ShouldAlmostEqual, 1000)
+
+	poses, err = tracker.Poses(ctx, []string{"2"}, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, poses, test.ShouldHaveLength, 1)
+	test.That(t, poses["2"], test.ShouldNotBeNil)
+
+	t.Run("camera without intrinsics", func(t *testing.T) {
+		cam.PropertiesFunc = func(ctx context.Context) (camera.Properties, error) {
+			return camera.Properties{}, nil
+		}
+		_, err := tracker.Poses(ctx, nil, nil)
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,171 @@
+// Package merged implements a pose tracker combining the poses of bodies seen by other pose trackers
+package merged
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+
+	"go.viam.com/rdk/components/posetracker"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot/framesystem"
+	"go.viam.com/rdk/spatialmath"
+)
+
+var model = resource.DefaultModelFamily.WithModel("merged")
+
+const (
+	// resolutionPriority reports the pose of a body from the first pose tracker in the list which sees it.
+	resolutionPriority = "priority"
+	// resolutionAverage reports the average of the poses of a body from every pose tracker which sees it.
+	resolutionAverage = "average"
+)
+
+// Config is the config of the merged pose_tracker model.
+type Config struct {
+	// PoseTrackers are the pose trackers whose bodies are merged, in order of priority.
+	PoseTrackers []string `json:"pose_trackers"`
+	// Frame is the frame the poses of all bodies are reported in, which defaults to the world frame.
+	Frame string `json:"frame,omitempty"`
+	// ConflictResolution is how the poses of a body seen by more than one pose tracker are combined, either "priority"
+	// (the default) or "average".
+	ConflictResolution string `json:"conflict_resolution,omitempty"`
+}
+
+// Validate validates the merged model's configuration.
+func (cfg *Config) Validate(path string) ([]string, error) {
+	if len(cfg.PoseTrackers) == 0 {
+		return nil, resource.NewConfigValidationFieldRequiredError(path, "pose_trackers")
+	}
+	switch cfg.ConflictResolution {
+	case "", resolutionPriority, resolutionAverage:
+	default:
+		return nil, resource.NewConfigValidationError(path, fmt.Errorf(
+			"conflict_resolution must be %q or %q, got %q", resolutionPriority, resolutionAverage, cfg.ConflictResolution))
+	}
+	deps := append([]string{}, cfg.PoseTrackers...)
+	deps = append(deps, framesystem.InternalServiceName.String())
+	return deps, nil
+}
+
+type merged struct {
+	resource.Named
+	resource.TriviallyCloseable
+	logger logging.Logger
+
+	mu         sync.Mutex
+	trackers   []posetracker.PoseTracker
+	fs         framesystem.Service
+	frame      string
+	resolution string
+}
+
+func init() {
+	resource.RegisterComponent(
+		posetracker.API, model,
+		resource.Registration[posetracker.PoseTracker, *Config]{
+			Constructor: newMergedModel,
+		})
+}
+
+func newMergedModel(ctx context.Context, deps resource.Dependencies, conf resource.Config, logger logging.Logger) (
+	posetracker.PoseTracker, error,
+) {
+	m := &merged{
+		Named:  conf.ResourceName().AsNamed(),
+		logger: logger,
+	}
+	if err := m.Reconfigure(ctx, deps, conf); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (m *merged) Reconfigure(ctx context.Context, deps resource.Dependencies, conf resource.Config) error {
+	newConf, err := resource.NativeConfig[*Config](conf)
+	if err != nil {
+		return err
+	}
+	trackers := make([]posetracker.PoseTracker, 0, len(newConf.PoseTrackers))
+	for _, name := range newConf.PoseTrackers {
+		tracker, err := resource.FromDependencies[posetracker.PoseTracker](deps, posetracker.Named(name))
+		if err != nil {
+			return err
+		}
+		trackers = append(trackers, tracker)
+	}
+	fs, err := framesystem.FromDependencies(deps)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.trackers = trackers
+	m.fs = fs
+	m.frame = newConf.Frame
+	if m.frame == "" {
+		m.frame = referenceframe.World
+	}
+	m.resolution = newConf.ConflictResolution
+	if m.resolution == "" {
+		m.resolution = resolutionPriority
+	}
+	return nil
+}
+
+// Poses returns the poses of the bodies seen by any of the pose trackers, transformed into the configured frame. Pose trackers
+// which fail are skipped, unless all of them fail.
+func (m *merged) Poses(
+	ctx context.Context, bodyNames []string, extra map[string]interface{},
+) (referenceframe.FrameSystemPoses, error) {
+	m.mu.Lock()
+	trackers, fs, frame, resolution := m.trackers, m.fs, m.frame, m.resolution
+	m.mu.Unlock()
+
+	seen := map[string][]spatialmath.Pose{}
+	var order []string
+	var errs error
+	for _, tracker := range trackers {
+		poses, err := posetracker.PosesInFrame(ctx, tracker, fs, frame, bodyNames, extra)
+		if err != nil {
+			m.logger.CDebugw(ctx, "skipping pose tracker", "name", tracker.Name().ShortName(), "error", err)
+			errs = multierr.Combine(errs, err)
+			continue
+		}
+		for body, pose := range poses {
+			if _, ok := seen[body]; !ok {
+				order = append(order, body)
+			}
+			seen[body] = append(seen[body], pose.Pose())
+		}
+	}
+	if errs != nil && len(multierr.Errors(errs)) == len(trackers) {
+		return nil, errors.Wrap(errs, "no pose tracker returned poses")
+	}
+
+	result := make(referenceframe.FrameSystemPoses, len(seen))
+	for _, body := range order {
+		poses := seen[body]
+		pose := poses[0]
+		if resolution == resolutionAverage {
+			pose = averagePose(poses)
+		}
+		result[body] = referenceframe.NewPoseInFrame(frame, pose)
+	}
+	return result, nil
+}
+
+// averagePose returns the average of the given poses, weighting each of them equally.
+func averagePose(poses []spatialmath.Pose) spatialmath.Pose {
+	avg := poses[0]
+	for i := 1; i < len(poses); i++ {
+		avg = spatialmath.Interpolate(avg, poses[i], 1/float64(i+1))
+	}
+	return avg
+}
@@ -0,0 +1,106 @@
+package merged
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/posetracker"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot/framesystem"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestValidate(t *testing.T) {
+	cfg := Config{}
+	_, err := cfg.Validate("path")
+	test.That(t, err, test.ShouldBeError, resource.NewConfigValidationFieldRequiredError("path", "pose_trackers"))
+
+	cfg = Config{PoseTrackers: []string{"mocap1", "mocap2"}, ConflictResolution: "vote"}
+	_, err = cfg.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "conflict_resolution")
+
+	cfg.ConflictResolution = resolutionAverage
+	deps, err := cfg.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, deps, test.ShouldResemble, []string{"mocap1", "mocap2", framesystem.InternalServiceName.String()})
+}
+
+func TestPoses(t *testing.T) {
+	ctx := context.Background()
+	newTracker := func(name string, poses referenceframe.FrameSystemPoses, err error) *inject.PoseTracker {
+		tracker := inject.NewPoseTracker(name)
+		tracker.PosesFunc = func(ctx context.Context, bodyNames []string, extra map[string]interface{},
+		) (referenceframe.FrameSystemPoses, error) {
+			return poses, err
+		}
+		return tracker
+	}
+	// mocap1 reports its poses in the world frame, mocap2 in its own frame which is offset 100mm along x from the world
+	mocap1 := newTracker("mocap1", referenceframe.FrameSystemPoses{
+		"gripper": referenceframe.NewPoseInFrame(referenceframe.World, spatialmath.NewPoseFromPoint(r3.Vector{X: 10})),
+	}, nil)
+	mocap2 := newTracker("mocap2", referenceframe.FrameSystemPoses{
+		"gripper": referenceframe.NewPoseInFrame("mocap2", spatialmath.NewPoseFromPoint(r3.Vector{X: -80})),
+		"dock":    referenceframe.NewPoseInFrame("mocap2", spatialmath.NewZeroPose()),
+	}, nil)
+	broken := newTracker("broken", nil, errors.New("lost"))
+
+	fs := inject.NewFrameSystemService("builtin")
+	fs.TransformPoseFunc = func(ctx context.Context, pose *referenceframe.PoseInFrame, dst string,
+		additionalTransforms []*referenceframe.LinkInFrame,
+	) (*referenceframe.PoseInFrame, error) {
+		test.That(t, pose.Parent(), test.ShouldEqual, "mocap2")
+		test.That(t, dst, test.ShouldEqual, referenceframe.World)
+		return referenceframe.NewPoseInFrame(dst, spatialmath.Compose(spatialmath.NewPoseFromPoint(r3.Vector{X: 100}), pose.Pose())), nil
+	}
+	deps := resource.Dependencies{
+		mocap1.Name():                   mocap1,
+		mocap2.Name():                   mocap2,
+		broken.Name():                   broken,
+		framesystem.InternalServiceName: fs,
+	}
+	newMerged := func(t *testing.T, conf *Config) posetracker.PoseTracker {
+		t.Helper()
+		tracker, err := newMergedModel(ctx, deps, resource.Config{
+			Name:                "merged",
+			API:                 posetracker.API,
+			Model:               model,
+			ConvertedAttributes: conf,
+		}, logging.NewTestLogger(t))
+		test.That(t, err, test.ShouldBeNil)
+		return tracker
+	}
+
+	t.Run("priority", func(t *testing.T) {
+		tracker := newMerged(t, &Config{PoseTrackers: []string{"broken", "mocap1", "mocap2"}})
+		poses, err := tracker.Poses(ctx, nil, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, poses, test.ShouldHaveLength, 2)
+		test.That(t, poses["gripper"].Parent(), test.ShouldEqual, referenceframe.World)
+		test.That(t, poses["gripper"].Pose().Point().X, test.ShouldAlmostEqual, 10)
+		test.That(t, poses["dock"].Pose().Point().X, test.ShouldAlmostEqual, 100)
+	})
+
+	t.Run("average", func(t *testing.T) {
+		tracker := newMerged(t, &Config{PoseTrackers: []string{"mocap1", "mocap2"}, ConflictResolution: resolutionAverage})
+		poses, err := tracker.Poses(ctx, nil, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, poses["gripper"].Pose().Point().X, test.ShouldAlmostEqual, 15)
+		test.That(t, poses["dock"].Pose().Point().X, test.ShouldAlmostEqual, 100)
+	})
+
+	t.Run("every tracker failing", func(t *testing.T) {
+		tracker := newMerged(t, &Config{PoseTrackers: []string{"broken"}})
+		_, err := tracker.Poses(ctx, nil, nil)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "lost")
+	})
+}
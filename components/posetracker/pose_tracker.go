@@ -5,11 +5,13 @@ package posetracker
 import (
 	"context"
 
+	"github.com/pkg/errors"
 	pb "go.viam.com/api/component/posetracker/v1"
 
 	"go.viam.com/rdk/referenceframe"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/robot/framesystem"
 )
 
 func init() {
@@ -44,3 +46,33 @@ type PoseTracker interface {
 func FromRobot(r robot.Robot, name string) (PoseTracker, error) {
 	return robot.ResourceFromRobot[PoseTracker](r, Named(name))
 }
+
+// PosesInFrame returns the poses of the bodies seen by the pose tracker like Poses, transformed from the frame of the pose
+// tracker into the given frame of the frame system, such as the world frame or the frame of an arm.
+func PosesInFrame(
+	ctx context.Context,
+	tracker PoseTracker,
+	fs framesystem.Service,
+	dst string,
+	bodyNames []string,
+	extra map[string]interface{},
+) (referenceframe.FrameSystemPoses, error) {
+	poses, err := tracker.Poses(ctx, bodyNames, extra)
+	if err != nil {
+		return nil, err
+	}
+	transformed := make(referenceframe.FrameSystemPoses, len(poses))
+	for body, pose := range poses {
+		if pose == nil {
+			continue
+		}
+		if pose.Parent() != dst {
+			pose, err = fs.TransformPose(ctx, pose, dst, nil)
+			if err != nil {
+				return nil, errors.Wrapf(err, "cannot transform the pose of %q into frame %q", body, dst)
+			}
+		}
+		transformed[body] = pose
+	}
+	return transformed, nil
+}
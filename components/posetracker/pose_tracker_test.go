@@ -0,0 +1,49 @@
+package posetracker_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/posetracker"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestPosesInFrame(t *testing.T) {
+	ctx := context.Background()
+	tracker := inject.NewPoseTracker(workingPTName)
+	tracker.PosesFunc = func(ctx context.Context, bodyNames []string, extra map[string]interface{},
+	) (referenceframe.FrameSystemPoses, error) {
+		return referenceframe.FrameSystemPoses{
+			bodyName:  referenceframe.NewPoseInFrame(workingPTName, spatialmath.NewPoseFromPoint(r3.Vector{X: 1})),
+			"inWorld": referenceframe.NewPoseInFrame(referenceframe.World, spatialmath.NewPoseFromPoint(r3.Vector{Y: 2})),
+		}, nil
+	}
+	fs := inject.NewFrameSystemService("builtin")
+	fs.TransformPoseFunc = func(ctx context.Context, pose *referenceframe.PoseInFrame, dst string,
+		additionalTransforms []*referenceframe.LinkInFrame,
+	) (*referenceframe.PoseInFrame, error) {
+		test.That(t, pose.Parent(), test.ShouldEqual, workingPTName)
+		// the pose tracker is 10mm above the world origin
+		return referenceframe.NewPoseInFrame(dst, spatialmath.Compose(spatialmath.NewPoseFromPoint(r3.Vector{Z: 10}), pose.Pose())), nil
+	}
+
+	poses, err := posetracker.PosesInFrame(ctx, tracker, fs, referenceframe.World, nil, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, poses, test.ShouldHaveLength, 2)
+	test.That(t, poses[bodyName].Parent(), test.ShouldEqual, referenceframe.World)
+	test.That(t, spatialmath.R3VectorAlmostEqual(poses[bodyName].Pose().Point(), r3.Vector{X: 1, Z: 10}, 1e-9), test.ShouldBeTrue)
+	// poses already in the requested frame are left as they are
+	test.That(t, spatialmath.R3VectorAlmostEqual(poses["inWorld"].Pose().Point(), r3.Vector{Y: 2}, 1e-9), test.ShouldBeTrue)
+
+	tracker.PosesFunc = func(ctx context.Context, bodyNames []string, extra map[string]interface{},
+	) (referenceframe.FrameSystemPoses, error) {
+		return nil, errPoseFailed
+	}
+	_, err = posetracker.PosesInFrame(ctx, tracker, fs, referenceframe.World, nil, nil)
+	test.That(t, err, test.ShouldBeError, errPoseFailed)
+}
@@ -0,0 +1,8 @@
+// Package register registers all relevant PoseTrackers
+package register
+
+import (
+	// Load all pose trackers.
+	_ "go.viam.com/rdk/components/posetracker/fiducial"
+	_ "go.viam.com/rdk/components/posetracker/merged"
+)
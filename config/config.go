@@ -732,6 +732,9 @@ type NetworkConfigData struct {
 
 	// Sessions configures session management.
 	Sessions SessionsConfig `json:"sessions"`
+
+	// Streams configures how the video of individual cameras is streamed over WebRTC.
+	Streams []CameraStreamConfig `json:"streams,omitempty"`
 }
 
 // MarshalJSON marshals out this config.
@@ -762,10 +765,55 @@ func (nc *NetworkConfig) Validate(path string) error {
 	if (nc.TLSCertFile == "") != (nc.TLSKeyFile == "") {
 		return resource.NewConfigValidationError(path, errors.New("must provide both tls_cert_file and tls_key_file"))
 	}
+	seenStreams := make(map[string]struct{}, len(nc.Streams))
+	for idx := range nc.Streams {
+		if err := nc.Streams[idx].Validate(fmt.Sprintf("%s.streams.%d", path, idx)); err != nil {
+			return err
+		}
+		if _, ok := seenStreams[nc.Streams[idx].Camera]; ok {
+			return resource.NewConfigValidationError(path,
+				errors.Errorf("camera %q may only have one stream config", nc.Streams[idx].Camera))
+		}
+		seenStreams[nc.Streams[idx].Camera] = struct{}{}
+	}
 
 	return nc.Sessions.Validate(path + ".sessions")
 }
 
+// CameraStreamConfig configures how the video of a camera is encoded for streaming, so that robots with many cameras can keep
+// their streams within the bandwidth of their link. Every stream is encoded with H.264, the only video codec with an encoder, so
+// the codec is not configurable.
+type CameraStreamConfig struct {
+	// Camera is the name of the camera whose stream is configured.
+	Camera string `json:"camera"`
+
+	// BitrateBPS is the bitrate the encoder targets, in bits per second. The default of the encoder is used when it is unset.
+	BitrateBPS int `json:"bitrate_bps,omitempty"`
+
+	// WidthPx and HeightPx are the resolution the video is resized to before it is encoded. The resolution of the camera is
+	// used when they are unset.
+	WidthPx  int `json:"width_px,omitempty"`
+	HeightPx int `json:"height_px,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (sc *CameraStreamConfig) Validate(path string) error {
+	if sc.Camera == "" {
+		return resource.NewConfigValidationFieldRequiredError(path, "camera")
+	}
+	if sc.BitrateBPS < 0 {
+		return resource.NewConfigValidationError(path, errors.New("bitrate_bps cannot be negative"))
+	}
+	if (sc.WidthPx == 0) != (sc.HeightPx == 0) {
+		return resource.NewConfigValidationError(path, errors.New("must provide both width_px and height_px"))
+	}
+	if sc.WidthPx < 0 || sc.HeightPx < 0 || sc.WidthPx%2 != 0 || sc.HeightPx%2 != 0 {
+		return resource.NewConfigValidationError(path,
+			errors.Errorf("width_px (%d) and height_px (%d) must be positive and even", sc.WidthPx, sc.HeightPx))
+	}
+	return nil
+}
+
 // SessionsConfig configures various parameters used in session management.
 type SessionsConfig struct {
 	// HeartbeatWindow is the window within which clients must send at least one
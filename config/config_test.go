@@ -362,6 +362,33 @@ func TestConfigEnsure(t *testing.T) {
 	invalidNetwork.Network.Sessions.HeartbeatWindow = 30 * time.Millisecond
 	test.That(t, invalidNetwork.Ensure(false, logger), test.ShouldBeNil)
 
+	invalidNetwork.Network.Streams = []config.CameraStreamConfig{{Camera: "cam", BitrateBPS: -1}}
+	err = invalidNetwork.Ensure(false, logger)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `streams.0`)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `bitrate_bps`)
+
+	invalidNetwork.Network.Streams = []config.CameraStreamConfig{{Camera: "cam", WidthPx: 640}}
+	err = invalidNetwork.Ensure(false, logger)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `both width_px and height_px`)
+
+	invalidNetwork.Network.Streams = []config.CameraStreamConfig{{Camera: "cam", WidthPx: 641, HeightPx: 480}}
+	err = invalidNetwork.Ensure(false, logger)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `even`)
+
+	invalidNetwork.Network.Streams = []config.CameraStreamConfig{{Camera: "cam"}, {Camera: "cam"}}
+	err = invalidNetwork.Ensure(false, logger)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `only have one stream config`)
+
+	invalidNetwork.Network.Streams = []config.CameraStreamConfig{
+		{Camera: "cam", BitrateBPS: 1_000_000, WidthPx: 640, HeightPx: 480},
+	}
+	test.That(t, invalidNetwork.Ensure(false, logger), test.ShouldBeNil)
+	invalidNetwork.Network.Streams = nil
+
 	invalidNetwork.Network.BindAddress = "woop"
 	err = invalidNetwork.Ensure(false, logger)
 	test.That(t, err, test.ShouldNotBeNil)
@@ -26,3 +26,9 @@ type VideoEncoderFactory interface {
 	New(height, width, keyFrameInterval int, logger logging.Logger) (VideoEncoder, error)
 	MIMEType() string
 }
+
+// A BitrateVideoEncoderFactory is a VideoEncoderFactory whose encoders can target a given bitrate, in bits per second.
+type BitrateVideoEncoderFactory interface {
+	VideoEncoderFactory
+	WithBitrate(bitrate int) VideoEncoderFactory
+}
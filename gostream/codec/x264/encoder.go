@@ -19,12 +19,17 @@ type encoder struct {
 	logger logging.Logger
 }
 
-// Gives suitable results. Probably want to make this configurable this in the future.
-const bitrate = 3_200_000
+// DefaultBitrate gives suitable results for a single stream.
+const DefaultBitrate = 3_200_000
 
 // NewEncoder returns an x264 encoder that can encode images of the given width and height. It will
 // also ensure that it produces key frames at the given interval.
 func NewEncoder(width, height, keyFrameInterval int, logger logging.Logger) (ourcodec.VideoEncoder, error) {
+	return NewEncoderWithBitrate(width, height, keyFrameInterval, DefaultBitrate, logger)
+}
+
+// NewEncoderWithBitrate returns an x264 encoder like NewEncoder which targets the given bitrate, in bits per second.
+func NewEncoderWithBitrate(width, height, keyFrameInterval, bitrate int, logger logging.Logger) (ourcodec.VideoEncoder, error) {
 	enc := &encoder{logger: logger}
 
 	var builder codec.VideoEncoderBuilder
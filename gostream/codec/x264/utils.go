@@ -14,14 +14,21 @@ func init() {
 }
 
 // NewEncoderFactory returns an x264 encoder factory.
-func NewEncoderFactory() codec.VideoEncoderFactory {
-	return &factory{}
+func NewEncoderFactory() codec.BitrateVideoEncoderFactory {
+	return &factory{bitrate: DefaultBitrate}
 }
 
-type factory struct{}
+type factory struct {
+	bitrate int
+}
 
 func (f *factory) New(width, height, keyFrameInterval int, logger logging.Logger) (codec.VideoEncoder, error) {
-	return NewEncoder(width, height, keyFrameInterval, logger)
+	return NewEncoderWithBitrate(width, height, keyFrameInterval, f.bitrate, logger)
+}
+
+// WithBitrate returns a factory of encoders which target the given bitrate instead.
+func (f *factory) WithBitrate(bitrate int) codec.VideoEncoderFactory {
+	return &factory{bitrate: bitrate}
 }
 
 func (f *factory) MIMEType() string {
@@ -44,6 +44,9 @@ type Stream interface {
 
 	// Stop stops further processing of frames.
 	Stop()
+
+	// VideoStats returns statistics of the video frames the stream has served.
+	VideoStats() VideoStats
 }
 
 type internalStream interface {
@@ -100,7 +103,7 @@ func NewStream(config StreamConfig, logger logging.Logger) (Stream, error) {
 
 		videoTrackLocal: trackLocal,
 		inputImageChan:  make(chan MediaReleasePair[image.Image]),
-		outputVideoChan: make(chan []byte),
+		outputVideoChan: make(chan encodedVideoFrame),
 
 		audioTrackLocal: audioTrackLocal,
 		inputAudioChan:  make(chan MediaReleasePair[wave.Audio]),
@@ -114,6 +117,12 @@ func NewStream(config StreamConfig, logger logging.Logger) (Stream, error) {
 	return bs, nil
 }
 
+// encodedVideoFrame is a frame encoded by a stream along with when it reached the stream.
+type encodedVideoFrame struct {
+	data       []byte
+	receivedAt time.Time
+}
+
 type basicStream struct {
 	mu               sync.RWMutex
 	name             string
@@ -123,8 +132,9 @@ type basicStream struct {
 
 	videoTrackLocal *trackLocalStaticSample
 	inputImageChan  chan MediaReleasePair[image.Image]
-	outputVideoChan chan []byte
+	outputVideoChan chan encodedVideoFrame
 	videoEncoder    codec.VideoEncoder
+	videoStats      videoStatsRecorder
 
 	audioTrackLocal *trackLocalStaticSample
 	inputAudioChan  chan MediaReleasePair[wave.Audio]
@@ -190,7 +200,7 @@ func (bs *basicStream) Stop() {
 	}
 
 	// reset
-	bs.outputVideoChan = make(chan []byte)
+	bs.outputVideoChan = make(chan encodedVideoFrame)
 	bs.outputAudioChan = make(chan []byte)
 	ctx, cancelFunc := context.WithCancel(context.Background())
 	bs.shutdownCtx = ctx
@@ -225,6 +235,10 @@ func (bs *basicStream) InputAudioChunks(props prop.Audio) (chan<- MediaReleasePa
 	return bs.inputAudioChan, nil
 }
 
+func (bs *basicStream) VideoStats() VideoStats {
+	return bs.videoStats.get()
+}
+
 func (bs *basicStream) VideoTrackLocal() (webrtc.TrackLocal, bool) {
 	return bs.videoTrackLocal, bs.videoTrackLocal != nil
 }
@@ -259,6 +273,7 @@ func (bs *basicStream) processInputFrames() {
 		if framePair.Media == nil {
 			continue
 		}
+		receivedAt := time.Now()
 		var initErr bool
 		func() {
 			if framePair.Release != nil {
@@ -309,18 +324,21 @@ func (bs *basicStream) processInputFrames() {
 
 				// thread-safe because the size is static
 				var err error
+				encodeStart := time.Now()
 				encodedFrame, err = bs.videoEncoder.Encode(bs.shutdownCtx, framePair.Media)
 				if err != nil {
+					bs.videoStats.failed()
 					bs.logger.Error(err)
 					return
 				}
+				bs.videoStats.encoded(time.Since(encodeStart))
 			}
 
 			if encodedFrame != nil {
 				select {
 				case <-bs.shutdownCtx.Done():
 					return
-				case bs.outputVideoChan <- encodedFrame:
+				case bs.outputVideoChan <- encodedVideoFrame{data: encodedFrame, receivedAt: receivedAt}:
 				}
 			}
 		}()
@@ -396,8 +414,10 @@ func (bs *basicStream) processOutputFrames() {
 		default:
 		}
 		now := time.Now()
-		if err := bs.videoTrackLocal.WriteData(outputFrame); err != nil {
+		if err := bs.videoTrackLocal.WriteData(outputFrame.data); err != nil {
 			bs.logger.Errorw("error writing frame", "error", err)
+		} else {
+			bs.videoStats.served(outputFrame.receivedAt, time.Now())
 		}
 		framesSent++
 		if Debug {
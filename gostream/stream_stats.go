@@ -0,0 +1,71 @@
+package gostream
+
+import (
+	"sync"
+	"time"
+)
+
+// statsSmoothing is the weight of the newest frame in the averages of VideoStats.
+const statsSmoothing = 0.1
+
+// VideoStats describes the video a stream has encoded and served, to help diagnose streams which fall behind their cameras.
+// Video passed through from a camera as RTP packets is not counted.
+type VideoStats struct {
+	// FramesServed is the number of frames written to the video track of the stream.
+	FramesServed int64
+	// FramesFailed is the number of frames which could not be encoded.
+	FramesFailed int64
+	// FPS is the rate at which frames are currently written to the video track.
+	FPS float64
+	// EncodeTime is the average time taken to encode a frame.
+	EncodeTime time.Duration
+	// Latency is an estimate of the average time from a frame reaching the stream to it being written to the video track,
+	// which includes the time it spends waiting to be encoded.
+	Latency time.Duration
+}
+
+// videoStatsRecorder accumulates the VideoStats of a stream, whose frames are encoded and written by different goroutines.
+type videoStatsRecorder struct {
+	mu          sync.Mutex
+	stats       VideoStats
+	lastFrameAt time.Time
+}
+
+// smooth returns the moving average of a value after the given sample of it.
+func smooth(avg, sample float64) float64 {
+	if avg == 0 {
+		return sample
+	}
+	return avg + statsSmoothing*(sample-avg)
+}
+
+func (r *videoStatsRecorder) encoded(encodeTime time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stats.EncodeTime = time.Duration(smooth(float64(r.stats.EncodeTime), float64(encodeTime)))
+}
+
+func (r *videoStatsRecorder) failed() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stats.FramesFailed++
+}
+
+func (r *videoStatsRecorder) served(receivedAt, servedAt time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.lastFrameAt.IsZero() {
+		if interval := servedAt.Sub(r.lastFrameAt); interval > 0 {
+			r.stats.FPS = smooth(r.stats.FPS, float64(time.Second)/float64(interval))
+		}
+	}
+	r.stats.FramesServed++
+	r.stats.Latency = time.Duration(smooth(float64(r.stats.Latency), float64(servedAt.Sub(receivedAt))))
+	r.lastFrameAt = servedAt
+}
+
+func (r *videoStatsRecorder) get() VideoStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.stats
+}
@@ -0,0 +1,33 @@
+package gostream
+
+import (
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+)
+
+func TestVideoStatsRecorder(t *testing.T) {
+	var recorder videoStatsRecorder
+	test.That(t, recorder.get(), test.ShouldResemble, VideoStats{})
+
+	start := time.Now()
+	recorder.encoded(10 * time.Millisecond)
+	recorder.served(start, start.Add(20*time.Millisecond))
+	stats := recorder.get()
+	test.That(t, stats.FramesServed, test.ShouldEqual, 1)
+	test.That(t, stats.EncodeTime, test.ShouldEqual, 10*time.Millisecond)
+	test.That(t, stats.Latency, test.ShouldEqual, 20*time.Millisecond)
+	// the rate is only known once there are two frames
+	test.That(t, stats.FPS, test.ShouldEqual, 0)
+
+	recorder.encoded(20 * time.Millisecond)
+	recorder.served(start.Add(50*time.Millisecond), start.Add(70*time.Millisecond))
+	recorder.failed()
+	stats = recorder.get()
+	test.That(t, stats.FramesServed, test.ShouldEqual, 2)
+	test.That(t, stats.FramesFailed, test.ShouldEqual, 1)
+	test.That(t, stats.EncodeTime, test.ShouldEqual, 11*time.Millisecond)
+	test.That(t, stats.Latency, test.ShouldEqual, 20*time.Millisecond)
+	test.That(t, stats.FPS, test.ShouldAlmostEqual, 20)
+}
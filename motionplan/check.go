@@ -28,6 +28,21 @@ func CheckPlan(
 	fs referenceframe.FrameSystem,
 	lookAheadDistanceMM float64,
 	logger logging.Logger,
+) error {
+	return CheckPlanWithConstraints(checkFrame, executionState, worldState, nil, fs, lookAheadDistanceMM, logger)
+}
+
+// CheckPlanWithConstraints checks the plan like CheckPlan, additionally checking the given constraints, which may be nil.
+// Of the constraints, only level constraints and collision paddings are checked, since the other constraints are relative to
+// the start and goal of the plan rather than to the state the check starts from.
+func CheckPlanWithConstraints(
+	checkFrame referenceframe.Frame, // TODO(RSDK-7421): remove this
+	executionState ExecutionState,
+	worldState *referenceframe.WorldState,
+	constraints *Constraints,
+	fs referenceframe.FrameSystem,
+	lookAheadDistanceMM float64,
+	logger logging.Logger,
 ) error {
 	plan := executionState.Plan()
 	wayPointIdx := executionState.Index()
@@ -60,18 +75,24 @@ func CheckPlan(
 		return err
 	}
 
+	checkedConstraints := &Constraints{
+		LevelConstraint:  constraints.GetLevelConstraint(),
+		CollisionPadding: constraints.GetCollisionPadding(),
+	}
+
 	// This should be done for any plan whose configurations are specified in relative terms rather than absolute ones.
 	// Currently this is only TP-space, so we check if the PTG length is >0.
 	if planOpts.useTPspace {
-		return checkPlanRelative(checkFrame, executionState, worldState, fs, lookAheadDistanceMM, sfPlanner)
+		return checkPlanRelative(checkFrame, executionState, worldState, checkedConstraints, fs, lookAheadDistanceMM, sfPlanner)
 	}
-	return checkPlanAbsolute(checkFrame, executionState, worldState, fs, lookAheadDistanceMM, sfPlanner)
+	return checkPlanAbsolute(checkFrame, executionState, worldState, checkedConstraints, fs, lookAheadDistanceMM, sfPlanner)
 }
 
 func checkPlanRelative(
 	checkFrame referenceframe.Frame, // TODO(RSDK-7421): remove this
 	executionState ExecutionState,
 	worldState *referenceframe.WorldState,
+	constraints *Constraints,
 	fs referenceframe.FrameSystem,
 	lookAheadDistanceMM float64,
 	sfPlanner *planManager,
@@ -100,7 +121,7 @@ func checkPlanRelative(
 		plan.Trajectory()[0],
 		worldState,
 		nil,
-		nil, // no pb.Constraints
+		constraints,
 		nil, // no plannOpts
 	); err != nil {
 		return err
@@ -192,13 +213,20 @@ func checkPlanRelative(
 		segments = append(segments, segment)
 	}
 
-	return checkSegments(sfPlanner, segments, lookAheadDistanceMM, checkFrame)
+	// level constraints are checked across the frame system, with the frames other than checkFrame at their current inputs
+	levelConstraints, err := createLevelConstraintsFS(sfPlanner.fs, constraints)
+	if err != nil {
+		return err
+	}
+
+	return checkSegments(sfPlanner, segments, lookAheadDistanceMM, checkFrame, levelConstraints, currentInputs)
 }
 
 func checkPlanAbsolute(
 	checkFrame referenceframe.Frame, // TODO(RSDK-7421): remove this
 	executionState ExecutionState,
 	worldState *referenceframe.WorldState,
+	constraints *Constraints,
 	fs referenceframe.FrameSystem,
 	lookAheadDistanceMM float64,
 	sfPlanner *planManager,
@@ -237,7 +265,7 @@ func checkPlanAbsolute(
 		startingInputs,
 		worldState,
 		nil,
-		nil, // no Constraints
+		constraints,
 		nil, // no planOpts
 	); err != nil {
 		return err
@@ -317,7 +345,14 @@ func checkSegmentsFS(sfPlanner *planManager, segments []*ik.SegmentFS, lookAhead
 }
 
 // TODO: Remove this function.
-func checkSegments(sfPlanner *planManager, segments []*ik.Segment, lookAheadDistanceMM float64, checkFrame referenceframe.Frame) error {
+func checkSegments(
+	sfPlanner *planManager,
+	segments []*ik.Segment,
+	lookAheadDistanceMM float64,
+	checkFrame referenceframe.Frame,
+	levelConstraints map[string]StateFSConstraint,
+	currentInputs referenceframe.FrameSystemInputs,
+) error {
 	// go through segments and check that we satisfy constraints
 	var totalTravelDistanceMM float64
 	for _, segment := range segments {
@@ -362,6 +397,24 @@ func checkSegments(sfPlanner *planManager, segments []*ik.Segment, lookAheadDist
 					err,
 				)
 			}
+
+			if len(levelConstraints) > 0 {
+				fsState := &ik.StateFS{Configuration: referenceframe.FrameSystemInputs{}, FS: sfPlanner.fs}
+				for name, inputs := range currentInputs {
+					fsState.Configuration[name] = inputs
+				}
+				fsState.Configuration[checkFrame.Name()] = interpConfig
+				for name, constraint := range levelConstraints {
+					if !constraint(fsState) {
+						return fmt.Errorf("found constraint violation in segment between %v and %v at %v: %s",
+							segment.StartPosition.Point(),
+							segment.EndPosition.Point(),
+							poseInPath.Point(),
+							name,
+						)
+					}
+				}
+			}
 		}
 
 		// Update total traveled distance after segment has been checked
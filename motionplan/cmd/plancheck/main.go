@@ -0,0 +1,236 @@
+//go:build !no_cgo
+
+// Plans a motion offline, without a live robot, and checks the plan against the constraints and obstacles of the request, so that
+// robot configurations can be validated in CI. The plan and any constraint violations are printed as JSON, and the exit status is
+// nonzero if planning fails or any constraint is violated. If planning fails, only the start configuration is checked.
+// The frame system is a robot.v1.FrameSystemConfigResponse, the world state a common.v1.WorldState, the goal a common.v1.PoseInFrame
+// and the constraints a service.motion.v1.Constraints, all as protobuf JSON. The start configuration maps frame names to their inputs,
+// defaulting to zero, and the options are the planner options as a JSON object.
+// $./plancheck -frame-system=fs.json -world-state=ws.json -start=start.json -frame=arm -goal=goal.json
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	commonpb "go.viam.com/api/common/v1"
+	robotpb "go.viam.com/api/robot/v1"
+	motionpb "go.viam.com/api/service/motion/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/referenceframe"
+)
+
+// checkConfig holds the paths of the files which describe a plan request, along with the name of the frame to move.
+type checkConfig struct {
+	frameSystem string
+	worldState  string
+	start       string
+	frame       string
+	goal        string
+	constraints string
+	options     string
+}
+
+// violation is a motionplan.ConstraintViolation as printed.
+type violation struct {
+	Step          int                  `json:"step"`
+	Constraint    string               `json:"constraint"`
+	Configuration map[string][]float64 `json:"configuration,omitempty"`
+}
+
+// checkResult is the outcome of planning and checking a request, as printed.
+type checkResult struct {
+	Trajectory []map[string][]float64 `json:"trajectory,omitempty"`
+	Violations []violation            `json:"violations"`
+	Error      string                 `json:"error,omitempty"`
+}
+
+// ok returns whether a plan was found without any constraint violations.
+func (res *checkResult) ok() bool {
+	return res.Error == "" && len(res.Violations) == 0
+}
+
+func main() {
+	cfg := checkConfig{}
+	flag.StringVar(&cfg.frameSystem, "frame-system", "", "path of the frame system config response as protobuf JSON")
+	flag.StringVar(&cfg.worldState, "world-state", "", "optional path of the world state as protobuf JSON")
+	flag.StringVar(&cfg.start, "start", "", "optional path of the start configuration, a JSON object of frame names to inputs")
+	flag.StringVar(&cfg.frame, "frame", "", "name of the frame to move to the goal")
+	flag.StringVar(&cfg.goal, "goal", "", "path of the goal pose in frame as protobuf JSON")
+	flag.StringVar(&cfg.constraints, "constraints", "", "optional path of the motion constraints as protobuf JSON")
+	flag.StringVar(&cfg.options, "options", "", "optional path of the planner options as a JSON object")
+	flag.Parse()
+	logger := logging.NewLogger("plancheck")
+
+	res, err := check(context.Background(), cfg, logger)
+	if err != nil {
+		logger.Fatal(err)
+	}
+	if err := writeResult(os.Stdout, res); err != nil {
+		logger.Fatal(err)
+	}
+	if !res.ok() {
+		os.Exit(1)
+	}
+}
+
+// check plans the request described by the config and checks its plan, returning an error only if the request could not be read
+// or checked. A failure to plan is reported in the result.
+func check(ctx context.Context, cfg checkConfig, logger logging.Logger) (*checkResult, error) {
+	request, err := readRequest(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+	res := &checkResult{Violations: []violation{}}
+	plan, err := motionplan.PlanMotion(ctx, request)
+	if err != nil {
+		res.Error = err.Error()
+		plan = nil
+	} else {
+		for _, step := range plan.Trajectory() {
+			res.Trajectory = append(res.Trajectory, inputsToFloats(step))
+		}
+	}
+	violations, err := motionplan.CheckConstraints(request, plan)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range violations {
+		res.Violations = append(res.Violations, violation{
+			Step:          v.Step,
+			Constraint:    v.Constraint,
+			Configuration: inputsToFloats(v.Configuration),
+		})
+	}
+	return res, nil
+}
+
+func writeResult(w io.Writer, res *checkResult) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(res)
+}
+
+func inputsToFloats(inputs referenceframe.FrameSystemInputs) map[string][]float64 {
+	if inputs == nil {
+		return nil
+	}
+	floats := make(map[string][]float64, len(inputs))
+	for name, frameInputs := range inputs {
+		floats[name] = referenceframe.InputsToFloats(frameInputs)
+	}
+	return floats
+}
+
+func readRequest(cfg checkConfig, logger logging.Logger) (*motionplan.PlanRequest, error) {
+	if cfg.frameSystem == "" || cfg.frame == "" || cfg.goal == "" {
+		return nil, errors.New("frame-system, frame and goal must be given")
+	}
+	fsResp := &robotpb.FrameSystemConfigResponse{}
+	if err := readProto(cfg.frameSystem, fsResp); err != nil {
+		return nil, err
+	}
+	parts := make([]*referenceframe.FrameSystemPart, 0, len(fsResp.GetFrameSystemConfigs()))
+	for _, fsc := range fsResp.GetFrameSystemConfigs() {
+		part, err := referenceframe.ProtobufToFrameSystemPart(fsc)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, part)
+	}
+	fs, err := referenceframe.NewFrameSystem("plancheck", parts, nil)
+	if err != nil {
+		return nil, err
+	}
+	if fs.Frame(cfg.frame) == nil {
+		return nil, referenceframe.NewFrameMissingError(cfg.frame)
+	}
+
+	var worldState *referenceframe.WorldState
+	if cfg.worldState != "" {
+		wsProto := &commonpb.WorldState{}
+		if err := readProto(cfg.worldState, wsProto); err != nil {
+			return nil, err
+		}
+		if worldState, err = referenceframe.WorldStateFromProtobuf(wsProto); err != nil {
+			return nil, err
+		}
+	}
+
+	start := referenceframe.NewZeroInputs(fs)
+	if cfg.start != "" {
+		startFloats := map[string][]float64{}
+		if err := readJSON(cfg.start, &startFloats); err != nil {
+			return nil, err
+		}
+		for name, floats := range startFloats {
+			if fs.Frame(name) == nil {
+				return nil, referenceframe.NewFrameMissingError(name)
+			}
+			start[name] = referenceframe.FloatsToInputs(floats)
+		}
+	}
+
+	goalProto := &commonpb.PoseInFrame{}
+	if err := readProto(cfg.goal, goalProto); err != nil {
+		return nil, err
+	}
+	goal := referenceframe.ProtobufToPoseInFrame(goalProto)
+
+	var constraints *motionplan.Constraints
+	if cfg.constraints != "" {
+		constraintsProto := &motionpb.Constraints{}
+		if err := readProto(cfg.constraints, constraintsProto); err != nil {
+			return nil, err
+		}
+		constraints = motionplan.ConstraintsFromProtobuf(constraintsProto)
+	}
+
+	options := map[string]interface{}{}
+	if cfg.options != "" {
+		if err := readJSON(cfg.options, &options); err != nil {
+			return nil, err
+		}
+	}
+
+	return &motionplan.PlanRequest{
+		Logger:      logger,
+		Goals:       []*motionplan.PlanState{motionplan.NewPlanState(referenceframe.FrameSystemPoses{cfg.frame: goal}, nil)},
+		FrameSystem: fs,
+		StartState:  motionplan.NewPlanState(nil, start),
+		WorldState:  worldState,
+		Constraints: constraints,
+		Options:     options,
+	}, nil
+}
+
+func readProto(path string, msg proto.Message) error {
+	bytes, err := os.ReadFile(path) //nolint:gosec
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("path=%q", path))
+	}
+	if err := protojson.Unmarshal(bytes, msg); err != nil {
+		return errors.Wrapf(err, "error parsing %q", path)
+	}
+	return nil
+}
+
+func readJSON(path string, v interface{}) error {
+	bytes, err := os.ReadFile(path) //nolint:gosec
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("path=%q", path))
+	}
+	if err := json.Unmarshal(bytes, v); err != nil {
+		return errors.Wrapf(err, "error parsing %q", path)
+	}
+	return nil
+}
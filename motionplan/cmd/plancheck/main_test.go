@@ -0,0 +1,98 @@
+//go:build !no_cgo
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	robotpb "go.viam.com/api/robot/v1"
+	"go.viam.com/test"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+func writeProto(t *testing.T, path string, msg proto.Message) {
+	t.Helper()
+	bytes, err := protojson.Marshal(msg)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, os.WriteFile(path, bytes, 0o644), test.ShouldBeNil)
+}
+
+func TestCheck(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	dir := t.TempDir()
+
+	model, err := referenceframe.ParseModelJSONFile(utils.ResolveFile("components/arm/example_kinematics/xarm6_kinematics_test.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+	part := &referenceframe.FrameSystemPart{
+		FrameConfig: referenceframe.NewLinkInFrame(referenceframe.World, spatialmath.NewZeroPose(), "xArm6", nil),
+		ModelFrame:  model,
+	}
+	fsc, err := part.ToProtobuf()
+	test.That(t, err, test.ShouldBeNil)
+	cfg := checkConfig{
+		frameSystem: filepath.Join(dir, "fs.json"),
+		frame:       "xArm6",
+		goal:        filepath.Join(dir, "goal.json"),
+	}
+	writeProto(t, cfg.frameSystem, &robotpb.FrameSystemConfigResponse{FrameSystemConfigs: []*robotpb.FrameSystemConfig{fsc}})
+
+	goal := spatialmath.NewPoseFromPoint(r3.Vector{X: 407, Y: 0, Z: 112})
+	writeProto(t, cfg.goal, referenceframe.PoseInFrameToProtobuf(referenceframe.NewPoseInFrame(referenceframe.World, goal)))
+
+	t.Run("valid plan", func(t *testing.T) {
+		res, err := check(context.Background(), cfg, logger)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, res.ok(), test.ShouldBeTrue)
+		test.That(t, len(res.Trajectory), test.ShouldBeGreaterThan, 1)
+		test.That(t, res.Trajectory[0]["xArm6"], test.ShouldResemble, make([]float64, 6))
+
+		var buf bytes.Buffer
+		test.That(t, writeResult(&buf, res), test.ShouldBeNil)
+		printed := checkResult{}
+		test.That(t, json.Unmarshal(buf.Bytes(), &printed), test.ShouldBeNil)
+		test.That(t, printed, test.ShouldResemble, *res)
+	})
+
+	t.Run("goal in an obstacle", func(t *testing.T) {
+		obstacle, err := spatialmath.NewBox(goal, r3.Vector{10, 10, 1}, "obstacle")
+		test.That(t, err, test.ShouldBeNil)
+		worldState, err := referenceframe.NewWorldState(
+			[]*referenceframe.GeometriesInFrame{referenceframe.NewGeometriesInFrame(referenceframe.World, []spatialmath.Geometry{obstacle})},
+			nil,
+		)
+		test.That(t, err, test.ShouldBeNil)
+		wsProto, err := worldState.ToProtobuf()
+		test.That(t, err, test.ShouldBeNil)
+		cfg := cfg
+		cfg.worldState = filepath.Join(dir, "ws.json")
+		writeProto(t, cfg.worldState, wsProto)
+
+		res, err := check(context.Background(), cfg, logger)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, res.ok(), test.ShouldBeFalse)
+		test.That(t, res.Error, test.ShouldNotBeEmpty)
+		test.That(t, res.Trajectory, test.ShouldBeEmpty)
+		test.That(t, res.Violations, test.ShouldBeEmpty)
+	})
+
+	t.Run("start of an unknown frame", func(t *testing.T) {
+		cfg := cfg
+		cfg.start = filepath.Join(dir, "start.json")
+		test.That(t, os.WriteFile(cfg.start, []byte(`{"gantry": [0]}`), 0o644), test.ShouldBeNil)
+
+		_, err := check(context.Background(), cfg, logger)
+		test.That(t, err, test.ShouldBeError, referenceframe.NewFrameMissingError("gantry"))
+	})
+}
@@ -135,6 +135,52 @@ func collisionSpecifications(
 	return allowedCollisions, nil
 }
 
+// collisionPadding maps the names of geometries to a clearance in mm which is kept around them when checking for collisions, on top
+// of the collision buffer.
+type collisionPadding map[string]float64
+
+// buffer returns the distance within which the two named geometries are considered to be in collision.
+func (p collisionPadding) buffer(name1, name2 string, collisionBufferMM float64) float64 {
+	return collisionBufferMM + p[name1] + p[name2]
+}
+
+// collisionPaddings resolves the given paddings to the geometries they apply to. As with collision specifications, a padding
+// naming a frame applies to all of its geometries, while any other name is taken to be the label of a single geometry, such as an
+// obstacle. Names which match no geometry of the frame system are kept as they are, since the obstacles they pad may only be
+// detected later on.
+func collisionPaddings(
+	paddings []CollisionPadding,
+	frameSystemGeometries map[string]*referenceframe.GeometriesInFrame,
+) (collisionPadding, error) {
+	if len(paddings) == 0 {
+		return nil, nil
+	}
+	resolved := collisionPadding{}
+	pad := func(name string, paddingMM float64) {
+		resolved[name] = math.Max(resolved[name], paddingMM)
+	}
+	for _, padding := range paddings {
+		if padding.PaddingMM < 0 {
+			return nil, fmt.Errorf("collision padding of %s can't be negative", padding.Name)
+		}
+		geomsInFrame, ok := frameSystemGeometries[padding.Name]
+		if !ok {
+			pad(padding.Name, padding.PaddingMM)
+			continue
+		}
+		for _, geom := range geomsInFrame.Geometries() {
+			pad(geom.Label(), padding.PaddingMM)
+		}
+		// components have their configured geometries on their origin frames
+		if originGeoms, ok := frameSystemGeometries[padding.Name+"_origin"]; ok {
+			for _, geom := range originGeoms.Geometries() {
+				pad(geom.Label(), padding.PaddingMM)
+			}
+		}
+	}
+	return resolved, nil
+}
+
 // geometryGraph is a struct that stores distance relationships between sets of geometries.
 type geometryGraph struct {
 	// x and y are the two sets of geometries, each of which will be compared to the geometries in the other set
@@ -193,15 +239,20 @@ type collisionGraph struct {
 	//    - true:  all distances will be determined and numerically reported
 	//    - false: collisions will be reported as bools, not numerically. Upon finding a collision, will exit early
 	reportDistances bool
+
+	// padding is the clearance kept around individual geometries, which may be nil
+	padding collisionPadding
 }
 
 // newCollisionGraph instantiates a collisionGraph object and checks for collisions between the x and y sets of geometries
 // collisions that are reported in the reference CollisionSystem argument will be ignored and not stored as edges in the graph.
-// if the set y is nil, the graph will be instantiated with y = x.
+// if the set y is nil, the graph will be instantiated with y = x. Geometries named in the padding are kept further apart than the
+// collision buffer by their padding.
 func newCollisionGraph(x, y []spatial.Geometry,
 	reference *collisionGraph,
 	reportDistances bool,
 	collisionBufferMM float64,
+	padding collisionPadding,
 ) (cg *collisionGraph, err error) {
 	if y == nil {
 		y = x
@@ -218,6 +269,7 @@ func newCollisionGraph(x, y []spatial.Geometry,
 	cg = &collisionGraph{
 		geometryGraph:   newGeometryGraph(xMap, yMap),
 		reportDistances: reportDistances,
+		padding:         padding,
 	}
 
 	var distance float64
@@ -227,15 +279,16 @@ func newCollisionGraph(x, y []spatial.Geometry,
 				// geometry pair already has distance information associated with it, or is comparing with itself - skip to next pair
 				continue
 			}
+			bufferMM := padding.buffer(xName, yName, collisionBufferMM)
 			if reference != nil && reference.collisionBetween(xName, yName, collisionBufferMM) {
 				// represent previously seen collisions as NaNs
 				// per IEE standards, any comparison with NaN will return false, so these will never be considered collisions
 				distance = math.NaN()
-			} else if distance, err = cg.checkCollision(xGeometry, yGeometry, collisionBufferMM); err != nil {
+			} else if distance, err = cg.checkCollision(xGeometry, yGeometry, bufferMM); err != nil {
 				return nil, err
 			}
 			cg.setDistance(xName, yName, distance)
-			if !reportDistances && distance <= collisionBufferMM {
+			if !reportDistances && distance <= bufferMM {
 				// collision found, can return early
 				return cg, nil
 			}
@@ -272,7 +325,7 @@ func (cg *collisionGraph) checkCollision(x, y spatial.Geometry, collisionBufferM
 // collisionBetween returns a bool describing if the collisionGraph has a collision between the two entities that are specified by name.
 func (cg *collisionGraph) collisionBetween(name1, name2 string, collisionBufferMM float64) bool {
 	if distance, ok := cg.getDistance(name1, name2); ok {
-		return distance <= collisionBufferMM
+		return distance <= cg.padding.buffer(name1, name2, collisionBufferMM)
 	}
 	return false
 }
@@ -282,7 +335,7 @@ func (cg *collisionGraph) collisions(collisionBufferMM float64) []Collision {
 	var collisions []Collision
 	for xName, row := range cg.distances {
 		for yName, distance := range row {
-			if distance <= collisionBufferMM {
+			if distance <= cg.padding.buffer(xName, yName, collisionBufferMM) {
 				collisions = append(collisions, Collision{xName, yName, distance})
 				if !cg.reportDistances {
 					// collision found, can return early
@@ -63,7 +63,7 @@ func TestCheckCollisions(t *testing.T) {
 	obstacles[1].SetLabel("obstacleCube444")
 	obstacles = append(obstacles, bc1.Transform(spatial.NewPoseFromPoint(r3.Vector{6, 6, 6})))
 	obstacles[2].SetLabel("obstacleCube666")
-	cg, err := newCollisionGraph(robot, obstacles, nil, true, defaultCollisionBufferMM)
+	cg, err := newCollisionGraph(robot, obstacles, nil, true, defaultCollisionBufferMM, nil)
 	test.That(t, err, test.ShouldBeNil)
 	expectedCollisions := []Collision{
 		{"robotCube333", "obstacleCube444", -1},
@@ -77,7 +77,7 @@ func TestCheckCollisions(t *testing.T) {
 	test.That(t, err, test.ShouldBeNil)
 	gf, _ := m.Geometries(make([]referenceframe.Input, len(m.DoF())))
 	test.That(t, gf, test.ShouldNotBeNil)
-	cg, err = newCollisionGraph(gf.Geometries(), gf.Geometries(), nil, true, defaultCollisionBufferMM)
+	cg, err = newCollisionGraph(gf.Geometries(), gf.Geometries(), nil, true, defaultCollisionBufferMM, nil)
 	test.That(t, err, test.ShouldBeNil)
 	test.That(t, len(cg.collisions(defaultCollisionBufferMM)), test.ShouldEqual, 4)
 }
@@ -96,6 +96,7 @@ func TestUniqueCollisions(t *testing.T) {
 		nil,
 		true,
 		defaultCollisionBufferMM,
+		nil,
 	)
 	test.That(t, err, test.ShouldBeNil)
 
@@ -109,6 +110,7 @@ func TestUniqueCollisions(t *testing.T) {
 		zeroPositionCG,
 		true,
 		defaultCollisionBufferMM,
+		nil,
 	)
 	test.That(t, err, test.ShouldBeNil)
 	test.That(t, len(cg.collisions(defaultCollisionBufferMM)), test.ShouldEqual, 0)
@@ -123,6 +125,7 @@ func TestUniqueCollisions(t *testing.T) {
 		zeroPositionCG,
 		true,
 		defaultCollisionBufferMM,
+		nil,
 	)
 	test.That(t, err, test.ShouldBeNil)
 	expectedCollisions := []Collision{{"xArm6:base_top", "xArm6:wrist_link", -66.6}, {"xArm6:wrist_link", "xArm6:upper_arm", -48.1}}
@@ -137,7 +140,42 @@ func TestUniqueCollisions(t *testing.T) {
 		zeroPositionCG,
 		true,
 		defaultCollisionBufferMM,
+		nil,
 	)
 	test.That(t, err, test.ShouldBeNil)
 	test.That(t, collisionListsAlmostEqual(cg.collisions(defaultCollisionBufferMM), expectedCollisions[:1]), test.ShouldBeTrue)
 }
+
+func TestCollisionPadding(t *testing.T) {
+	robot, err := spatial.NewSphere(spatial.NewZeroPose(), 5, "robot")
+	test.That(t, err, test.ShouldBeNil)
+	obstacle, err := spatial.NewSphere(spatial.NewPoseFromPoint(r3.Vector{X: 30}), 5, "obstacle")
+	test.That(t, err, test.ShouldBeNil)
+	frameSystemGeometries := map[string]*referenceframe.GeometriesInFrame{
+		"base": referenceframe.NewGeometriesInFrame(referenceframe.World, []spatial.Geometry{robot}),
+	}
+
+	// the spheres are 20mm apart
+	for _, tc := range []struct {
+		paddings []CollisionPadding
+		collides bool
+	}{
+		{nil, false},
+		{[]CollisionPadding{{Name: "base", PaddingMM: 15}}, false},
+		{[]CollisionPadding{{Name: "base", PaddingMM: 25}}, true},
+		{[]CollisionPadding{{Name: "base", PaddingMM: 10}, {Name: "obstacle", PaddingMM: 15}}, true},
+	} {
+		padding, err := collisionPaddings(tc.paddings, frameSystemGeometries)
+		test.That(t, err, test.ShouldBeNil)
+		for _, reportDistances := range []bool{false, true} {
+			cg, err := newCollisionGraph(
+				[]spatial.Geometry{robot}, []spatial.Geometry{obstacle}, nil, reportDistances, defaultCollisionBufferMM, padding,
+			)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, len(cg.collisions(defaultCollisionBufferMM)) > 0, test.ShouldEqual, tc.collides)
+		}
+	}
+
+	_, err = collisionPaddings([]CollisionPadding{{Name: "base", PaddingMM: -1}}, frameSystemGeometries)
+	test.That(t, err, test.ShouldNotBeNil)
+}
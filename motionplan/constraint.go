@@ -94,6 +94,7 @@ func createAllCollisionConstraints(
 	movingRobotGeometries, staticRobotGeometries, worldGeometries, boundingRegions []spatial.Geometry,
 	allowedCollisions []*Collision,
 	collisionBufferMM float64,
+	padding collisionPadding,
 ) (map[string]StateFSConstraint, map[string]StateConstraint, error) {
 	constraintFSMap := map[string]StateFSConstraint{}
 	constraintMap := map[string]StateConstraint{}
@@ -106,7 +107,7 @@ func createAllCollisionConstraints(
 		for _, geom := range worldGeometries {
 			if octree, ok := geom.(*pointcloud.BasicOctree); ok {
 				if zeroCG == nil {
-					zeroCG, err = setupZeroCG(movingRobotGeometries, worldGeometries, allowedCollisions, collisionBufferMM)
+					zeroCG, err = setupZeroCG(movingRobotGeometries, worldGeometries, allowedCollisions, collisionBufferMM, nil)
 					if err != nil {
 						return nil, nil, err
 					}
@@ -122,12 +123,16 @@ func createAllCollisionConstraints(
 		}
 
 		// create constraint to keep moving geometries from hitting world state obstacles
-		obstacleConstraint, err := NewCollisionConstraint(movingRobotGeometries, worldGeometries, allowedCollisions, false, collisionBufferMM)
+		obstacleConstraint, err := newCollisionConstraint(
+			movingRobotGeometries, worldGeometries, allowedCollisions, false, collisionBufferMM, padding,
+		)
 		if err != nil {
 			return nil, nil, err
 		}
 		// create constraint to keep moving geometries from hitting world state obstacles
-		obstacleConstraintFS, err := NewCollisionConstraintFS(movingRobotGeometries, worldGeometries, allowedCollisions, false, collisionBufferMM)
+		obstacleConstraintFS, err := newCollisionConstraintFS(
+			movingRobotGeometries, worldGeometries, allowedCollisions, false, collisionBufferMM, padding,
+		)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -185,9 +190,10 @@ func createAllCollisionConstraints(
 func setupZeroCG(moving, static []spatial.Geometry,
 	collisionSpecifications []*Collision,
 	collisionBufferMM float64,
+	padding collisionPadding,
 ) (*collisionGraph, error) {
 	// create the reference collisionGraph
-	zeroCG, err := newCollisionGraph(moving, static, nil, true, collisionBufferMM)
+	zeroCG, err := newCollisionGraph(moving, static, nil, true, collisionBufferMM, padding)
 	if err != nil {
 		return nil, err
 	}
@@ -207,7 +213,19 @@ func NewCollisionConstraint(
 	reportDistances bool,
 	collisionBufferMM float64,
 ) (StateConstraint, error) {
-	zeroCG, err := setupZeroCG(moving, static, collisionSpecifications, collisionBufferMM)
+	return newCollisionConstraint(moving, static, collisionSpecifications, reportDistances, collisionBufferMM, nil)
+}
+
+// newCollisionConstraint creates a collision constraint which keeps the geometries named in the padding further from each other than
+// the collision buffer by their padding.
+func newCollisionConstraint(
+	moving, static []spatial.Geometry,
+	collisionSpecifications []*Collision,
+	reportDistances bool,
+	collisionBufferMM float64,
+	padding collisionPadding,
+) (StateConstraint, error) {
+	zeroCG, err := setupZeroCG(moving, static, collisionSpecifications, collisionBufferMM, padding)
 	if err != nil {
 		return nil, err
 	}
@@ -238,7 +256,7 @@ func NewCollisionConstraint(
 			return false
 		}
 
-		cg, err := newCollisionGraph(internalGeoms, static, zeroCG, reportDistances, collisionBufferMM)
+		cg, err := newCollisionGraph(internalGeoms, static, zeroCG, reportDistances, collisionBufferMM, padding)
 		if err != nil {
 			return false
 		}
@@ -257,7 +275,19 @@ func NewCollisionConstraintFS(
 	reportDistances bool,
 	collisionBufferMM float64,
 ) (StateFSConstraint, error) {
-	zeroCG, err := setupZeroCG(moving, static, collisionSpecifications, collisionBufferMM)
+	return newCollisionConstraintFS(moving, static, collisionSpecifications, reportDistances, collisionBufferMM, nil)
+}
+
+// newCollisionConstraintFS creates a collision constraint for a frame system which keeps the geometries named in the padding further
+// from each other than the collision buffer by their padding.
+func newCollisionConstraintFS(
+	moving, static []spatial.Geometry,
+	collisionSpecifications []*Collision,
+	reportDistances bool,
+	collisionBufferMM float64,
+	padding collisionPadding,
+) (StateFSConstraint, error) {
+	zeroCG, err := setupZeroCG(moving, static, collisionSpecifications, collisionBufferMM, padding)
 	if err != nil {
 		return nil, err
 	}
@@ -284,7 +314,7 @@ func NewCollisionConstraintFS(
 			}
 		}
 
-		cg, err := newCollisionGraph(internalGeoms, static, zeroCG, reportDistances, collisionBufferMM)
+		cg, err := newCollisionGraph(internalGeoms, static, zeroCG, reportDistances, collisionBufferMM, padding)
 		if err != nil {
 			return false
 		}
@@ -414,6 +444,78 @@ func NewLineConstraint(pt1, pt2 r3.Vector, tolerance float64) (StateConstraint,
 	return validFunc, gradFunc
 }
 
+// NewSingularityConstraint is used to keep a frame away from kinematic singularities, near which the inputs of an arm must move ever
+// faster to move its end at all, and will return 1) a constraint function which will determine whether the manipulability of a
+// configuration, as measured by ik.Manipulability, is at least minManipulability, and 2) a metric which penalizes configurations by how
+// far their manipulability falls short of it.
+func NewSingularityConstraint(minManipulability float64) (StateConstraint, ik.StateMetric) {
+	gradFunc := func(state *ik.State) float64 {
+		manipulability, err := ik.Manipulability(state.Frame, state.Configuration)
+		if err != nil {
+			return math.Inf(1)
+		}
+		return math.Max(minManipulability-manipulability, 0)
+	}
+
+	validFunc := func(state *ik.State) bool {
+		return gradFunc(state) == 0
+	}
+
+	return validFunc, gradFunc
+}
+
+const (
+	// torqueStepRads is the greatest change of any input between the states of a segment at which the loads on the joints of a frame
+	// are estimated by a torque constraint.
+	torqueStepRads = 0.05
+	// torqueMaxSteps bounds the number of states along a segment at which loads are estimated, as translational inputs change by mm.
+	torqueMaxSteps = 100
+)
+
+// NewTorqueConstraint is used to keep the loads on the joints of a model, which carries the given payload, within the MaxTorque of
+// each of its joints, and will return a constraint function which will determine whether they are within their limits at states
+// along a segment. Each joint which moves across the segment is taken to accelerate at up to maxJointAcceleration, in rad/s^2, or in
+// mm/s^2 for translational joints, in either direction. The payload may be nil.
+func NewTorqueConstraint(payload *referenceframe.Payload, maxJointAcceleration float64) SegmentConstraint {
+	return func(segment *ik.Segment) bool {
+		model, ok := segment.Frame.(referenceframe.Model)
+		if !ok {
+			return false
+		}
+		limits := referenceframe.JointLoadLimits(model)
+		start, end := segment.StartConfiguration, segment.EndConfiguration
+		if len(start) != len(limits) || len(end) != len(limits) {
+			return false
+		}
+		accelerations := make([]float64, len(limits))
+		steps := 1
+		for i := range limits {
+			delta := math.Abs(end[i].Value - start[i].Value)
+			if delta > 0 {
+				accelerations[i] = maxJointAcceleration
+			}
+			steps = int(math.Max(float64(steps), math.Ceil(delta/torqueStepRads)))
+		}
+		steps = int(math.Min(float64(steps), torqueMaxSteps))
+		for step := 0; step <= steps; step++ {
+			inputs, err := model.Interpolate(start, end, float64(step)/float64(steps))
+			if err != nil {
+				return false
+			}
+			loads, err := referenceframe.ComputeJointLoads(model, inputs, payload)
+			if err != nil {
+				return false
+			}
+			for i, load := range loads.PeakLoads(accelerations) {
+				if limits[i] > 0 && load > limits[i] {
+					return false
+				}
+			}
+		}
+		return true
+	}
+}
+
 // NewOctreeCollisionConstraint takes an octree and will return a constraint that checks whether any geometries
 // intersect with points in the octree. Threshold sets the confidence level required for a point to be considered, and buffer is the
 // distance to a point that is considered a collision in mm.
@@ -462,7 +564,7 @@ func NewBoundingRegionConstraint(robotGeoms, boundingRegions []spatial.Geometry,
 		default:
 			internalGeoms = robotGeoms
 		}
-		cg, err := newCollisionGraph(internalGeoms, boundingRegions, nil, true, collisionBufferMM)
+		cg, err := newCollisionGraph(internalGeoms, boundingRegions, nil, true, collisionBufferMM, nil)
 		if err != nil {
 			return false
 		}
@@ -470,6 +572,79 @@ func NewBoundingRegionConstraint(robotGeoms, boundingRegions []spatial.Geometry,
 	}
 }
 
+// NewInteractionSpaceConstraint returns a constraint which is satisfied when each of the given robot geometries, positioned at the
+// state, is fully encompassed by one of the interaction spaces. Unlike a bounding region, which the robot need only touch, an
+// interaction space is a keep-in region for the whole robot.
+func NewInteractionSpaceConstraint(robotGeoms, interactionSpaces []spatial.Geometry) StateConstraint {
+	return func(state *ik.State) bool {
+		internalGeoms := robotGeoms
+		switch {
+		case state.Configuration != nil:
+			internal, err := state.Frame.Geometries(state.Configuration)
+			if err != nil {
+				return false
+			}
+			internalGeoms = internal.Geometries()
+		case state.Position != nil:
+			// TODO(RSDK-5391): remove this case
+			internal, err := state.Frame.Geometries(make([]referenceframe.Input, len(state.Frame.DoF())))
+			if err != nil {
+				return false
+			}
+			internalGeoms = nil
+			for _, geom := range internal.Geometries() {
+				internalGeoms = append(internalGeoms, geom.Transform(state.Position))
+			}
+		}
+		return withinInteractionSpaces(internalGeoms, interactionSpaces)
+	}
+}
+
+// NewInteractionSpaceConstraintFS returns a constraint which is satisfied when each of the moving geometries of the frame system,
+// positioned at the state, is fully encompassed by one of the interaction spaces.
+func NewInteractionSpaceConstraintFS(moving, interactionSpaces []spatial.Geometry) StateFSConstraint {
+	movingMap := map[string]bool{}
+	for _, geom := range moving {
+		movingMap[geom.Label()] = true
+	}
+	return func(state *ik.StateFS) bool {
+		internalGeometries, err := referenceframe.FrameSystemGeometries(state.FS, state.Configuration)
+		if err != nil {
+			return false
+		}
+		var internalGeoms []spatial.Geometry
+		for _, geosInFrame := range internalGeometries {
+			for _, geom := range geosInFrame.Geometries() {
+				if movingMap[geom.Label()] {
+					internalGeoms = append(internalGeoms, geom)
+				}
+			}
+		}
+		return withinInteractionSpaces(internalGeoms, interactionSpaces)
+	}
+}
+
+// withinInteractionSpaces returns whether each of the geometries is fully encompassed by at least one of the interaction spaces.
+func withinInteractionSpaces(geoms, interactionSpaces []spatial.Geometry) bool {
+	for _, geom := range geoms {
+		within := false
+		for _, space := range interactionSpaces {
+			encompassed, err := geom.EncompassedBy(space)
+			if err != nil {
+				return false
+			}
+			if encompassed {
+				within = true
+				break
+			}
+		}
+		if !within {
+			return false
+		}
+	}
+	return true
+}
+
 // LinearConstraint specifies that the components being moved should move linearly relative to their goals.
 type LinearConstraint struct {
 	LineToleranceMm          float64 // Max linear deviation from straight-line between start and goal, in mm.
@@ -490,6 +665,46 @@ type OrientationConstraint struct {
 	OrientationToleranceDegs float64
 }
 
+// LevelConstraint specifies that a frame will keep its z axis within some angle of vertical, pointing either up or down, so that a
+// payload carried by the frame is not tipped.
+type LevelConstraint struct {
+	Frame         string
+	ToleranceDegs float64
+}
+
+// HitchConstraint specifies that a frame planned for along PTG trajectories tows a trailer, and may only follow trajectories along
+// which the trailer cannot jackknife: it may not reverse, and may not turn so tightly that the trailer swings further than
+// MaxHitchAngleDegs from straight behind it.
+type HitchConstraint struct {
+	Frame string
+	// HitchOffsetMM is how far behind the center of rotation of the frame the trailer is hitched.
+	HitchOffsetMM float64
+	// TrailerLengthMM is the distance from the hitch to the axle of the trailer.
+	TrailerLengthMM   float64
+	MaxHitchAngleDegs float64
+}
+
+// SingularityConstraint specifies that a frame, such as an arm, will keep its manipulability, as measured by ik.Manipulability, at
+// least MinManipulability throughout a motion, so that it does not pass near a kinematic singularity, where following a Cartesian
+// path would demand runaway joint velocities.
+type SingularityConstraint struct {
+	Frame             string
+	MinManipulability float64
+}
+
+// TorqueConstraint specifies that the loads on the joints of a frame, such as an arm carrying a payload, will stay within the MaxTorque
+// of each of its joints throughout a motion. Loads are estimated from the masses of the links of the model of the frame, so frames
+// whose models describe neither masses nor limits are unconstrained.
+type TorqueConstraint struct {
+	Frame     string
+	PayloadKg float64
+	// PayloadCenterOfMass is the center of mass of the payload, in mm, in the frame of the end effector.
+	PayloadCenterOfMass r3.Vector
+	// MaxJointAcceleration is the greatest acceleration of each joint, in rad/s^2, or in mm/s^2 for translational joints. If zero, the
+	// loads needed to hold the frame still against gravity alone are constrained.
+	MaxJointAcceleration float64
+}
+
 // CollisionSpecificationAllowedFrameCollisions is used to define frames that are allowed to collide.
 type CollisionSpecificationAllowedFrameCollisions struct {
 	Frame1, Frame2 string
@@ -501,13 +716,39 @@ type CollisionSpecification struct {
 	Allows []CollisionSpecificationAllowedFrameCollisions
 }
 
+// CollisionPadding keeps a clearance around the geometries of a frame, or around a single geometry such as an obstacle, so that
+// plans pass them with room to spare rather than grazing them.
+type CollisionPadding struct {
+	// Name is the name of a frame, whose geometries are all padded, or the label of a single geometry.
+	Name      string
+	PaddingMM float64
+}
+
+// SoftConstraint is a preference rather than a requirement of a plan, such as staying near the centerline of a corridor or making
+// little use of a joint. Rather than ruling out states, the cost of each state of a plan, multiplied by the weight, is added to the
+// cost which the planner minimizes.
+type SoftConstraint struct {
+	Name   string
+	Weight float64
+	// Cost returns how far the state is from what is preferred. Lower is better.
+	Cost ik.StateFSMetric
+}
+
 // Constraints is a struct to store the constraints imposed upon a robot
 // It serves as a convenenient RDK wrapper for the protobuf object.
+// LevelConstraints, HitchConstraints, SingularityConstraints, TorqueConstraints, CollisionPaddings and SoftConstraints have no protobuf
+// equivalent and are not converted to or from protobuf.
 type Constraints struct {
 	LinearConstraint       []LinearConstraint
 	PseudolinearConstraint []PseudolinearConstraint
 	OrientationConstraint  []OrientationConstraint
 	CollisionSpecification []CollisionSpecification
+	LevelConstraint        []LevelConstraint
+	HitchConstraint        []HitchConstraint
+	SingularityConstraint  []SingularityConstraint
+	TorqueConstraint       []TorqueConstraint
+	CollisionPadding       []CollisionPadding
+	SoftConstraint         []SoftConstraint
 }
 
 // NewEmptyConstraints creates a new, empty Constraints object.
@@ -517,6 +758,12 @@ func NewEmptyConstraints() *Constraints {
 		PseudolinearConstraint: make([]PseudolinearConstraint, 0),
 		OrientationConstraint:  make([]OrientationConstraint, 0),
 		CollisionSpecification: make([]CollisionSpecification, 0),
+		LevelConstraint:        make([]LevelConstraint, 0),
+		HitchConstraint:        make([]HitchConstraint, 0),
+		SingularityConstraint:  make([]SingularityConstraint, 0),
+		TorqueConstraint:       make([]TorqueConstraint, 0),
+		CollisionPadding:       make([]CollisionPadding, 0),
+		SoftConstraint:         make([]SoftConstraint, 0),
 	}
 }
 
@@ -532,6 +779,12 @@ func NewConstraints(
 		PseudolinearConstraint: pseudoConstraints,
 		OrientationConstraint:  orientConstraints,
 		CollisionSpecification: collSpecifications,
+		LevelConstraint:        make([]LevelConstraint, 0),
+		HitchConstraint:        make([]HitchConstraint, 0),
+		SingularityConstraint:  make([]SingularityConstraint, 0),
+		TorqueConstraint:       make([]TorqueConstraint, 0),
+		CollisionPadding:       make([]CollisionPadding, 0),
+		SoftConstraint:         make([]SoftConstraint, 0),
 	}
 }
 
@@ -706,6 +959,84 @@ func (c *Constraints) GetCollisionSpecification() []CollisionSpecification {
 	return nil
 }
 
+// AddLevelConstraint appends a LevelConstraint to a Constraints object.
+func (c *Constraints) AddLevelConstraint(levelConstraint LevelConstraint) {
+	c.LevelConstraint = append(c.LevelConstraint, levelConstraint)
+}
+
+// GetLevelConstraint checks if the Constraints object is nil and if not then returns its LevelConstraint field.
+func (c *Constraints) GetLevelConstraint() []LevelConstraint {
+	if c != nil {
+		return c.LevelConstraint
+	}
+	return nil
+}
+
+// AddHitchConstraint appends a HitchConstraint to a Constraints object.
+func (c *Constraints) AddHitchConstraint(hitchConstraint HitchConstraint) {
+	c.HitchConstraint = append(c.HitchConstraint, hitchConstraint)
+}
+
+// GetHitchConstraint checks if the Constraints object is nil and if not then returns its HitchConstraint field.
+func (c *Constraints) GetHitchConstraint() []HitchConstraint {
+	if c != nil {
+		return c.HitchConstraint
+	}
+	return nil
+}
+
+// AddSingularityConstraint appends a SingularityConstraint to a Constraints object.
+func (c *Constraints) AddSingularityConstraint(singularityConstraint SingularityConstraint) {
+	c.SingularityConstraint = append(c.SingularityConstraint, singularityConstraint)
+}
+
+// GetSingularityConstraint checks if the Constraints object is nil and if not then returns its SingularityConstraint field.
+func (c *Constraints) GetSingularityConstraint() []SingularityConstraint {
+	if c != nil {
+		return c.SingularityConstraint
+	}
+	return nil
+}
+
+// AddTorqueConstraint appends a TorqueConstraint to a Constraints object.
+func (c *Constraints) AddTorqueConstraint(torqueConstraint TorqueConstraint) {
+	c.TorqueConstraint = append(c.TorqueConstraint, torqueConstraint)
+}
+
+// GetTorqueConstraint checks if the Constraints object is nil and if not then returns its TorqueConstraint field.
+func (c *Constraints) GetTorqueConstraint() []TorqueConstraint {
+	if c != nil {
+		return c.TorqueConstraint
+	}
+	return nil
+}
+
+// AddCollisionPadding appends a CollisionPadding to a Constraints object.
+func (c *Constraints) AddCollisionPadding(padding CollisionPadding) {
+	c.CollisionPadding = append(c.CollisionPadding, padding)
+}
+
+// GetCollisionPadding checks if the Constraints object is nil and if not then returns its CollisionPadding field.
+func (c *Constraints) GetCollisionPadding() []CollisionPadding {
+	if c != nil {
+		return c.CollisionPadding
+	}
+	return nil
+}
+
+// AddSoftConstraint appends a SoftConstraint to a Constraints object.
+func (c *Constraints) AddSoftConstraint(softConstraint SoftConstraint) {
+	c.SoftConstraint = append(c.SoftConstraint, softConstraint)
+}
+
+// GetSoftConstraint checks if the Constraints object is nil and if not then returns its SoftConstraint field.
+func (c *Constraints) GetSoftConstraint() []SoftConstraint {
+	if c != nil {
+		return c.SoftConstraint
+	}
+	return nil
+}
+
 type fsPathConstraint struct {
 	metricMap     map[string]ik.StateMetric
 	constraintMap map[string]StateConstraint
@@ -832,6 +1163,26 @@ func CreateSlerpOrientationConstraintFS(
 	return constraintInternal.constraint, constraintInternal.metric, nil
 }
 
+// CreateLevelConstraintFS returns a constraint which checks whether the z axis of the given frame is within the given tolerance, in
+// degrees, of vertical, pointing either up or down.
+func CreateLevelConstraintFS(fs referenceframe.FrameSystem, frame string, toleranceDegs float64) (StateFSConstraint, error) {
+	if fs.Frame(frame) == nil {
+		return nil, referenceframe.NewFrameMissingError(frame)
+	}
+	if toleranceDegs <= 0 || toleranceDegs >= 90 {
+		return nil, fmt.Errorf("level constraint tolerance for frame %s must be between 0 and 90 degrees, got %f", frame, toleranceDegs)
+	}
+	minVertical := math.Cos(toleranceDegs * math.Pi / 180)
+	return func(state *ik.StateFS) bool {
+		tf, err := fs.Transform(state.Configuration, referenceframe.NewZeroPoseInFrame(frame), referenceframe.World)
+		if err != nil {
+			return false
+		}
+		ov := tf.(*referenceframe.PoseInFrame).Pose().Orientation().OrientationVectorRadians()
+		return math.Abs(ov.OZ)/r3.Vector{X: ov.OX, Y: ov.OY, Z: ov.OZ}.Norm() >= minVertical
+	}, nil
+}
+
 // CreateLineConstraintFS will measure the linear distance between the positions of two poses across a frame system,
 // and return a constraint that checks whether given positions are within a specified tolerance distance of the shortest
 // line segment between their respective positions, as well as a metric which returns the distance to that valid region.
@@ -13,11 +13,21 @@ var defaultMinStepCount = 2
 
 // ConstraintHandler is a convenient wrapper for constraint handling which is likely to be common among most motion
 // planners. Including a constraint handler as an anonymous struct member allows reuse.
+//
+// Soft constraints are preferences rather than requirements. Instead of ruling out states, each adds its weighted cost at the states
+// of a plan to the cost which the planner minimizes, so that they can never make a problem infeasible.
 type ConstraintHandler struct {
-	segmentConstraints   map[string]SegmentConstraint
-	segmentFSConstraints map[string]SegmentFSConstraint
-	stateConstraints     map[string]StateConstraint
-	stateFSConstraints   map[string]StateFSConstraint
+	segmentConstraints     map[string]SegmentConstraint
+	segmentFSConstraints   map[string]SegmentFSConstraint
+	stateConstraints       map[string]StateConstraint
+	stateFSConstraints     map[string]StateFSConstraint
+	softStateFSConstraints map[string]softConstraint
+}
+
+// softConstraint is a cost of a state and the weight it is added to the cost of a plan with.
+type softConstraint struct {
+	cost   ik.StateFSMetric
+	weight float64
 }
 
 // CheckStateConstraints will check a given input against all state constraints.
@@ -353,3 +363,51 @@ func (c *ConstraintHandler) CheckSegmentAndStateValidityFS(segment *ik.SegmentFS
 	valid, _ = c.CheckSegmentFSConstraints(segment)
 	return valid, nil
 }
+
+// AddSoftStateFSConstraint will add or overwrite a soft constraint with a given name. The cost function should return how far the
+// given state is from what is preferred, which is multiplied by the weight before being added to the cost of a plan.
+func (c *ConstraintHandler) AddSoftStateFSConstraint(name string, cost ik.StateFSMetric, weight float64) {
+	if c.softStateFSConstraints == nil {
+		c.softStateFSConstraints = map[string]softConstraint{}
+	}
+	name = name + "_" + fmt.Sprintf("%p", cost)
+	c.softStateFSConstraints[name] = softConstraint{cost: cost, weight: weight}
+}
+
+// RemoveSoftStateFSConstraint will remove the given soft constraint.
+func (c *ConstraintHandler) RemoveSoftStateFSConstraint(name string) {
+	delete(c.softStateFSConstraints, name)
+}
+
+// SoftStateFSConstraints will list all FS soft constraints by name.
+func (c *ConstraintHandler) SoftStateFSConstraints() []string {
+	names := make([]string, 0, len(c.softStateFSConstraints))
+	for name := range c.softStateFSConstraints {
+		names = append(names, name)
+	}
+	return names
+}
+
+// SoftStateFSCost returns the sum of the weighted costs of all soft constraints at the given state.
+func (c *ConstraintHandler) SoftStateFSCost(state *ik.StateFS) float64 {
+	var total float64
+	for _, soft := range c.softStateFSConstraints {
+		total += soft.weight * soft.cost(state)
+	}
+	return total
+}
+
+// withSoftCosts returns a segment metric which adds the weighted costs of the soft constraints at the end of each segment to the
+// given metric. Segments without a frame system are evaluated in the given one.
+func (c *ConstraintHandler) withSoftCosts(metric ik.SegmentFSMetric, fs referenceframe.FrameSystem) ik.SegmentFSMetric {
+	if len(c.softStateFSConstraints) == 0 {
+		return metric
+	}
+	return func(segment *ik.SegmentFS) float64 {
+		segmentFS := segment.FS
+		if segmentFS == nil {
+			segmentFS = fs
+		}
+		return metric(segment) + c.SoftStateFSCost(&ik.StateFS{Configuration: segment.EndConfiguration, FS: segmentFS})
+	}
+}
@@ -246,6 +246,7 @@ func TestCollisionConstraints(t *testing.T) {
 		worldGeometries.Geometries(),
 		nil, nil,
 		defaultCollisionBufferMM,
+		nil,
 	)
 	test.That(t, err, test.ShouldBeNil)
 	for name, constraint := range collisionConstraints {
@@ -310,6 +311,7 @@ func BenchmarkCollisionConstraints(b *testing.B) {
 		worldGeometries.Geometries(),
 		nil, nil,
 		defaultCollisionBufferMM,
+		nil,
 	)
 	test.That(b, err, test.ShouldBeNil)
 	for name, constraint := range collisionConstraints {
@@ -370,3 +372,259 @@ func TestConstraintConstructors(t *testing.T) {
 	pbToRDKConstraint := ConstraintsFromProtobuf(pbConstraint)
 	test.That(t, c, test.ShouldResemble, pbToRDKConstraint)
 }
+
+func TestLevelConstraint(t *testing.T) {
+	fs := frame.NewEmptyFrameSystem("")
+	tilt, err := frame.NewRotationalFrame("tilt", spatial.R4AA{RX: 1}, frame.Limit{Min: -math.Pi, Max: math.Pi})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(tilt, fs.World()), test.ShouldBeNil)
+	gripper, err := frame.NewStaticFrame("gripper", spatial.NewPoseFromPoint(r3.Vector{Z: 100}))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(gripper, tilt), test.ShouldBeNil)
+
+	constraint, err := CreateLevelConstraintFS(fs, "gripper", 10)
+	test.That(t, err, test.ShouldBeNil)
+	level := func(degs float64) bool {
+		return constraint(&ik.StateFS{
+			Configuration: frame.FrameSystemInputs{"tilt": {{Value: utils.DegToRad(degs)}}},
+			FS:            fs,
+		})
+	}
+	test.That(t, level(0), test.ShouldBeTrue)
+	test.That(t, level(-9), test.ShouldBeTrue)
+	test.That(t, level(20), test.ShouldBeFalse)
+	test.That(t, level(90), test.ShouldBeFalse)
+	// a frame pointing down is level
+	test.That(t, level(175), test.ShouldBeTrue)
+	// a frame whose inputs are missing is not known to be level
+	test.That(t, constraint(&ik.StateFS{Configuration: frame.FrameSystemInputs{}, FS: fs}), test.ShouldBeFalse)
+
+	_, err = CreateLevelConstraintFS(fs, "missing", 10)
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = CreateLevelConstraintFS(fs, "gripper", 90)
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestSingularityConstraint(t *testing.T) {
+	ur5e, err := frame.ParseModelJSONFile(utils.ResolveFile("components/arm/universalrobots/ur5e.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+	// the wrist of the arm is singular when its fifth joint is straight, aligning the axes of the fourth and sixth
+	wrist := func(radians float64) []frame.Input {
+		return frame.FloatsToInputs([]float64{0, -math.Pi / 2, math.Pi / 2, -math.Pi / 2, radians, 0})
+	}
+
+	constraint, metric := NewSingularityConstraint(0.01)
+	test.That(t, constraint(&ik.State{Frame: ur5e, Configuration: wrist(math.Pi / 2)}), test.ShouldBeTrue)
+	test.That(t, metric(&ik.State{Frame: ur5e, Configuration: wrist(math.Pi / 2)}), test.ShouldEqual, 0)
+	test.That(t, constraint(&ik.State{Frame: ur5e, Configuration: wrist(0.05)}), test.ShouldBeFalse)
+	test.That(t, constraint(&ik.State{Frame: ur5e, Configuration: wrist(0)}), test.ShouldBeFalse)
+	// configurations are penalized more the nearer they are to the singularity
+	test.That(t, metric(&ik.State{Frame: ur5e, Configuration: wrist(0)}), test.ShouldBeGreaterThan,
+		metric(&ik.State{Frame: ur5e, Configuration: wrist(0.05)}))
+
+	fs := frame.NewEmptyFrameSystem("")
+	test.That(t, fs.AddFrame(ur5e, fs.World()), test.ShouldBeNil)
+	opt := newBasicPlannerOptions()
+	constraints := NewEmptyConstraints()
+	constraints.AddSingularityConstraint(SingularityConstraint{Frame: ur5e.Name(), MinManipulability: 0.01})
+	test.That(t, opt.addSingularityConstraints(fs, constraints), test.ShouldBeNil)
+	ok, failName := opt.CheckStateFSConstraints(&ik.StateFS{FS: fs, Configuration: frame.FrameSystemInputs{ur5e.Name(): wrist(0)}})
+	test.That(t, ok, test.ShouldBeFalse)
+	test.That(t, failName, test.ShouldContainSubstring, defaultSingularityConstraintDesc)
+	ok, _ = opt.CheckStateFSConstraints(&ik.StateFS{FS: fs, Configuration: frame.FrameSystemInputs{ur5e.Name(): wrist(1)}})
+	test.That(t, ok, test.ShouldBeTrue)
+
+	for _, invalid := range []SingularityConstraint{{Frame: "missing", MinManipulability: 0.01}, {Frame: ur5e.Name()}} {
+		constraints := NewEmptyConstraints()
+		constraints.AddSingularityConstraint(invalid)
+		test.That(t, newBasicPlannerOptions().addSingularityConstraints(fs, constraints), test.ShouldNotBeNil)
+	}
+}
+
+func TestJointLimitMargin(t *testing.T) {
+	fs := frame.NewEmptyFrameSystem("")
+	joint, err := frame.NewRotationalFrame("joint", spatial.R4AA{RZ: 1}, frame.Limit{Min: -2, Max: 2})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(joint, fs.World()), test.ShouldBeNil)
+	slider, err := frame.NewTranslationalFrame("slider", r3.Vector{X: 1}, frame.Limit{Min: 0, Max: 1000})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(slider, joint), test.ShouldBeNil)
+	at := func(radians, mm float64) *ik.StateFS {
+		return &ik.StateFS{FS: fs, Configuration: frame.FrameSystemInputs{
+			"joint":  frame.FloatsToInputs([]float64{radians}),
+			"slider": frame.FloatsToInputs([]float64{mm}),
+		}}
+	}
+
+	t.Run("no margin is kept by default", func(t *testing.T) {
+		opt := newBasicPlannerOptions()
+		test.That(t, opt.addJointLimitMargin(fs, at(0, 500).Configuration), test.ShouldBeNil)
+		ok, _ := opt.CheckStateFSConstraints(at(2, 1000))
+		test.That(t, ok, test.ShouldBeTrue)
+	})
+
+	t.Run("the larger of the margins is kept", func(t *testing.T) {
+		opt := newBasicPlannerOptions()
+		opt.JointLimitMargin = 0.1
+		opt.JointLimitMarginPercent = 5
+		test.That(t, opt.addJointLimitMargin(fs, at(0, 500).Configuration), test.ShouldBeNil)
+		for _, tc := range []struct {
+			radians, mm float64
+			valid       bool
+		}{
+			{1.7, 500, true},
+			// 5% of the range of the joint is 0.2 radians
+			{1.85, 500, false},
+			// 5% of the range of the slider is 50mm
+			{0, 960, false},
+			{0, 940, true},
+			{0, 0.05, false},
+		} {
+			ok, failName := opt.CheckStateFSConstraints(at(tc.radians, tc.mm))
+			test.That(t, ok, test.ShouldEqual, tc.valid)
+			if !tc.valid {
+				test.That(t, failName, test.ShouldStartWith, defaultJointLimitMarginConstraintDesc)
+			}
+		}
+	})
+
+	t.Run("motion may begin near the limits", func(t *testing.T) {
+		opt := newBasicPlannerOptions()
+		opt.JointLimitMargin = 0.5
+		test.That(t, opt.addJointLimitMargin(fs, at(1.9, 500).Configuration), test.ShouldBeNil)
+		ok, _ := opt.CheckStateFSConstraints(at(1.9, 500))
+		test.That(t, ok, test.ShouldBeTrue)
+		ok, _ = opt.CheckStateFSConstraints(at(1.6, 500))
+		test.That(t, ok, test.ShouldBeTrue)
+		// but may not move any nearer to them
+		ok, _ = opt.CheckStateFSConstraints(at(1.95, 500))
+		test.That(t, ok, test.ShouldBeFalse)
+		ok, _ = opt.CheckStateFSConstraints(at(-1.6, 500))
+		test.That(t, ok, test.ShouldBeFalse)
+	})
+
+	t.Run("invalid margins are rejected", func(t *testing.T) {
+		for _, opt := range []*plannerOptions{
+			{JointLimitMargin: -1},
+			{JointLimitMarginPercent: 50},
+			{JointLimitMargin: 3},
+		} {
+			test.That(t, opt.addJointLimitMargin(fs, at(0, 500).Configuration), test.ShouldNotBeNil)
+		}
+	})
+}
+
+func TestInteractionSpaceConstraint(t *testing.T) {
+	geometry, err := spatial.NewBox(spatial.NewZeroPose(), r3.Vector{X: 100, Y: 100, Z: 100}, "slider")
+	test.That(t, err, test.ShouldBeNil)
+	slider, err := frame.NewTranslationalFrameWithGeometry("slider", r3.Vector{X: 1}, frame.Limit{Min: -1000, Max: 1000}, geometry)
+	test.That(t, err, test.ShouldBeNil)
+	fs := frame.NewEmptyFrameSystem("test")
+	test.That(t, fs.AddFrame(slider, fs.World()), test.ShouldBeNil)
+	space, err := spatial.NewBox(spatial.NewZeroPose(), r3.Vector{X: 600, Y: 600, Z: 600}, "space")
+	test.That(t, err, test.ShouldBeNil)
+
+	constraint := NewInteractionSpaceConstraint([]spatial.Geometry{geometry}, []spatial.Geometry{space})
+	test.That(t, constraint(&ik.State{Frame: slider, Configuration: frame.FloatsToInputs([]float64{200})}), test.ShouldBeTrue)
+	// the slider would only partially be within the interaction space
+	test.That(t, constraint(&ik.State{Frame: slider, Configuration: frame.FloatsToInputs([]float64{280})}), test.ShouldBeFalse)
+
+	constraintFS := NewInteractionSpaceConstraintFS([]spatial.Geometry{geometry}, []spatial.Geometry{space})
+	within := &ik.StateFS{FS: fs, Configuration: frame.FrameSystemInputs{"slider": frame.FloatsToInputs([]float64{-200})}}
+	test.That(t, constraintFS(within), test.ShouldBeTrue)
+	outside := &ik.StateFS{FS: fs, Configuration: frame.FrameSystemInputs{"slider": frame.FloatsToInputs([]float64{900})}}
+	test.That(t, constraintFS(outside), test.ShouldBeFalse)
+}
+
+func TestSoftConstraints(t *testing.T) {
+	slider, err := frame.NewTranslationalFrame("slider", r3.Vector{X: 1}, frame.Limit{Min: -1000, Max: 1000})
+	test.That(t, err, test.ShouldBeNil)
+	fs := frame.NewEmptyFrameSystem("test")
+	test.That(t, fs.AddFrame(slider, fs.World()), test.ShouldBeNil)
+	// prefer the slider to stay near the origin
+	nearOrigin := func(state *ik.StateFS) float64 {
+		return math.Abs(state.Configuration["slider"][0].Value)
+	}
+	at := func(x float64) frame.FrameSystemInputs {
+		return frame.FrameSystemInputs{"slider": frame.FloatsToInputs([]float64{x})}
+	}
+
+	handler := &ConstraintHandler{}
+	test.That(t, handler.SoftStateFSCost(&ik.StateFS{Configuration: at(100), FS: fs}), test.ShouldEqual, 0)
+	test.That(t, handler.withSoftCosts(ik.FSConfigurationL2Distance, fs)(&ik.SegmentFS{
+		StartConfiguration: at(0), EndConfiguration: at(100),
+	}), test.ShouldAlmostEqual, 100)
+
+	handler.AddSoftStateFSConstraint("near origin", nearOrigin, 0.5)
+	test.That(t, len(handler.SoftStateFSConstraints()), test.ShouldEqual, 1)
+	test.That(t, handler.SoftStateFSCost(&ik.StateFS{Configuration: at(-100), FS: fs}), test.ShouldAlmostEqual, 50)
+	// segments are costed at their ends
+	test.That(t, handler.withSoftCosts(ik.FSConfigurationL2Distance, fs)(&ik.SegmentFS{
+		StartConfiguration: at(0), EndConfiguration: at(100),
+	}), test.ShouldAlmostEqual, 150)
+
+	handler.RemoveSoftStateFSConstraint(handler.SoftStateFSConstraints()[0])
+	test.That(t, handler.SoftStateFSConstraints(), test.ShouldBeEmpty)
+
+	t.Run("soft constraints of a request", func(t *testing.T) {
+		pm, err := newPlanManager(fs, logging.NewTestLogger(t), defaultRandomSeed)
+		test.That(t, err, test.ShouldBeNil)
+		goal := &PlanState{poses: frame.FrameSystemPoses{"slider": frame.NewPoseInFrame(frame.World, spatial.NewZeroPose())}}
+		constraints := NewEmptyConstraints()
+		constraints.AddSoftConstraint(SoftConstraint{Name: "near origin", Weight: 2, Cost: nearOrigin})
+		opt, err := pm.plannerSetupFromMoveRequest(&PlanState{configuration: at(0)}, goal, at(0), nil, nil, constraints, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, opt.SoftStateFSConstraints(), test.ShouldNotBeEmpty)
+		test.That(t, opt.scoreFunc(&ik.SegmentFS{StartConfiguration: at(100), EndConfiguration: at(100)}), test.ShouldAlmostEqual, 200)
+
+		constraints.AddSoftConstraint(SoftConstraint{Name: "no cost", Weight: 1})
+		_, err = pm.plannerSetupFromMoveRequest(&PlanState{configuration: at(0)}, goal, at(0), nil, nil, constraints, nil)
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
+
+func TestTorqueConstraint(t *testing.T) {
+	// a single joint pitching a 1 kg link, whose limit is exceeded by holding it level while carrying a 1 kg payload at its end
+	cfg := &frame.ModelConfig{
+		Name: "pendulum",
+		Links: []frame.LinkConfig{
+			{ID: "base"},
+			{ID: "arm", Parent: "shoulder", Translation: r3.Vector{X: 1000}, Mass: 1},
+		},
+		Joints: []frame.JointConfig{
+			{ID: "shoulder", Type: frame.RevoluteJoint, Parent: "base", Axis: spatial.AxisConfig{Y: 1}, Max: 180, Min: -180, MaxTorque: 15},
+		},
+	}
+	model, err := cfg.ParseConfig("")
+	test.That(t, err, test.ShouldBeNil)
+	level, upright := frame.FloatsToInputs([]float64{0}), frame.FloatsToInputs([]float64{-math.Pi / 2})
+
+	unloaded := NewTorqueConstraint(nil, 0)
+	test.That(t, unloaded(&ik.Segment{Frame: model, StartConfiguration: upright, EndConfiguration: level}), test.ShouldBeTrue)
+	loaded := NewTorqueConstraint(&frame.Payload{Mass: 1}, 0)
+	test.That(t, loaded(&ik.Segment{Frame: model, StartConfiguration: upright, EndConfiguration: upright}), test.ShouldBeTrue)
+	test.That(t, loaded(&ik.Segment{Frame: model, StartConfiguration: upright, EndConfiguration: level}), test.ShouldBeFalse)
+	// accelerating the link takes more torque than holding it
+	accelerating := NewTorqueConstraint(nil, 10)
+	test.That(t, accelerating(&ik.Segment{Frame: model, StartConfiguration: upright, EndConfiguration: level}), test.ShouldBeFalse)
+
+	fs := frame.NewEmptyFrameSystem("")
+	test.That(t, fs.AddFrame(model, fs.World()), test.ShouldBeNil)
+	opt := newBasicPlannerOptions()
+	constraints := NewEmptyConstraints()
+	constraints.AddTorqueConstraint(TorqueConstraint{Frame: model.Name(), PayloadKg: 1})
+	test.That(t, opt.addTorqueConstraints(fs, constraints), test.ShouldBeNil)
+	ok, failName := opt.CheckSegmentFSConstraints(&ik.SegmentFS{
+		FS:                 fs,
+		StartConfiguration: frame.FrameSystemInputs{model.Name(): upright},
+		EndConfiguration:   frame.FrameSystemInputs{model.Name(): level},
+	})
+	test.That(t, ok, test.ShouldBeFalse)
+	test.That(t, failName, test.ShouldContainSubstring, defaultTorqueConstraintDesc)
+
+	for _, invalid := range []TorqueConstraint{{Frame: "missing"}, {Frame: model.Name(), PayloadKg: -1}} {
+		constraints := NewEmptyConstraints()
+		constraints.AddTorqueConstraint(invalid)
+		test.That(t, newBasicPlannerOptions().addTorqueConstraints(fs, constraints), test.ShouldNotBeNil)
+	}
+}
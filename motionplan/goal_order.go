@@ -0,0 +1,61 @@
+//go:build !no_cgo
+
+package motionplan
+
+import (
+	"go.viam.com/rdk/spatialmath"
+)
+
+// OrderGoals returns the order in which to visit the given goal poses, as indices into goals, such that the path from start through
+// each of them in turn is short, measured by the straight-line distance between their positions. The path is built greedily, visiting
+// the nearest goal not yet visited next, and is then shortened by reversing any stretch of it whose reversal makes it shorter.
+func OrderGoals(start spatialmath.Pose, goals []spatialmath.Pose) []int {
+	// points[0] is the start, and points[i+1] is goals[i]
+	points := make([]spatialmath.Pose, 0, len(goals)+1)
+	points = append(points, start)
+	points = append(points, goals...)
+	dist := func(i, j int) float64 {
+		return points[i].Point().Distance(points[j].Point())
+	}
+
+	path := []int{0}
+	visited := make([]bool, len(points))
+	visited[0] = true
+	for len(path) < len(points) {
+		last, next := path[len(path)-1], -1
+		for i := range points {
+			if !visited[i] && (next < 0 || dist(last, i) < dist(last, next)) {
+				next = i
+			}
+		}
+		visited[next] = true
+		path = append(path, next)
+	}
+
+	// the start is fixed and the path need not return to it, so there is no edge after the last goal
+	for improved := true; improved; {
+		improved = false
+		for i := 1; i < len(path)-1; i++ {
+			for j := i + 1; j < len(path); j++ {
+				before := dist(path[i-1], path[i])
+				after := dist(path[i-1], path[j])
+				if j < len(path)-1 {
+					before += dist(path[j], path[j+1])
+					after += dist(path[i], path[j+1])
+				}
+				if after < before-defaultEpsilon {
+					for a, b := i, j; a < b; a, b = a+1, b-1 {
+						path[a], path[b] = path[b], path[a]
+					}
+					improved = true
+				}
+			}
+		}
+	}
+
+	order := make([]int, 0, len(goals))
+	for _, i := range path[1:] {
+		order = append(order, i-1)
+	}
+	return order
+}
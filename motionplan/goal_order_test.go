@@ -0,0 +1,36 @@
+package motionplan
+
+import (
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	spatial "go.viam.com/rdk/spatialmath"
+)
+
+func TestOrderGoals(t *testing.T) {
+	at := func(x, y float64) spatial.Pose {
+		return spatial.NewPoseFromPoint(r3.Vector{X: x, Y: y})
+	}
+	start := at(0, 0)
+
+	test.That(t, OrderGoals(start, nil), test.ShouldBeEmpty)
+	test.That(t, OrderGoals(start, []spatial.Pose{at(5, 5)}), test.ShouldResemble, []int{0})
+
+	// goals along a line are visited outward from the start, whatever order they are given in
+	line := []spatial.Pose{at(300, 0), at(100, 0), at(400, 0), at(200, 0)}
+	test.That(t, OrderGoals(start, line), test.ShouldResemble, []int{1, 3, 0, 2})
+
+	// visiting the nearest goal first leaves the path crossing itself, which reversing part of it undoes
+	goals := []spatial.Pose{at(300, 400), at(400, 100), at(0, 200), at(300, 200)}
+	order := OrderGoals(start, goals)
+	test.That(t, order, test.ShouldResemble, []int{2, 0, 3, 1})
+	length := 0.
+	last := start
+	for _, i := range order {
+		length += last.Point().Distance(goals[i].Point())
+		last = goals[i]
+	}
+	test.That(t, length, test.ShouldBeLessThan, 902)
+}
@@ -0,0 +1,234 @@
+//go:build !no_cgo
+
+package motionplan
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// defaultGraspIKSolutions is how many IK solutions are compared for each grasp when the request does not set max_ik_solutions.
+const defaultGraspIKSolutions = 3
+
+// Gripper describes a parallel jaw gripper in the coordinates of the frame it is mounted at.
+type Gripper struct {
+	// ApproachAxis is the direction in which the gripper moves onto an object.
+	ApproachAxis r3.Vector
+	// ClosingAxis is the direction in which the fingers close, which must not be parallel to the approach axis.
+	ClosingAxis r3.Vector
+	// GraspOffsetMM is how far along the approach axis the point between the fingertips is from the origin of the frame.
+	GraspOffsetMM float64
+	// MaxSpanMM is how far apart the open fingers are, which is the widest an object may be across the closing axis.
+	MaxSpanMM float64
+}
+
+// Validate ensures the gripper describes a grasp.
+func (g Gripper) Validate() error {
+	if g.ApproachAxis.Norm() == 0 {
+		return errors.New("gripper approach axis may not be zero")
+	}
+	if g.ClosingAxis.Norm() == 0 || g.ApproachAxis.Cross(g.ClosingAxis).Norm() < 1e-6*g.ApproachAxis.Norm()*g.ClosingAxis.Norm() {
+		return errors.New("gripper closing axis may not be zero or parallel to the approach axis")
+	}
+	if g.GraspOffsetMM < 0 {
+		return errors.New("gripper grasp offset may not be negative")
+	}
+	if g.MaxSpanMM <= 0 {
+		return errors.New("gripper max span must be positive")
+	}
+	return nil
+}
+
+// Grasp is a pose of the gripper frame which holds an object between its fingers.
+type Grasp struct {
+	// Pose is the pose of the gripper frame, in the frame the object is given in.
+	Pose spatialmath.Pose
+	// WidthMM is the width of the object between the fingers.
+	WidthMM float64
+	// Configuration is the configuration of the frame system reaching the grasp, which is only set for planned grasps.
+	Configuration referenceframe.FrameSystemInputs
+	// Cost is the distance of the configuration from the start configuration, which is only set for planned grasps.
+	Cost float64
+}
+
+// GraspCandidates returns the grasps of an object by a gripper. Boxes, spheres and capsules are grasped across each of their axes
+// which fit between the fingers, approaching along each of the other axes from both sides. The fingers reach as deep into the object
+// as the grasp offset allows, up to its center.
+func GraspCandidates(object spatialmath.Geometry, gripper Gripper) ([]*Grasp, error) {
+	if err := gripper.Validate(); err != nil {
+		return nil, err
+	}
+	// halfExtents is the distance from the center of the object to its surface along each of its axes, and widths is the width of
+	// the object between the fingers when they close along each axis
+	var halfExtents, widths [3]float64
+	proto := object.ToProtobuf()
+	switch {
+	case proto.GetBox() != nil:
+		dims := proto.GetBox().GetDimsMm()
+		widths = [3]float64{dims.GetX(), dims.GetY(), dims.GetZ()}
+		halfExtents = [3]float64{dims.GetX() / 2, dims.GetY() / 2, dims.GetZ() / 2}
+	case proto.GetSphere() != nil:
+		r := proto.GetSphere().GetRadiusMm()
+		widths = [3]float64{2 * r, 2 * r, 2 * r}
+		halfExtents = [3]float64{r, r, r}
+	case proto.GetCapsule() != nil:
+		r, length := proto.GetCapsule().GetRadiusMm(), proto.GetCapsule().GetLengthMm()
+		widths = [3]float64{2 * r, 2 * r, length}
+		halfExtents = [3]float64{r, r, length / 2}
+	default:
+		return nil, fmt.Errorf("cannot generate grasps for geometry %s of type %T", object.Label(), object)
+	}
+	// points are encoded as spheres without a radius, which leave nothing between the fingers
+	if widths[0] <= 0 || widths[1] <= 0 || widths[2] <= 0 {
+		return nil, fmt.Errorf("cannot generate grasps for geometry %s, which has no volume", object.Label())
+	}
+
+	axes := [3]r3.Vector{{X: 1}, {Y: 1}, {Z: 1}}
+	objectRotation := spatialmath.NewPoseFromOrientation(object.Pose().Orientation())
+	toObject := func(v r3.Vector) r3.Vector {
+		return spatialmath.Compose(objectRotation, spatialmath.NewPoseFromPoint(v)).Point()
+	}
+
+	var grasps []*Grasp
+	for approachIdx := range axes {
+		for _, approachSign := range []float64{-1, 1} {
+			approach := toObject(axes[approachIdx].Mul(approachSign))
+			depth := math.Min(halfExtents[approachIdx], gripper.GraspOffsetMM)
+			// the fingers reach depth past the surface of the object which the gripper approaches
+			graspPoint := object.Pose().Point().Add(approach.Mul(depth - halfExtents[approachIdx]))
+			origin := graspPoint.Sub(approach.Mul(gripper.GraspOffsetMM))
+			for closingIdx := range axes {
+				if closingIdx == approachIdx || widths[closingIdx] > gripper.MaxSpanMM {
+					continue
+				}
+				for _, closingSign := range []float64{1, -1} {
+					closing := toObject(axes[closingIdx].Mul(closingSign))
+					orientation, err := gripperOrientation(gripper, approach, closing)
+					if err != nil {
+						return nil, err
+					}
+					grasps = append(grasps, &Grasp{Pose: spatialmath.NewPose(origin, orientation), WidthMM: widths[closingIdx]})
+				}
+			}
+		}
+	}
+	if len(grasps) == 0 {
+		return nil, fmt.Errorf("geometry %s is too wide to fit in a gripper with a span of %.0fmm", object.Label(), gripper.MaxSpanMM)
+	}
+	return grasps, nil
+}
+
+// gripperOrientation returns the orientation of the gripper frame which points its approach and closing axes along the given
+// directions.
+func gripperOrientation(gripper Gripper, approach, closing r3.Vector) (spatialmath.Orientation, error) {
+	// orthonormal bases of the gripper axes in the gripper frame and of the directions they should point along
+	gripperAxes := orthonormalAxes(gripper.ApproachAxis, gripper.ClosingAxis)
+	targetAxes := orthonormalAxes(approach, closing)
+	// rotation matrices store the transpose of the rotation they apply, which maps each gripper axis onto its target
+	data := make([]float64, 0, 9)
+	for row := 0; row < 3; row++ {
+		for col := 0; col < 3; col++ {
+			var v float64
+			for k := range gripperAxes {
+				v += vectorComponent(gripperAxes[k], row) * vectorComponent(targetAxes[k], col)
+			}
+			data = append(data, v)
+		}
+	}
+	return spatialmath.NewRotationMatrix(data)
+}
+
+// orthonormalAxes returns the right handed orthonormal basis whose first axis is along a and whose second axis is in the plane of a
+// and b.
+func orthonormalAxes(a, b r3.Vector) [3]r3.Vector {
+	a = a.Normalize()
+	b = b.Sub(a.Mul(a.Dot(b))).Normalize()
+	return [3]r3.Vector{a, b, a.Cross(b)}
+}
+
+func vectorComponent(v r3.Vector, i int) float64 {
+	switch i {
+	case 0:
+		return v.X
+	case 1:
+		return v.Y
+	default:
+		return v.Z
+	}
+}
+
+// PlanGrasps returns the grasps of an object, given in the world frame, which the gripper frame of the request's frame system can
+// reach from the start configuration of the request without collisions. The object may be an obstacle of the request's world state,
+// in which case the gripper frame is allowed to touch it. Grasps are ordered by the distance of their best IK solution from the start
+// configuration, nearest first.
+func PlanGrasps(
+	ctx context.Context,
+	request *PlanRequest,
+	gripperFrame string,
+	object spatialmath.Geometry,
+	gripper Gripper,
+) ([]*Grasp, error) {
+	candidates, err := GraspCandidates(object, gripper)
+	if err != nil {
+		return nil, err
+	}
+	if request.Logger == nil {
+		return nil, errors.New("PlanRequest cannot have nil logger")
+	}
+	if request.FrameSystem == nil || request.FrameSystem.Frame(gripperFrame) == nil {
+		return nil, referenceframe.NewFrameMissingError(gripperFrame)
+	}
+
+	constraints := &Constraints{}
+	if request.Constraints != nil {
+		*constraints = *request.Constraints
+	}
+	if _, ok := request.WorldState.ObstacleNames()[object.Label()]; ok && object.Label() != "" {
+		constraints.CollisionSpecification = append(append([]CollisionSpecification{}, constraints.CollisionSpecification...),
+			CollisionSpecification{Allows: []CollisionSpecificationAllowedFrameCollisions{{Frame1: gripperFrame, Frame2: object.Label()}}},
+		)
+	}
+	options := make(map[string]interface{}, len(request.Options)+1)
+	for k, v := range request.Options {
+		options[k] = v
+	}
+	if _, ok := options["max_ik_solutions"]; !ok {
+		options["max_ik_solutions"] = defaultGraspIKSolutions
+	}
+
+	var reachable []*Grasp
+	for _, candidate := range candidates {
+		graspRequest := *request
+		graspRequest.Goals = []*PlanState{NewPlanState(referenceframe.FrameSystemPoses{
+			gripperFrame: referenceframe.NewPoseInFrame(referenceframe.World, candidate.Pose),
+		}, nil)}
+		graspRequest.Constraints = constraints
+		graspRequest.Options = options
+		solutions, err := BestIKSolutions(ctx, &graspRequest)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			request.Logger.CDebugf(ctx, "grasp at %v is unreachable: %v", spatialmath.PoseToProtobuf(candidate.Pose), err)
+			continue
+		}
+		candidate.Configuration = solutions[0].Configuration
+		candidate.Cost = solutions[0].Cost
+		reachable = append(reachable, candidate)
+	}
+	if len(reachable) == 0 {
+		return nil, fmt.Errorf("none of the %d grasps of %s are reachable", len(candidates), object.Label())
+	}
+	sort.SliceStable(reachable, func(i, j int) bool {
+		return reachable[i].Cost < reachable[j].Cost
+	})
+	return reachable, nil
+}
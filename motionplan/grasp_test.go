@@ -0,0 +1,116 @@
+//go:build !no_cgo
+
+package motionplan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	frame "go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+func TestGraspCandidates(t *testing.T) {
+	gripper := Gripper{ApproachAxis: r3.Vector{Z: 1}, ClosingAxis: r3.Vector{X: 1}, GraspOffsetMM: 50, MaxSpanMM: 80}
+
+	t.Run("boxes are grasped across the axes which fit between the fingers", func(t *testing.T) {
+		center := spatialmath.NewPose(r3.Vector{X: 500, Z: 300}, &spatialmath.OrientationVectorDegrees{OZ: 1, Theta: 30})
+		box, err := spatialmath.NewBox(center, r3.Vector{X: 40, Y: 200, Z: 60}, "block")
+		test.That(t, err, test.ShouldBeNil)
+		grasps, err := GraspCandidates(box, gripper)
+		test.That(t, err, test.ShouldBeNil)
+		// the box is too long to be grasped across its y axis
+		test.That(t, len(grasps), test.ShouldEqual, 16)
+		for _, grasp := range grasps {
+			test.That(t, grasp.WidthMM, test.ShouldBeLessThanOrEqualTo, gripper.MaxSpanMM)
+			// the gripper approaches the box towards its center, holding it between the fingertips
+			graspPoint := spatialmath.Compose(grasp.Pose, spatialmath.NewPoseFromPoint(r3.Vector{Z: gripper.GraspOffsetMM})).Point()
+			inside, err := spatialmath.NewPoint(graspPoint, "").CollidesWith(box, 1e-6)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, inside, test.ShouldBeTrue)
+			approach := spatialmath.Compose(grasp.Pose, spatialmath.NewPoseFromPoint(r3.Vector{Z: 1})).Point().Sub(grasp.Pose.Point())
+			toCenter := center.Point().Sub(grasp.Pose.Point()).Normalize()
+			test.That(t, approach.Dot(toCenter), test.ShouldAlmostEqual, 1, 1e-6)
+		}
+	})
+
+	t.Run("fingers reach the center of objects shallower than the grasp offset", func(t *testing.T) {
+		sphere, err := spatialmath.NewSphere(spatialmath.NewPoseFromPoint(r3.Vector{X: 500}), 20, "ball")
+		test.That(t, err, test.ShouldBeNil)
+		grasps, err := GraspCandidates(sphere, gripper)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(grasps), test.ShouldEqual, 24)
+		for _, grasp := range grasps {
+			test.That(t, grasp.WidthMM, test.ShouldEqual, 40)
+			graspPoint := spatialmath.Compose(grasp.Pose, spatialmath.NewPoseFromPoint(r3.Vector{Z: gripper.GraspOffsetMM})).Point()
+			test.That(t, graspPoint.Distance(r3.Vector{X: 500}), test.ShouldBeLessThan, 1e-6)
+		}
+	})
+
+	t.Run("objects which cannot be grasped are rejected", func(t *testing.T) {
+		wide, err := spatialmath.NewCapsule(spatialmath.NewZeroPose(), 50, 300, "pipe")
+		test.That(t, err, test.ShouldBeNil)
+		_, err = GraspCandidates(wide, gripper)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "too wide")
+
+		_, err = GraspCandidates(spatialmath.NewPoint(r3.Vector{}, "point"), gripper)
+		test.That(t, err, test.ShouldNotBeNil)
+
+		box, err := spatialmath.NewBox(spatialmath.NewZeroPose(), r3.Vector{X: 10, Y: 10, Z: 10}, "")
+		test.That(t, err, test.ShouldBeNil)
+		_, err = GraspCandidates(box, Gripper{ApproachAxis: r3.Vector{Z: 1}, ClosingAxis: r3.Vector{Z: 2}, MaxSpanMM: 80})
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = GraspCandidates(box, Gripper{ApproachAxis: r3.Vector{Z: 1}, ClosingAxis: r3.Vector{X: 1}})
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
+
+func TestPlanGrasps(t *testing.T) {
+	fs := frame.NewEmptyFrameSystem("")
+	x, err := frame.ParseModelJSONFile(utils.ResolveFile("components/arm/example_kinematics/xarm6_kinematics_test.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(x, fs.World()), test.ShouldBeNil)
+	gripperFrame, err := frame.NewStaticFrame("xArmVgripper", spatialmath.NewPoseFromPoint(r3.Vector{Z: 100}))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(gripperFrame, x), test.ShouldBeNil)
+
+	block, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(r3.Vector{X: 400, Z: 200}), r3.Vector{X: 40, Y: 40, Z: 40}, "block")
+	test.That(t, err, test.ShouldBeNil)
+	worldState, err := frame.NewWorldState(
+		[]*frame.GeometriesInFrame{frame.NewGeometriesInFrame(frame.World, []spatialmath.Geometry{block})},
+		nil,
+	)
+	test.That(t, err, test.ShouldBeNil)
+	gripper := Gripper{ApproachAxis: r3.Vector{Z: 1}, ClosingAxis: r3.Vector{X: 1}, GraspOffsetMM: 30, MaxSpanMM: 80}
+
+	start := frame.NewZeroInputs(fs)
+	grasps, err := PlanGrasps(context.Background(), &PlanRequest{
+		Logger:      logger,
+		StartState:  &PlanState{configuration: start},
+		FrameSystem: fs,
+		WorldState:  worldState,
+	}, "xArmVgripper", block, gripper)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, grasps, test.ShouldNotBeEmpty)
+	for i, grasp := range grasps {
+		if i > 0 {
+			test.That(t, grasp.Cost, test.ShouldBeGreaterThanOrEqualTo, grasps[i-1].Cost)
+		}
+		// the configuration places the gripper at the grasp
+		tf, err := fs.Transform(grasp.Configuration, frame.NewZeroPoseInFrame("xArmVgripper"), frame.World)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, spatialmath.PoseAlmostEqualEps(tf.(*frame.PoseInFrame).Pose(), grasp.Pose, 1), test.ShouldBeTrue)
+	}
+
+	_, err = PlanGrasps(context.Background(), &PlanRequest{
+		Logger:      logger,
+		StartState:  &PlanState{configuration: start},
+		FrameSystem: fs,
+	}, "missing", block, gripper)
+	test.That(t, err, test.ShouldNotBeNil)
+}
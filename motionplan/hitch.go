@@ -0,0 +1,110 @@
+//go:build !no_cgo
+
+package motionplan
+
+import (
+	"fmt"
+	"math"
+
+	"go.viam.com/rdk/motionplan/tpspace"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// The turning radius of a step of a trajectory is allowed to fall short of the minimum by this proportion, so that arcs planned at
+// exactly the minimum radius are not ruled out by floating point error.
+const hitchRadiusTolerance = 1e-6
+
+// ptgTransitionConstraint tests whether a frame may move from one node of a PTG trajectory to the next.
+type ptgTransitionConstraint func(from, to *tpspace.TrajNode) bool
+
+// SteadyStateHitchAngle returns the hitch angle, in radians, which the trailer settles at while the frame towing it drives forward
+// around a circle of the given radius in mm, and whether it settles at all. A trailer longer than the radius of the circle swept by
+// the hitch folds in until it jackknifes.
+func (hc HitchConstraint) SteadyStateHitchAngle(radiusMM float64) (float64, bool) {
+	if math.IsInf(radiusMM, 1) {
+		return 0, true
+	}
+	hitchRadius := math.Hypot(radiusMM, hc.HitchOffsetMM)
+	if hitchRadius < hc.TrailerLengthMM {
+		return 0, false
+	}
+	// the hitch trails the center of rotation of the frame around the circle, and the axle of the trailer trails the hitch
+	return math.Atan2(hc.HitchOffsetMM, radiusMM) + math.Asin(hc.TrailerLengthMM/hitchRadius), true
+}
+
+// MinTurningRadiusMM returns the tightest radius, in mm, around which the frame may drive forward without its trailer settling at a
+// hitch angle beyond MaxHitchAngleDegs.
+func (hc HitchConstraint) MinTurningRadiusMM() (float64, error) {
+	if hc.HitchOffsetMM < 0 {
+		return 0, fmt.Errorf("hitch offset of frame %s may not be negative, got %f", hc.Frame, hc.HitchOffsetMM)
+	}
+	if hc.TrailerLengthMM <= 0 {
+		return 0, fmt.Errorf("trailer length of frame %s must be positive, got %f", hc.Frame, hc.TrailerLengthMM)
+	}
+	if hc.MaxHitchAngleDegs <= 0 || hc.MaxHitchAngleDegs >= 90 {
+		return 0, fmt.Errorf("max hitch angle of frame %s must be between 0 and 90 degrees, got %f", hc.Frame, hc.MaxHitchAngleDegs)
+	}
+	maxAngle := hc.MaxHitchAngleDegs * math.Pi / 180
+	within := func(radiusMM float64) bool {
+		angle, ok := hc.SteadyStateHitchAngle(radiusMM)
+		return ok && angle <= maxAngle
+	}
+	// the steady state hitch angle shrinks as the radius grows, so the tightest radius within the limit is found by bisection
+	tight, wide := 0., hc.TrailerLengthMM
+	for !within(wide) {
+		tight, wide = wide, 2*wide
+	}
+	for wide-tight > defaultEpsilon {
+		mid := (tight + wide) / 2
+		if within(mid) {
+			wide = mid
+		} else {
+			tight = mid
+		}
+	}
+	return wide, nil
+}
+
+// createHitchConstraint returns a constraint which lets a frame towing a trailer only drive forward, around arcs no tighter than its
+// minimum turning radius. Driving forward, the hitch angle only ever approaches the steady state angle of the arc being driven, so
+// whatever the hitch angle is at the start of a trajectory, it stays within the limit if it starts within it. Reversing, it diverges
+// from the steady state, so no trajectory which reverses can be known to be safe without knowing where the trailer starts.
+func createHitchConstraint(hc HitchConstraint) (ptgTransitionConstraint, error) {
+	minRadiusMM, err := hc.MinTurningRadiusMM()
+	if err != nil {
+		return nil, err
+	}
+	minRadiusMM *= 1 - hitchRadiusTolerance
+	return func(from, to *tpspace.TrajNode) bool {
+		// the velocities of a node are those at which it is left
+		if from.LinVel < 0 {
+			return false
+		}
+		delta := spatialmath.PoseBetween(from.Pose, to.Pose)
+		turn := math.Abs(delta.Orientation().OrientationVectorRadians().Theta)
+		if turn < defaultEpsilon {
+			return true
+		}
+		// the radius of the arc through both nodes, which is zero for a rotation in place
+		return delta.Point().Norm()/(2*math.Sin(turn/2)) >= minRadiusMM
+	}, nil
+}
+
+// addHitchConstraints adds a constraint for each frame which tows a trailer.
+func (p *plannerOptions) addHitchConstraints(fs referenceframe.FrameSystem, constraints *Constraints) error {
+	for _, hitchConstraint := range constraints.GetHitchConstraint() {
+		if fs.Frame(hitchConstraint.Frame) == nil {
+			return referenceframe.NewFrameMissingError(hitchConstraint.Frame)
+		}
+		constraint, err := createHitchConstraint(hitchConstraint)
+		if err != nil {
+			return err
+		}
+		if p.hitchConstraints == nil {
+			p.hitchConstraints = map[string]ptgTransitionConstraint{}
+		}
+		p.hitchConstraints[hitchConstraint.Frame] = constraint
+	}
+	return nil
+}
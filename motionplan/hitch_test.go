@@ -0,0 +1,74 @@
+package motionplan
+
+import (
+	"math"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/motionplan/tpspace"
+	frame "go.viam.com/rdk/referenceframe"
+	spatial "go.viam.com/rdk/spatialmath"
+)
+
+func TestHitchSteadyState(t *testing.T) {
+	hc := HitchConstraint{Frame: "tractor", TrailerLengthMM: 1000, MaxHitchAngleDegs: 30}
+
+	angle, ok := hc.SteadyStateHitchAngle(math.Inf(1))
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, angle, test.ShouldEqual, 0)
+
+	// hitched at the center of rotation, the trailer settles where its axle circles inside the hitch
+	angle, ok = hc.SteadyStateHitchAngle(2000)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, angle, test.ShouldAlmostEqual, math.Pi/6)
+
+	_, ok = hc.SteadyStateHitchAngle(500)
+	test.That(t, ok, test.ShouldBeFalse)
+
+	radius, err := hc.MinTurningRadiusMM()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, radius, test.ShouldAlmostEqual, 2000, defaultEpsilon)
+
+	// hitching further behind swings the trailer further on the same circle
+	hc.HitchOffsetMM = 300
+	angle, ok = hc.SteadyStateHitchAngle(2000)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, angle, test.ShouldBeGreaterThan, math.Pi/6)
+	offsetRadius, err := hc.MinTurningRadiusMM()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, offsetRadius, test.ShouldBeGreaterThan, radius)
+
+	hc.MaxHitchAngleDegs = 90
+	_, err = hc.MinTurningRadiusMM()
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestHitchConstraint(t *testing.T) {
+	hc := HitchConstraint{Frame: "tractor", TrailerLengthMM: 1000, MaxHitchAngleDegs: 30}
+	constraint, err := createHitchConstraint(hc)
+	test.That(t, err, test.ShouldBeNil)
+
+	// arcs of the circle PTG have a radius of pi times the turning radius over alpha
+	arc := func(radiusMM, alpha, dist float64) *tpspace.TrajNode {
+		pose, err := tpspace.NewCirclePTG(radiusMM).Transform([]frame.Input{{alpha}, {dist}})
+		test.That(t, err, test.ShouldBeNil)
+		return &tpspace.TrajNode{Pose: pose, Dist: dist, Alpha: alpha, LinVel: 1, AngVel: alpha / math.Pi}
+	}
+	start := &tpspace.TrajNode{Pose: spatial.NewZeroPose(), LinVel: 1}
+
+	test.That(t, constraint(start, arc(2000, 0, 100)), test.ShouldBeTrue)
+	test.That(t, constraint(start, arc(2500, math.Pi, 100)), test.ShouldBeTrue)
+	test.That(t, constraint(start, arc(1500, -math.Pi, 100)), test.ShouldBeFalse)
+
+	// a wide arc is still ruled out if it is driven in reverse
+	reverse := &tpspace.TrajNode{Pose: spatial.NewZeroPose(), LinVel: -1}
+	test.That(t, constraint(reverse, arc(2500, math.Pi, 100)), test.ShouldBeFalse)
+
+	// as is rotating in place
+	spin := &tpspace.TrajNode{Pose: spatial.NewPoseFromOrientation(&spatial.OrientationVectorDegrees{OZ: 1, Theta: 10})}
+	test.That(t, constraint(start, spin), test.ShouldBeFalse)
+
+	_, err = createHitchConstraint(HitchConstraint{Frame: "tractor", MaxHitchAngleDegs: 30})
+	test.That(t, err, test.ShouldNotBeNil)
+}
@@ -0,0 +1,273 @@
+package ik
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/num/quat"
+
+	"go.viam.com/rdk/referenceframe"
+	spatial "go.viam.com/rdk/spatialmath"
+)
+
+const (
+	// step by which configurations are perturbed to estimate Jacobians and gradients numerically.
+	nullspaceDiffStep = 1e-6
+
+	// step by which configurations are perturbed to estimate the gradients of objectives, which may themselves be estimated numerically.
+	nullspaceGradientStep = 1e-4
+
+	// largest change of any one input allowed in a single step of nullspace descent, in radians or mm.
+	nullspaceMaxStep = 0.05
+
+	// damping of the pseudoinverse of the Jacobian, which keeps it bounded near singularities.
+	nullspaceDamping = 1e-6
+
+	// how far the end of the frame may drift, in mm and radians, while it is moved through its nullspace.
+	nullspaceMaxDrift = 1e-4
+)
+
+// NullspaceObjective scores a configuration of a frame by some secondary objective, lower being better. Redundant frames, those with
+// more degrees of freedom than the six needed to place their end, may reach the same pose in many configurations, and are moved
+// between them to minimize such an objective without moving their end.
+type NullspaceObjective func(referenceframe.Frame, []referenceframe.Input) float64
+
+// JointLimitObjective scores how near the inputs of the frame are to the limits of their range, as the sum of the squared distances
+// of each from the middle of its range, normalized by the range. Inputs with unbounded ranges are not scored.
+func JointLimitObjective(f referenceframe.Frame, inputs []referenceframe.Input) float64 {
+	score := 0.
+	for i, limit := range f.DoF() {
+		span := limit.Max - limit.Min
+		if math.IsInf(span, 0) || span <= 0 || i >= len(inputs) {
+			continue
+		}
+		score += math.Pow((inputs[i].Value-(limit.Max+limit.Min)/2)/span, 2)
+	}
+	return score
+}
+
+// NewPostureObjective returns an objective which scores how far the inputs of the frame are from the given preferred posture, such as
+// one holding the elbow of an arm up, as the sum of their squared differences, normalized by the range of each input where bounded.
+func NewPostureObjective(preferred []float64) NullspaceObjective {
+	return func(f referenceframe.Frame, inputs []referenceframe.Input) float64 {
+		score := 0.
+		for i, limit := range f.DoF() {
+			if i >= len(inputs) || i >= len(preferred) {
+				break
+			}
+			diff := inputs[i].Value - preferred[i]
+			if span := limit.Max - limit.Min; !math.IsInf(span, 0) && span > 0 {
+				diff /= span
+			}
+			score += diff * diff
+		}
+		return score
+	}
+}
+
+// ManipulabilityObjective scores the inputs of the frame by the negative of their manipulability, such that configurations far from
+// singularities score lowest.
+func ManipulabilityObjective(f referenceframe.Frame, inputs []referenceframe.Input) float64 {
+	manipulability, err := Manipulability(f, inputs)
+	if err != nil {
+		return math.Inf(1)
+	}
+	return -manipulability
+}
+
+// Manipulability returns the manipulability of the frame at the given inputs, sqrt(det(J Jᵀ)) for its Jacobian J, which is
+// proportional to the volume of the ellipsoid of velocities its end can reach from them, and which falls to zero at singularities,
+// where it cannot move in some direction however fast its inputs move. Distances are measured in meters so that translation and
+// rotation are weighted alike.
+func Manipulability(f referenceframe.Frame, inputs []referenceframe.Input) (float64, error) {
+	jac, err := Jacobian(f, inputs)
+	if err != nil {
+		return 0, err
+	}
+	for i := 0; i < 3; i++ {
+		row := jac.RawRowView(i)
+		for j := range row {
+			row[j] /= 1000
+		}
+	}
+	// frames with fewer than six inputs can move their ends in only as many directions, so the volume is taken in those alone
+	var jjt mat.Dense
+	if rows, cols := jac.Dims(); cols < rows {
+		jjt.Mul(jac.T(), jac)
+	} else {
+		jjt.Mul(jac, jac.T())
+	}
+	return math.Sqrt(math.Max(mat.Det(&jjt), 0)), nil
+}
+
+// Jacobian numerically estimates the Jacobian of the pose of the frame with respect to its inputs, as a 6xN matrix whose first three
+// rows are the translation in mm and whose last three are the rotation in radians of the end of the frame, both measured in the frame
+// of its end at the given inputs.
+func Jacobian(f referenceframe.Frame, inputs []referenceframe.Input) (*mat.Dense, error) {
+	pose, err := f.Transform(inputs)
+	if err != nil {
+		return nil, err
+	}
+	jac := mat.NewDense(6, len(inputs), nil)
+	perturbed := make([]referenceframe.Input, len(inputs))
+	for j := range inputs {
+		copy(perturbed, inputs)
+		perturbed[j].Value += nullspaceDiffStep
+		next, err := f.Transform(perturbed)
+		if err != nil {
+			// step the other way from inputs at the upper end of their range
+			perturbed[j].Value = inputs[j].Value - nullspaceDiffStep
+			if next, err = f.Transform(perturbed); err != nil {
+				return nil, err
+			}
+		}
+		twist := poseTwist(pose, next)
+		scale := 1 / (perturbed[j].Value - inputs[j].Value)
+		for i, v := range twist {
+			jac.Set(i, j, v*scale)
+		}
+	}
+	return jac, nil
+}
+
+// NullspaceDescent moves the inputs of the frame through the nullspace of its Jacobian, in which they change without moving the end of
+// the frame, for the given number of iterations of descent on the objective. If the frame is not redundant, if no step improves the
+// objective, or if the end of the frame would drift from where the given inputs place it, the inputs are returned unchanged.
+func NullspaceDescent(
+	f referenceframe.Frame,
+	inputs []referenceframe.Input,
+	objective NullspaceObjective,
+	iterations int,
+) []referenceframe.Input {
+	if len(f.DoF()) <= 6 || objective == nil {
+		return inputs
+	}
+	goal, err := f.Transform(inputs)
+	if err != nil {
+		return inputs
+	}
+	current := append([]referenceframe.Input{}, inputs...)
+	currentScore := objective(f, current)
+	for iter := 0; iter < iterations; iter++ {
+		jac, err := Jacobian(f, current)
+		if err != nil {
+			break
+		}
+		projector := nullspaceProjector(jac)
+		var step mat.VecDense
+		step.MulVec(projector, objectiveGradient(f, current, objective))
+		largest := mat.Norm(&step, math.Inf(1))
+		if largest < defaultEpsilon*nullspaceDiffStep {
+			break
+		}
+		step.ScaleVec(-nullspaceMaxStep/largest, &step)
+
+		improved := false
+		for scale := 1.; scale > 1e-3 && !improved; scale /= 2 {
+			candidate := make([]referenceframe.Input, len(current))
+			for i := range current {
+				candidate[i] = referenceframe.Input{Value: current[i].Value + scale*step.AtVec(i)}
+			}
+			candidate, ok := correctDrift(f, candidate, goal)
+			if !ok {
+				continue
+			}
+			if score := objective(f, candidate); score < currentScore {
+				current, currentScore, improved = candidate, score, true
+			}
+		}
+		if !improved {
+			break
+		}
+	}
+	return current
+}
+
+// correctDrift moves the inputs of the frame by Newton steps until their end is back at the goal pose, reporting whether they reached
+// it while staying within the limits of the frame.
+func correctDrift(f referenceframe.Frame, inputs []referenceframe.Input, goal spatial.Pose) ([]referenceframe.Input, bool) {
+	for i := 0; i < 5; i++ {
+		pose, err := f.Transform(inputs)
+		if err != nil {
+			return nil, false
+		}
+		twist := poseTwist(pose, goal)
+		if math.Max(mat.Norm(mat.NewVecDense(3, twist[:3]), 2), mat.Norm(mat.NewVecDense(3, twist[3:]), 2)) < nullspaceMaxDrift {
+			return inputs, true
+		}
+		jac, err := Jacobian(f, inputs)
+		if err != nil {
+			return nil, false
+		}
+		var correction mat.VecDense
+		correction.MulVec(pseudoinverse(jac), mat.NewVecDense(6, twist))
+		for j := range inputs {
+			inputs[j].Value += correction.AtVec(j)
+		}
+	}
+	return nil, false
+}
+
+// poseTwist returns the translation and rotation from one pose to another, measured in the frame of the first. The rotation vector is
+// found from the quaternion directly, as conversion to axis angles loses the tiny rotations which Jacobians are estimated from.
+func poseTwist(from, to spatial.Pose) []float64 {
+	between := spatial.PoseBetween(from, to)
+	pt := between.Point()
+	q := between.Orientation().Quaternion()
+	if q.Real < 0 {
+		q = quat.Scale(-1, q)
+	}
+	imag := math.Sqrt(q.Imag*q.Imag + q.Jmag*q.Jmag + q.Kmag*q.Kmag)
+	scale := 2 / q.Real
+	if imag > defaultEpsilon*defaultEpsilon {
+		scale = 2 * math.Atan2(imag, q.Real) / imag
+	}
+	return []float64{pt.X, pt.Y, pt.Z, scale * q.Imag, scale * q.Jmag, scale * q.Kmag}
+}
+
+// objectiveGradient numerically estimates the gradient of the objective with respect to the inputs of the frame.
+func objectiveGradient(f referenceframe.Frame, inputs []referenceframe.Input, objective NullspaceObjective) *mat.VecDense {
+	grad := mat.NewVecDense(len(inputs), nil)
+	perturbed := make([]referenceframe.Input, len(inputs))
+	for j := range inputs {
+		copy(perturbed, inputs)
+		perturbed[j].Value = inputs[j].Value + nullspaceGradientStep
+		up := objective(f, perturbed)
+		perturbed[j].Value = inputs[j].Value - nullspaceGradientStep
+		down := objective(f, perturbed)
+		if !math.IsInf(up, 0) && !math.IsInf(down, 0) {
+			grad.SetVec(j, (up-down)/(2*nullspaceGradientStep))
+		}
+	}
+	return grad
+}
+
+// pseudoinverse returns the damped right pseudoinverse of the Jacobian, J^T (J J^T + λ²I)^-1.
+func pseudoinverse(jac *mat.Dense) *mat.Dense {
+	rows, cols := jac.Dims()
+	var jjt mat.Dense
+	jjt.Mul(jac, jac.T())
+	for i := 0; i < rows; i++ {
+		jjt.Set(i, i, jjt.At(i, i)+nullspaceDamping*nullspaceDamping)
+	}
+	var inv mat.Dense
+	if err := inv.Inverse(&jjt); err != nil {
+		return mat.NewDense(cols, rows, nil)
+	}
+	var pinv mat.Dense
+	pinv.Mul(jac.T(), &inv)
+	return &pinv
+}
+
+// nullspaceProjector returns the matrix I - J⁺J which projects changes of inputs onto the nullspace of the Jacobian.
+func nullspaceProjector(jac *mat.Dense) *mat.Dense {
+	_, cols := jac.Dims()
+	var proj mat.Dense
+	proj.Mul(pseudoinverse(jac), jac)
+	ident := mat.NewDense(cols, cols, nil)
+	for i := 0; i < cols; i++ {
+		ident.Set(i, i, 1)
+	}
+	proj.Sub(ident, &proj)
+	return &proj
+}
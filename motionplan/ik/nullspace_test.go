@@ -0,0 +1,87 @@
+package ik
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/referenceframe"
+	spatial "go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+func TestNullspaceDescent(t *testing.T) {
+	xarm7, err := referenceframe.ParseModelJSONFile(utils.ResolveFile("components/arm/example_kinematics/xarm7_kinematics_test.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+	inputs := referenceframe.FloatsToInputs([]float64{1.5, 0.5, -1.2, 1, 0.3, 0.8, -0.4})
+	start, err := xarm7.Transform(inputs)
+	test.That(t, err, test.ShouldBeNil)
+
+	t.Run("the Jacobian predicts small motions", func(t *testing.T) {
+		jac, err := Jacobian(xarm7, inputs)
+		test.That(t, err, test.ShouldBeNil)
+		rows, cols := jac.Dims()
+		test.That(t, rows, test.ShouldEqual, 6)
+		test.That(t, cols, test.ShouldEqual, 7)
+
+		moved := append([]referenceframe.Input{}, inputs...)
+		moved[2].Value += 1e-3
+		end, err := xarm7.Transform(moved)
+		test.That(t, err, test.ShouldBeNil)
+		twist := poseTwist(start, end)
+		for i := range twist {
+			test.That(t, twist[i], test.ShouldAlmostEqual, jac.At(i, 2)*1e-3, 1e-3)
+		}
+	})
+
+	for _, tc := range []struct {
+		name      string
+		objective NullspaceObjective
+	}{
+		{"joint limits are avoided", JointLimitObjective},
+		{"preferred postures are approached", NewPostureObjective([]float64{0, 0, 0, 1, 0, 1, 0})},
+		{"manipulability is maximized", ManipulabilityObjective},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			refined := NullspaceDescent(xarm7, inputs, tc.objective, 50)
+			test.That(t, tc.objective(xarm7, refined), test.ShouldBeLessThan, tc.objective(xarm7, inputs))
+
+			// the end of the arm stays where it was
+			end, err := xarm7.Transform(refined)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, spatial.PoseAlmostEqualEps(start, end, 1e-3), test.ShouldBeTrue)
+		})
+	}
+
+	t.Run("frames which are not redundant are unchanged", func(t *testing.T) {
+		ur5e, err := referenceframe.ParseModelJSONFile(utils.ResolveFile("components/arm/universalrobots/ur5e.json"), "")
+		test.That(t, err, test.ShouldBeNil)
+		inputs := referenceframe.FloatsToInputs([]float64{0, -math.Pi / 2, math.Pi / 2, -math.Pi / 2, -math.Pi / 2, 0})
+		test.That(t, NullspaceDescent(ur5e, inputs, JointLimitObjective, 50), test.ShouldResemble, inputs)
+	})
+}
+
+func TestManipulability(t *testing.T) {
+	ur5e, err := referenceframe.ParseModelJSONFile(utils.ResolveFile("components/arm/universalrobots/ur5e.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+	wrist := func(radians float64) float64 {
+		manipulability, err := Manipulability(ur5e, referenceframe.FloatsToInputs(
+			[]float64{0, -math.Pi / 2, math.Pi / 2, -math.Pi / 2, radians, 0},
+		))
+		test.That(t, err, test.ShouldBeNil)
+		return manipulability
+	}
+	// manipulability falls to zero as the wrist straightens into its singularity
+	test.That(t, wrist(0), test.ShouldAlmostEqual, 0, 1e-6)
+	test.That(t, wrist(0.1), test.ShouldBeGreaterThan, wrist(0.01))
+	test.That(t, wrist(math.Pi/2), test.ShouldBeGreaterThan, wrist(0.1))
+
+	// frames with fewer than six inputs are measured in the directions they can move
+	slider, err := referenceframe.NewTranslationalFrame("slider", r3.Vector{X: 1}, referenceframe.Limit{Min: -1000, Max: 1000})
+	test.That(t, err, test.ShouldBeNil)
+	manipulability, err := Manipulability(slider, referenceframe.FloatsToInputs([]float64{0}))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, manipulability, test.ShouldAlmostEqual, 1e-3, 1e-6)
+}
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
 	"go.viam.com/utils"
 
 	"go.viam.com/rdk/logging"
@@ -169,6 +170,25 @@ func PlanMotion(ctx context.Context, request *PlanRequest) (Plan, error) {
 	return Replan(ctx, request, nil, 0)
 }
 
+// PlanMotionWithTrees plans a motion from a provided plan request as PlanMotion does, additionally returning the search trees grown by
+// the planner for each waypoint if the capture_tree option is set. Trees are returned even if planning fails, so that developers may
+// visualize why a plan could not be found or took a long time to find.
+func PlanMotionWithTrees(ctx context.Context, request *PlanRequest) (Plan, []*PlannerTree, error) {
+	if err := request.validatePlanRequest(); err != nil {
+		return nil, nil, err
+	}
+	rseed := defaultRandomSeed
+	if seed, ok := request.Options["rseed"].(int); ok {
+		rseed = seed
+	}
+	pm, err := newPlanManager(request.FrameSystem, request.Logger, rseed)
+	if err != nil {
+		return nil, nil, err
+	}
+	plan, err := pm.planMultiWaypoint(ctx, request, nil)
+	return plan, pm.capturedTrees(), err
+}
+
 // PlanFrameMotion plans a motion to destination for a given frame with no frame system. It will create a new FS just for the plan.
 // WorldState is not supported in the absence of a real frame system.
 func PlanFrameMotion(ctx context.Context,
@@ -178,17 +198,34 @@ func PlanFrameMotion(ctx context.Context,
 	seed []referenceframe.Input,
 	constraints *Constraints,
 	planningOpts map[string]interface{},
+) ([][]referenceframe.Input, error) {
+	return PlanFrameMotionThrough(ctx, logger, []spatialmath.Pose{dst}, f, seed, constraints, planningOpts)
+}
+
+// PlanFrameMotionThrough plans the motion of a single frame through each of the given destinations in turn. The destinations are
+// planned for together, as the waypoints of a single plan, and the returned trajectory passes through all of them without stopping
+// between them.
+func PlanFrameMotionThrough(ctx context.Context,
+	logger logging.Logger,
+	dsts []spatialmath.Pose,
+	f referenceframe.Frame,
+	seed []referenceframe.Input,
+	constraints *Constraints,
+	planningOpts map[string]interface{},
 ) ([][]referenceframe.Input, error) {
 	// ephemerally create a framesystem containing just the frame for the solve
 	fs := referenceframe.NewEmptyFrameSystem("")
 	if err := fs.AddFrame(f, fs.World()); err != nil {
 		return nil, err
 	}
+	goals := make([]*PlanState, 0, len(dsts))
+	for _, dst := range dsts {
+		goal := referenceframe.NewPoseInFrame(referenceframe.World, dst)
+		goals = append(goals, &PlanState{poses: referenceframe.FrameSystemPoses{f.Name(): goal}})
+	}
 	plan, err := PlanMotion(ctx, &PlanRequest{
-		Logger: logger,
-		Goals: []*PlanState{
-			{poses: referenceframe.FrameSystemPoses{f.Name(): referenceframe.NewPoseInFrame(referenceframe.World, dst)}},
-		},
+		Logger:      logger,
+		Goals:       goals,
 		StartState:  &PlanState{configuration: referenceframe.FrameSystemInputs{f.Name(): seed}},
 		FrameSystem: fs,
 		Constraints: constraints,
@@ -200,9 +237,75 @@ func PlanFrameMotion(ctx context.Context,
 	return plan.Trajectory().GetFrameInputs(f.Name())
 }
 
+// IKSolution is a configuration of a frame system which reaches the goal of an IK request.
+type IKSolution struct {
+	Configuration referenceframe.FrameSystemInputs
+	// Cost is the distance of the configuration from the start configuration of the request.
+	Cost float64
+}
+
+// BestIKSolutions solves for configurations of the frame system which place the frames of the single goal of the request at their goal
+// poses while satisfying the constraints of the request, including collisions with its world state. No path to the configurations is
+// planned. Up to max_ik_solutions distinct solutions are returned, ordered from the nearest to the start configuration.
+func BestIKSolutions(ctx context.Context, request *PlanRequest) ([]*IKSolution, error) {
+	if err := request.validatePlanRequest(); err != nil {
+		return nil, err
+	}
+	if len(request.Goals) != 1 || len(request.Goals[0].poses) == 0 {
+		return nil, errors.New("IK solutions can only be found for a single goal of poses")
+	}
+
+	rseed := defaultRandomSeed
+	if seed, ok := request.Options["rseed"].(int); ok {
+		rseed = seed
+	}
+	pm, err := newPlanManager(request.FrameSystem, request.Logger, rseed)
+	if err != nil {
+		return nil, err
+	}
+	start := request.StartState.configuration
+	goal := request.Goals[0]
+	opt, err := pm.plannerSetupFromMoveRequest(
+		request.StartState, goal, start, request.WorldState, request.BoundingRegions, request.Constraints, request.Options,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if opt.useTPspace {
+		return nil, errors.New("IK solutions cannot be found for PTG frames")
+	}
+	// Regenerate opts for goals transformed into the world frame, as when planning
+	if goal, err = alterGoals(opt.motionChains, pm.fs, start, goal); err != nil {
+		return nil, err
+	}
+	opt, err = pm.plannerSetupFromMoveRequest(
+		request.StartState, goal, start, request.WorldState, request.BoundingRegions, request.Constraints, request.Options,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	mp, err := newPlanner(pm.fs, pm.randseed, pm.logger, opt)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := mp.getSolutions(ctx, start, opt.getGoalMetric(goal.poses))
+	if err != nil {
+		return nil, err
+	}
+	solutions := make([]*IKSolution, 0, len(nodes))
+	for _, n := range nodes {
+		solutions = append(solutions, &IKSolution{Configuration: n.Q(), Cost: n.Cost()})
+	}
+	return solutions, nil
+}
+
 // Replan plans a motion from a provided plan request, and then will return that plan only if its cost is better than the cost of the
 // passed-in plan multiplied by `replanCostFactor`.
 func Replan(ctx context.Context, request *PlanRequest, currentPlan Plan, replanCostFactor float64) (Plan, error) {
+	ctx, span := trace.StartSpan(ctx, "motionplan::Replan")
+	defer span.End()
+
 	// Make sure request is well formed and not missing vital information
 	if err := request.validatePlanRequest(); err != nil {
 		return nil, err
@@ -420,7 +523,10 @@ func (mp *planner) getSolutions(ctx context.Context, seed referenceframe.FrameSy
 		ikErr <- mp.solver.Solve(ctxWithCancel, solutionGen, linearSeed, minFunc, mp.randseed.Int())
 	})
 
+	// solutions are keyed by their rank, their distance from the seed plus their score by any nullspace objectives
 	solutions := map[float64]referenceframe.FrameSystemInputs{}
+	distances := map[float64]float64{}
+	moving, _ := mp.frameLists()
 
 	// A map keeping track of which constraints fail
 	failures := map[string]int{}
@@ -447,6 +553,7 @@ IK:
 				// if nil, step is guaranteed to fail the below check, but we want to do it anyway to capture the failure reason
 				step = alteredStep
 			}
+			step, objectiveScore := mp.planOpts.resolveRedundancy(mp.fs, moving, step)
 			// Ensure the end state is a valid one
 			statePass, failName := mp.planOpts.CheckStateFSConstraints(&ik.StateFS{
 				Configuration: step,
@@ -462,9 +569,11 @@ IK:
 
 				if arcPass {
 					score := mp.planOpts.configurationDistanceFunc(stepArc)
+					rank := score + objectiveScore
 					if score < mp.planOpts.MinScore && mp.planOpts.MinScore > 0 {
 						solutions = map[float64]referenceframe.FrameSystemInputs{}
-						solutions[score] = step
+						solutions[rank] = step
+						distances = map[float64]float64{rank: score}
 						// good solution, stopping early
 						break IK
 					}
@@ -480,7 +589,8 @@ IK:
 						}
 					}
 
-					solutions[score] = step
+					solutions[rank] = step
+					distances[rank] = score
 					if len(solutions) >= nSolutions {
 						// sufficient solutions found, stopping early
 						break IK
@@ -536,7 +646,7 @@ IK:
 
 	orderedSolutions := make([]node, 0)
 	for _, key := range keys {
-		orderedSolutions = append(orderedSolutions, &basicNode{q: solutions[key], cost: key})
+		orderedSolutions = append(orderedSolutions, &basicNode{q: solutions[key], cost: distances[key]})
 	}
 	return orderedSolutions, nil
 }
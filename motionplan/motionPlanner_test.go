@@ -264,6 +264,7 @@ func simple2DMap() (*planConfig, error) {
 		worldGeometries.Geometries(),
 		nil, nil,
 		defaultCollisionBufferMM,
+		nil,
 	)
 	if err != nil {
 		return nil, err
@@ -328,6 +329,7 @@ func simpleXArmMotion() (*planConfig, error) {
 		nil,
 		nil,
 		defaultCollisionBufferMM,
+		nil,
 	)
 	if err != nil {
 		return nil, err
@@ -395,6 +397,7 @@ func simpleUR5eMotion() (*planConfig, error) {
 		nil,
 		nil,
 		defaultCollisionBufferMM,
+		nil,
 	)
 	if err != nil {
 		return nil, err
@@ -633,6 +636,68 @@ func TestReachOverArm(t *testing.T) {
 	test.That(t, len(plan.Trajectory()), test.ShouldBeGreaterThan, 2)
 }
 
+func TestPlanMotionWithTrees(t *testing.T) {
+	xarm, err := frame.ParseModelJSONFile(utils.ResolveFile("components/arm/example_kinematics/xarm6_kinematics_test.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+	offset, err := frame.NewStaticFrame("offset", spatialmath.NewPoseFromPoint(r3.Vector{X: -500, Y: 200}))
+	test.That(t, err, test.ShouldBeNil)
+	ur5, err := frame.ParseModelJSONFile(utils.ResolveFile("components/arm/universalrobots/ur5e.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+	fs := frame.NewEmptyFrameSystem("test")
+	test.That(t, fs.AddFrame(offset, fs.World()), test.ShouldBeNil)
+	test.That(t, fs.AddFrame(xarm, offset), test.ShouldBeNil)
+	// the UR arm is in the way of the xarm, such that the planner has to search for a path rather than interpolating
+	test.That(t, fs.AddFrame(ur5, fs.World()), test.ShouldBeNil)
+
+	goal := frame.NewPoseInFrame("offset", spatialmath.NewPose(r3.Vector{Y: -500, Z: 100}, &spatialmath.OrientationVector{OZ: -1}))
+	request := func(opts map[string]interface{}) *PlanRequest {
+		return &PlanRequest{
+			Logger:      logger,
+			Goals:       []*PlanState{{poses: frame.FrameSystemPoses{xarm.Name(): goal}}},
+			StartState:  &PlanState{configuration: frame.NewZeroInputs(fs)},
+			FrameSystem: fs,
+			Options:     opts,
+		}
+	}
+
+	t.Run("trees are not captured by default", func(t *testing.T) {
+		plan, trees, err := PlanMotionWithTrees(context.Background(), request(map[string]interface{}{"timeout": 150.0, "smooth_iter": 5}))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, plan, test.ShouldNotBeNil)
+		test.That(t, trees, test.ShouldBeEmpty)
+	})
+
+	t.Run("trees are captured if requested", func(t *testing.T) {
+		opts := map[string]interface{}{"timeout": 150.0, "smooth_iter": 5, "capture_tree": true}
+		plan, trees, err := PlanMotionWithTrees(context.Background(), request(opts))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(plan.Trajectory()), test.ShouldBeGreaterThan, 2)
+		test.That(t, trees, test.ShouldNotBeEmpty)
+
+		tree := trees[0]
+		test.That(t, tree.Err, test.ShouldBeNil)
+		test.That(t, len(tree.StartTree), test.ShouldBeGreaterThan, 1)
+		test.That(t, tree.GoalTree, test.ShouldNotBeEmpty)
+		roots := 0
+		for _, edge := range tree.StartTree {
+			test.That(t, edge.Child.Configuration[xarm.Name()], test.ShouldNotBeNil)
+			if edge.Parent == nil {
+				roots++
+			}
+		}
+		test.That(t, roots, test.ShouldBeGreaterThan, 0)
+		test.That(t, roots, test.ShouldBeLessThan, len(tree.StartTree))
+	})
+
+	t.Run("trees are returned if planning fails", func(t *testing.T) {
+		opts := map[string]interface{}{"timeout": 150.0, "plan_iter": 1, "capture_tree": true}
+		_, trees, err := PlanMotionWithTrees(context.Background(), request(opts))
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, trees, test.ShouldNotBeEmpty)
+		test.That(t, trees[0].Err, test.ShouldNotBeNil)
+	})
+}
+
 func TestPlanMapMotion(t *testing.T) {
 	ctx := context.Background()
 	logger := logging.NewTestLogger(t)
@@ -0,0 +1,83 @@
+//go:build !no_cgo
+
+package motionplan
+
+import (
+	"go.viam.com/rdk/motionplan/ik"
+	"go.viam.com/rdk/referenceframe"
+)
+
+// defaultNullspaceIterations is the number of steps through its nullspace which each IK solution for a redundant frame is moved by.
+const defaultNullspaceIterations = 20
+
+// NullspaceObjectives weight the secondary objectives toward which the IK solutions of redundant frames, such as 7-DoF arms like the
+// xArm7, are moved through their nullspace, without moving the ends of those frames. Solutions are also ranked by how well they meet
+// these objectives, alongside their distance from the start. A weight left at zero disables its objective.
+type NullspaceObjectives struct {
+	// JointLimitAvoidance keeps joints near the middle of their ranges.
+	JointLimitAvoidance float64 `json:"joint_limit_avoidance"`
+	// Manipulability keeps frames away from singularities.
+	Manipulability float64 `json:"manipulability"`
+	// Posture keeps frames near their preferred posture, such as one holding the elbow of an arm up.
+	Posture float64 `json:"posture"`
+	// PreferredPosture is the preferred posture of each redundant frame, by frame name, in radians or mm per input.
+	PreferredPosture map[string][]float64 `json:"preferred_posture"`
+}
+
+// objective returns the weighted sum of the objectives which apply to the named frame, or nil if none do.
+func (n *NullspaceObjectives) objective(frame string) ik.NullspaceObjective {
+	if n == nil {
+		return nil
+	}
+	type weighted struct {
+		objective ik.NullspaceObjective
+		weight    float64
+	}
+	objectives := []weighted{}
+	if n.JointLimitAvoidance > 0 {
+		objectives = append(objectives, weighted{ik.JointLimitObjective, n.JointLimitAvoidance})
+	}
+	if n.Manipulability > 0 {
+		objectives = append(objectives, weighted{ik.ManipulabilityObjective, n.Manipulability})
+	}
+	if preferred, ok := n.PreferredPosture[frame]; ok && n.Posture > 0 {
+		objectives = append(objectives, weighted{ik.NewPostureObjective(preferred), n.Posture})
+	}
+	if len(objectives) == 0 {
+		return nil
+	}
+	return func(f referenceframe.Frame, inputs []referenceframe.Input) float64 {
+		score := 0.
+		for _, o := range objectives {
+			score += o.weight * o.objective(f, inputs)
+		}
+		return score
+	}
+}
+
+// resolveRedundancy moves each of the given moving frames which is redundant through its nullspace toward the configured objectives,
+// returning the resulting configuration and its total score by those objectives.
+func (p *plannerOptions) resolveRedundancy(
+	fs referenceframe.FrameSystem,
+	moving []string,
+	step referenceframe.FrameSystemInputs,
+) (referenceframe.FrameSystemInputs, float64) {
+	if p.NullspaceObjectives == nil {
+		return step, 0
+	}
+	resolved := referenceframe.FrameSystemInputs{}
+	for name, inputs := range step {
+		resolved[name] = inputs
+	}
+	score := 0.
+	for _, name := range moving {
+		frame := fs.Frame(name)
+		objective := p.NullspaceObjectives.objective(name)
+		if frame == nil || objective == nil || len(frame.DoF()) <= 6 {
+			continue
+		}
+		resolved[name] = ik.NullspaceDescent(frame, step[name], objective, defaultNullspaceIterations)
+		score += objective(frame, resolved[name])
+	}
+	return resolved, score
+}
@@ -0,0 +1,70 @@
+//go:build !no_cgo
+
+package motionplan
+
+import (
+	"encoding/json"
+	"testing"
+
+	"go.viam.com/test"
+
+	frame "go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+func TestNullspaceObjectives(t *testing.T) {
+	xarm7, err := frame.ParseModelJSONFile(utils.ResolveFile("components/arm/example_kinematics/xarm7_kinematics_test.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+	fs := frame.NewEmptyFrameSystem("")
+	test.That(t, fs.AddFrame(xarm7, fs.World()), test.ShouldBeNil)
+	inputs := frame.FloatsToInputs([]float64{1.5, 0.5, -1.2, 1, 0.3, 0.8, -0.4})
+	step := frame.FrameSystemInputs{xarm7.Name(): inputs}
+
+	t.Run("objectives are parsed from planning options", func(t *testing.T) {
+		opt := newBasicPlannerOptions()
+		raw, err := json.Marshal(map[string]interface{}{"nullspace_objectives": map[string]interface{}{
+			"joint_limit_avoidance": 1.,
+			"posture":               0.5,
+			"preferred_posture":     map[string]interface{}{xarm7.Name(): []float64{0, 0, 0, 1, 0, 1, 0}},
+		}})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, json.Unmarshal(raw, opt), test.ShouldBeNil)
+		test.That(t, *opt.NullspaceObjectives, test.ShouldResemble, NullspaceObjectives{
+			JointLimitAvoidance: 1,
+			Posture:             0.5,
+			PreferredPosture:    map[string][]float64{xarm7.Name(): {0, 0, 0, 1, 0, 1, 0}},
+		})
+	})
+
+	t.Run("no objectives leave solutions unchanged", func(t *testing.T) {
+		opt := newBasicPlannerOptions()
+		resolved, score := opt.resolveRedundancy(fs, []string{xarm7.Name()}, step)
+		test.That(t, resolved, test.ShouldResemble, step)
+		test.That(t, score, test.ShouldEqual, 0)
+
+		opt.NullspaceObjectives = &NullspaceObjectives{Posture: 1}
+		test.That(t, opt.NullspaceObjectives.objective(xarm7.Name()), test.ShouldBeNil)
+	})
+
+	t.Run("redundant frames are moved toward their objectives", func(t *testing.T) {
+		opt := newBasicPlannerOptions()
+		opt.NullspaceObjectives = &NullspaceObjectives{JointLimitAvoidance: 1, Manipulability: 0.1}
+		objective := opt.NullspaceObjectives.objective(xarm7.Name())
+		test.That(t, objective, test.ShouldNotBeNil)
+
+		resolved, score := opt.resolveRedundancy(fs, []string{xarm7.Name()}, step)
+		test.That(t, score, test.ShouldBeLessThan, objective(xarm7, inputs))
+		test.That(t, score, test.ShouldAlmostEqual, objective(xarm7, resolved[xarm7.Name()]))
+
+		before, err := xarm7.Transform(inputs)
+		test.That(t, err, test.ShouldBeNil)
+		after, err := xarm7.Transform(resolved[xarm7.Name()])
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, spatialmath.PoseAlmostEqualEps(before, after, 1e-3), test.ShouldBeTrue)
+
+		// frames which are not moving are left where they are
+		resolved, _ = opt.resolveRedundancy(fs, nil, step)
+		test.That(t, resolved, test.ShouldResemble, step)
+	})
+}
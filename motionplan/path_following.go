@@ -0,0 +1,47 @@
+//go:build !no_cgo
+
+package motionplan
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// PlanFrameMotionAlongPath plans the motion of a single frame along a Cartesian path, such as the seam of a weld, given as a dense
+// sequence of poses. The frame first moves freely from its seed to the start of the path, and then follows the path from pose to pose
+// without stopping, deviating from the straight line and slerped orientation between each pair of consecutive poses by no more than
+// the tolerances of the given linear constraint. A tolerance left at zero takes its default. The returned trajectory covers both the
+// approach and the path.
+func PlanFrameMotionAlongPath(ctx context.Context,
+	logger logging.Logger,
+	path []spatialmath.Pose,
+	f referenceframe.Frame,
+	seed []referenceframe.Input,
+	tolerance LinearConstraint,
+	planningOpts map[string]interface{},
+) ([][]referenceframe.Input, error) {
+	if len(path) < 2 {
+		return nil, errors.New("a path to follow must have at least two poses")
+	}
+	if tolerance.LineToleranceMm < 0 || tolerance.OrientationToleranceDegs < 0 {
+		return nil, errors.New("path following tolerances may not be negative")
+	}
+
+	approach, err := PlanFrameMotion(ctx, logger, path[0], f, seed, nil, planningOpts)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not plan approach to the start of the path")
+	}
+	constraints := NewEmptyConstraints()
+	constraints.AddLinearConstraint(tolerance)
+	follow, err := PlanFrameMotionThrough(ctx, logger, path[1:], f, approach[len(approach)-1], constraints, planningOpts)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not plan motion along the path")
+	}
+	// the path is followed from where the approach ends, which is therefore the first step of both
+	return append(approach, follow[1:]...), nil
+}
@@ -0,0 +1,71 @@
+//go:build !no_cgo
+
+package motionplan
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	frame "go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+func TestPlanFrameMotionAlongPath(t *testing.T) {
+	ur5e, err := frame.ParseModelJSONFile(utils.ResolveFile("components/arm/universalrobots/ur5e.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+	seed := frame.FloatsToInputs([]float64{0, -math.Pi / 2, math.Pi / 2, -math.Pi / 2, -math.Pi / 2, 0})
+	start, err := ur5e.Transform(seed)
+	test.That(t, err, test.ShouldBeNil)
+
+	// a seam running 100mm away from and then 100mm across from a point near the start of the arm
+	corner := spatialmath.Compose(start, spatialmath.NewPoseFromPoint(r3.Vector{X: 50, Y: 50}))
+	path := []spatialmath.Pose{}
+	for x := 0.; x <= 100; x += 10 {
+		path = append(path, spatialmath.Compose(corner, spatialmath.NewPoseFromPoint(r3.Vector{X: x})))
+	}
+	for y := 10.; y <= 100; y += 10 {
+		path = append(path, spatialmath.Compose(corner, spatialmath.NewPoseFromPoint(r3.Vector{X: 100, Y: y})))
+	}
+
+	t.Run("invalid paths are rejected", func(t *testing.T) {
+		_, err := PlanFrameMotionAlongPath(context.Background(), logger, path[:1], ur5e, seed, LinearConstraint{}, nil)
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = PlanFrameMotionAlongPath(context.Background(), logger, path, ur5e, seed, LinearConstraint{LineToleranceMm: -1}, nil)
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("the path is followed within tolerance", func(t *testing.T) {
+		tolerance := LinearConstraint{LineToleranceMm: 1, OrientationToleranceDegs: 2}
+		traj, err := PlanFrameMotionAlongPath(context.Background(), logger, path, ur5e, seed, tolerance, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, traj[0], test.ShouldResemble, seed)
+
+		poses := make([]spatialmath.Pose, 0, len(traj))
+		for _, inputs := range traj {
+			pose, err := ur5e.Transform(inputs)
+			test.That(t, err, test.ShouldBeNil)
+			poses = append(poses, pose)
+		}
+		test.That(t, spatialmath.PoseAlmostCoincidentEps(poses[len(poses)-1], path[len(path)-1], 1), test.ShouldBeTrue)
+
+		// once the approach reaches the start of the path, every step stays on the path
+		following := false
+		for _, pose := range poses {
+			if !following {
+				following = pose.Point().Distance(path[0].Point()) < 1
+				continue
+			}
+			dist := math.Inf(1)
+			for i := 1; i < len(path); i++ {
+				dist = math.Min(dist, spatialmath.DistToLineSegment(path[i-1].Point(), path[i].Point(), pose.Point()))
+			}
+			test.That(t, dist, test.ShouldBeLessThanOrEqualTo, tolerance.LineToleranceMm+defaultEpsilon)
+		}
+		test.That(t, following, test.ShouldBeTrue)
+	})
+}
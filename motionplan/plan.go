@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"strings"
 
 	"github.com/golang/geo/r3"
 	geo "github.com/kellydunn/golang-geo"
@@ -24,42 +25,66 @@ type Plan interface {
 	Path() Path
 }
 
-// RemainingPlan returns a new Plan equal to the given plan from the waypointIndex onwards.
+// RemainingPlan returns a new Plan equal to the given plan from the waypointIndex onwards. Plans of multiple frames may omit frames from
+// the steps in which they do not move, so the first step of the remaining plan is filled in with the most recent inputs and poses of
+// every frame, such that it describes the full state from which the remainder of the plan starts.
 func RemainingPlan(plan Plan, waypointIndex int) (Plan, error) {
 	if waypointIndex < 0 {
 		return nil, errors.New("could not access plan with negative waypoint index")
 	}
+	// plans may lack either a trajectory or a path, in which case the remaining plan lacks it too
 	traj := plan.Trajectory()
-	if traj != nil && waypointIndex > len(traj) {
+	if len(traj) > 0 && waypointIndex > len(traj) {
 		return nil, fmt.Errorf("could not access trajectory index %d, must be less than %d", waypointIndex, len(plan.Trajectory()))
 	}
 	path := plan.Path()
-	if path != nil && waypointIndex > len(path) {
+	if len(path) > 0 && waypointIndex > len(path) {
 		return nil, fmt.Errorf("could not access path index %d, must be less than %d", waypointIndex, len(plan.Path()))
 	}
-	simplePlan := NewSimplePlan(path[waypointIndex:], traj[waypointIndex:])
-	if rrt, ok := plan.(*rrtPlan); ok {
+	var remainingTraj Trajectory
+	if len(traj) > 0 {
+		remainingTraj = append(Trajectory{}, traj[waypointIndex:]...)
+		if len(remainingTraj) > 0 {
+			remainingTraj[0] = traj.stateAt(waypointIndex)
+		}
+	}
+	var remainingPath Path
+	if len(path) > 0 {
+		remainingPath = append(Path{}, path[waypointIndex:]...)
+		if len(remainingPath) > 0 {
+			remainingPath[0] = path.stateAt(waypointIndex)
+		}
+	}
+	simplePlan := NewSimplePlan(remainingPath, remainingTraj)
+	simplePlan.metadata = remainingWaypointMetadata(PlanWaypointMetadata(plan), waypointIndex)
+	if rrt, ok := plan.(*rrtPlan); ok && waypointIndex <= len(rrt.nodes) {
 		return &rrtPlan{SimplePlan: *simplePlan, nodes: rrt.nodes[waypointIndex:]}, nil
 	}
 	return simplePlan, nil
 }
 
 // OffsetPlan returns a new Plan that is equivalent to the given Plan if its Path was offset by the given Pose.
-// Does not modify Trajectory.
+// Only poses in the world frame are offset, as poses of any other frames of a multi-frame plan are relative to their parent frames and
+// so move along with them. Does not modify Trajectory.
 func OffsetPlan(plan Plan, offset spatialmath.Pose) Plan {
 	path := plan.Path()
 	if path == nil {
-		return NewSimplePlan(nil, plan.Trajectory())
+		return WithWaypointMetadata(NewSimplePlan(nil, plan.Trajectory()), PlanWaypointMetadata(plan))
 	}
 	newPath := make([]referenceframe.FrameSystemPoses, 0, len(path))
 	for _, step := range path {
 		newStep := make(referenceframe.FrameSystemPoses, len(step))
 		for frame, pose := range step {
+			if pose.Parent() != referenceframe.World {
+				newStep[frame] = pose
+				continue
+			}
 			newStep[frame] = referenceframe.NewPoseInFrame(pose.Parent(), spatialmath.Compose(offset, pose.Pose()))
 		}
 		newPath = append(newPath, newStep)
 	}
 	simplePlan := NewSimplePlan(newPath, plan.Trajectory())
+	simplePlan.metadata = PlanWaypointMetadata(plan)
 	if rrt, ok := plan.(*rrtPlan); ok {
 		return &rrtPlan{SimplePlan: *simplePlan, nodes: rrt.nodes}
 	}
@@ -71,18 +96,34 @@ func OffsetPlan(plan Plan, offset spatialmath.Pose) Plan {
 type Trajectory []referenceframe.FrameSystemInputs
 
 // GetFrameInputs is a helper function which will extract the waypoints of a single frame from the map output of a trajectory.
+// Steps which omit the frame, as steps of multi-frame plans may for frames which do not move, repeat its most recent inputs.
 func (traj Trajectory) GetFrameInputs(frameName string) ([][]referenceframe.Input, error) {
 	solution := make([][]referenceframe.Input, 0, len(traj))
 	for _, step := range traj {
 		frameStep, ok := step[frameName]
 		if !ok {
-			return nil, fmt.Errorf("frame named %s not found in trajectory", frameName)
+			if len(solution) == 0 {
+				return nil, fmt.Errorf("frame named %s not found in trajectory", frameName)
+			}
+			frameStep = solution[len(solution)-1]
 		}
 		solution = append(solution, frameStep)
 	}
 	return solution, nil
 }
 
+// stateAt returns the inputs of every frame of the trajectory as of the given step, carrying forward the most recent inputs of any
+// frames omitted from the step.
+func (traj Trajectory) stateAt(index int) referenceframe.FrameSystemInputs {
+	state := referenceframe.FrameSystemInputs{}
+	for _, step := range traj[:index+1] {
+		for frame, inputs := range step {
+			state[frame] = inputs
+		}
+	}
+	return state
+}
+
 // String returns a human-readable version of the trajectory, suitable for debugging.
 func (traj Trajectory) String() string {
 	var str string
@@ -158,18 +199,35 @@ func newPathFromRelativePath(path Path) (Path, error) {
 }
 
 // GetFramePoses returns a slice of poses a given frame should visit in the course of the Path.
+// Steps which omit the frame, as steps of multi-frame plans may for frames which do not move, repeat its most recent pose.
 func (path Path) GetFramePoses(frameName string) ([]spatialmath.Pose, error) {
 	poses := []spatialmath.Pose{}
 	for _, step := range path {
 		poseInFrame, ok := step[frameName]
 		if !ok {
-			return nil, fmt.Errorf("frame named %s not found in path", frameName)
+			if len(poses) == 0 {
+				return nil, fmt.Errorf("frame named %s not found in path", frameName)
+			}
+			poses = append(poses, poses[len(poses)-1])
+			continue
 		}
 		poses = append(poses, poseInFrame.Pose())
 	}
 	return poses, nil
 }
 
+// stateAt returns the poses of every frame of the path as of the given step, carrying forward the most recent poses of any frames
+// omitted from the step.
+func (path Path) stateAt(index int) referenceframe.FrameSystemPoses {
+	state := referenceframe.FrameSystemPoses{}
+	for _, step := range path[:index+1] {
+		for frame, pose := range step {
+			state[frame] = pose
+		}
+	}
+	return state
+}
+
 func (path Path) String() string {
 	var str string
 	for _, step := range path {
@@ -221,13 +279,15 @@ func NewGeoPlan(plan Plan, pt *geo.Point) Plan {
 		}
 		newPath = append(newPath, newStep)
 	}
-	return NewSimplePlan(newPath, plan.Trajectory())
+	return WithWaypointMetadata(NewSimplePlan(newPath, plan.Trajectory()), PlanWaypointMetadata(plan))
 }
 
 // SimplePlan is a struct containing a Path and a Trajectory, together these comprise a Plan.
 type SimplePlan struct {
 	path Path
 	traj Trajectory
+	// metadata of the waypoints of the plan, keyed by the index of the step at which each is reached
+	metadata map[int]*WaypointMetadata
 }
 
 // NewSimplePlan instantiates a new Plan from a Path and Trajectory.
@@ -344,6 +404,86 @@ func CalculateFrameErrorState(e ExecutionState, executionFrame, localizationFram
 	return spatialmath.PoseBetween(nominalPose, currentPose.Pose()), nil
 }
 
+// errorStateResolutionMM is the maximum distance between the points sampled along each remaining step of a Path when searching it for the
+// point nearest to the actual position of a frame.
+const errorStateResolutionMM = 10.
+
+// CalculateFrameErrorStateFromPath takes an ExecutionState and a Frame and calculates the error between the Frame's actual position and
+// the nearest point on the remainder of its Path, beginning with the step currently being executed. Unlike CalculateFrameErrorState,
+// which compares against the expected position along the current step, this does not misreport deviation when the Frame cuts a corner
+// between steps. As with CalculateFrameErrorState, the inputs of each step of the Trajectory are taken to describe motion relative to the
+// pose at the end of the prior step, as they do for kinematic bases. Such relative inputs may lie outside the limits of the Frame, which
+// place it absolutely, so they are not held to them.
+func CalculateFrameErrorStateFromPath(e ExecutionState, executionFrame, localizationFrame referenceframe.Frame) (spatialmath.Pose, error) {
+	currentPose, ok := e.CurrentPoses()[localizationFrame.Name()]
+	if !ok {
+		return nil, newFrameNotFoundError(localizationFrame.Name())
+	}
+	path := e.Plan().Path()
+	if path == nil {
+		return nil, errors.New("cannot calculate error state on a nil Path")
+	}
+	if len(path) == 0 {
+		return spatialmath.NewZeroPose(), nil
+	}
+	index := e.Index() - 1
+	if index < 0 || index >= len(path) {
+		return nil, fmt.Errorf("index %d out of bounds for Path of length %d", index, len(path))
+	}
+	traj := e.Plan().Trajectory()
+	if len(traj) != len(path) {
+		return nil, errors.New("plan trajectory and path should be the same length")
+	}
+
+	startPose, ok := path[index][executionFrame.Name()]
+	if !ok {
+		return nil, newFrameNotFoundError(executionFrame.Name())
+	}
+	if startPose.Parent() != currentPose.Parent() {
+		return nil, errors.New("cannot compose two PoseInFrames with different parents")
+	}
+	nearestPose := startPose.Pose()
+	nearestDist := nearestPose.Point().Distance(currentPose.Pose().Point())
+	zeroInputs := make([]referenceframe.Input, len(executionFrame.DoF()))
+	for i := index + 1; i < len(path); i++ {
+		stepInputs, ok := traj[i][executionFrame.Name()]
+		if !ok {
+			return nil, newFrameNotFoundError(executionFrame.Name())
+		}
+		stepPose, err := executionFrame.Transform(stepInputs)
+		if err != nil && !strings.Contains(err.Error(), referenceframe.OOBErrString) {
+			return nil, err
+		}
+		samples := math.Max(1, math.Ceil(stepPose.Point().Norm()/errorStateResolutionMM))
+		for j := 1.; j <= samples; j++ {
+			inputs, err := executionFrame.Interpolate(zeroInputs, stepInputs, j/samples)
+			if err != nil {
+				if !strings.Contains(err.Error(), referenceframe.OOBErrString) {
+					return nil, err
+				}
+				// steps are relative to the last, so need not be within the limits of the frame, such as those of the height of a
+				// flying base
+				inputs = make([]referenceframe.Input, len(stepInputs))
+				for k, input := range stepInputs {
+					inputs[k] = referenceframe.Input{Value: input.Value * j / samples}
+				}
+			}
+			poseInStep, err := executionFrame.Transform(inputs)
+			if err != nil && !strings.Contains(err.Error(), referenceframe.OOBErrString) {
+				return nil, err
+			}
+			pose := spatialmath.Compose(startPose.Pose(), poseInStep)
+			if dist := pose.Point().Distance(currentPose.Pose().Point()); dist < nearestDist {
+				nearestPose, nearestDist = pose, dist
+			}
+		}
+		if startPose, ok = path[i][executionFrame.Name()]; !ok {
+			return nil, newFrameNotFoundError(executionFrame.Name())
+		}
+	}
+	return spatialmath.PoseBetween(nearestPose, currentPose.Pose()), nil
+}
+
 // newFrameNotFoundError returns an error indicating that a given frame was not found in the given ExecutionState.
 func newFrameNotFoundError(frameName string) error {
 	return fmt.Errorf("could not find frame %s in ExecutionState", frameName)
@@ -354,6 +494,7 @@ func newFrameNotFoundError(frameName string) error {
 type PlanState struct {
 	poses         referenceframe.FrameSystemPoses
 	configuration referenceframe.FrameSystemInputs
+	metadata      *WaypointMetadata
 }
 
 // NewPlanState creates a PlanState from the given poses and configuration. Either or both may be nil.
@@ -398,6 +539,9 @@ func (p PlanState) Serialize() map[string]interface{} {
 	}
 	m["poses"] = poseMap
 	m["configuration"] = confMap
+	if p.metadata != nil {
+		m["metadata"] = p.metadata.serialize()
+	}
 	return m
 }
 
@@ -447,5 +591,88 @@ func DeserializePlanState(iface map[string]interface{}) (*PlanState, error) {
 			return nil, errors.New("could not decode contents of configuration")
 		}
 	}
+	if metadataIface, ok := iface["metadata"]; ok {
+		metadata, err := deserializeWaypointMetadata(metadataIface)
+		if err != nil {
+			return nil, fmt.Errorf("could not decode contents of metadata: %w", err)
+		}
+		ps.metadata = metadata
+	}
 	return ps, nil
 }
+
+// PlanFormatVersion is the version of the format in which MarshalPlan encodes plans. It is incremented whenever the format changes in a
+// way which older versions of UnmarshalPlan cannot read.
+const PlanFormatVersion = 1
+
+// serializedPlan is the JSON representation of a Plan.
+type serializedPlan struct {
+	Version    int                                `json:"version"`
+	Trajectory []map[string][]float64             `json:"trajectory"`
+	Path       []map[string]*commonpb.PoseInFrame `json:"path"`
+	Metadata   map[string]interface{}             `json:"metadata,omitempty"`
+	// Waypoints holds the metadata of the waypoints of the plan, keyed by the index of the step at which each is reached.
+	Waypoints map[int]*WaypointMetadata `json:"waypoint_metadata,omitempty"`
+}
+
+// MarshalPlan encodes the Trajectory and Path of a Plan, along with any metadata describing it, as versioned JSON which may be persisted
+// or transmitted to another process and decoded with UnmarshalPlan.
+func MarshalPlan(plan Plan, metadata map[string]interface{}) ([]byte, error) {
+	if plan == nil {
+		return nil, errors.New("cannot marshal nil plan")
+	}
+	sp := serializedPlan{
+		Version:    PlanFormatVersion,
+		Trajectory: make([]map[string][]float64, 0, len(plan.Trajectory())),
+		Path:       make([]map[string]*commonpb.PoseInFrame, 0, len(plan.Path())),
+		Metadata:   metadata,
+		Waypoints:  PlanWaypointMetadata(plan),
+	}
+	for _, step := range plan.Trajectory() {
+		stepFloats := make(map[string][]float64, len(step))
+		for fName, inputs := range step {
+			stepFloats[fName] = referenceframe.InputsToFloats(inputs)
+		}
+		sp.Trajectory = append(sp.Trajectory, stepFloats)
+	}
+	for _, step := range plan.Path() {
+		stepProto := make(map[string]*commonpb.PoseInFrame, len(step))
+		for fName, pif := range step {
+			stepProto[fName] = referenceframe.PoseInFrameToProtobuf(pif)
+		}
+		sp.Path = append(sp.Path, stepProto)
+	}
+	return json.Marshal(sp)
+}
+
+// UnmarshalPlan decodes a Plan and its metadata from JSON encoded by MarshalPlan. Plans encoded by a newer, unsupported version of the
+// format are rejected.
+func UnmarshalPlan(data []byte) (Plan, map[string]interface{}, error) {
+	var sp serializedPlan
+	if err := json.Unmarshal(data, &sp); err != nil {
+		return nil, nil, err
+	}
+	if sp.Version < 1 || sp.Version > PlanFormatVersion {
+		return nil, nil, fmt.Errorf("unsupported plan format version %d, must be between 1 and %d", sp.Version, PlanFormatVersion)
+	}
+	traj := make(Trajectory, 0, len(sp.Trajectory))
+	for _, stepFloats := range sp.Trajectory {
+		step := make(referenceframe.FrameSystemInputs, len(stepFloats))
+		for fName, floats := range stepFloats {
+			step[fName] = referenceframe.FloatsToInputs(floats)
+		}
+		traj = append(traj, step)
+	}
+	path := make(Path, 0, len(sp.Path))
+	for i, stepProto := range sp.Path {
+		step := make(referenceframe.FrameSystemPoses, len(stepProto))
+		for fName, pifProto := range stepProto {
+			if pifProto == nil {
+				return nil, nil, fmt.Errorf("path step %d is missing a pose for frame %s", i, fName)
+			}
+			step[fName] = referenceframe.ProtobufToPoseInFrame(pifProto)
+		}
+		path = append(path, step)
+	}
+	return WithWaypointMetadata(NewSimplePlan(path, traj), sp.Waypoints), sp.Metadata, nil
+}
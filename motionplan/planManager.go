@@ -36,6 +36,9 @@ const (
 type planManager struct {
 	*planner                // TODO: This should probably be removed
 	activeBackgroundWorkers sync.WaitGroup
+
+	treesMu sync.Mutex
+	trees   []*PlannerTree // search trees captured during planning if the capture_tree option is set
 }
 
 func newPlanManager(
@@ -322,6 +325,7 @@ func (pm *planManager) planParallelRRTMotion(
 	select {
 	case finalSteps := <-plannerChan:
 		// We didn't get a solution preview (possible error), so we get and process the full step set and error.
+		pm.captureTree(pathPlanner.opt(), finalSteps)
 
 		mapSeed := finalSteps.maps
 
@@ -524,6 +528,11 @@ func (pm *planManager) plannerSetupFromMoveRequest(
 		return nil, err
 	}
 
+	padding, err := collisionPaddings(constraints.GetCollisionPadding(), frameSystemGeometries)
+	if err != nil {
+		return nil, err
+	}
+
 	// add collision constraints
 	fsCollisionConstraints, stateCollisionConstraints, err := createAllCollisionConstraints(
 		movingRobotGeometries,
@@ -532,6 +541,7 @@ func (pm *planManager) plannerSetupFromMoveRequest(
 		boundingRegions,
 		allowedCollisions,
 		collisionBufferMM,
+		padding,
 	)
 	if err != nil {
 		return nil, err
@@ -544,6 +554,22 @@ func (pm *planManager) plannerSetupFromMoveRequest(
 		opt.AddStateFSConstraint(name, constraint)
 	}
 
+	// keep the moving geometries within the interaction spaces of the world state, if it has any
+	interactionSpaces, err := worldState.InteractionSpacesInWorldFrame(pm.fs, seedMap)
+	if err != nil {
+		return nil, err
+	}
+	if len(interactionSpaces.Geometries()) > 0 {
+		opt.AddStateConstraint(
+			defaultInteractionSpaceConstraintDesc,
+			NewInteractionSpaceConstraint(movingRobotGeometries, interactionSpaces.Geometries()),
+		)
+		opt.AddStateFSConstraint(
+			defaultInteractionSpaceConstraintDesc,
+			NewInteractionSpaceConstraintFS(movingRobotGeometries, interactionSpaces.Geometries()),
+		)
+	}
+
 	// error handling around extracting motion_profile information from map[string]interface{}
 	var motionProfile string
 	profile, ok := planningOpts["motion_profile"]
@@ -597,6 +623,18 @@ func (pm *planManager) plannerSetupFromMoveRequest(
 	if err != nil {
 		return nil, err
 	}
+	if err := opt.addLevelConstraints(pm.fs, constraints); err != nil {
+		return nil, err
+	}
+	if err := opt.addHitchConstraints(pm.fs, constraints); err != nil {
+		return nil, err
+	}
+	if err := opt.addSingularityConstraints(pm.fs, constraints); err != nil {
+		return nil, err
+	}
+	if err := opt.addTorqueConstraints(pm.fs, constraints); err != nil {
+		return nil, err
+	}
 	// convert map to json, then to a struct, overwriting present defaults
 	jsonString, err := json.Marshal(planningOpts)
 	if err != nil {
@@ -606,6 +644,9 @@ func (pm *planManager) plannerSetupFromMoveRequest(
 	if err != nil {
 		return nil, err
 	}
+	if err := opt.addJointLimitMargin(pm.fs, seedMap); err != nil {
+		return nil, err
+	}
 
 	alg, ok := planningOpts["planning_alg"]
 	if ok {
@@ -623,6 +664,20 @@ func (pm *planManager) plannerSetupFromMoveRequest(
 		}
 		planAlg = cbirrtName
 	}
+
+	// soft constraints are minimized alongside the cost of the plan, by every planner
+	for _, soft := range constraints.GetSoftConstraint() {
+		if opt.useTPspace {
+			// the configurations of PTG frames are relative to the start of each segment, so they cannot be costed as states
+			return nil, errors.New("soft constraints are not supported for PTG frames")
+		}
+		if soft.Cost == nil || soft.Weight < 0 {
+			return nil, fmt.Errorf("soft constraint %s must have a cost and a non-negative weight", soft.Name)
+		}
+		opt.AddSoftStateFSConstraint(soft.Name, soft.Cost, soft.Weight)
+	}
+	opt.scoreFunc = opt.withSoftCosts(opt.scoreFunc, pm.fs)
+
 	switch planAlg {
 	case cbirrtName:
 		opt.PlannerConstructor = newCBiRRTMotionPlanner
@@ -754,7 +809,7 @@ func (pm *planManager) generateWaypoints(request *PlanRequest, seedPlan Plan, wp
 	waypoints := []atomicWaypoint{}
 	for i := 1; i <= numSteps; i++ {
 		by := float64(i) / float64(numSteps)
-		to := &PlanState{referenceframe.FrameSystemPoses{}, referenceframe.FrameSystemInputs{}}
+		to := &PlanState{poses: referenceframe.FrameSystemPoses{}, configuration: referenceframe.FrameSystemInputs{}}
 		if wpGoals.poses != nil {
 			for frameName, pif := range wpGoals.poses {
 				toPose := spatialmath.Interpolate(startPoses[frameName].Pose(), pif.Pose(), by)
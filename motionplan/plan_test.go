@@ -213,3 +213,184 @@ func TestNewGeoPlan(t *testing.T) {
 		})
 	}
 }
+
+func TestMarshalPlan(t *testing.T) {
+	pose := spatialmath.NewPose(r3.Vector{X: 1, Y: 2, Z: 3}, &spatialmath.OrientationVectorDegrees{OZ: 1, Theta: 90})
+	plan := NewSimplePlan(
+		Path{
+			{"base": referenceframe.NewPoseInFrame(referenceframe.World, spatialmath.NewZeroPose())},
+			{"base": referenceframe.NewPoseInFrame(referenceframe.World, pose)},
+		},
+		Trajectory{
+			{"base": referenceframe.FloatsToInputs([]float64{0, 0, 0, 0})},
+			{"base": referenceframe.FloatsToInputs([]float64{1, 0.5, 0, 200})},
+		},
+	)
+
+	t.Run("round trip", func(t *testing.T) {
+		data, err := MarshalPlan(plan, map[string]interface{}{"component": "base"})
+		test.That(t, err, test.ShouldBeNil)
+		decoded, metadata, err := UnmarshalPlan(data)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, metadata, test.ShouldResemble, map[string]interface{}{"component": "base"})
+		test.That(t, decoded.Trajectory(), test.ShouldResemble, plan.Trajectory())
+		test.That(t, len(decoded.Path()), test.ShouldEqual, len(plan.Path()))
+		for i, step := range decoded.Path() {
+			test.That(t, step["base"].Parent(), test.ShouldEqual, referenceframe.World)
+			test.That(t, spatialmath.PoseAlmostEqual(step["base"].Pose(), plan.Path()[i]["base"].Pose()), test.ShouldBeTrue)
+		}
+	})
+
+	t.Run("nil plan", func(t *testing.T) {
+		_, err := MarshalPlan(nil, nil)
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("unsupported versions are rejected", func(t *testing.T) {
+		_, _, err := UnmarshalPlan([]byte(`{"version": 2, "trajectory": [], "path": []}`))
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "unsupported plan format version 2")
+
+		_, _, err = UnmarshalPlan([]byte(`{"trajectory": [], "path": []}`))
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
+
+func TestMultiFramePlan(t *testing.T) {
+	basePose := func(x float64) *referenceframe.PoseInFrame {
+		return referenceframe.NewPoseInFrame(referenceframe.World, spatialmath.NewPoseFromPoint(r3.Vector{X: x}))
+	}
+	gripperPose := referenceframe.NewPoseInFrame("arm", spatialmath.NewPoseFromPoint(r3.Vector{Z: 100}))
+	// the arm and gripper are omitted from the steps in which they do not move
+	plan := NewSimplePlan(
+		Path{
+			{"base": basePose(0), "arm": basePose(0), "gripper": gripperPose},
+			{"base": basePose(100)},
+			{"arm": basePose(150)},
+		},
+		Trajectory{
+			{"base": referenceframe.FloatsToInputs([]float64{0}), "arm": referenceframe.FloatsToInputs([]float64{0, 0})},
+			{"base": referenceframe.FloatsToInputs([]float64{100})},
+			{"arm": referenceframe.FloatsToInputs([]float64{1, 1})},
+		},
+	)
+
+	t.Run("GetFrameInputs and GetFramePoses carry forward omitted frames", func(t *testing.T) {
+		armInputs, err := plan.Trajectory().GetFrameInputs("arm")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, armInputs, test.ShouldResemble, [][]referenceframe.Input{{{0}, {0}}, {{0}, {0}}, {{1}, {1}}})
+		basePoses, err := plan.Path().GetFramePoses("base")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(basePoses), test.ShouldEqual, 3)
+		test.That(t, basePoses[2].Point().X, test.ShouldAlmostEqual, 100)
+
+		_, err = plan.Trajectory().GetFrameInputs("gripper")
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("RemainingPlan starts from the full state", func(t *testing.T) {
+		remaining, err := RemainingPlan(plan, 2)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(remaining.Trajectory()), test.ShouldEqual, 1)
+		test.That(t, remaining.Trajectory()[0], test.ShouldResemble, referenceframe.FrameSystemInputs{
+			"base": referenceframe.FloatsToInputs([]float64{100}),
+			"arm":  referenceframe.FloatsToInputs([]float64{1, 1}),
+		})
+		step := remaining.Path()[0]
+		test.That(t, len(step), test.ShouldEqual, 3)
+		test.That(t, step["base"].Pose().Point().X, test.ShouldAlmostEqual, 100)
+		test.That(t, step["arm"].Pose().Point().X, test.ShouldAlmostEqual, 150)
+
+		// the given plan is unmodified
+		test.That(t, len(plan.Trajectory()[2]), test.ShouldEqual, 1)
+
+		remaining, err = RemainingPlan(plan, 3)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, remaining.Trajectory(), test.ShouldBeEmpty)
+
+		// plans without a path remain without one
+		remaining, err = RemainingPlan(NewSimplePlan(nil, plan.Trajectory()), 2)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(remaining.Trajectory()), test.ShouldEqual, 1)
+		test.That(t, remaining.Path(), test.ShouldBeEmpty)
+		_, err = RemainingPlan(NewSimplePlan(nil, plan.Trajectory()), 4)
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("OffsetPlan only offsets poses in the world frame", func(t *testing.T) {
+		offset := OffsetPlan(plan, spatialmath.NewPoseFromPoint(r3.Vector{Y: 10}))
+		first := offset.Path()[0]
+		test.That(t, first["base"].Pose().Point().Y, test.ShouldAlmostEqual, 10)
+		test.That(t, first["arm"].Pose().Point().Y, test.ShouldAlmostEqual, 10)
+		test.That(t, first["gripper"], test.ShouldEqual, gripperPose)
+		test.That(t, offset.Trajectory(), test.ShouldResemble, plan.Trajectory())
+	})
+}
+
+func TestCalculateFrameErrorStateFromPath(t *testing.T) {
+	geometry, err := spatialmath.NewBox(spatialmath.NewZeroPose(), r3.Vector{X: 10, Y: 10, Z: 10}, "")
+	test.That(t, err, test.ShouldBeNil)
+	limits := []referenceframe.Limit{{Min: -5000, Max: 5000}, {Min: -5000, Max: 5000}}
+	model, err := referenceframe.New2DMobileModelFrame("base", limits, geometry)
+	test.That(t, err, test.ShouldBeNil)
+
+	poseAt := func(x, y float64) referenceframe.FrameSystemPoses {
+		return referenceframe.FrameSystemPoses{
+			"base": referenceframe.NewPoseInFrame(referenceframe.World, spatialmath.NewPoseFromPoint(r3.Vector{X: x, Y: y})),
+		}
+	}
+	inputs := func(x, y float64) referenceframe.FrameSystemInputs {
+		return referenceframe.FrameSystemInputs{"base": referenceframe.FloatsToInputs([]float64{x, y})}
+	}
+	// the path turns a corner at (1000, 0), with the inputs of each step relative to the end of the prior step
+	plan := NewSimplePlan(
+		Path{poseAt(0, 0), poseAt(1000, 0), poseAt(1000, 1000)},
+		Trajectory{inputs(0, 0), inputs(1000, 0), inputs(0, 1000)},
+	)
+	errorState := func(t *testing.T, index int, x, y float64) spatialmath.Pose {
+		t.Helper()
+		state, err := NewExecutionState(plan, index, inputs(500, 0), poseAt(x, y))
+		test.That(t, err, test.ShouldBeNil)
+		errorState, err := CalculateFrameErrorStateFromPath(state, model, model)
+		test.That(t, err, test.ShouldBeNil)
+		return errorState
+	}
+
+	t.Run("on the path", func(t *testing.T) {
+		test.That(t, errorState(t, 1, 300, 0).Point().Norm(), test.ShouldAlmostEqual, 0)
+	})
+
+	t.Run("cutting a corner measures from the nearest point on the path", func(t *testing.T) {
+		// the base is ahead of where the inputs of its current step would place it, and has begun to turn the corner early
+		test.That(t, errorState(t, 1, 950, 60).Point().Norm(), test.ShouldAlmostEqual, 50)
+
+		// the index based error state measures from the expected position along the current step
+		state, err := NewExecutionState(plan, 1, inputs(500, 0), poseAt(950, 60))
+		test.That(t, err, test.ShouldBeNil)
+		indexErrorState, err := CalculateFrameErrorState(state, model, model)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, indexErrorState.Point().Norm(), test.ShouldBeGreaterThan, 450)
+	})
+
+	t.Run("completed steps are not considered", func(t *testing.T) {
+		// the nearest point on the remaining path is the start of the second step, though the base is on the first step
+		test.That(t, errorState(t, 2, 300, 0).Point().Norm(), test.ShouldAlmostEqual, 700)
+	})
+
+	t.Run("relative inputs outside the limits of the frame", func(t *testing.T) {
+		narrow, err := referenceframe.New2DMobileModelFrame("base", []referenceframe.Limit{{Min: 0, Max: 500}, {Min: 0, Max: 500}}, geometry)
+		test.That(t, err, test.ShouldBeNil)
+		state, err := NewExecutionState(plan, 2, inputs(500, 0), poseAt(1050, 500))
+		test.That(t, err, test.ShouldBeNil)
+		errorState, err := CalculateFrameErrorStateFromPath(state, narrow, narrow)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, errorState.Point().Norm(), test.ShouldAlmostEqual, 50)
+	})
+
+	t.Run("out of bounds index", func(t *testing.T) {
+		state, err := NewExecutionState(plan, 0, inputs(0, 0), poseAt(0, 0))
+		test.That(t, err, test.ShouldBeNil)
+		_, err = CalculateFrameErrorStateFromPath(state, model, model)
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
@@ -3,10 +3,12 @@
 package motionplan
 
 import (
+	"fmt"
 	"math"
 	"runtime"
 
 	"go.viam.com/rdk/motionplan/ik"
+	"go.viam.com/rdk/motionplan/tpspace"
 	"go.viam.com/rdk/referenceframe"
 	"go.viam.com/rdk/spatialmath"
 )
@@ -55,13 +57,18 @@ const (
 	defaultRandomSeed = 0
 
 	// descriptions of constraints.
-	defaultLinearConstraintDesc         = "Constraint to follow linear path"
-	defaultPseudolinearConstraintDesc   = "Constraint to follow pseudolinear path, with tolerance scaled to path length"
-	defaultOrientationConstraintDesc    = "Constraint to maintain orientation within bounds"
-	defaultBoundingRegionConstraintDesc = "Constraint to maintain position within bounds"
-	defaultObstacleConstraintDesc       = "Collision between the robot and an obstacle"
-	defaultSelfCollisionConstraintDesc  = "Collision between two robot components that are moving"
-	defaultRobotCollisionConstraintDesc = "Collision between a robot component that is moving and one that is stationary"
+	defaultLinearConstraintDesc           = "Constraint to follow linear path"
+	defaultPseudolinearConstraintDesc     = "Constraint to follow pseudolinear path, with tolerance scaled to path length"
+	defaultOrientationConstraintDesc      = "Constraint to maintain orientation within bounds"
+	defaultLevelConstraintDesc            = "Constraint to keep frame level"
+	defaultSingularityConstraintDesc      = "Constraint to keep frame away from singularities"
+	defaultTorqueConstraintDesc           = "Constraint to keep joint loads within their limits"
+	defaultJointLimitMarginConstraintDesc = "Constraint to keep inputs away from their limits"
+	defaultBoundingRegionConstraintDesc   = "Constraint to maintain position within bounds"
+	defaultInteractionSpaceConstraintDesc = "Constraint to keep the robot within its interaction spaces"
+	defaultObstacleConstraintDesc         = "Collision between the robot and an obstacle"
+	defaultSelfCollisionConstraintDesc    = "Collision between two robot components that are moving"
+	defaultRobotCollisionConstraintDesc   = "Collision between a robot component that is moving and one that is stationary"
 
 	// When breaking down a path into smaller waypoints, add a waypoint every this many mm of movement.
 	defaultStepSizeMM = 10
@@ -172,6 +179,19 @@ type plannerOptions struct {
 	// Number of seeds to pre-generate for bidirectional position-only solving.
 	PositionSeeds int `json:"position_seeds"`
 
+	// If true, the search trees grown by RRT-based planners are captured so they may be inspected after planning.
+	CaptureTree bool `json:"capture_tree"`
+
+	// How far, in radians or mm, to keep the inputs of frames from the limits of their ranges.
+	JointLimitMargin float64 `json:"joint_limit_margin"`
+
+	// How far, as a percentage of the width of their ranges, to keep the inputs of frames from the limits of their ranges. If both
+	// margins are set, the larger is kept.
+	JointLimitMarginPercent float64 `json:"joint_limit_margin_percent"`
+
+	// Secondary objectives toward which the IK solutions of redundant frames are moved through their nullspace.
+	NullspaceObjectives *NullspaceObjectives `json:"nullspace_objectives"`
+
 	// poseDistanceFunc is the function that the planner will use to measure the degree of "closeness" between two poses
 	poseDistanceFunc ik.SegmentMetric
 
@@ -190,6 +210,9 @@ type plannerOptions struct {
 
 	useTPspace   bool
 	ptgFrameName string
+
+	// hitchConstraints keep the trailers towed by PTG frames from jackknifing, keyed by the name of the frame towing each
+	hitchConstraints map[string]ptgTransitionConstraint
 }
 
 // getGoalMetric creates the distance metric for the solver using the configured options.
@@ -339,6 +362,139 @@ func (p *plannerOptions) addOrientationConstraints(
 	return nil
 }
 
+// addLevelConstraints adds a constraint for each frame which must be kept level.
+func (p *plannerOptions) addLevelConstraints(fs referenceframe.FrameSystem, constraints *Constraints) error {
+	levelConstraints, err := createLevelConstraintsFS(fs, constraints)
+	if err != nil {
+		return err
+	}
+	for name, constraint := range levelConstraints {
+		p.AddStateFSConstraint(name, constraint)
+	}
+	return nil
+}
+
+// addSingularityConstraints adds a constraint for each frame which must be kept away from singularities, and steers the planner
+// away from them by how far each state falls short.
+func (p *plannerOptions) addSingularityConstraints(fs referenceframe.FrameSystem, constraints *Constraints) error {
+	for _, singularityConstraint := range constraints.GetSingularityConstraint() {
+		name := singularityConstraint.Frame
+		frame := fs.Frame(name)
+		if frame == nil {
+			return referenceframe.NewFrameMissingError(name)
+		}
+		if singularityConstraint.MinManipulability <= 0 {
+			return fmt.Errorf("singularity constraint for frame %s must have a positive minimum manipulability, got %f",
+				name, singularityConstraint.MinManipulability)
+		}
+		constraint, metric := NewSingularityConstraint(singularityConstraint.MinManipulability)
+		fsMetric := func(state *ik.StateFS) float64 {
+			return metric(&ik.State{Configuration: state.Configuration[name], Frame: frame})
+		}
+		p.AddStateFSConstraint(defaultSingularityConstraintDesc+" "+name, func(state *ik.StateFS) bool {
+			return constraint(&ik.State{Configuration: state.Configuration[name], Frame: frame})
+		})
+		p.pathMetric = ik.CombineFSMetrics(p.pathMetric, fsMetric)
+	}
+	return nil
+}
+
+// addTorqueConstraints adds a constraint for each frame whose joints must hold their loads within their limits.
+func (p *plannerOptions) addTorqueConstraints(fs referenceframe.FrameSystem, constraints *Constraints) error {
+	for _, torqueConstraint := range constraints.GetTorqueConstraint() {
+		name := torqueConstraint.Frame
+		frame := fs.Frame(name)
+		if frame == nil {
+			return referenceframe.NewFrameMissingError(name)
+		}
+		if torqueConstraint.PayloadKg < 0 || torqueConstraint.MaxJointAcceleration < 0 {
+			return fmt.Errorf("torque constraint for frame %s must not have a negative payload or acceleration, got %f and %f",
+				name, torqueConstraint.PayloadKg, torqueConstraint.MaxJointAcceleration)
+		}
+		model, ok := frame.(referenceframe.Model)
+		if !ok {
+			return fmt.Errorf("torque constraint for frame %s requires a model of its joints, got %T", name, frame)
+		}
+		// the loads on a model can only be estimated if its joints are understood
+		if _, err := referenceframe.ComputeJointLoads(model, make([]referenceframe.Input, len(model.DoF())), nil); err != nil {
+			return err
+		}
+		payload := &referenceframe.Payload{Mass: torqueConstraint.PayloadKg, CenterOfMass: torqueConstraint.PayloadCenterOfMass}
+		constraint := NewTorqueConstraint(payload, torqueConstraint.MaxJointAcceleration)
+		p.AddSegmentFSConstraint(defaultTorqueConstraintDesc+" "+name, func(segment *ik.SegmentFS) bool {
+			start, end := segment.StartConfiguration[name], segment.EndConfiguration[name]
+			if start == nil || end == nil {
+				return true
+			}
+			return constraint(&ik.Segment{StartConfiguration: start, EndConfiguration: end, Frame: frame})
+		})
+	}
+	return nil
+}
+
+// addJointLimitMargin adds a constraint which keeps the inputs of each frame within limits shrunk by the configured margins, so that
+// plans keep away from hard stops. An input which starts within the margin may stay where it is or move away from its limit, so that
+// motion may still begin from a state near its limits.
+func (p *plannerOptions) addJointLimitMargin(fs referenceframe.FrameSystem, start referenceframe.FrameSystemInputs) error {
+	if p.JointLimitMargin < 0 || p.JointLimitMarginPercent < 0 || p.JointLimitMarginPercent >= 50 {
+		return fmt.Errorf("joint_limit_margin must not be negative, and joint_limit_margin_percent must be in [0, 50), got %f and %f",
+			p.JointLimitMargin, p.JointLimitMarginPercent)
+	}
+	if p.JointLimitMargin == 0 && p.JointLimitMarginPercent == 0 {
+		return nil
+	}
+	limits := map[string][]referenceframe.Limit{}
+	for _, name := range fs.FrameNames() {
+		frame := fs.Frame(name)
+		if _, isPTGframe := frame.(tpspace.PTGProvider); isPTGframe || len(frame.DoF()) == 0 {
+			// the inputs of PTG frames select trajectories rather than positioning joints
+			continue
+		}
+		shrunk := make([]referenceframe.Limit, 0, len(frame.DoF()))
+		for i, limit := range frame.DoF() {
+			margin := p.JointLimitMargin
+			if span := limit.Max - limit.Min; !math.IsInf(span, 0) {
+				margin = math.Max(margin, span*p.JointLimitMarginPercent/100)
+			}
+			inner := referenceframe.Limit{Min: limit.Min + margin, Max: limit.Max - margin}
+			if inner.Min > inner.Max {
+				return fmt.Errorf("joint limit margin of %f leaves no range for input %d of frame %s", margin, i, name)
+			}
+			if i < len(start[name]) {
+				inner.Min = math.Min(inner.Min, start[name][i].Value)
+				inner.Max = math.Max(inner.Max, start[name][i].Value)
+			}
+			shrunk = append(shrunk, inner)
+		}
+		limits[name] = shrunk
+	}
+	p.AddStateFSConstraint(defaultJointLimitMarginConstraintDesc, func(state *ik.StateFS) bool {
+		for name, frameLimits := range limits {
+			inputs := state.Configuration[name]
+			for i := 0; i < len(inputs) && i < len(frameLimits); i++ {
+				if inputs[i].Value < frameLimits[i].Min || inputs[i].Value > frameLimits[i].Max {
+					return false
+				}
+			}
+		}
+		return true
+	})
+	return nil
+}
+
+// createLevelConstraintsFS returns the level constraints of the given constraints, named by the frame they keep level.
+func createLevelConstraintsFS(fs referenceframe.FrameSystem, constraints *Constraints) (map[string]StateFSConstraint, error) {
+	levelConstraints := map[string]StateFSConstraint{}
+	for _, levelConstraint := range constraints.GetLevelConstraint() {
+		constraint, err := CreateLevelConstraintFS(fs, levelConstraint.Frame, levelConstraint.ToleranceDegs)
+		if err != nil {
+			return nil, err
+		}
+		levelConstraints[defaultLevelConstraintDesc+" "+levelConstraint.Frame] = constraint
+	}
+	return levelConstraints, nil
+}
+
 func (p *plannerOptions) fillMotionChains(fs referenceframe.FrameSystem, to *PlanState) error {
 	motionChains := make([]*motionChain, 0, len(to.poses)+len(to.configuration))
 
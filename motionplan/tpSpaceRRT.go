@@ -587,6 +587,7 @@ func (mp *tpSpaceRRTMotionPlanner) getExtensionCandidate(
 // Check our constraints (mainly collision) and return a valid node to add, or nil if no nodes along the traj are valid.
 func (mp *tpSpaceRRTMotionPlanner) checkTraj(trajK []*tpspace.TrajNode, arcStartPose spatialmath.Pose) node {
 	passed := []node{}
+	hitchConstraint := mp.planOpts.hitchConstraints[mp.tpFrame.Name()]
 	// Check each point along the trajectory to confirm constraints are met
 	for i := 0; i < len(trajK); i++ {
 		trajPt := trajK[i]
@@ -595,6 +596,9 @@ func (mp *tpSpaceRRTMotionPlanner) checkTraj(trajK []*tpspace.TrajNode, arcStart
 
 		// In addition to checking every `Resolution`, we also check both endpoints.
 		ok, _ := mp.planOpts.CheckStateConstraints(trajState)
+		if ok && hitchConstraint != nil && i > 0 {
+			ok = hitchConstraint(trajK[i-1], trajPt)
+		}
 		if !ok {
 			okDist := trajPt.Dist * defaultCollisionWalkbackPct
 			if okDist > defaultMinTrajectoryLength {
@@ -149,6 +149,7 @@ func TestPtgWithObstacle(t *testing.T) {
 		worldGeometries.Geometries(),
 		nil, nil,
 		defaultCollisionBufferMM,
+		nil,
 	)
 	test.That(t, err, test.ShouldBeNil)
 
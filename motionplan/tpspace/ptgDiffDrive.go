@@ -65,3 +65,18 @@ func (ptg *ptgDiffDrive) Transform(inputs []referenceframe.Input) (spatialmath.P
 	pt := r3.Vector{0, dist - math.Abs(rdkutils.RadToDeg(alpha)), 0} // Straight line, +Y is "forwards"
 	return spatialmath.Compose(pose, spatialmath.NewPoseFromPoint(pt)), nil
 }
+
+// DiffDriveInputs returns the alpha and distance of the diff drive PTG trajectory which rotates in place by the given number of
+// degrees, positive being counterclockwise, and then moves straight ahead by the given number of millimeters.
+func DiffDriveInputs(turnDegrees, straightMM float64) (float64, float64, error) {
+	alpha := rdkutils.DegToRad(turnDegrees / angleAdjust)
+	if math.Abs(alpha) > math.Pi {
+		return 0, 0, fmt.Errorf(
+			"ptgDiffDrive cannot rotate in place by more than %.1f degrees, but %f was requested", 180*angleAdjust, turnDegrees,
+		)
+	}
+	if straightMM < 0 {
+		return 0, 0, fmt.Errorf("ptgDiffDrive cannot move backwards, but %f millimeters was requested", straightMM)
+	}
+	return alpha, math.Abs(rdkutils.RadToDeg(alpha)) + straightMM, nil
+}
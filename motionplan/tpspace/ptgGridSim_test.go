@@ -14,7 +14,7 @@ const (
 func TestSim(t *testing.T) {
 	simDist := 2500.
 	alphaCnt := uint(121)
-	for _, ptg := range defaultPTGs {
+	for _, ptg := range []ptgFactory{ptgFactories[PTGTypeCS], ptgFactories[PTGTypeSideSOverturn]} {
 		ptgGen := ptg(turnRadMeters)
 		test.That(t, ptgGen, test.ShouldNotBeNil)
 		grid, err := NewPTGGridSim(ptgGen, alphaCnt, simDist, false)
@@ -3,7 +3,6 @@
 package tpspace
 
 import (
-	"errors"
 	"fmt"
 	"math"
 	"strconv"
@@ -34,27 +33,12 @@ const (
 
 type ptgFactory func(float64) PTG
 
-// These PTGs do not end in a straight line, and thus are restricted to a shorter maximum length.
-var defaultShortPtgs = []ptgFactory{
-	NewCCPTG,
-	NewCCSPTG,
-}
-
-var defaultCorrectionPtg = NewCirclePTG
-
-// These PTGs curve at the beginning and then have a straight line of arbitrary length, which is allowed to extend to defaultRefDistLong.
-var defaultPTGs = []ptgFactory{
-	NewCSPTG,
-	NewSideSOverturnPTG,
-}
-
-var defaultDiffPTG ptgFactory = NewDiffDrivePTG
-
 type ptgGroupFrame struct {
 	name               string
 	limits             []referenceframe.Limit
 	geometries         []spatialmath.Geometry
 	solvers            []PTGSolver
+	library            *PTGLibrary
 	turnRadMillimeters float64
 	trajCount          int
 	correctionIdx      int
@@ -71,72 +55,75 @@ func NewPTGFrameFromKinematicOptions(
 	geoms []spatialmath.Geometry,
 	diffDriveOnly bool,
 	canRotateInPlace bool,
+) (referenceframe.Frame, error) {
+	library, err := DefaultPTGLibrary(turnRadMeters, diffDriveOnly, canRotateInPlace)
+	if err != nil {
+		return nil, err
+	}
+	return NewPTGFrameFromLibrary(name, logger, turnRadMeters, library, trajCount, geoms)
+}
+
+// NewPTGFrameFromLibrary will create a new Frame which is also a PTGProvider, precomputing the trajectories of each PTG of the library.
+// PTGs of the library without a turning radius of their own curve with the given turning radius of the base.
+func NewPTGFrameFromLibrary(
+	name string,
+	logger logging.Logger,
+	turnRadMeters float64,
+	library *PTGLibrary,
+	trajCount int,
+	geoms []spatialmath.Geometry,
 ) (referenceframe.Frame, error) {
 	if turnRadMeters <= 0 {
 		return nil, fmt.Errorf("cannot create ptg frame, turning radius %f must be >0", turnRadMeters)
 	}
-	if diffDriveOnly && !canRotateInPlace {
-		return nil, errors.New("if diffDriveOnly is used, canRotateInPlace must be true")
+	if err := library.Validate(); err != nil {
+		return nil, err
 	}
-
 	if trajCount <= 0 {
 		trajCount = defaultTrajCount
 	}
-
 	turnRadMillimeters := turnRadMeters * 1000
 
-	refDistLong := defaultRefDistLong
-	refDistShort := math.Max(
-		math.Min(turnRadMillimeters*math.Pi*defaultRefDistHalfCircles, refDistLong*0.1),
-		defaultRefDistShortMin,
-	)
-
-	pf := &ptgGroupFrame{name: name}
-
-	longPtgsToUse := []ptgFactory{}
-	shortPtgsToUse := []ptgFactory{}
-	if canRotateInPlace {
-		longPtgsToUse = append(longPtgsToUse, defaultDiffPTG)
-	}
-	if !diffDriveOnly {
-		longPtgsToUse = append(longPtgsToUse, defaultPTGs...)
-		shortPtgsToUse = append(shortPtgsToUse, defaultShortPtgs...)
-		// Use Circle PTG for course correction. Ensure it is last.
-		shortPtgsToUse = append(shortPtgsToUse, defaultCorrectionPtg)
-		pf.correctionIdx = len(longPtgsToUse) + (len(shortPtgsToUse) - 1)
-	} else {
-		// Use diff drive PTG for course correction
-		pf.correctionIdx = 0
-	}
-
-	longPtgs := initializePTGs(turnRadMillimeters, longPtgsToUse)
-	allSolvers, err := initializeSolvers(logger, refDistLong, refDistShort, trajCount, longPtgs)
-	if err != nil {
-		return nil, err
+	ptgs := make([]PTG, 0, len(library.PTGs))
+	maxDist := 0.
+	for _, cfg := range library.PTGs {
+		turnRadius := cfg.TurningRadiusMM
+		if turnRadius == 0 {
+			turnRadius = turnRadMillimeters
+		}
+		ptgs = append(ptgs, ptgFactories[cfg.Type](turnRadius))
+		maxDist = math.Max(maxDist, cfg.MaxDistanceMM)
 	}
-	shortPtgs := initializePTGs(turnRadMillimeters, shortPtgsToUse)
-	shortSolvers, err := initializeSolvers(logger, refDistShort, refDistShort, trajCount, shortPtgs)
+	solvers, err := initializeSolvers(logger, library.PTGs, trajCount, ptgs)
 	if err != nil {
 		return nil, err
 	}
-	allSolvers = append(allSolvers, shortSolvers...)
-
-	pf.solvers = allSolvers
-	pf.geometries = geoms
-	pf.turnRadMillimeters = turnRadMillimeters
-	pf.trajCount = trajCount
-	pf.logger = logger
 
+	pf := &ptgGroupFrame{
+		name:               name,
+		geometries:         geoms,
+		solvers:            solvers,
+		library:            library,
+		turnRadMillimeters: turnRadMillimeters,
+		trajCount:          trajCount,
+		correctionIdx:      library.CorrectionIndex,
+		logger:             logger,
+	}
 	pf.limits = []referenceframe.Limit{
 		{Min: 0, Max: float64(len(pf.solvers) - 1)},
 		{Min: -math.Pi, Max: math.Pi},
-		{Min: 0, Max: refDistLong},
-		{Min: 0, Max: refDistLong},
+		{Min: 0, Max: maxDist},
+		{Min: 0, Max: maxDist},
 	}
 
 	return pf, nil
 }
 
+// PTGLibrary returns the PTG library the frame was created from.
+func (pf *ptgGroupFrame) PTGLibrary() *PTGLibrary {
+	return pf.library
+}
+
 func (pf *ptgGroupFrame) CorrectionSolverIdx() int {
 	return pf.correctionIdx
 }
@@ -299,27 +286,19 @@ func (pf *ptgGroupFrame) validInputs(inputs []referenceframe.Input) error {
 	return errAll
 }
 
-func initializePTGs(turnRadius float64, constructors []ptgFactory) []PTG {
-	ptgs := []PTG{}
-	for _, ptg := range constructors {
-		ptgs = append(ptgs, ptg(turnRadius))
-	}
-	return ptgs
-}
-
 type solverAndError struct {
 	idx    int
 	solver PTGSolver
 	err    error
 }
 
-func initializeSolvers(logger logging.Logger, simDistFar, simDistRestricted float64, trajCount int, ptgs []PTG) ([]PTGSolver, error) {
+func initializeSolvers(logger logging.Logger, cfgs []PTGConfig, trajCount int, ptgs []PTG) ([]PTGSolver, error) {
 	solvers := make([]PTGSolver, len(ptgs))
 	solverChan := make(chan *solverAndError, len(ptgs))
 	for i := range ptgs {
 		j := i
 		utils.PanicCapturingGo(func() {
-			solver, err := NewPTGIK(ptgs[j], logger, simDistFar, simDistRestricted, j, trajCount)
+			solver, err := NewPTGIK(ptgs[j], logger, cfgs[j].MaxDistanceMM, cfgs[j].RestrictedDistanceMM, j, trajCount)
 			solverChan <- &solverAndError{j, solver, err}
 		})
 	}
@@ -246,3 +246,13 @@ func (ptg *ptgIK) ptgMetricIkFunc(distMetric ik.StateMetric) func([]float64) flo
 		return distMetric(&ik.State{Position: queryPose})
 	}
 }
+
+// RotatesInPlace returns whether the PTG solved by the solver is a diff drive PTG, which rotates in place before moving straight.
+func RotatesInPlace(solver PTGSolver) bool {
+	var ptg PTG = solver
+	if ik, ok := solver.(*ptgIK); ok {
+		ptg = ik.PTG
+	}
+	_, ok := ptg.(*ptgDiffDrive)
+	return ok
+}
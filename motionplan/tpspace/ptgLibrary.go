@@ -0,0 +1,157 @@
+//go:build !no_cgo
+
+package tpspace
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// The types of PTG which a PTG library may be made up of.
+const (
+	PTGTypeCS            = "cs"
+	PTGTypeSideSOverturn = "side_s_overturn"
+	PTGTypeSideS         = "side_s"
+	PTGTypeCC            = "cc"
+	PTGTypeCCS           = "ccs"
+	PTGTypeCircle        = "circle"
+	PTGTypeDiffDrive     = "diff_drive"
+)
+
+var ptgFactories = map[string]ptgFactory{
+	PTGTypeCS:            NewCSPTG,
+	PTGTypeSideSOverturn: NewSideSOverturnPTG,
+	PTGTypeSideS:         NewSideSPTG,
+	PTGTypeCC:            NewCCPTG,
+	PTGTypeCCS:           NewCCSPTG,
+	PTGTypeCircle:        NewCirclePTG,
+	PTGTypeDiffDrive:     NewDiffDrivePTG,
+}
+
+// PTGConfig describes one of the PTGs of a PTG library.
+type PTGConfig struct {
+	// Type is the type of the PTG, one of the PTGType constants.
+	Type string `json:"type"`
+	// TurningRadiusMM is the radius of the arcs the PTG curves along. If zero, the turning radius of the base is used.
+	TurningRadiusMM float64 `json:"turning_radius_mm,omitempty"`
+	// MaxDistanceMM is the longest the first leg of a trajectory of the PTG may be.
+	MaxDistanceMM float64 `json:"max_distance_mm"`
+	// RestrictedDistanceMM is the longest each later leg of a trajectory of the PTG may be, and how far its trajectories are
+	// precomputed to.
+	RestrictedDistanceMM float64 `json:"restricted_distance_mm"`
+}
+
+// PTGLibrary is the set of PTGs a PTG-based kinematic base plans with. Bases much larger or more nimble than those the default set
+// suits may be given their own, which may be inspected, tuned, and persisted as JSON.
+type PTGLibrary struct {
+	PTGs []PTGConfig `json:"ptgs"`
+	// CorrectionIndex is the index of the PTG used to correct the course of the base while it executes a plan.
+	CorrectionIndex int `json:"correction_index"`
+}
+
+// PTGLibraryProvider is a PTGProvider whose PTGs were created from a PTG library.
+type PTGLibraryProvider interface {
+	PTGProvider
+	PTGLibrary() *PTGLibrary
+}
+
+// DefaultPTGLibrary returns the PTG library used for a base with the given turning radius when it is not given its own. Bases which
+// are diffDriveOnly are given only the PTG which rotates in place, which they must be able to do.
+func DefaultPTGLibrary(turnRadMeters float64, diffDriveOnly, canRotateInPlace bool) (*PTGLibrary, error) {
+	if turnRadMeters <= 0 {
+		return nil, fmt.Errorf("cannot create ptg library, turning radius %f must be >0", turnRadMeters)
+	}
+	if diffDriveOnly && !canRotateInPlace {
+		return nil, errors.New("if diffDriveOnly is used, canRotateInPlace must be true")
+	}
+	turnRadMillimeters := turnRadMeters * 1000
+	refDistShort := math.Max(
+		math.Min(turnRadMillimeters*math.Pi*defaultRefDistHalfCircles, defaultRefDistLong*0.1),
+		defaultRefDistShortMin,
+	)
+	long := func(ptgType string) PTGConfig {
+		return PTGConfig{
+			Type: ptgType, TurningRadiusMM: turnRadMillimeters, MaxDistanceMM: defaultRefDistLong, RestrictedDistanceMM: refDistShort,
+		}
+	}
+	short := func(ptgType string) PTGConfig {
+		return PTGConfig{
+			Type: ptgType, TurningRadiusMM: turnRadMillimeters, MaxDistanceMM: refDistShort, RestrictedDistanceMM: refDistShort,
+		}
+	}
+
+	library := &PTGLibrary{}
+	if canRotateInPlace {
+		library.PTGs = append(library.PTGs, long(PTGTypeDiffDrive))
+	}
+	if diffDriveOnly {
+		// Use diff drive PTG for course correction
+		return library, nil
+	}
+	// These PTGs curve at the beginning and then have a straight line of arbitrary length
+	library.PTGs = append(library.PTGs, long(PTGTypeCS), long(PTGTypeSideSOverturn))
+	// These PTGs do not end in a straight line, and thus are restricted to a shorter maximum length
+	library.PTGs = append(library.PTGs, short(PTGTypeCC), short(PTGTypeCCS))
+	// Use Circle PTG for course correction. Ensure it is last.
+	library.PTGs = append(library.PTGs, short(PTGTypeCircle))
+	library.CorrectionIndex = len(library.PTGs) - 1
+	return library, nil
+}
+
+// Validate returns an error describing why the library cannot be planned with, if it cannot.
+func (l *PTGLibrary) Validate() error {
+	if len(l.PTGs) == 0 {
+		return errors.New("a ptg library must have at least one ptg")
+	}
+	for i, ptg := range l.PTGs {
+		if _, ok := ptgFactories[ptg.Type]; !ok {
+			types := make([]string, 0, len(ptgFactories))
+			for ptgType := range ptgFactories {
+				types = append(types, ptgType)
+			}
+			sort.Strings(types)
+			return fmt.Errorf("ptg %d has unknown type %q, must be one of %v", i, ptg.Type, types)
+		}
+		if ptg.TurningRadiusMM < 0 {
+			return fmt.Errorf("ptg %d may not have a negative turning radius", i)
+		}
+		if ptg.MaxDistanceMM <= 0 || ptg.RestrictedDistanceMM <= 0 {
+			return fmt.Errorf("the distances of ptg %d must be positive", i)
+		}
+	}
+	if l.CorrectionIndex < 0 || l.CorrectionIndex >= len(l.PTGs) {
+		return fmt.Errorf("correction index %d is not the index of one of the %d ptgs", l.CorrectionIndex, len(l.PTGs))
+	}
+	return nil
+}
+
+// ReadPTGLibraryFile reads a PTG library persisted as JSON at the given path.
+func ReadPTGLibraryFile(path string) (*PTGLibrary, error) {
+	//nolint:gosec
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	library := &PTGLibrary{}
+	if err := json.Unmarshal(data, library); err != nil {
+		return nil, errors.Wrapf(err, "could not parse ptg library at %s", path)
+	}
+	if err := library.Validate(); err != nil {
+		return nil, errors.Wrapf(err, "invalid ptg library at %s", path)
+	}
+	return library, nil
+}
+
+// WriteFile persists the library as JSON at the given path.
+func (l *PTGLibrary) WriteFile(path string) error {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
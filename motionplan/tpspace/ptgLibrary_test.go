@@ -0,0 +1,86 @@
+package tpspace
+
+import (
+	"path/filepath"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestDefaultPTGLibrary(t *testing.T) {
+	library, err := DefaultPTGLibrary(1., false, true)
+	test.That(t, err, test.ShouldBeNil)
+	types := []string{}
+	for _, ptg := range library.PTGs {
+		types = append(types, ptg.Type)
+		test.That(t, ptg.TurningRadiusMM, test.ShouldEqual, 1000.)
+	}
+	test.That(t, types, test.ShouldResemble, []string{
+		PTGTypeDiffDrive, PTGTypeCS, PTGTypeSideSOverturn, PTGTypeCC, PTGTypeCCS, PTGTypeCircle,
+	})
+	test.That(t, library.CorrectionIndex, test.ShouldEqual, len(library.PTGs)-1)
+	test.That(t, library.PTGs[1].MaxDistanceMM, test.ShouldEqual, defaultRefDistLong)
+	test.That(t, library.PTGs[3].MaxDistanceMM, test.ShouldBeLessThan, defaultRefDistLong)
+
+	library, err = DefaultPTGLibrary(1., true, true)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(library.PTGs), test.ShouldEqual, 1)
+	test.That(t, library.PTGs[0].Type, test.ShouldEqual, PTGTypeDiffDrive)
+	test.That(t, library.CorrectionIndex, test.ShouldEqual, 0)
+
+	_, err = DefaultPTGLibrary(1., true, false)
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = DefaultPTGLibrary(0, false, false)
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestPTGLibraryValidate(t *testing.T) {
+	valid := PTGConfig{Type: PTGTypeCircle, MaxDistanceMM: 1000, RestrictedDistanceMM: 500}
+	test.That(t, (&PTGLibrary{PTGs: []PTGConfig{valid}}).Validate(), test.ShouldBeNil)
+
+	unknown := valid
+	unknown.Type = "zigzag"
+	negativeRadius := valid
+	negativeRadius.TurningRadiusMM = -1
+	noDistance := valid
+	noDistance.RestrictedDistanceMM = 0
+	for _, library := range []*PTGLibrary{
+		{},
+		{PTGs: []PTGConfig{unknown}},
+		{PTGs: []PTGConfig{negativeRadius}},
+		{PTGs: []PTGConfig{noDistance}},
+		{PTGs: []PTGConfig{valid}, CorrectionIndex: 1},
+	} {
+		test.That(t, library.Validate(), test.ShouldNotBeNil)
+	}
+}
+
+func TestPTGLibraryFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ptgs.json")
+	_, err := ReadPTGLibraryFile(path)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	// a library tuned for a large base, with a wider circle used for course correction
+	library := &PTGLibrary{
+		PTGs: []PTGConfig{
+			{Type: PTGTypeCS, MaxDistanceMM: 5000, RestrictedDistanceMM: 1000},
+			{Type: PTGTypeCircle, TurningRadiusMM: 3000, MaxDistanceMM: 1000, RestrictedDistanceMM: 1000},
+		},
+		CorrectionIndex: 1,
+	}
+	test.That(t, library.WriteFile(path), test.ShouldBeNil)
+	read, err := ReadPTGLibraryFile(path)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, read, test.ShouldResemble, library)
+
+	frame, err := NewPTGFrameFromLibrary("", logging.NewTestLogger(t), 1., read, 2, nil)
+	test.That(t, err, test.ShouldBeNil)
+	provider, ok := frame.(PTGLibraryProvider)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, provider.PTGLibrary(), test.ShouldEqual, read)
+	test.That(t, len(provider.PTGSolvers()), test.ShouldEqual, 2)
+	test.That(t, frame.(PTGCourseCorrection).CorrectionSolverIdx(), test.ShouldEqual, 1)
+	test.That(t, frame.DoF()[2].Max, test.ShouldEqual, 5000.)
+}
@@ -4,6 +4,7 @@ import (
 	"math"
 	"testing"
 
+	"github.com/golang/geo/r3"
 	"go.viam.com/test"
 
 	"go.viam.com/rdk/logging"
@@ -33,6 +34,21 @@ func TestPtgDiffDrive(t *testing.T) {
 	test.That(t, spatialmath.PoseAlmostEqual(pose, goalPose), test.ShouldBeTrue)
 }
 
+func TestDiffDriveInputs(t *testing.T) {
+	p := NewDiffDrivePTG(0)
+	alpha, dist, err := DiffDriveInputs(-90, 500)
+	test.That(t, err, test.ShouldBeNil)
+	pose, err := p.Transform([]referenceframe.Input{{alpha}, {dist}})
+	test.That(t, err, test.ShouldBeNil)
+	goalPose := spatialmath.NewPose(r3.Vector{X: 500}, &spatialmath.OrientationVectorDegrees{OZ: 1, Theta: -90})
+	test.That(t, spatialmath.PoseAlmostEqual(pose, goalPose), test.ShouldBeTrue)
+
+	_, _, err = DiffDriveInputs(179, 0)
+	test.That(t, err, test.ShouldNotBeNil)
+	_, _, err = DiffDriveInputs(0, -1)
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
 func TestPtgTransform(t *testing.T) {
 	pFrame, err := NewPTGFrameFromKinematicOptions(
 		"",
@@ -0,0 +1,75 @@
+//go:build !no_cgo
+
+package motionplan
+
+import (
+	"go.viam.com/rdk/referenceframe"
+)
+
+// PlannerTree is a snapshot of the search trees grown by an RRT-based planner while solving a single waypoint of a motion. Trees are
+// only captured if the capture_tree planning option is set, and may be retrieved with PlanMotionWithTrees.
+type PlannerTree struct {
+	// StartTree holds the edges of the tree grown from the start of the waypoint, and GoalTree those of the tree grown from its goal.
+	StartTree []TreeEdge
+	GoalTree  []TreeEdge
+	// Err is the error with which the planner stopped growing the trees, or nil if it found a path.
+	Err error
+}
+
+// TreeNode is a single node of a PlannerTree.
+type TreeNode struct {
+	Configuration referenceframe.FrameSystemInputs
+	// Poses are only populated for nodes of planners which plan over poses, such as the TP-space planner.
+	Poses referenceframe.FrameSystemPoses
+	Cost  float64
+}
+
+// TreeEdge connects a node of a PlannerTree to its parent. The roots of a tree, which are seeded from IK solutions, have a nil Parent.
+type TreeEdge struct {
+	Child  TreeNode
+	Parent *TreeNode
+}
+
+// newPlannerTree copies the given rrt maps into a PlannerTree, such that it is unaffected by any later growth of the maps.
+func newPlannerTree(maps *rrtMaps, err error) *PlannerTree {
+	return &PlannerTree{
+		StartTree: treeEdges(maps.startMap),
+		GoalTree:  treeEdges(maps.goalMap),
+		Err:       err,
+	}
+}
+
+func treeEdges(rmap rrtMap) []TreeEdge {
+	edges := make([]TreeEdge, 0, len(rmap))
+	for child, parent := range rmap {
+		edge := TreeEdge{Child: newTreeNode(child)}
+		if parent != nil {
+			parentNode := newTreeNode(parent)
+			edge.Parent = &parentNode
+		}
+		edges = append(edges, edge)
+	}
+	return edges
+}
+
+func newTreeNode(n node) TreeNode {
+	return TreeNode{Configuration: n.Q(), Poses: n.Poses(), Cost: n.Cost()}
+}
+
+// captureTree records the trees of the given solution if the capture_tree option is set.
+func (pm *planManager) captureTree(opt *plannerOptions, solution *rrtSolution) {
+	if !opt.CaptureTree || solution == nil || solution.maps == nil {
+		return
+	}
+	tree := newPlannerTree(solution.maps, solution.err)
+	pm.treesMu.Lock()
+	defer pm.treesMu.Unlock()
+	pm.trees = append(pm.trees, tree)
+}
+
+// capturedTrees returns the trees captured so far by the plan manager.
+func (pm *planManager) capturedTrees() []*PlannerTree {
+	pm.treesMu.Lock()
+	defer pm.treesMu.Unlock()
+	return append([]*PlannerTree{}, pm.trees...)
+}
@@ -0,0 +1,140 @@
+//go:build !no_cgo
+
+package motionplan
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/motionplan/ik"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// ConstraintViolation is a configuration of a plan, or of the start of a plan request, which violates a constraint.
+type ConstraintViolation struct {
+	// Step is the index of the step of the trajectory which the violation is on the way to, or -1 for the start configuration.
+	Step int
+	// Constraint names the violated constraint.
+	Constraint string
+	// Configuration is the configuration which violates the constraint, which is nil for constraints on whole segments.
+	Configuration referenceframe.FrameSystemInputs
+}
+
+// CheckConstraints checks the start configuration of a plan request, and each segment of the trajectory of the plan if it is not nil,
+// against the constraints of the request, including collisions with its world state. The first violation found in each segment is
+// returned. Constraints relative to a goal, such as linear constraints, are checked against the last goal of the request. Plans of
+// PTG frames cannot be checked.
+//
+// The planner ignores collisions which are already present at the start of a plan, so collisions of the start configuration with the
+// obstacles of the world state are reported separately, once per colliding pair of geometries.
+func CheckConstraints(request *PlanRequest, plan Plan) ([]ConstraintViolation, error) {
+	if err := request.validatePlanRequest(); err != nil {
+		return nil, err
+	}
+	pm, err := newPlanManager(request.FrameSystem, request.Logger, defaultRandomSeed)
+	if err != nil {
+		return nil, err
+	}
+	start := request.StartState.configuration
+	opt, err := pm.plannerSetupFromMoveRequest(
+		request.StartState, request.Goals[len(request.Goals)-1], start, request.WorldState, request.BoundingRegions,
+		request.Constraints, request.Options,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if opt.useTPspace {
+		return nil, errors.New("constraints cannot be checked for plans of PTG frames")
+	}
+
+	violations, err := startCollisions(request, pm.fs)
+	if err != nil {
+		return nil, err
+	}
+	if ok, name := opt.CheckStateFSConstraints(&ik.StateFS{Configuration: start, FS: pm.fs}); !ok {
+		violations = append(violations, ConstraintViolation{Step: -1, Constraint: constraintDescription(name), Configuration: start})
+	}
+	if plan == nil {
+		return violations, nil
+	}
+	traj := plan.Trajectory()
+	for i := 1; i < len(traj); i++ {
+		segment := &ik.SegmentFS{StartConfiguration: traj[i-1], EndConfiguration: traj[i], FS: pm.fs}
+		if ok, name := opt.CheckSegmentFSConstraints(segment); !ok {
+			violations = append(violations, ConstraintViolation{Step: i, Constraint: constraintDescription(name)})
+			continue
+		}
+		interpolated, err := interpolateSegmentFS(segment, opt.Resolution)
+		if err != nil {
+			return nil, err
+		}
+		for _, configuration := range interpolated {
+			if ok, name := opt.CheckStateFSConstraints(&ik.StateFS{Configuration: configuration, FS: pm.fs}); !ok {
+				violations = append(violations, ConstraintViolation{Step: i, Constraint: constraintDescription(name), Configuration: configuration})
+				break
+			}
+		}
+	}
+	return violations, nil
+}
+
+// constraintDescription strips the address of the constraint function which the constraint handler appends to the names of
+// constraints to tell apart those with the same description.
+func constraintDescription(name string) string {
+	if i := strings.LastIndex(name, "_0x"); i >= 0 {
+		return name[:i]
+	}
+	return name
+}
+
+// startCollisions returns a violation for each collision of the start configuration of a plan request with the obstacles of its world
+// state which is not allowed by the collision specifications of the request.
+func startCollisions(request *PlanRequest, fs referenceframe.FrameSystem) ([]ConstraintViolation, error) {
+	start := request.StartState.configuration
+	frameSystemGeometries, err := referenceframe.FrameSystemGeometries(fs, start)
+	if err != nil {
+		return nil, err
+	}
+	robotGeometries := []spatialmath.Geometry{}
+	for _, geometries := range frameSystemGeometries {
+		robotGeometries = append(robotGeometries, geometries.Geometries()...)
+	}
+	worldGeometries, err := request.WorldState.ObstaclesInWorldFrame(fs, start)
+	if err != nil {
+		return nil, err
+	}
+	allowedCollisions, err := collisionSpecifications(
+		request.Constraints.GetCollisionSpecification(), frameSystemGeometries, request.WorldState,
+	)
+	if err != nil {
+		return nil, err
+	}
+	collisionBufferMM := defaultCollisionBufferMM
+	if raw, ok := request.Options["collision_buffer_mm"].(float64); ok {
+		collisionBufferMM = raw
+	}
+	padding, err := collisionPaddings(request.Constraints.GetCollisionPadding(), frameSystemGeometries)
+	if err != nil {
+		return nil, err
+	}
+	allowedCG, err := setupZeroCG(nil, nil, allowedCollisions, collisionBufferMM, padding)
+	if err != nil {
+		return nil, err
+	}
+	cg, err := newCollisionGraph(robotGeometries, worldGeometries.Geometries(), allowedCG, true, collisionBufferMM, padding)
+	if err != nil {
+		return nil, err
+	}
+	violations := []ConstraintViolation{}
+	for _, collision := range cg.collisions(collisionBufferMM) {
+		violations = append(violations, ConstraintViolation{
+			Step:          -1,
+			Constraint:    fmt.Sprintf("%s: %s and %s", defaultObstacleConstraintDesc, collision.name1, collision.name2),
+			Configuration: start,
+		})
+	}
+	return violations, nil
+}
@@ -0,0 +1,103 @@
+package motionplan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+	frame "go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+func TestCheckConstraints(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	fs := frame.NewEmptyFrameSystem("test")
+	modelXarm, err := frame.ParseModelJSONFile(utils.ResolveFile("components/arm/example_kinematics/xarm6_kinematics_test.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(modelXarm, fs.World()), test.ShouldBeNil)
+
+	goal := spatialmath.NewPoseFromPoint(r3.Vector{X: 407, Y: 0, Z: 112})
+	goals := []*PlanState{{poses: frame.FrameSystemPoses{"xArm6": frame.NewPoseInFrame(frame.World, goal)}}}
+	plan, err := PlanMotion(context.Background(), &PlanRequest{
+		Logger:      logger,
+		Goals:       goals,
+		StartState:  &PlanState{configuration: frame.NewZeroInputs(fs)},
+		FrameSystem: fs,
+	})
+	test.That(t, err, test.ShouldBeNil)
+	traj := plan.Trajectory()
+
+	obstacle, err := spatialmath.NewBox(goal, r3.Vector{10, 10, 1}, "obstacle")
+	test.That(t, err, test.ShouldBeNil)
+	worldState, err := frame.NewWorldState(
+		[]*frame.GeometriesInFrame{frame.NewGeometriesInFrame(frame.World, []spatialmath.Geometry{obstacle})},
+		nil,
+	)
+	test.That(t, err, test.ShouldBeNil)
+
+	t.Run("plan without obstacles", func(t *testing.T) {
+		violations, err := CheckConstraints(&PlanRequest{
+			Logger:      logger,
+			Goals:       goals,
+			StartState:  &PlanState{configuration: traj[0]},
+			FrameSystem: fs,
+		}, plan)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, violations, test.ShouldBeEmpty)
+	})
+	t.Run("plan into an obstacle", func(t *testing.T) {
+		violations, err := CheckConstraints(&PlanRequest{
+			Logger:      logger,
+			Goals:       goals,
+			StartState:  &PlanState{configuration: traj[0]},
+			FrameSystem: fs,
+			WorldState:  worldState,
+		}, plan)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(violations), test.ShouldBeGreaterThan, 0)
+		last := violations[len(violations)-1]
+		test.That(t, last.Step, test.ShouldEqual, len(traj)-1)
+		test.That(t, last.Constraint, test.ShouldEqual, defaultObstacleConstraintDesc)
+		test.That(t, last.Configuration, test.ShouldNotBeNil)
+	})
+	t.Run("start in an obstacle", func(t *testing.T) {
+		violations, err := CheckConstraints(&PlanRequest{
+			Logger:      logger,
+			Goals:       goals,
+			StartState:  &PlanState{configuration: traj[len(traj)-1]},
+			FrameSystem: fs,
+			WorldState:  worldState,
+		}, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(violations), test.ShouldBeGreaterThan, 0)
+		for _, violation := range violations {
+			test.That(t, violation.Step, test.ShouldEqual, -1)
+			test.That(t, violation.Constraint, test.ShouldContainSubstring, "obstacle")
+		}
+	})
+	t.Run("start outside the interaction spaces", func(t *testing.T) {
+		farAway, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(r3.Vector{X: 5000}), r3.Vector{100, 100, 100}, "far_away")
+		test.That(t, err, test.ShouldBeNil)
+		keepIn, err := frame.NewWorldStateWithInteractionSpaces(
+			nil,
+			[]*frame.GeometriesInFrame{frame.NewGeometriesInFrame(frame.World, []spatialmath.Geometry{farAway})},
+			nil,
+		)
+		test.That(t, err, test.ShouldBeNil)
+		violations, err := CheckConstraints(&PlanRequest{
+			Logger:      logger,
+			Goals:       goals,
+			StartState:  &PlanState{configuration: traj[0]},
+			FrameSystem: fs,
+			WorldState:  keepIn,
+		}, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(violations), test.ShouldEqual, 1)
+		test.That(t, violations[0].Step, test.ShouldEqual, -1)
+		test.That(t, violations[0].Constraint, test.ShouldEqual, defaultInteractionSpaceConstraintDesc)
+	})
+}
@@ -0,0 +1,125 @@
+package motionplan
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// WaypointMetadata describes how a plan is to be executed as it arrives at one of its waypoints, such as slowing down through a
+// doorway or pausing at an inspection point.
+type WaypointMetadata struct {
+	// MaxSpeedMMPerSec, if positive, is the speed the moving frame may not exceed while travelling to the waypoint.
+	MaxSpeedMMPerSec float64 `json:"max_speed_mm_per_sec,omitempty"`
+	// ToleranceMM, if positive, is how far from the waypoint the moving frame may end up and still be considered to have arrived.
+	ToleranceMM float64 `json:"tolerance_mm,omitempty"`
+	// DwellSeconds is how long execution pauses once the waypoint is reached.
+	DwellSeconds float64 `json:"dwell_s,omitempty"`
+	// Action names a hook to run once the waypoint is reached, before execution continues.
+	Action string `json:"action,omitempty"`
+}
+
+// Validate returns an error if any of the metadata is negative.
+func (md *WaypointMetadata) Validate() error {
+	if md.MaxSpeedMMPerSec < 0 || md.ToleranceMM < 0 || md.DwellSeconds < 0 {
+		return errors.New("waypoint metadata may not be negative")
+	}
+	return nil
+}
+
+// Dwell returns how long execution pauses once the waypoint is reached.
+func (md *WaypointMetadata) Dwell() time.Duration {
+	return time.Duration(md.DwellSeconds * float64(time.Second))
+}
+
+// Metadata returns the metadata describing how to execute a plan as it arrives at the PlanState, which may be nil.
+func (p *PlanState) Metadata() *WaypointMetadata {
+	return p.metadata
+}
+
+// WithMetadata returns a copy of the PlanState carrying the given metadata.
+func (p *PlanState) WithMetadata(metadata *WaypointMetadata) *PlanState {
+	return &PlanState{poses: p.poses, configuration: p.configuration, metadata: metadata}
+}
+
+// serialize turns the metadata into a map[string]interface{} suitable for being transmitted over proto.
+func (md *WaypointMetadata) serialize() map[string]interface{} {
+	m := map[string]interface{}{}
+	if md.MaxSpeedMMPerSec != 0 {
+		m["max_speed_mm_per_sec"] = md.MaxSpeedMMPerSec
+	}
+	if md.ToleranceMM != 0 {
+		m["tolerance_mm"] = md.ToleranceMM
+	}
+	if md.DwellSeconds != 0 {
+		m["dwell_s"] = md.DwellSeconds
+	}
+	if md.Action != "" {
+		m["action"] = md.Action
+	}
+	return m
+}
+
+func deserializeWaypointMetadata(iface interface{}) (*WaypointMetadata, error) {
+	data, err := json.Marshal(iface)
+	if err != nil {
+		return nil, err
+	}
+	metadata := &WaypointMetadata{}
+	if err := json.Unmarshal(data, metadata); err != nil {
+		return nil, err
+	}
+	if err := metadata.Validate(); err != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
+
+// WaypointMetadata returns the metadata of the waypoints of the plan, keyed by the index of the step of its Trajectory at which each
+// is reached. It is nil if no waypoint carries metadata.
+func (plan *SimplePlan) WaypointMetadata() map[int]*WaypointMetadata {
+	return plan.metadata
+}
+
+// PlanWaypointMetadata returns the metadata of the waypoints of the plan, keyed by the index of the step of its Trajectory at which
+// each is reached, or nil if the plan carries none.
+func PlanWaypointMetadata(plan Plan) map[int]*WaypointMetadata {
+	if p, ok := plan.(interface {
+		WaypointMetadata() map[int]*WaypointMetadata
+	}); ok {
+		return p.WaypointMetadata()
+	}
+	return nil
+}
+
+// WithWaypointMetadata returns a copy of the plan whose waypoints carry the given metadata, keyed by the index of the step of its
+// Trajectory at which each is reached.
+func WithWaypointMetadata(plan Plan, metadata map[int]*WaypointMetadata) Plan {
+	if len(metadata) == 0 {
+		metadata = nil
+	}
+	if rrt, ok := plan.(*rrtPlan); ok {
+		withMetadata := *rrt
+		withMetadata.metadata = metadata
+		return &withMetadata
+	}
+	simplePlan := NewSimplePlan(plan.Path(), plan.Trajectory())
+	simplePlan.metadata = metadata
+	return simplePlan
+}
+
+// remainingWaypointMetadata returns the metadata of the waypoints reached from the given step onwards, keyed by their steps relative
+// to it.
+func remainingWaypointMetadata(metadata map[int]*WaypointMetadata, waypointIndex int) map[int]*WaypointMetadata {
+	var remaining map[int]*WaypointMetadata
+	for step, md := range metadata {
+		if step < waypointIndex {
+			continue
+		}
+		if remaining == nil {
+			remaining = map[int]*WaypointMetadata{}
+		}
+		remaining[step-waypointIndex] = md
+	}
+	return remaining
+}
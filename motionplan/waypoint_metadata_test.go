@@ -0,0 +1,70 @@
+package motionplan
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+func TestWaypointMetadata(t *testing.T) {
+	md := &WaypointMetadata{MaxSpeedMMPerSec: 50, ToleranceMM: 5, DwellSeconds: 1.5, Action: "photograph"}
+	test.That(t, md.Validate(), test.ShouldBeNil)
+	test.That(t, md.Dwell(), test.ShouldEqual, 1500*time.Millisecond)
+	test.That(t, (&WaypointMetadata{ToleranceMM: -1}).Validate(), test.ShouldNotBeNil)
+
+	t.Run("plan states carry metadata through serialization", func(t *testing.T) {
+		goal := referenceframe.FrameSystemPoses{
+			"arm": referenceframe.NewPoseInFrame(referenceframe.World, spatialmath.NewPoseFromPoint(r3.Vector{X: 100})),
+		}
+		state := NewPlanState(goal, nil).WithMetadata(md)
+		test.That(t, state.Metadata(), test.ShouldEqual, md)
+		test.That(t, state.Poses(), test.ShouldResemble, goal)
+
+		// waypoints arrive in the extra of requests as JSON
+		data, err := json.Marshal(state.Serialize())
+		test.That(t, err, test.ShouldBeNil)
+		serialized := map[string]interface{}{}
+		test.That(t, json.Unmarshal(data, &serialized), test.ShouldBeNil)
+		deserialized, err := DeserializePlanState(serialized)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, deserialized.Metadata(), test.ShouldResemble, md)
+
+		deserialized, err = DeserializePlanState(NewPlanState(goal, nil).Serialize())
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, deserialized.Metadata(), test.ShouldBeNil)
+
+		_, err = DeserializePlanState(map[string]interface{}{"metadata": map[string]interface{}{"dwell_s": -1.}})
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	plan := WithWaypointMetadata(NewSimplePlan(nil, Trajectory{
+		{"arm": referenceframe.FloatsToInputs([]float64{0})},
+		{"arm": referenceframe.FloatsToInputs([]float64{1})},
+		{"arm": referenceframe.FloatsToInputs([]float64{2})},
+		{"arm": referenceframe.FloatsToInputs([]float64{3})},
+	}), map[int]*WaypointMetadata{1: {Action: "photograph"}, 3: md})
+
+	t.Run("remaining plans keep the metadata of the waypoints still to be reached", func(t *testing.T) {
+		remaining, err := RemainingPlan(plan, 2)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, PlanWaypointMetadata(remaining), test.ShouldResemble, map[int]*WaypointMetadata{1: md})
+
+		remaining, err = RemainingPlan(plan, 1)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, PlanWaypointMetadata(remaining), test.ShouldResemble, map[int]*WaypointMetadata{0: {Action: "photograph"}, 2: md})
+	})
+
+	t.Run("marshalled plans keep their metadata", func(t *testing.T) {
+		data, err := MarshalPlan(plan, nil)
+		test.That(t, err, test.ShouldBeNil)
+		decoded, _, err := UnmarshalPlan(data)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, PlanWaypointMetadata(decoded), test.ShouldResemble, PlanWaypointMetadata(plan))
+	})
+}
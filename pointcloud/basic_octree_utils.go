@@ -210,3 +210,48 @@ func getMaxSideLengthFromPcMetaData(meta MetaData) float64 {
 func pointsAlmostEqualEpsilon(v, ov r3.Vector, epsilon float64) bool {
 	return math.Abs(v.X-ov.X) < epsilon && math.Abs(v.Y-ov.Y) < epsilon && math.Abs(v.Z-ov.Z) < epsilon
 }
+
+// growToward doubles the side length of a basic octree, moving its center towards the given point, so that its existing contents
+// become one of the octants of the new octree. This does not touch any of the stored points.
+func (octree *BasicOctree) growToward(p r3.Vector) {
+	if octree.node.nodeType == leafNodeEmpty {
+		octree.center = p
+		return
+	}
+	direction := r3.Vector{X: 1, Y: 1, Z: 1}
+	if p.X < octree.center.X {
+		direction.X = -1
+	}
+	if p.Y < octree.center.Y {
+		direction.Y = -1
+	}
+	if p.Z < octree.center.Z {
+		direction.Z = -1
+	}
+	old := *octree
+	old.label = ""
+	newCenter := octree.center.Add(direction.Mul(octree.sideLength / 2))
+
+	// the octants are ordered as in splitIntoOctants, and the one opposite the direction of growth holds the existing contents
+	children := []*BasicOctree{}
+	for _, i := range []float64{-1.0, 1.0} {
+		for _, j := range []float64{-1.0, 1.0} {
+			for _, k := range []float64{-1.0, 1.0} {
+				if i == -direction.X && j == -direction.Y && k == -direction.Z {
+					children = append(children, &old)
+					continue
+				}
+				children = append(children, &BasicOctree{
+					center:     newCenter.Add(r3.Vector{X: i, Y: j, Z: k}.Mul(octree.sideLength / 2)),
+					sideLength: octree.sideLength,
+					node:       newLeafNodeEmpty(),
+					meta:       NewMetaData(),
+				})
+			}
+		}
+	}
+	octree.node = newInternalNode(children)
+	octree.node.maxVal = old.node.maxVal
+	octree.center = newCenter
+	octree.sideLength *= 2
+}
@@ -0,0 +1,156 @@
+package pointcloud
+
+import (
+	"math"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	"gonum.org/v1/gonum/mat"
+
+	"go.viam.com/rdk/spatialmath"
+)
+
+// ICPConfig configures the iterative closest point alignment of one point cloud to another.
+type ICPConfig struct {
+	// MaxIterations bounds the number of alignment steps taken.
+	MaxIterations int
+	// MaxCorrespondenceDistance is the furthest a point may be from its nearest neighbor in the target and still be used to align
+	// the clouds, so that the parts of the clouds which do not overlap are ignored.
+	MaxCorrespondenceDistance float64
+	// ConvergenceThreshold is the distance moved by the furthest moving point below which a step is considered to have converged.
+	ConvergenceThreshold float64
+}
+
+// ICPResult is the outcome of aligning one point cloud to another.
+type ICPResult struct {
+	// Transform is the pose which, composed with the points of the source cloud, moves them onto the target cloud.
+	Transform spatialmath.Pose
+	// Iterations is the number of alignment steps taken.
+	Iterations int
+	// Converged is whether the alignment converged before running out of iterations.
+	Converged bool
+	// RMSError is the root mean square distance between the aligned source points and their nearest neighbors in the target.
+	RMSError float64
+	// Fitness is the fraction of the source points which had a correspondence in the target at the end of the alignment.
+	Fitness float64
+}
+
+// RegisterPointCloudICP finds the transform aligning source to target with point to point iterative closest point, starting from
+// the given guess. The guess must be close enough to the true alignment for most points to find their true neighbors within the
+// maximum correspondence distance.
+func RegisterPointCloudICP(source PointCloud, target *KDTree, guess spatialmath.Pose, cfg ICPConfig) (ICPResult, error) {
+	if source.Size() == 0 || target.Size() == 0 {
+		return ICPResult{}, errors.New("cannot align empty point clouds")
+	}
+	if cfg.MaxIterations <= 0 {
+		return ICPResult{}, errors.Errorf("max iterations must be positive, got %d", cfg.MaxIterations)
+	}
+	if cfg.MaxCorrespondenceDistance <= 0 {
+		return ICPResult{}, errors.Errorf("max correspondence distance must be positive, got %.2f", cfg.MaxCorrespondenceDistance)
+	}
+	if guess == nil {
+		guess = spatialmath.NewZeroPose()
+	}
+	points := make([]r3.Vector, 0, source.Size())
+	source.Iterate(0, 0, func(p r3.Vector, d Data) bool {
+		points = append(points, p)
+		return true
+	})
+
+	result := ICPResult{Transform: guess}
+	for result.Iterations < cfg.MaxIterations {
+		result.Iterations++
+		srcMatched, tgtMatched := correspondences(points, target, result.Transform, cfg.MaxCorrespondenceDistance)
+		if len(srcMatched) < 3 {
+			return ICPResult{}, errors.Errorf("only %d points have correspondences, which is too few to align the clouds", len(srcMatched))
+		}
+		step, err := bestFitTransform(srcMatched, tgtMatched)
+		if err != nil {
+			return ICPResult{}, err
+		}
+		// how far the furthest point moved tells whether another step is worthwhile
+		moved := 0.
+		for _, p := range srcMatched {
+			moved = math.Max(moved, spatialmath.Compose(step, spatialmath.NewPoseFromPoint(p)).Point().Distance(p))
+		}
+		result.Transform = spatialmath.Compose(step, result.Transform)
+		if moved < cfg.ConvergenceThreshold {
+			result.Converged = true
+			break
+		}
+	}
+
+	srcMatched, tgtMatched := correspondences(points, target, result.Transform, cfg.MaxCorrespondenceDistance)
+	sumSq := 0.
+	for i := range srcMatched {
+		sumSq += srcMatched[i].Sub(tgtMatched[i]).Norm2()
+	}
+	if len(srcMatched) > 0 {
+		result.RMSError = math.Sqrt(sumSq / float64(len(srcMatched)))
+	}
+	result.Fitness = float64(len(srcMatched)) / float64(len(points))
+	return result, nil
+}
+
+// correspondences returns the source points moved by the transform which have a neighbor in the target within maxDist, along with
+// those neighbors.
+func correspondences(points []r3.Vector, target *KDTree, transform spatialmath.Pose, maxDist float64) ([]r3.Vector, []r3.Vector) {
+	var srcMatched, tgtMatched []r3.Vector
+	for _, p := range points {
+		moved := spatialmath.Compose(transform, spatialmath.NewPoseFromPoint(p)).Point()
+		nearest, _, dist, ok := target.NearestNeighbor(moved)
+		if !ok || dist > maxDist {
+			continue
+		}
+		srcMatched = append(srcMatched, moved)
+		tgtMatched = append(tgtMatched, nearest)
+	}
+	return srcMatched, tgtMatched
+}
+
+// bestFitTransform returns the rigid transform minimizing the squared distances between the paired points, using the SVD of their
+// cross covariance (the Kabsch algorithm).
+func bestFitTransform(src, tgt []r3.Vector) (spatialmath.Pose, error) {
+	var srcCentroid, tgtCentroid r3.Vector
+	for i := range src {
+		srcCentroid = srcCentroid.Add(src[i])
+		tgtCentroid = tgtCentroid.Add(tgt[i])
+	}
+	srcCentroid = srcCentroid.Mul(1 / float64(len(src)))
+	tgtCentroid = tgtCentroid.Mul(1 / float64(len(tgt)))
+
+	cov := mat.NewDense(3, 3, nil)
+	for i := range src {
+		s := src[i].Sub(srcCentroid)
+		t := tgt[i].Sub(tgtCentroid)
+		sv := []float64{s.X, s.Y, s.Z}
+		tv := []float64{t.X, t.Y, t.Z}
+		for r := 0; r < 3; r++ {
+			for c := 0; c < 3; c++ {
+				cov.Set(r, c, cov.At(r, c)+sv[r]*tv[c])
+			}
+		}
+	}
+	var svd mat.SVD
+	if !svd.Factorize(cov, mat.SVDFull) {
+		return nil, errors.New("could not factorize point cloud covariance")
+	}
+	var u, v, rot mat.Dense
+	svd.UTo(&u)
+	svd.VTo(&v)
+	rot.Mul(&v, u.T())
+	if mat.Det(&rot) < 0 {
+		// the best fit is a reflection, so flip the axis of least variance to make it a rotation
+		for r := 0; r < 3; r++ {
+			v.Set(r, 2, -v.At(r, 2))
+		}
+		rot.Mul(&v, u.T())
+	}
+	// spatialmath orientations are built from the transpose of the rotation matrix acting on column vectors
+	rm, err := spatialmath.NewRotationMatrix(mat.DenseCopyOf(rot.T()).RawMatrix().Data)
+	if err != nil {
+		return nil, err
+	}
+	rotated := spatialmath.Compose(spatialmath.NewPoseFromOrientation(rm), spatialmath.NewPoseFromPoint(srcCentroid)).Point()
+	return spatialmath.NewPose(tgtCentroid.Sub(rotated), rm), nil
+}
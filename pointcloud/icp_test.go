@@ -0,0 +1,53 @@
+package pointcloud
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/spatialmath"
+)
+
+func TestRegisterPointCloudICP(t *testing.T) {
+	// scattered points in a room, which only align with themselves in one way
+	rng := rand.New(rand.NewSource(1))
+	room := New()
+	for i := 0; i < 300; i++ {
+		p := r3.Vector{X: rng.Float64() * 2000, Y: rng.Float64() * 2000}
+		test.That(t, room.Set(p, NewValueData(100)), test.ShouldBeNil)
+	}
+	target := ToKDTree(room)
+
+	// the same room seen from a second session started elsewhere
+	offset := spatialmath.NewPose(r3.Vector{X: 40, Y: -30}, &spatialmath.OrientationVector{OZ: 1, Theta: 0.02})
+	source := New()
+	room.Iterate(0, 0, func(p r3.Vector, d Data) bool {
+		moved := spatialmath.Compose(spatialmath.PoseInverse(offset), spatialmath.NewPoseFromPoint(p)).Point()
+		test.That(t, source.Set(moved, d), test.ShouldBeNil)
+		return true
+	})
+
+	cfg := ICPConfig{MaxIterations: 100, MaxCorrespondenceDistance: 300, ConvergenceThreshold: 1e-3}
+	result, err := RegisterPointCloudICP(source, target, nil, cfg)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, result.Converged, test.ShouldBeTrue)
+	test.That(t, spatialmath.PoseAlmostEqualEps(result.Transform, offset, 1), test.ShouldBeTrue)
+	test.That(t, result.RMSError, test.ShouldBeLessThan, 1)
+	test.That(t, result.Fitness, test.ShouldAlmostEqual, 1)
+
+	t.Run("invalid inputs", func(t *testing.T) {
+		_, err := RegisterPointCloudICP(New(), target, nil, cfg)
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = RegisterPointCloudICP(source, target, nil, ICPConfig{MaxCorrespondenceDistance: 500})
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = RegisterPointCloudICP(source, target, nil, ICPConfig{MaxIterations: 10})
+		test.That(t, err, test.ShouldNotBeNil)
+		// a guess too far away for any points to correspond
+		far := spatialmath.NewPoseFromPoint(r3.Vector{X: math.Pow(10, 6)})
+		_, err = RegisterPointCloudICP(source, target, far, cfg)
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,397 @@
+package pointcloud
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/spatialmath"
+)
+
+// OccupancyGridMode is how the pixels of an occupancy grid image are interpreted, as in the ROS map_server.
+type OccupancyGridMode string
+
+const (
+	// OccupancyGridTrinary classifies each cell as occupied, free or unknown using the thresholds.
+	OccupancyGridTrinary OccupancyGridMode = "trinary"
+	// OccupancyGridScale keeps the occupancy probability of cells between the thresholds rather than marking them unknown.
+	OccupancyGridScale OccupancyGridMode = "scale"
+	// OccupancyGridRaw reads each pixel as an occupancy percentage, where values above 100 are unknown.
+	OccupancyGridRaw OccupancyGridMode = "raw"
+)
+
+// UnknownOccupancy is the occupancy of the cells of an occupancy grid which have not been observed.
+const UnknownOccupancy = -1.
+
+// OccupancyGrid is a 2d map of the probability that each cell of the XY plane is occupied, as stored by ROS in a PGM image and
+// YAML metadata file.
+type OccupancyGrid struct {
+	// ResolutionMM is the side length of each cell.
+	ResolutionMM float64
+	// Origin is the pose in the map of the corner of the bottom left cell of the image. Only its yaw is used.
+	Origin spatialmath.Pose
+	// Width is the number of columns of cells and Height the number of rows.
+	Width, Height int
+	// Occupancy holds the probability in [0, 1] that each cell is occupied, or UnknownOccupancy, indexed by row * Width + column
+	// with rows counted up from the bottom of the image.
+	Occupancy []float64
+	// OccupiedThresh is the probability above which cells are obstacles.
+	OccupiedThresh float64
+}
+
+// occupancyGridMetadata holds the fields of the YAML file describing an occupancy grid.
+type occupancyGridMetadata struct {
+	image          string
+	resolution     float64
+	origin         [3]float64
+	negate         bool
+	occupiedThresh float64
+	freeThresh     float64
+	mode           OccupancyGridMode
+}
+
+// NewOccupancyGridFromFile reads an occupancy grid from a ROS map YAML file and the PGM image it refers to, which is found
+// relative to the YAML file.
+func NewOccupancyGridFromFile(yamlPath string) (*OccupancyGrid, error) {
+	//nolint:gosec
+	metaFile, err := os.Open(yamlPath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		//nolint:errcheck,gosec
+		metaFile.Close()
+	}()
+	meta, err := parseOccupancyGridMetadata(metaFile)
+	if err != nil {
+		return nil, err
+	}
+	imagePath := meta.image
+	if !filepath.IsAbs(imagePath) {
+		imagePath = filepath.Join(filepath.Dir(yamlPath), imagePath)
+	}
+	//nolint:gosec
+	imageFile, err := os.Open(imagePath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		//nolint:errcheck,gosec
+		imageFile.Close()
+	}()
+	return newOccupancyGrid(meta, imageFile)
+}
+
+// ReadOccupancyGrid reads an occupancy grid from the contents of a ROS map YAML file and the PGM image it describes. The image
+// field of the YAML is ignored.
+func ReadOccupancyGrid(yamlIn, pgmIn io.Reader) (*OccupancyGrid, error) {
+	meta, err := parseOccupancyGridMetadata(yamlIn)
+	if err != nil {
+		return nil, err
+	}
+	return newOccupancyGrid(meta, pgmIn)
+}
+
+// parseOccupancyGridMetadata parses the flat key: value YAML written by the ROS map_saver.
+func parseOccupancyGridMetadata(in io.Reader) (occupancyGridMetadata, error) {
+	meta := occupancyGridMetadata{occupiedThresh: 0.65, freeThresh: 0.196, mode: OccupancyGridTrinary}
+	seen := map[string]bool{}
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+		seen[key] = true
+		var err error
+		switch key {
+		case "image":
+			meta.image = value
+		case "resolution":
+			meta.resolution, err = strconv.ParseFloat(value, 64)
+		case "origin":
+			fields := strings.Split(strings.Trim(value, "[]"), ",")
+			if len(fields) != 3 {
+				return occupancyGridMetadata{}, errors.Errorf("origin must be [x, y, yaw], got %q", value)
+			}
+			for i, field := range fields {
+				if meta.origin[i], err = strconv.ParseFloat(strings.TrimSpace(field), 64); err != nil {
+					break
+				}
+			}
+		case "negate":
+			var negate int
+			negate, err = strconv.Atoi(value)
+			meta.negate = negate != 0
+		case "occupied_thresh":
+			meta.occupiedThresh, err = strconv.ParseFloat(value, 64)
+		case "free_thresh":
+			meta.freeThresh, err = strconv.ParseFloat(value, 64)
+		case "mode":
+			meta.mode = OccupancyGridMode(value)
+		}
+		if err != nil {
+			return occupancyGridMetadata{}, errors.Wrapf(err, "invalid occupancy grid %s", key)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return occupancyGridMetadata{}, err
+	}
+	for _, required := range []string{"image", "resolution", "origin"} {
+		if !seen[required] {
+			return occupancyGridMetadata{}, errors.Errorf("occupancy grid metadata is missing %s", required)
+		}
+	}
+	if meta.resolution <= 0 {
+		return occupancyGridMetadata{}, errors.New("occupancy grid resolution must be positive")
+	}
+	switch meta.mode {
+	case OccupancyGridTrinary, OccupancyGridScale, OccupancyGridRaw:
+	default:
+		return occupancyGridMetadata{}, errors.Errorf("unsupported occupancy grid mode %q", meta.mode)
+	}
+	return meta, nil
+}
+
+func newOccupancyGrid(meta occupancyGridMetadata, pgmIn io.Reader) (*OccupancyGrid, error) {
+	width, height, maxVal, pixels, err := readPGM(pgmIn)
+	if err != nil {
+		return nil, err
+	}
+	grid := &OccupancyGrid{
+		ResolutionMM: 1000 * meta.resolution,
+		Origin: spatialmath.NewPose(
+			r3.Vector{X: 1000 * meta.origin[0], Y: 1000 * meta.origin[1]},
+			&spatialmath.OrientationVector{OZ: 1, Theta: meta.origin[2]},
+		),
+		Width:          width,
+		Height:         height,
+		Occupancy:      make([]float64, width*height),
+		OccupiedThresh: meta.occupiedThresh,
+	}
+	for row := 0; row < height; row++ {
+		for column := 0; column < width; column++ {
+			// the first row of the image is the top of the map
+			pixel := pixels[(height-1-row)*width+column]
+			grid.Occupancy[row*width+column] = meta.occupancy(pixel, maxVal)
+		}
+	}
+	return grid, nil
+}
+
+// occupancy converts a pixel to the probability that its cell is occupied, following the ROS map_server.
+func (meta occupancyGridMetadata) occupancy(pixel, maxVal int) float64 {
+	if meta.mode == OccupancyGridRaw {
+		if pixel > 100 {
+			return UnknownOccupancy
+		}
+		return float64(pixel) / 100
+	}
+	p := float64(maxVal-pixel) / float64(maxVal)
+	if meta.negate {
+		p = float64(pixel) / float64(maxVal)
+	}
+	switch {
+	case p > meta.occupiedThresh:
+		if meta.mode == OccupancyGridTrinary {
+			return 1
+		}
+		return p
+	case p < meta.freeThresh:
+		return 0
+	case meta.mode == OccupancyGridScale:
+		return p
+	default:
+		return UnknownOccupancy
+	}
+}
+
+// maxPGMPixels is the most pixels an occupancy grid image may have, which is a square map over a kilometer wide at 10cm per cell.
+const maxPGMPixels = 1 << 27
+
+// readPGM reads the pixels of a binary (P5) or ascii (P2) PGM image in row major order from the top of the image.
+func readPGM(in io.Reader) (int, int, int, []int, error) {
+	r := bufio.NewReader(in)
+	var header [4]string
+	for i := range header {
+		token, err := readPGMToken(r)
+		if err != nil {
+			return 0, 0, 0, nil, errors.Wrap(err, "could not read pgm header")
+		}
+		header[i] = token
+	}
+	if header[0] != "P5" && header[0] != "P2" {
+		return 0, 0, 0, nil, errors.Errorf("unsupported pgm format %q", header[0])
+	}
+	var dims [3]int
+	for i, token := range header[1:] {
+		v, err := strconv.Atoi(token)
+		if err != nil || v <= 0 {
+			return 0, 0, 0, nil, errors.Errorf("invalid pgm header value %q", token)
+		}
+		dims[i] = v
+	}
+	width, height, maxVal := dims[0], dims[1], dims[2]
+	if maxVal > math.MaxUint16 {
+		return 0, 0, 0, nil, errors.Errorf("invalid pgm maximum value %d", maxVal)
+	}
+	// the dimensions are checked before the pixels are allocated, so that a corrupt header cannot exhaust memory
+	if width > maxPGMPixels/height {
+		return 0, 0, 0, nil, errors.Errorf("pgm image of %dx%d pixels exceeds the maximum of %d pixels", width, height, maxPGMPixels)
+	}
+
+	pixels := make([]int, width*height)
+	for i := range pixels {
+		if header[0] == "P2" {
+			token, err := readPGMToken(r)
+			if err != nil {
+				return 0, 0, 0, nil, errors.Wrapf(err, "could not read pixel %d", i)
+			}
+			if pixels[i], err = strconv.Atoi(token); err != nil {
+				return 0, 0, 0, nil, errors.Wrapf(err, "invalid pixel %d", i)
+			}
+			continue
+		}
+		hi, err := r.ReadByte()
+		if err != nil {
+			return 0, 0, 0, nil, errors.Wrapf(err, "could not read pixel %d", i)
+		}
+		pixels[i] = int(hi)
+		if maxVal > math.MaxUint8 {
+			// two byte pixels are big endian
+			lo, err := r.ReadByte()
+			if err != nil {
+				return 0, 0, 0, nil, errors.Wrapf(err, "could not read pixel %d", i)
+			}
+			pixels[i] = pixels[i]<<8 | int(lo)
+		}
+	}
+	return width, height, maxVal, pixels, nil
+}
+
+// readPGMToken reads the next whitespace separated token of a PGM header, skipping comments, and consumes the single whitespace
+// character following it.
+func readPGMToken(r *bufio.Reader) (string, error) {
+	var token strings.Builder
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			if errors.Is(err, io.EOF) && token.Len() > 0 {
+				return token.String(), nil
+			}
+			return "", err
+		}
+		switch {
+		case b == '#' && token.Len() == 0:
+			if _, err := r.ReadString('\n'); err != nil {
+				return "", err
+			}
+		case b == ' ' || b == '\t' || b == '\n' || b == '\r':
+			if token.Len() > 0 {
+				return token.String(), nil
+			}
+		default:
+			token.WriteByte(b)
+		}
+	}
+}
+
+// cellCenter returns the center of the cell in the map frame.
+func (g *OccupancyGrid) cellCenter(column, row int) r3.Vector {
+	local := r3.Vector{X: (float64(column) + 0.5) * g.ResolutionMM, Y: (float64(row) + 0.5) * g.ResolutionMM}
+	return spatialmath.Compose(g.Origin, spatialmath.NewPoseFromPoint(local)).Point()
+}
+
+// Occupied returns whether the cell at the given column and row, counted up from the bottom of the image, is an obstacle.
+func (g *OccupancyGrid) Occupied(column, row int) bool {
+	if column < 0 || column >= g.Width || row < 0 || row >= g.Height {
+		return false
+	}
+	return g.Occupancy[row*g.Width+column] > g.OccupiedThresh
+}
+
+// ToPointCloud returns the centers of the occupied cells in the map frame, valued by their occupancy percentage as in the point
+// cloud maps of SLAM services.
+func (g *OccupancyGrid) ToPointCloud() (PointCloud, error) {
+	cloud := New()
+	for row := 0; row < g.Height; row++ {
+		for column := 0; column < g.Width; column++ {
+			if !g.Occupied(column, row) {
+				continue
+			}
+			value := int(math.Round(100 * g.Occupancy[row*g.Width+column]))
+			if err := cloud.Set(g.cellCenter(column, row), NewValueData(value)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return cloud, nil
+}
+
+// ToBasicOctree returns the centers of the occupied cells in the map frame as an octree for collision checking.
+func (g *OccupancyGrid) ToBasicOctree() (*BasicOctree, error) {
+	cloud, err := g.ToPointCloud()
+	if err != nil {
+		return nil, err
+	}
+	builder, err := NewBasicOctreeBuilder(g.ResolutionMM)
+	if err != nil {
+		return nil, err
+	}
+	var setErr error
+	cloud.Iterate(0, 0, func(p r3.Vector, d Data) bool {
+		setErr = builder.Set(p, d)
+		return setErr == nil
+	})
+	if setErr != nil {
+		return nil, setErr
+	}
+	return builder.Octree()
+}
+
+// ToBoxes returns boxes of the given height covering the occupied cells, merging the occupied cells of each row which are next to
+// each other into a single box so that far fewer geometries than cells are needed to describe most maps.
+func (g *OccupancyGrid) ToBoxes(heightMM float64) ([]spatialmath.Geometry, error) {
+	if heightMM <= 0 {
+		return nil, errors.New("box height must be positive")
+	}
+	boxes := []spatialmath.Geometry{}
+	for row := 0; row < g.Height; row++ {
+		for column := 0; column < g.Width; {
+			if !g.Occupied(column, row) {
+				column++
+				continue
+			}
+			start := column
+			for column < g.Width && g.Occupied(column, row) {
+				column++
+			}
+			local := spatialmath.NewPoseFromPoint(r3.Vector{
+				X: float64(start+column) / 2 * g.ResolutionMM,
+				Y: (float64(row) + 0.5) * g.ResolutionMM,
+				Z: heightMM / 2,
+			})
+			box, err := spatialmath.NewBox(
+				spatialmath.Compose(g.Origin, local),
+				r3.Vector{X: float64(column-start) * g.ResolutionMM, Y: g.ResolutionMM, Z: heightMM},
+				fmt.Sprintf("occupancy_grid_%d_%d", row, start),
+			)
+			if err != nil {
+				return nil, err
+			}
+			boxes = append(boxes, box)
+		}
+	}
+	return boxes, nil
+}
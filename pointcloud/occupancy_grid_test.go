@@ -0,0 +1,158 @@
+package pointcloud
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/spatialmath"
+)
+
+// testOccupancyGridPGM is a 4x3 image whose top row is a wall, whose middle row is free apart from one unknown cell, and whose
+// bottom row has a single obstacle.
+var testOccupancyGridPGM = append([]byte("P5\n# map_saver\n4 3\n255\n"),
+	0, 0, 0, 254,
+	254, 205, 254, 254,
+	254, 254, 254, 0,
+)
+
+const testOccupancyGridYAML = `image: map.pgm
+resolution: 0.050 # meters per cell
+origin: [-1.0, 2.0, 0.0]
+negate: 0
+occupied_thresh: 0.65
+free_thresh: 0.196
+`
+
+func TestReadOccupancyGrid(t *testing.T) {
+	grid, err := ReadOccupancyGrid(strings.NewReader(testOccupancyGridYAML), strings.NewReader(string(testOccupancyGridPGM)))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, grid.ResolutionMM, test.ShouldEqual, 50)
+	test.That(t, grid.Width, test.ShouldEqual, 4)
+	test.That(t, grid.Height, test.ShouldEqual, 3)
+	test.That(t, spatialmath.PoseAlmostEqual(grid.Origin, spatialmath.NewPoseFromPoint(r3.Vector{X: -1000, Y: 2000})), test.ShouldBeTrue)
+
+	// rows are counted up from the bottom of the image
+	test.That(t, grid.Occupied(3, 0), test.ShouldBeTrue)
+	test.That(t, grid.Occupied(0, 0), test.ShouldBeFalse)
+	test.That(t, grid.Occupancy[1*4+1], test.ShouldEqual, UnknownOccupancy)
+	test.That(t, grid.Occupied(1, 1), test.ShouldBeFalse)
+	for column := 0; column < 3; column++ {
+		test.That(t, grid.Occupied(column, 2), test.ShouldBeTrue)
+	}
+	test.That(t, grid.Occupied(3, 2), test.ShouldBeFalse)
+	test.That(t, grid.Occupied(-1, 0), test.ShouldBeFalse)
+	test.That(t, grid.Occupied(0, 3), test.ShouldBeFalse)
+
+	cloud, err := grid.ToPointCloud()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cloud.Size(), test.ShouldEqual, 4)
+	d, ok := cloud.At(-1000+3.5*50, 2000+0.5*50, 0)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, d.Value(), test.ShouldEqual, 100)
+
+	octree, err := grid.ToBasicOctree()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, octree.Size(), test.ShouldEqual, 4)
+
+	// the wall is merged into a single box
+	boxes, err := grid.ToBoxes(300)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(boxes), test.ShouldEqual, 2)
+	wall := boxes[1]
+	test.That(t, wall.Pose().Point().ApproxEqual(r3.Vector{X: -1000 + 75, Y: 2000 + 125, Z: 150}), test.ShouldBeTrue)
+	_, err = grid.ToBoxes(0)
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestOccupancyGridModes(t *testing.T) {
+	// an ascii image of a single row of increasingly dark pixels
+	pgm := "P2\n5 1\n100\n100 70 50 30 0\n"
+	read := func(t *testing.T, yaml string) *OccupancyGrid {
+		t.Helper()
+		grid, err := ReadOccupancyGrid(strings.NewReader("image: map.pgm\nresolution: 1\norigin: [0, 0, 0]\n"+yaml),
+			strings.NewReader(pgm))
+		test.That(t, err, test.ShouldBeNil)
+		return grid
+	}
+
+	grid := read(t, "")
+	test.That(t, grid.Occupancy, test.ShouldResemble, []float64{0, UnknownOccupancy, UnknownOccupancy, 1, 1})
+
+	grid = read(t, "mode: scale\n")
+	test.That(t, grid.Occupancy[0], test.ShouldEqual, 0)
+	test.That(t, grid.Occupancy[1], test.ShouldAlmostEqual, 0.3)
+	test.That(t, grid.Occupancy[2], test.ShouldAlmostEqual, 0.5)
+	test.That(t, grid.Occupancy[3], test.ShouldAlmostEqual, 0.7)
+	test.That(t, grid.Occupied(2, 0), test.ShouldBeFalse)
+	test.That(t, grid.Occupied(3, 0), test.ShouldBeTrue)
+
+	grid = read(t, "negate: 1\n")
+	test.That(t, grid.Occupancy, test.ShouldResemble, []float64{1, 1, UnknownOccupancy, UnknownOccupancy, 0})
+
+	grid = read(t, "mode: raw\n")
+	test.That(t, grid.Occupancy[0], test.ShouldEqual, 1)
+	test.That(t, grid.Occupancy[1], test.ShouldAlmostEqual, 0.7)
+	test.That(t, grid.Occupancy[4], test.ShouldEqual, 0)
+}
+
+func TestOccupancyGridRotatedOrigin(t *testing.T) {
+	yaml := "image: map.pgm\nresolution: 0.1\norigin: [0, 0, 1.5707963267948966]\n"
+	grid, err := ReadOccupancyGrid(strings.NewReader(yaml), strings.NewReader("P2 2 1 255 0 254"))
+	test.That(t, err, test.ShouldBeNil)
+	cloud, err := grid.ToPointCloud()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cloud.Size(), test.ShouldEqual, 1)
+	cloud.Iterate(0, 0, func(p r3.Vector, d Data) bool {
+		// the first column is rotated from along X to along Y
+		test.That(t, math.Abs(p.X+50), test.ShouldBeLessThan, 1e-6)
+		test.That(t, math.Abs(p.Y-50), test.ShouldBeLessThan, 1e-6)
+		return true
+	})
+}
+
+func TestNewOccupancyGridFromFile(t *testing.T) {
+	dir := t.TempDir()
+	test.That(t, os.WriteFile(filepath.Join(dir, "map.pgm"), testOccupancyGridPGM, 0o600), test.ShouldBeNil)
+	test.That(t, os.WriteFile(filepath.Join(dir, "map.yaml"), []byte(testOccupancyGridYAML), 0o600), test.ShouldBeNil)
+	grid, err := NewOccupancyGridFromFile(filepath.Join(dir, "map.yaml"))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, grid.Width, test.ShouldEqual, 4)
+
+	_, err = NewOccupancyGridFromFile(filepath.Join(dir, "missing.yaml"))
+	test.That(t, err, test.ShouldNotBeNil)
+
+	for _, yaml := range []string{
+		"resolution: 0.05\norigin: [0, 0, 0]\n",
+		"image: map.pgm\nresolution: 0\norigin: [0, 0, 0]\n",
+		"image: map.pgm\nresolution: 0.05\norigin: [0, 0]\n",
+		"image: map.pgm\nresolution: 0.05\norigin: [0, 0, 0]\nmode: fancy\n",
+	} {
+		_, err := ReadOccupancyGrid(strings.NewReader(yaml), strings.NewReader(string(testOccupancyGridPGM)))
+		test.That(t, err, test.ShouldNotBeNil)
+	}
+	for _, pgm := range []string{"P6 1 1 255 0", "P5 2 2 255 \x00", "P2 1 1 255"} {
+		_, err := ReadOccupancyGrid(strings.NewReader(testOccupancyGridYAML), strings.NewReader(pgm))
+		test.That(t, err, test.ShouldNotBeNil)
+	}
+}
+
+func TestReadOccupancyGridBadHeader(t *testing.T) {
+	for _, pgm := range []string{"P5 0 3 255 ", "P5 4 -3 255 ", "P5 4 3 0 ", "P5 4 3 65536 "} {
+		_, err := ReadOccupancyGrid(strings.NewReader(testOccupancyGridYAML), strings.NewReader(pgm))
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "invalid pgm")
+	}
+
+	// images too large to allocate are refused before their pixels are read, even if their size overflows
+	for _, pgm := range []string{"P5 100000 100000 255 ", "P2 9223372036854775807 2 255 ", "P5 2 9223372036854775807 255 "} {
+		_, err := ReadOccupancyGrid(strings.NewReader(testOccupancyGridYAML), strings.NewReader(pgm))
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "exceeds the maximum")
+	}
+}
@@ -0,0 +1,141 @@
+package pointcloud
+
+import (
+	"bufio"
+	"io"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+)
+
+// streamProgressInterval is the number of points read between calls to the progress callback of ReadPCDToBasicOctreeStreaming.
+const streamProgressInterval = 100000
+
+// PCDReader reads the points of a PCD file one at a time, so that files larger than the available memory can be processed.
+type PCDReader struct {
+	in     *bufio.Reader
+	header pcdHeader
+	read   int
+}
+
+// NewPCDReader parses the header of a PCD file, leaving its points to be read with Next.
+func NewPCDReader(in io.Reader) (*PCDReader, error) {
+	buffered := bufio.NewReader(in)
+	header, err := parsePCDHeader(buffered)
+	if err != nil {
+		return nil, err
+	}
+	switch header.data {
+	case PCDAscii, PCDBinary:
+	case PCDCompressed:
+		return nil, errors.New("compressed pcd not yet supported")
+	default:
+		return nil, errors.Errorf("unsupported pcd data type %v", header.data)
+	}
+	return &PCDReader{in: buffered, header: *header}, nil
+}
+
+// NumPoints returns the number of points the header of the file declares.
+func (r *PCDReader) NumPoints() int {
+	return int(r.header.points)
+}
+
+// Next returns the next point of the file, or io.EOF once all of the points have been read.
+func (r *PCDReader) Next() (PointAndData, error) {
+	if r.read >= r.NumPoints() {
+		return PointAndData{}, io.EOF
+	}
+	var pd PointAndData
+	var err error
+	if r.header.data == PCDAscii {
+		pd, err = extractPCDPointASCII(r.in, r.header, r.read)
+	} else {
+		pd, err = extractPCDPointBinary(r.in, r.header)
+	}
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			// a file which ends early holds fewer points than its header declares, as is tolerated by ReadPCD
+			r.read = r.NumPoints()
+		}
+		return PointAndData{}, err
+	}
+	r.read++
+	return pd, nil
+}
+
+// BasicOctreeBuilder builds a basic octree from points whose bounds are not known in advance, growing the octree as points outside
+// of it are added.
+type BasicOctreeBuilder struct {
+	initialSideLength float64
+	octree            *BasicOctree
+}
+
+// NewBasicOctreeBuilder returns a builder whose octree starts with the given side length around the first point added.
+func NewBasicOctreeBuilder(initialSideLength float64) (*BasicOctreeBuilder, error) {
+	if initialSideLength <= 0 {
+		return nil, errors.Errorf("invalid side length (%.2f) for octree", initialSideLength)
+	}
+	return &BasicOctreeBuilder{initialSideLength: initialSideLength}, nil
+}
+
+// Set adds a point to the octree, doubling the octree towards the point until it fits.
+func (b *BasicOctreeBuilder) Set(p r3.Vector, d Data) error {
+	if b.octree == nil {
+		octree, err := NewBasicOctree(p, b.initialSideLength)
+		if err != nil {
+			return err
+		}
+		b.octree = octree
+	}
+	for depth := 0; !b.octree.checkPointPlacement(p); depth++ {
+		if depth >= maxRecursionDepth {
+			return errors.New("error max allowable recursion depth reached")
+		}
+		b.octree.growToward(p)
+	}
+	return b.octree.Set(p, d)
+}
+
+// Octree returns the octree built so far, which is empty if no points have been added.
+func (b *BasicOctreeBuilder) Octree() (*BasicOctree, error) {
+	if b.octree == nil {
+		return NewBasicOctree(r3.Vector{}, b.initialSideLength)
+	}
+	return b.octree, nil
+}
+
+// ReadPCDToBasicOctreeStreaming reads a PCD file into a basic octree without first reading the whole file into memory, as
+// ReadPCDToBasicOctree does to find the bounds of the octree. If progress is not nil it is called periodically with the number of
+// points read so far and the number of points in the file.
+func ReadPCDToBasicOctreeStreaming(in io.Reader, progress func(read, total int)) (*BasicOctree, error) {
+	reader, err := NewPCDReader(in)
+	if err != nil {
+		return nil, err
+	}
+	// the octree starts at a meter across, the scale of the maps this is used for, and grows from there
+	builder, err := NewBasicOctreeBuilder(1000)
+	if err != nil {
+		return nil, err
+	}
+	read := 0
+	for {
+		pd, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := builder.Set(pd.P, pd.D); err != nil {
+			return nil, err
+		}
+		read++
+		if progress != nil && read%streamProgressInterval == 0 {
+			progress(read, reader.NumPoints())
+		}
+	}
+	if progress != nil {
+		progress(read, reader.NumPoints())
+	}
+	return builder.Octree()
+}
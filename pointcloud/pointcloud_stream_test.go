@@ -0,0 +1,96 @@
+package pointcloud
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+)
+
+func TestBasicOctreeBuilder(t *testing.T) {
+	_, err := NewBasicOctreeBuilder(0)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	builder, err := NewBasicOctreeBuilder(10)
+	test.That(t, err, test.ShouldBeNil)
+	empty, err := builder.Octree()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, empty.Size(), test.ShouldEqual, 0)
+
+	// points far outside of the initial octree in every direction grow it
+	points := []PointAndData{
+		{P: r3.Vector{X: 1, Y: 2, Z: 3}, D: NewValueData(10)},
+		{P: r3.Vector{X: 5000, Y: -3000, Z: 20}, D: NewValueData(90)},
+		{P: r3.Vector{X: -70000, Y: 15, Z: -8}, D: NewValueData(30)},
+		{P: r3.Vector{X: 4, Y: 5, Z: 1e6}, D: NewValueData(50)},
+	}
+	for _, pd := range points {
+		test.That(t, builder.Set(pd.P, pd.D), test.ShouldBeNil)
+	}
+	octree, err := builder.Octree()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, octree.Size(), test.ShouldEqual, len(points))
+	test.That(t, octree.MaxVal(), test.ShouldEqual, 90)
+	for _, pd := range points {
+		d, ok := octree.At(pd.P.X, pd.P.Y, pd.P.Z)
+		test.That(t, ok, test.ShouldBeTrue)
+		test.That(t, d, test.ShouldResemble, pd.D)
+	}
+	iterated := 0
+	octree.Iterate(0, 0, func(p r3.Vector, d Data) bool {
+		iterated++
+		return true
+	})
+	test.That(t, iterated, test.ShouldEqual, len(points))
+	test.That(t, octree.MetaData().MinX, test.ShouldEqual, -70000)
+	test.That(t, octree.MetaData().MaxZ, test.ShouldEqual, 1e6)
+}
+
+func TestReadPCDToBasicOctreeStreaming(t *testing.T) {
+	cloud := New()
+	for i := 0; i < 250; i++ {
+		test.That(t, cloud.Set(r3.Vector{X: float64(i * 100), Y: float64(-i * 40), Z: float64(i % 7)}, nil), test.ShouldBeNil)
+	}
+
+	for _, pcdType := range []PCDType{PCDAscii, PCDBinary} {
+		var buf bytes.Buffer
+		test.That(t, ToPCD(cloud, &buf, pcdType), test.ShouldBeNil)
+		expected, err := ReadPCDToBasicOctree(bytes.NewReader(buf.Bytes()))
+		test.That(t, err, test.ShouldBeNil)
+
+		var progress [][2]int
+		octree, err := ReadPCDToBasicOctreeStreaming(bytes.NewReader(buf.Bytes()), func(read, total int) {
+			progress = append(progress, [2]int{read, total})
+		})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, octree.Size(), test.ShouldEqual, expected.Size())
+		expected.Iterate(0, 0, func(p r3.Vector, d Data) bool {
+			_, ok := octree.At(p.X, p.Y, p.Z)
+			test.That(t, ok, test.ShouldBeTrue)
+			return true
+		})
+		test.That(t, progress, test.ShouldResemble, [][2]int{{250, 250}})
+	}
+
+	t.Run("points are read one at a time", func(t *testing.T) {
+		var buf bytes.Buffer
+		test.That(t, ToPCD(cloud, &buf, PCDBinary), test.ShouldBeNil)
+		reader, err := NewPCDReader(&buf)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, reader.NumPoints(), test.ShouldEqual, 250)
+		for i := 0; i < 250; i++ {
+			_, err := reader.Next()
+			test.That(t, err, test.ShouldBeNil)
+		}
+		_, err = reader.Next()
+		test.That(t, err, test.ShouldEqual, io.EOF)
+	})
+
+	t.Run("invalid files", func(t *testing.T) {
+		_, err := ReadPCDToBasicOctreeStreaming(strings.NewReader("not a pcd"), nil)
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
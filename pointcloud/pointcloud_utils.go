@@ -93,6 +93,68 @@ func StatisticalOutlierFilter(meanK int, stdDevThresh float64) (func(PointCloud)
 	return filterFunc, nil
 }
 
+// VoxelDownsample reduces the point cloud to a single point per cubic voxel of the given side length, placed at the centroid of the
+// points within the voxel. The data of each point is that of the point in its voxel with the highest value, so that the occupancy
+// probabilities of maps are not diluted by downsampling.
+func VoxelDownsample(cloud PointCloud, voxelSize float64) (PointCloud, error) {
+	if voxelSize <= 0 {
+		return nil, errors.Errorf("voxel size must be positive, got %.2f", voxelSize)
+	}
+	type voxel struct {
+		sum   r3.Vector
+		count int
+		data  Data
+	}
+	voxels := map[VoxelCoords]*voxel{}
+	cloud.Iterate(0, 0, func(p r3.Vector, d Data) bool {
+		coords := VoxelCoords{
+			I: int64(math.Floor(p.X / voxelSize)),
+			J: int64(math.Floor(p.Y / voxelSize)),
+			K: int64(math.Floor(p.Z / voxelSize)),
+		}
+		v, ok := voxels[coords]
+		if !ok {
+			v = &voxel{data: d}
+			voxels[coords] = v
+		}
+		v.sum = v.sum.Add(p)
+		v.count++
+		if d != nil && (v.data == nil || d.Value() > v.data.Value()) {
+			v.data = d
+		}
+		return true
+	})
+
+	downsampled := NewWithPrealloc(len(voxels))
+	for _, v := range voxels {
+		if err := downsampled.Set(v.sum.Mul(1/float64(v.count)), v.data); err != nil {
+			return nil, err
+		}
+	}
+	return downsampled, nil
+}
+
+// CropToGeometry returns a point cloud of the points of the cloud which lie within the geometry.
+func CropToGeometry(cloud PointCloud, region spatialmath.Geometry) (PointCloud, error) {
+	cropped := New()
+	var iterateErr error
+	cloud.Iterate(0, 0, func(p r3.Vector, d Data) bool {
+		inside, err := spatialmath.NewPoint(p, "").EncompassedBy(region)
+		if err != nil {
+			iterateErr = err
+			return false
+		}
+		if inside {
+			iterateErr = cropped.Set(p, d)
+		}
+		return iterateErr == nil
+	})
+	if iterateErr != nil {
+		return nil, iterateErr
+	}
+	return cropped, nil
+}
+
 // ToBasicOctree takes a pointcloud object and converts it into a basic octree.
 func ToBasicOctree(cloud PointCloud) (*BasicOctree, error) {
 	if basicOctree, ok := cloud.(*BasicOctree); ok {
@@ -93,3 +93,39 @@ func TestToOctree(t *testing.T) {
 		return true
 	})
 }
+
+func TestVoxelDownsample(t *testing.T) {
+	clouds := makeClouds(t)
+	downsampled, err := VoxelDownsample(clouds[1], 10)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, downsampled.Size(), test.ShouldEqual, 2)
+	_, ok := downsampled.At(30, 0.5, 0.5)
+	test.That(t, ok, test.ShouldBeTrue)
+	_, ok = downsampled.At(28, 0.5, 0.5)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	// the highest value in each voxel is kept
+	cloud := New()
+	test.That(t, cloud.Set(NewVector(0, 0, 0), NewValueData(20)), test.ShouldBeNil)
+	test.That(t, cloud.Set(NewVector(2, 0, 0), NewValueData(90)), test.ShouldBeNil)
+	downsampled, err = VoxelDownsample(cloud, 10)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, downsampled.Size(), test.ShouldEqual, 1)
+	d, ok := downsampled.At(1, 0, 0)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, d.Value(), test.ShouldEqual, 90)
+
+	_, err = VoxelDownsample(cloud, 0)
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestCropToGeometry(t *testing.T) {
+	clouds := makeClouds(t)
+	region, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(r3.Vector{X: 30}), r3.Vector{X: 2, Y: 10, Z: 10}, "")
+	test.That(t, err, test.ShouldBeNil)
+	cropped, err := CropToGeometry(clouds[1], region)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cropped.Size(), test.ShouldEqual, 4)
+	_, ok := cropped.At(28, 0.5, 0.5)
+	test.That(t, ok, test.ShouldBeFalse)
+}
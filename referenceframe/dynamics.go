@@ -0,0 +1,203 @@
+package referenceframe
+
+import (
+	"math"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/spatialmath"
+)
+
+// standardGravity is the acceleration due to gravity in m/s^2, which pulls along -Z of the base of a model.
+const standardGravity = 9.80665
+
+// Payload is a body carried at the end effector of a model, such as an object held by a gripper.
+type Payload struct {
+	Mass         float64   // in kg
+	CenterOfMass r3.Vector // in mm, in the frame of the end effector
+}
+
+// JointLoads are the loads on the joints of a model at a configuration, one for each of its inputs. The loads on rotational joints
+// are in N*m, and those on translational joints are in N.
+type JointLoads struct {
+	// Gravity is the load each joint must hold to keep the model still against gravity.
+	Gravity []float64
+	// Inertia is the inertia each joint moves when accelerated with the others held still, in kg*m^2, or in kg for translational
+	// joints.
+	Inertia []float64
+
+	translational []bool
+}
+
+// Loads returns the load on each joint of the model as its inputs are accelerated at the given rates, in rad/s^2, or in mm/s^2 for
+// translational joints. Accelerations may be nil if the model is held still. Joints are accelerated independently of each other, so
+// loads due to their coupling are not estimated.
+func (l *JointLoads) Loads(accelerations []float64) []float64 {
+	loads := make([]float64, len(l.Gravity))
+	copy(loads, l.Gravity)
+	for i := 0; i < len(accelerations) && i < len(loads); i++ {
+		acceleration := accelerations[i]
+		if l.translational[i] {
+			acceleration *= 1e-3
+		}
+		loads[i] += l.Inertia[i] * acceleration
+	}
+	return loads
+}
+
+// PeakLoads returns the greatest magnitude of the load on each joint of the model as its inputs are accelerated in either direction
+// at up to the given rates, as described by Loads.
+func (l *JointLoads) PeakLoads(accelerations []float64) []float64 {
+	peaks := l.Loads(nil)
+	for i := range peaks {
+		peaks[i] = math.Abs(peaks[i])
+		if i < len(accelerations) {
+			acceleration := math.Abs(accelerations[i])
+			if l.translational[i] {
+				acceleration *= 1e-3
+			}
+			peaks[i] += l.Inertia[i] * acceleration
+		}
+	}
+	return peaks
+}
+
+// body is a mass moved by the joints of a model, located in the frame of its base.
+type body struct {
+	mass        float64
+	center      r3.Vector // in mm
+	orientation spatialmath.Orientation
+	inertia     r3.Vector
+}
+
+// joint is a degree of freedom of a model, located in the frame of its base.
+type joint struct {
+	origin        r3.Vector // in mm
+	axis          r3.Vector
+	translational bool
+	// bodies is the number of bodies nearer to the base than the joint, which it does not move
+	bodies int
+}
+
+// ComputeJointLoads estimates the loads on the joints of a model, whose base is upright, at the given inputs while carrying the given
+// payload, which may be nil. The bodies of the model are described by the masses of the links of its ModelConfig; models without
+// any have no loads.
+func ComputeJointLoads(model Model, inputs []Input, payload *Payload) (*JointLoads, error) {
+	m, ok := model.(*SimpleModel)
+	if !ok {
+		return nil, errors.Errorf("cannot compute joint loads of model %s of type %T", model.Name(), model)
+	}
+	if len(m.DoF()) != len(inputs) {
+		return nil, NewIncorrectDoFError(len(inputs), len(m.DoF()))
+	}
+	links := map[string]LinkConfig{}
+	if cfg := m.ModelConfig(); cfg != nil {
+		for _, link := range cfg.Links {
+			links[link.ID] = link
+		}
+	}
+
+	bodies := []body{}
+	joints := []joint{}
+	composed := spatialmath.NewZeroPose()
+	posIdx := 0
+	for _, transform := range m.OrdTransforms {
+		dof := len(transform.DoF()) + posIdx
+		input := inputs[posIdx:dof]
+		posIdx = dof
+
+		switch f := transform.(type) {
+		case *rotationalFrame:
+			joints = append(joints, joint{origin: composed.Point(), axis: rotateVector(composed, f.rotAxis), bodies: len(bodies)})
+		case *translationalFrame:
+			joints = append(joints, joint{
+				origin:        composed.Point(),
+				axis:          rotateVector(composed, f.transAxis),
+				translational: true,
+				bodies:        len(bodies),
+			})
+		default:
+			if len(f.DoF()) > 0 {
+				return nil, errors.Errorf("cannot compute joint loads of frame %s of type %T", f.Name(), f)
+			}
+		}
+
+		pose, err := transform.Transform(input)
+		if pose == nil {
+			return nil, err
+		}
+		composed = spatialmath.Compose(composed, pose)
+		if link, ok := links[transform.Name()]; ok && link.Mass > 0 {
+			b := body{mass: link.Mass, orientation: composed.Orientation(), center: composed.Point()}
+			if link.CenterOfMass != nil {
+				b.center = spatialmath.Compose(composed, spatialmath.NewPoseFromPoint(*link.CenterOfMass)).Point()
+			}
+			if link.Inertia != nil {
+				b.inertia = *link.Inertia
+			}
+			bodies = append(bodies, b)
+		}
+	}
+	if payload != nil && payload.Mass > 0 {
+		bodies = append(bodies, body{
+			mass:        payload.Mass,
+			center:      spatialmath.Compose(composed, spatialmath.NewPoseFromPoint(payload.CenterOfMass)).Point(),
+			orientation: composed.Orientation(),
+		})
+	}
+
+	loads := &JointLoads{
+		Gravity:       make([]float64, len(joints)),
+		Inertia:       make([]float64, len(joints)),
+		translational: make([]bool, len(joints)),
+	}
+	for i, j := range joints {
+		loads.translational[i] = j.translational
+		for _, b := range bodies[j.bodies:] {
+			weight := r3.Vector{Z: -b.mass * standardGravity}
+			if j.translational {
+				loads.Gravity[i] -= weight.Dot(j.axis)
+				loads.Inertia[i] += b.mass
+				continue
+			}
+			// lever arm from the joint to the body, in meters
+			arm := b.center.Sub(j.origin).Mul(1e-3)
+			loads.Gravity[i] -= arm.Cross(weight).Dot(j.axis)
+			// the body contributes its inertia about the joint axis, by the parallel axis theorem
+			radial := arm.Sub(j.axis.Mul(arm.Dot(j.axis)))
+			axis := rotateVector(spatialmath.PoseInverse(spatialmath.NewPoseFromOrientation(b.orientation)), j.axis)
+			loads.Inertia[i] += b.mass*radial.Norm2() +
+				b.inertia.X*axis.X*axis.X + b.inertia.Y*axis.Y*axis.Y + b.inertia.Z*axis.Z*axis.Z
+		}
+	}
+	return loads, nil
+}
+
+// JointLoadLimits returns the greatest load each input of a model may hold, as given by the MaxTorque of its joints, with zero for
+// inputs whose load is unlimited.
+func JointLoadLimits(model Model) []float64 {
+	limits := make([]float64, len(model.DoF()))
+	m, ok := model.(*SimpleModel)
+	if !ok || m.ModelConfig() == nil {
+		return limits
+	}
+	maxTorques := map[string]float64{}
+	for _, j := range m.ModelConfig().Joints {
+		maxTorques[j.ID] = j.MaxTorque
+	}
+	posIdx := 0
+	for _, transform := range m.OrdTransforms {
+		for range transform.DoF() {
+			limits[posIdx] = maxTorques[transform.Name()]
+			posIdx++
+		}
+	}
+	return limits
+}
+
+// rotateVector returns the vector rotated by the orientation of the pose.
+func rotateVector(pose spatialmath.Pose, v r3.Vector) r3.Vector {
+	rotation := spatialmath.NewPoseFromOrientation(pose.Orientation())
+	return spatialmath.Compose(rotation, spatialmath.NewPoseFromPoint(v)).Point().Normalize()
+}
@@ -0,0 +1,73 @@
+package referenceframe
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	spatial "go.viam.com/rdk/spatialmath"
+)
+
+func TestComputeJointLoads(t *testing.T) {
+	// a single joint pitching a 1 kg mass held 1 m out along X
+	cfg := &ModelConfig{
+		Name: "pendulum",
+		Links: []LinkConfig{
+			{ID: "base", Translation: r3.Vector{Z: 100}},
+			{
+				ID: "arm", Parent: "shoulder", Translation: r3.Vector{X: 1000},
+				Mass: 1, Inertia: &r3.Vector{X: 0.1, Y: 0.2, Z: 0.3},
+			},
+		},
+		Joints: []JointConfig{
+			{ID: "shoulder", Type: RevoluteJoint, Parent: "base", Axis: spatial.AxisConfig{Y: 1}, Max: 180, Min: -180, MaxTorque: 5},
+		},
+	}
+	model, err := cfg.ParseConfig("")
+	test.That(t, err, test.ShouldBeNil)
+
+	t.Run("held level", func(t *testing.T) {
+		loads, err := ComputeJointLoads(model, []Input{{0}}, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, loads.Gravity[0], test.ShouldAlmostEqual, -standardGravity)
+		test.That(t, loads.Inertia[0], test.ShouldAlmostEqual, 1.2)
+		test.That(t, loads.Loads([]float64{1})[0], test.ShouldAlmostEqual, 1.2-standardGravity)
+		test.That(t, loads.PeakLoads([]float64{1})[0], test.ShouldAlmostEqual, 1.2+standardGravity)
+	})
+
+	t.Run("held upright", func(t *testing.T) {
+		loads, err := ComputeJointLoads(model, []Input{{-math.Pi / 2}}, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, loads.Gravity[0], test.ShouldAlmostEqual, 0)
+	})
+
+	t.Run("carrying a payload", func(t *testing.T) {
+		loads, err := ComputeJointLoads(model, []Input{{0}}, &Payload{Mass: 2, CenterOfMass: r3.Vector{X: 500}})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, loads.Gravity[0], test.ShouldAlmostEqual, -4*standardGravity)
+		test.That(t, loads.Inertia[0], test.ShouldAlmostEqual, 1.2+2*1.5*1.5)
+	})
+
+	t.Run("incorrect inputs", func(t *testing.T) {
+		_, err := ComputeJointLoads(model, []Input{}, nil)
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("load limits", func(t *testing.T) {
+		test.That(t, JointLoadLimits(model), test.ShouldResemble, []float64{5})
+	})
+
+	t.Run("round trips through json", func(t *testing.T) {
+		data, err := json.Marshal(cfg)
+		test.That(t, err, test.ShouldBeNil)
+		parsed, err := UnmarshalModelJSON(data, "")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, JointLoadLimits(parsed), test.ShouldResemble, []float64{5})
+		loads, err := ComputeJointLoads(parsed, []Input{{0}}, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, loads.Gravity[0], test.ShouldAlmostEqual, -standardGravity)
+	})
+}
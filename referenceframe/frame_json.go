@@ -22,6 +22,10 @@ type LinkConfig struct {
 	Orientation *spatial.OrientationConfig `json:"orientation"`
 	Geometry    *spatial.GeometryConfig    `json:"geometry,omitempty"`
 	Parent      string                     `json:"parent,omitempty"`
+	// Mass, CenterOfMass and Inertia describe the body of a link, and are used to estimate the loads on the joints which move it.
+	Mass         float64    `json:"mass,omitempty"`           // in kg
+	CenterOfMass *r3.Vector `json:"center_of_mass,omitempty"` // in mm, in the frame of the link, at its origin if unset
+	Inertia      *r3.Vector `json:"inertia,omitempty"`        // principal moments in kg*m^2 about the axes of the link frame
 }
 
 // JointConfig is a frame with nonzero DOF. Supports rotational or translational.
@@ -33,6 +37,8 @@ type JointConfig struct {
 	Max      float64                 `json:"max"`                // in mm or degs
 	Min      float64                 `json:"min"`                // in mm or degs
 	Geometry *spatial.GeometryConfig `json:"geometry,omitempty"` // only valid for prismatic/translational joints
+	// MaxTorque is the greatest load the joint may hold, in N*m, or in N for prismatic/translational joints. Zero means unlimited.
+	MaxTorque float64 `json:"max_torque,omitempty"`
 }
 
 // DHParamConfig is a revolute and static frame combined in a set of Denavit Hartenberg parameters.
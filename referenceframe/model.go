@@ -302,6 +302,45 @@ func New2DMobileModelFrame(name string, limits []Limit, collisionGeometry spatia
 	return model, nil
 }
 
+// New3DMobileModelFrame builds the kinematic model associated with a base which moves freely through the air or water, such as a
+// drone, and whose state is given by its position (x, y, z) and optionally its heading (theta) about the vertical axis. It does not
+// pitch or roll as it moves.
+func New3DMobileModelFrame(name string, limits []Limit, collisionGeometry spatialmath.Geometry) (Model, error) {
+	if len(limits) != 3 && len(limits) != 4 {
+		return nil,
+			errors.Errorf("Must have 3DOF state (x, y, z) or 4DOF state (x, y, z, theta) to create 3DMobileModelFrame, have %d dof", len(limits))
+	}
+
+	x, err := NewTranslationalFrame("x", r3.Vector{X: 1}, limits[0])
+	if err != nil {
+		return nil, err
+	}
+	y, err := NewTranslationalFrame("y", r3.Vector{Y: 1}, limits[1])
+	if err != nil {
+		return nil, err
+	}
+	z, err := NewTranslationalFrame("z", r3.Vector{Z: 1}, limits[2])
+	if err != nil {
+		return nil, err
+	}
+	geometry, err := NewStaticFrameWithGeometry("geometry", spatialmath.NewZeroPose(), collisionGeometry)
+	if err != nil {
+		return nil, err
+	}
+
+	model := NewSimpleModel(name)
+	if len(limits) == 4 {
+		theta, err := NewRotationalFrame("theta", *spatialmath.NewR4AA(), limits[3])
+		if err != nil {
+			return nil, err
+		}
+		model.OrdTransforms = []Frame{x, y, z, theta, geometry}
+	} else {
+		model.OrdTransforms = []Frame{x, y, z, geometry}
+	}
+	return model, nil
+}
+
 // ComputeOOBPosition takes a frame and a slice of Inputs and returns the cartesian position of the frame after
 // transforming it by the given inputs even when if the inputs given would violate the Limits of the frame.
 // This is performed statelessly without changing any data.
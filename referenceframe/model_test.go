@@ -140,3 +140,30 @@ func Test2DMobileModelFrame(t *testing.T) {
 	limit := frame.DoF()
 	test.That(t, limit[0], test.ShouldResemble, expLimit[0])
 }
+
+func Test3DMobileModelFrame(t *testing.T) {
+	expLimit := []Limit{{-10, 10}, {-10, 10}, {0, 20}, {-2 * math.Pi, 2 * math.Pi}}
+	sphere, err := spatial.NewSphere(spatial.NewZeroPose(), 10, "")
+	test.That(t, err, test.ShouldBeNil)
+	frame, err := New3DMobileModelFrame("test", expLimit, sphere)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(frame.DoF()), test.ShouldEqual, 4)
+
+	pose, err := frame.Transform(FloatsToInputs([]float64{3, 5, 7, math.Pi / 2}))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatial.PoseAlmostEqual(pose, spatial.NewPose(r3.Vector{3, 5, 7}, &spatial.OrientationVector{OZ: 1, Theta: math.Pi / 2})),
+		test.ShouldBeTrue)
+	// the geometry of the base moves with it through the air
+	geometries, err := frame.Geometries(FloatsToInputs([]float64{3, 5, 7, 0}))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatial.R3VectorAlmostEqual(geometries.Geometries()[0].Pose().Point(), r3.Vector{3, 5, 7}, 1e-8), test.ShouldBeTrue)
+	// bases may not fly beyond their limits
+	_, err = frame.Transform(FloatsToInputs([]float64{3, 5, -1, 0}))
+	test.That(t, err, test.ShouldNotBeNil)
+
+	frame, err = New3DMobileModelFrame("test", expLimit[:3], sphere)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(frame.DoF()), test.ShouldEqual, 3)
+	_, err = New3DMobileModelFrame("test", expLimit[:2], sphere)
+	test.That(t, err, test.ShouldNotBeNil)
+}
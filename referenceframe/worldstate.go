@@ -13,10 +13,14 @@ import (
 const unnamedWorldStateGeometryPrefix = "unnamedWorldStateGeometry_"
 
 // WorldState is a struct to store the data representation of the robot's environment.
+//
+// Interaction spaces are keep-in regions which the moving geometries of the robot must stay within. They are not yet part of the
+// protobuf definition of a WorldState, so they are dropped when a WorldState is converted to one.
 type WorldState struct {
-	obstacleNames map[string]bool
-	obstacles     []*GeometriesInFrame
-	transforms    []*LinkInFrame
+	obstacleNames     map[string]bool
+	obstacles         []*GeometriesInFrame
+	interactionSpaces []*GeometriesInFrame
+	transforms        []*LinkInFrame
 }
 
 // NewEmptyWorldState is a constructor for a WorldState object that has no obstacles or transforms.
@@ -60,6 +64,20 @@ func NewWorldState(obstacles []*GeometriesInFrame, transforms []*LinkInFrame) (*
 	return ws, nil
 }
 
+// NewWorldStateWithInteractionSpaces is a constructor for a WorldState object which also keeps the robot within the given
+// interaction spaces.
+func NewWorldStateWithInteractionSpaces(
+	obstacles, interactionSpaces []*GeometriesInFrame,
+	transforms []*LinkInFrame,
+) (*WorldState, error) {
+	ws, err := NewWorldState(obstacles, transforms)
+	if err != nil {
+		return nil, err
+	}
+	ws.interactionSpaces = interactionSpaces
+	return ws, nil
+}
+
 // WorldStateFromProtobuf takes the protobuf definition of a WorldState and converts it to a rdk defined WorldState.
 func WorldStateFromProtobuf(proto *commonpb.WorldState) (*WorldState, error) {
 	transforms, err := LinkInFramesFromTransformsProtobuf(proto.GetTransforms())
@@ -121,9 +139,32 @@ func (ws *WorldState) String() string {
 			})
 		}
 	}
+	for _, geometries := range ws.interactionSpaces {
+		for _, geometry := range geometries.geometries {
+			t.AppendRow([]interface{}{
+				geometry.Label() + " (interaction space)",
+				fmt.Sprint(geometry),
+				geometries.frame,
+			})
+		}
+	}
 	return t.Render()
 }
 
+// WithObstacles returns a copy of the WorldState to which the given obstacles have been added, keeping its transforms and
+// interaction spaces. The names of the added obstacles must not collide with those already in the WorldState.
+func (ws *WorldState) WithObstacles(obstacles ...*GeometriesInFrame) (*WorldState, error) {
+	var existing []*GeometriesInFrame
+	if ws != nil {
+		existing = ws.obstacles
+	}
+	return NewWorldStateWithInteractionSpaces(
+		append(append([]*GeometriesInFrame{}, existing...), obstacles...),
+		ws.InteractionSpaces(),
+		ws.Transforms(),
+	)
+}
+
 // ObstacleNames returns the set of geometry names that have been registered in the WorldState, represented as a map.
 func (ws *WorldState) ObstacleNames() map[string]bool {
 	if ws == nil {
@@ -151,9 +192,30 @@ func (ws *WorldState) ObstaclesInWorldFrame(fs FrameSystem, inputs FrameSystemIn
 	if ws == nil {
 		return NewGeometriesInFrame(World, []spatialmath.Geometry{}), nil
 	}
+	return geometriesInWorldFrame(fs, inputs, ws.obstacles)
+}
+
+// InteractionSpaces returns the interaction spaces that have been added to the WorldState.
+func (ws *WorldState) InteractionSpaces() []*GeometriesInFrame {
+	if ws == nil {
+		return []*GeometriesInFrame{}
+	}
+	return ws.interactionSpaces
+}
+
+// InteractionSpacesInWorldFrame takes a frame system and a set of inputs for that frame system and converts all the interaction
+// spaces in the WorldState such that they are in the frame system's World reference frame.
+func (ws *WorldState) InteractionSpacesInWorldFrame(fs FrameSystem, inputs FrameSystemInputs) (*GeometriesInFrame, error) {
+	if ws == nil {
+		return NewGeometriesInFrame(World, []spatialmath.Geometry{}), nil
+	}
+	return geometriesInWorldFrame(fs, inputs, ws.interactionSpaces)
+}
 
-	allGeometries := make([]spatialmath.Geometry, 0, len(ws.obstacles))
-	for _, gf := range ws.obstacles {
+// geometriesInWorldFrame transforms all the given geometries into the World reference frame of the frame system.
+func geometriesInWorldFrame(fs FrameSystem, inputs FrameSystemInputs, gifs []*GeometriesInFrame) (*GeometriesInFrame, error) {
+	allGeometries := make([]spatialmath.Geometry, 0, len(gifs))
+	for _, gf := range gifs {
 		tf, err := fs.Transform(inputs, gf, World)
 		if err != nil {
 			return nil, err
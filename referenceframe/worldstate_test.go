@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/golang/geo/r3"
 	"github.com/jedib0t/go-pretty/v6/table"
 	"go.viam.com/test"
 
@@ -78,3 +79,70 @@ func TestString(t *testing.T) {
 
 	test.That(t, fmt.Sprint(ws), test.ShouldEqual, testTable.Render())
 }
+
+func TestInteractionSpaces(t *testing.T) {
+	obstacle, err := spatialmath.NewSphere(spatialmath.NewZeroPose(), 10, "obstacle")
+	test.That(t, err, test.ShouldBeNil)
+	space, err := spatialmath.NewBox(spatialmath.NewZeroPose(), r3.Vector{X: 100, Y: 100, Z: 100}, "space")
+	test.That(t, err, test.ShouldBeNil)
+
+	fs := NewEmptyFrameSystem("test")
+	mount, err := NewStaticFrame("mount", spatialmath.NewPoseFromPoint(r3.Vector{X: 500}))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(mount, fs.World()), test.ShouldBeNil)
+	offset, err := NewStaticFrame("offset", spatialmath.NewZeroPose())
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(offset, mount), test.ShouldBeNil)
+
+	ws, err := NewWorldStateWithInteractionSpaces(
+		[]*GeometriesInFrame{NewGeometriesInFrame(World, []spatialmath.Geometry{obstacle})},
+		[]*GeometriesInFrame{NewGeometriesInFrame("offset", []spatialmath.Geometry{space})},
+		nil,
+	)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(ws.InteractionSpaces()), test.ShouldEqual, 1)
+
+	inWorld, err := ws.InteractionSpacesInWorldFrame(fs, NewZeroInputs(fs))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(inWorld.Geometries()), test.ShouldEqual, 1)
+	test.That(t, inWorld.Geometries()[0].Pose().Point().X, test.ShouldAlmostEqual, 500)
+	obstacles, err := ws.ObstaclesInWorldFrame(fs, NewZeroInputs(fs))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(obstacles.Geometries()), test.ShouldEqual, 1)
+
+	var empty *WorldState
+	test.That(t, empty.InteractionSpaces(), test.ShouldBeEmpty)
+	inWorld, err = empty.InteractionSpacesInWorldFrame(fs, NewZeroInputs(fs))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, inWorld.Geometries(), test.ShouldBeEmpty)
+}
+
+func TestWithObstacles(t *testing.T) {
+	obstacle, err := spatialmath.NewSphere(spatialmath.NewZeroPose(), 10, "obstacle")
+	test.That(t, err, test.ShouldBeNil)
+	space, err := spatialmath.NewBox(spatialmath.NewZeroPose(), r3.Vector{X: 100, Y: 100, Z: 100}, "space")
+	test.That(t, err, test.ShouldBeNil)
+	ws, err := NewWorldStateWithInteractionSpaces(
+		[]*GeometriesInFrame{NewGeometriesInFrame(World, []spatialmath.Geometry{obstacle})},
+		[]*GeometriesInFrame{NewGeometriesInFrame(World, []spatialmath.Geometry{space})},
+		nil,
+	)
+	test.That(t, err, test.ShouldBeNil)
+
+	detection, err := spatialmath.NewSphere(spatialmath.NewPoseFromPoint(r3.Vector{X: 100}), 10, "detection")
+	test.That(t, err, test.ShouldBeNil)
+	added, err := ws.WithObstacles(NewGeometriesInFrame("camera", []spatialmath.Geometry{detection}))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, added.ObstacleNames(), test.ShouldResemble, map[string]bool{"obstacle": true, "detection": true})
+	test.That(t, len(added.InteractionSpaces()), test.ShouldEqual, 1)
+	// the original is unchanged
+	test.That(t, ws.ObstacleNames(), test.ShouldResemble, map[string]bool{"obstacle": true})
+
+	_, err = added.WithObstacles(NewGeometriesInFrame(World, []spatialmath.Geometry{detection}))
+	test.That(t, err, test.ShouldNotBeNil)
+
+	var empty *WorldState
+	added, err = empty.WithObstacles(NewGeometriesInFrame(World, []spatialmath.Geometry{obstacle}))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, added.ObstacleNames(), test.ShouldResemble, map[string]bool{"obstacle": true})
+}
@@ -0,0 +1,181 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+// ReservationOverrideKey is the key in a command's extra parameters that, when set to true, allows the command to be run
+// against a resource even though it is reserved by someone else.
+const ReservationOverrideKey = "override_reservation"
+
+// ReservationsAPI is the fully qualified API for the internal reservations service.
+var ReservationsAPI = APINamespaceRDKInternal.WithServiceType("reservations")
+
+// ReservationsInternalName is used to refer to/depend on the reservations of a robot internally.
+var ReservationsInternalName = NewName(ReservationsAPI, "builtin")
+
+// Reservations holds the reservations on the resources of a robot, of which each robot has one, so that robots sharing a
+// process, such as a robot and its remotes, never block each other's resources. A nil Reservations reserves nothing.
+type Reservations struct {
+	Named
+	TriviallyReconfigurable
+	TriviallyCloseable
+
+	mu     sync.Mutex
+	byName map[Name][]*Reservation
+}
+
+// NewReservations returns new reservations, in which nothing is reserved.
+func NewReservations() *Reservations {
+	return &Reservations{Named: ReservationsInternalName.AsNamed(), byName: map[Name][]*Reservation{}}
+}
+
+// A Reservation is a lease held on a resource, such as the one a motion service execution holds on the base it is moving, which
+// prevents clients from commanding the resource until it is released. A resource may hold several reservations at once, in
+// which case commands are refused until all of them are released.
+type Reservation struct {
+	reservations *Reservations
+	name         Name
+	holder       string
+	once         sync.Once
+}
+
+// Reserve reserves the named resource for the holder until the returned reservation is released.
+func (rs *Reservations) Reserve(name Name, holder string) *Reservation {
+	r := &Reservation{reservations: rs, name: name, holder: holder}
+	if rs == nil {
+		return r
+	}
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.byName[name] = append(rs.byName[name], r)
+	return r
+}
+
+// Release releases the reservation. It is safe to call more than once.
+func (r *Reservation) Release() {
+	if r.reservations == nil {
+		return
+	}
+	r.once.Do(func() {
+		rs := r.reservations
+		rs.mu.Lock()
+		defer rs.mu.Unlock()
+		held := rs.byName[r.name]
+		for i, other := range held {
+			if other == r {
+				held = append(held[:i], held[i+1:]...)
+				break
+			}
+		}
+		if len(held) == 0 {
+			delete(rs.byName, r.name)
+		} else {
+			rs.byName[r.name] = held
+		}
+	})
+}
+
+// Holder returns the holder of the most recent reservation on the named resource, and whether the resource is reserved.
+func (rs *Reservations) Holder(name Name) (string, bool) {
+	if rs == nil {
+		return "", false
+	}
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	held := rs.byName[name]
+	if len(held) == 0 {
+		return "", false
+	}
+	return held[len(held)-1].holder, true
+}
+
+// Check returns a ReservedError if the named resource is reserved, unless the extra parameters of the command override the
+// reservation.
+func (rs *Reservations) Check(name Name, extra map[string]interface{}) error {
+	if override, ok := extra[ReservationOverrideKey].(bool); ok && override {
+		return nil
+	}
+	if holder, ok := rs.Holder(name); ok {
+		return NewReservedError(name, holder)
+	}
+	return nil
+}
+
+type reservationsCtxKey int
+
+const ctxKeyReservations = reservationsCtxKey(iota)
+
+// ContextWithReservations attaches the reservations of a robot to the given context.
+func ContextWithReservations(ctx context.Context, rs *Reservations) context.Context {
+	return context.WithValue(ctx, ctxKeyReservations, rs)
+}
+
+// ReservationsFromContext returns the reservations attached to the given context, or nil if there are none.
+func ReservationsFromContext(ctx context.Context) *Reservations {
+	rs, _ := ctx.Value(ctxKeyReservations).(*Reservations)
+	return rs
+}
+
+// CheckReservation returns a ReservedError if the named resource is reserved in the reservations attached to the context,
+// unless the extra parameters of the command override the reservation. Nothing is reserved if the context has no reservations.
+func CheckReservation(ctx context.Context, name Name, extra map[string]interface{}) error {
+	return ReservationsFromContext(ctx).Check(name, extra)
+}
+
+// UnaryServerInterceptor attaches the reservations to the context of the request before passing it to the unary handler, so
+// that resource servers can check them.
+func (rs *Reservations) UnaryServerInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	return handler(ContextWithReservations(ctx, rs), req)
+}
+
+// StreamServerInterceptor attaches the reservations to the context of the stream before passing it to the stream handler, so
+// that resource servers can check them.
+func (rs *Reservations) StreamServerInterceptor(
+	srv interface{},
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	return handler(srv, &reservationsServerStream{ss, ContextWithReservations(ss.Context(), rs)})
+}
+
+type reservationsServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s reservationsServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// NewReservedError is used when a resource cannot be commanded because it is reserved by another holder.
+func NewReservedError(name Name, holder string) error {
+	return &ReservedError{Name: name, Holder: holder}
+}
+
+// IsReservedError returns whether or not the given error is a ReservedError.
+func IsReservedError(err error) bool {
+	var errArt *ReservedError
+	return errors.As(err, &errArt)
+}
+
+// ReservedError is returned when a resource is commanded while it is reserved by another holder.
+type ReservedError struct {
+	Name   Name
+	Holder string
+}
+
+func (e *ReservedError) Error() string {
+	return fmt.Sprintf("resource %q is busy by %s; set %q in extra to override", e.Name, e.Holder, ReservationOverrideKey)
+}
@@ -0,0 +1,55 @@
+package resource
+
+import (
+	"context"
+	"testing"
+
+	"go.viam.com/test"
+)
+
+func TestReservation(t *testing.T) {
+	name := NewName(APINamespace("foo").WithType("bar").WithSubtype("baz"), "reserved")
+	reservations := NewReservations()
+	test.That(t, reservations.Check(name, nil), test.ShouldBeNil)
+
+	r1 := reservations.Reserve(name, "execution 1")
+
+	err := reservations.Check(name, nil)
+	test.That(t, IsReservedError(err), test.ShouldBeTrue)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `resource "foo:bar:baz/reserved" is busy by execution 1`)
+	test.That(t, reservations.Check(name, map[string]interface{}{ReservationOverrideKey: true}), test.ShouldBeNil)
+
+	// the resource stays reserved until every reservation is released
+	r2 := reservations.Reserve(name, "execution 2")
+	holder, ok := reservations.Holder(name)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, holder, test.ShouldEqual, "execution 2")
+
+	r2.Release()
+	r2.Release()
+	holder, ok = reservations.Holder(name)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, holder, test.ShouldEqual, "execution 1")
+
+	r1.Release()
+	test.That(t, reservations.Check(name, nil), test.ShouldBeNil)
+}
+
+func TestReservationsAreScopedToRobot(t *testing.T) {
+	name := NewName(APINamespace("foo").WithType("bar").WithSubtype("baz"), "reserved")
+	reservations := NewReservations()
+	r := reservations.Reserve(name, "execution 1")
+	defer r.Release()
+
+	// a resource of the same name on another robot is not reserved
+	ctx := ContextWithReservations(context.Background(), reservations)
+	otherCtx := ContextWithReservations(context.Background(), NewReservations())
+	test.That(t, IsReservedError(CheckReservation(ctx, name, nil)), test.ShouldBeTrue)
+	test.That(t, CheckReservation(otherCtx, name, nil), test.ShouldBeNil)
+
+	// nothing is reserved without reservations
+	test.That(t, CheckReservation(context.Background(), name, nil), test.ShouldBeNil)
+	var none *Reservations
+	none.Reserve(name, "execution 2").Release()
+	test.That(t, none.Check(name, nil), test.ShouldBeNil)
+}
@@ -27,9 +27,11 @@ func InvalidDistortionError(msg string) error {
 
 // NewDistorter returns a Distorter given a valid DistortionType and its parameters.
 func NewDistorter(distortionType DistortionType, parameters []float64) (Distorter, error) {
-	switch distortionType { //nolint:exhaustive
+	switch distortionType {
 	case BrownConradyDistortionType:
 		return NewBrownConrady(parameters)
+	case KannalaBrandtDistortionType:
+		return NewKannalaBrandt(parameters)
 	default:
 		return nil, errors.Errorf("do not know how to parse %q distortion model", distortionType)
 	}
@@ -0,0 +1,67 @@
+package transform
+
+import (
+	"math"
+	"testing"
+
+	"go.viam.com/test"
+)
+
+func TestKannalaBrandt(t *testing.T) {
+	t.Run("nil &KannalaBrandt{} are invalid", func(t *testing.T) {
+		var nilKannalaBrandtPtr *KannalaBrandt
+		err := nilKannalaBrandtPtr.CheckValid()
+		expected := "KannalaBrandt shaped distortion_parameters not provided: invalid distortion_parameters"
+		test.That(t, err.Error(), test.ShouldContainSubstring, expected)
+		test.That(t, nilKannalaBrandtPtr.Parameters(), test.ShouldResemble, []float64{})
+		x, y := nilKannalaBrandtPtr.Transform(0.3, 0.4)
+		test.That(t, x, test.ShouldEqual, 0.3)
+		test.That(t, y, test.ShouldEqual, 0.4)
+	})
+
+	t.Run("parameters are filled in order", func(t *testing.T) {
+		kb, err := NewKannalaBrandt([]float64{0.1, 0.2})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, kb.CheckValid(), test.ShouldBeNil)
+		test.That(t, kb.Parameters(), test.ShouldResemble, []float64{0.1, 0.2, 0, 0})
+		_, err = NewKannalaBrandt([]float64{0.1, 0.2, 0.3, 0.4, 0.5})
+		test.That(t, err, test.ShouldNotBeNil)
+
+		distorter, err := NewDistorter(KannalaBrandtDistortionType, []float64{0.1, 0.2, 0.3, 0.4})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, distorter.ModelType(), test.ShouldEqual, KannalaBrandtDistortionType)
+		test.That(t, distorter.Parameters(), test.ShouldResemble, []float64{0.1, 0.2, 0.3, 0.4})
+	})
+
+	t.Run("points are moved along their radius", func(t *testing.T) {
+		kb := &KannalaBrandt{}
+		x, y := kb.Transform(0, 0)
+		test.That(t, x, test.ShouldEqual, 0)
+		test.That(t, y, test.ShouldEqual, 0)
+
+		// without any distortion terms a point at 45 degrees off the optical axis lands at pi/4 from the center
+		x, y = kb.Transform(0.6, 0.8)
+		test.That(t, math.Hypot(x, y), test.ShouldAlmostEqual, math.Pi/4)
+		test.That(t, x/y, test.ShouldAlmostEqual, 0.75)
+
+		kb.K1 = 0.1
+		x, _ = kb.Transform(1, 0)
+		theta := math.Pi / 4
+		test.That(t, x, test.ShouldAlmostEqual, theta*(1+0.1*theta*theta))
+	})
+
+	t.Run("fisheye pinhole models distort images", func(t *testing.T) {
+		model := &PinholeCameraModel{
+			PinholeCameraIntrinsics: &PinholeCameraIntrinsics{Width: 100, Height: 100, Fx: 50, Fy: 50, Ppx: 50, Ppy: 50},
+			Distortion:              &KannalaBrandt{K1: -0.01},
+		}
+		distortionMap := model.DistortionMap()
+		x, y := distortionMap(50, 50)
+		test.That(t, x, test.ShouldAlmostEqual, 50)
+		test.That(t, y, test.ShouldAlmostEqual, 50)
+		// points far from the center are pulled in towards it
+		x, y = distortionMap(100, 50)
+		test.That(t, x, test.ShouldBeLessThan, 100)
+		test.That(t, y, test.ShouldAlmostEqual, 50)
+	})
+}
@@ -0,0 +1,148 @@
+// Package estop implements the software emergency stop of a robot. Engaging it notifies every subscriber, such as the motion service,
+// which stops whatever it is moving and refuses to move anything else until the e-stop is reset. It is no substitute for a hardware
+// e-stop which cuts power to actuators.
+package estop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+// SubtypeName is a constant that identifies the internal e-stop resource subtype string.
+const SubtypeName = "estop"
+
+// API is the fully qualified API for the internal e-stop service.
+var API = resource.APINamespaceRDKInternal.WithServiceType(SubtypeName)
+
+// InternalServiceName is used to refer to/depend on this service internally.
+var InternalServiceName = resource.NewName(API, "builtin")
+
+// A StopFunc is called when the e-stop is engaged, with the reason it was engaged for, and returns once what it stops has stopped.
+type StopFunc func(ctx context.Context, reason string) error
+
+// Service is the software e-stop of a robot, of which each robot has one.
+type Service interface {
+	resource.Resource
+
+	// Engage engages the e-stop for the given reason and calls every subscriber at once, returning once all of them have returned.
+	// Engaging the e-stop while it is already engaged calls the subscribers again, but keeps the reason it was first engaged for.
+	Engage(ctx context.Context, reason string) error
+
+	// Reset releases the e-stop so that motion may be commanded again. Nothing which was stopped resumes on its own.
+	Reset()
+
+	// Engaged returns whether the e-stop is engaged, and the reason it was engaged for.
+	Engaged() (bool, string)
+
+	// Check returns an EngagedError if the e-stop is engaged.
+	Check() error
+
+	// Subscribe has stop called whenever the e-stop is engaged, until the returned function is called.
+	Subscribe(stop StopFunc) func()
+}
+
+// FromDependencies is a helper for getting the e-stop of the robot from a collection of dependencies.
+func FromDependencies(deps resource.Dependencies) (Service, error) {
+	return resource.FromDependencies[Service](deps, InternalServiceName)
+}
+
+// New returns a new e-stop, which is not engaged.
+func New(logger logging.Logger) Service {
+	return &eStop{
+		Named:       InternalServiceName.AsNamed(),
+		logger:      logger,
+		subscribers: map[int]StopFunc{},
+	}
+}
+
+type eStop struct {
+	resource.Named
+	resource.TriviallyReconfigurable
+	resource.TriviallyCloseable
+	logger logging.Logger
+
+	mu          sync.Mutex
+	engaged     bool
+	reason      string
+	nextID      int
+	subscribers map[int]StopFunc
+}
+
+func (e *eStop) Engage(ctx context.Context, reason string) error {
+	e.mu.Lock()
+	if !e.engaged {
+		e.engaged = true
+		e.reason = reason
+	}
+	subscribers := make([]StopFunc, 0, len(e.subscribers))
+	for _, stop := range e.subscribers {
+		subscribers = append(subscribers, stop)
+	}
+	e.mu.Unlock()
+	e.logger.CWarnf(ctx, "e-stop engaged: %s", reason)
+
+	errs := make([]error, len(subscribers))
+	var wg sync.WaitGroup
+	for i, stop := range subscribers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = stop(ctx, reason)
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+func (e *eStop) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.engaged = false
+	e.reason = ""
+}
+
+func (e *eStop) Engaged() (bool, string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.engaged, e.reason
+}
+
+func (e *eStop) Check() error {
+	if engaged, reason := e.Engaged(); engaged {
+		return &EngagedError{Reason: reason}
+	}
+	return nil
+}
+
+func (e *eStop) Subscribe(stop StopFunc) func() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	id := e.nextID
+	e.nextID++
+	e.subscribers[id] = stop
+	return func() {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		delete(e.subscribers, id)
+	}
+}
+
+// IsEngagedError returns whether or not the given error is an EngagedError.
+func IsEngagedError(err error) bool {
+	var errArt *EngagedError
+	return errors.As(err, &errArt)
+}
+
+// EngagedError is returned when motion is commanded while the e-stop is engaged.
+type EngagedError struct {
+	Reason string
+}
+
+func (e *EngagedError) Error() string {
+	return fmt.Sprintf("e-stop is engaged (%s); reset it before commanding motion", e.Reason)
+}
@@ -0,0 +1,58 @@
+package estop_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/robot/estop"
+)
+
+func TestEStop(t *testing.T) {
+	ctx := context.Background()
+	e := estop.New(logging.NewTestLogger(t))
+
+	var stopped []string
+	unsubscribe := e.Subscribe(func(ctx context.Context, reason string) error {
+		stopped = append(stopped, reason)
+		return nil
+	})
+	failing := e.Subscribe(func(ctx context.Context, reason string) error {
+		return errors.New("cannot stop")
+	})
+
+	test.That(t, e.Check(), test.ShouldBeNil)
+
+	err := e.Engage(ctx, "person in cell")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "cannot stop")
+	test.That(t, stopped, test.ShouldResemble, []string{"person in cell"})
+	engaged, reason := e.Engaged()
+	test.That(t, engaged, test.ShouldBeTrue)
+	test.That(t, reason, test.ShouldEqual, "person in cell")
+	test.That(t, estop.IsEngagedError(e.Check()), test.ShouldBeTrue)
+	test.That(t, e.Check().Error(), test.ShouldContainSubstring, "person in cell")
+
+	// engaging again stops the subscribers again, but keeps the first reason
+	failing()
+	test.That(t, e.Engage(ctx, "again"), test.ShouldBeNil)
+	test.That(t, stopped, test.ShouldResemble, []string{"person in cell", "again"})
+	_, reason = e.Engaged()
+	test.That(t, reason, test.ShouldEqual, "person in cell")
+
+	e.Reset()
+	test.That(t, e.Check(), test.ShouldBeNil)
+
+	// unsubscribed functions are not called
+	unsubscribe()
+	test.That(t, e.Engage(ctx, "unsubscribed"), test.ShouldBeNil)
+	test.That(t, stopped, test.ShouldHaveLength, 2)
+
+	// each robot has its own e-stop
+	other := estop.New(logging.NewTestLogger(t))
+	test.That(t, other.Check(), test.ShouldBeNil)
+	test.That(t, other.Name(), test.ShouldResemble, estop.InternalServiceName)
+}
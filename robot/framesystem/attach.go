@@ -0,0 +1,89 @@
+package framesystem
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// AttachGeometry attaches the geometry, posed relative to the named frame, to that frame as a frame of its own named by the label of
+// the geometry. The geometry then moves with the frame, and is part of every frame system returned until it is detached, so that
+// motion planning checks it for collisions like any other part of the machine.
+func (svc *frameSystemService) AttachGeometry(ctx context.Context, frame string, geometry spatialmath.Geometry) error {
+	if geometry == nil {
+		return errors.New("cannot attach a nil geometry")
+	}
+	label := geometry.Label()
+	if label == "" {
+		return errors.New("an attached geometry must have a label to name its frame")
+	}
+	fs, err := svc.FrameSystem(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if fs.Frame(frame) == nil {
+		return referenceframe.NewFrameMissingError(frame)
+	}
+	if fs.Frame(label) != nil {
+		return DuplicateFrameNameError(label)
+	}
+
+	svc.partsMu.Lock()
+	defer svc.partsMu.Unlock()
+	for _, attached := range svc.attached {
+		if attached.Name() == label {
+			return DuplicateFrameNameError(label)
+		}
+	}
+	svc.attached = append(svc.attached, referenceframe.NewLinkInFrame(frame, spatialmath.NewZeroPose(), label, geometry))
+	// transforms to and from the attached frame must not outlive it
+	svc.static = newStaticTransformCache()
+	return nil
+}
+
+// DetachGeometry detaches the geometry with the given label, along with any geometries attached to it, from the frame system.
+func (svc *frameSystemService) DetachGeometry(ctx context.Context, label string) error {
+	svc.partsMu.Lock()
+	defer svc.partsMu.Unlock()
+	detached := map[string]bool{label: true}
+	found := false
+	remaining := make([]*referenceframe.LinkInFrame, 0, len(svc.attached))
+	// geometries are attached after their parents, so the parent of each is known to be detached before it is reached
+	for _, attached := range svc.attached {
+		if attached.Name() == label {
+			found = true
+			continue
+		}
+		if detached[attached.Parent()] {
+			detached[attached.Name()] = true
+			continue
+		}
+		remaining = append(remaining, attached)
+	}
+	if !found {
+		return AttachedGeometryNotFoundError(label)
+	}
+	svc.attached = remaining
+	svc.static = newStaticTransformCache()
+	return nil
+}
+
+// attachedTo returns the geometries of the given attached geometries which are still attached to a frame among the given parts,
+// directly or through other attached geometries.
+func attachedTo(parts []*referenceframe.FrameSystemPart, attached []*referenceframe.LinkInFrame) []*referenceframe.LinkInFrame {
+	frames := map[string]bool{referenceframe.World: true}
+	for _, part := range parts {
+		frames[part.FrameConfig.Name()] = true
+	}
+	remaining := make([]*referenceframe.LinkInFrame, 0, len(attached))
+	for _, link := range attached {
+		if frames[link.Parent()] {
+			frames[link.Name()] = true
+			remaining = append(remaining, link)
+		}
+	}
+	return remaining
+}
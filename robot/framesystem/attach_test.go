@@ -0,0 +1,112 @@
+package framesystem
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+func TestAttachGeometry(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+
+	model, err := referenceframe.ParseModelJSONFile(utils.ResolveFile("components/arm/example_kinematics/xarm6_kinematics_test.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+	armName := resource.NewName(resource.APINamespaceRDK.WithComponentType("arm"), "arm")
+	arm := &countingArm{Named: armName.AsNamed(), dof: len(model.DoF())}
+	parts := []*referenceframe.FrameSystemPart{
+		{
+			FrameConfig: referenceframe.NewLinkInFrame(referenceframe.World, spatialmath.NewZeroPose(), "arm", nil),
+			ModelFrame:  model,
+		},
+		{
+			FrameConfig: referenceframe.NewLinkInFrame("arm", spatialmath.NewPoseFromPoint(r3.Vector{Z: 50}), "gripper", nil),
+		},
+	}
+	svc, err := New(ctx, resource.Dependencies{armName: arm}, logger)
+	test.That(t, err, test.ShouldBeNil)
+	reconfigure := func(parts []*referenceframe.FrameSystemPart) {
+		test.That(t, svc.Reconfigure(ctx, resource.Dependencies{armName: arm}, resource.Config{
+			ConvertedAttributes: &Config{Parts: parts},
+		}), test.ShouldBeNil)
+	}
+	reconfigure(parts)
+	box := func(label string) spatialmath.Geometry {
+		geometry, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(r3.Vector{Z: 40}), r3.Vector{X: 60, Y: 60, Z: 80}, label)
+		test.That(t, err, test.ShouldBeNil)
+		return geometry
+	}
+	// the pose of the center of the named frame in the frame of the gripper
+	inGripper := func(name string) spatialmath.Pose {
+		pose, err := svc.TransformPose(ctx, referenceframe.NewZeroPoseInFrame(name), "gripper", nil)
+		test.That(t, err, test.ShouldBeNil)
+		return pose.Pose()
+	}
+
+	t.Run("invalid attachments are refused", func(t *testing.T) {
+		test.That(t, svc.AttachGeometry(ctx, "gripper", nil), test.ShouldNotBeNil)
+		test.That(t, svc.AttachGeometry(ctx, "gripper", box("")), test.ShouldNotBeNil)
+		test.That(t, svc.AttachGeometry(ctx, "missing", box("cup")), test.ShouldNotBeNil)
+		test.That(t, svc.AttachGeometry(ctx, "gripper", box("arm")), test.ShouldBeError, DuplicateFrameNameError("arm"))
+		test.That(t, svc.DetachGeometry(ctx, "cup"), test.ShouldBeError, AttachedGeometryNotFoundError("cup"))
+	})
+
+	// the number of geometries in the frame system
+	countGeometries := func() int {
+		fs, err := svc.FrameSystem(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		inputs, _, err := svc.CurrentInputs(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		geometries, err := referenceframe.FrameSystemGeometries(fs, inputs)
+		test.That(t, err, test.ShouldBeNil)
+		count := 0
+		for _, inFrame := range geometries {
+			count += len(inFrame.Geometries())
+		}
+		return count
+	}
+
+	t.Run("attached geometries move with their frames until detached", func(t *testing.T) {
+		machineGeometries := countGeometries()
+		test.That(t, svc.AttachGeometry(ctx, "gripper", box("cup")), test.ShouldBeNil)
+		test.That(t, svc.AttachGeometry(ctx, "gripper", box("cup")), test.ShouldBeError, DuplicateFrameNameError("cup"))
+		// a lid sits on the cup
+		test.That(t, svc.AttachGeometry(ctx, "cup", box("lid")), test.ShouldBeNil)
+		test.That(t, spatialmath.PoseAlmostEqual(inGripper("lid"), spatialmath.NewZeroPose()), test.ShouldBeTrue)
+
+		// so the geometries of the cup and lid are checked for collisions along with those of the machine
+		test.That(t, countGeometries(), test.ShouldEqual, machineGeometries+2)
+
+		// detaching the cup detaches the lid sitting on it
+		test.That(t, svc.DetachGeometry(ctx, "cup"), test.ShouldBeNil)
+		test.That(t, countGeometries(), test.ShouldEqual, machineGeometries)
+		fs, err := svc.FrameSystem(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, fs.Frame("cup"), test.ShouldBeNil)
+		test.That(t, fs.Frame("lid"), test.ShouldBeNil)
+		_, err = svc.TransformPose(ctx, referenceframe.NewZeroPoseInFrame("lid"), "gripper", nil)
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("geometries stay attached through reconfiguration while their frames remain", func(t *testing.T) {
+		test.That(t, svc.AttachGeometry(ctx, "gripper", box("cup")), test.ShouldBeNil)
+		reconfigure(parts)
+		fs, err := svc.FrameSystem(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, fs.Frame("cup"), test.ShouldNotBeNil)
+
+		reconfigure(parts[:1])
+		fs, err = svc.FrameSystem(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, fs.Frame("cup"), test.ShouldBeNil)
+		test.That(t, svc.DetachGeometry(ctx, "cup"), test.ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,184 @@
+package framesystem
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// PointCloudInFrame is a point cloud along with the name of the frame its points are in.
+type PointCloudInFrame struct {
+	PointCloud pointcloud.PointCloud
+	Frame      string
+}
+
+// staticTransformKey identifies the transform from the origin of the src frame to the dst frame.
+type staticTransformKey struct {
+	src, dst string
+}
+
+// staticTransformCache caches the transforms between pairs of frames which are connected only through frames without inputs. They
+// do not change until the frame system is reconfigured, so transforming between them needs neither the frame system to be traversed
+// nor any inputs to be read.
+type staticTransformCache struct {
+	mu         sync.RWMutex
+	transforms map[staticTransformKey]spatialmath.Pose
+}
+
+func newStaticTransformCache() *staticTransformCache {
+	return &staticTransformCache{transforms: map[staticTransformKey]spatialmath.Pose{}}
+}
+
+func (c *staticTransformCache) get(src, dst string) (spatialmath.Pose, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	tf, ok := c.transforms[staticTransformKey{src, dst}]
+	return tf, ok
+}
+
+func (c *staticTransformCache) put(src, dst string, tf spatialmath.Pose) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.transforms[staticTransformKey{src, dst}] = tf
+}
+
+// staticBetween returns whether the transform between the two frames is independent of the inputs of the frame system, which is the
+// case if every frame between them, but not above their common ancestor, has no degrees of freedom.
+func staticBetween(fs referenceframe.FrameSystem, src, dst string) (bool, error) {
+	traceback := func(name string) ([]referenceframe.Frame, error) {
+		frame := fs.Frame(name)
+		if frame == nil {
+			return nil, referenceframe.NewFrameMissingError(name)
+		}
+		return fs.TracebackFrame(frame)
+	}
+	srcChain, err := traceback(src)
+	if err != nil {
+		return false, err
+	}
+	dstChain, err := traceback(dst)
+	if err != nil {
+		return false, err
+	}
+	inSrc := map[string]bool{}
+	for _, frame := range srcChain {
+		inSrc[frame.Name()] = true
+	}
+	inDst := map[string]bool{}
+	for _, frame := range dstChain {
+		inDst[frame.Name()] = true
+		if !inSrc[frame.Name()] && len(frame.DoF()) > 0 {
+			return false, nil
+		}
+	}
+	for _, frame := range srcChain {
+		if !inDst[frame.Name()] && len(frame.DoF()) > 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// TransformPoses transforms each of the poses to the destination frame. The frame system is built and the current inputs are read
+// at most once for the whole batch, and transforms between frames connected only through static frames are cached between calls.
+func (svc *frameSystemService) TransformPoses(
+	ctx context.Context,
+	poses []*referenceframe.PoseInFrame,
+	dst string,
+	additionalTransforms []*referenceframe.LinkInFrame,
+) ([]*referenceframe.PoseInFrame, error) {
+	if len(poses) == 0 {
+		return nil, nil
+	}
+	fs, cache, err := svc.frameSystemWithCache(additionalTransforms)
+	if err != nil {
+		return nil, err
+	}
+	// additional transforms may differ between calls, so transforms through them are not cached
+	if len(additionalTransforms) > 0 {
+		cache = nil
+	}
+
+	var inputs referenceframe.FrameSystemInputs
+	transformed := make([]*referenceframe.PoseInFrame, 0, len(poses))
+	for _, pose := range poses {
+		src := pose.Parent()
+		if cache != nil {
+			if tf, ok := cache.get(src, dst); ok {
+				transformed = append(transformed, referenceframe.NewPoseInFrame(dst, spatialmath.Compose(tf, pose.Pose())))
+				continue
+			}
+		}
+		static, err := staticBetween(fs, src, dst)
+		if err != nil {
+			return nil, err
+		}
+		if static {
+			tf, err := fs.Transform(referenceframe.NewZeroInputs(fs), referenceframe.NewZeroPoseInFrame(src), dst)
+			if err != nil {
+				return nil, err
+			}
+			srcToDst, ok := tf.(*referenceframe.PoseInFrame)
+			if !ok {
+				return nil, errors.New("unable to assert referenceframe.Transformable into *referenceframe.PoseInFrame")
+			}
+			if cache != nil {
+				cache.put(src, dst, srcToDst.Pose())
+			}
+			transformed = append(transformed, referenceframe.NewPoseInFrame(dst, spatialmath.Compose(srcToDst.Pose(), pose.Pose())))
+			continue
+		}
+		if inputs == nil {
+			if inputs, _, err = svc.readInputs(ctx, fs); err != nil {
+				return nil, err
+			}
+		}
+		tf, err := fs.Transform(inputs, pose, dst)
+		if err != nil {
+			return nil, err
+		}
+		pif, ok := tf.(*referenceframe.PoseInFrame)
+		if !ok {
+			return nil, errors.New("unable to assert referenceframe.Transformable into *referenceframe.PoseInFrame")
+		}
+		transformed = append(transformed, pif)
+	}
+	return transformed, nil
+}
+
+// TransformPointClouds transforms each of the point clouds to the destination frame, defaulting to the world frame if it is empty.
+// The transforms of their frames are found together, as by TransformPoses.
+func (svc *frameSystemService) TransformPointClouds(
+	ctx context.Context,
+	srcpcs []PointCloudInFrame,
+	dstName string,
+) ([]pointcloud.PointCloud, error) {
+	if dstName == "" {
+		dstName = referenceframe.World
+	}
+	origins := make([]*referenceframe.PoseInFrame, 0, len(srcpcs))
+	for _, srcpc := range srcpcs {
+		if srcpc.Frame == "" {
+			return nil, errors.New("srcName cannot be empty, must provide name of point cloud origin")
+		}
+		origins = append(origins, referenceframe.NewZeroPoseInFrame(srcpc.Frame))
+	}
+	transforms, err := svc.TransformPoses(ctx, origins, dstName, nil)
+	if err != nil {
+		return nil, err
+	}
+	transformed := make([]pointcloud.PointCloud, 0, len(srcpcs))
+	for i, srcpc := range srcpcs {
+		pc, err := pointcloud.ApplyOffset(ctx, srcpc.PointCloud, transforms[i].Pose(), svc.logger)
+		if err != nil {
+			return nil, err
+		}
+		transformed = append(transformed, pc)
+	}
+	return transformed, nil
+}
@@ -0,0 +1,143 @@
+package framesystem
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+// countingArm is an InputEnabled resource which counts how often its inputs are read.
+type countingArm struct {
+	resource.Named
+	resource.TriviallyReconfigurable
+	resource.TriviallyCloseable
+	dof   int
+	reads int
+}
+
+func (a *countingArm) CurrentInputs(ctx context.Context) ([]referenceframe.Input, error) {
+	a.reads++
+	return make([]referenceframe.Input, a.dof), nil
+}
+
+func (a *countingArm) GoToInputs(ctx context.Context, inputs ...[]referenceframe.Input) error {
+	return nil
+}
+
+func TestTransformPoses(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+
+	model, err := referenceframe.ParseModelJSONFile(utils.ResolveFile("components/arm/example_kinematics/xarm6_kinematics_test.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+	armName := resource.NewName(resource.APINamespaceRDK.WithComponentType("arm"), "arm")
+	arm := &countingArm{Named: armName.AsNamed(), dof: len(model.DoF())}
+	parts := []*referenceframe.FrameSystemPart{
+		{
+			FrameConfig: referenceframe.NewLinkInFrame(referenceframe.World, spatialmath.NewPoseFromPoint(r3.Vector{X: 100}), "arm", nil),
+			ModelFrame:  model,
+		},
+		{
+			FrameConfig: referenceframe.NewLinkInFrame(referenceframe.World, spatialmath.NewPoseFromPoint(r3.Vector{Z: 500}), "camera", nil),
+		},
+		{
+			FrameConfig: referenceframe.NewLinkInFrame("arm", spatialmath.NewPoseFromPoint(r3.Vector{Z: 50}), "gripper", nil),
+		},
+	}
+	svc, err := New(ctx, resource.Dependencies{armName: arm}, logger)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, svc.Reconfigure(ctx, resource.Dependencies{armName: arm}, resource.Config{
+		ConvertedAttributes: &Config{Parts: parts},
+	}), test.ShouldBeNil)
+
+	detections := []*referenceframe.PoseInFrame{
+		referenceframe.NewPoseInFrame("camera", spatialmath.NewPoseFromPoint(r3.Vector{X: 10})),
+		referenceframe.NewPoseInFrame("camera", spatialmath.NewPoseFromPoint(r3.Vector{Y: 10})),
+	}
+
+	t.Run("static frames are transformed without reading inputs", func(t *testing.T) {
+		transformed, err := svc.TransformPoses(ctx, detections, referenceframe.World, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(transformed), test.ShouldEqual, 2)
+		test.That(t, spatialmath.R3VectorAlmostEqual(transformed[0].Pose().Point(), r3.Vector{X: 10, Z: 500}, 1e-8), test.ShouldBeTrue)
+		test.That(t, spatialmath.R3VectorAlmostEqual(transformed[1].Pose().Point(), r3.Vector{Y: 10, Z: 500}, 1e-8), test.ShouldBeTrue)
+		test.That(t, arm.reads, test.ShouldEqual, 0)
+
+		fss := svc.(*frameSystemService)
+		_, cached := fss.static.get("camera", referenceframe.World)
+		test.That(t, cached, test.ShouldBeTrue)
+	})
+
+	t.Run("inputs are read once per batch", func(t *testing.T) {
+		batch := append([]*referenceframe.PoseInFrame{
+			referenceframe.NewZeroPoseInFrame("gripper"),
+			referenceframe.NewZeroPoseInFrame("gripper"),
+		}, detections...)
+		transformed, err := svc.TransformPoses(ctx, batch, "camera", nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(transformed), test.ShouldEqual, 4)
+		test.That(t, arm.reads, test.ShouldEqual, 1)
+
+		single, err := svc.TransformPose(ctx, referenceframe.NewZeroPoseInFrame("gripper"), "camera", nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, spatialmath.PoseAlmostEqual(single.Pose(), transformed[0].Pose()), test.ShouldBeTrue)
+	})
+
+	t.Run("point clouds are transformed together", func(t *testing.T) {
+		pc := pointcloud.New()
+		test.That(t, pc.Set(r3.Vector{X: 1}, nil), test.ShouldBeNil)
+		transformed, err := svc.TransformPointClouds(ctx, []PointCloudInFrame{{PointCloud: pc, Frame: "camera"}}, "")
+		test.That(t, err, test.ShouldBeNil)
+		points := []r3.Vector{}
+		transformed[0].Iterate(0, 0, func(p r3.Vector, d pointcloud.Data) bool {
+			points = append(points, p)
+			return true
+		})
+		test.That(t, len(points), test.ShouldEqual, 1)
+		test.That(t, spatialmath.R3VectorAlmostEqual(points[0], r3.Vector{X: 1, Z: 500}, 1e-8), test.ShouldBeTrue)
+
+		_, err = svc.TransformPointClouds(ctx, []PointCloudInFrame{{PointCloud: pc}}, "")
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("reconfiguring clears the cache", func(t *testing.T) {
+		moved := []*referenceframe.FrameSystemPart{parts[0], {
+			FrameConfig: referenceframe.NewLinkInFrame(referenceframe.World, spatialmath.NewPoseFromPoint(r3.Vector{Z: 800}), "camera", nil),
+		}}
+		test.That(t, svc.Reconfigure(ctx, resource.Dependencies{armName: arm}, resource.Config{
+			ConvertedAttributes: &Config{Parts: moved},
+		}), test.ShouldBeNil)
+		transformed, err := svc.TransformPoses(ctx, detections[:1], referenceframe.World, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, transformed[0].Pose().Point().Z, test.ShouldAlmostEqual, 800)
+	})
+
+	t.Run("transforms of a frame system built before reconfiguring are not cached after it", func(t *testing.T) {
+		fss := svc.(*frameSystemService)
+		stale, staleCache, err := fss.frameSystemWithCache(nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, svc.Reconfigure(ctx, resource.Dependencies{armName: arm}, resource.Config{
+			ConvertedAttributes: &Config{Parts: parts},
+		}), test.ShouldBeNil)
+
+		// a batch which began before the reconfiguration finishes by caching into the cache of its own frame system
+		tf, err := stale.Transform(referenceframe.NewZeroInputs(stale), referenceframe.NewZeroPoseInFrame("camera"), referenceframe.World)
+		test.That(t, err, test.ShouldBeNil)
+		staleCache.put("camera", referenceframe.World, tf.(*referenceframe.PoseInFrame).Pose())
+		_, cached := fss.static.get("camera", referenceframe.World)
+		test.That(t, cached, test.ShouldBeFalse)
+
+		transformed, err := svc.TransformPoses(ctx, detections[:1], referenceframe.World, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, transformed[0].Pose().Point().Z, test.ShouldAlmostEqual, 500)
+	})
+}
@@ -21,3 +21,29 @@ func DependencyNotFoundError(name string) error {
 func NotInputEnabledError(component resource.Resource) error {
 	return errors.Errorf("%v(%T) is not InputEnabled", component.Name(), component)
 }
+
+// DuplicateFrameNameError returns an error if a frame is attempted to be added to the frame system with the name of a frame already
+// in it.
+func DuplicateFrameNameError(name string) error {
+	return errors.Errorf("frame system already has a frame named: %v", name)
+}
+
+// AttachedGeometryNotFoundError returns an error if no geometry with the given label is attached to the frame system.
+func AttachedGeometryNotFoundError(label string) error {
+	return errors.Errorf("frame system has no attached geometry with label: %v", label)
+}
+
+// NotPoseTrackerError is returned when the given component is not a pose tracker but should be.
+func NotPoseTrackerError(component resource.Resource) error {
+	return errors.Errorf("%v(%T) is not a pose tracker", component.Name(), component)
+}
+
+// BodyNotTrackedError returns an error if the given pose tracker does not see the given body.
+func BodyNotTrackedError(poseTrackerName, body string) error {
+	return errors.Errorf("pose tracker %v does not see body: %v", poseTrackerName, body)
+}
+
+// TrackedFrameNotFoundError returns an error if the frame system has no tracked frame with the given name.
+func TrackedFrameNotFoundError(name string) error {
+	return errors.Errorf("frame system has no tracked frame named: %v", name)
+}
@@ -7,7 +7,6 @@ import (
 	"sync"
 
 	"github.com/jedib0t/go-pretty/v6/table"
-	"github.com/pkg/errors"
 	"go.opencensus.io/trace"
 
 	"go.viam.com/rdk/logging"
@@ -85,6 +84,33 @@ type Service interface {
 
 	// FrameSystem returns the frame system of the machine and incorporates any specified additional transformations.
 	FrameSystem(ctx context.Context, additionalTransforms []*referenceframe.LinkInFrame) (referenceframe.FrameSystem, error)
+
+	// TransformPoses returns each of the poses transformed to the destination reference frame, reading the current inputs of the
+	// machine at most once for the whole batch.
+	TransformPoses(
+		ctx context.Context,
+		poses []*referenceframe.PoseInFrame,
+		dst string,
+		additionalTransforms []*referenceframe.LinkInFrame,
+	) ([]*referenceframe.PoseInFrame, error)
+
+	// TransformPointClouds returns each of the point clouds with its points adjusted to the destination frame.
+	TransformPointClouds(ctx context.Context, srcpcs []PointCloudInFrame, dstName string) ([]pointcloud.PointCloud, error)
+
+	// AttachGeometry attaches the geometry, such as an object which has just been grasped, to the named frame until it is detached,
+	// so that motion planning includes it in self-collision and obstacle checks. The geometry is posed relative to the frame, and
+	// its label names the frame it is attached as.
+	AttachGeometry(ctx context.Context, frame string, geometry spatialmath.Geometry) error
+
+	// DetachGeometry detaches the geometry with the given label, such as an object which has just been released.
+	DetachGeometry(ctx context.Context, label string) error
+
+	// AddTrackedFrame adds a frame with the given name whose pose is the latest pose of the body seen by the named pose tracker, such
+	// as a tag on a cart, so that poses relative to it, such as motion goals, are resolved against wherever the body was last seen.
+	AddTrackedFrame(ctx context.Context, name, poseTrackerName, body string) error
+
+	// RemoveTrackedFrame removes the tracked frame with the given name.
+	RemoveTrackedFrame(ctx context.Context, name string) error
 }
 
 // FromDependencies is a helper for getting the framesystem from a collection of dependencies.
@@ -98,6 +124,7 @@ func New(ctx context.Context, deps resource.Dependencies, logger logging.Logger)
 		Named:      InternalServiceName.AsNamed(),
 		components: make(map[string]resource.Resource),
 		logger:     logger,
+		static:     newStaticTransformCache(),
 	}
 	if err := fs.Reconfigure(ctx, deps, resource.Config{ConvertedAttributes: &Config{}}); err != nil {
 		return nil, err
@@ -164,6 +191,12 @@ type frameSystemService struct {
 
 	parts   []*referenceframe.FrameSystemPart
 	partsMu sync.RWMutex
+	// static caches transforms between static frames of the parts, and is replaced whenever they are reconfigured
+	static *staticTransformCache
+	// attached are the geometries attached to frames of the parts at runtime, each after the frame it is attached to
+	attached []*referenceframe.LinkInFrame
+	// tracked are the frames added at runtime whose poses are those of bodies seen by pose trackers, each after its parent
+	tracked []*trackedFrame
 }
 
 // Reconfigure will rebuild the frame system from the newly updated robot.
@@ -195,6 +228,14 @@ func (svc *frameSystemService) Reconfigure(ctx context.Context, deps resource.De
 		return err
 	}
 	svc.parts = sortedParts
+	// tracked frames and attached geometries stay through reconfiguration, unless the frames or pose trackers they rely on are removed
+	svc.tracked = trackedBy(sortedParts, components, svc.tracked)
+	allParts, err := svc.allParts()
+	if err != nil {
+		return err
+	}
+	svc.attached = attachedTo(allParts, svc.attached)
+	svc.static = newStaticTransformCache()
 	svc.logger.Debugf("reconfigured robot frame system: %v", (&Config{Parts: sortedParts}).String())
 	return nil
 }
@@ -209,46 +250,11 @@ func (svc *frameSystemService) TransformPose(
 	ctx, span := trace.StartSpan(ctx, "services::framesystem::TransformPose")
 	defer span.End()
 
-	fs, err := svc.FrameSystem(ctx, additionalTransforms)
+	transformed, err := svc.TransformPoses(ctx, []*referenceframe.PoseInFrame{pose}, dst, additionalTransforms)
 	if err != nil {
 		return nil, err
 	}
-	input := referenceframe.NewZeroInputs(fs)
-
-	svc.partsMu.RLock()
-	defer svc.partsMu.RUnlock()
-
-	// build maps of relevant components and inputs from initial inputs
-	for name, inputs := range input {
-		// skip frame if it does not have input
-		if len(inputs) == 0 {
-			continue
-		}
-
-		// add component to map
-		component, ok := svc.components[name]
-		if !ok {
-			return nil, DependencyNotFoundError(name)
-		}
-		inputEnabled, ok := component.(InputEnabled)
-		if !ok {
-			return nil, NotInputEnabledError(component)
-		}
-
-		// add input to map
-		pos, err := inputEnabled.CurrentInputs(ctx)
-		if err != nil {
-			return nil, err
-		}
-		input[name] = pos
-	}
-
-	tf, err := fs.Transform(input, pose, dst)
-	if err != nil {
-		return nil, err
-	}
-	pose, _ = tf.(*referenceframe.PoseInFrame)
-	return pose, nil
+	return transformed[0], nil
 }
 
 // CurrentInputs will get present inputs for a framesystem from a robot and return a map of those inputs, as well as a map of the
@@ -260,10 +266,26 @@ func (svc *frameSystemService) CurrentInputs(
 	if err != nil {
 		return nil, nil, err
 	}
+	return svc.readInputs(ctx, fs)
+}
+
+// readInputs reads the current inputs of each frame of the frame system which has them, returning them along with the InputEnabled
+// resources they came from.
+func (svc *frameSystemService) readInputs(
+	ctx context.Context,
+	fs referenceframe.FrameSystem,
+) (referenceframe.FrameSystemInputs, map[string]InputEnabled, error) {
+	svc.partsMu.RLock()
+	defer svc.partsMu.RUnlock()
+
 	input := referenceframe.NewZeroInputs(fs)
 
 	// build maps of relevant components and inputs from initial inputs
 	resources := map[string]InputEnabled{}
+	tracked := map[string]InputEnabled{}
+	for _, tf := range svc.tracked {
+		tracked[tf.name] = tf
+	}
 	for name, original := range input {
 		// skip frames with no input
 		if len(original) == 0 {
@@ -271,13 +293,15 @@ func (svc *frameSystemService) CurrentInputs(
 		}
 
 		// add component to map
-		component, ok := svc.components[name]
+		inputEnabled, ok := tracked[name]
 		if !ok {
-			return nil, nil, DependencyNotFoundError(name)
-		}
-		inputEnabled, ok := component.(InputEnabled)
-		if !ok {
-			return nil, nil, NotInputEnabledError(component)
+			component, ok := svc.components[name]
+			if !ok {
+				return nil, nil, DependencyNotFoundError(name)
+			}
+			if inputEnabled, ok = component.(InputEnabled); !ok {
+				return nil, nil, NotInputEnabledError(component)
+			}
 		}
 		resources[name] = inputEnabled
 
@@ -299,7 +323,31 @@ func (svc *frameSystemService) FrameSystem(
 ) (referenceframe.FrameSystem, error) {
 	_, span := trace.StartSpan(ctx, "services::framesystem::FrameSystem")
 	defer span.End()
-	return referenceframe.NewFrameSystem(LocalFrameSystemName, svc.parts, additionalTransforms)
+	fs, _, err := svc.frameSystemWithCache(additionalTransforms)
+	return fs, err
+}
+
+// frameSystemWithCache builds the frame system along with the cache of its static transforms, which are read under the same lock
+// so that transforms of a frame system built before a reconfiguration are never cached for the frame system after it.
+func (svc *frameSystemService) frameSystemWithCache(
+	additionalTransforms []*referenceframe.LinkInFrame,
+) (referenceframe.FrameSystem, *staticTransformCache, error) {
+	svc.partsMu.RLock()
+	parts, err := svc.allParts()
+	if err != nil {
+		svc.partsMu.RUnlock()
+		return nil, nil, err
+	}
+	transforms := make([]*referenceframe.LinkInFrame, 0, len(svc.attached)+len(additionalTransforms))
+	transforms = append(transforms, svc.attached...)
+	cache := svc.static
+	svc.partsMu.RUnlock()
+	transforms = append(transforms, additionalTransforms...)
+	fs, err := referenceframe.NewFrameSystem(LocalFrameSystemName, parts, transforms)
+	if err != nil {
+		return nil, nil, err
+	}
+	return fs, cache, nil
 }
 
 // TransformPointCloud applies the same pose offset to each point in a single pointcloud and returns the transformed point cloud.
@@ -308,20 +356,11 @@ func (svc *frameSystemService) FrameSystem(
 // of the transformed pointcloud because that will make the transformations inaccurate.
 func (svc *frameSystemService) TransformPointCloud(ctx context.Context, srcpc pointcloud.PointCloud, srcName, dstName string,
 ) (pointcloud.PointCloud, error) {
-	if dstName == "" {
-		dstName = referenceframe.World
-	}
-	if srcName == "" {
-		return nil, errors.New("srcName cannot be empty, must provide name of point cloud origin")
-	}
-	// get transform pose needed to get to destination frame
-	sourceFrameZero := referenceframe.NewPoseInFrame(srcName, spatialmath.NewZeroPose())
-	theTransform, err := svc.TransformPose(ctx, sourceFrameZero, dstName, nil)
+	transformed, err := svc.TransformPointClouds(ctx, []PointCloudInFrame{{PointCloud: srcpc, Frame: srcName}}, dstName)
 	if err != nil {
 		return nil, err
 	}
-	// returned the transformed pointcloud where the transform was applied to each point
-	return pointcloud.ApplyOffset(ctx, srcpc, theTransform.Pose(), svc.logger)
+	return transformed[0], nil
 }
 
 // PrefixRemoteParts applies prefixes to a list of FrameSystemParts appropriate to the remote they originate from.
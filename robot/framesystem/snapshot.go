@@ -0,0 +1,194 @@
+package framesystem
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/pkg/errors"
+	goutils "go.viam.com/utils"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/referenceframe"
+)
+
+// Snapshot is the state of a frame system at an instant: the frame system itself, including any additional transforms it was
+// captured with, and the inputs of each of its frames.
+type Snapshot struct {
+	Time        time.Time
+	FrameSystem referenceframe.FrameSystem
+	Inputs      referenceframe.FrameSystemInputs
+}
+
+// CaptureSnapshot captures the current state of the frame system of the service, timestamped by the given clock once its inputs
+// have been read. If the clock is nil, the wall clock is used.
+func CaptureSnapshot(
+	ctx context.Context,
+	svc Service,
+	additionalTransforms []*referenceframe.LinkInFrame,
+	clk clock.Clock,
+) (*Snapshot, error) {
+	if clk == nil {
+		clk = clock.New()
+	}
+	fs, err := svc.FrameSystem(ctx, additionalTransforms)
+	if err != nil {
+		return nil, err
+	}
+	inputs, _, err := svc.CurrentInputs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Snapshot{Time: clk.Now(), FrameSystem: fs, Inputs: inputs}, nil
+}
+
+// TransformPose transforms the pose to the destination frame as the frame system was at the time of the snapshot.
+func (s *Snapshot) TransformPose(pose *referenceframe.PoseInFrame, dst string) (*referenceframe.PoseInFrame, error) {
+	tf, err := s.FrameSystem.Transform(s.Inputs, pose, dst)
+	if err != nil {
+		return nil, err
+	}
+	pif, ok := tf.(*referenceframe.PoseInFrame)
+	if !ok {
+		return nil, errors.New("unable to assert referenceframe.Transformable into *referenceframe.PoseInFrame")
+	}
+	return pif, nil
+}
+
+// SnapshotHistory keeps the snapshots of a frame system captured over a trailing window of time, so that where its frames were at
+// past instants may be queried, such as to associate a camera image with the pose of the gripper when it was taken.
+type SnapshotHistory struct {
+	svc    Service
+	window time.Duration
+	clock  clock.Clock
+
+	mu sync.RWMutex
+	// snapshots are sorted by time
+	snapshots []*Snapshot
+	workers   *goutils.StoppableWorkers
+}
+
+// NewSnapshotHistory returns a history of snapshots of the frame system of the service, discarding those older than window. It is
+// timed by the given clock, or by the wall clock if it is nil.
+func NewSnapshotHistory(svc Service, window time.Duration, clk clock.Clock) *SnapshotHistory {
+	if clk == nil {
+		clk = clock.New()
+	}
+	return &SnapshotHistory{svc: svc, window: window, clock: clk}
+}
+
+// Capture captures the current state of the frame system and adds it to the history.
+func (h *SnapshotHistory) Capture(ctx context.Context) (*Snapshot, error) {
+	snapshot, err := CaptureSnapshot(ctx, h.svc, nil, h.clock)
+	if err != nil {
+		return nil, err
+	}
+	h.Add(snapshot)
+	return snapshot, nil
+}
+
+// Add adds a snapshot to the history, discarding any which have fallen out of its window.
+func (h *SnapshotHistory) Add(snapshot *Snapshot) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	i := sort.Search(len(h.snapshots), func(i int) bool { return h.snapshots[i].Time.After(snapshot.Time) })
+	h.snapshots = append(h.snapshots, nil)
+	copy(h.snapshots[i+1:], h.snapshots[i:])
+	h.snapshots[i] = snapshot
+
+	oldest := h.clock.Now().Add(-h.window)
+	expired := sort.Search(len(h.snapshots), func(i int) bool { return !h.snapshots[i].Time.Before(oldest) })
+	h.snapshots = h.snapshots[expired:]
+}
+
+// StartRecording captures a snapshot every interval in the background until the history is closed. Snapshots which fail to be
+// captured are logged and skipped.
+func (h *SnapshotHistory) StartRecording(interval time.Duration, logger logging.Logger) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.workers != nil {
+		return
+	}
+	h.workers = goutils.NewBackgroundStoppableWorkers(func(ctx context.Context) {
+		ticker := h.clock.Ticker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := h.Capture(ctx); err != nil && ctx.Err() == nil {
+					logger.CDebugf(ctx, "could not capture frame system snapshot: %v", err)
+				}
+			}
+		}
+	})
+}
+
+// Close stops recording snapshots.
+func (h *SnapshotHistory) Close() {
+	h.mu.Lock()
+	workers := h.workers
+	h.workers = nil
+	h.mu.Unlock()
+	if workers != nil {
+		workers.Stop()
+	}
+}
+
+// At returns the state of the frame system at the given time. Times between two snapshots have the inputs of the frames interpolated
+// between them, and times outside of the span of the history are an error.
+func (h *SnapshotHistory) At(t time.Time) (*Snapshot, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if len(h.snapshots) == 0 {
+		return nil, errors.New("no frame system snapshots have been captured")
+	}
+	first, last := h.snapshots[0], h.snapshots[len(h.snapshots)-1]
+	if t.Before(first.Time) || t.After(last.Time) {
+		return nil, errors.Errorf("no frame system snapshot at %v, snapshots span %v to %v", t, first.Time, last.Time)
+	}
+	i := sort.Search(len(h.snapshots), func(i int) bool { return !h.snapshots[i].Time.Before(t) })
+	after := h.snapshots[i]
+	if after.Time.Equal(t) {
+		return after, nil
+	}
+	before := h.snapshots[i-1]
+	by := float64(t.Sub(before.Time)) / float64(after.Time.Sub(before.Time))
+	inputs, err := referenceframe.InterpolateFS(before.FrameSystem, before.Inputs, after.Inputs, by)
+	if err != nil {
+		// the frames of the frame system changed between the snapshots, so its state is only known as of the earlier one
+		return &Snapshot{Time: t, FrameSystem: before.FrameSystem, Inputs: before.Inputs}, nil
+	}
+	// frames without inputs are not interpolated, but are still needed to transform through them
+	for name, frameInputs := range before.Inputs {
+		if _, ok := inputs[name]; !ok {
+			inputs[name] = frameInputs
+		}
+	}
+	return &Snapshot{Time: t, FrameSystem: before.FrameSystem, Inputs: inputs}, nil
+}
+
+// Between returns the snapshots captured from start to end inclusive, in the order they were captured, so that the motion of the
+// frame system over that span may be replayed.
+func (h *SnapshotHistory) Between(start, end time.Time) []*Snapshot {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	from := sort.Search(len(h.snapshots), func(i int) bool { return !h.snapshots[i].Time.Before(start) })
+	to := sort.Search(len(h.snapshots), func(i int) bool { return h.snapshots[i].Time.After(end) })
+	if from >= to {
+		return nil
+	}
+	return append([]*Snapshot{}, h.snapshots[from:to]...)
+}
+
+// TransformPose transforms the pose to the destination frame as the frame system was at the given time.
+func (h *SnapshotHistory) TransformPose(t time.Time, pose *referenceframe.PoseInFrame, dst string) (*referenceframe.PoseInFrame, error) {
+	snapshot, err := h.At(t)
+	if err != nil {
+		return nil, err
+	}
+	return snapshot.TransformPose(pose, dst)
+}
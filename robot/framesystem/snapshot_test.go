@@ -0,0 +1,89 @@
+package framesystem_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/robot/framesystem"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestSnapshotHistory(t *testing.T) {
+	ctx := context.Background()
+	fs := referenceframe.NewEmptyFrameSystem("test")
+	gantry, err := referenceframe.NewTranslationalFrame("gantry", r3.Vector{X: 1}, referenceframe.Limit{Min: -1000, Max: 1000})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(gantry, fs.World()), test.ShouldBeNil)
+	gripper, err := referenceframe.NewStaticFrame("gripper", spatialmath.NewPoseFromPoint(r3.Vector{Z: 100}))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(gripper, gantry), test.ShouldBeNil)
+
+	position := 0.
+	svc := &inject.FrameSystemService{}
+	svc.FrameSystemFunc = func(context.Context, []*referenceframe.LinkInFrame) (referenceframe.FrameSystem, error) {
+		return fs, nil
+	}
+	svc.CurrentInputsFunc = func(context.Context) (referenceframe.FrameSystemInputs, map[string]framesystem.InputEnabled, error) {
+		inputs := referenceframe.NewZeroInputs(fs)
+		inputs["gantry"] = []referenceframe.Input{{Value: position}}
+		return inputs, nil, nil
+	}
+
+	clk := clock.NewMock()
+	history := framesystem.NewSnapshotHistory(svc, 10*time.Second, clk)
+	_, err = history.At(clk.Now())
+	test.That(t, err, test.ShouldNotBeNil)
+
+	start := clk.Now()
+	for i := 0; i < 4; i++ {
+		position = float64(i) * 100
+		_, err := history.Capture(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		clk.Add(time.Second)
+	}
+	gripperOrigin := referenceframe.NewPoseInFrame("gripper", spatialmath.NewZeroPose())
+
+	t.Run("queries where frames were at captured instants", func(t *testing.T) {
+		pif, err := history.TransformPose(start.Add(2*time.Second), gripperOrigin, referenceframe.World)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, pif.Pose().Point().X, test.ShouldAlmostEqual, 200)
+		test.That(t, pif.Pose().Point().Z, test.ShouldAlmostEqual, 100)
+	})
+
+	t.Run("interpolates between snapshots", func(t *testing.T) {
+		pif, err := history.TransformPose(start.Add(1500*time.Millisecond), gripperOrigin, referenceframe.World)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, pif.Pose().Point().X, test.ShouldAlmostEqual, 150)
+	})
+
+	t.Run("times outside the history are an error", func(t *testing.T) {
+		_, err := history.At(start.Add(-time.Second))
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = history.At(clk.Now())
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("replays snapshots in order", func(t *testing.T) {
+		snapshots := history.Between(start.Add(time.Second), start.Add(2*time.Second))
+		test.That(t, len(snapshots), test.ShouldEqual, 2)
+		test.That(t, snapshots[0].Inputs["gantry"][0].Value, test.ShouldEqual, 100)
+		test.That(t, snapshots[1].Inputs["gantry"][0].Value, test.ShouldEqual, 200)
+		test.That(t, history.Between(start.Add(time.Hour), start.Add(2*time.Hour)), test.ShouldBeEmpty)
+	})
+
+	t.Run("discards snapshots outside the window", func(t *testing.T) {
+		clk.Add(8 * time.Second)
+		_, err := history.Capture(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		snapshots := history.Between(start, clk.Now())
+		test.That(t, len(snapshots), test.ShouldEqual, 3)
+		test.That(t, snapshots[0].Time, test.ShouldEqual, start.Add(2*time.Second))
+	})
+}
@@ -0,0 +1,169 @@
+package framesystem
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// poseTracker is the part of a pose tracker component the frame system reads tracked frames from. It is declared here, rather than
+// imported, as the pose tracker package depends on this one.
+type poseTracker interface {
+	resource.Resource
+	Poses(ctx context.Context, bodyNames []string, extra map[string]interface{}) (referenceframe.FrameSystemPoses, error)
+}
+
+// trackedFrame is a frame whose pose relative to its parent is that of a body most recently seen by a pose tracker, such as a tag on
+// a cart. Its inputs are the seven values of that pose, read from the pose tracker whenever the inputs of the frame system are.
+type trackedFrame struct {
+	name    string
+	parent  string
+	body    string
+	tracker poseTracker
+}
+
+// part returns the frame system part of the tracked frame.
+func (tf *trackedFrame) part() (*referenceframe.FrameSystemPart, error) {
+	pose, err := referenceframe.NewPoseFrame("pose", nil)
+	if err != nil {
+		return nil, err
+	}
+	model := referenceframe.NewSimpleModel(tf.name)
+	model.OrdTransforms = []referenceframe.Frame{pose}
+	return &referenceframe.FrameSystemPart{
+		FrameConfig: referenceframe.NewLinkInFrame(tf.parent, spatialmath.NewZeroPose(), tf.name, nil),
+		ModelFrame:  model,
+	}, nil
+}
+
+// CurrentInputs returns the latest pose of the tracked body as the inputs of the frame.
+func (tf *trackedFrame) CurrentInputs(ctx context.Context) ([]referenceframe.Input, error) {
+	poses, err := tf.tracker.Poses(ctx, []string{tf.body}, nil)
+	if err != nil {
+		return nil, err
+	}
+	pose, ok := poses[tf.body]
+	if !ok || pose == nil {
+		return nil, BodyNotTrackedError(tf.tracker.Name().ShortName(), tf.body)
+	}
+	if pose.Parent() != tf.parent {
+		return nil, errors.Errorf(
+			"pose tracker %v gave the pose of body %v in frame %v rather than %v", tf.tracker.Name().ShortName(), tf.body, pose.Parent(), tf.parent,
+		)
+	}
+	return referenceframe.PoseToInputs(pose.Pose()), nil
+}
+
+// GoToInputs does nothing, as a tracked frame is moved by whatever carries its body rather than through the frame system.
+func (tf *trackedFrame) GoToInputs(ctx context.Context, inputs ...[]referenceframe.Input) error {
+	return nil
+}
+
+// AddTrackedFrame adds a frame with the given name to the frame system, whose pose is the latest pose of the body as seen by the pose
+// tracker. The frame is a child of the frame the pose tracker gives the pose of the body in, and stays in the frame system until it is
+// removed.
+func (svc *frameSystemService) AddTrackedFrame(ctx context.Context, name, poseTrackerName, body string) error {
+	svc.partsMu.RLock()
+	component, ok := svc.components[poseTrackerName]
+	svc.partsMu.RUnlock()
+	if !ok {
+		return DependencyNotFoundError(poseTrackerName)
+	}
+	tracker, ok := component.(poseTracker)
+	if !ok {
+		return NotPoseTrackerError(component)
+	}
+	poses, err := tracker.Poses(ctx, []string{body}, nil)
+	if err != nil {
+		return err
+	}
+	pose, ok := poses[body]
+	if !ok || pose == nil {
+		return BodyNotTrackedError(poseTrackerName, body)
+	}
+	fs, err := svc.FrameSystem(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if fs.Frame(pose.Parent()) == nil {
+		return referenceframe.NewFrameMissingError(pose.Parent())
+	}
+	if fs.Frame(name) != nil {
+		return DuplicateFrameNameError(name)
+	}
+
+	svc.partsMu.Lock()
+	defer svc.partsMu.Unlock()
+	for _, tracked := range svc.tracked {
+		if tracked.name == name {
+			return DuplicateFrameNameError(name)
+		}
+	}
+	svc.tracked = append(svc.tracked, &trackedFrame{name: name, parent: pose.Parent(), body: body, tracker: tracker})
+	svc.static = newStaticTransformCache()
+	return nil
+}
+
+// RemoveTrackedFrame removes the tracked frame with the given name, along with any geometries attached to it, from the frame system.
+func (svc *frameSystemService) RemoveTrackedFrame(ctx context.Context, name string) error {
+	svc.partsMu.Lock()
+	defer svc.partsMu.Unlock()
+	remaining := make([]*trackedFrame, 0, len(svc.tracked))
+	for _, tracked := range svc.tracked {
+		if tracked.name != name {
+			remaining = append(remaining, tracked)
+		}
+	}
+	if len(remaining) == len(svc.tracked) {
+		return TrackedFrameNotFoundError(name)
+	}
+	svc.tracked = remaining
+	parts, err := svc.allParts()
+	if err != nil {
+		return err
+	}
+	svc.attached = attachedTo(parts, svc.attached)
+	svc.static = newStaticTransformCache()
+	return nil
+}
+
+// allParts returns the configured parts of the frame system followed by those of its tracked frames. The caller must hold partsMu.
+func (svc *frameSystemService) allParts() ([]*referenceframe.FrameSystemPart, error) {
+	parts := make([]*referenceframe.FrameSystemPart, 0, len(svc.parts)+len(svc.tracked))
+	parts = append(parts, svc.parts...)
+	for _, tracked := range svc.tracked {
+		part, err := tracked.part()
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, part)
+	}
+	return parts, nil
+}
+
+// trackedBy returns the tracked frames which can still be tracked by the given parts and components: those whose parent frame is
+// among the parts, directly or through other tracked frames, and whose pose tracker is among the components.
+func trackedBy(
+	parts []*referenceframe.FrameSystemPart,
+	components map[string]resource.Resource,
+	tracked []*trackedFrame,
+) []*trackedFrame {
+	frames := map[string]bool{referenceframe.World: true}
+	for _, part := range parts {
+		frames[part.FrameConfig.Name()] = true
+	}
+	remaining := make([]*trackedFrame, 0, len(tracked))
+	for _, tf := range tracked {
+		tracker, ok := components[tf.tracker.Name().ShortName()].(poseTracker)
+		if !ok || !frames[tf.parent] {
+			continue
+		}
+		frames[tf.name] = true
+		remaining = append(remaining, &trackedFrame{name: tf.name, parent: tf.parent, body: tf.body, tracker: tracker})
+	}
+	return remaining
+}
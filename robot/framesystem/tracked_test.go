@@ -0,0 +1,135 @@
+package framesystem
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+// fakeTracker is a pose tracker which sees each of its bodies at the pose it was last given, in the frame of the camera.
+type fakeTracker struct {
+	resource.Named
+	resource.TriviallyReconfigurable
+	resource.TriviallyCloseable
+	bodies map[string]spatialmath.Pose
+}
+
+func (ft *fakeTracker) Poses(
+	ctx context.Context,
+	bodyNames []string,
+	extra map[string]interface{},
+) (referenceframe.FrameSystemPoses, error) {
+	poses := referenceframe.FrameSystemPoses{}
+	for _, body := range bodyNames {
+		if pose, ok := ft.bodies[body]; ok {
+			poses[body] = referenceframe.NewPoseInFrame("camera", pose)
+		}
+	}
+	return poses, nil
+}
+
+func TestTrackedFrames(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+
+	model, err := referenceframe.ParseModelJSONFile(utils.ResolveFile("components/arm/example_kinematics/xarm6_kinematics_test.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+	armName := resource.NewName(resource.APINamespaceRDK.WithComponentType("arm"), "arm")
+	arm := &countingArm{Named: armName.AsNamed(), dof: len(model.DoF())}
+	trackerName := resource.NewName(resource.APINamespaceRDK.WithComponentType("pose_tracker"), "tracker")
+	tracker := &fakeTracker{Named: trackerName.AsNamed(), bodies: map[string]spatialmath.Pose{
+		"tag": spatialmath.NewPoseFromPoint(r3.Vector{X: 100}),
+	}}
+	deps := resource.Dependencies{armName: arm, trackerName: tracker}
+	parts := []*referenceframe.FrameSystemPart{
+		{
+			FrameConfig: referenceframe.NewLinkInFrame(referenceframe.World, spatialmath.NewZeroPose(), "arm", nil),
+			ModelFrame:  model,
+		},
+		{
+			FrameConfig: referenceframe.NewLinkInFrame(referenceframe.World, spatialmath.NewPoseFromPoint(r3.Vector{Z: 500}), "camera", nil),
+		},
+	}
+	svc, err := New(ctx, deps, logger)
+	test.That(t, err, test.ShouldBeNil)
+	reconfigure := func(parts []*referenceframe.FrameSystemPart) {
+		test.That(t, svc.Reconfigure(ctx, deps, resource.Config{ConvertedAttributes: &Config{Parts: parts}}), test.ShouldBeNil)
+	}
+	reconfigure(parts)
+	// the point in the world of the origin of the named frame
+	inWorld := func(name string) r3.Vector {
+		pose, err := svc.TransformPose(ctx, referenceframe.NewZeroPoseInFrame(name), referenceframe.World, nil)
+		test.That(t, err, test.ShouldBeNil)
+		return pose.Pose().Point()
+	}
+
+	t.Run("frames are only tracked from bodies seen by pose trackers", func(t *testing.T) {
+		test.That(t, svc.AddTrackedFrame(ctx, "cart", "missing", "tag"), test.ShouldBeError, DependencyNotFoundError("missing"))
+		test.That(t, svc.AddTrackedFrame(ctx, "cart", "arm", "tag"), test.ShouldBeError, NotPoseTrackerError(arm))
+		test.That(t, svc.AddTrackedFrame(ctx, "cart", "tracker", "dock"), test.ShouldBeError, BodyNotTrackedError("tracker", "dock"))
+		test.That(t, svc.AddTrackedFrame(ctx, "camera", "tracker", "tag"), test.ShouldBeError, DuplicateFrameNameError("camera"))
+		test.That(t, svc.RemoveTrackedFrame(ctx, "cart"), test.ShouldBeError, TrackedFrameNotFoundError("cart"))
+	})
+
+	t.Run("tracked frames follow their bodies", func(t *testing.T) {
+		test.That(t, svc.AddTrackedFrame(ctx, "cart", "tracker", "tag"), test.ShouldBeNil)
+		test.That(t, svc.AddTrackedFrame(ctx, "cart", "tracker", "tag"), test.ShouldBeError, DuplicateFrameNameError("cart"))
+		test.That(t, spatialmath.R3VectorAlmostEqual(inWorld("cart"), r3.Vector{X: 100, Z: 500}, 1e-8), test.ShouldBeTrue)
+
+		// goals relative to the cart are resolved against wherever it was last seen
+		tracker.bodies["tag"] = spatialmath.NewPoseFromPoint(r3.Vector{X: 250, Y: -50})
+		goal := referenceframe.NewPoseInFrame("cart", spatialmath.NewPoseFromPoint(r3.Vector{Z: 10}))
+		transformed, err := svc.TransformPose(ctx, goal, referenceframe.World, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, spatialmath.R3VectorAlmostEqual(transformed.Pose().Point(), r3.Vector{X: 250, Y: -50, Z: 510}, 1e-8), test.ShouldBeTrue)
+
+		// the cart is read along with the inputs of the rest of the machine, but is not moved by the frame system
+		inputs, resources, err := svc.CurrentInputs(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, inputs["cart"], test.ShouldResemble, referenceframe.PoseToInputs(tracker.bodies["tag"]))
+		test.That(t, resources, test.ShouldContainKey, "cart")
+		test.That(t, resources["cart"].GoToInputs(ctx, referenceframe.PoseToInputs(spatialmath.NewZeroPose())), test.ShouldBeNil)
+		test.That(t, spatialmath.R3VectorAlmostEqual(inWorld("cart"), r3.Vector{X: 250, Y: -50, Z: 500}, 1e-8), test.ShouldBeTrue)
+
+		// bodies which are lost leave the frame system without a pose for their frames
+		delete(tracker.bodies, "tag")
+		_, err = svc.TransformPose(ctx, goal, referenceframe.World, nil)
+		test.That(t, err, test.ShouldBeError, BodyNotTrackedError("tracker", "tag"))
+		tracker.bodies["tag"] = spatialmath.NewPoseFromPoint(r3.Vector{X: 100})
+	})
+
+	t.Run("removing a tracked frame detaches what is attached to it", func(t *testing.T) {
+		box, err := spatialmath.NewBox(spatialmath.NewZeroPose(), r3.Vector{X: 100, Y: 100, Z: 100}, "crate")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, svc.AttachGeometry(ctx, "cart", box), test.ShouldBeNil)
+		test.That(t, spatialmath.R3VectorAlmostEqual(inWorld("crate"), r3.Vector{X: 100, Z: 500}, 1e-8), test.ShouldBeTrue)
+
+		test.That(t, svc.RemoveTrackedFrame(ctx, "cart"), test.ShouldBeNil)
+		fs, err := svc.FrameSystem(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, fs.Frame("cart"), test.ShouldBeNil)
+		test.That(t, fs.Frame("crate"), test.ShouldBeNil)
+		test.That(t, svc.DetachGeometry(ctx, "crate"), test.ShouldBeError, AttachedGeometryNotFoundError("crate"))
+	})
+
+	t.Run("frames stay tracked through reconfiguration while their parents remain", func(t *testing.T) {
+		test.That(t, svc.AddTrackedFrame(ctx, "cart", "tracker", "tag"), test.ShouldBeNil)
+		reconfigure(parts)
+		fs, err := svc.FrameSystem(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, fs.Frame("cart"), test.ShouldNotBeNil)
+
+		reconfigure(parts[:1])
+		fs, err = svc.FrameSystem(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, fs.Frame("cart"), test.ShouldBeNil)
+	})
+}
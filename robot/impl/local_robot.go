@@ -35,6 +35,7 @@ import (
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/robot"
 	"go.viam.com/rdk/robot/client"
+	"go.viam.com/rdk/robot/estop"
 	"go.viam.com/rdk/robot/framesystem"
 	"go.viam.com/rdk/robot/packages"
 	"go.viam.com/rdk/robot/web"
@@ -83,8 +84,10 @@ type localRobot struct {
 	configRevisionMu sync.RWMutex
 
 	// internal services that are in the graph but we also hold onto
-	webSvc   web.Service
-	frameSvc framesystem.Service
+	webSvc       web.Service
+	frameSvc     framesystem.Service
+	eStopSvc     estop.Service
+	reservations *resource.Reservations
 
 	// map keyed by Module.Name. This is necessary to get the package manager to use a new folder
 	// when a local tarball is updated.
@@ -466,6 +469,8 @@ func newWithResources(
 	if err != nil {
 		return nil, err
 	}
+	r.eStopSvc = estop.New(logger)
+	r.reservations = resource.NewReservations()
 
 	// now that we're changing the resource graph, take the reconfigurationLock so
 	// that other goroutines can't interleave
@@ -481,6 +486,16 @@ func newWithResources(
 		resource.NewConfiguredGraphNode(resource.Config{}, r.frameSvc, builtinModel)); err != nil {
 		return nil, err
 	}
+	if err := r.manager.resources.AddNode(
+		estop.InternalServiceName,
+		resource.NewConfiguredGraphNode(resource.Config{}, r.eStopSvc, builtinModel)); err != nil {
+		return nil, err
+	}
+	if err := r.manager.resources.AddNode(
+		resource.ReservationsInternalName,
+		resource.NewConfiguredGraphNode(resource.Config{}, r.reservations, builtinModel)); err != nil {
+		return nil, err
+	}
 	if err := r.manager.resources.AddNode(
 		r.packageManager.Name(),
 		resource.NewConfiguredGraphNode(resource.Config{}, r.packageManager, builtinModel)); err != nil {
@@ -754,7 +769,8 @@ func (r *localRobot) updateWeakDependents(ctx context.Context) {
 				if err := res.Reconfigure(ctxWithTimeout, components, resource.Config{ConvertedAttributes: fsCfg}); err != nil {
 					r.Logger().CErrorw(ctx, "failed to reconfigure internal service during weak dependencies update", "service", resName, "error", err)
 				}
-			case packages.InternalServiceName, packages.DeferredServiceName, icloud.InternalServiceName:
+			case packages.InternalServiceName, packages.DeferredServiceName, icloud.InternalServiceName, estop.InternalServiceName,
+				resource.ReservationsInternalName:
 			default:
 				r.logger.CWarnw(ctx, "do not know how to reconfigure internal service during weak dependencies update", "service", resName)
 			}
@@ -1576,6 +1592,16 @@ func (r *localRobot) checkMaintenanceSensorReadings(ctx context.Context,
 	return canReconfigure, nil
 }
 
+// EStop returns the software e-stop of the robot.
+func (r *localRobot) EStop() estop.Service {
+	return r.eStopSvc
+}
+
+// Reservations returns the reservations on the resources of the robot.
+func (r *localRobot) Reservations() *resource.Reservations {
+	return r.reservations
+}
+
 // RestartAllowed returns whether the robot can safely be restarted. The robot
 // can be safely restarted if the robot is not in the middle of a reconfigure,
 // and a reconfigure would be allowed.
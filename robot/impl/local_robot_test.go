@@ -54,6 +54,7 @@ import (
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/robot"
 	"go.viam.com/rdk/robot/client"
+	"go.viam.com/rdk/robot/estop"
 	"go.viam.com/rdk/robot/framesystem"
 	"go.viam.com/rdk/robot/packages"
 	putils "go.viam.com/rdk/robot/packages/testutils"
@@ -1459,6 +1460,46 @@ func TestConfigMethod(t *testing.T) {
 	test.That(t, actualCfg, test.ShouldResemble, &expectedCfg)
 }
 
+func TestEStop(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	r := setupLocalRobot(t, ctx, &config.Config{}, logger)
+	other := setupLocalRobot(t, ctx, &config.Config{}, logger)
+
+	ms, err := motion.FromRobot(r, resource.DefaultServiceName)
+	test.That(t, err, test.ShouldBeNil)
+	otherMS, err := motion.FromRobot(other, resource.DefaultServiceName)
+	test.That(t, err, test.ShouldBeNil)
+
+	// engaging the e-stop of a robot stops the motion service of that robot alone
+	test.That(t, r.EStop().Engage(ctx, "person in cell"), test.ShouldBeNil)
+	_, err = ms.Move(ctx, motion.MoveReq{ComponentName: arm.Named("arm")})
+	test.That(t, estop.IsEngagedError(err), test.ShouldBeTrue)
+	_, err = otherMS.Move(ctx, motion.MoveReq{ComponentName: arm.Named("arm")})
+	test.That(t, estop.IsEngagedError(err), test.ShouldBeFalse)
+	test.That(t, other.EStop().Check(), test.ShouldBeNil)
+
+	// the motion service resets the e-stop of its robot
+	_, err = ms.DoCommand(ctx, map[string]interface{}{motionBuiltin.DoResetEStop: true})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, r.EStop().Check(), test.ShouldBeNil)
+}
+
+func TestReservations(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	r := setupLocalRobot(t, ctx, &config.Config{}, logger)
+	other := setupLocalRobot(t, ctx, &config.Config{}, logger)
+
+	// reserving a component of a robot leaves the component of the same name on another robot free
+	reservation := r.Reservations().Reserve(arm.Named("arm"), "motion execution 1")
+	test.That(t, resource.IsReservedError(r.Reservations().Check(arm.Named("arm"), nil)), test.ShouldBeTrue)
+	test.That(t, other.Reservations().Check(arm.Named("arm"), nil), test.ShouldBeNil)
+
+	reservation.Release()
+	test.That(t, r.Reservations().Check(arm.Named("arm"), nil), test.ShouldBeNil)
+}
+
 func TestCheckMaxInstanceValid(t *testing.T) {
 	logger := logging.NewTestLogger(t)
 	cfg := &config.Config{
@@ -2998,6 +3039,20 @@ func getExpectedDefaultStatuses(revision string, md cloud.Metadata) []resource.S
 			},
 			CloudMetadata: md,
 		},
+		{
+			NodeStatus: resource.NodeStatus{
+				Name:  estop.InternalServiceName,
+				State: resource.NodeStateReady,
+			},
+			CloudMetadata: md,
+		},
+		{
+			NodeStatus: resource.NodeStatus{
+				Name:  resource.ReservationsInternalName,
+				State: resource.NodeStateReady,
+			},
+			CloudMetadata: md,
+		},
 		{
 			NodeStatus: resource.NodeStatus{
 				Name: resource.Name{
@@ -20,6 +20,7 @@ import (
 	"go.viam.com/rdk/pointcloud"
 	"go.viam.com/rdk/referenceframe"
 	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot/estop"
 	"go.viam.com/rdk/robot/framesystem"
 	"go.viam.com/rdk/robot/packages"
 	weboptions "go.viam.com/rdk/robot/web/options"
@@ -191,6 +192,13 @@ type LocalRobot interface {
 	// RestartAllowed returns whether the robot can safely be restarted.
 	RestartAllowed() bool
 
+	// EStop returns the software e-stop of the robot, which stops the motion service and everything it moves while engaged.
+	EStop() estop.Service
+
+	// Reservations returns the reservations on the resources of the robot, such as those the motion service holds on the
+	// components it moves, which refuse commands to the reserved resources from clients of the robot.
+	Reservations() *resource.Reservations
+
 	// Kill will attempt to kill any processes on the system started by the robot as quickly as possible.
 	// This operation is not clean and will not wait for completion.
 	// Only use this if comfortable with leaking resources (in cases where exiting the program as quickly as possible is desired).
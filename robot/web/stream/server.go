@@ -18,7 +18,9 @@ import (
 
 	"go.viam.com/rdk/components/audioinput"
 	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/config"
 	"go.viam.com/rdk/gostream"
+	"go.viam.com/rdk/gostream/codec"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/robot"
@@ -57,9 +59,10 @@ type Server struct {
 	activeBackgroundWorkers sync.WaitGroup
 	isAlive                 bool
 
-	streamConfig gostream.StreamConfig
-	videoSources map[string]gostream.HotSwappableVideoSource
-	audioSources map[string]gostream.HotSwappableAudioSource
+	streamConfig  gostream.StreamConfig
+	cameraStreams map[string]config.CameraStreamConfig
+	videoSources  map[string]gostream.HotSwappableVideoSource
+	audioSources  map[string]gostream.HotSwappableAudioSource
 }
 
 // Resolution holds the width and height of a video stream.
@@ -69,12 +72,17 @@ type Resolution struct {
 }
 
 // NewServer returns a server that will run on the given port and initially starts with the given
-// stream.
+// stream. The video of the cameras in cameraStreams is encoded as they configure.
 func NewServer(
 	robot robot.Robot,
 	streamConfig gostream.StreamConfig,
+	cameraStreams []config.CameraStreamConfig,
 	logger logging.Logger,
 ) *Server {
+	cameraStreamsByName := make(map[string]config.CameraStreamConfig, len(cameraStreams))
+	for _, cameraStream := range cameraStreams {
+		cameraStreamsByName[cameraStream.Camera] = cameraStream
+	}
 	closedCtx, closedFn := context.WithCancel(context.Background())
 	server := &Server{
 		closedCtx:         closedCtx,
@@ -85,6 +93,7 @@ func NewServer(
 		activePeerStreams: map[*webrtc.PeerConnection]map[string]*peerState{},
 		isAlive:           true,
 		streamConfig:      streamConfig,
+		cameraStreams:     cameraStreamsByName,
 		videoSources:      map[string]gostream.HotSwappableVideoSource{},
 		audioSources:      map[string]gostream.HotSwappableAudioSource{},
 	}
@@ -511,7 +520,7 @@ func (server *Server) AddNewStreams(ctx context.Context) error {
 		// "started".
 		config := gostream.StreamConfig{
 			Name:                name,
-			VideoEncoderFactory: server.streamConfig.VideoEncoderFactory,
+			VideoEncoderFactory: server.videoEncoderFactory(name),
 		}
 		// Call `createStream`. `createStream` is responsible for first checking if the stream
 		// already exists. If it does, it skips creating a new stream and we continue to the next source.
@@ -524,6 +533,7 @@ func (server *Server) AddNewStreams(ctx context.Context) error {
 			continue
 		}
 		server.startVideoStream(ctx, server.videoSources[name], stream)
+		server.applyConfiguredResolution(ctx, name)
 	}
 
 	for name := range server.audioSources {
@@ -546,6 +556,45 @@ func (server *Server) AddNewStreams(ctx context.Context) error {
 	return nil
 }
 
+// videoEncoderFactory returns the factory of the encoders of the named camera's stream, which target the bitrate of its stream
+// config when the encoders support it.
+func (server *Server) videoEncoderFactory(name string) codec.VideoEncoderFactory {
+	cameraStream, ok := server.cameraStreams[name]
+	if !ok || cameraStream.BitrateBPS == 0 {
+		return server.streamConfig.VideoEncoderFactory
+	}
+	factory, ok := server.streamConfig.VideoEncoderFactory.(codec.BitrateVideoEncoderFactory)
+	if !ok {
+		server.logger.Warnf("the video encoder of stream %q does not support setting its bitrate, using its default bitrate", name)
+		return server.streamConfig.VideoEncoderFactory
+	}
+	return factory.WithBitrate(cameraStream.BitrateBPS)
+}
+
+// applyConfiguredResolution resizes the video of the named camera to the resolution of its stream config, if it has one.
+func (server *Server) applyConfiguredResolution(ctx context.Context, name string) {
+	cameraStream, ok := server.cameraStreams[name]
+	if !ok || cameraStream.WidthPx == 0 {
+		return
+	}
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if err := server.resizeVideoSource(ctx, name, cameraStream.WidthPx, cameraStream.HeightPx); err != nil {
+		server.logger.Warnw("failed to resize stream to its configured resolution", "name", name, "error", err)
+	}
+}
+
+// Stats returns the video statistics of each stream, by name.
+func (server *Server) Stats() any {
+	server.mu.RLock()
+	defer server.mu.RUnlock()
+	stats := make(map[string]gostream.VideoStats, len(server.nameToStreamState))
+	for name, streamState := range server.nameToStreamState {
+		stats[name] = streamState.Stream.VideoStats()
+	}
+	return stats
+}
+
 // Close closes the Server and waits for spun off goroutines to complete.
 func (server *Server) Close() error {
 	server.closedFn()
@@ -81,6 +81,11 @@ func (mS *mockStream) AudioTrackLocal() (webrtc.TrackLocal, bool) {
 	return nil, false
 }
 
+func (mS *mockStream) VideoStats() gostream.VideoStats {
+	test.That(mS.t, "should not be called", test.ShouldBeFalse)
+	return gostream.VideoStats{}
+}
+
 type mockRTPPassthroughSource struct {
 	subscribeRTPFunc func(
 		ctx context.Context,
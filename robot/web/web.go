@@ -230,6 +230,11 @@ func (svc *webService) StartModule(ctx context.Context) error {
 	unaryInterceptors = append(unaryInterceptors,
 		opManager.UnaryServerInterceptor, logging.UnaryServerInterceptor)
 	streamInterceptors = append(streamInterceptors, opManager.StreamServerInterceptor)
+	if localRobot, isLocal := svc.r.(robot.LocalRobot); isLocal {
+		reservations := localRobot.Reservations()
+		unaryInterceptors = append(unaryInterceptors, reservations.UnaryServerInterceptor)
+		streamInterceptors = append(streamInterceptors, reservations.StreamServerInterceptor)
+	}
 	// TODO(PRODUCT-343): Add session manager interceptors
 
 	opts := []googlegrpc.ServerOption{
@@ -421,7 +426,7 @@ func (svc *webService) runWeb(ctx context.Context, options weboptions.Options) (
 		return err
 	}
 
-	if err := svc.initStreamServer(ctx); err != nil {
+	if err := svc.initStreamServer(ctx, options.Network.Streams); err != nil {
 		return err
 	}
 
@@ -640,6 +645,13 @@ func (svc *webService) initRPCOptions(listenerTCPAddr *net.TCPAddr, options webo
 	}
 	streamInterceptors = append(streamInterceptors, opManager.StreamServerInterceptor)
 
+	// attach the reservations of the robot to every request so that resource servers refuse commands to reserved resources
+	if localRobot, isLocal := svc.r.(robot.LocalRobot); isLocal {
+		reservations := localRobot.Reservations()
+		unaryInterceptors = append(unaryInterceptors, reservations.UnaryServerInterceptor)
+		streamInterceptors = append(streamInterceptors, reservations.StreamServerInterceptor)
+	}
+
 	rpcOpts = append(
 		rpcOpts,
 		rpc.WithUnknownServiceHandler(svc.foreignServiceHandler),
@@ -1012,6 +1024,7 @@ func (svc *webService) foreignServiceHandler(srv interface{}, stream googlegrpc.
 
 type stats struct {
 	RPCServer any
+	Streams   any
 }
 
 // Stats returns ftdc data on behalf of the rpcServer and other web services.
@@ -1021,7 +1034,11 @@ func (svc *webService) Stats() any {
 	svc.mu.Lock()
 	defer svc.mu.Unlock()
 
-	return stats{svc.rpcServer.Stats()}
+	ret := stats{RPCServer: svc.rpcServer.Stats()}
+	if svc.streamServer != nil {
+		ret.Streams = svc.streamServer.Stats()
+	}
+	return ret
 }
 
 // RestartStatusResponse is the JSON response of the `restart_status` HTTP
@@ -10,6 +10,7 @@ import (
 	"github.com/pkg/errors"
 	streampb "go.viam.com/api/stream/v1"
 
+	"go.viam.com/rdk/config"
 	"go.viam.com/rdk/gostream"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/resource"
@@ -54,7 +55,7 @@ func (svc *webService) closeStreamServer() {
 	}
 }
 
-func (svc *webService) initStreamServer(ctx context.Context) error {
+func (svc *webService) initStreamServer(ctx context.Context, cameraStreams []config.CameraStreamConfig) error {
 	// Check to make sure stream config option is set in the webservice.
 	var streamConfig gostream.StreamConfig
 	if svc.opts.streamConfig != nil {
@@ -62,7 +63,7 @@ func (svc *webService) initStreamServer(ctx context.Context) error {
 	} else {
 		svc.logger.Warn("streamConfig is nil, using empty config")
 	}
-	svc.streamServer = webstream.NewServer(svc.r, streamConfig, svc.logger)
+	svc.streamServer = webstream.NewServer(svc.r, streamConfig, cameraStreams, svc.logger)
 	if err := svc.streamServer.AddNewStreams(svc.cancelCtx); err != nil {
 		return err
 	}
@@ -5,6 +5,7 @@ package web
 import (
 	"context"
 
+	"go.viam.com/rdk/config"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/robot"
@@ -41,7 +42,7 @@ func (svc *webService) Reconfigure(ctx context.Context, deps resource.Dependenci
 func (svc *webService) closeStreamServer() {}
 
 // stub implementation when gostream not available
-func (svc *webService) initStreamServer(ctx context.Context) error {
+func (svc *webService) initStreamServer(ctx context.Context, cameraStreams []config.CameraStreamConfig) error {
 	return nil
 }
 
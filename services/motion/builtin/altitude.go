@@ -0,0 +1,86 @@
+package builtin
+
+import (
+	"fmt"
+	"math"
+
+	geo "github.com/kellydunn/golang-geo"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+const (
+	// destinationAltitudeExtraKey is the key of extra through which MoveOnGlobe is given the altitude of its destination, in meters.
+	// The distance to a destination above or below the base is then estimated along the slope to it rather than across the ground.
+	destinationAltitudeExtraKey = "destination_altitude_m"
+	// planAltitudeExtraKey is the key of extra through which MoveOnGlobe is told to plan for the base to reach the altitude of its
+	// destination, for bases such as drones whose kinematics change their height. Positions reported by the movement sensor are then
+	// localized at their height above where the base started.
+	planAltitudeExtraKey = "plan_altitude"
+)
+
+// globeAltitude is the altitude of the destination of a MoveOnGlobe request.
+type globeAltitude struct {
+	destinationM float64
+	plan         bool
+}
+
+// parseGlobeAltitude parses the altitude of the destination of a MoveOnGlobe request from extra, returning nil if the request has none.
+func parseGlobeAltitude(extra map[string]interface{}) (*globeAltitude, error) {
+	var plan bool
+	if planRaw, ok := extra[planAltitudeExtraKey]; ok {
+		if plan, ok = planRaw.(bool); !ok {
+			return nil, fmt.Errorf("could not interpret %s field as bool", planAltitudeExtraKey)
+		}
+	}
+	altitudeRaw, ok := extra[destinationAltitudeExtraKey]
+	if !ok {
+		if plan {
+			return nil, fmt.Errorf("%s requires %s", planAltitudeExtraKey, destinationAltitudeExtraKey)
+		}
+		return nil, nil
+	}
+	altitude, ok := altitudeRaw.(float64)
+	if !ok {
+		return nil, fmt.Errorf("could not interpret %s field as float", destinationAltitudeExtraKey)
+	}
+	if math.IsNaN(altitude) || math.IsInf(altitude, 0) {
+		return nil, fmt.Errorf("%s must be finite", destinationAltitudeExtraKey)
+	}
+	return &globeAltitude{destinationM: altitude, plan: plan}, nil
+}
+
+// goal returns the goal of the base at the destination, relative to the origin at the given altitude, and the factor by which the
+// distance to the goal across the ground understates the distance the base travels to reach it. The goal is only above or below the
+// origin if the altitude is planned for; otherwise the height of the destination is accounted for by the factor alone.
+func (a *globeAltitude) goal(destination, origin *geo.Point, originAltitudeM float64) (spatialmath.Pose, float64) {
+	ground := spatialmath.GeoPointToPoint(destination, origin)
+	if a == nil {
+		return spatialmath.NewPoseFromPoint(ground), 1
+	}
+	sloped := spatialmath.GeoPointToPointWithAltitude(destination, origin, a.destinationM, originAltitudeM)
+	switch {
+	case a.plan:
+		return spatialmath.NewPoseFromPoint(sloped), 1
+	case ground.Norm() == 0:
+		return spatialmath.NewPoseFromPoint(ground), 1
+	default:
+		return spatialmath.NewPoseFromPoint(ground), sloped.Norm() / ground.Norm()
+	}
+}
+
+// movesVertically returns whether moving any of the degrees of freedom of the frame alone changes its height. Degrees of freedom
+// which cannot be moved alone are assumed not to.
+func movesVertically(frame referenceframe.Frame) bool {
+	dof := frame.DoF()
+	for i, limit := range dof {
+		inputs := make([]referenceframe.Input, len(dof))
+		inputs[i] = referenceframe.Input{Value: limit.Max}
+		pose, err := frame.Transform(inputs)
+		if err == nil && math.Abs(pose.Point().Z) > 1e-6 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package builtin
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	geo "github.com/kellydunn/golang-geo"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/referenceframe"
+)
+
+func TestGlobeAltitude(t *testing.T) {
+	altitude, err := parseGlobeAltitude(map[string]interface{}{})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, altitude, test.ShouldBeNil)
+	for _, extra := range []map[string]interface{}{
+		{destinationAltitudeExtraKey: "high"},
+		{destinationAltitudeExtraKey: math.NaN()},
+		{destinationAltitudeExtraKey: 10., planAltitudeExtraKey: "yes"},
+		{planAltitudeExtraKey: true},
+	} {
+		_, err := parseGlobeAltitude(extra)
+		test.That(t, err, test.ShouldNotBeNil)
+	}
+
+	origin := geo.NewPoint(40.7, -74)
+	// a destination 30 meters north and 40 meters above the base
+	destination := origin.PointAtDistanceAndBearing(0.03, 0)
+
+	goal, slope := (*globeAltitude)(nil).goal(destination, origin, 100)
+	test.That(t, goal.Point().Z, test.ShouldEqual, 0)
+	test.That(t, slope, test.ShouldEqual, 1)
+
+	// bases on the ground travel further up the slope than across the ground
+	altitude, err = parseGlobeAltitude(map[string]interface{}{destinationAltitudeExtraKey: 140.})
+	test.That(t, err, test.ShouldBeNil)
+	goal, slope = altitude.goal(destination, origin, 100)
+	test.That(t, goal.Point().Z, test.ShouldEqual, 0)
+	test.That(t, goal.Point().Norm()*slope, test.ShouldAlmostEqual, 50000, 1)
+
+	// while those which fly are planned up to the destination
+	altitude, err = parseGlobeAltitude(map[string]interface{}{destinationAltitudeExtraKey: 140., planAltitudeExtraKey: true})
+	test.That(t, err, test.ShouldBeNil)
+	goal, slope = altitude.goal(destination, origin, 100)
+	test.That(t, goal.Point().Z, test.ShouldAlmostEqual, 40000)
+	test.That(t, slope, test.ShouldEqual, 1)
+
+	ground, err := referenceframe.NewTranslationalFrame("ground", r3.Vector{X: 1}, referenceframe.Limit{Min: -10, Max: 10})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, movesVertically(ground), test.ShouldBeFalse)
+	lift, err := referenceframe.NewTranslationalFrame("lift", r3.Vector{Z: 1}, referenceframe.Limit{Min: -10, Max: 10})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, movesVertically(lift), test.ShouldBeTrue)
+}
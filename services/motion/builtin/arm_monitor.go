@@ -0,0 +1,292 @@
+package builtin
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/spatialmath"
+)
+
+const (
+	// armObstacleDetectorsExtraKey is the key of extra through which Move is given the obstacle detectors, each a vision_service and
+	// camera, to poll while executing the plan. Without it, the plan is executed without checking for new obstacles.
+	armObstacleDetectorsExtraKey = "obstacle_detectors"
+	// armObstaclePollingHzExtraKey is the key of extra setting how often Move polls its obstacle detectors.
+	armObstaclePollingHzExtraKey = "obstacle_polling_frequency_hz"
+	// armMaxReplansExtraKey is the key of extra setting how many times Move replans around new obstacles before failing.
+	armMaxReplansExtraKey = "max_replans"
+
+	// defaultArmObstaclePollingHz is how often Move polls its obstacle detectors if no frequency is given. Arms move quickly relative
+	// to the distances between them and obstacles, so they are polled more often than those of bases.
+	defaultArmObstaclePollingHz = 10.
+)
+
+// armMonitor describes how Move watches for new obstacles intersecting the remainder of its plan while executing it.
+type armMonitor struct {
+	detectors  []readinessDetectorNames
+	period     time.Duration
+	maxReplans int
+}
+
+// parseArmMonitor parses how Move watches for new obstacles from extra, returning nil if it does not watch for them.
+func parseArmMonitor(extra map[string]interface{}) (*armMonitor, error) {
+	raw, ok := extra[armObstacleDetectorsExtraKey]
+	if !ok {
+		return nil, nil
+	}
+	monitor := &armMonitor{period: time.Duration(float64(time.Second) / defaultArmObstaclePollingHz)}
+	if err := mapstructure.Decode(raw, &monitor.detectors); err != nil {
+		return nil, fmt.Errorf("could not interpret %s field: %w", armObstacleDetectorsExtraKey, err)
+	}
+	for _, detector := range monitor.detectors {
+		if detector.VisionService == "" || detector.Camera == "" {
+			return nil, fmt.Errorf("each of %s must name a vision_service and a camera", armObstacleDetectorsExtraKey)
+		}
+	}
+	if raw, ok := extra[armObstaclePollingHzExtraKey]; ok {
+		hz, ok := raw.(float64)
+		if !ok {
+			return nil, fmt.Errorf("could not interpret %s field as float", armObstaclePollingHzExtraKey)
+		}
+		if hz <= 0 {
+			return nil, fmt.Errorf("%s must be positive", armObstaclePollingHzExtraKey)
+		}
+		monitor.period = time.Duration(float64(time.Second) / hz)
+	}
+	if raw, ok := extra[armMaxReplansExtraKey]; ok {
+		replans, ok := raw.(float64)
+		if !ok || replans != math.Trunc(replans) {
+			return nil, fmt.Errorf("could not interpret %s field as an integer", armMaxReplansExtraKey)
+		}
+		if replans < 0 {
+			return nil, fmt.Errorf("%s may not be negative", armMaxReplansExtraKey)
+		}
+		monitor.maxReplans = int(replans)
+	}
+	return monitor, nil
+}
+
+// executeMonitored executes the plan while watching for new obstacles intersecting its remainder. If one does, the moving components
+// are stopped and the request is replanned around the detected obstacles, until the maximum number of replans is exceeded.
+func (ms *builtIn) executeMonitored(
+	ctx context.Context,
+	req motion.MoveReq,
+	plan motionplan.Plan,
+	monitor *armMonitor,
+	execute func(context.Context, motionplan.Plan) error,
+) error {
+	for replans := 0; ; replans++ {
+		worldState, reason, err := ms.watchExecution(ctx, req, plan, monitor, replans, execute)
+		if err != nil || worldState == nil {
+			return err
+		}
+		if replans >= monitor.maxReplans {
+			return fmt.Errorf("stopped since %s, and exceeded maximum number of replans: %d", reason, monitor.maxReplans)
+		}
+		// the waypoints of the request are cleared from extra once planned for, so they cannot be planned through again
+		if _, ok := req.Extra["waypoints"]; ok {
+			return fmt.Errorf("stopped since %s, and cannot replan a request with waypoints", reason)
+		}
+		ms.logger.CInfof(ctx, "replanning Move since %s", reason)
+
+		// replan from where the components were stopped
+		extra := make(map[string]interface{}, len(req.Extra))
+		for key, value := range req.Extra {
+			extra[key] = value
+		}
+		delete(extra, "start_state")
+		req.Extra = extra
+		req.WorldState = worldState
+		if plan, _, err = ms.plan(ctx, req); err != nil {
+			return err
+		}
+	}
+}
+
+// watchExecution executes the plan, polling the obstacle detectors of the monitor until it finishes. If a detection intersects the
+// remainder of the plan, the moving components are stopped and the world state of the request with the detections added is returned
+// along with the reason for stopping.
+func (ms *builtIn) watchExecution(
+	ctx context.Context,
+	req motion.MoveReq,
+	plan motionplan.Plan,
+	monitor *armMonitor,
+	replans int,
+	execute func(context.Context, motionplan.Plan) error,
+) (*referenceframe.WorldState, string, error) {
+	frameSys, err := ms.fsService.FrameSystem(ctx, req.WorldState.Transforms())
+	if err != nil {
+		return nil, "", err
+	}
+	clk := ms.clock
+	if clk == nil {
+		clk = clock.New()
+	}
+
+	executeCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- execute(executeCtx, plan)
+	}()
+
+	ticker := clk.Ticker(monitor.period)
+	defer ticker.Stop()
+	for {
+		select {
+		case err := <-done:
+			return nil, "", err
+		case <-ticker.C:
+			worldState, reason, err := ms.checkArmObstacles(ctx, req, frameSys, plan, monitor, replans)
+			if err == nil && worldState == nil {
+				continue
+			}
+			cancel()
+			<-done
+			return worldState, reason, multierr.Combine(err, ms.stopPlanned(ctx, plan))
+		}
+	}
+}
+
+// checkArmObstacles polls the obstacle detectors of the monitor, returning the world state of the request with the detections added
+// and the reason, if any detection intersects the remainder of the plan.
+func (ms *builtIn) checkArmObstacles(
+	ctx context.Context,
+	req motion.MoveReq,
+	frameSys referenceframe.FrameSystem,
+	plan motionplan.Plan,
+	monitor *armMonitor,
+	replans int,
+) (*referenceframe.WorldState, string, error) {
+	inputs, _, err := ms.fsService.CurrentInputs(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	detections := []spatialmath.Geometry{}
+	for _, detector := range monitor.detectors {
+		visionSvc, ok := findByShortName(ms.visionServices, detector.VisionService)
+		if !ok {
+			return nil, "", fmt.Errorf("%q is not a dependency of the motion service", detector.VisionService)
+		}
+		objects, err := visionSvc.GetObjectPointClouds(ctx, detector.Camera, nil)
+		if err != nil {
+			return nil, "", err
+		}
+		for i, object := range objects {
+			if object.Geometry == nil {
+				continue
+			}
+			// labels are unique across replans, since the detections of earlier ones are kept in the world state
+			geometry := object.Geometry
+			label := detector.Camera + "_transientObstacle_" + strconv.Itoa(replans) + "_" + strconv.Itoa(i)
+			if geometry.Label() != "" {
+				label += "_" + geometry.Label()
+			}
+			geometry.SetLabel(label)
+			tf, err := frameSys.Transform(
+				inputs,
+				referenceframe.NewGeometriesInFrame(detector.Camera, []spatialmath.Geometry{geometry}),
+				referenceframe.World,
+			)
+			if err != nil {
+				return nil, "", err
+			}
+			worldGifs, ok := tf.(*referenceframe.GeometriesInFrame)
+			if !ok {
+				return nil, "", errors.New("unable to assert referenceframe.Transformable into *referenceframe.GeometriesInFrame")
+			}
+			detections = append(detections, worldGifs.Geometries()...)
+		}
+	}
+	if len(detections) == 0 {
+		return nil, "", nil
+	}
+
+	checkFrame := frameSys.Frame(req.ComponentName.ShortName())
+	if checkFrame == nil {
+		return nil, "", fmt.Errorf("component named %s not found in robot frame system", req.ComponentName.ShortName())
+	}
+	tf, err := frameSys.Transform(inputs, referenceframe.NewZeroPoseInFrame(checkFrame.Name()), referenceframe.World)
+	if err != nil {
+		return nil, "", err
+	}
+	currentPose, ok := tf.(*referenceframe.PoseInFrame)
+	if !ok {
+		return nil, "", errors.New("unable to assert referenceframe.Transformable into *referenceframe.PoseInFrame")
+	}
+	executionState, err := motionplan.NewExecutionState(
+		plan,
+		nearestTrajectoryStep(plan.Trajectory(), inputs),
+		inputs,
+		referenceframe.FrameSystemPoses{checkFrame.Name(): currentPose},
+	)
+	if err != nil {
+		return nil, "", err
+	}
+	detected := referenceframe.NewGeometriesInFrame(referenceframe.World, detections)
+	checkedWorldState, err := referenceframe.NewWorldState([]*referenceframe.GeometriesInFrame{detected}, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	// the whole remainder of the plan is checked, since arms cover it far more quickly than bases
+	if err := motionplan.CheckPlanWithConstraints(
+		checkFrame, executionState, checkedWorldState, req.Constraints, frameSys, math.Inf(1), ms.logger,
+	); err != nil {
+		worldState, err2 := req.WorldState.WithObstacles(detected)
+		return worldState, err.Error(), err2
+	}
+	return nil, "", nil
+}
+
+// nearestTrajectoryStep returns the index of the step of the trajectory whose inputs are nearest the given inputs.
+func nearestTrajectoryStep(trajectory motionplan.Trajectory, inputs referenceframe.FrameSystemInputs) int {
+	nearest, nearestDist := 0, math.Inf(1)
+	for i, step := range trajectory {
+		dist := 0.
+		for name, stepInputs := range step {
+			current, ok := inputs[name]
+			if !ok || len(current) != len(stepInputs) {
+				continue
+			}
+			dist += referenceframe.InputsL2Distance(current, stepInputs)
+		}
+		if dist < nearestDist {
+			nearest, nearestDist = i, dist
+		}
+	}
+	return nearest
+}
+
+// stopPlanned stops the actuators whose inputs change over the plan.
+func (ms *builtIn) stopPlanned(ctx context.Context, plan motionplan.Plan) error {
+	_, resources, err := ms.fsService.CurrentInputs(ctx)
+	if err != nil {
+		return err
+	}
+	trajectory := plan.Trajectory()
+	var stopErr error
+	for name, start := range trajectory[0] {
+		actuator, ok := resources[name].(inputEnabledActuator)
+		if !ok {
+			continue
+		}
+		for _, step := range trajectory[1:] {
+			if referenceframe.InputsL2Distance(start, step[name]) > 0 {
+				stopErr = multierr.Combine(stopErr, actuator.Stop(ctx, nil))
+				break
+			}
+		}
+	}
+	return stopErr
+}
@@ -0,0 +1,61 @@
+package builtin
+
+import (
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/referenceframe"
+)
+
+func TestParseArmMonitor(t *testing.T) {
+	monitor, err := parseArmMonitor(map[string]interface{}{})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, monitor, test.ShouldBeNil)
+
+	detectors := []interface{}{map[string]interface{}{"vision_service": "vision", "camera": "camera"}}
+	monitor, err = parseArmMonitor(map[string]interface{}{armObstacleDetectorsExtraKey: detectors})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, monitor.detectors, test.ShouldResemble, []readinessDetectorNames{{VisionService: "vision", Camera: "camera"}})
+	test.That(t, monitor.period, test.ShouldEqual, 100*time.Millisecond)
+	test.That(t, monitor.maxReplans, test.ShouldEqual, 0)
+
+	monitor, err = parseArmMonitor(map[string]interface{}{
+		armObstacleDetectorsExtraKey: detectors,
+		armObstaclePollingHzExtraKey: 4.,
+		armMaxReplansExtraKey:        2.,
+	})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, monitor.period, test.ShouldEqual, 250*time.Millisecond)
+	test.That(t, monitor.maxReplans, test.ShouldEqual, 2)
+
+	for _, extra := range []map[string]interface{}{
+		{armObstacleDetectorsExtraKey: "camera"},
+		{armObstacleDetectorsExtraKey: []interface{}{map[string]interface{}{"camera": "camera"}}},
+		{armObstacleDetectorsExtraKey: detectors, armObstaclePollingHzExtraKey: 0.},
+		{armObstacleDetectorsExtraKey: detectors, armMaxReplansExtraKey: 1.5},
+		{armObstacleDetectorsExtraKey: detectors, armMaxReplansExtraKey: -1.},
+	} {
+		_, err := parseArmMonitor(extra)
+		test.That(t, err, test.ShouldNotBeNil)
+	}
+}
+
+func TestNearestTrajectoryStep(t *testing.T) {
+	trajectory := motionplan.Trajectory{
+		{"arm": referenceframe.FloatsToInputs([]float64{0, 0}), "gripper": {}},
+		{"arm": referenceframe.FloatsToInputs([]float64{1, 0}), "gripper": {}},
+		{"arm": referenceframe.FloatsToInputs([]float64{1, 1}), "gripper": {}},
+	}
+	nearest := func(values ...float64) int {
+		return nearestTrajectoryStep(trajectory, referenceframe.FrameSystemInputs{
+			"arm":     referenceframe.FloatsToInputs(values),
+			"gripper": {},
+		})
+	}
+	test.That(t, nearest(0.1, 0), test.ShouldEqual, 0)
+	test.That(t, nearest(0.9, 0.2), test.ShouldEqual, 1)
+	test.That(t, nearest(1, 0.8), test.ShouldEqual, 2)
+}
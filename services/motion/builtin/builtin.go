@@ -7,20 +7,25 @@ import (
 	"sync"
 	"time"
 
+	"github.com/benbjohnson/clock"
 	"github.com/go-viper/mapstructure/v2"
 	"github.com/golang/geo/r3"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
 	pb "go.viam.com/api/service/motion/v1"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/types/known/structpb"
 
+	"go.viam.com/rdk/components/base/kinematicbase"
 	"go.viam.com/rdk/components/movementsensor"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/motionplan/tpspace"
 	"go.viam.com/rdk/operation"
 	"go.viam.com/rdk/referenceframe"
 	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot/estop"
 	"go.viam.com/rdk/robot/framesystem"
 	"go.viam.com/rdk/services/motion"
 	"go.viam.com/rdk/services/motion/builtin/state"
@@ -47,8 +52,17 @@ func init() {
 
 // export keys to be used with DoCommand so they can be referenced by clients.
 const (
-	DoPlan    = "plan"
-	DoExecute = "execute"
+	DoPlan                 = "plan"
+	DoExecute              = "execute"
+	DoCheckReadiness       = "check_readiness"
+	DoCalibrateHandEye     = "calibrate_hand_eye"
+	DoEstimateSensorOffset = "estimate_sensor_offset"
+	DoGetPTGLibrary        = "get_ptg_library"
+	DoSetPTGLibrary        = "set_ptg_library"
+	DoSavePTGLibrary       = "save_ptg_library"
+	DoEStop                = "estop"
+	DoResetEStop           = "reset_estop"
+	DoReplan               = "replan"
 )
 
 const (
@@ -60,6 +74,11 @@ const (
 	defaultLinearMPerSec               = 0.3
 	defaultSlamPlanDeviationM          = 1.
 	defaultGlobePlanDeviationM         = 2.6
+	// localizationDeviationSigmas is the number of localization standard deviations the base may appear to deviate from its plan
+	// before a replan is issued.
+	localizationDeviationSigmas = 3.
+	// maxPlanDeviationScale bounds how far localization uncertainty may widen the configured plan deviation.
+	maxPlanDeviationScale = 4.
 )
 
 var (
@@ -83,20 +102,55 @@ type inputEnabledActuator interface {
 // Config describes how to configure the service; currently only used for specifying dependency on framesystem service.
 type Config struct {
 	LogFilePath string `json:"log_file_path"`
+	// PTGLibraries maps the names of bases to the files their PTG libraries are persisted in, as JSON. A base whose file exists
+	// plans with the library in it rather than the default one for its turning radius.
+	PTGLibraries map[string]string `json:"ptg_libraries,omitempty"`
+	// WaypointActions maps the names of the actions the metadata of waypoints may name to the commands they run.
+	WaypointActions map[string]WaypointActionConfig `json:"waypoint_actions,omitempty"`
+	// SafetyZones are regions of the frame system which the robot moves through slowly, or not at all.
+	SafetyZones []SafetyZoneConfig `json:"safety_zones,omitempty"`
 }
 
-// Validate here adds a dependency on the internal framesystem service.
+// Validate here adds a dependency on the internal framesystem, e-stop and reservations services, and on the resources of any waypoint
+// actions. It also ensures any safety zones are valid.
 func (c *Config) Validate(path string) ([]string, error) {
-	return []string{framesystem.InternalServiceName.String()}, nil
+	deps := []string{
+		framesystem.InternalServiceName.String(),
+		estop.InternalServiceName.String(),
+		resource.ReservationsInternalName.String(),
+	}
+	for name, action := range c.WaypointActions {
+		if action.Resource == "" {
+			return nil, resource.NewConfigValidationFieldRequiredError(path, fmt.Sprintf("waypoint_actions.%s.resource", name))
+		}
+		deps = append(deps, action.Resource)
+	}
+	for i, zone := range c.SafetyZones {
+		if err := zone.validate(fmt.Sprintf("%s.safety_zones.%d", path, i)); err != nil {
+			return nil, err
+		}
+	}
+	return deps, nil
 }
 
 // NewBuiltIn returns a new move and grab service for the given robot.
 func NewBuiltIn(
 	ctx context.Context, deps resource.Dependencies, conf resource.Config, logger logging.Logger,
+) (motion.Service, error) {
+	return NewBuiltInWithClock(ctx, deps, conf, clock.New(), logger)
+}
+
+// NewBuiltInWithClock returns a new move and grab service for the given robot, which times the execution of plans on bases with
+// the given clock. Simulations use a mock clock so that bases drive the same distance between polls for replanning regardless of how
+// fast the machine running them is.
+func NewBuiltInWithClock(
+	ctx context.Context, deps resource.Dependencies, conf resource.Config, clk clock.Clock, logger logging.Logger,
 ) (motion.Service, error) {
 	ms := &builtIn{
-		Named:  conf.ResourceName().AsNamed(),
-		logger: logger,
+		Named:   conf.ResourceName().AsNamed(),
+		logger:  logger,
+		metrics: newMotionMetrics(),
+		clock:   clk,
 	}
 
 	if err := ms.Reconfigure(ctx, deps, conf); err != nil {
@@ -125,14 +179,33 @@ func (ms *builtIn) Reconfigure(
 		}
 		ms.logger = logger
 	}
+	ptgLibraries, err := loadPTGLibraries(config.PTGLibraries)
+	if err != nil {
+		return err
+	}
+	ms.ptgMu.Lock()
+	ms.ptgLibraryPaths = config.PTGLibraries
+	ms.ptgLibraries = ptgLibraries
+	ms.ptgMu.Unlock()
+	if ms.waypointActions, err = newWaypointActions(config.WaypointActions, deps); err != nil {
+		return err
+	}
+	if ms.safetyZones, ms.geoSafetyZones, err = newSafetyZones(config.SafetyZones); err != nil {
+		return err
+	}
 	movementSensors := make(map[resource.Name]movementsensor.MovementSensor)
 	slamServices := make(map[resource.Name]slam.Service)
 	visionServices := make(map[resource.Name]vision.Service)
 	components := make(map[resource.Name]resource.Resource)
+	var reservations *resource.Reservations
 	for name, dep := range deps {
 		switch dep := dep.(type) {
 		case framesystem.Service:
 			ms.fsService = dep
+		case estop.Service:
+			ms.subscribeEStop(dep)
+		case *resource.Reservations:
+			reservations = dep
 		case movementsensor.MovementSensor:
 			movementSensors[name] = dep
 		case slam.Service:
@@ -147,15 +220,21 @@ func (ms *builtIn) Reconfigure(
 	ms.slamServices = slamServices
 	ms.visionServices = visionServices
 	ms.components = components
+	ms.reservations = reservations
 	if ms.state != nil {
 		ms.state.Stop()
 	}
+	ms.degradationMu.Lock()
+	ms.detectorDegradations = nil
+	ms.degradationMu.Unlock()
 
-	state, err := state.NewState(stateTTL, stateTTLCheckInterval, ms.logger)
+	state, err := state.NewState(stateTTL, stateTTLCheckInterval, reservations, ms.logger)
 	if err != nil {
 		return err
 	}
+	state.ObserveReplans(ms.metrics.observeReplan)
 	ms.state = state
+	ms.eStop.set(state, components)
 	return nil
 }
 
@@ -169,9 +248,62 @@ type builtIn struct {
 	components      map[resource.Name]resource.Resource
 	logger          logging.Logger
 	state           *state.State
+	// reservations holds the reservations of the robot, in which components are reserved while the motion service moves them
+	reservations *resource.Reservations
+
+	// headingTrackers holds the GPS track localizer most recently used for each movement sensor which lacks a compass, so that
+	// replans can reuse its heading estimate rather than repeating the calibration move
+	headingMu       sync.Mutex
+	headingTrackers map[resource.Name]*motion.GPSTrackLocalizer
+
+	// detectorDegradations holds the unavailable obstacle detectors of the most recent execution on each component, so that
+	// replans keep applying their degradation policies
+	degradationMu        sync.Mutex
+	detectorDegradations map[resource.Name]*detectorDegradation
+
+	// planningHorizons holds the planning horizon of the most recent MoveOnGlobe execution on each component, so that extending
+	// the plan to the next horizon is not counted as a replan
+	horizonMu        sync.Mutex
+	planningHorizons map[resource.Name]*planningHorizon
+
+	// obstacleMemories holds the transient detections remembered by the most recent execution on each component, so that replans
+	// still avoid obstacles which are no longer in view
+	memoryMu         sync.Mutex
+	obstacleMemories map[resource.Name]*obstacleMemory
+
+	// recoveries holds the recovery behaviors performed by the most recent execution on each component, so that each is performed
+	// once per execution
+	recoveryMu sync.Mutex
+	recoveries map[resource.Name]*recovery
+
+	// sensorOffsets holds the estimated pose of each base relative to a movement sensor mounted on it, for use when the frame
+	// system does not relate the two
+	offsetMu      sync.Mutex
+	sensorOffsets map[sensorMount]spatialmath.Pose
+
+	// ptgLibraries holds the PTG library each base plans with in place of the default one, loaded from the files configured in
+	// ptgLibraryPaths or set through DoCommand
+	ptgMu           sync.Mutex
+	ptgLibraries    map[string]*tpspace.PTGLibrary
+	ptgLibraryPaths map[string]string
+
+	// waypointActions holds the hooks the metadata of waypoints may name, run once they are reached
+	waypointActions map[string]waypointAction
+
+	// safetyZones holds the regions of the frame system which the robot moves through slowly, or not at all, and geoSafetyZones
+	// those fixed to the globe
+	safetyZones    []safetyZone
+	geoSafetyZones []safetyZone
+
+	// eStop holds the e-stop of the robot, and what is stopped when it is engaged
+	eStop eStopTargets
+
+	metrics *motionMetrics
+	clock   clock.Clock
 }
 
 func (ms *builtIn) Close(ctx context.Context) error {
+	ms.subscribeEStop(nil)
 	ms.mu.Lock()
 	defer ms.mu.Unlock()
 	if ms.state != nil {
@@ -181,22 +313,88 @@ func (ms *builtIn) Close(ctx context.Context) error {
 }
 
 func (ms *builtIn) Move(ctx context.Context, req motion.MoveReq) (bool, error) {
+	ctx, span := trace.StartSpan(ctx, "motion::builtin::Move")
+	defer span.End()
+
+	ctx, done, err := ms.guardEStop(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer done()
 	ms.mu.RLock()
 	defer ms.mu.RUnlock()
 	operation.CancelOtherWithLabel(ctx, builtinOpLabel)
 
-	plan, err := ms.plan(ctx, req)
+	maxSpeed, err := maxEndEffectorSpeed(req.Extra)
+	if err != nil {
+		return false, err
+	}
+	monitor, err := parseArmMonitor(req.Extra)
+	if err != nil {
+		return false, err
+	}
+	intercept, err := parseInterception(req.Extra)
+	if err != nil {
+		return false, err
+	}
+	actions, err := ms.waypointActionsFromExtra(req.Extra)
+	if err != nil {
+		return false, err
+	}
+	reservation := ms.reservations.Reserve(req.ComponentName, fmt.Sprintf("motion Move request %s", uuid.New()))
+	defer reservation.Release()
+
+	execute := func(ctx context.Context, plan motionplan.Plan, mobile *mobileBase) error {
+		// the frame system is only needed to limit speeds and check the arrival of the component at its waypoints
+		var frameSys referenceframe.FrameSystem
+		if mobile != nil {
+			frameSys = mobile.frameSystem
+		} else if maxSpeed > 0 || len(ms.safetyZones) > 0 || motionplan.PlanWaypointMetadata(plan) != nil {
+			fs, err := ms.fsService.FrameSystem(ctx, req.WorldState.Transforms())
+			if err != nil {
+				return err
+			}
+			frameSys = fs
+		}
+		return ms.executeWaypoints(ctx, frameSys, plan, req.ComponentName.ShortName(), maxSpeed, actions, mobile)
+	}
+	if intercept != nil {
+		if monitor != nil {
+			return false, fmt.Errorf("obstacle_detectors are not supported when told to %s a destination", interceptExtraKey)
+		}
+		err = ms.executeIntercepting(ctx, req, intercept, execute)
+		return err == nil, err
+	}
+
+	plan, mobile, err := ms.plan(ctx, req)
 	if err != nil {
 		return false, err
 	}
-	err = ms.execute(ctx, plan.Trajectory())
+	if monitor != nil {
+		if mobile != nil {
+			return false, errors.New("obstacle_detectors are not supported when moving a component together with the base carrying it")
+		}
+		err = ms.executeMonitored(ctx, req, plan, monitor, func(ctx context.Context, plan motionplan.Plan) error {
+			return execute(ctx, plan, nil)
+		})
+		return err == nil, err
+	}
+	err = execute(ctx, plan, mobile)
 	return err == nil, err
 }
 
 func (ms *builtIn) MoveOnMap(ctx context.Context, req motion.MoveOnMapReq) (motion.ExecutionID, error) {
+	ctx, span := trace.StartSpan(ctx, "motion::builtin::MoveOnMap")
+	defer span.End()
+
 	if err := ctx.Err(); err != nil {
 		return uuid.Nil, err
 	}
+	ctx, done, err := ms.guardEStop(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	defer done()
 	ms.mu.RLock()
 	defer ms.mu.RUnlock()
 	ms.logger.CDebugf(ctx, "MoveOnMap called with %s", req)
@@ -213,10 +411,48 @@ func (ms *builtIn) MoveOnMap(ctx context.Context, req motion.MoveOnMapReq) (moti
 }
 
 type validatedExtra struct {
-	maxReplans       int
-	replanCostFactor float64
-	motionProfile    string
-	extra            map[string]interface{}
+	maxReplans        int
+	replanCostFactor  float64
+	motionProfile     string
+	detectorCorridors []*detectorCorridor
+	detectorPolicies  map[string]detectorPolicy
+	maxSensorSkew     time.Duration
+	planningHorizonMM float64
+	straightLineMaxMM float64
+	costmapThreshold  float64
+	obstacleMemory    obstacleMemoryConfig
+	mapFilter         mapFilter
+	occupancyGridPath string
+	replanOnMapChange bool
+	// relocalizationTimeout is how long MoveOnMap waits for a lost SLAM service to relocalize
+	relocalizationTimeout time.Duration
+	localizer             string
+	localizerSources      []string
+	// requireFrameTransforms refuses to assume that a movement sensor missing from the frame system is coincident with the base
+	requireFrameTransforms bool
+	// levelPayload keeps the payloads carried by the base level
+	levelPayload levelPayload
+	// localPlanner follows the plan with the local planner of the base
+	localPlanner localPlannerConfig
+	// replanDebounce keeps noisy polls of the replanners from replanning the execution
+	replanDebounce replanDebounce
+	// interactionSpaces are the world frame regions the base must stay within
+	interactionSpaces []spatialmath.Geometry
+	// collisionPadding keeps clearances around the base and obstacles
+	collisionPadding []motionplan.CollisionPadding
+	// obstacleWait is how long the base waits for transient obstacles blocking its plan to clear before replanning
+	obstacleWait time.Duration
+	// trailer is the trailer towed by the base, if any
+	trailer *kinematicbase.TrailerOptions
+	// flight is how the base flies, if it does
+	flight *kinematicbase.FlightOptions
+	// stall is how long the base may make no progress before its execution fails
+	stall stallConfig
+	// slip is how much the base may slip before it backs up and replans
+	slip slipConfig
+	// recovery is the ordered list of recovery behaviors performed when replanning fails
+	recovery []recoveryBehavior
+	extra    map[string]interface{}
 }
 
 func newValidatedExtra(extra map[string]interface{}) (validatedExtra, error) {
@@ -226,6 +462,7 @@ func newValidatedExtra(extra map[string]interface{}) (validatedExtra, error) {
 	v := validatedExtra{}
 	if extra == nil {
 		v.extra = map[string]interface{}{"smooth_iter": defaultSmoothIter}
+		v.costmapThreshold = defaultCostmapThreshold
 		return v, nil
 	}
 	if replansRaw, ok := extra["max_replans"]; ok {
@@ -246,23 +483,186 @@ func newValidatedExtra(extra map[string]interface{}) (validatedExtra, error) {
 		}
 		replanCostFactor = costFactor
 	}
+	var detectorCorridors []*detectorCorridor
+	if corridorsRaw, ok := extra["detector_corridors"]; ok {
+		var err error
+		if detectorCorridors, err = newDetectorCorridors(corridorsRaw); err != nil {
+			return validatedExtra{}, err
+		}
+	}
+	var detectorPolicies map[string]detectorPolicy
+	if policiesRaw, ok := extra["obstacle_detector_policies"]; ok {
+		var err error
+		if detectorPolicies, err = newDetectorPolicies(policiesRaw); err != nil {
+			return validatedExtra{}, err
+		}
+	}
+	var maxSensorSkew time.Duration
+	if skewRaw, ok := extra["max_sensor_skew_ms"]; ok {
+		skewMS, ok := skewRaw.(float64)
+		if !ok {
+			return validatedExtra{}, errors.New("could not interpret max_sensor_skew_ms field as float")
+		}
+		if skewMS <= 0 {
+			return validatedExtra{}, errors.New("max_sensor_skew_ms must be positive")
+		}
+		maxSensorSkew = time.Duration(skewMS * float64(time.Millisecond))
+	}
+	planningHorizonMM, err := parsePlanningHorizon(extra)
+	if err != nil {
+		return validatedExtra{}, err
+	}
+	var straightLineMaxMM float64
+	if straightLineRaw, ok := extra[straightLineExtraKey]; ok {
+		if straightLineMaxMM, ok = straightLineRaw.(float64); !ok {
+			return validatedExtra{}, fmt.Errorf("could not interpret %s field as float", straightLineExtraKey)
+		}
+		if straightLineMaxMM < 0 {
+			return validatedExtra{}, fmt.Errorf("%s may not be negative", straightLineExtraKey)
+		}
+	}
+	costmapThreshold, err := parseCostmapThreshold(extra)
+	if err != nil {
+		return validatedExtra{}, err
+	}
+	obstacleMemory, err := parseObstacleMemory(extra)
+	if err != nil {
+		return validatedExtra{}, err
+	}
+	mapFilter, err := parseMapFilter(extra)
+	if err != nil {
+		return validatedExtra{}, err
+	}
+	occupancyGridPath, err := parseOccupancyGridPath(extra)
+	if err != nil {
+		return validatedExtra{}, err
+	}
+	replanOnMapChange, err := parseReplanOnMapChange(extra)
+	if err != nil {
+		return validatedExtra{}, err
+	}
+	relocalizationTimeout, err := parseRelocalizationTimeout(extra)
+	if err != nil {
+		return validatedExtra{}, err
+	}
+	var localizer string
+	if localizerRaw, ok := extra["localizer"]; ok {
+		if localizer, ok = localizerRaw.(string); !ok {
+			return validatedExtra{}, errors.New("could not interpret localizer field as string")
+		}
+	}
+	requireFrameTransforms, err := parseRequireFrameTransforms(extra)
+	if err != nil {
+		return validatedExtra{}, err
+	}
+	levelPayload, err := parseLevelPayload(extra)
+	if err != nil {
+		return validatedExtra{}, err
+	}
+	localPlanner, err := parseLocalPlanner(extra)
+	if err != nil {
+		return validatedExtra{}, err
+	}
+	replanDebounce, err := parseReplanDebounce(extra)
+	if err != nil {
+		return validatedExtra{}, err
+	}
+	interactionSpaces, err := parseInteractionSpaces(extra)
+	if err != nil {
+		return validatedExtra{}, err
+	}
+	collisionPadding, err := parseCollisionPadding(extra)
+	if err != nil {
+		return validatedExtra{}, err
+	}
+	obstacleWait, err := parseObstacleWait(extra)
+	if err != nil {
+		return validatedExtra{}, err
+	}
+	trailer, err := parseTrailer(extra)
+	if err != nil {
+		return validatedExtra{}, err
+	}
+	flight, err := parseFlight(extra)
+	if err != nil {
+		return validatedExtra{}, err
+	}
+	stall, err := parseStall(extra)
+	if err != nil {
+		return validatedExtra{}, err
+	}
+	slip, err := parseSlip(extra)
+	if err != nil {
+		return validatedExtra{}, err
+	}
+	recovery, err := parseRecoveryBehaviors(extra)
+	if err != nil {
+		return validatedExtra{}, err
+	}
+	var localizerSources []string
+	if sourcesRaw, ok := extra["localizer_sources"]; ok {
+		sources, ok := sourcesRaw.([]interface{})
+		if !ok {
+			return validatedExtra{}, errors.New("could not interpret localizer_sources field as a list")
+		}
+		for _, sourceRaw := range sources {
+			source, ok := sourceRaw.(string)
+			if !ok {
+				return validatedExtra{}, errors.New("could not interpret localizer_sources entry as string")
+			}
+			localizerSources = append(localizerSources, source)
+		}
+	}
 
 	if _, ok := extra["smooth_iter"]; !ok {
 		extra["smooth_iter"] = defaultSmoothIter
 	}
 
 	return validatedExtra{
-		maxReplans:       maxReplans,
-		motionProfile:    motionProfile,
-		replanCostFactor: replanCostFactor,
-		extra:            extra,
+		maxReplans:             maxReplans,
+		motionProfile:          motionProfile,
+		replanCostFactor:       replanCostFactor,
+		detectorCorridors:      detectorCorridors,
+		detectorPolicies:       detectorPolicies,
+		maxSensorSkew:          maxSensorSkew,
+		planningHorizonMM:      planningHorizonMM,
+		straightLineMaxMM:      straightLineMaxMM,
+		costmapThreshold:       costmapThreshold,
+		obstacleMemory:         obstacleMemory,
+		mapFilter:              mapFilter,
+		occupancyGridPath:      occupancyGridPath,
+		replanOnMapChange:      replanOnMapChange,
+		relocalizationTimeout:  relocalizationTimeout,
+		localizer:              localizer,
+		localizerSources:       localizerSources,
+		requireFrameTransforms: requireFrameTransforms,
+		levelPayload:           levelPayload,
+		localPlanner:           localPlanner,
+		replanDebounce:         replanDebounce,
+		interactionSpaces:      interactionSpaces,
+		collisionPadding:       collisionPadding,
+		obstacleWait:           obstacleWait,
+		trailer:                trailer,
+		flight:                 flight,
+		stall:                  stall,
+		slip:                   slip,
+		recovery:               recovery,
+		extra:                  extra,
 	}, nil
 }
 
 func (ms *builtIn) MoveOnGlobe(ctx context.Context, req motion.MoveOnGlobeReq) (motion.ExecutionID, error) {
+	ctx, span := trace.StartSpan(ctx, "motion::builtin::MoveOnGlobe")
+	defer span.End()
+
 	if err := ctx.Err(); err != nil {
 		return uuid.Nil, err
 	}
+	ctx, done, err := ms.guardEStop(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	defer done()
 	ms.mu.RLock()
 	defer ms.mu.RUnlock()
 	ms.logger.CDebugf(ctx, "MoveOnGlobe called with %s", req)
@@ -336,7 +736,7 @@ func (ms *builtIn) PlanHistory(
 	return ms.state.PlanHistory(req)
 }
 
-// DoCommand supports two commands which are specified through the command map
+// DoCommand supports the following commands which are specified through the command map
 //   - DoPlan generates and returns a Trajectory for a given motionpb.MoveRequest without executing it
 //     required key: DoPlan
 //     input value: a motionpb.MoveRequest which will be used to create a Trajectory
@@ -345,12 +745,127 @@ func (ms *builtIn) PlanHistory(
 //     required key: DoExecute
 //     input value: a motionplan.Trajectory
 //     output value: a bool
+//   - DoCheckReadiness checks the dependencies of a prospective request without moving anything
+//     required key: DoCheckReadiness
+//     input value: a map with the required key "component_name" and optional keys "movement_sensor_name", "slam_service_name",
+//     "obstacle_detectors" (a list of maps with keys "vision_service" and "camera"), "max_latency_ms" and "extra"
+//     output value: a map with a bool "ready" and a list of per-dependency "checks" reporting readiness, latency and any error
+//   - DoCalibrateHandEye moves an arm through poses while a pose tracker using a camera on the arm observes a fixed target, and
+//     solves for the pose of the camera relative to the end of the arm
+//     required key: DoCalibrateHandEye
+//     input value: a map with the required keys "arm_name", "pose_tracker_name", "body_name" (the target seen by the pose
+//     tracker) and "poses" (a list of at least 3 arm poses in the form of frame configs), and the optional key "settle_ms"
+//     output value: a map with the frame config of the camera under "frame" and the number of observations used under "samples"
+//   - DoEstimateSensorOffset turns a base in place while recording the positions of a movement sensor on it, and estimates the
+//     offset between the two for MoveOnGlobe requests when the frame system does not relate them
+//     required key: DoEstimateSensorOffset
+//     input value: a map with the required keys "base_name" and "movement_sensor_name", and the optional keys "spins" (the
+//     number of equal spins in the full turn), "heading_mm" (how far to drive to estimate the heading without a compass),
+//     "degs_per_sec" and "mm_per_sec"
+//     output value: a map with the frame config of the movement sensor relative to the base under "frame"
+//   - DoEStop engages the e-stop of the robot, which stops every execution, arm and base, and rejects requests which move components
+//     until it is reset
+//     required key: DoEStop
+//     input value: the reason the e-stop is engaged
+//     output value: a bool
+//   - DoResetEStop resets the e-stop of the robot
+//     required key: DoResetEStop
+//     input value: ignored
+//     output value: a bool
+//   - DoReplan cancels the current plan of the active execution on a component and replans it, recording the replan as manual
+//     required key: DoReplan
+//     input value: a map with the required key "component_name" and the optional key "reason"
+//     output value: a bool
 func (ms *builtIn) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	// the e-stop is engaged or reset on its own, before anything else is done, and without waiting on the lock of the service, which
+	// is held for as long as a synchronous request runs
+	if req, ok := cmd[DoEStop]; ok {
+		reason, err := utils.AssertType[string](req)
+		if err != nil {
+			return nil, err
+		}
+		if err := ms.eStop.engage(ctx, reason); err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{DoEStop: true}, nil
+	}
+	if _, ok := cmd[DoResetEStop]; ok {
+		if err := ms.eStop.reset(); err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{DoResetEStop: true}, nil
+	}
+
 	ms.mu.RLock()
 	defer ms.mu.RUnlock()
-	operation.CancelOtherWithLabel(ctx, builtinOpLabel)
 
 	resp := make(map[string]interface{}, 0)
+	if req, ok := cmd[DoCheckReadiness]; ok {
+		report, err := ms.checkReadiness(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		resp[DoCheckReadiness] = report
+	}
+	// ptg libraries are only inspected and tuned, which moves nothing
+	if req, ok := cmd[DoGetPTGLibrary]; ok {
+		library, err := ms.getPTGLibrary(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		resp[DoGetPTGLibrary] = library
+	}
+	if req, ok := cmd[DoSetPTGLibrary]; ok {
+		if err := ms.setPTGLibrary(ctx, req); err != nil {
+			return nil, err
+		}
+		resp[DoSetPTGLibrary] = true
+	}
+	if req, ok := cmd[DoSavePTGLibrary]; ok {
+		if err := ms.savePTGLibrary(req); err != nil {
+			return nil, err
+		}
+		resp[DoSavePTGLibrary] = true
+	}
+	if req, ok := cmd[DoReplan]; ok {
+		if err := ms.requestReplan(req); err != nil {
+			return nil, err
+		}
+		resp[DoReplan] = true
+	}
+	// readiness checks do not move anything, so only calibration, planning and execution cancel other operations
+	_, calibrate := cmd[DoCalibrateHandEye]
+	_, estimate := cmd[DoEstimateSensorOffset]
+	_, plan := cmd[DoPlan]
+	_, execute := cmd[DoExecute]
+	if !calibrate && !estimate && !plan && !execute {
+		return resp, nil
+	}
+	if calibrate || estimate || execute {
+		var done func()
+		var err error
+		if ctx, done, err = ms.guardEStop(ctx); err != nil {
+			return nil, err
+		}
+		defer done()
+	}
+	operation.CancelOtherWithLabel(ctx, builtinOpLabel)
+
+	if req, ok := cmd[DoCalibrateHandEye]; ok {
+		result, err := ms.calibrateHandEye(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		resp[DoCalibrateHandEye] = result
+	}
+	if req, ok := cmd[DoEstimateSensorOffset]; ok {
+		result, err := ms.estimateSensorOffset(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		resp[DoEstimateSensorOffset] = result
+	}
+
 	if req, ok := cmd[DoPlan]; ok {
 		s, err := utils.AssertType[string](req)
 		if err != nil {
@@ -373,7 +888,7 @@ func (ms *builtIn) DoCommand(ctx context.Context, cmd map[string]interface{}) (m
 		if err != nil {
 			return nil, err
 		}
-		plan, err := ms.plan(ctx, moveReq)
+		plan, _, err := ms.plan(ctx, moveReq)
 		if err != nil {
 			return nil, err
 		}
@@ -384,7 +899,7 @@ func (ms *builtIn) DoCommand(ctx context.Context, cmd map[string]interface{}) (m
 		if err := mapstructure.Decode(req, &trajectory); err != nil {
 			return nil, err
 		}
-		if err := ms.execute(ctx, trajectory); err != nil {
+		if err := ms.execute(ctx, trajectory, nil); err != nil {
 			return nil, err
 		}
 		resp[DoExecute] = true
@@ -392,30 +907,53 @@ func (ms *builtIn) DoCommand(ctx context.Context, cmd map[string]interface{}) (m
 	return resp, nil
 }
 
-func (ms *builtIn) plan(ctx context.Context, req motion.MoveReq) (motionplan.Plan, error) {
+// plan returns a plan for the given request, along with the base carrying the component if the request plans for the whole body.
+func (ms *builtIn) plan(ctx context.Context, req motion.MoveReq) (motionplan.Plan, *mobileBase, error) {
 	frameSys, err := ms.fsService.FrameSystem(ctx, req.WorldState.Transforms())
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// build maps of relevant components and inputs from initial inputs
 	fsInputs, _, err := ms.fsService.CurrentInputs(ctx)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	ms.logger.CDebugf(ctx, "frame system inputs: %v", fsInputs)
 
 	movingFrame := frameSys.Frame(req.ComponentName.ShortName())
 	if movingFrame == nil {
-		return nil, fmt.Errorf("component named %s not found in robot frame system", req.ComponentName.ShortName())
+		return nil, nil, fmt.Errorf("component named %s not found in robot frame system", req.ComponentName.ShortName())
+	}
+	mobile, err := ms.wholeBodyBase(ctx, frameSys, movingFrame, req.Extra)
+	if err != nil {
+		return nil, nil, err
+	}
+	mobile.addTo(fsInputs, nil)
+	level, err := parseLevelPayload(req.Extra)
+	if err != nil {
+		return nil, nil, err
+	}
+	constraints, err := level.constrain(req.Constraints, frameSys, []string{movingFrame.Name()})
+	if err != nil {
+		return nil, nil, err
+	}
+	paddings, err := parseCollisionPadding(req.Extra)
+	if err != nil {
+		return nil, nil, err
+	}
+	constraints = padCollisions(constraints, paddings)
+	constraints, err = ms.constrainPayloads(ctx, constraints, frameSys, movingFrame)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	startState, waypoints, err := waypointsFromRequest(req, fsInputs)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	if len(waypoints) == 0 {
-		return nil, errors.New("could not find any waypoints to plan for in MoveRequest. Fill in Destination or goal_state")
+		return nil, nil, errors.New("could not find any waypoints to plan for in MoveRequest. Fill in Destination or goal_state")
 	}
 	// The contents of waypoints can be gigantic, and if so, making copies of `extra` becomes the majority of motion planning runtime.
 	// As the meaning from `waypoints` has already been extracted above into its proper data structure, there is no longer a need to
@@ -424,6 +962,18 @@ func (ms *builtIn) plan(ctx context.Context, req motion.MoveReq) (motionplan.Pla
 		req.Extra["waypoints"] = nil
 	}
 
+	// goals of the component are goals of its tool center point, if the request gives one
+	goalFrame := movingFrame.Name()
+	tcp, err := parseToolCenterPoint(req.Extra)
+	if err != nil {
+		return nil, nil, err
+	}
+	if tcp != nil {
+		if goalFrame, waypoints, err = aimToolCenterPoint(frameSys, movingFrame.Name(), tcp, waypoints); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	// re-evaluate goal poses to be in the frame of World
 	// TODO (RSDK-8847) : this is a workaround to help account for us not yet being able to properly synchronize simultaneous motion across
 	// multiple components. If we are moving component1, mounted on arm2, to a goal in frame of component2, which is mounted on arm2, then
@@ -437,35 +987,63 @@ func (ms *builtIn) plan(ctx context.Context, req motion.MoveReq) (motionplan.Pla
 			for fName, destination := range wp.Poses() {
 				tf, err := frameSys.Transform(fsInputs, destination, solvingFrame)
 				if err != nil {
-					return nil, err
+					return nil, nil, err
 				}
 				goalPose, _ := tf.(*referenceframe.PoseInFrame)
 				step[fName] = goalPose
 			}
-			worldWaypoints = append(worldWaypoints, motionplan.NewPlanState(step, wp.Configuration()))
+			worldWaypoints = append(worldWaypoints, motionplan.NewPlanState(step, wp.Configuration()).WithMetadata(wp.Metadata()))
 		} else {
 			worldWaypoints = append(worldWaypoints, wp)
 		}
 	}
+	optimizeOrder, err := parseOptimizeWaypointOrder(req.Extra)
+	if err != nil {
+		return nil, nil, err
+	}
+	if optimizeOrder {
+		tf, err := frameSys.Transform(startState.Configuration(), referenceframe.NewZeroPoseInFrame(goalFrame), solvingFrame)
+		if err != nil {
+			return nil, nil, err
+		}
+		start, ok := tf.(*referenceframe.PoseInFrame)
+		if !ok {
+			return nil, nil, errors.New("unable to assert referenceframe.Transformable into *referenceframe.PoseInFrame")
+		}
+		if worldWaypoints, err = orderWaypoints(start.Pose(), worldWaypoints, goalFrame); err != nil {
+			return nil, nil, err
+		}
+	}
 
 	// the goal is to move the component to goalPose which is specified in coordinates of goalFrameName
-	return motionplan.PlanMotion(ctx, &motionplan.PlanRequest{
+	planStart := time.Now()
+	plan, err := motionplan.PlanMotion(ctx, &motionplan.PlanRequest{
 		Logger:      ms.logger,
 		Goals:       worldWaypoints,
 		StartState:  startState,
 		FrameSystem: frameSys,
 		WorldState:  req.WorldState,
-		Constraints: req.Constraints,
+		Constraints: constraints,
 		Options:     req.Extra,
 	})
+	ms.metrics.observePlan(time.Since(planStart), err)
+	if err != nil {
+		return nil, nil, err
+	}
+	if plan, err = annotateWaypoints(frameSys, plan, goalFrame, worldWaypoints); err != nil {
+		return nil, nil, err
+	}
+	return plan, mobile, nil
 }
 
-func (ms *builtIn) execute(ctx context.Context, trajectory motionplan.Trajectory) error {
+// execute follows the given trajectory, driving the base carrying the moving component if it was planned for.
+func (ms *builtIn) execute(ctx context.Context, trajectory motionplan.Trajectory, mobile *mobileBase) error {
 	// build maps of relevant components from initial inputs
 	_, resources, err := ms.fsService.CurrentInputs(ctx)
 	if err != nil {
 		return err
 	}
+	mobile.addTo(nil, resources)
 
 	// Batch GoToInputs calls if possible; components may want to blend between inputs
 	combinedSteps := []map[string][][]referenceframe.Input{}
@@ -533,13 +1111,7 @@ func (ms *builtIn) execute(ctx context.Context, trajectory motionplan.Trajectory
 			if !ok {
 				return fmt.Errorf("plan had step for resource %s but no resource with that name found in framesystem", name)
 			}
-			if err := r.GoToInputs(ctx, inputs...); err != nil {
-				// If there is an error on GoToInputs, stop the component if possible before returning the error
-				if actuator, ok := r.(inputEnabledActuator); ok {
-					if stopErr := actuator.Stop(ctx, nil); stopErr != nil {
-						return errors.Wrap(err, stopErr.Error())
-					}
-				}
+			if err := goToInputs(ctx, r, inputs...); err != nil {
 				return err
 			}
 		}
@@ -547,6 +1119,20 @@ func (ms *builtIn) execute(ctx context.Context, trajectory motionplan.Trajectory
 	return nil
 }
 
+// goToInputs moves the resource through the given inputs, stopping it if possible when the move fails.
+func goToInputs(ctx context.Context, r framesystem.InputEnabled, inputs ...[]referenceframe.Input) error {
+	if err := r.GoToInputs(ctx, inputs...); err != nil {
+		// If there is an error on GoToInputs, stop the component if possible before returning the error
+		if actuator, ok := r.(inputEnabledActuator); ok {
+			if stopErr := actuator.Stop(ctx, nil); stopErr != nil {
+				return errors.Wrap(err, stopErr.Error())
+			}
+		}
+		return err
+	}
+	return nil
+}
+
 func waypointsFromRequest(
 	req motion.MoveReq,
 	fsInputs referenceframe.FrameSystemInputs,
@@ -15,6 +15,7 @@ import (
 	commonpb "go.viam.com/api/common/v1"
 	"go.viam.com/test"
 	"go.viam.com/utils/protoutils"
+	"go.viam.com/utils/testutils"
 	"google.golang.org/protobuf/encoding/protojson"
 
 	"go.viam.com/rdk/components/arm"
@@ -65,22 +66,26 @@ func TestMoveResponseString(t *testing.T) {
 	testCases := []testCase{
 		{
 			"when executeResponse.Replan is false & ReplanReason is empty and error is not nil",
-			"builtin.moveResponse{executeResponse: state.ExecuteResponse{Replan:false, ReplanReason:\"\"}, err: an error}",
+			"builtin.moveResponse{executeResponse: state.ExecuteResponse{Replan:false, ReplanReason:\"\", " +
+				"ReplanReasonCode:\"\"}, err: an error}",
 			moveResponse{err: errors.New("an error")},
 		},
 		{
 			"when executeResponse.Replan is true & ReplanReason is not empty and error is not nil",
-			"builtin.moveResponse{executeResponse: state.ExecuteResponse{Replan:true, ReplanReason:\"some reason\"}, err: an error}",
+			"builtin.moveResponse{executeResponse: state.ExecuteResponse{Replan:true, ReplanReason:\"some reason\", " +
+				"ReplanReasonCode:\"\"}, err: an error}",
 			moveResponse{executeResponse: state.ExecuteResponse{Replan: true, ReplanReason: "some reason"}, err: errors.New("an error")},
 		},
 		{
 			"when executeResponse.Replan is true & ReplanReason is not empty and error is nil",
-			"builtin.moveResponse{executeResponse: state.ExecuteResponse{Replan:true, ReplanReason:\"some reason\"}, err: <nil>}",
+			"builtin.moveResponse{executeResponse: state.ExecuteResponse{Replan:true, ReplanReason:\"some reason\", " +
+				"ReplanReasonCode:\"\"}, err: <nil>}",
 			moveResponse{executeResponse: state.ExecuteResponse{Replan: true, ReplanReason: "some reason"}},
 		},
 		{
 			"when executeResponse.Replan is false & ReplanReason is empty and error is nil",
-			"builtin.moveResponse{executeResponse: state.ExecuteResponse{Replan:false, ReplanReason:\"\"}, err: <nil>}",
+			"builtin.moveResponse{executeResponse: state.ExecuteResponse{Replan:false, ReplanReason:\"\", " +
+				"ReplanReasonCode:\"\"}, err: <nil>}",
 			moveResponse{},
 		},
 	}
@@ -100,22 +105,26 @@ func TestReplanResponseString(t *testing.T) {
 	testCases := []testCase{
 		{
 			"when replan is true and reason is non empty and error is nil",
-			"builtin.replanResponse{executeResponse: state.ExecuteResponse{Replan:true, ReplanReason:\"some reason\"}, err: <nil>}",
+			"builtin.replanResponse{executeResponse: state.ExecuteResponse{Replan:true, ReplanReason:\"some reason\", " +
+				"ReplanReasonCode:\"\"}, err: <nil>}",
 			replanResponse{executeResponse: state.ExecuteResponse{Replan: true, ReplanReason: "some reason"}},
 		},
 		{
 			"when replan is true and reason is non empty and error is not nil",
-			"builtin.replanResponse{executeResponse: state.ExecuteResponse{Replan:true, ReplanReason:\"some reason\"}, err: an error}",
+			"builtin.replanResponse{executeResponse: state.ExecuteResponse{Replan:true, ReplanReason:\"some reason\", " +
+				"ReplanReasonCode:\"\"}, err: an error}",
 			replanResponse{executeResponse: state.ExecuteResponse{Replan: true, ReplanReason: "some reason"}, err: errors.New("an error")},
 		},
 		{
 			"when replan is false and error is nil",
-			"builtin.replanResponse{executeResponse: state.ExecuteResponse{Replan:false, ReplanReason:\"\"}, err: <nil>}",
+			"builtin.replanResponse{executeResponse: state.ExecuteResponse{Replan:false, ReplanReason:\"\", " +
+				"ReplanReasonCode:\"\"}, err: <nil>}",
 			replanResponse{},
 		},
 		{
 			"when replan is false and error is not nil",
-			"builtin.replanResponse{executeResponse: state.ExecuteResponse{Replan:false, ReplanReason:\"\"}, err: an error}",
+			"builtin.replanResponse{executeResponse: state.ExecuteResponse{Replan:false, ReplanReason:\"\", " +
+				"ReplanReasonCode:\"\"}, err: an error}",
 			replanResponse{err: errors.New("an error")},
 		},
 	}
@@ -208,6 +217,37 @@ func TestArmMove(t *testing.T) {
 	})
 }
 
+func TestSimulatedArmMove(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	cfg, err := config.Read(ctx, "../data/simulated_arm.json", logger, nil)
+	test.That(t, err, test.ShouldBeNil)
+	myRobot, err := robotimpl.New(ctx, cfg, nil, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer myRobot.Close(context.Background())
+	ms, err := motion.FromRobot(myRobot, "builtin")
+	test.That(t, err, test.ShouldBeNil)
+	a, err := arm.FromRobot(myRobot, "pieceArm")
+	test.That(t, err, test.ShouldBeNil)
+
+	// the simulated arm takes time to execute the plan and reports that it is moving while it does so
+	grabPose := referenceframe.NewPoseInFrame("pieceArm", spatialmath.NewPoseFromPoint(r3.Vector{X: 0, Y: -30, Z: -50}))
+	moveErr := make(chan error, 1)
+	go func() {
+		_, err := ms.Move(ctx, motion.MoveReq{ComponentName: arm.Named("pieceArm"), Destination: grabPose})
+		moveErr <- err
+	}()
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		moving, err := a.IsMoving(ctx)
+		test.That(tb, err, test.ShouldBeNil)
+		test.That(tb, moving, test.ShouldBeTrue)
+	})
+	test.That(t, <-moveErr, test.ShouldBeNil)
+	moving, err := a.IsMoving(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, moving, test.ShouldBeFalse)
+}
+
 func TestArmMoveWithObstacles(t *testing.T) {
 	t.Run("check a movement that should not succeed due to obstacles", func(t *testing.T) {
 		ms, teardown := setupMotionServiceFromConfig(t, "../data/moving_arm.json")
@@ -851,7 +891,10 @@ func TestGetTransientDetectionsSlam(t *testing.T) {
 
 	testFn := func(t *testing.T, tc testCase) {
 		t.Helper()
-		transformedGeoms, err := mr.getTransientDetections(ctx, injectedVis, camera.Named("test-camera"))
+		snap, err := mr.snapshot(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		detector := obstacleDetector{visSrvc: injectedVis, camName: camera.Named("test-camera")}
+		transformedGeoms, err := mr.getTransientDetections(snap, detector)
 		test.That(t, err, test.ShouldBeNil)
 		test.That(t, transformedGeoms.Parent(), test.ShouldEqual, referenceframe.World)
 		test.That(t, len(transformedGeoms.Geometries()), test.ShouldEqual, 1)
@@ -1307,7 +1350,7 @@ func TestDoCommand(t *testing.T) {
 		ms, teardown := setupMotionServiceFromConfig(t, "../data/moving_arm.json")
 		defer teardown()
 
-		plan, err := ms.(*builtIn).plan(ctx, moveReq)
+		plan, _, err := ms.(*builtIn).plan(ctx, moveReq)
 		test.That(t, err, test.ShouldBeNil)
 
 		// format the command to sent DoCommand
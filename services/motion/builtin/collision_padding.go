@@ -0,0 +1,50 @@
+package builtin
+
+import (
+	"fmt"
+	"sort"
+
+	"go.viam.com/rdk/motionplan"
+)
+
+// collisionPaddingExtraKey is the key of extra through which Move, MoveOnGlobe and MoveOnMap are given the clearances in mm to keep
+// between obstacles and the frames or obstacles named by its keys, both while planning and while checking the plan during execution.
+const collisionPaddingExtraKey = "collision_padding_mm"
+
+// parseCollisionPadding reads the clearances a request keeps around frames and obstacles, ordered by name.
+func parseCollisionPadding(extra map[string]interface{}) ([]motionplan.CollisionPadding, error) {
+	raw, ok := extra[collisionPaddingExtraKey]
+	if !ok {
+		return nil, nil
+	}
+	paddingsRaw, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("could not interpret %s field as a map", collisionPaddingExtraKey)
+	}
+	paddings := make([]motionplan.CollisionPadding, 0, len(paddingsRaw))
+	for name, paddingRaw := range paddingsRaw {
+		paddingMM, ok := paddingRaw.(float64)
+		if !ok {
+			return nil, fmt.Errorf("could not interpret %s entry %s as float", collisionPaddingExtraKey, name)
+		}
+		if paddingMM < 0 {
+			return nil, fmt.Errorf("%s entry %s can't be negative", collisionPaddingExtraKey, name)
+		}
+		paddings = append(paddings, motionplan.CollisionPadding{Name: name, PaddingMM: paddingMM})
+	}
+	sort.Slice(paddings, func(i, j int) bool { return paddings[i].Name < paddings[j].Name })
+	return paddings, nil
+}
+
+// padCollisions returns a copy of the given constraints which also keeps the given clearances.
+func padCollisions(constraints *motionplan.Constraints, paddings []motionplan.CollisionPadding) *motionplan.Constraints {
+	if len(paddings) == 0 {
+		return constraints
+	}
+	padded := motionplan.NewEmptyConstraints()
+	if constraints != nil {
+		*padded = *constraints
+	}
+	padded.CollisionPadding = append(append([]motionplan.CollisionPadding{}, padded.CollisionPadding...), paddings...)
+	return padded
+}
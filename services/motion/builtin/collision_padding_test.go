@@ -0,0 +1,44 @@
+package builtin
+
+import (
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/motionplan"
+)
+
+func TestCollisionPadding(t *testing.T) {
+	t.Run("parsed from extra", func(t *testing.T) {
+		valExtra, err := newValidatedExtra(map[string]interface{}{
+			collisionPaddingExtraKey: map[string]interface{}{"rover": 150., "shelf": 50.},
+		})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, valExtra.collisionPadding, test.ShouldResemble, []motionplan.CollisionPadding{
+			{Name: "rover", PaddingMM: 150},
+			{Name: "shelf", PaddingMM: 50},
+		})
+	})
+
+	t.Run("invalid paddings are rejected", func(t *testing.T) {
+		_, err := parseCollisionPadding(map[string]interface{}{collisionPaddingExtraKey: 150.})
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = parseCollisionPadding(map[string]interface{}{collisionPaddingExtraKey: map[string]interface{}{"rover": "far"}})
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = parseCollisionPadding(map[string]interface{}{collisionPaddingExtraKey: map[string]interface{}{"rover": -1.}})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "negative")
+	})
+
+	t.Run("padded constraints are copies", func(t *testing.T) {
+		constraints := motionplan.NewEmptyConstraints()
+		constraints.AddLevelConstraint(motionplan.LevelConstraint{Frame: "arm", ToleranceDegs: 5})
+		padded := padCollisions(constraints, []motionplan.CollisionPadding{{Name: "rover", PaddingMM: 150}})
+		test.That(t, len(padded.GetCollisionPadding()), test.ShouldEqual, 1)
+		test.That(t, padded.GetLevelConstraint(), test.ShouldResemble, constraints.GetLevelConstraint())
+		test.That(t, constraints.GetCollisionPadding(), test.ShouldBeEmpty)
+
+		test.That(t, padCollisions(constraints, nil), test.ShouldEqual, constraints)
+		test.That(t, len(padCollisions(nil, []motionplan.CollisionPadding{{Name: "rover"}}).GetCollisionPadding()), test.ShouldEqual, 1)
+	})
+}
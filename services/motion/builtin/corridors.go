@@ -0,0 +1,230 @@
+package builtin
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// detectorCorridorConfig describes a region of the world in which transient detections from some or all obstacle detectors are
+// not trusted, for example a doorway where reflections routinely cause a detector to hallucinate obstacles.
+// It is provided to MoveOnGlobe and MoveOnMap through the "detector_corridors" key of extra.
+type detectorCorridorConfig struct {
+	Label string `json:"label"`
+	// Geometry is specified in the world frame of the request; for MoveOnGlobe this is the frame whose origin is the starting
+	// position of the base.
+	Geometry spatialmath.GeometryConfig `json:"geometry"`
+	// Cameras lists the short names of the cameras whose detections are filtered within the corridor. If empty, detections
+	// from every obstacle detector are filtered.
+	Cameras []string `json:"cameras,omitempty"`
+	// RequiredDetections down-weights rather than ignores detections in the corridor: they are only considered once they have
+	// been observed on this many consecutive obstacle polls. Zero ignores detections in the corridor entirely.
+	RequiredDetections int `json:"required_detections,omitempty"`
+}
+
+// detectorCorridor is a validated detectorCorridorConfig.
+type detectorCorridor struct {
+	label              string
+	geometry           spatialmath.Geometry
+	cameras            map[string]bool
+	requiredDetections int
+}
+
+// newDetectorCorridors parses the raw value of the "detector_corridors" key of extra.
+func newDetectorCorridors(raw interface{}) ([]*detectorCorridor, error) {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	var cfgs []detectorCorridorConfig
+	if err := json.Unmarshal(data, &cfgs); err != nil {
+		return nil, errors.Wrap(err, "could not interpret detector_corridors field as a list of corridors")
+	}
+
+	corridors := make([]*detectorCorridor, 0, len(cfgs))
+	labels := map[string]bool{}
+	for i, cfg := range cfgs {
+		if cfg.Label == "" {
+			cfg.Label = fmt.Sprintf("corridor_%d", i)
+		}
+		if labels[cfg.Label] {
+			return nil, fmt.Errorf("detector corridor label %q is not unique", cfg.Label)
+		}
+		labels[cfg.Label] = true
+		if cfg.RequiredDetections < 0 {
+			return nil, fmt.Errorf("detector corridor %q required_detections may not be negative", cfg.Label)
+		}
+		geometry, err := cfg.Geometry.ParseConfig()
+		if err != nil {
+			return nil, errors.Wrapf(err, "detector corridor %q has an invalid geometry", cfg.Label)
+		}
+		cameras := map[string]bool{}
+		for _, camera := range cfg.Cameras {
+			cameras[camera] = true
+		}
+		corridors = append(corridors, &detectorCorridor{
+			label:              cfg.Label,
+			geometry:           geometry,
+			cameras:            cameras,
+			requiredDetections: cfg.RequiredDetections,
+		})
+	}
+	return corridors, nil
+}
+
+// appliesTo returns whether the corridor filters detections made by the named camera.
+func (c *detectorCorridor) appliesTo(camName string) bool {
+	return len(c.cameras) == 0 || c.cameras[camName]
+}
+
+// contains returns whether the center of the world frame geometry lies within the corridor.
+func (c *detectorCorridor) contains(geometry spatialmath.Geometry) (bool, error) {
+	return c.containsPoint(geometry.Pose().Point())
+}
+
+// containsPoint returns whether the world frame point lies within the corridor.
+func (c *detectorCorridor) containsPoint(p r3.Vector) (bool, error) {
+	return spatialmath.NewPoint(p, "").CollidesWith(c.geometry, 0)
+}
+
+// corridorFilter removes transient detections which fall within detector corridors. It keeps track of how many consecutive
+// polls each corridor has seen detections in so that down-weighted corridors can let persistent obstacles through.
+type corridorFilter struct {
+	corridors []*detectorCorridor
+
+	mu sync.Mutex
+	// streaks maps a camera name and corridor label to the number of consecutive polls with detections in the corridor
+	streaks map[string]map[string]int
+}
+
+func newCorridorFilter(corridors []*detectorCorridor) *corridorFilter {
+	return &corridorFilter{corridors: corridors, streaks: map[string]map[string]int{}}
+}
+
+// filter returns the world frame geometries observed by the named camera which should be treated as obstacles.
+// When track is false the detection streaks are neither consulted nor updated, and every detection within a corridor is dropped;
+// this is used while planning, where a single unconfirmed detection should not cause the planner to route around a corridor.
+func (f *corridorFilter) filter(camName string, geometries []spatialmath.Geometry, track bool) ([]spatialmath.Geometry, error) {
+	if f == nil || len(f.corridors) == 0 {
+		return geometries, nil
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	streaks, ok := f.streaks[camName]
+	if !ok {
+		streaks = map[string]int{}
+		f.streaks[camName] = streaks
+	}
+
+	kept := make([]spatialmath.Geometry, 0, len(geometries))
+	suppressed := map[string][]spatialmath.Geometry{}
+	for _, geometry := range geometries {
+		if costmap, ok := geometry.(*pointcloud.BasicOctree); ok {
+			// a costmap spans both corridors and the space around them, so it is filtered cell by cell
+			outside, inside, err := f.partitionCostmap(camName, costmap)
+			if err != nil {
+				return nil, err
+			}
+			if outside != nil {
+				kept = append(kept, outside)
+			}
+			for label, cells := range inside {
+				suppressed[label] = append(suppressed[label], cells)
+			}
+			continue
+		}
+		var corridor *detectorCorridor
+		for _, c := range f.corridors {
+			if !c.appliesTo(camName) {
+				continue
+			}
+			inside, err := c.contains(geometry)
+			if err != nil {
+				return nil, err
+			}
+			if inside {
+				corridor = c
+				break
+			}
+		}
+		if corridor == nil {
+			kept = append(kept, geometry)
+			continue
+		}
+		suppressed[corridor.label] = append(suppressed[corridor.label], geometry)
+	}
+
+	for _, c := range f.corridors {
+		if !c.appliesTo(camName) {
+			continue
+		}
+		detections, seen := suppressed[c.label]
+		if !track {
+			continue
+		}
+		if !seen {
+			streaks[c.label] = 0
+			continue
+		}
+		streaks[c.label]++
+		if c.requiredDetections > 0 && streaks[c.label] >= c.requiredDetections {
+			kept = append(kept, detections...)
+		}
+	}
+	return kept, nil
+}
+
+// partitionCostmap splits the world frame costmap into the cells outside of every corridor and the cells inside each corridor.
+func (f *corridorFilter) partitionCostmap(
+	camName string,
+	costmap *pointcloud.BasicOctree,
+) (spatialmath.Geometry, map[string]spatialmath.Geometry, error) {
+	assigned := map[r3.Vector]string{}
+	var containsErr error
+	costmap.Iterate(0, 0, func(p r3.Vector, d pointcloud.Data) bool {
+		for _, c := range f.corridors {
+			if !c.appliesTo(camName) {
+				continue
+			}
+			inside, err := c.containsPoint(p)
+			if err != nil {
+				containsErr = err
+				return false
+			}
+			if inside {
+				assigned[p] = c.label
+				break
+			}
+		}
+		return true
+	})
+	if containsErr != nil {
+		return nil, nil, containsErr
+	}
+
+	outside, err := filterOctree(costmap, func(p r3.Vector, d pointcloud.Data) bool {
+		_, ok := assigned[p]
+		return !ok
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	inside := map[string]spatialmath.Geometry{}
+	for _, label := range assigned {
+		if _, ok := inside[label]; ok {
+			continue
+		}
+		cells, err := filterOctree(costmap, func(p r3.Vector, d pointcloud.Data) bool { return assigned[p] == label })
+		if err != nil {
+			return nil, nil, err
+		}
+		inside[label] = cells
+	}
+	return geometryOrNil(outside), inside, nil
+}
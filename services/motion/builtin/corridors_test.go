@@ -0,0 +1,89 @@
+package builtin
+
+import (
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/spatialmath"
+)
+
+func TestDetectorCorridors(t *testing.T) {
+	doorway := map[string]interface{}{
+		"label":    "doorway",
+		"geometry": map[string]interface{}{"type": "box", "x": 1000, "y": 1000, "z": 1000},
+		"cameras":  []interface{}{"front"},
+	}
+	hallway := map[string]interface{}{
+		"label":               "hallway",
+		"geometry":            map[string]interface{}{"type": "sphere", "r": 500, "translation": map[string]interface{}{"x": 5000}},
+		"required_detections": 2,
+	}
+
+	t.Run("parsed from extra", func(t *testing.T) {
+		valExtra, err := newValidatedExtra(map[string]interface{}{"detector_corridors": []interface{}{doorway, hallway}})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(valExtra.detectorCorridors), test.ShouldEqual, 2)
+		test.That(t, valExtra.detectorCorridors[0].label, test.ShouldEqual, "doorway")
+		test.That(t, valExtra.detectorCorridors[0].appliesTo("front"), test.ShouldBeTrue)
+		test.That(t, valExtra.detectorCorridors[0].appliesTo("rear"), test.ShouldBeFalse)
+		test.That(t, valExtra.detectorCorridors[1].appliesTo("rear"), test.ShouldBeTrue)
+	})
+
+	t.Run("invalid corridors are rejected", func(t *testing.T) {
+		_, err := newValidatedExtra(map[string]interface{}{"detector_corridors": "doorway"})
+		test.That(t, err, test.ShouldNotBeNil)
+
+		_, err = newValidatedExtra(map[string]interface{}{"detector_corridors": []interface{}{doorway, doorway}})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "not unique")
+
+		_, err = newValidatedExtra(map[string]interface{}{"detector_corridors": []interface{}{
+			map[string]interface{}{"label": "bad", "geometry": map[string]interface{}{"type": "cone"}},
+		}})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "invalid geometry")
+	})
+
+	t.Run("detections are filtered", func(t *testing.T) {
+		corridors, err := newDetectorCorridors([]interface{}{doorway, hallway})
+		test.That(t, err, test.ShouldBeNil)
+		f := newCorridorFilter(corridors)
+
+		inDoorway, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(r3.Vector{X: 100}), r3.Vector{X: 10, Y: 10, Z: 10}, "a")
+		test.That(t, err, test.ShouldBeNil)
+		inHallway, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(r3.Vector{X: 5100}), r3.Vector{X: 10, Y: 10, Z: 10}, "b")
+		test.That(t, err, test.ShouldBeNil)
+		elsewhere, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(r3.Vector{Y: 3000}), r3.Vector{X: 10, Y: 10, Z: 10}, "c")
+		test.That(t, err, test.ShouldBeNil)
+		detections := []spatialmath.Geometry{inDoorway, inHallway, elsewhere}
+
+		// the doorway only ignores the front camera
+		kept, err := f.filter("rear", detections, false)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, kept, test.ShouldResemble, []spatialmath.Geometry{inDoorway, elsewhere})
+
+		// the hallway lets detections through once they have been seen on two consecutive polls
+		kept, err = f.filter("front", detections, true)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, kept, test.ShouldResemble, []spatialmath.Geometry{elsewhere})
+		kept, err = f.filter("front", detections, true)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, kept, test.ShouldResemble, []spatialmath.Geometry{elsewhere, inHallway})
+
+		// a poll without any detection in the hallway resets the count
+		kept, err = f.filter("front", []spatialmath.Geometry{elsewhere}, true)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, kept, test.ShouldResemble, []spatialmath.Geometry{elsewhere})
+		kept, err = f.filter("front", detections, true)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, kept, test.ShouldResemble, []spatialmath.Geometry{elsewhere})
+
+		// a nil filter keeps everything
+		var nilFilter *corridorFilter
+		kept, err = nilFilter.filter("front", detections, true)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, kept, test.ShouldResemble, detections)
+	})
+}
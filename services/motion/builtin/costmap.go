@@ -0,0 +1,89 @@
+package builtin
+
+import (
+	"fmt"
+
+	"github.com/golang/geo/r3"
+
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/spatialmath"
+	viz "go.viam.com/rdk/vision"
+)
+
+const (
+	// costmapThresholdExtraKey is the key of extra through which MoveOnGlobe and MoveOnMap are given the cost in (0, 1] at which
+	// a cell of a costmap reported by an obstacle detector is treated as an obstacle.
+	costmapThresholdExtraKey = "costmap_cost_threshold"
+	defaultCostmapThreshold  = 0.5
+	// occupiedProbability is the value given to the points of a thresholded costmap, so that every remaining cell collides.
+	occupiedProbability = 100
+)
+
+// parseCostmapThreshold parses the costmap cost threshold from extra, returning the default if it is not set.
+func parseCostmapThreshold(extra map[string]interface{}) (float64, error) {
+	raw, ok := extra[costmapThresholdExtraKey]
+	if !ok {
+		return defaultCostmapThreshold, nil
+	}
+	threshold, ok := raw.(float64)
+	if !ok {
+		return 0, fmt.Errorf("could not interpret %s field as float", costmapThresholdExtraKey)
+	}
+	if threshold <= 0 || threshold > 1 {
+		return 0, fmt.Errorf("%s must be in (0, 1]", costmapThresholdExtraKey)
+	}
+	return threshold, nil
+}
+
+// isCostmap returns the octree of a detection if it is a costmap rather than a discrete object.
+func isCostmap(detection *viz.Object) (*pointcloud.BasicOctree, bool) {
+	if detection.Geometry == nil || detection.Geometry.Label() != viz.CostmapLabel {
+		return nil, false
+	}
+	octree, ok := detection.Geometry.(*pointcloud.BasicOctree)
+	return octree, ok
+}
+
+// thresholdCostmap returns an octree of the cells of the costmap whose cost is at least the threshold, or nil if there are none.
+// The cells are kept as points of a single geometry, so that checking a plan against a cluttered scene does not require one
+// geometry per detected object.
+func thresholdCostmap(costmap *pointcloud.BasicOctree, threshold float64) (*pointcloud.BasicOctree, error) {
+	minValue := int(threshold * occupiedProbability)
+	return filterOctree(costmap, func(p r3.Vector, d pointcloud.Data) bool { return d.Value() >= minValue })
+}
+
+// filterOctree returns an octree of the points of the given octree for which keep returns true, each marked as occupied, or nil
+// if there are none.
+func filterOctree(octree *pointcloud.BasicOctree, keep func(r3.Vector, pointcloud.Data) bool) (*pointcloud.BasicOctree, error) {
+	meta := octree.MetaData()
+	center := r3.Vector{X: (meta.MinX + meta.MaxX) / 2, Y: (meta.MinY + meta.MaxY) / 2, Z: (meta.MinZ + meta.MaxZ) / 2}
+	side := 2*r3.Vector{X: meta.MaxX - meta.MinX, Y: meta.MaxY - meta.MinY, Z: meta.MaxZ - meta.MinZ}.Norm() + 1
+	filtered, err := pointcloud.NewBasicOctree(center, side)
+	if err != nil {
+		return nil, err
+	}
+	var setErr error
+	octree.Iterate(0, 0, func(p r3.Vector, d pointcloud.Data) bool {
+		if !keep(p, d) {
+			return true
+		}
+		setErr = filtered.Set(p, pointcloud.NewValueData(occupiedProbability))
+		return setErr == nil
+	})
+	if setErr != nil {
+		return nil, setErr
+	}
+	if filtered.Size() == 0 {
+		return nil, nil
+	}
+	filtered.SetLabel(octree.Label())
+	return filtered, nil
+}
+
+// geometryOrNil avoids returning a typed nil octree as a non-nil geometry.
+func geometryOrNil(octree *pointcloud.BasicOctree) spatialmath.Geometry {
+	if octree == nil {
+		return nil
+	}
+	return octree
+}
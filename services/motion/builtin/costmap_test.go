@@ -0,0 +1,83 @@
+package builtin
+
+import (
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/spatialmath"
+	viz "go.viam.com/rdk/vision"
+)
+
+func TestCostmapDetections(t *testing.T) {
+	// a costmap with a costly cell 1m ahead, a cheap cell 2m ahead and a costly cell 5m ahead
+	costmap, err := pointcloud.NewBasicOctree(r3.Vector{}, 20000)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, costmap.Set(r3.Vector{X: 1000}, pointcloud.NewValueData(90)), test.ShouldBeNil)
+	test.That(t, costmap.Set(r3.Vector{X: 2000}, pointcloud.NewValueData(20)), test.ShouldBeNil)
+	test.That(t, costmap.Set(r3.Vector{X: 5000}, pointcloud.NewValueData(100)), test.ShouldBeNil)
+	costmap.SetLabel(viz.CostmapLabel)
+
+	t.Run("costmaps are recognized by label", func(t *testing.T) {
+		octree, ok := isCostmap(&viz.Object{PointCloud: costmap, Geometry: costmap})
+		test.That(t, ok, test.ShouldBeTrue)
+		test.That(t, octree, test.ShouldEqual, costmap)
+		_, ok = isCostmap(&viz.Object{PointCloud: pointcloud.New()})
+		test.That(t, ok, test.ShouldBeFalse)
+	})
+
+	t.Run("cells below the threshold are not obstacles", func(t *testing.T) {
+		thresholded, err := thresholdCostmap(costmap, defaultCostmapThreshold)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, thresholded.Size(), test.ShouldEqual, 2)
+		test.That(t, thresholded.Label(), test.ShouldEqual, viz.CostmapLabel)
+		d, ok := thresholded.At(1000, 0, 0)
+		test.That(t, ok, test.ShouldBeTrue)
+		test.That(t, d.Value(), test.ShouldEqual, occupiedProbability)
+		_, ok = thresholded.At(2000, 0, 0)
+		test.That(t, ok, test.ShouldBeFalse)
+
+		thresholded, err = thresholdCostmap(costmap, 0.1)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, thresholded.Size(), test.ShouldEqual, 3)
+
+		empty, err := pointcloud.NewBasicOctree(r3.Vector{}, 100)
+		test.That(t, err, test.ShouldBeNil)
+		thresholded, err = thresholdCostmap(empty, 0.1)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, thresholded, test.ShouldBeNil)
+	})
+
+	t.Run("threshold parsed from extra", func(t *testing.T) {
+		valExtra, err := newValidatedExtra(map[string]interface{}{costmapThresholdExtraKey: 0.8})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, valExtra.costmapThreshold, test.ShouldEqual, 0.8)
+		valExtra, err = newValidatedExtra(nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, valExtra.costmapThreshold, test.ShouldEqual, defaultCostmapThreshold)
+		for _, bad := range []interface{}{0., 2., "high"} {
+			_, err = newValidatedExtra(map[string]interface{}{costmapThresholdExtraKey: bad})
+			test.That(t, err, test.ShouldNotBeNil)
+		}
+	})
+
+	t.Run("cells within corridors are suppressed", func(t *testing.T) {
+		corridors, err := newDetectorCorridors([]interface{}{map[string]interface{}{
+			"label":    "doorway",
+			"geometry": map[string]interface{}{"type": "box", "x": 500, "y": 500, "z": 500, "translation": map[string]interface{}{"x": 1000}},
+		}})
+		test.That(t, err, test.ShouldBeNil)
+		f := newCorridorFilter(corridors)
+
+		kept, err := f.filter("front", []spatialmath.Geometry{costmap}, false)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(kept), test.ShouldEqual, 1)
+		outside, ok := kept[0].(*pointcloud.BasicOctree)
+		test.That(t, ok, test.ShouldBeTrue)
+		test.That(t, outside.Size(), test.ShouldEqual, 2)
+		_, ok = outside.At(1000, 0, 0)
+		test.That(t, ok, test.ShouldBeFalse)
+	})
+}
@@ -0,0 +1,241 @@
+package builtin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/components/base/kinematicbase"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/vision"
+)
+
+// degradationPolicy is what an execution does when one of its obstacle detectors is unavailable.
+type degradationPolicy string
+
+const (
+	// degradationPolicyAbort fails the execution. This is the policy of any obstacle detector which is not listed in the
+	// "obstacle_detector_policies" key of extra, so that obstacle detectors are required unless made optional.
+	degradationPolicyAbort degradationPolicy = "abort"
+	// degradationPolicySkip continues the execution without the detections of the unavailable obstacle detector.
+	degradationPolicySkip degradationPolicy = "skip"
+	// degradationPolicyReduceSpeed continues the execution without the detections of the unavailable obstacle detector, driving
+	// the base at a fraction of its configured speed.
+	degradationPolicyReduceSpeed degradationPolicy = "reduce_speed"
+
+	defaultDegradedSpeedScale = 0.5
+)
+
+// detectorPolicyConfig makes an obstacle detector optional by describing how an execution degrades when it is unavailable.
+// It is provided to MoveOnGlobe and MoveOnMap through the "obstacle_detector_policies" key of extra.
+type detectorPolicyConfig struct {
+	// Camera is the short name of the camera of the obstacle detector.
+	Camera string            `json:"camera"`
+	Policy degradationPolicy `json:"policy"`
+	// SpeedScale is the fraction of the configured speed the base drives at under the reduce_speed policy.
+	SpeedScale float64 `json:"speed_scale,omitempty"`
+}
+
+// detectorPolicy is a validated detectorPolicyConfig.
+type detectorPolicy struct {
+	policy     degradationPolicy
+	speedScale float64
+}
+
+// newDetectorPolicies parses the raw value of the "obstacle_detector_policies" key of extra into policies by camera short name.
+func newDetectorPolicies(raw interface{}) (map[string]detectorPolicy, error) {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	var cfgs []detectorPolicyConfig
+	if err := json.Unmarshal(data, &cfgs); err != nil {
+		return nil, errors.Wrap(err, "could not interpret obstacle_detector_policies field as a list of policies")
+	}
+
+	policies := make(map[string]detectorPolicy, len(cfgs))
+	for _, cfg := range cfgs {
+		if cfg.Camera == "" {
+			return nil, errors.New("obstacle detector policy must specify a camera")
+		}
+		if _, ok := policies[cfg.Camera]; ok {
+			return nil, fmt.Errorf("obstacle detector policy for camera %q is specified more than once", cfg.Camera)
+		}
+		p := detectorPolicy{policy: cfg.Policy}
+		switch cfg.Policy {
+		case degradationPolicyAbort, degradationPolicySkip:
+		case degradationPolicyReduceSpeed:
+			p.speedScale = cfg.SpeedScale
+			if p.speedScale == 0 {
+				p.speedScale = defaultDegradedSpeedScale
+			}
+			if p.speedScale < 0 || p.speedScale > 1 {
+				return nil, fmt.Errorf("obstacle detector policy for camera %q speed_scale must be between 0 and 1", cfg.Camera)
+			}
+		default:
+			return nil, fmt.Errorf("obstacle detector policy for camera %q has unknown policy %q", cfg.Camera, cfg.Policy)
+		}
+		policies[cfg.Camera] = p
+	}
+	return policies, nil
+}
+
+// detectorDegradation tracks which obstacle detectors of an execution are unavailable. It is shared by the replans of an
+// execution so that a detector which became unavailable keeps its policy applied to later plans.
+type detectorDegradation struct {
+	policies map[string]detectorPolicy
+
+	mu sync.Mutex
+	// unavailable maps the camera short name of each unavailable obstacle detector to why it is unavailable
+	unavailable map[string]string
+}
+
+// detectorDegradation returns the degradation tracker for an execution on the named component, starting a new one when the
+// execution is first planned.
+func (ms *builtIn) detectorDegradation(
+	componentName resource.Name,
+	policies map[string]detectorPolicy,
+	replanCount int,
+) *detectorDegradation {
+	ms.degradationMu.Lock()
+	defer ms.degradationMu.Unlock()
+	if ms.detectorDegradations == nil {
+		ms.detectorDegradations = map[resource.Name]*detectorDegradation{}
+	}
+	d, ok := ms.detectorDegradations[componentName]
+	if !ok || replanCount == 0 {
+		d = &detectorDegradation{unavailable: map[string]string{}}
+		ms.detectorDegradations[componentName] = d
+	}
+	d.policies = policies
+	return d
+}
+
+// forgetDetectorDegradation forgets the degradation tracker of an execution which has ended, unless a newer execution on the same
+// component has already replaced it.
+func (ms *builtIn) forgetDetectorDegradation(d *detectorDegradation) {
+	ms.degradationMu.Lock()
+	defer ms.degradationMu.Unlock()
+	for componentName, tracked := range ms.detectorDegradations {
+		if tracked == d {
+			delete(ms.detectorDegradations, componentName)
+		}
+	}
+}
+
+// policy returns the policy applied when the obstacle detector using the named camera is unavailable.
+func (d *detectorDegradation) policy(camName string) detectorPolicy {
+	if d == nil {
+		return detectorPolicy{policy: degradationPolicyAbort}
+	}
+	if p, ok := d.policies[camName]; ok {
+		return p
+	}
+	return detectorPolicy{policy: degradationPolicyAbort}
+}
+
+// degrade records that the obstacle detector using the named camera is unavailable, returning an error if its policy is to abort.
+func (d *detectorDegradation) degrade(camName string, reason error) error {
+	p := d.policy(camName)
+	if p.policy == degradationPolicyAbort {
+		return errors.Wrapf(reason, "obstacle detector using camera %q is unavailable", camName)
+	}
+	d.markUnavailable(camName, reason)
+	return nil
+}
+
+func (d *detectorDegradation) markUnavailable(camName string, reason error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.unavailable[camName] = reason.Error()
+}
+
+// restore records that the obstacle detector using the named camera is available again.
+func (d *detectorDegradation) restore(camName string) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.unavailable, camName)
+}
+
+// unavailableCameras returns the sorted camera short names of the unavailable obstacle detectors.
+func (d *detectorDegradation) unavailableCameras() []string {
+	if d == nil {
+		return nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	cameras := make([]string, 0, len(d.unavailable))
+	for camName := range d.unavailable {
+		cameras = append(cameras, camName)
+	}
+	sort.Strings(cameras)
+	return cameras
+}
+
+// describe returns a description of why the obstacle detector using the named camera is unavailable and the policy applied.
+func (d *detectorDegradation) describe(camName string) string {
+	d.mu.Lock()
+	reason := d.unavailable[camName]
+	d.mu.Unlock()
+	p := d.policy(camName)
+	msg := fmt.Sprintf("obstacle detector using camera %q is unavailable, applying degradation policy %s", camName, p.policy)
+	if p.policy == degradationPolicyReduceSpeed {
+		msg += fmt.Sprintf(" at %.0f%% speed", p.speedScale*100)
+	}
+	return msg + ": " + reason
+}
+
+// speedScale returns the fraction of the configured speed the base should drive at given the unavailable obstacle detectors.
+func (d *detectorDegradation) speedScale() float64 {
+	scale := 1.
+	for _, camName := range d.unavailableCameras() {
+		if p := d.policy(camName); p.policy == degradationPolicyReduceSpeed && p.speedScale < scale {
+			scale = p.speedScale
+		}
+	}
+	return scale
+}
+
+// applySpeedScale reduces the velocities of the kinematic base options by the speed scale of the unavailable obstacle detectors.
+func (d *detectorDegradation) applySpeedScale(opts kinematicbase.Options) kinematicbase.Options {
+	scale := d.speedScale()
+	opts.LinearVelocityMMPerSec *= scale
+	opts.AngularVelocityDegsPerSec *= scale
+	return opts
+}
+
+// resolveObstacleDetectors looks up the vision service of every configured obstacle detector. Detectors whose vision service
+// is missing are left out and recorded as unavailable, unless their policy is to abort.
+func (ms *builtIn) resolveObstacleDetectors(
+	ctx context.Context,
+	motionCfg *validatedMotionConfiguration,
+	degradation *detectorDegradation,
+) (map[vision.Service][]resource.Name, error) {
+	obstacleDetectors := make(map[vision.Service][]resource.Name)
+	for _, obstacleDetectorNamePair := range motionCfg.obstacleDetectors {
+		// get vision service
+		visionServiceName := obstacleDetectorNamePair.VisionServiceName
+		camName := obstacleDetectorNamePair.CameraName
+		visionSvc, ok := ms.visionServices[visionServiceName]
+		if !ok {
+			err := resource.DependencyNotFoundError(visionServiceName)
+			if degradation.policy(camName.ShortName()).policy == degradationPolicyAbort {
+				return nil, err
+			}
+			degradation.markUnavailable(camName.ShortName(), err)
+			ms.logger.CWarn(ctx, degradation.describe(camName.ShortName()))
+			continue
+		}
+
+		// add camera to vision service map
+		obstacleDetectors[visionSvc] = append(obstacleDetectors[visionSvc], camName)
+	}
+	return obstacleDetectors, nil
+}
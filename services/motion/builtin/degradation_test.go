@@ -0,0 +1,138 @@
+package builtin
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	geo "github.com/kellydunn/golang-geo"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/components/base/kinematicbase"
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/services/vision"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+	viz "go.viam.com/rdk/vision"
+)
+
+func TestObstacleDetectorDegradation(t *testing.T) {
+	ctx := context.Background()
+	origin := geo.NewPoint(0, 0)
+
+	newEnvironment := func(t *testing.T) *builtIn {
+		t.Helper()
+		_, ms, closeFunc := CreateMoveOnGlobeTestEnvironment(ctx, t, origin, 80, spatialmath.NewZeroPose())
+		t.Cleanup(func() { closeFunc(ctx) })
+		injectedVis, ok := ms.(*builtIn).visionServices[vision.Named("injectedVisionSvc")].(*inject.VisionService)
+		test.That(t, ok, test.ShouldBeTrue)
+		injectedVis.GetObjectPointCloudsFunc = func(
+			ctx context.Context, cameraName string, extra map[string]interface{},
+		) ([]*viz.Object, error) {
+			return nil, errors.New("camera disconnected")
+		}
+		return ms.(*builtIn)
+	}
+
+	newRequest := func(
+		t *testing.T, ms *builtIn, visionSvc string, policy map[string]interface{}, replanCount int,
+	) (*moveRequest, error) {
+		t.Helper()
+		var extra map[string]interface{}
+		if policy != nil {
+			extra = map[string]interface{}{"obstacle_detector_policies": []interface{}{policy}}
+		}
+		destination := spatialmath.PoseToGeoPose(spatialmath.NewGeoPose(origin, 0), spatialmath.NewPoseFromPoint(r3.Vector{Y: 3000}))
+		req := motion.MoveOnGlobeReq{
+			ComponentName:      base.Named("test-base"),
+			Destination:        destination.Location(),
+			MovementSensorName: resource.NewName(movementsensor.API, moveSensorName),
+			MotionCfg: &motion.MotionConfiguration{
+				ObstacleDetectors: []motion.ObstacleDetectorName{
+					{VisionServiceName: vision.Named(visionSvc), CameraName: camera.Named("test-camera")},
+				},
+			},
+			Extra: extra,
+		}
+		planExecutor, err := ms.newMoveOnGlobeRequest(ctx, req, nil, replanCount)
+		if err != nil {
+			return nil, err
+		}
+		mr, ok := planExecutor.(*moveRequest)
+		test.That(t, ok, test.ShouldBeTrue)
+		return mr, nil
+	}
+
+	t.Run("obstacle detectors are required by default", func(t *testing.T) {
+		ms := newEnvironment(t)
+		mr, err := newRequest(t, ms, "injectedVisionSvc", nil, 0)
+		test.That(t, err, test.ShouldBeNil)
+		_, err = mr.snapshot(ctx)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, `obstacle detector using camera "test-camera" is unavailable`)
+
+		_, err = newRequest(t, ms, "missingVisionSvc", nil, 0)
+		test.That(t, err, test.ShouldBeError, resource.DependencyNotFoundError(vision.Named("missingVisionSvc")))
+	})
+
+	t.Run("skipped obstacle detectors replan once to record the degradation", func(t *testing.T) {
+		ms := newEnvironment(t)
+		policy := map[string]interface{}{"camera": "test-camera", "policy": "skip"}
+		mr, err := newRequest(t, ms, "injectedVisionSvc", policy, 0)
+		test.That(t, err, test.ShouldBeNil)
+
+		snap, err := mr.snapshot(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(snap.detections), test.ShouldEqual, 0)
+		resp, err := mr.obstaclesIntersectPlan(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, resp.Replan, test.ShouldBeTrue)
+		test.That(t, resp.ReplanReason, test.ShouldContainSubstring, "applying degradation policy skip: camera disconnected")
+
+		// the replanned request already knows of the degradation
+		mr, err = newRequest(t, ms, "injectedVisionSvc", policy, 1)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, mr.degradation.speedScale(), test.ShouldEqual, 1)
+		resp, err = mr.obstaclesIntersectPlan(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, resp.Replan, test.ShouldBeFalse)
+
+		// a new execution starts without degradations
+		mr, err = newRequest(t, ms, "injectedVisionSvc", policy, 0)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(mr.degradedCameras), test.ShouldEqual, 0)
+
+		// and they are forgotten once it ends
+		mr.Finish()
+		test.That(t, len(ms.detectorDegradations), test.ShouldEqual, 0)
+	})
+
+	t.Run("missing vision services reduce the speed of the base", func(t *testing.T) {
+		ms := newEnvironment(t)
+		policy := map[string]interface{}{"camera": "test-camera", "policy": "reduce_speed", "speed_scale": 0.25}
+		mr, err := newRequest(t, ms, "missingVisionSvc", policy, 0)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(mr.obstacleDetectors), test.ShouldEqual, 0)
+		test.That(t, mr.degradedCameras["test-camera"], test.ShouldBeTrue)
+
+		opts := mr.degradation.applySpeedScale(kinematicbase.Options{LinearVelocityMMPerSec: 400, AngularVelocityDegsPerSec: 60})
+		test.That(t, opts.LinearVelocityMMPerSec, test.ShouldEqual, 100)
+		test.That(t, opts.AngularVelocityDegsPerSec, test.ShouldEqual, 15)
+	})
+
+	t.Run("invalid policies", func(t *testing.T) {
+		for _, policy := range []map[string]interface{}{
+			{"policy": "skip"},
+			{"camera": "test-camera", "policy": "ignore"},
+			{"camera": "test-camera", "policy": "reduce_speed", "speed_scale": 2.},
+		} {
+			_, err := newValidatedExtra(map[string]interface{}{"obstacle_detector_policies": []interface{}{policy}})
+			test.That(t, err, test.ShouldNotBeNil)
+		}
+	})
+}
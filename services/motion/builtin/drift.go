@@ -0,0 +1,34 @@
+package builtin
+
+import (
+	"context"
+
+	"github.com/golang/geo/r3"
+
+	"go.viam.com/rdk/components/base/kinematicbase"
+	"go.viam.com/rdk/resource"
+)
+
+// driftOptions returns the options with which the base compensates for the drift estimated by the drift sensor of the configuration,
+// or nil if it has none.
+func (ms *builtIn) driftOptions(motionCfg *validatedMotionConfiguration) (*kinematicbase.DriftOptions, error) {
+	if motionCfg.driftSensor.Name == "" {
+		return nil, nil
+	}
+	driftSensor, ok := ms.movementSensors[motionCfg.driftSensor]
+	if !ok {
+		return nil, resource.DependencyNotFoundError(motionCfg.driftSensor)
+	}
+	opts := kinematicbase.NewDriftOptions(func(ctx context.Context) (r3.Vector, error) {
+		velocity, err := driftSensor.LinearVelocity(ctx, nil)
+		if err != nil {
+			return r3.Vector{}, err
+		}
+		// movement sensors report velocities in m/s
+		return velocity.Mul(1e3), nil
+	})
+	if motionCfg.driftCompensation > 0 {
+		opts.Gain = motionCfg.driftCompensation
+	}
+	return opts, nil
+}
@@ -0,0 +1,47 @@
+package builtin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestDriftOptions(t *testing.T) {
+	sensor := inject.NewMovementSensor("current")
+	sensor.LinearVelocityFunc = func(ctx context.Context, extra map[string]interface{}) (r3.Vector, error) {
+		return r3.Vector{X: 0.5}, nil
+	}
+	ms := &builtIn{movementSensors: map[resource.Name]movementsensor.MovementSensor{sensor.Name(): sensor}}
+
+	vmc, err := newValidatedMotionCfg(&motion.MotionConfiguration{}, requestTypeMoveOnGlobe)
+	test.That(t, err, test.ShouldBeNil)
+	opts, err := ms.driftOptions(vmc)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, opts, test.ShouldBeNil)
+
+	vmc, err = newValidatedMotionCfg(&motion.MotionConfiguration{DriftSensor: sensor.Name(), DriftCompensation: 0.5}, requestTypeMoveOnGlobe)
+	test.That(t, err, test.ShouldBeNil)
+	opts, err = ms.driftOptions(vmc)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, opts.Gain, test.ShouldEqual, 0.5)
+	drift, err := opts.Drift(context.Background())
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, drift, test.ShouldResemble, r3.Vector{X: 500})
+
+	vmc, err = newValidatedMotionCfg(&motion.MotionConfiguration{DriftSensor: movementsensor.Named("wind")}, requestTypeMoveOnMap)
+	test.That(t, err, test.ShouldBeNil)
+	_, err = ms.driftOptions(vmc)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	for _, compensation := range []float64{-0.5, 2} {
+		_, err := newValidatedMotionCfg(&motion.MotionConfiguration{DriftCompensation: compensation}, requestTypeMoveOnMap)
+		test.That(t, err, test.ShouldNotBeNil)
+	}
+}
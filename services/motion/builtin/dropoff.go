@@ -0,0 +1,29 @@
+package builtin
+
+import (
+	"strings"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+	viz "go.viam.com/rdk/vision"
+)
+
+// isDropOff returns whether the transient obstacle is a negative obstacle, such as a cliff or a hole, reported by an obstacle
+// detector. The labels of transient obstacles end with the labels given to them by the detector.
+func isDropOff(geometry spatialmath.Geometry) bool {
+	return geometry.Label() == viz.DropOffLabel || strings.HasSuffix(geometry.Label(), "_"+viz.DropOffLabel)
+}
+
+// splitDropOffs splits the detected obstacles into the drop-offs and all others. Drop-offs do not clear by themselves, so a plan
+// blocked by one is replanned at once rather than waited on as other obstacles may be.
+func splitDropOffs(gifs *referenceframe.GeometriesInFrame) (*referenceframe.GeometriesInFrame, *referenceframe.GeometriesInFrame) {
+	dropOffs, others := []spatialmath.Geometry{}, []spatialmath.Geometry{}
+	for _, geometry := range gifs.Geometries() {
+		if isDropOff(geometry) {
+			dropOffs = append(dropOffs, geometry)
+		} else {
+			others = append(others, geometry)
+		}
+	}
+	return referenceframe.NewGeometriesInFrame(gifs.Parent(), dropOffs), referenceframe.NewGeometriesInFrame(gifs.Parent(), others)
+}
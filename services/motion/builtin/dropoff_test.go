@@ -0,0 +1,35 @@
+package builtin
+
+import (
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+	viz "go.viam.com/rdk/vision"
+)
+
+func TestSplitDropOffs(t *testing.T) {
+	newBox := func(label string) spatialmath.Geometry {
+		box, err := spatialmath.NewBox(spatialmath.NewZeroPose(), r3.Vector{X: 100, Y: 100, Z: 100}, label)
+		test.That(t, err, test.ShouldBeNil)
+		return box
+	}
+	dropOff := newBox("cam_transientObstacle_0_" + viz.DropOffLabel)
+	obstacle := newBox("cam_transientObstacle_1")
+	labeled := newBox("cam_transientObstacle_2_not" + viz.DropOffLabel)
+
+	test.That(t, isDropOff(dropOff), test.ShouldBeTrue)
+	test.That(t, isDropOff(newBox(viz.DropOffLabel)), test.ShouldBeTrue)
+	test.That(t, isDropOff(obstacle), test.ShouldBeFalse)
+	test.That(t, isDropOff(labeled), test.ShouldBeFalse)
+
+	dropOffs, others := splitDropOffs(
+		referenceframe.NewGeometriesInFrame(referenceframe.World, []spatialmath.Geometry{obstacle, dropOff, labeled}),
+	)
+	test.That(t, dropOffs.Parent(), test.ShouldEqual, referenceframe.World)
+	test.That(t, dropOffs.Geometries(), test.ShouldResemble, []spatialmath.Geometry{dropOff})
+	test.That(t, others.Geometries(), test.ShouldResemble, []spatialmath.Geometry{obstacle, labeled})
+}
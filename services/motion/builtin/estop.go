@@ -0,0 +1,172 @@
+package builtin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.viam.com/rdk/components/arm"
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot/estop"
+	"go.viam.com/rdk/services/motion/builtin/state"
+)
+
+// eStopTimeout bounds how long each arm and base is given to stop when the e-stop is engaged.
+const eStopTimeout = 5 * time.Second
+
+// errNoEStop is returned when the e-stop is engaged or reset through a motion service which does not depend on one.
+var errNoEStop = errors.New("the motion service does not depend on the e-stop of a robot")
+
+// eStopTargets holds the e-stop of the robot and what the motion service stops when it is engaged. It is kept apart from the rest of
+// the service, whose lock is held for as long as a synchronous request runs, so that the e-stop never waits on the motion it stops.
+type eStopTargets struct {
+	mu          sync.Mutex
+	service     estop.Service
+	unsubscribe func()
+	state       *state.State
+	components  map[resource.Name]resource.Resource
+	nextID      int
+	requests    map[int]context.CancelFunc
+}
+
+// set replaces the state and components which are stopped when the e-stop is engaged.
+func (t *eStopTargets) set(s *state.State, components map[resource.Name]resource.Resource) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.state = s
+	t.components = components
+}
+
+// subscribeEStop has the service stop its motion whenever the given e-stop is engaged, in place of any it was subscribed to before. A
+// nil e-stop only unsubscribes the service.
+func (ms *builtIn) subscribeEStop(service estop.Service) {
+	t := &ms.eStop
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.service == service {
+		return
+	}
+	if t.unsubscribe != nil {
+		t.unsubscribe()
+		t.unsubscribe = nil
+	}
+	t.service = service
+	if service != nil {
+		t.unsubscribe = service.Subscribe(ms.emergencyStop)
+	}
+}
+
+// engage engages the e-stop the service depends on for the given reason.
+func (t *eStopTargets) engage(ctx context.Context, reason string) error {
+	t.mu.Lock()
+	service := t.service
+	t.mu.Unlock()
+	if service == nil {
+		return errNoEStop
+	}
+	return service.Engage(ctx, reason)
+}
+
+// reset resets the e-stop the service depends on.
+func (t *eStopTargets) reset() error {
+	t.mu.Lock()
+	service := t.service
+	t.mu.Unlock()
+	if service == nil {
+		return errNoEStop
+	}
+	service.Reset()
+	return nil
+}
+
+// guardEStop returns a context for a request which moves components, which is cancelled if the e-stop is engaged while the request
+// runs, along with a function to call once the request returns. An error is returned if the e-stop is already engaged.
+func (ms *builtIn) guardEStop(ctx context.Context) (context.Context, func(), error) {
+	ctx, cancel := context.WithCancel(ctx)
+	t := &ms.eStop
+	t.mu.Lock()
+	if t.requests == nil {
+		t.requests = map[int]context.CancelFunc{}
+	}
+	id := t.nextID
+	t.nextID++
+	t.requests[id] = cancel
+	service := t.service
+	t.mu.Unlock()
+	done := func() {
+		t.mu.Lock()
+		delete(t.requests, id)
+		t.mu.Unlock()
+		cancel()
+	}
+	// the e-stop is checked once the request can be cancelled, so that it cannot be engaged unnoticed in between
+	if service != nil {
+		if err := service.Check(); err != nil {
+			done()
+			return nil, nil, err
+		}
+	}
+	return ctx, done, nil
+}
+
+// emergencyStop cancels the requests in progress, stops every execution, and stops every arm and base the service depends on, each
+// within eStopTimeout.
+func (ms *builtIn) emergencyStop(ctx context.Context, reason string) error {
+	t := &ms.eStop
+	t.mu.Lock()
+	for _, cancel := range t.requests {
+		cancel()
+	}
+	s, components := t.state, t.components
+	t.mu.Unlock()
+	ms.logger.CWarnf(ctx, "e-stop engaged, stopping all motion: %s", reason)
+
+	var wg sync.WaitGroup
+	if s != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.StopAllExecutions()
+		}()
+	}
+	var mu sync.Mutex
+	var errs []error
+	for name, component := range components {
+		var stop func(context.Context, map[string]interface{}) error
+		switch c := component.(type) {
+		case arm.Arm:
+			stop = c.Stop
+		case base.Base:
+			stop = c.Stop
+		default:
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			stopCtx, cancel := context.WithTimeout(ctx, eStopTimeout)
+			defer cancel()
+			// a component which ignores its context is not waited on past the timeout
+			stopped := make(chan error, 1)
+			go func() {
+				stopped <- stop(stopCtx, nil)
+			}()
+			var err error
+			select {
+			case err = <-stopped:
+			case <-stopCtx.Done():
+				err = stopCtx.Err()
+			}
+			if err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("failed to stop %s: %w", name, err))
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
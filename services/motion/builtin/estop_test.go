@@ -0,0 +1,100 @@
+package builtin
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/arm"
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot/estop"
+	"go.viam.com/rdk/services/motion/builtin/state"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestEmergencyStop(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	e := estop.New(logger)
+
+	var armStops, baseStops atomic.Int32
+	injectArm := inject.NewArm("arm")
+	injectArm.StopFunc = func(ctx context.Context, extra map[string]interface{}) error {
+		armStops.Add(1)
+		return nil
+	}
+	injectBase := inject.NewBase("base")
+	injectBase.StopFunc = func(ctx context.Context, extra map[string]interface{}) error {
+		baseStops.Add(1)
+		return errors.New("wheels jammed")
+	}
+	s, err := state.NewState(stateTTL, stateTTLCheckInterval, nil, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer s.Stop()
+
+	ms := &builtIn{logger: logger}
+	ms.eStop.set(s, map[resource.Name]resource.Resource{
+		arm.Named("arm"):   injectArm,
+		base.Named("base"): injectBase,
+	})
+	ms.subscribeEStop(e)
+	defer ms.subscribeEStop(nil)
+
+	reqCtx, done, err := ms.guardEStop(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	defer done()
+
+	err = e.Engage(ctx, "test")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "wheels jammed")
+	test.That(t, armStops.Load(), test.ShouldEqual, 1)
+	test.That(t, baseStops.Load(), test.ShouldEqual, 1)
+
+	// the request in progress is cancelled, and new requests are rejected until the e-stop is reset
+	select {
+	case <-reqCtx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("request was not cancelled by the e-stop")
+	}
+	_, _, err = ms.guardEStop(ctx)
+	test.That(t, estop.IsEngagedError(err), test.ShouldBeTrue)
+
+	e.Reset()
+	_, done2, err := ms.guardEStop(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	done2()
+
+	// the e-stop is engaged through DoCommand even while a synchronous request holds the lock of the service
+	ms.mu.Lock()
+	engaged := make(chan error, 1)
+	go func() {
+		_, err := ms.DoCommand(ctx, map[string]interface{}{DoEStop: "from DoCommand"})
+		engaged <- err
+	}()
+	select {
+	case err := <-engaged:
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "wheels jammed")
+	case <-time.After(time.Second):
+		t.Fatal("DoCommand waited on the lock of the service to engage the e-stop")
+	}
+	ms.mu.Unlock()
+	_, reason := e.Engaged()
+	test.That(t, reason, test.ShouldEqual, "from DoCommand")
+	_, err = ms.DoCommand(ctx, map[string]interface{}{DoResetEStop: true})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, e.Check(), test.ShouldBeNil)
+
+	// once unsubscribed, the service is not stopped by the e-stop, nor can engage it
+	ms.subscribeEStop(nil)
+	test.That(t, e.Engage(ctx, "unsubscribed"), test.ShouldBeNil)
+	test.That(t, armStops.Load(), test.ShouldEqual, 2)
+	_, err = ms.DoCommand(ctx, map[string]interface{}{DoEStop: "no e-stop"})
+	test.That(t, err, test.ShouldBeError, errNoEStop)
+}
@@ -0,0 +1,45 @@
+package builtin
+
+import (
+	"fmt"
+
+	"go.viam.com/rdk/components/base/kinematicbase"
+)
+
+// flightExtraKey is the key of extra through which a request is told that its base flies, such as a drone registered as a base. It is
+// either true, or a map which may set the min_height_mm and max_height_mm the base is planned to fly between, in the world frame of the
+// request. Flying bases are planned for in three dimensions, climbing over obstacles rather than driving around them, and MoveOnGlobe
+// requests for them may plan the altitude of their destination.
+const flightExtraKey = "flight"
+
+// parseFlight parses the flight options of the base of a request from extra, returning nil if its base does not fly.
+func parseFlight(extra map[string]interface{}) (*kinematicbase.FlightOptions, error) {
+	raw, ok := extra[flightExtraKey]
+	if !ok {
+		return nil, nil
+	}
+	flight := kinematicbase.NewFlightOptions()
+	switch value := raw.(type) {
+	case bool:
+		if !value {
+			return nil, nil
+		}
+		return flight, nil
+	case map[string]interface{}:
+		for name, field := range map[string]*float64{"min_height_mm": &flight.MinHeightMM, "max_height_mm": &flight.MaxHeightMM} {
+			rawValue, ok := value[name]
+			if !ok {
+				continue
+			}
+			if *field, ok = rawValue.(float64); !ok {
+				return nil, fmt.Errorf("could not interpret %s entry %s as float", flightExtraKey, name)
+			}
+		}
+		if flight.MaxHeightMM <= flight.MinHeightMM {
+			return nil, fmt.Errorf("%s max_height_mm must be above min_height_mm", flightExtraKey)
+		}
+		return flight, nil
+	default:
+		return nil, fmt.Errorf("could not interpret %s field as a bool or a map", flightExtraKey)
+	}
+}
@@ -0,0 +1,38 @@
+package builtin
+
+import (
+	"testing"
+
+	"go.viam.com/test"
+)
+
+func TestParseFlight(t *testing.T) {
+	flight, err := parseFlight(map[string]interface{}{})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, flight, test.ShouldBeNil)
+	flight, err = parseFlight(map[string]interface{}{flightExtraKey: false})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, flight, test.ShouldBeNil)
+
+	flight, err = parseFlight(map[string]interface{}{flightExtraKey: true})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, flight.MinHeightMM, test.ShouldEqual, 0)
+	test.That(t, flight.MaxHeightMM, test.ShouldEqual, 120000)
+
+	flight, err = parseFlight(map[string]interface{}{flightExtraKey: map[string]interface{}{"min_height_mm": -500., "max_height_mm": 3000.}})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, flight.MinHeightMM, test.ShouldEqual, -500)
+	test.That(t, flight.MaxHeightMM, test.ShouldEqual, 3000)
+	valExtra, err := newValidatedExtra(map[string]interface{}{flightExtraKey: true})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, kbOptionsFromCfg(&validatedMotionConfiguration{}, valExtra).Flight, test.ShouldNotBeNil)
+
+	for _, raw := range []interface{}{
+		"drone",
+		map[string]interface{}{"max_height_mm": "high"},
+		map[string]interface{}{"min_height_mm": 3000., "max_height_mm": 1000.},
+	} {
+		_, err := parseFlight(map[string]interface{}{flightExtraKey: raw})
+		test.That(t, err, test.ShouldNotBeNil)
+	}
+}
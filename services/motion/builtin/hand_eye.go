@@ -0,0 +1,140 @@
+package builtin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-viper/mapstructure/v2"
+	"go.viam.com/utils"
+
+	"go.viam.com/rdk/components/arm"
+	"go.viam.com/rdk/components/posetracker"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+const (
+	// defaultHandEyeSettle is how long the arm is left to settle at each calibration pose before the target is observed.
+	defaultHandEyeSettle = 500 * time.Millisecond
+	// minHandEyeSamples is the fewest observations of the target which can constrain the pose of the camera.
+	minHandEyeSamples = 3
+)
+
+// handEyeRequest describes a hand-eye calibration of a camera mounted on an arm.
+type handEyeRequest struct {
+	ArmName         string                   `mapstructure:"arm_name"`
+	PoseTrackerName string                   `mapstructure:"pose_tracker_name"`
+	BodyName        string                   `mapstructure:"body_name"`
+	Poses           []map[string]interface{} `mapstructure:"poses"`
+	SettleMS        float64                  `mapstructure:"settle_ms"`
+}
+
+// calibrateHandEye moves the arm through the requested poses while a pose tracker using the camera on the arm observes a fixed
+// target, and solves for the pose of the camera relative to the end of the arm. The result is returned as the frame config of
+// the camera, since the motion service cannot change the config of the machine.
+func (ms *builtIn) calibrateHandEye(ctx context.Context, raw interface{}) (map[string]interface{}, error) {
+	var req handEyeRequest
+	if err := mapstructure.Decode(raw, &req); err != nil {
+		return nil, err
+	}
+	if req.ArmName == "" || req.PoseTrackerName == "" || req.BodyName == "" {
+		return nil, errors.New("arm_name, pose_tracker_name and body_name are required to calibrate")
+	}
+	if len(req.Poses) < minHandEyeSamples {
+		return nil, fmt.Errorf("need at least %d poses to calibrate, got %d", minHandEyeSamples, len(req.Poses))
+	}
+	settle := defaultHandEyeSettle
+	if req.SettleMS > 0 {
+		settle = time.Duration(req.SettleMS * float64(time.Millisecond))
+	}
+	component, ok := findByShortName(ms.components, req.ArmName)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a dependency of the motion service", req.ArmName)
+	}
+	movingArm, ok := component.(arm.Arm)
+	if !ok {
+		return nil, fmt.Errorf("%q is not an arm", req.ArmName)
+	}
+	component, ok = findByShortName(ms.components, req.PoseTrackerName)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a dependency of the motion service", req.PoseTrackerName)
+	}
+	tracker, ok := component.(posetracker.PoseTracker)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a pose tracker", req.PoseTrackerName)
+	}
+
+	armPoses := make([]spatialmath.Pose, 0, len(req.Poses))
+	targetPoses := make([]spatialmath.Pose, 0, len(req.Poses))
+	for i, rawPose := range req.Poses {
+		goal, err := poseFromMap(rawPose)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse calibration pose %d: %w", i, err)
+		}
+		if err := movingArm.MoveToPosition(ctx, goal, nil); err != nil {
+			return nil, fmt.Errorf("cannot move to calibration pose %d: %w", i, err)
+		}
+		if !utils.SelectContextOrWait(ctx, settle) {
+			return nil, ctx.Err()
+		}
+		armPose, err := movingArm.EndPosition(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		seen, err := tracker.Poses(ctx, []string{req.BodyName}, nil)
+		if err != nil {
+			return nil, err
+		}
+		target, ok := seen[req.BodyName]
+		if !ok || target == nil {
+			ms.logger.CWarnf(ctx, "%s does not see %s from calibration pose %d, skipping it", req.PoseTrackerName, req.BodyName, i)
+			continue
+		}
+		armPoses = append(armPoses, armPose)
+		targetPoses = append(targetPoses, target.Pose())
+	}
+	if len(armPoses) < minHandEyeSamples {
+		return nil, fmt.Errorf("the target was only seen from %d of the calibration poses, need at least %d", len(armPoses),
+			minHandEyeSamples)
+	}
+
+	cameraPose, err := spatialmath.HandEyeCalibration(armPoses, targetPoses)
+	if err != nil {
+		return nil, err
+	}
+	orientation, err := spatialmath.NewOrientationConfig(cameraPose.Orientation())
+	if err != nil {
+		return nil, err
+	}
+	frame := referenceframe.LinkConfig{
+		Parent:      req.ArmName,
+		Translation: cameraPose.Point(),
+		Orientation: orientation,
+	}
+	var frameMap map[string]interface{}
+	if err := jsonRoundTrip(frame, &frameMap); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"frame": frameMap, "samples": len(armPoses)}, nil
+}
+
+// poseFromMap parses a pose given in the form of a frame config, with a "translation" and an optional "orientation".
+func poseFromMap(raw map[string]interface{}) (spatialmath.Pose, error) {
+	var link referenceframe.LinkConfig
+	if err := jsonRoundTrip(raw, &link); err != nil {
+		return nil, err
+	}
+	return link.Pose()
+}
+
+// jsonRoundTrip converts between representations of a value through its JSON form.
+func jsonRoundTrip(from, to interface{}) error {
+	data, err := json.Marshal(from)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, to)
+}
@@ -0,0 +1,92 @@
+package builtin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestCalibrateHandEye(t *testing.T) {
+	ctx := context.Background()
+	camera := spatialmath.NewPose(r3.Vector{X: 40, Y: 10, Z: 30}, &spatialmath.OrientationVectorDegrees{OX: 1, Theta: 90})
+	target := spatialmath.NewPose(r3.Vector{X: 500, Y: 50, Z: -100}, &spatialmath.OrientationVectorDegrees{OZ: 1})
+
+	var armPose spatialmath.Pose
+	injectedArm := inject.NewArm("arm")
+	injectedArm.MoveToPositionFunc = func(ctx context.Context, to spatialmath.Pose, extra map[string]interface{}) error {
+		armPose = to
+		return nil
+	}
+	injectedArm.EndPositionFunc = func(ctx context.Context, extra map[string]interface{}) (spatialmath.Pose, error) {
+		return armPose, nil
+	}
+	visible := true
+	tracker := inject.NewPoseTracker("tags")
+	tracker.PosesFunc = func(ctx context.Context, bodyNames []string, extra map[string]interface{},
+	) (referenceframe.FrameSystemPoses, error) {
+		test.That(t, bodyNames, test.ShouldResemble, []string{"tag1"})
+		if !visible {
+			return referenceframe.FrameSystemPoses{}, nil
+		}
+		seen := spatialmath.PoseBetween(spatialmath.Compose(armPose, camera), target)
+		return referenceframe.FrameSystemPoses{"tag1": referenceframe.NewPoseInFrame("tags", seen)}, nil
+	}
+	ms := &builtIn{
+		components: map[resource.Name]resource.Resource{
+			injectedArm.Name(): injectedArm,
+			tracker.Name():     tracker,
+		},
+		logger: logging.NewTestLogger(t),
+	}
+
+	poses := []interface{}{
+		map[string]interface{}{"translation": map[string]interface{}{"x": 300, "z": 300}},
+		map[string]interface{}{
+			"translation": map[string]interface{}{"x": 320, "y": 40, "z": 280},
+			"orientation": map[string]interface{}{"type": "ov_degrees", "value": map[string]interface{}{"x": 0.2, "z": 1, "th": 20}},
+		},
+		map[string]interface{}{
+			"translation": map[string]interface{}{"x": 280, "y": -30, "z": 320},
+			"orientation": map[string]interface{}{"type": "ov_degrees", "value": map[string]interface{}{"y": 0.3, "z": 1, "th": -30}},
+		},
+	}
+	req := map[string]interface{}{
+		"arm_name":          "arm",
+		"pose_tracker_name": "tags",
+		"body_name":         "tag1",
+		"poses":             poses,
+		"settle_ms":         1,
+	}
+	result, err := ms.calibrateHandEye(ctx, req)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, result["samples"], test.ShouldEqual, 3)
+	frame := result["frame"].(map[string]interface{})
+	test.That(t, frame["parent"], test.ShouldEqual, "arm")
+	var link referenceframe.LinkConfig
+	test.That(t, jsonRoundTrip(frame, &link), test.ShouldBeNil)
+	solved, err := link.Pose()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatialmath.PoseAlmostEqualEps(solved, camera, 1e-6), test.ShouldBeTrue)
+
+	t.Run("a target which is not seen", func(t *testing.T) {
+		visible = false
+		_, err := ms.calibrateHandEye(ctx, req)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "only seen from 0")
+	})
+
+	t.Run("too few poses", func(t *testing.T) {
+		_, err := ms.calibrateHandEye(ctx, map[string]interface{}{
+			"arm_name": "arm", "pose_tracker_name": "tags", "body_name": "tag1", "poses": poses[:2],
+		})
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
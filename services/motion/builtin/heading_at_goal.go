@@ -0,0 +1,33 @@
+package builtin
+
+import (
+	"math"
+
+	"github.com/golang/geo/r3"
+
+	"go.viam.com/rdk/spatialmath"
+)
+
+// headingOrientation returns the orientation of a base facing the given compass heading, in degrees clockwise from north, in the
+// frame MoveOnGlobe plans in, whose +Y axis points north. As compass headings are left-handed, the right-handed theta is their negation.
+func headingOrientation(compassHeading float64) spatialmath.Orientation {
+	return &spatialmath.OrientationVectorDegrees{OZ: 1, Theta: -compassHeading}
+}
+
+// headingError returns the angle in degrees, in the range [-180, 180], through which a base at the given pose must turn
+// counterclockwise to face the heading of the goal.
+func headingError(goal, basePose spatialmath.Pose) float64 {
+	forward := func(o spatialmath.Orientation) r3.Vector {
+		return spatialmath.Compose(spatialmath.NewPoseFromOrientation(o), spatialmath.NewPoseFromPoint(r3.Vector{Y: 1})).Point()
+	}
+	return angleBetweenDegrees(forward(basePose.Orientation()), forward(goal.Orientation()))
+}
+
+// headingAtGoalCheck returns a check of whether a base is at the goal: within the given radius of its position, and facing within the
+// given threshold of its heading.
+func headingAtGoalCheck(goal spatialmath.Pose, radiusMM, thresholdDegrees float64) func(spatialmath.Pose) bool {
+	return func(basePose spatialmath.Pose) bool {
+		return spatialmath.PoseAlmostCoincidentEps(goal, basePose, radiusMM) &&
+			math.Abs(headingError(goal, basePose)) <= thresholdDegrees
+	}
+}
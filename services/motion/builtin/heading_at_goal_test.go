@@ -0,0 +1,28 @@
+package builtin
+
+import (
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/spatialmath"
+)
+
+func TestHeadingAtGoal(t *testing.T) {
+	// a base facing east, at a compass heading of 90 degrees, faces +X
+	east := spatialmath.NewPoseFromOrientation(headingOrientation(90))
+	facing := spatialmath.Compose(east, spatialmath.NewPoseFromPoint(r3.Vector{Y: 1})).Point()
+	test.That(t, spatialmath.R3VectorAlmostEqual(facing, r3.Vector{X: 1}, 1e-6), test.ShouldBeTrue)
+
+	goal := spatialmath.NewPose(r3.Vector{X: 1000, Y: 1000}, headingOrientation(90))
+	atGoal := headingAtGoalCheck(goal, 100, 8)
+	at := func(x, y, compassHeading float64) spatialmath.Pose {
+		return spatialmath.NewPose(r3.Vector{X: x, Y: y}, headingOrientation(compassHeading))
+	}
+	test.That(t, headingError(goal, at(0, 0, 0)), test.ShouldAlmostEqual, -90)
+	test.That(t, headingError(goal, at(0, 0, 350)), test.ShouldAlmostEqual, -100)
+	test.That(t, atGoal(at(1050, 1000, 85)), test.ShouldBeTrue)
+	test.That(t, atGoal(at(1050, 1000, 75)), test.ShouldBeFalse)
+	test.That(t, atGoal(at(1200, 1000, 90)), test.ShouldBeFalse)
+}
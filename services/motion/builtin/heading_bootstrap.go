@@ -0,0 +1,84 @@
+package builtin
+
+import (
+	"context"
+	"fmt"
+
+	geo "github.com/kellydunn/golang-geo"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// minHeadingTrackFraction is the fraction of the requested heading calibration distance the base must actually travel for its
+// GPS track to be trusted.
+const minHeadingTrackFraction = 0.5
+
+// headingTracker returns a GPS track localizer for a movement sensor which does not support CompassHeading. The first request for a
+// MoveOnGlobe call drives the base straight ahead to estimate its heading; replans reuse the heading tracked during execution.
+func (ms *builtIn) headingTracker(
+	ctx context.Context,
+	b base.Base,
+	movementSensor movementsensor.MovementSensor,
+	origin *geo.Point,
+	calibration spatialmath.Pose,
+	calibrationMM, mmPerSec float64,
+	replanCount int,
+) (*motion.GPSTrackLocalizer, error) {
+	ms.headingMu.Lock()
+	previous, ok := ms.headingTrackers[movementSensor.Name()]
+	ms.headingMu.Unlock()
+
+	var tracker *motion.GPSTrackLocalizer
+	if ok && replanCount > 0 {
+		tracker = motion.NewGPSTrackLocalizer(movementSensor, origin, calibration, nil, previous.CompassHeading(), calibrationMM)
+	} else {
+		heading, end, err := estimateHeading(ctx, b, movementSensor, calibrationMM, mmPerSec)
+		if err != nil {
+			return nil, err
+		}
+		ms.logger.CInfof(ctx, "estimated initial heading of %.1f degrees from a %.0fmm calibration move", heading, calibrationMM)
+		tracker = motion.NewGPSTrackLocalizer(movementSensor, origin, calibration, end, heading, calibrationMM)
+	}
+
+	ms.headingMu.Lock()
+	defer ms.headingMu.Unlock()
+	if ms.headingTrackers == nil {
+		ms.headingTrackers = map[resource.Name]*motion.GPSTrackLocalizer{}
+	}
+	ms.headingTrackers[movementSensor.Name()] = tracker
+	return tracker, nil
+}
+
+// estimateHeading drives the base straight ahead for the given distance and returns the compass heading of the track between the
+// positions reported by the movement sensor before and after the move, along with the final position.
+func estimateHeading(
+	ctx context.Context,
+	b base.Base,
+	movementSensor movementsensor.MovementSensor,
+	distanceMM, mmPerSec float64,
+) (float64, *geo.Point, error) {
+	start, _, err := movementSensor.Position(ctx, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := b.MoveStraight(ctx, int(distanceMM), mmPerSec, nil); err != nil {
+		return 0, nil, err
+	}
+	end, _, err := movementSensor.Position(ctx, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	traveledMM := start.GreatCircleDistance(end) * 1e6
+	if traveledMM < distanceMM*minHeadingTrackFraction {
+		return 0, nil, fmt.Errorf(
+			"heading calibration move traveled %.0fmm of the %.0fmm requested, which is too short to estimate a heading",
+			traveledMM, distanceMM,
+		)
+	}
+	return motion.BearingToCompassHeading(start.BearingTo(end)), end, nil
+}
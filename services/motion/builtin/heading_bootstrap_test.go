@@ -0,0 +1,91 @@
+package builtin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	geo "github.com/kellydunn/golang-geo"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/base"
+	simbase "go.viam.com/rdk/components/base/kinematicbase/fake"
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestHeadingBootstrap(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	origin := spatialmath.NewGeoPose(geo.NewPoint(40, -74), 0)
+
+	// newGPS returns a movement sensor without a compass which reports the true position of the simulated base
+	newGPS := func(b *simbase.Base) *inject.MovementSensor {
+		gps := inject.NewMovementSensor("gps")
+		gps.PositionFunc = func(ctx context.Context, extra map[string]interface{}) (*geo.Point, float64, error) {
+			return spatialmath.PoseToGeoPose(origin, b.TruePose()).Location(), 0, nil
+		}
+		gps.PropertiesFunc = func(ctx context.Context, extra map[string]interface{}) (*movementsensor.Properties, error) {
+			return &movementsensor.Properties{PositionSupported: true}, nil
+		}
+		return gps
+	}
+
+	t.Run("heading is estimated from the calibration move", func(t *testing.T) {
+		// the base starts facing east
+		start := spatialmath.NewPoseFromOrientation(&spatialmath.OrientationVectorDegrees{OZ: 1, Theta: -90})
+		b := simbase.NewBase(base.Named("base"), nil, start, nil, 0, logger)
+		heading, end, err := estimateHeading(ctx, b, newGPS(b), 1000, 5000)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, heading, test.ShouldAlmostEqual, 90, 1)
+		test.That(t, spatialmath.GeoPointToPoint(end, origin.Location()).X, test.ShouldAlmostEqual, 1000, 50)
+	})
+
+	t.Run("a base which does not move cannot estimate its heading", func(t *testing.T) {
+		stuck := inject.NewBase("stuck")
+		stuck.MoveStraightFunc = func(ctx context.Context, distanceMm int, mmPerSec float64, extra map[string]interface{}) error {
+			return nil
+		}
+		b := simbase.NewBase(base.Named("base"), nil, spatialmath.NewZeroPose(), nil, 0, logger)
+		_, _, err := estimateHeading(ctx, stuck, newGPS(b), 1000, 5000)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "too short to estimate a heading")
+	})
+
+	t.Run("the tracked heading follows the base and is reused by replans", func(t *testing.T) {
+		ms := &builtIn{logger: logger}
+		b := simbase.NewBase(base.Named("base"), nil, spatialmath.NewZeroPose(), nil, 0, logger)
+		gps := newGPS(b)
+
+		tracker, err := ms.headingTracker(ctx, b, gps, origin.Location(), nil, 500, 5000, 0)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, tracker.CompassHeading(), test.ShouldAlmostEqual, 0, 1)
+
+		// turn to face west and drive far enough for the track to update the heading
+		test.That(t, b.Spin(ctx, 90, 900, nil), test.ShouldBeNil)
+		test.That(t, b.MoveStraight(ctx, 600, 5000, nil), test.ShouldBeNil)
+		pif, err := motion.TwoDLocalizer(tracker).CurrentPosition(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, tracker.CompassHeading(), test.ShouldAlmostEqual, 270, 1)
+		test.That(t, pif.Pose().Orientation().OrientationVectorDegrees().Theta, test.ShouldAlmostEqual, 90, 1)
+		test.That(t, pif.Pose().Point().Distance(r3.Vector{X: -600, Y: 500}), test.ShouldBeLessThan, 50)
+
+		// a replan does not repeat the calibration move
+		before := b.TruePose()
+		replanTracker, err := ms.headingTracker(ctx, b, gps, origin.Location(), nil, 500, 5000, 1)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, replanTracker.CompassHeading(), test.ShouldAlmostEqual, 270, 1)
+		test.That(t, spatialmath.PoseAlmostCoincidentEps(before, b.TruePose(), 1), test.ShouldBeTrue)
+	})
+
+	t.Run("heading calibration is validated", func(t *testing.T) {
+		_, err := newValidatedMotionCfg(&motion.MotionConfiguration{HeadingCalibrationMM: -1}, requestTypeMoveOnGlobe)
+		test.That(t, err, test.ShouldNotBeNil)
+		vmc, err := newValidatedMotionCfg(&motion.MotionConfiguration{HeadingCalibrationMM: 1500}, requestTypeMoveOnGlobe)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, vmc.headingCalibrationMM, test.ShouldEqual, 1500)
+	})
+}
@@ -0,0 +1,109 @@
+package builtin
+
+import (
+	"fmt"
+	"sync"
+
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/spatialmath"
+)
+
+const (
+	// planningHorizonExtraKey is the key of extra through which MoveOnGlobe is given the distance in millimeters it plans ahead of
+	// the base. When the destination is further away than the horizon, only the route towards the destination up to the horizon is
+	// planned, and the plan is extended each time the base reaches the end of it.
+	planningHorizonExtraKey = "planning_horizon_mm"
+	// horizonObstacleScale bounds the obstacles considered in each horizon to those within this many horizons of the base.
+	horizonObstacleScale = 2.
+)
+
+// planningHorizon tracks how many times the plan of an execution has been extended, so that extensions are not counted against
+// the maximum number of replans.
+type planningHorizon struct {
+	distanceMM float64
+
+	mu         sync.Mutex
+	extensions int
+}
+
+// parsePlanningHorizon parses the planning horizon from extra, returning zero if it is not set.
+func parsePlanningHorizon(extra map[string]interface{}) (float64, error) {
+	raw, ok := extra[planningHorizonExtraKey]
+	if !ok {
+		return 0, nil
+	}
+	horizon, ok := raw.(float64)
+	if !ok {
+		return 0, fmt.Errorf("could not interpret %s field as float", planningHorizonExtraKey)
+	}
+	if horizon <= 0 {
+		return 0, fmt.Errorf("%s must be positive", planningHorizonExtraKey)
+	}
+	if horizon > maxTravelDistanceMM {
+		return 0, fmt.Errorf("%s may not exceed %d kilometers", planningHorizonExtraKey, int(maxTravelDistanceMM*1e-6))
+	}
+	return horizon, nil
+}
+
+// planningHorizon returns the planning horizon of an execution on the named component, starting a new one when the execution is
+// first planned.
+func (ms *builtIn) planningHorizon(componentName resource.Name, distanceMM float64, replanCount int) *planningHorizon {
+	ms.horizonMu.Lock()
+	defer ms.horizonMu.Unlock()
+	if ms.planningHorizons == nil {
+		ms.planningHorizons = map[resource.Name]*planningHorizon{}
+	}
+	h, ok := ms.planningHorizons[componentName]
+	if !ok || replanCount == 0 {
+		h = &planningHorizon{}
+		ms.planningHorizons[componentName] = h
+	}
+	h.distanceMM = distanceMM
+	return h
+}
+
+// replans returns how many of the given replans of the execution were not extensions of its planning horizon.
+func (h *planningHorizon) replans(replanCount int) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return replanCount - h.extensions
+}
+
+func (h *planningHorizon) extend() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.extensions++
+}
+
+// goal returns the goal to plan towards this horizon: the destination if it is within the horizon, and otherwise the point the
+// horizon distance along the straight line towards it. Also returns whether the goal is the destination.
+func (h *planningHorizon) goal(destination spatialmath.Pose) (spatialmath.Pose, bool) {
+	distance := destination.Point().Norm()
+	if h == nil || h.distanceMM == 0 || distance <= h.distanceMM {
+		return destination, true
+	}
+	return spatialmath.NewPoseFromPoint(destination.Point().Mul(h.distanceMM / distance)), false
+}
+
+// filterObstacles drops the obstacles which are too far from the start of the horizon to affect its plan, so that the size of each
+// planning problem is bounded regardless of the length of the route.
+func (h *planningHorizon) filterObstacles(obstacles []spatialmath.Geometry) ([]spatialmath.Geometry, error) {
+	if h == nil || h.distanceMM == 0 {
+		return obstacles, nil
+	}
+	region, err := spatialmath.NewSphere(spatialmath.NewZeroPose(), horizonObstacleScale*h.distanceMM, "")
+	if err != nil {
+		return nil, err
+	}
+	kept := make([]spatialmath.Geometry, 0, len(obstacles))
+	for _, obstacle := range obstacles {
+		nearby, err := obstacle.CollidesWith(region, 0)
+		if err != nil {
+			return nil, err
+		}
+		if nearby {
+			kept = append(kept, obstacle)
+		}
+	}
+	return kept, nil
+}
@@ -0,0 +1,88 @@
+package builtin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	geo "github.com/kellydunn/golang-geo"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/spatialmath"
+)
+
+func TestPlanningHorizon(t *testing.T) {
+	ctx := context.Background()
+	origin := geo.NewPoint(0, 0)
+	_, ms, closeFunc := CreateMoveOnGlobeTestEnvironment(ctx, t, origin, 80, spatialmath.NewZeroPose())
+	defer closeFunc(ctx)
+
+	// a destination 10km north, beyond the maximum distance of a single plan
+	destination := spatialmath.PoseToGeoPose(spatialmath.NewGeoPose(origin, 0), spatialmath.NewPoseFromPoint(r3.Vector{Y: 1e7}))
+	farObstacle, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(r3.Vector{Y: 8000}), r3.Vector{X: 100, Y: 100, Z: 100}, "far")
+	test.That(t, err, test.ShouldBeNil)
+	newRequest := func(extra map[string]interface{}, replanCount int) (*moveRequest, error) {
+		req := motion.MoveOnGlobeReq{
+			ComponentName:      base.Named("test-base"),
+			Destination:        destination.Location(),
+			MovementSensorName: resource.NewName(movementsensor.API, moveSensorName),
+			Obstacles:          []*spatialmath.GeoGeometry{spatialmath.NewGeoGeometry(origin, []spatialmath.Geometry{farObstacle})},
+			Extra:              extra,
+		}
+		planExecutor, err := ms.(*builtIn).newMoveOnGlobeRequest(ctx, req, nil, replanCount)
+		if err != nil {
+			return nil, err
+		}
+		mr, ok := planExecutor.(*moveRequest)
+		test.That(t, ok, test.ShouldBeTrue)
+		return mr, nil
+	}
+
+	t.Run("destinations beyond the maximum travel distance require a planning horizon", func(t *testing.T) {
+		_, err := newRequest(nil, 0)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "cannot move more than")
+	})
+
+	t.Run("only the route up to the horizon is planned", func(t *testing.T) {
+		mr, err := newRequest(map[string]interface{}{planningHorizonExtraKey: 3000.}, 0)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, mr.horizon, test.ShouldNotBeNil)
+		for _, goal := range mr.planRequest.Goals[0].Poses() {
+			test.That(t, goal.Pose().Point().Norm(), test.ShouldAlmostEqual, 3000, 1)
+		}
+		// the obstacle more than two horizons away is left out of the plan request
+		obstacles := mr.planRequest.WorldState.ObstacleNames()
+		test.That(t, obstacles, test.ShouldNotContainKey, "far")
+
+		// reaching the end of the horizon extends the plan without counting as a replan
+		resp := mr.reachedGoal()
+		test.That(t, resp.Replan, test.ShouldBeTrue)
+		test.That(t, resp.ReplanReason, test.ShouldContainSubstring, "planning horizon")
+		extra := map[string]interface{}{planningHorizonExtraKey: 3000., "max_replans": 0}
+		_, err = newRequest(extra, 1)
+		test.That(t, err, test.ShouldBeNil)
+		_, err = newRequest(extra, 2)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "exceeded maximum number of replans")
+	})
+
+	t.Run("destinations within the horizon are planned to directly", func(t *testing.T) {
+		near := spatialmath.NewPoseFromPoint(r3.Vector{X: 1000})
+		goal, atDestination := (&planningHorizon{distanceMM: 3000}).goal(near)
+		test.That(t, atDestination, test.ShouldBeTrue)
+		test.That(t, spatialmath.PoseAlmostEqual(goal, near), test.ShouldBeTrue)
+		test.That(t, (&moveRequest{}).reachedGoal().Replan, test.ShouldBeFalse)
+	})
+
+	t.Run("invalid horizons", func(t *testing.T) {
+		for _, horizon := range []interface{}{-1., "far", 1e7} {
+			_, err := newValidatedExtra(map[string]interface{}{planningHorizonExtraKey: horizon})
+			test.That(t, err, test.ShouldNotBeNil)
+		}
+	})
+}
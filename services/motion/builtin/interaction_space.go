@@ -0,0 +1,43 @@
+package builtin
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/spatialmath"
+)
+
+// interactionSpacesExtraKey is the key of extra through which MoveOnGlobe and MoveOnMap are given the regions the base must keep
+// within, both while planning and while checking its plan for obstacles during execution. Each is a geometry config in the world
+// frame of the request; for MoveOnGlobe this is the frame whose origin is the starting position of the base.
+const interactionSpacesExtraKey = "interaction_spaces"
+
+// parseInteractionSpaces parses the interaction spaces of the base from extra.
+func parseInteractionSpaces(extra map[string]interface{}) ([]spatialmath.Geometry, error) {
+	raw, ok := extra[interactionSpacesExtraKey]
+	if !ok {
+		return nil, nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	var cfgs []spatialmath.GeometryConfig
+	if err := json.Unmarshal(data, &cfgs); err != nil {
+		return nil, errors.Wrapf(err, "could not interpret %s field as a list of geometries", interactionSpacesExtraKey)
+	}
+	spaces := make([]spatialmath.Geometry, 0, len(cfgs))
+	for i, cfg := range cfgs {
+		if cfg.Label == "" {
+			cfg.Label = fmt.Sprintf("interaction_space_%d", i)
+		}
+		space, err := cfg.ParseConfig()
+		if err != nil {
+			return nil, errors.Wrapf(err, "interaction space %q has an invalid geometry", cfg.Label)
+		}
+		spaces = append(spaces, space)
+	}
+	return spaces, nil
+}
@@ -0,0 +1,42 @@
+package builtin
+
+import (
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/spatialmath"
+)
+
+func TestParseInteractionSpaces(t *testing.T) {
+	t.Run("absent", func(t *testing.T) {
+		spaces, err := parseInteractionSpaces(map[string]interface{}{})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, spaces, test.ShouldBeEmpty)
+	})
+
+	t.Run("parsed from extra", func(t *testing.T) {
+		valExtra, err := newValidatedExtra(map[string]interface{}{interactionSpacesExtraKey: []interface{}{
+			map[string]interface{}{"type": "box", "x": 4000, "y": 2000, "z": 1000, "label": "warehouse"},
+			map[string]interface{}{"type": "sphere", "r": 500, "translation": map[string]interface{}{"y": 3000}},
+		}})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(valExtra.interactionSpaces), test.ShouldEqual, 2)
+		test.That(t, valExtra.interactionSpaces[0].Label(), test.ShouldEqual, "warehouse")
+		test.That(t, valExtra.interactionSpaces[1].Label(), test.ShouldEqual, "interaction_space_1")
+		test.That(t, spatialmath.R3VectorAlmostEqual(valExtra.interactionSpaces[1].Pose().Point(), r3.Vector{Y: 3000}, 1e-6), test.ShouldBeTrue)
+	})
+
+	t.Run("invalid interaction spaces are rejected", func(t *testing.T) {
+		_, err := parseInteractionSpaces(map[string]interface{}{interactionSpacesExtraKey: "warehouse"})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "could not interpret interaction_spaces field")
+
+		_, err = parseInteractionSpaces(map[string]interface{}{interactionSpacesExtraKey: []interface{}{
+			map[string]interface{}{"type": "cone"},
+		}})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "invalid geometry")
+	})
+}
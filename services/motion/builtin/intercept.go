@@ -0,0 +1,270 @@
+package builtin
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"go.uber.org/multierr"
+
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/spatialmath"
+)
+
+const (
+	// interceptExtraKey is the key of extra through which Move is told that its destination moves, such as one in a frame tracked from
+	// a pose tracker or detected by a vision service, so that the destination is polled while the plan is executed and the component
+	// is retargeted to intercept it whenever it has moved.
+	interceptExtraKey = "intercept"
+	// interceptPollingHzExtraKey is the key of extra setting how often Move polls a destination it is intercepting.
+	interceptPollingHzExtraKey = "intercept_polling_frequency_hz"
+	// interceptRetargetThresholdExtraKey is the key of extra setting how far, in mm, a destination being intercepted may move from the
+	// one planned for before Move retargets.
+	interceptRetargetThresholdExtraKey = "retarget_threshold_mm"
+	// interceptLeadTimeExtraKey is the key of extra setting how far ahead, in seconds, Move aims along the path of a destination being
+	// intercepted, as extrapolated from its velocity between its last two polls. Without it, Move aims where the destination was last.
+	interceptLeadTimeExtraKey = "intercept_lead_time_s"
+	// interceptMaxRetargetsExtraKey is the key of extra setting how many times Move retargets a destination being intercepted before
+	// failing.
+	interceptMaxRetargetsExtraKey = "max_retargets"
+	// followExtraKey is the key of extra through which Move is told to keep following a destination being intercepted once it is
+	// reached, retargeting without limit whenever it moves, until the request is cancelled.
+	followExtraKey = "follow"
+
+	// defaultInterceptPollingHz is how often Move polls a destination being intercepted if no frequency is given.
+	defaultInterceptPollingHz = 10.
+	// defaultRetargetThresholdMM is how far a destination being intercepted may move before Move retargets if no threshold is given.
+	defaultRetargetThresholdMM = 10.
+	// defaultMaxRetargets is how many times Move retargets a destination being intercepted if no maximum is given.
+	defaultMaxRetargets = 20
+)
+
+// interception describes how Move chases a destination which moves.
+type interception struct {
+	period      time.Duration
+	thresholdMM float64
+	lead        time.Duration
+	maxRetarget int
+	follow      bool
+}
+
+// parseInterception parses how Move chases a moving destination from extra, returning nil if the destination is not moving.
+func parseInterception(extra map[string]interface{}) (*interception, error) {
+	raw, ok := extra[interceptExtraKey]
+	if !ok {
+		return nil, nil
+	}
+	intercept, ok := raw.(bool)
+	if !ok {
+		return nil, fmt.Errorf("could not interpret %s field as bool", interceptExtraKey)
+	}
+	if !intercept {
+		return nil, nil
+	}
+	ic := &interception{
+		period:      time.Duration(float64(time.Second) / defaultInterceptPollingHz),
+		thresholdMM: defaultRetargetThresholdMM,
+		maxRetarget: defaultMaxRetargets,
+	}
+	// nonNegative parses the float of extra at the key, if present
+	nonNegative := func(key string, allowZero bool) (float64, bool, error) {
+		raw, ok := extra[key]
+		if !ok {
+			return 0, false, nil
+		}
+		value, ok := raw.(float64)
+		if !ok {
+			return 0, false, fmt.Errorf("could not interpret %s field as float", key)
+		}
+		if value < 0 {
+			return 0, false, fmt.Errorf("%s may not be negative", key)
+		}
+		if value == 0 && !allowZero {
+			return 0, false, fmt.Errorf("%s must be positive", key)
+		}
+		return value, true, nil
+	}
+	hz, ok, err := nonNegative(interceptPollingHzExtraKey, false)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		ic.period = time.Duration(float64(time.Second) / hz)
+	}
+	if threshold, ok, err := nonNegative(interceptRetargetThresholdExtraKey, true); err != nil {
+		return nil, err
+	} else if ok {
+		ic.thresholdMM = threshold
+	}
+	if lead, ok, err := nonNegative(interceptLeadTimeExtraKey, true); err != nil {
+		return nil, err
+	} else if ok {
+		ic.lead = time.Duration(lead * float64(time.Second))
+	}
+	if raw, ok := extra[interceptMaxRetargetsExtraKey]; ok {
+		retargets, ok := raw.(float64)
+		if !ok || retargets != math.Trunc(retargets) {
+			return nil, fmt.Errorf("could not interpret %s field as an integer", interceptMaxRetargetsExtraKey)
+		}
+		if retargets < 0 {
+			return nil, fmt.Errorf("%s may not be negative", interceptMaxRetargetsExtraKey)
+		}
+		ic.maxRetarget = int(retargets)
+	}
+	if raw, ok := extra[followExtraKey]; ok {
+		if ic.follow, ok = raw.(bool); !ok {
+			return nil, fmt.Errorf("could not interpret %s field as bool", followExtraKey)
+		}
+	}
+	return ic, nil
+}
+
+// movingTarget estimates where a moving destination will be from its two most recent observations.
+type movingTarget struct {
+	previous, last spatialmath.Pose
+	previousAt     time.Time
+	lastAt         time.Time
+}
+
+// observe records that the destination was at the given pose at the given time.
+func (mt *movingTarget) observe(pose spatialmath.Pose, at time.Time) {
+	mt.previous, mt.previousAt = mt.last, mt.lastAt
+	mt.last, mt.lastAt = pose, at
+}
+
+// predict returns where the destination will be after the given lead time if it keeps moving as it did between its last two
+// observations, keeping the orientation it was last seen in.
+func (mt *movingTarget) predict(lead time.Duration) spatialmath.Pose {
+	if mt.previous == nil || lead <= 0 {
+		return mt.last
+	}
+	dt := mt.lastAt.Sub(mt.previousAt).Seconds()
+	if dt <= 0 {
+		return mt.last
+	}
+	velocity := mt.last.Point().Sub(mt.previous.Point()).Mul(1 / dt)
+	return spatialmath.NewPose(mt.last.Point().Add(velocity.Mul(lead.Seconds())), mt.last.Orientation())
+}
+
+// executeIntercepting plans and executes Move to a destination which moves, polling it while executing and retargeting wherever it
+// is expected to be whenever it has moved further than the threshold from the destination last planned for. Unless the destination is
+// followed, this ends once the component reaches it, or fails once the maximum number of retargets is exceeded.
+func (ms *builtIn) executeIntercepting(
+	ctx context.Context,
+	req motion.MoveReq,
+	ic *interception,
+	execute func(context.Context, motionplan.Plan, *mobileBase) error,
+) error {
+	if req.Destination == nil {
+		return fmt.Errorf("%s requires a destination", interceptExtraKey)
+	}
+	if _, ok := req.Extra["waypoints"]; ok {
+		return fmt.Errorf("cannot %s a destination of a request with waypoints", interceptExtraKey)
+	}
+	if _, ok := req.Extra["goal_state"]; ok {
+		return fmt.Errorf("cannot %s a destination of a request with a goal_state", interceptExtraKey)
+	}
+	clk := ms.clock
+	if clk == nil {
+		clk = clock.New()
+	}
+	destination := req.Destination
+	target := &movingTarget{}
+	// locate polls the destination, returning where it is expected to be
+	locate := func() (spatialmath.Pose, error) {
+		pif, err := ms.fsService.TransformPose(ctx, destination, referenceframe.World, req.WorldState.Transforms())
+		if err != nil {
+			return nil, err
+		}
+		target.observe(pif.Pose(), clk.Now())
+		return target.predict(ic.lead), nil
+	}
+	aim, err := locate()
+	if err != nil {
+		return err
+	}
+	for retargets := 0; ; retargets++ {
+		req.Destination = referenceframe.NewPoseInFrame(referenceframe.World, aim)
+		plan, mobile, err := ms.plan(ctx, req)
+		if err != nil {
+			return err
+		}
+		next, err := ms.chase(ctx, clk, ic, aim, locate, plan, func(ctx context.Context) error {
+			return execute(ctx, plan, mobile)
+		})
+		if err != nil {
+			return err
+		}
+		if next == nil {
+			if !ic.follow {
+				return nil
+			}
+			// the destination has been reached, so wait for it to move away again
+			if next, err = ms.chase(ctx, clk, ic, aim, locate, plan, nil); err != nil {
+				return err
+			}
+		} else if !ic.follow && retargets >= ic.maxRetarget {
+			return fmt.Errorf("destination moved, and exceeded maximum number of retargets: %d", ic.maxRetarget)
+		}
+		ms.logger.CDebugf(ctx, "retargeting Move to intercept its destination at %v", spatialmath.PoseToProtobuf(next))
+		aim = next
+	}
+}
+
+// chase executes the plan to the aim, polling where the destination is expected to be until the plan finishes. If it is expected
+// more than the threshold away from the aim, the moving components are stopped and the new aim is returned. If execute is nil, the
+// destination is polled until it moves away or the context is done.
+func (ms *builtIn) chase(
+	ctx context.Context,
+	clk clock.Clock,
+	ic *interception,
+	aim spatialmath.Pose,
+	locate func() (spatialmath.Pose, error),
+	plan motionplan.Plan,
+	execute func(context.Context) error,
+) (spatialmath.Pose, error) {
+	executeCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	done := make(chan error, 1)
+	if execute != nil {
+		go func() {
+			done <- execute(executeCtx)
+		}()
+	}
+	// stop cancels execution, if any, and stops the components moved by the plan
+	stop := func(err error) error {
+		if execute == nil {
+			return err
+		}
+		cancel()
+		<-done
+		return multierr.Combine(err, ms.stopPlanned(ctx, plan))
+	}
+
+	ticker := clk.Ticker(ic.period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, stop(ctx.Err())
+		case err := <-done:
+			return nil, err
+		case <-ticker.C:
+			next, err := locate()
+			if err != nil {
+				return nil, stop(err)
+			}
+			if next.Point().Sub(aim.Point()).Norm() <= ic.thresholdMM {
+				continue
+			}
+			if err := stop(nil); err != nil {
+				return nil, err
+			}
+			return next, nil
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package builtin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/spatialmath"
+)
+
+func TestParseInterception(t *testing.T) {
+	for _, extra := range []map[string]interface{}{{}, {interceptExtraKey: false}} {
+		ic, err := parseInterception(extra)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, ic, test.ShouldBeNil)
+	}
+
+	ic, err := parseInterception(map[string]interface{}{interceptExtraKey: true})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ic, test.ShouldResemble, &interception{
+		period:      100 * time.Millisecond,
+		thresholdMM: defaultRetargetThresholdMM,
+		maxRetarget: defaultMaxRetargets,
+	})
+
+	ic, err = parseInterception(map[string]interface{}{
+		interceptExtraKey:                  true,
+		interceptPollingHzExtraKey:         4.,
+		interceptRetargetThresholdExtraKey: 0.,
+		interceptLeadTimeExtraKey:          0.5,
+		interceptMaxRetargetsExtraKey:      3.,
+		followExtraKey:                     true,
+	})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ic, test.ShouldResemble, &interception{
+		period:      250 * time.Millisecond,
+		lead:        500 * time.Millisecond,
+		maxRetarget: 3,
+		follow:      true,
+	})
+
+	for _, extra := range []map[string]interface{}{
+		{interceptExtraKey: "yes"},
+		{interceptExtraKey: true, interceptPollingHzExtraKey: 0.},
+		{interceptExtraKey: true, interceptRetargetThresholdExtraKey: -1.},
+		{interceptExtraKey: true, interceptLeadTimeExtraKey: "1s"},
+		{interceptExtraKey: true, interceptMaxRetargetsExtraKey: 1.5},
+		{interceptExtraKey: true, interceptMaxRetargetsExtraKey: -1.},
+		{interceptExtraKey: true, followExtraKey: 1.},
+	} {
+		_, err := parseInterception(extra)
+		test.That(t, err, test.ShouldNotBeNil)
+	}
+}
+
+func TestMovingTarget(t *testing.T) {
+	start := time.Now()
+	target := &movingTarget{}
+	target.observe(spatialmath.NewPoseFromPoint(r3.Vector{X: 100}), start)
+	// a target seen once is expected to stay where it is
+	test.That(t, spatialmath.PoseAlmostEqual(target.predict(time.Second), spatialmath.NewPoseFromPoint(r3.Vector{X: 100})), test.ShouldBeTrue)
+
+	// a conveyor carrying the target 200mm/s along y
+	orientation := &spatialmath.OrientationVectorDegrees{OZ: -1}
+	target.observe(spatialmath.NewPose(r3.Vector{X: 100, Y: 20}, orientation), start.Add(100*time.Millisecond))
+	test.That(t, spatialmath.PoseAlmostEqual(
+		target.predict(500*time.Millisecond),
+		spatialmath.NewPose(r3.Vector{X: 100, Y: 120}, orientation),
+	), test.ShouldBeTrue)
+	test.That(t, spatialmath.PoseAlmostEqual(target.predict(0), spatialmath.NewPose(r3.Vector{X: 100, Y: 20}, orientation)), test.ShouldBeTrue)
+
+	// only the latest two observations are used
+	target.observe(spatialmath.NewPose(r3.Vector{X: 100, Y: 20}, orientation), start.Add(200*time.Millisecond))
+	test.That(t, spatialmath.PoseAlmostEqual(
+		target.predict(500*time.Millisecond),
+		spatialmath.NewPose(r3.Vector{X: 100, Y: 20}, orientation),
+	), test.ShouldBeTrue)
+}
@@ -0,0 +1,108 @@
+package builtin
+
+import (
+	"fmt"
+	"sort"
+
+	"go.viam.com/rdk/components/arm"
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/referenceframe"
+)
+
+const (
+	// levelPayloadExtraKey keeps the payloads carried during a move within the given number of degrees of level, both while planning
+	// and while checking the plan during execution.
+	levelPayloadExtraKey = "level_payload_tolerance_degs"
+	// levelPayloadFramesExtraKey names the frames holding the payloads to keep level. Move defaults to the component being moved, and
+	// MoveOnGlobe and MoveOnMap default to the arms mounted on the base.
+	levelPayloadFramesExtraKey = "level_payload_frames"
+)
+
+// levelPayload describes the payloads which a request keeps level. A frame is level when its z axis is vertical, pointing either up
+// or down.
+type levelPayload struct {
+	toleranceDegs float64
+	frames        []string
+}
+
+// enabled returns whether the request keeps any payloads level.
+func (lp levelPayload) enabled() bool {
+	return lp.toleranceDegs > 0
+}
+
+// constrain returns a copy of the given constraints which keeps each payload level, using the default frames if the request named
+// none. Every frame must be in the given frame system.
+func (lp levelPayload) constrain(
+	constraints *motionplan.Constraints,
+	frameSys referenceframe.FrameSystem,
+	defaultFrames []string,
+) (*motionplan.Constraints, error) {
+	if !lp.enabled() {
+		return constraints, nil
+	}
+	frames := lp.frames
+	if len(frames) == 0 {
+		frames = defaultFrames
+	}
+	if len(frames) == 0 {
+		return nil, fmt.Errorf("found no payloads to keep level, name their frames in %s", levelPayloadFramesExtraKey)
+	}
+	levelConstraints := motionplan.NewEmptyConstraints()
+	if constraints != nil {
+		*levelConstraints = *constraints
+	}
+	levelConstraints.LevelConstraint = append([]motionplan.LevelConstraint{}, levelConstraints.LevelConstraint...)
+	for _, frame := range frames {
+		if frameSys.Frame(frame) == nil {
+			return nil, fmt.Errorf("cannot keep the payload of %s level, which is not in the frame system of the move", frame)
+		}
+		levelConstraints.AddLevelConstraint(motionplan.LevelConstraint{Frame: frame, ToleranceDegs: lp.toleranceDegs})
+	}
+	return levelConstraints, nil
+}
+
+// carriedArms returns the names of the arms in the given frame system, which for a base move are the arms mounted on the base.
+func (ms *builtIn) carriedArms(frameSys referenceframe.FrameSystem) []string {
+	var arms []string
+	for _, name := range frameSys.FrameNames() {
+		component, ok := findByShortName(ms.components, name)
+		if !ok {
+			continue
+		}
+		if _, ok := component.(arm.Arm); ok {
+			arms = append(arms, name)
+		}
+	}
+	sort.Strings(arms)
+	return arms
+}
+
+// parseLevelPayload reads which payloads a request keeps level, and how closely.
+func parseLevelPayload(extra map[string]interface{}) (levelPayload, error) {
+	raw, ok := extra[levelPayloadExtraKey]
+	if !ok {
+		return levelPayload{}, nil
+	}
+	toleranceDegs, ok := raw.(float64)
+	if !ok {
+		return levelPayload{}, fmt.Errorf("could not interpret %s field as float", levelPayloadExtraKey)
+	}
+	if toleranceDegs <= 0 || toleranceDegs >= 90 {
+		return levelPayload{}, fmt.Errorf("%s must be between 0 and 90 degrees", levelPayloadExtraKey)
+	}
+	lp := levelPayload{toleranceDegs: toleranceDegs}
+	if framesRaw, ok := extra[levelPayloadFramesExtraKey]; ok {
+		frames, ok := framesRaw.([]interface{})
+		if !ok {
+			return levelPayload{}, fmt.Errorf("could not interpret %s field as a list", levelPayloadFramesExtraKey)
+		}
+		for _, frameRaw := range frames {
+			frame, ok := frameRaw.(string)
+			if !ok {
+				return levelPayload{}, fmt.Errorf("could not interpret %s entry as string", levelPayloadFramesExtraKey)
+			}
+			lp.frames = append(lp.frames, frame)
+		}
+	}
+	return lp, nil
+}
@@ -0,0 +1,85 @@
+package builtin
+
+import (
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestLevelPayload(t *testing.T) {
+	fs := referenceframe.NewEmptyFrameSystem("test")
+	baseFrame, err := referenceframe.NewStaticFrame("base", spatialmath.NewZeroPose())
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(baseFrame, fs.World()), test.ShouldBeNil)
+	armFrame, err := referenceframe.NewTranslationalFrame("arm", r3.Vector{Z: 1}, referenceframe.Limit{Min: 0, Max: 500})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(armFrame, baseFrame), test.ShouldBeNil)
+	gripperFrame, err := referenceframe.NewStaticFrame("gripper", spatialmath.NewPoseFromPoint(r3.Vector{Z: 100}))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(gripperFrame, armFrame), test.ShouldBeNil)
+
+	t.Run("payloads are only kept level when requested", func(t *testing.T) {
+		lp, err := parseLevelPayload(nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, lp.enabled(), test.ShouldBeFalse)
+		existing := &motionplan.Constraints{OrientationConstraint: []motionplan.OrientationConstraint{{OrientationToleranceDegs: 5}}}
+		constraints, err := lp.constrain(existing, fs, []string{"arm"})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, constraints, test.ShouldEqual, existing)
+
+		for _, extra := range []map[string]interface{}{
+			{levelPayloadExtraKey: "flat"},
+			{levelPayloadExtraKey: 0.},
+			{levelPayloadExtraKey: 90.},
+			{levelPayloadExtraKey: 5., levelPayloadFramesExtraKey: "gripper"},
+			{levelPayloadExtraKey: 5., levelPayloadFramesExtraKey: []interface{}{1}},
+		} {
+			_, err := parseLevelPayload(extra)
+			test.That(t, err, test.ShouldNotBeNil)
+		}
+	})
+
+	t.Run("the named payloads are constrained alongside the request's constraints", func(t *testing.T) {
+		lp, err := parseLevelPayload(map[string]interface{}{
+			levelPayloadExtraKey:       5.,
+			levelPayloadFramesExtraKey: []interface{}{"gripper"},
+		})
+		test.That(t, err, test.ShouldBeNil)
+		existing := &motionplan.Constraints{OrientationConstraint: []motionplan.OrientationConstraint{{OrientationToleranceDegs: 5}}}
+		constraints, err := lp.constrain(existing, fs, []string{"arm"})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, constraints.OrientationConstraint, test.ShouldResemble, existing.OrientationConstraint)
+		test.That(t, constraints.LevelConstraint, test.ShouldResemble, []motionplan.LevelConstraint{{Frame: "gripper", ToleranceDegs: 5}})
+		// the request's constraints are left as they were
+		test.That(t, existing.LevelConstraint, test.ShouldBeEmpty)
+
+		lp.frames = []string{"missing"}
+		_, err = lp.constrain(nil, fs, nil)
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("the arms carried by a base are kept level by default", func(t *testing.T) {
+		lp, err := parseLevelPayload(map[string]interface{}{levelPayloadExtraKey: 5.})
+		test.That(t, err, test.ShouldBeNil)
+		a := inject.NewArm("arm")
+		b := inject.NewBase("base")
+		ms := &builtIn{components: map[resource.Name]resource.Resource{a.Name(): a, b.Name(): b}}
+		arms := ms.carriedArms(fs)
+		test.That(t, arms, test.ShouldResemble, []string{"arm"})
+		constraints, err := lp.constrain(nil, fs, arms)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, constraints.LevelConstraint, test.ShouldResemble, []motionplan.LevelConstraint{{Frame: "arm", ToleranceDegs: 5}})
+
+		ms = &builtIn{components: map[resource.Name]resource.Resource{b.Name(): b}}
+		_, err = lp.constrain(nil, fs, ms.carriedArms(fs))
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, levelPayloadFramesExtraKey)
+	})
+}
@@ -0,0 +1,107 @@
+package builtin
+
+import (
+	"context"
+	"fmt"
+
+	"go.viam.com/rdk/components/base/kinematicbase"
+	"go.viam.com/rdk/motionplan/tpspace"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+const (
+	// localPlannerExtraKey is the key of extra through which MoveOnGlobe and MoveOnMap are told to follow their plans with the local
+	// planner of the base, which steers around obstacles reported by the obstacle detectors without replanning, only replanning when
+	// the way along the plan is blocked.
+	localPlannerExtraKey = "local_planner"
+	// localPlannerHzExtraKey is the key of extra through which the rate at which the local planner chooses velocities is given.
+	localPlannerHzExtraKey = "local_planner_hz"
+)
+
+// localPlannerConfig describes whether and how fast plans are followed with the local planner of the base.
+type localPlannerConfig struct {
+	enabled bool
+	rateHz  float64
+}
+
+// parseLocalPlanner parses the local planner configuration from extra, returning a disabled config if it is not set.
+func parseLocalPlanner(extra map[string]interface{}) (localPlannerConfig, error) {
+	cfg := localPlannerConfig{}
+	if raw, ok := extra[localPlannerExtraKey]; ok {
+		if cfg.enabled, ok = raw.(bool); !ok {
+			return localPlannerConfig{}, fmt.Errorf("could not interpret %s field as bool", localPlannerExtraKey)
+		}
+	}
+	if raw, ok := extra[localPlannerHzExtraKey]; ok {
+		if !cfg.enabled {
+			return localPlannerConfig{}, fmt.Errorf("%s requires %s to be true", localPlannerHzExtraKey, localPlannerExtraKey)
+		}
+		rateHz, ok := raw.(float64)
+		if !ok {
+			return localPlannerConfig{}, fmt.Errorf("could not interpret %s field as float", localPlannerHzExtraKey)
+		}
+		if rateHz <= 0 {
+			return localPlannerConfig{}, fmt.Errorf("%s must be positive", localPlannerHzExtraKey)
+		}
+		cfg.rateHz = rateHz
+	}
+	return cfg, nil
+}
+
+// options returns the options of the local planner of the base, or nil if the local planner is not enabled. The obstacles avoided
+// by the local planner are set once the move request which reads them is created.
+func (cfg localPlannerConfig) options() *kinematicbase.LocalPlannerOptions {
+	if !cfg.enabled {
+		return nil
+	}
+	opts := kinematicbase.NewLocalPlannerOptions(nil)
+	if cfg.rateHz > 0 {
+		opts.RateHz = cfg.rateHz
+	}
+	return opts
+}
+
+// useLocalPlanner has the local planner of the base avoid the transient detections of the request, if the local planner is enabled
+// and the base supports it.
+func (mr *moveRequest) useLocalPlanner(opts kinematicbase.Options) {
+	if opts.LocalPlanner == nil {
+		return
+	}
+	if _, ok := mr.kinematicBase.Kinematics().(tpspace.PTGProvider); !ok {
+		mr.logger.Warnf("%s is ignored since the base does not use PTG kinematics", localPlannerExtraKey)
+		return
+	}
+	opts.LocalPlanner.Obstacles = mr.localObstacles
+	mr.localPlanning = true
+}
+
+// localObstacles returns the world frame transient detections of the obstacle detectors, along with any remembered obstacles, for
+// the local planner to avoid. Obstacles of the world state are not included since the global plan already avoids them.
+func (mr *moveRequest) localObstacles(ctx context.Context) ([]spatialmath.Geometry, error) {
+	snap, err := mr.snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	detectedGifs := []*referenceframe.GeometriesInFrame{}
+	obstacles := []spatialmath.Geometry{}
+	for visSrvc, cameraNames := range mr.obstacleDetectors {
+		for _, camName := range cameraNames {
+			gifs, err := mr.getTransientDetections(snap, obstacleDetector{visSrvc: visSrvc, camName: camName})
+			if err != nil {
+				return nil, err
+			}
+			geoms, err := mr.corridors.filter(camName.ShortName(), gifs.Geometries(), true)
+			if err != nil {
+				return nil, err
+			}
+			detectedGifs = append(detectedGifs, referenceframe.NewGeometriesInFrame(gifs.Parent(), geoms))
+			obstacles = append(obstacles, geoms...)
+		}
+	}
+	remembered, err := mr.recallObstacles(snap, detectedGifs)
+	if err != nil {
+		return nil, err
+	}
+	return append(obstacles, remembered...), nil
+}
@@ -0,0 +1,34 @@
+package builtin
+
+import (
+	"testing"
+
+	"go.viam.com/test"
+)
+
+func TestParseLocalPlanner(t *testing.T) {
+	cfg, err := parseLocalPlanner(nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cfg.options(), test.ShouldBeNil)
+
+	cfg, err = parseLocalPlanner(map[string]interface{}{localPlannerExtraKey: true})
+	test.That(t, err, test.ShouldBeNil)
+	opts := cfg.options()
+	test.That(t, opts, test.ShouldNotBeNil)
+	test.That(t, opts.RateHz, test.ShouldEqual, 15)
+
+	cfg, err = parseLocalPlanner(map[string]interface{}{localPlannerExtraKey: true, localPlannerHzExtraKey: 20.})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cfg.options().RateHz, test.ShouldEqual, 20)
+
+	for _, extra := range []map[string]interface{}{
+		{localPlannerExtraKey: "yes"},
+		{localPlannerHzExtraKey: 20.},
+		{localPlannerExtraKey: false, localPlannerHzExtraKey: 20.},
+		{localPlannerExtraKey: true, localPlannerHzExtraKey: 0.},
+		{localPlannerExtraKey: true, localPlannerHzExtraKey: "fast"},
+	} {
+		_, err := parseLocalPlanner(extra)
+		test.That(t, err, test.ShouldNotBeNil)
+	}
+}
@@ -0,0 +1,60 @@
+package builtin
+
+import (
+	"context"
+	"fmt"
+
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/motion"
+)
+
+// newLocalizer constructs the localizer selected by the "localizer" extra, or the given default localizer if none is selected.
+// Its sources are the resources named by the "localizer_sources" extra, or the given default source if none are named. The
+// localizer is wrapped so that reported orientations are collapsed to 2d.
+func (ms *builtIn) newLocalizer(
+	ctx context.Context,
+	valExtra validatedExtra,
+	defaultName string,
+	defaultSource resource.Resource,
+	sources motion.LocalizerSources,
+) (motion.Localizer, error) {
+	name := valExtra.localizer
+	if name == "" {
+		name = defaultName
+	}
+	if len(valExtra.localizerSources) == 0 {
+		sources.Resources = []resource.Resource{defaultSource}
+	}
+	for _, sourceName := range valExtra.localizerSources {
+		r, err := ms.localizerSource(sourceName)
+		if err != nil {
+			return nil, err
+		}
+		sources.Resources = append(sources.Resources, r)
+	}
+	localizer, err := motion.NewLocalizer(ctx, name, sources)
+	if err != nil {
+		return nil, err
+	}
+	return motion.TwoDLocalizer(localizer), nil
+}
+
+// localizerSource finds the movement sensor, slam service or component with the given short name.
+func (ms *builtIn) localizerSource(shortName string) (resource.Resource, error) {
+	for name, movementSensor := range ms.movementSensors {
+		if name.ShortName() == shortName {
+			return movementSensor, nil
+		}
+	}
+	for name, slamSvc := range ms.slamServices {
+		if name.ShortName() == shortName {
+			return slamSvc, nil
+		}
+	}
+	for name, component := range ms.components {
+		if name.ShortName() == shortName {
+			return component, nil
+		}
+	}
+	return nil, fmt.Errorf("localizer source %q is not a dependency of the motion service", shortName)
+}
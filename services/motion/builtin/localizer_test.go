@@ -0,0 +1,66 @@
+package builtin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	geo "github.com/kellydunn/golang-geo"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/spatialmath"
+)
+
+func TestLocalizerSelection(t *testing.T) {
+	ctx := context.Background()
+	origin := geo.NewPoint(0, 0)
+	destination := spatialmath.PoseToGeoPose(spatialmath.NewGeoPose(origin, 0), spatialmath.NewPoseFromPoint(r3.Vector{Y: 3000}))
+
+	newRequest := func(t *testing.T, extra map[string]interface{}) error {
+		t.Helper()
+		_, ms, closeFunc := CreateMoveOnGlobeTestEnvironment(ctx, t, origin, 80, spatialmath.NewZeroPose())
+		t.Cleanup(func() { closeFunc(ctx) })
+		_, err := ms.(*builtIn).newMoveOnGlobeRequest(ctx, motion.MoveOnGlobeReq{
+			ComponentName:      base.Named("test-base"),
+			Destination:        destination.Location(),
+			MovementSensorName: resource.NewName(movementsensor.API, moveSensorName),
+			Extra:              extra,
+		}, nil, 0)
+		return err
+	}
+
+	t.Run("localizer extras are validated", func(t *testing.T) {
+		valExtra, err := newValidatedExtra(map[string]interface{}{
+			"localizer":         motion.FusedLocalizerName,
+			"localizer_sources": []interface{}{moveSensorName},
+		})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, valExtra.localizer, test.ShouldEqual, motion.FusedLocalizerName)
+		test.That(t, valExtra.localizerSources, test.ShouldResemble, []string{moveSensorName})
+
+		_, err = newValidatedExtra(map[string]interface{}{"localizer": 1})
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = newValidatedExtra(map[string]interface{}{"localizer_sources": moveSensorName})
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("a localizer may be selected by name", func(t *testing.T) {
+		test.That(t, newRequest(t, nil), test.ShouldBeNil)
+		test.That(t, newRequest(t, map[string]interface{}{
+			"localizer":         motion.FusedLocalizerName,
+			"localizer_sources": []interface{}{moveSensorName},
+		}), test.ShouldBeNil)
+
+		err := newRequest(t, map[string]interface{}{"localizer": "dead_reckoning"})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "unknown localizer")
+
+		err = newRequest(t, map[string]interface{}{"localizer_sources": []interface{}{"missing"}})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "is not a dependency")
+	})
+}
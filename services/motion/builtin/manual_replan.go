@@ -0,0 +1,37 @@
+package builtin
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-viper/mapstructure/v2"
+)
+
+// defaultManualReplanReason is the reason recorded for a manual replan whose request does not give one.
+const defaultManualReplanReason = "requested through DoCommand"
+
+// manualReplanRequest is the input of DoReplan.
+type manualReplanRequest struct {
+	ComponentName string `mapstructure:"component_name"`
+	Reason        string `mapstructure:"reason"`
+}
+
+// requestReplan requests that the active execution on the component named by the given DoReplan input replan.
+func (ms *builtIn) requestReplan(raw interface{}) error {
+	var req manualReplanRequest
+	if err := mapstructure.Decode(raw, &req); err != nil {
+		return err
+	}
+	if req.ComponentName == "" {
+		return errors.New("component_name is required to request a replan")
+	}
+	if req.Reason == "" {
+		req.Reason = defaultManualReplanReason
+	}
+	for name := range ms.components {
+		if name.ShortName() == req.ComponentName || name.String() == req.ComponentName {
+			return ms.state.ReplanExecutionByResource(name, req.Reason)
+		}
+	}
+	return fmt.Errorf("%q is not a dependency of the motion service", req.ComponentName)
+}
@@ -0,0 +1,37 @@
+package builtin
+
+import (
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/motion/builtin/state"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestRequestReplan(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	s, err := state.NewState(stateTTL, stateTTLCheckInterval, nil, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer s.Stop()
+	ms := &builtIn{
+		logger:     logger,
+		state:      s,
+		components: map[resource.Name]resource.Resource{base.Named("base"): inject.NewBase("base")},
+	}
+
+	err = ms.requestReplan(map[string]interface{}{})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "component_name")
+
+	err = ms.requestReplan(map[string]interface{}{"component_name": "other"})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "not a dependency")
+
+	// a component with no active execution has nothing to replan
+	err = ms.requestReplan(map[string]interface{}{"component_name": "base", "reason": "operator"})
+	test.That(t, resource.IsNotFoundError(err), test.ShouldBeTrue)
+}
@@ -0,0 +1,62 @@
+package builtin
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.viam.com/rdk/services/slam"
+)
+
+// replanOnMapChangeExtraKey is the key of extra which, when true, has MoveOnMap replan whenever the map of the SLAM service
+// changes, so that plans made around a map which has since been remodeled are not followed.
+const replanOnMapChangeExtraKey = "replan_on_map_change"
+
+// mapChangePollInterval is how often the version of the SLAM map is checked. It is much longer than the obstacle polling period
+// since SLAM services which do not track their map versions have their whole map fetched to check it.
+const mapChangePollInterval = 5 * time.Second
+
+// parseReplanOnMapChange parses whether to replan when the SLAM map changes from extra.
+func parseReplanOnMapChange(extra map[string]interface{}) (bool, error) {
+	raw, ok := extra[replanOnMapChangeExtraKey]
+	if !ok {
+		return false, nil
+	}
+	replan, ok := raw.(bool)
+	if !ok {
+		return false, fmt.Errorf("could not interpret %s field as bool", replanOnMapChangeExtraKey)
+	}
+	return replan, nil
+}
+
+// mapWatcher notices when the map of a SLAM service changes from the version a plan was made around.
+type mapWatcher struct {
+	slamSvc     slam.Service
+	version     string
+	lastChecked time.Time
+}
+
+func newMapWatcher(ctx context.Context, slamSvc slam.Service) (*mapWatcher, error) {
+	version, err := slam.MapVersion(ctx, slamSvc)
+	if err != nil {
+		return nil, err
+	}
+	return &mapWatcher{slamSvc: slamSvc, version: version, lastChecked: time.Now()}, nil
+}
+
+// changed returns a description of the change if the map has changed since the plan was made, checking at most once per
+// mapChangePollInterval.
+func (w *mapWatcher) changed(ctx context.Context) (string, error) {
+	if time.Since(w.lastChecked) < mapChangePollInterval {
+		return "", nil
+	}
+	w.lastChecked = time.Now()
+	version, err := slam.MapVersion(ctx, w.slamSvc)
+	if err != nil {
+		return "", err
+	}
+	if version == w.version {
+		return "", nil
+	}
+	return fmt.Sprintf("SLAM map changed from version %s to %s", w.version, version), nil
+}
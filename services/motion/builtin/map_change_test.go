@@ -0,0 +1,52 @@
+package builtin
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/services/slam"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestMapWatcher(t *testing.T) {
+	t.Run("parsed from extra", func(t *testing.T) {
+		valExtra, err := newValidatedExtra(map[string]interface{}{replanOnMapChangeExtraKey: true})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, valExtra.replanOnMapChange, test.ShouldBeTrue)
+		valExtra, err = newValidatedExtra(map[string]interface{}{})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, valExtra.replanOnMapChange, test.ShouldBeFalse)
+		_, err = newValidatedExtra(map[string]interface{}{replanOnMapChangeExtraKey: "yes"})
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	version := "v1"
+	slamSvc := inject.NewSLAMService("slam")
+	slamSvc.DoCommandFunc = func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+		return map[string]interface{}{slam.DoMapVersion: version}, nil
+	}
+	ctx := context.Background()
+	watcher, err := newMapWatcher(ctx, slamSvc)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, watcher.version, test.ShouldEqual, "v1")
+
+	// the map is not checked again until the poll interval has passed
+	version = "v2"
+	reason, err := watcher.changed(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, reason, test.ShouldBeEmpty)
+
+	watcher.lastChecked = time.Now().Add(-mapChangePollInterval)
+	reason, err = watcher.changed(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, reason, test.ShouldContainSubstring, "v2")
+
+	version = "v1"
+	watcher.lastChecked = time.Now().Add(-mapChangePollInterval)
+	reason, err = watcher.changed(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, reason, test.ShouldBeEmpty)
+}
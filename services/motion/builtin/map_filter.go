@@ -0,0 +1,114 @@
+package builtin
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/golang/geo/r3"
+
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/spatialmath"
+)
+
+const (
+	// mapVoxelSizeExtraKey is the key of extra through which MoveOnMap is given the side length in millimeters of the voxels the SLAM
+	// map is downsampled to before planning.
+	mapVoxelSizeExtraKey = "map_voxel_size_mm"
+	// mapCropMarginExtraKey is the key of extra through which MoveOnMap is given how far in millimeters to either side of and beyond
+	// the straight line between the base and the destination the SLAM map is kept. Points of the map outside of this corridor are
+	// not planned around, so the margin must leave room for the detours the plan may need to take.
+	mapCropMarginExtraKey = "map_crop_margin_mm"
+)
+
+// mapFilter reduces the SLAM map planned around by MoveOnMap, so that planning latency is bounded for large maps.
+type mapFilter struct {
+	voxelSizeMM  float64
+	cropMarginMM float64
+}
+
+// parseMapFilter parses the map filter from extra, returning a zero filter, which keeps the whole map, if it is not set.
+func parseMapFilter(extra map[string]interface{}) (mapFilter, error) {
+	f := mapFilter{}
+	for key, value := range map[string]*float64{mapVoxelSizeExtraKey: &f.voxelSizeMM, mapCropMarginExtraKey: &f.cropMarginMM} {
+		raw, ok := extra[key]
+		if !ok {
+			continue
+		}
+		v, ok := raw.(float64)
+		if !ok {
+			return mapFilter{}, fmt.Errorf("could not interpret %s field as float", key)
+		}
+		if v <= 0 {
+			return mapFilter{}, fmt.Errorf("%s must be positive", key)
+		}
+		*value = v
+	}
+	return f, nil
+}
+
+// crops returns whether the map is cropped to the corridor between the base and the destination.
+func (f mapFilter) crops() bool {
+	return f.cropMarginMM > 0
+}
+
+// apply crops the map to the corridor between start and goal and downsamples it, returning the result as an octree for collision
+// checking. Returns nil if no points of the map remain.
+func (f mapFilter) apply(cloud pointcloud.PointCloud, start, goal r3.Vector) (*pointcloud.BasicOctree, error) {
+	if cloud.Size() == 0 {
+		return nil, nil
+	}
+	filtered := cloud
+	if f.crops() {
+		corridor, err := f.corridor(cloud.MetaData(), start, goal)
+		if err != nil {
+			return nil, err
+		}
+		if filtered, err = pointcloud.CropToGeometry(filtered, corridor); err != nil {
+			return nil, err
+		}
+	}
+	var err error
+	if f.voxelSizeMM > 0 {
+		if filtered, err = pointcloud.VoxelDownsample(filtered, f.voxelSizeMM); err != nil {
+			return nil, err
+		}
+	}
+	if filtered.Size() == 0 {
+		return nil, nil
+	}
+	if octree, ok := filtered.(*pointcloud.BasicOctree); ok {
+		return octree, nil
+	}
+
+	// the octree is padded so that clouds reduced to a single point still have a valid side length
+	meta := filtered.MetaData()
+	center := r3.Vector{X: (meta.MinX + meta.MaxX) / 2, Y: (meta.MinY + meta.MaxY) / 2, Z: (meta.MinZ + meta.MaxZ) / 2}
+	side := math.Max(meta.MaxX-meta.MinX, math.Max(meta.MaxY-meta.MinY, meta.MaxZ-meta.MinZ)) + 1
+	octree, err := pointcloud.NewBasicOctree(center, side)
+	if err != nil {
+		return nil, err
+	}
+	var setErr error
+	filtered.Iterate(0, 0, func(p r3.Vector, d pointcloud.Data) bool {
+		setErr = octree.Set(p, d)
+		return setErr == nil
+	})
+	if setErr != nil {
+		return nil, setErr
+	}
+	return octree, nil
+}
+
+// corridor returns a box around the straight line from start to goal in the XY plane of the map, padded by the crop margin on
+// every side and spanning the full height of the map.
+func (f mapFilter) corridor(meta pointcloud.MetaData, start, goal r3.Vector) (spatialmath.Geometry, error) {
+	line := r3.Vector{X: goal.X - start.X, Y: goal.Y - start.Y}
+	center := r3.Vector{X: (start.X + goal.X) / 2, Y: (start.Y + goal.Y) / 2, Z: (meta.MinZ + meta.MaxZ) / 2}
+	orientation := &spatialmath.EulerAngles{Yaw: math.Atan2(line.Y, line.X)}
+	dims := r3.Vector{
+		X: line.Norm() + 2*f.cropMarginMM,
+		Y: 2 * f.cropMarginMM,
+		Z: meta.MaxZ - meta.MinZ + 2*f.cropMarginMM,
+	}
+	return spatialmath.NewBox(spatialmath.NewPose(center, orientation), dims, "")
+}
@@ -0,0 +1,62 @@
+package builtin
+
+import (
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/pointcloud"
+)
+
+func TestMapFilter(t *testing.T) {
+	// a row of points along the diagonal from the origin to (5000, 5000), and a wall far to the side of it
+	cloud := pointcloud.New()
+	for i := 0; i <= 50; i++ {
+		test.That(t, cloud.Set(r3.Vector{X: float64(i * 100), Y: float64(i * 100)}, pointcloud.NewValueData(100)), test.ShouldBeNil)
+		test.That(t, cloud.Set(r3.Vector{X: float64(i * 100), Y: -3000, Z: 500}, pointcloud.NewValueData(100)), test.ShouldBeNil)
+	}
+	start := r3.Vector{}
+	goal := r3.Vector{X: 5000, Y: 5000}
+
+	t.Run("parsed from extra", func(t *testing.T) {
+		valExtra, err := newValidatedExtra(map[string]interface{}{mapVoxelSizeExtraKey: 50., mapCropMarginExtraKey: 1000.})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, valExtra.mapFilter, test.ShouldResemble, mapFilter{voxelSizeMM: 50, cropMarginMM: 1000})
+		valExtra, err = newValidatedExtra(map[string]interface{}{})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, valExtra.mapFilter, test.ShouldResemble, mapFilter{})
+
+		_, err = newValidatedExtra(map[string]interface{}{mapVoxelSizeExtraKey: 0.})
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = newValidatedExtra(map[string]interface{}{mapCropMarginExtraKey: "wide"})
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("cropped to the corridor between start and goal", func(t *testing.T) {
+		octree, err := mapFilter{cropMarginMM: 1000}.apply(cloud, start, goal)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, octree.Size(), test.ShouldEqual, 51)
+		_, ok := octree.At(2500, 2500, 0)
+		test.That(t, ok, test.ShouldBeTrue)
+		_, ok = octree.At(2500, -3000, 500)
+		test.That(t, ok, test.ShouldBeFalse)
+
+		// a corridor which misses every point leaves nothing to plan around
+		octree, err = mapFilter{cropMarginMM: 100}.apply(cloud, r3.Vector{X: 5000}, r3.Vector{X: 5000, Y: -2000})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, octree, test.ShouldBeNil)
+	})
+
+	t.Run("downsampled", func(t *testing.T) {
+		octree, err := mapFilter{voxelSizeMM: 1000}.apply(cloud, start, goal)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, octree.Size(), test.ShouldBeLessThan, cloud.Size()/5)
+		test.That(t, octree.MaxVal(), test.ShouldEqual, 100)
+
+		// a map reduced to a single point is still a valid octree
+		octree, err = mapFilter{voxelSizeMM: 1e5, cropMarginMM: 100}.apply(cloud, start, goal)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, octree.Size(), test.ShouldEqual, 1)
+	})
+}
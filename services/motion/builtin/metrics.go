@@ -0,0 +1,170 @@
+package builtin
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.viam.com/rdk/services/motion"
+)
+
+var (
+	// planningSecondsBuckets and executionSecondsBuckets are the upper bounds of the buckets of the planning and execution duration
+	// histograms.
+	planningSecondsBuckets  = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+	executionSecondsBuckets = []float64{1, 5, 10, 30, 60, 120, 300, 600, 1800}
+	// deviationMMBuckets are the upper bounds of the buckets of the histogram of how far bases deviate from their plans.
+	deviationMMBuckets = []float64{10, 25, 50, 100, 250, 500, 1000, 2500}
+)
+
+// replanReasonCodes are the reason codes replans are counted by, which are those plan histories report replans with.
+var replanReasonCodes = []motion.ReplanReasonCode{
+	motion.ReplanReasonUnknown,
+	motion.ReplanReasonDeviation,
+	motion.ReplanReasonObstacle,
+	motion.ReplanReasonManual,
+	motion.ReplanReasonRecovery,
+	motion.ReplanReasonHorizon,
+}
+
+// histogram counts observations into buckets by their upper bounds, as prometheus histograms do.
+type histogram struct {
+	bounds []float64
+	counts []int64
+	count  int64
+	sum    float64
+}
+
+func newHistogram(bounds []float64) *histogram {
+	return &histogram{bounds: bounds, counts: make([]int64, len(bounds))}
+}
+
+func (h *histogram) observe(value float64) {
+	h.count++
+	h.sum += value
+	for i, bound := range h.bounds {
+		if value <= bound {
+			h.counts[i]++
+		}
+	}
+}
+
+// histogramStats is a histogram as recorded by FTDC. The buckets are keyed by their upper bounds, such as le_2_5 for 2.5, and count
+// the observations no larger than their bound. Count is the number of observations, which is the count of the infinite bucket.
+type histogramStats struct {
+	Count   int64
+	Sum     float64
+	Buckets map[string]int64
+}
+
+func (h *histogram) stats() histogramStats {
+	buckets := make(map[string]int64, len(h.bounds))
+	for i, bound := range h.bounds {
+		buckets["le_"+strings.ReplaceAll(strconv.FormatFloat(bound, 'g', -1, 64), ".", "_")] = h.counts[i]
+	}
+	return histogramStats{Count: h.count, Sum: h.sum, Buckets: buckets}
+}
+
+// motionStats are the metrics of the motion service as recorded by FTDC. Replans are counted by their reason codes, such as
+// deviation, so that they fall into the same categories as the replans of plan histories.
+type motionStats struct {
+	PlansGenerated   int64
+	PlanFailures     int64
+	PlanningSeconds  histogramStats
+	Replans          map[string]int64
+	ExecutionSeconds histogramStats
+	DeviationMM      histogramStats
+}
+
+// motionMetrics counts the plans and executions of the motion service. A nil motionMetrics records nothing.
+type motionMetrics struct {
+	mu               sync.Mutex
+	plansGenerated   int64
+	planFailures     int64
+	planningSeconds  *histogram
+	replans          map[motion.ReplanReasonCode]int64
+	executionSeconds *histogram
+	deviationMM      *histogram
+}
+
+func newMotionMetrics() *motionMetrics {
+	return &motionMetrics{
+		planningSeconds:  newHistogram(planningSecondsBuckets),
+		replans:          map[motion.ReplanReasonCode]int64{},
+		executionSeconds: newHistogram(executionSecondsBuckets),
+		deviationMM:      newHistogram(deviationMMBuckets),
+	}
+}
+
+// observePlan records a plan which took the given time to generate, or to fail to.
+func (m *motionMetrics) observePlan(duration time.Duration, err error) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err != nil {
+		m.planFailures++
+		return
+	}
+	m.plansGenerated++
+	m.planningSeconds.observe(duration.Seconds())
+}
+
+// observeExecution records how long a plan was executed for, until it either finished or was replanned.
+func (m *motionMetrics) observeExecution(duration time.Duration) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.executionSeconds.observe(duration.Seconds())
+}
+
+// observeReplan records a replan with the given reason code.
+func (m *motionMetrics) observeReplan(code motion.ReplanReasonCode) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.replans[code]++
+}
+
+// observeDeviation records how far a base was from its plan.
+func (m *motionMetrics) observeDeviation(deviationMM float64) {
+	if m == nil || math.IsNaN(deviationMM) {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deviationMM.observe(deviationMM)
+}
+
+func (m *motionMetrics) stats() motionStats {
+	if m == nil {
+		m = newMotionMetrics()
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	replans := make(map[string]int64, len(replanReasonCodes))
+	for _, code := range replanReasonCodes {
+		replans[string(code)] = m.replans[code]
+	}
+	return motionStats{
+		PlansGenerated:   m.plansGenerated,
+		PlanFailures:     m.planFailures,
+		PlanningSeconds:  m.planningSeconds.stats(),
+		Replans:          replans,
+		ExecutionSeconds: m.executionSeconds.stats(),
+		DeviationMM:      m.deviationMM.stats(),
+	}
+}
+
+// Stats satisfies the ftdc.Statser interface, so that the metrics of the motion service are recorded alongside the other metrics of
+// the robot.
+func (ms *builtIn) Stats() any {
+	return ms.metrics.stats()
+}
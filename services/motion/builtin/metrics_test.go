@@ -0,0 +1,48 @@
+package builtin
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/services/motion"
+)
+
+func TestMotionMetrics(t *testing.T) {
+	m := newMotionMetrics()
+	m.observePlan(300*time.Millisecond, nil)
+	m.observePlan(2*time.Second, nil)
+	m.observePlan(time.Second, errors.New("no path"))
+	m.observeReplan(motion.ReplanReasonDeviation)
+	m.observeReplan(motion.ReplanReasonObstacle)
+	m.observeReplan(motion.ReplanReasonObstacle)
+	m.observeExecution(45 * time.Second)
+	m.observeDeviation(30)
+
+	stats := m.stats()
+	test.That(t, stats.PlansGenerated, test.ShouldEqual, 2)
+	test.That(t, stats.PlanFailures, test.ShouldEqual, 1)
+	test.That(t, stats.PlanningSeconds.Count, test.ShouldEqual, 2)
+	test.That(t, stats.PlanningSeconds.Sum, test.ShouldAlmostEqual, 2.3)
+	// buckets count every observation no larger than their bound
+	test.That(t, stats.PlanningSeconds.Buckets["le_0_25"], test.ShouldEqual, 0)
+	test.That(t, stats.PlanningSeconds.Buckets["le_0_5"], test.ShouldEqual, 1)
+	test.That(t, stats.PlanningSeconds.Buckets["le_2_5"], test.ShouldEqual, 2)
+	test.That(t, stats.PlanningSeconds.Buckets["le_60"], test.ShouldEqual, 2)
+	test.That(t, stats.Replans, test.ShouldResemble, map[string]int64{
+		"unknown": 0, "deviation": 1, "obstacle": 2, "manual": 0, "recovery": 0, "horizon": 0,
+	})
+	test.That(t, stats.ExecutionSeconds.Buckets["le_30"], test.ShouldEqual, 0)
+	test.That(t, stats.ExecutionSeconds.Buckets["le_60"], test.ShouldEqual, 1)
+	test.That(t, stats.DeviationMM.Buckets["le_25"], test.ShouldEqual, 0)
+	test.That(t, stats.DeviationMM.Buckets["le_50"], test.ShouldEqual, 1)
+
+	// the schema of the stats recorded by FTDC never changes, even before anything is observed
+	empty := (&builtIn{}).Stats().(motionStats)
+	test.That(t, len(empty.PlanningSeconds.Buckets), test.ShouldEqual, len(stats.PlanningSeconds.Buckets))
+	test.That(t, len(empty.Replans), test.ShouldEqual, len(stats.Replans))
+	test.That(t, len(empty.ExecutionSeconds.Buckets), test.ShouldEqual, len(executionSecondsBuckets))
+	test.That(t, len(empty.DeviationMM.Buckets), test.ShouldEqual, len(deviationMMBuckets))
+}
@@ -1,7 +1,6 @@
 package builtin
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"math"
@@ -44,6 +43,11 @@ type validatedMotionConfiguration struct {
 	planDeviationMM       float64
 	linearMPerSec         float64
 	angularDegsPerSec     float64
+	headingCalibrationMM  float64
+	slowdownDistanceMM    float64
+	minSpeedScale         float64
+	driftSensor           resource.Name
+	driftCompensation     float64
 }
 
 type requestType uint8
@@ -65,7 +69,44 @@ type moveRequest struct {
 	seedPlan          motionplan.Plan
 	kinematicBase     kinematicbase.KinematicBase
 	obstacleDetectors map[vision.Service][]resource.Name
-	replanCostFactor  float64
+	// degradation tracks which obstacle detectors are unavailable and the policies applied to them
+	degradation *detectorDegradation
+	// finish releases what the execution of the request holds on the motion service across its replans
+	finish func()
+	// degradedCameras are the cameras of the obstacle detectors which were unavailable when the request was created
+	degradedCameras map[string]bool
+	// corridors filters transient detections which fall in regions where the obstacle detectors are not trusted
+	corridors *corridorFilter
+	// straightLineMaxMM is the distance within which unobstructed goals are planned to with a straight line
+	straightLineMaxMM float64
+	// costmapThreshold is the cost at which the cells of costmaps reported by obstacle detectors are obstacles
+	costmapThreshold float64
+	// memory remembers transient detections across replans, and is nil if they are not to be remembered
+	memory *obstacleMemory
+	// horizon is only set if the goal of the request is the end of a planning horizon short of the destination
+	horizon *planningHorizon
+	// mapWatcher is only set if MoveOnMap is to replan when the SLAM map changes
+	mapWatcher *mapWatcher
+	// slamSvc is only set if requestType == requestTypeMoveOnMap, so that execution is paused while SLAM is lost
+	slamSvc slam.Service
+	// metrics records the plans and executions of the request
+	metrics *motionMetrics
+	// localPlanning is set if the local planner of the base steers around transient detections, in which case they do not trigger
+	// replans
+	localPlanning bool
+	// obstacleWait holds the base while transient detections block the plan before replanning, and is nil if it replans at once
+	obstacleWait *obstacleWait
+	// stall fails the execution if the base makes no progress while commanded to move, and is nil if stalls are not detected
+	stall *stallDetector
+	// slip backs the base up and replans if it covers too little of the distance it is commanded to drive, and is nil if slip is not
+	// detected
+	slip *slipDetector
+	// recovery performs the recovery behaviors of the request when replanning fails, and is nil if it has none
+	recovery *recovery
+	// maxSensorSkew is the longest span of time over which the values making up a sensor snapshot may be sampled, and is zero if
+	// snapshots are only retaken towards a target skew
+	maxSensorSkew    time.Duration
+	replanCostFactor float64
 	// TODO(RSDK-8683): remove atGoalCheck and put it in the motionplan package
 	// atGoalCheck func(basePose spatialmath.Pose) *state.ExecuteResponse
 	atGoalCheck func(basePose spatialmath.Pose) bool
@@ -85,12 +126,21 @@ type moveRequest struct {
 	position, obstacle *replanner
 }
 
-// plan creates a plan using the currentInputs of the robot and the moveRequest's planRequest.
+// Plan creates a plan using the currentInputs of the robot and the moveRequest's planRequest, recording how long planning took.
 func (mr *moveRequest) Plan(ctx context.Context) (motionplan.Plan, error) {
-	inputs, err := mr.kinematicBase.CurrentInputs(ctx)
+	start := time.Now()
+	plan, err := mr.plan(ctx)
+	mr.metrics.observePlan(time.Since(start), err)
+	return plan, err
+}
+
+// plan creates a plan using the currentInputs of the robot and the moveRequest's planRequest.
+func (mr *moveRequest) plan(ctx context.Context) (motionplan.Plan, error) {
+	snap, err := mr.snapshot(ctx)
 	if err != nil {
 		return nil, err
 	}
+	inputs := snap.kinematicBaseInputs
 	// TODO: this is really hacky and we should figure out a better place to store this information
 	if len(mr.kinematicBase.Kinematics().DoF()) == 2 {
 		inputs = inputs[:2]
@@ -108,22 +158,44 @@ func (mr *moveRequest) Plan(ctx context.Context) (motionplan.Plan, error) {
 	gifs := []*referenceframe.GeometriesInFrame{}
 	for visSrvc, cameraNames := range mr.obstacleDetectors {
 		for _, camName := range cameraNames {
-			transientGifs, err := mr.getTransientDetections(ctx, visSrvc, camName)
+			transientGifs, err := mr.getTransientDetections(snap, obstacleDetector{visSrvc: visSrvc, camName: camName})
 			if err != nil {
 				return nil, err
 			}
-			gifs = append(gifs, transientGifs)
+			geoms, err := mr.corridors.filter(camName.ShortName(), transientGifs.Geometries(), false)
+			if err != nil {
+				return nil, err
+			}
+			gifs = append(gifs, referenceframe.NewGeometriesInFrame(transientGifs.Parent(), geoms))
 		}
 	}
+	remembered, err := mr.recallObstacles(snap, gifs)
+	if err != nil {
+		return nil, err
+	}
+	if len(remembered) > 0 {
+		gifs = append(gifs, referenceframe.NewGeometriesInFrame(referenceframe.World, remembered))
+	}
 	gifs = append(gifs, existingGifs)
 
 	// update worldstate to include transient detections
 	planRequestCopy := *mr.planRequest
-	planRequestCopy.WorldState, err = referenceframe.NewWorldState(gifs, nil)
+	planRequestCopy.WorldState, err = referenceframe.NewWorldStateWithInteractionSpaces(
+		gifs, mr.planRequest.WorldState.InteractionSpaces(), nil,
+	)
 	if err != nil {
 		return nil, err
 	}
 
+	// short unobstructed moves don't need sampling based planning
+	plan, err := mr.straightLinePlan(ctx, &planRequestCopy)
+	if err != nil {
+		return nil, err
+	}
+	if plan != nil {
+		return plan, nil
+	}
+
 	// TODO(RSDK-5634): this should pass in mr.seedplan and the appropriate replanCostFactor once this bug is found and fixed.
 	return motionplan.Replan(ctx, &planRequestCopy, nil, 0)
 }
@@ -133,14 +205,32 @@ func (mr *moveRequest) Execute(ctx context.Context, plan motionplan.Plan) (state
 	cancelCtx, cancelFn := context.WithCancel(ctx)
 	defer cancelFn()
 
+	start := time.Now()
 	mr.start(cancelCtx, plan)
-	return mr.listen(cancelCtx)
+	resp, err := mr.listen(cancelCtx)
+	mr.metrics.observeExecution(time.Since(start))
+	if err == nil && resp.Replan && mr.slip.slipping() != "" {
+		// the plan stops being executed before the base backs up away from it
+		cancelFn()
+		mr.executeBackgroundWorkers.Wait()
+		if err := mr.recoverFromSlip(ctx); err != nil {
+			return state.ExecuteResponse{}, err
+		}
+	}
+	return resp, err
 }
 
 func (mr *moveRequest) AnchorGeoPose() *spatialmath.GeoPose {
 	return mr.geoPoseOrigin
 }
 
+// Finish releases what the execution of the request holds on the motion service once it has ended, implementing state.Finisher.
+func (mr *moveRequest) Finish() {
+	if mr.finish != nil {
+		mr.finish()
+	}
+}
+
 // execute attempts to follow a given Plan starting from the index percribed by waypointIndex.
 // Note that waypointIndex is an atomic int that is incremented in this function after each waypoint has been successfully reached.
 func (mr *moveRequest) execute(ctx context.Context, plan motionplan.Plan) (state.ExecuteResponse, error) {
@@ -150,7 +240,7 @@ func (mr *moveRequest) execute(ctx context.Context, plan motionplan.Plan) (state
 	// current & desired orientation
 	if resp := mr.atGoalCheck(mr.planRequest.StartState.Poses()[mr.kinematicBase.Name().ShortName()].Pose()); resp {
 		mr.logger.Info("no need to move, already within planDeviationMM of the goal")
-		return state.ExecuteResponse{Replan: false}, nil
+		return mr.reachedGoal(), nil
 	}
 
 	waypoints, err := plan.Trajectory().GetFrameInputs(mr.kinematicBase.Name().ShortName())
@@ -164,6 +254,10 @@ func (mr *moveRequest) execute(ctx context.Context, plan motionplan.Plan) (state
 		if stopErr := mr.stop(); stopErr != nil {
 			return state.ExecuteResponse{}, errors.Wrap(err, stopErr.Error())
 		}
+		// the local planner could not steer around the obstacles in its way, so a new plan must go around them
+		if errors.Is(err, kinematicbase.ErrLocalPlanBlocked) {
+			return state.ExecuteResponse{Replan: true, ReplanReason: err.Error(), ReplanReasonCode: motion.ReplanReasonObstacle}, nil
+		}
 		return state.ExecuteResponse{}, err
 	}
 
@@ -177,73 +271,131 @@ func (mr *moveRequest) execute(ctx context.Context, plan motionplan.Plan) (state
 		return state.ExecuteResponse{}, errors.New("exeuctionState.CurrentPoses() does not contain an entry for the LocalizationFrame")
 	}
 	if resp := mr.atGoalCheck(currentPosition.Pose()); !resp {
-		return state.ExecuteResponse{Replan: true, ReplanReason: "issuing a replan since we are not within planDeviationMM of the goal"}, nil
+		return state.ExecuteResponse{
+			Replan:           true,
+			ReplanReason:     "issuing a replan since we are not within planDeviationMM of the goal",
+			ReplanReasonCode: motion.ReplanReasonDeviation,
+		}, nil
+	}
+	return mr.reachedGoal(), nil
+}
+
+// reachedGoal returns the response to the base reaching the goal of the request, which extends the plan to the next planning
+// horizon if the goal is short of the destination.
+func (mr *moveRequest) reachedGoal() state.ExecuteResponse {
+	if mr.horizon == nil {
+		return state.ExecuteResponse{Replan: false}
+	}
+	mr.horizon.extend()
+	return state.ExecuteResponse{
+		Replan:           true,
+		ReplanReason:     "reached the planning horizon, extending the plan towards the destination",
+		ReplanReasonCode: motion.ReplanReasonHorizon,
 	}
-	return state.ExecuteResponse{Replan: false}, nil
 }
 
 // deviatedFromPlan takes a plan and an index of a waypoint on that Plan and returns whether or not it is still
 // following the plan as described by the PlanDeviation specified for the moveRequest.
 func (mr *moveRequest) deviatedFromPlan(ctx context.Context, plan motionplan.Plan) (state.ExecuteResponse, error) {
+	// the position of the base cannot be trusted while SLAM is lost, so execution is stopped until the replan finds it relocalized
+	if mr.slamSvc != nil {
+		if reason := slamLost(ctx, mr.slamSvc, mr.logger); reason != "" {
+			return state.ExecuteResponse{
+				Replan:           true,
+				ReplanReason:     reason + ", pausing until it relocalizes",
+				ReplanReasonCode: motion.ReplanReasonRecovery,
+			}, nil
+		}
+	}
+
 	// calculate the error state
 	executionState, err := mr.kinematicBase.ExecutionState(ctx)
 	if err != nil {
 		return state.ExecuteResponse{}, err
 	}
-	errorState, err := motionplan.CalculateFrameErrorState(executionState, mr.kinematicBase.Kinematics(), mr.kinematicBase.LocalizationFrame())
+	if err := mr.checkStall(ctx, executionState); err != nil {
+		return state.ExecuteResponse{}, err
+	}
+	if reason, err := mr.checkSlip(executionState); err != nil {
+		return state.ExecuteResponse{}, err
+	} else if reason != "" {
+		return state.ExecuteResponse{Replan: true, ReplanReason: reason, ReplanReasonCode: motion.ReplanReasonRecovery}, nil
+	}
+	// deviation is measured from the nearest point on the remaining path, so that cutting a corner between steps is not a deviation
+	errorState, err := motionplan.CalculateFrameErrorStateFromPath(
+		executionState, mr.kinematicBase.Kinematics(), mr.kinematicBase.LocalizationFrame(),
+	)
 	if err != nil {
 		return state.ExecuteResponse{}, err
 	}
 
+	mr.metrics.observeDeviation(errorState.Point().Norm())
+
 	// check if the error state is outside the acceptable bounds
-	if errorState.Point().Norm() > mr.config.planDeviationMM {
+	planDeviationMM := mr.planDeviationMM(ctx)
+	if errorState.Point().Norm() > planDeviationMM {
 		msg := "error state exceeds planDeviationMM; planDeviationMM: %f, errorstate.Point().Norm(): %f, errorstate.Point(): %#v "
-		reason := fmt.Sprintf(msg, mr.config.planDeviationMM, errorState.Point().Norm(), errorState.Point())
-		return state.ExecuteResponse{Replan: true, ReplanReason: reason}, nil
+		reason := fmt.Sprintf(msg, planDeviationMM, errorState.Point().Norm(), errorState.Point())
+		return state.ExecuteResponse{Replan: true, ReplanReason: reason, ReplanReasonCode: motion.ReplanReasonDeviation}, nil
 	}
 	return state.ExecuteResponse{}, nil
 }
 
-// getTransientDetections returns a list of geometries as observed by the provided vision service and camera.
-// Depending on the caller, the geometries returned are either in their relative position
-// with respect to the base or in their absolute position with respect to the world.
-func (mr *moveRequest) getTransientDetections(
-	ctx context.Context,
-	visSrvc vision.Service,
-	camName resource.Name,
-) (*referenceframe.GeometriesInFrame, error) {
+// planDeviationMM returns how far the base may deviate from its plan before a replan is issued. The configured plan deviation is
+// widened while the localizer is uncertain of the position of the base, so that localization noise alone does not trigger replans.
+func (mr *moveRequest) planDeviationMM(ctx context.Context) float64 {
+	conf, err := mr.kinematicBase.Confidence(ctx)
+	if err != nil {
+		mr.logger.CDebugf(ctx, "could not get localization confidence, using the configured plan deviation: %v", err)
+		return mr.config.planDeviationMM
+	}
+	deviation := math.Min(localizationDeviationSigmas*conf.PositionStdDevMM, maxPlanDeviationScale*mr.config.planDeviationMM)
+	if deviation <= mr.config.planDeviationMM {
+		return mr.config.planDeviationMM
+	}
 	mr.logger.CDebugf(ctx,
-		"proceeding to get detections from vision service: %s with camera: %s",
-		visSrvc.Name().ShortName(),
-		camName.ShortName(),
+		"widening plan deviation from %.0fmm to %.0fmm due to localization uncertainty", mr.config.planDeviationMM, deviation,
 	)
+	return deviation
+}
 
-	baseExecutionState, err := mr.kinematicBase.ExecutionState(ctx)
-	if err != nil {
-		return nil, err
-	}
+// getTransientDetections returns a list of geometries as observed by the provided obstacle detector in the given snapshot,
+// positioned in the world frame using the localization and inputs read alongside the detections.
+func (mr *moveRequest) getTransientDetections(
+	snap *sensorSnapshot,
+	detector obstacleDetector,
+) (*referenceframe.GeometriesInFrame, error) {
+	camName := detector.camName
 	// the inputMap informs where we are in the world
 	// the inputMap will be used downstream to transform the observed geometry from the camera frame
 	// into the world frame
-	inputMap, _, err := mr.fsService.CurrentInputs(ctx)
-	if err != nil {
-		return nil, err
+	inputMap := make(referenceframe.FrameSystemInputs, len(snap.inputs)+1)
+	for name, inputs := range snap.inputs {
+		inputMap[name] = inputs
 	}
 	kbInputs := make([]referenceframe.Input, len(mr.kinematicBase.Kinematics().DoF()))
 	kbInputs = append(kbInputs, referenceframe.PoseToInputs(
-		baseExecutionState.CurrentPoses()[mr.kinematicBase.LocalizationFrame().Name()].Pose(),
+		snap.executionState.CurrentPoses()[mr.kinematicBase.LocalizationFrame().Name()].Pose(),
 	)...)
 	inputMap[mr.kinematicBase.Name().ShortName()] = kbInputs
 
-	detections, err := visSrvc.GetObjectPointClouds(ctx, camName.Name, nil)
-	if err != nil {
-		return nil, err
-	}
+	detections := snap.detections[detector]
 
 	// transformed detections
 	transientGeoms := []spatialmath.Geometry{}
 	for i, detection := range detections {
 		geometry := detection.Geometry
+		if costmap, ok := isCostmap(detection); ok {
+			// only the cells of a costmap which are costly enough are obstacles
+			occupied, err := thresholdCostmap(costmap, mr.costmapThreshold)
+			if err != nil {
+				return nil, err
+			}
+			if occupied == nil {
+				continue
+			}
+			geometry = occupied
+		}
 		// update the label of the geometry so we know it is transient
 		label := camName.ShortName() + "_transientObstacle_" + strconv.Itoa(i)
 		if geometry.Label() != "" {
@@ -271,6 +423,30 @@ func (mr *moveRequest) getTransientDetections(
 	return referenceframe.NewGeometriesInFrame(referenceframe.World, transientGeoms), nil
 }
 
+// recallObstacles returns the obstacles remembered from earlier snapshots of the execution, and then remembers the given world
+// frame detections of this snapshot.
+func (mr *moveRequest) recallObstacles(
+	snap *sensorSnapshot,
+	detected []*referenceframe.GeometriesInFrame,
+) ([]spatialmath.Geometry, error) {
+	if mr.memory == nil {
+		return nil, nil
+	}
+	position := snap.executionState.CurrentPoses()[mr.kinematicBase.LocalizationFrame().Name()].Pose().Point()
+	remembered, err := mr.memory.recall(snap.timestamp, position)
+	if err != nil {
+		return nil, err
+	}
+	detections := []spatialmath.Geometry{}
+	for _, gifs := range detected {
+		detections = append(detections, gifs.Geometries()...)
+	}
+	if err := mr.memory.remember(snap.timestamp, detections); err != nil {
+		return nil, err
+	}
+	return remembered, nil
+}
+
 // obstaclesIntersectPlan takes a list of waypoints and an index of a waypoint on that Plan and reports an error indicating
 // whether or not any obstacle detectors report geometries in positions which would cause a collision with the executor
 // following the Plan.
@@ -284,6 +460,14 @@ func (mr *moveRequest) obstaclesIntersectPlan(
 	// (due to some other non-motion call for example), then we can't just get current inputs
 	// we need the original input to place that thing in its original position
 	// hence, cached CurrentInputs from the start are used i.e. mr.planRequest.StartConfiguration
+	// the base is released whenever the plan is not found to be blocked
+	blocked := false
+	defer func() {
+		if !blocked {
+			mr.obstacleWait.clear()
+		}
+	}()
+
 	existingGifs, err := mr.planRequest.WorldState.ObstaclesInWorldFrame(
 		mr.planRequest.FrameSystem, mr.planRequest.StartState.Configuration(),
 	)
@@ -291,64 +475,147 @@ func (mr *moveRequest) obstaclesIntersectPlan(
 		return state.ExecuteResponse{}, err
 	}
 
-	for visSrvc, cameraNames := range mr.obstacleDetectors {
+	snap, err := mr.snapshot(ctx)
+	if err != nil {
+		return state.ExecuteResponse{}, err
+	}
+
+	// replan when an obstacle detector has become unavailable so that its degradation policy is applied to the execution and
+	// recorded in the plan status
+	for _, camName := range mr.degradation.unavailableCameras() {
+		if !mr.degradedCameras[camName] {
+			reason := mr.degradation.describe(camName)
+			mr.logger.CWarn(ctx, reason)
+			return state.ExecuteResponse{Replan: true, ReplanReason: reason, ReplanReasonCode: motion.ReplanReasonObstacle}, nil
+		}
+	}
+
+	if mr.mapWatcher != nil {
+		reason, err := mr.mapWatcher.changed(ctx)
+		if err != nil {
+			return state.ExecuteResponse{}, err
+		}
+		if reason != "" {
+			mr.logger.CInfo(ctx, reason)
+			return state.ExecuteResponse{Replan: true, ReplanReason: reason, ReplanReasonCode: motion.ReplanReasonObstacle}, nil
+		}
+	}
+
+	// Note: detections are initially observed from the camera frame but must be transformed to be in
+	// world frame. We cannot use the inputs of the base to transform the detections since they are relative.
+	// All detections are transformed before the execution state of the snapshot is augmented below.
+	detectedGifs := []*referenceframe.GeometriesInFrame{}
+	// the local planner steers around transient detections itself, and the execution is replanned if they block its way
+	obstacleDetectors := mr.obstacleDetectors
+	if mr.localPlanning {
+		obstacleDetectors = nil
+	}
+	for visSrvc, cameraNames := range obstacleDetectors {
 		for _, camName := range cameraNames {
-			// Note: detections are initially observed from the camera frame but must be transformed to be in
-			// world frame. We cannot use the inputs of the base to transform the detections since they are relative.
-			gifs, err := mr.getTransientDetections(ctx, visSrvc, camName)
+			gifs, err := mr.getTransientDetections(snap, obstacleDetector{visSrvc: visSrvc, camName: camName})
 			if err != nil {
 				return state.ExecuteResponse{}, err
 			}
-			if len(gifs.Geometries()) == 0 {
-				mr.logger.CDebug(ctx, "no obstacles detected")
-				continue
-			}
-
-			// construct new worldstate
-			worldState, err := referenceframe.NewWorldState([]*referenceframe.GeometriesInFrame{existingGifs, gifs}, nil)
+			geoms, err := mr.corridors.filter(camName.ShortName(), gifs.Geometries(), true)
 			if err != nil {
 				return state.ExecuteResponse{}, err
 			}
-
-			// get the execution state of the base
-			baseExecutionState, err := mr.kinematicBase.ExecutionState(ctx)
-			if err != nil {
-				return state.ExecuteResponse{}, err
+			if len(geoms) == 0 {
+				mr.logger.CDebug(ctx, "no obstacles detected")
+				continue
 			}
+			detectedGifs = append(detectedGifs, referenceframe.NewGeometriesInFrame(gifs.Parent(), geoms))
+		}
+	}
+	// obstacles which have left the view of the cameras are checked as if they were still detected
+	if !mr.localPlanning {
+		remembered, err := mr.recallObstacles(snap, detectedGifs)
+		if err != nil {
+			return state.ExecuteResponse{}, err
+		}
+		if len(remembered) > 0 {
+			detectedGifs = append(detectedGifs, referenceframe.NewGeometriesInFrame(referenceframe.World, remembered))
+		}
+	}
+	if len(detectedGifs) == 0 {
+		// payloads kept level are checked even when nothing is detected, since they may be tipped by the base
+		if len(mr.planRequest.Constraints.GetLevelConstraint()) == 0 {
+			return state.ExecuteResponse{}, nil
+		}
+		detectedGifs = append(detectedGifs, referenceframe.NewGeometriesInFrame(referenceframe.World, nil))
+	}
 
-			// build representation of frame system's inputs
-			// TODO(pl): in the case where we have e.g. an arm (not moving) mounted on a base, we should be passing its current
-			// configuration rather than the zero inputs
-			updatedBaseExecutionState := baseExecutionState
-			_, ok := mr.kinematicBase.Kinematics().(tpspace.PTGProvider)
-			if ok {
-				updatedBaseExecutionState, err = mr.augmentBaseExecutionState(baseExecutionState)
-				if err != nil {
-					return state.ExecuteResponse{}, err
-				}
-			}
+	// build representation of frame system's inputs using the execution state of the base read alongside the detections, with
+	// components mounted on the base, such as an arm holding a payload, at the inputs read alongside it
+	updatedBaseExecutionState := snap.executionState
+	currentInputs := updatedBaseExecutionState.CurrentInputs()
+	for name, inputs := range snap.inputs {
+		if _, ok := currentInputs[name]; !ok && currentInputs != nil {
+			currentInputs[name] = inputs
+		}
+	}
+	if _, ok := mr.kinematicBase.Kinematics().(tpspace.PTGProvider); ok {
+		updatedBaseExecutionState, err = mr.augmentBaseExecutionState(snap.executionState)
+		if err != nil {
+			return state.ExecuteResponse{}, err
+		}
+	}
 
-			mr.logger.CDebugf(ctx, "CheckPlan inputs: \n currentPosition: %v\n currentInputs: %v\n worldstate: %s",
-				updatedBaseExecutionState.CurrentPoses()[mr.kinematicBase.Kinematics().Name()].Pose(),
-				updatedBaseExecutionState.CurrentInputs(),
-				worldState.String(),
-			)
-			if err := motionplan.CheckPlan(
-				mr.localizingFS.Frame(mr.kinematicBase.Kinematics().Name()), // frame we wish to check for collisions
-				updatedBaseExecutionState,
-				worldState, // detected obstacles by this instance of camera + service
-				mr.localizingFS,
-				lookAheadDistanceMM,
-				mr.planRequest.Logger,
-			); err != nil {
+	for _, gifs := range detectedGifs {
+		// drop-offs are checked on their own, since the base is replanned around them at once rather than waiting for them to clear
+		dropOffs, others := splitDropOffs(gifs)
+		if len(dropOffs.Geometries()) > 0 {
+			if err := mr.checkDetections(ctx, existingGifs, dropOffs, updatedBaseExecutionState); err != nil {
 				mr.planRequest.Logger.CInfo(ctx, err.Error())
-				return state.ExecuteResponse{Replan: true, ReplanReason: err.Error()}, nil
+				return state.ExecuteResponse{
+					Replan:           true,
+					ReplanReason:     "drop-off intersects plan: " + err.Error(),
+					ReplanReasonCode: motion.ReplanReasonObstacle,
+				}, nil
+			}
+			if len(others.Geometries()) == 0 {
+				continue
 			}
 		}
+		if err := mr.checkDetections(ctx, existingGifs, others, updatedBaseExecutionState); err != nil {
+			mr.planRequest.Logger.CInfo(ctx, err.Error())
+			blocked = true
+			return mr.obstacleWait.blocked(err.Error()), nil
+		}
 	}
 	return state.ExecuteResponse{}, nil
 }
 
+// checkDetections checks the remainder of the plan being executed for collisions with the detected obstacles, along with the
+// obstacles of the request.
+func (mr *moveRequest) checkDetections(
+	ctx context.Context,
+	existingGifs, detected *referenceframe.GeometriesInFrame,
+	executionState motionplan.ExecutionState,
+) error {
+	worldState, err := referenceframe.NewWorldStateWithInteractionSpaces(
+		[]*referenceframe.GeometriesInFrame{existingGifs, detected}, mr.planRequest.WorldState.InteractionSpaces(), nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	mr.logger.CDebugf(ctx, "CheckPlan inputs: \n currentPosition: %v\n currentInputs: %v\n worldstate: %s",
+		executionState.CurrentPoses()[mr.kinematicBase.Kinematics().Name()].Pose(),
+		executionState.CurrentInputs(),
+		worldState.String(),
+	)
+	return motionplan.CheckPlanWithConstraints(
+		mr.localizingFS.Frame(mr.kinematicBase.Kinematics().Name()), // frame we wish to check for collisions
+		executionState,
+		worldState, // detected obstacles by this instance of camera + service
+		mr.planRequest.Constraints,
+		mr.localizingFS,
+		lookAheadDistanceMM,
+		mr.planRequest.Logger,
+	)
+}
+
 // In order for the localizingFS to work as intended when working with PTGs we must update the baseExecutionState.
 // The original baseExecutionState passed into this method contains a plan, currentPoses, and currentInputs.
 // We update the plan object such that the trajectory which is of form referenceframe.Input also houses information
@@ -460,6 +727,13 @@ func kbOptionsFromCfg(motionCfg *validatedMotionConfiguration, validatedExtra va
 
 	kinematicsOptions.GoalRadiusMM = motionCfg.planDeviationMM
 	kinematicsOptions.HeadingThresholdDegrees = 8
+	kinematicsOptions.LocalPlanner = validatedExtra.localPlanner.options()
+	kinematicsOptions.SpeedScaling = motionCfg.speedScalingOptions()
+	if validatedExtra.obstacleWait > 0 {
+		kinematicsOptions.Hold = &kinematicbase.HoldOptions{}
+	}
+	kinematicsOptions.Trailer = validatedExtra.trailer
+	kinematicsOptions.Flight = validatedExtra.flight
 	return kinematicsOptions
 }
 
@@ -551,6 +825,33 @@ func newValidatedMotionCfg(motionCfg *motion.MotionConfiguration, reqType reques
 		vmc.obstacleDetectors = motionCfg.ObstacleDetectors
 	}
 
+	if err := validateNotNegNorNaN(motionCfg.HeadingCalibrationMM, "HeadingCalibrationMM"); err != nil {
+		return empty, err
+	}
+	vmc.headingCalibrationMM = motionCfg.HeadingCalibrationMM
+
+	if err := validateNotNegNorNaN(motionCfg.SlowdownDistanceMM, "SlowdownDistanceMM"); err != nil {
+		return empty, err
+	}
+	vmc.slowdownDistanceMM = motionCfg.SlowdownDistanceMM
+
+	if err := validateNotNegNorNaN(motionCfg.MinSpeedScale, "MinSpeedScale"); err != nil {
+		return empty, err
+	}
+	if motionCfg.MinSpeedScale > 1 {
+		return empty, errors.New("MinSpeedScale may not be greater than 1")
+	}
+	vmc.minSpeedScale = motionCfg.MinSpeedScale
+
+	if err := validateNotNegNorNaN(motionCfg.DriftCompensation, "DriftCompensation"); err != nil {
+		return empty, err
+	}
+	if motionCfg.DriftCompensation > 1 {
+		return empty, errors.New("DriftCompensation may not be greater than 1")
+	}
+	vmc.driftSensor = motionCfg.DriftSensor
+	vmc.driftCompensation = motionCfg.DriftCompensation
+
 	return vmc, nil
 }
 
@@ -565,16 +866,26 @@ func (ms *builtIn) newMoveOnGlobeRequest(
 		return nil, err
 	}
 
-	if valExtra.maxReplans >= 0 {
-		if replanCount > valExtra.maxReplans {
+	// extensions of the planning horizon are not replans, nor are those made before a recovery
+	horizon := ms.planningHorizon(req.ComponentName, valExtra.planningHorizonMM, replanCount)
+	recovery := ms.recovery(req.ComponentName, valExtra.recovery, replanCount)
+	if replans := recovery.replans(horizon.replans(replanCount)); valExtra.maxReplans >= 0 {
+		if replans > valExtra.maxReplans {
 			return nil, fmt.Errorf("exceeded maximum number of replans: %d", valExtra.maxReplans)
 		}
 	}
+	if recovery.constraintsRelaxed() {
+		valExtra.relaxConstraints()
+	}
 
 	motionCfg, err := newValidatedMotionCfg(req.MotionCfg, requestTypeMoveOnGlobe)
 	if err != nil {
 		return nil, err
 	}
+	altitude, err := parseGlobeAltitude(req.Extra)
+	if err != nil {
+		return nil, err
+	}
 	// ensure arguments are well behaved
 	obstacles := req.Obstacles
 	if obstacles == nil {
@@ -588,8 +899,19 @@ func (ms *builtIn) newMoveOnGlobeRequest(
 		return nil, errors.New("destination may not contain NaN")
 	}
 
-	// build kinematic options
-	kinematicsOptions := kbOptionsFromCfg(motionCfg, valExtra)
+	degradation := ms.detectorDegradation(req.ComponentName, valExtra.detectorPolicies, replanCount)
+	obstacleDetectors, err := ms.resolveObstacleDetectors(ctx, motionCfg, degradation)
+	if err != nil {
+		return nil, err
+	}
+
+	// build kinematic options, slowing the base if any obstacle detectors are unavailable
+	kinematicsOptions := degradation.applySpeedScale(kbOptionsFromCfg(motionCfg, valExtra))
+	kinematicsOptions.Clock = ms.clock
+	kinematicsOptions.PTGLibrary = ms.ptgLibrary(req.ComponentName.ShortName())
+	if kinematicsOptions.Drift, err = ms.driftOptions(motionCfg); err != nil {
+		return nil, err
+	}
 
 	// build the localizer from the movement sensor
 	movementSensor, ok := ms.movementSensors[req.MovementSensorName]
@@ -597,25 +919,16 @@ func (ms *builtIn) newMoveOnGlobeRequest(
 		return nil, resource.DependencyNotFoundError(req.MovementSensorName)
 	}
 
-	origin, _, err := movementSensor.Position(ctx, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	heading, err := movementSensor.CompassHeading(ctx, nil)
+	origin, originAltitude, err := movementSensor.Position(ctx, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	// add an offset between the movement sensor and the base if it is applicable
-	baseOrigin := referenceframe.NewPoseInFrame(req.ComponentName.ShortName(), spatialmath.NewZeroPose())
-	movementSensorToBase, err := ms.fsService.TransformPose(ctx, baseOrigin, movementSensor.Name().ShortName(), nil)
+	movementSensorToBase, err := ms.movementSensorToBase(ctx, movementSensor, req.ComponentName, valExtra.requireFrameTransforms)
 	if err != nil {
-		// here we make the assumption the movement sensor is coincident with the base
-		movementSensorToBase = baseOrigin
+		return nil, err
 	}
-	// Create a localizer from the movement sensor, and collapse reported orientations to 2d
-	localizer := motion.TwoDLocalizer(motion.NewMovementSensorLocalizer(movementSensor, origin, movementSensorToBase.Pose()))
 
 	// create a KinematicBase from the componentName
 	baseComponent, ok := ms.components[req.ComponentName]
@@ -627,20 +940,71 @@ func (ms *builtIn) newMoveOnGlobeRequest(
 		return nil, fmt.Errorf("cannot move component of type %T because it is not a Base", baseComponent)
 	}
 
+	properties, err := movementSensor.Properties(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	var heading float64
+	var localizer motion.Localizer
+	if !properties.CompassHeadingSupported && motionCfg.headingCalibrationMM > 0 && valExtra.localizer == "" {
+		// without a compass the heading of the base is estimated from its GPS track
+		if altitude != nil && altitude.plan {
+			return nil, fmt.Errorf("%s requires a movement sensor which reports its compass heading", planAltitudeExtraKey)
+		}
+		tracker, err := ms.headingTracker(
+			ctx, b, movementSensor, origin, movementSensorToBase.Pose(),
+			motionCfg.headingCalibrationMM, kinematicsOptions.LinearVelocityMMPerSec, replanCount,
+		)
+		if err != nil {
+			return nil, err
+		}
+		heading = tracker.CompassHeading()
+		localizer = motion.TwoDLocalizer(tracker)
+	} else {
+		heading, err = movementSensor.CompassHeading(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		// Create a localizer from the movement sensor unless another is selected, and collapse reported orientations to 2d
+		sources := motion.LocalizerSources{
+			Origin:      origin,
+			Calibration: movementSensorToBase.Pose(),
+			BodyName:    req.ComponentName.ShortName(),
+		}
+		if altitude != nil && altitude.plan {
+			sources.OriginAltitude = &originAltitude
+		}
+		localizer, err = ms.newLocalizer(ctx, valExtra, motion.MovementSensorLocalizerName, movementSensor, sources)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	fs, err := ms.fsService.FrameSystem(ctx, nil)
 	if err != nil {
 		return nil, err
 	}
+	kinematicsOptions.SafetyZones = geoSafetyZoneSource(ms.geoSafetyZones, origin)
 
 	// Important: GeoPointToPose will create a pose such that incrementing latitude towards north increments +Y, and incrementing
 	// longitude towards east increments +X. Heading is not taken into account. This pose must therefore be transformed based on the
 	// orientation of the base such that it is a pose relative to the base's current location.
-	goalPoseRaw := spatialmath.NewPoseFromPoint(spatialmath.GeoPointToPoint(req.Destination, origin))
-	// construct limits
-	straightlineDistance := goalPoseRaw.Point().Norm()
-	if straightlineDistance > maxTravelDistanceMM {
+	// The distance to destinations above or below the base is estimated along the slope to them.
+	goalPoseRaw, slope := altitude.goal(req.Destination, origin, originAltitude)
+	if valExtra.planningHorizonMM == 0 && goalPoseRaw.Point().Norm()*slope > maxTravelDistanceMM {
 		return nil, fmt.Errorf("cannot move more than %d kilometers", int(maxTravelDistanceMM*1e-6))
 	}
+	// only plan as far as the planning horizon towards the destination
+	goalPoseRaw, atDestination := horizon.goal(goalPoseRaw)
+	// the base is planned and driven to face the requested heading at the destination, unless the request has none or the motion
+	// profile disregards orientation
+	headingAtGoal := atDestination && !math.IsNaN(req.Heading) && valExtra.motionProfile != motionplan.PositionOnlyMotionProfile
+	if headingAtGoal {
+		goalPoseRaw = spatialmath.NewPose(goalPoseRaw.Point(), headingOrientation(req.Heading))
+		kinematicsOptions.PositionOnlyMode = false
+	}
+	// construct limits
+	straightlineDistance := goalPoseRaw.Point().Norm() * slope
 
 	// Set the limits for a base if we are using diffential drive.
 	// If we are using PTG kineamtics these limits will be ignored.
@@ -653,13 +1017,42 @@ func (ms *builtIn) newMoveOnGlobeRequest(
 	if err != nil {
 		return nil, err
 	}
+	if altitude != nil && altitude.plan && !movesVertically(kb.Kinematics()) {
+		return nil, fmt.Errorf("cannot plan the altitude of base %s, whose kinematics cannot change its height", b.Name().ShortName())
+	}
 
 	// convert obstacles of type []GeoGeometry into []Geometry
-	geomsRaw := spatialmath.GeoGeometriesToGeometries(obstacles, origin)
+	geomsRaw, err := horizon.filterObstacles(spatialmath.GeoGeometriesToGeometries(obstacles, origin))
+	if err != nil {
+		return nil, err
+	}
 
 	// convert bounding regions which are GeoGeometries into Geometries
 	boundingRegions := spatialmath.GeoGeometriesToGeometries(req.BoundingRegions, origin)
 
+	// bases kept to a path network are routed over it, and only planned for within the corridor around their route
+	network, err := parsePathNetwork(req.Extra)
+	if err != nil {
+		return nil, err
+	}
+	var route *networkRoute
+	if network != nil {
+		if valExtra.planningHorizonMM > 0 || len(valExtra.interactionSpaces) > 0 || len(boundingRegions) > 0 {
+			return nil, fmt.Errorf(
+				"%s cannot be combined with a planning horizon, interaction spaces or bounding regions", pathNetworkExtraKey,
+			)
+		}
+		if route, err = network.route(origin, goalPoseRaw.Point()); err != nil {
+			return nil, err
+		}
+		// relaxed constraints no longer keep the base within the corridors
+		if !recovery.constraintsRelaxed() {
+			if valExtra.interactionSpaces, err = route.corridors(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	mr, err := ms.createBaseMoveRequest(
 		ctx,
 		motionCfg,
@@ -669,6 +1062,8 @@ func (ms *builtIn) newMoveOnGlobeRequest(
 		fs,
 		geomsRaw,
 		valExtra,
+		obstacleDetectors,
+		degradation,
 	)
 	if err != nil {
 		return nil, err
@@ -677,7 +1072,21 @@ func (ms *builtIn) newMoveOnGlobeRequest(
 	mr.replanCostFactor = valExtra.replanCostFactor
 	mr.requestType = requestTypeMoveOnGlobe
 	mr.geoPoseOrigin = spatialmath.NewGeoPose(origin, heading)
+	if headingAtGoal {
+		mr.atGoalCheck = headingAtGoalCheck(goalPoseRaw, motionCfg.planDeviationMM, kinematicsOptions.HeadingThresholdDegrees)
+	}
 	mr.planRequest.BoundingRegions = boundingRegions
+	if route != nil {
+		mr.planRequest.Goals = append(route.waypoints(kb.Kinematics().Name()), mr.planRequest.Goals...)
+	}
+	mr.memory = ms.obstacleMemory(req.ComponentName, valExtra.obstacleMemory, replanCount)
+	ms.useRecovery(mr, recovery)
+	mr.useLocalPlanner(kinematicsOptions)
+	mr.useSpeedScaling(kinematicsOptions)
+	mr.useObstacleWait(kinematicsOptions)
+	if !atDestination {
+		mr.horizon = horizon
+	}
 	return mr, nil
 }
 
@@ -693,11 +1102,16 @@ func (ms *builtIn) newMoveOnMapRequest(
 		return nil, err
 	}
 
-	if valExtra.maxReplans >= 0 {
-		if replanCount > valExtra.maxReplans {
+	// replans made before a recovery are not counted
+	recovery := ms.recovery(req.ComponentName, valExtra.recovery, replanCount)
+	if replans := recovery.replans(replanCount); valExtra.maxReplans >= 0 {
+		if replans > valExtra.maxReplans {
 			return nil, fmt.Errorf("exceeded maximum number of replans: %d", valExtra.maxReplans)
 		}
 	}
+	if recovery.constraintsRelaxed() {
+		valExtra.relaxConstraints()
+	}
 
 	motionCfg, err := newValidatedMotionCfg(req.MotionCfg, requestTypeMoveOnMap)
 	if err != nil {
@@ -707,6 +1121,10 @@ func (ms *builtIn) newMoveOnMapRequest(
 	if req.Destination == nil {
 		return nil, errors.New("destination cannot be nil")
 	}
+	tcp, err := parseToolCenterPoint(req.Extra)
+	if err != nil {
+		return nil, err
+	}
 
 	// get the SLAM Service from the slamName
 	slamSvc, ok := ms.slamServices[req.SlamName]
@@ -723,9 +1141,27 @@ func (ms *builtIn) newMoveOnMapRequest(
 		return nil, fmt.Errorf("expected SLAM to be in localization only mode, got %v", slamProps.MappingMode)
 	}
 
-	// gets the extents of the SLAM map
-	limits, err := slam.Limits(ctx, slamSvc, true)
-	if err != nil {
+	// planning from a position SLAM is lost on would drive the base blind
+	if err := waitForRelocalization(ctx, slamSvc, valExtra.relocalizationTimeout, ms.logger); err != nil {
+		return nil, err
+	}
+
+	// the version of the map is noted before it is read so that any change made while planning around it causes a replan
+	var watcher *mapWatcher
+	if valExtra.replanOnMapChange {
+		if watcher, err = newMapWatcher(ctx, slamSvc); err != nil {
+			return nil, err
+		}
+	}
+
+	// gets the extents of the map, which is either the SLAM map or an occupancy grid map planned around in its place
+	var gridOctree *pointcloud.BasicOctree
+	var limits []referenceframe.Limit
+	if valExtra.occupancyGridPath != "" {
+		if gridOctree, limits, err = loadOccupancyGridMap(valExtra.occupancyGridPath); err != nil {
+			return nil, err
+		}
+	} else if limits, err = slam.Limits(ctx, slamSvc, true); err != nil {
 		return nil, err
 	}
 	limits = append(limits, referenceframe.Limit{Min: -2 * math.Pi, Max: 2 * math.Pi})
@@ -740,35 +1176,77 @@ func (ms *builtIn) newMoveOnMapRequest(
 		return nil, fmt.Errorf("cannot move component of type %T because it is not a Base", component)
 	}
 
-	// build kinematic options
-	kinematicsOptions := kbOptionsFromCfg(motionCfg, valExtra)
-
-	fs, err := ms.fsService.FrameSystem(ctx, nil)
+	degradation := ms.detectorDegradation(req.ComponentName, valExtra.detectorPolicies, replanCount)
+	obstacleDetectors, err := ms.resolveObstacleDetectors(ctx, motionCfg, degradation)
 	if err != nil {
 		return nil, err
 	}
 
-	// Create a localizer from the movement sensor, and collapse reported orientations to 2d
-	localizer := motion.TwoDLocalizer(motion.NewSLAMLocalizer(slamSvc))
-	kb, err := kinematicbase.WrapWithKinematics(ctx, b, ms.logger, localizer, limits, kinematicsOptions)
-	if err != nil {
+	// build kinematic options, slowing the base if any obstacle detectors are unavailable
+	kinematicsOptions := degradation.applySpeedScale(kbOptionsFromCfg(motionCfg, valExtra))
+	kinematicsOptions.Clock = ms.clock
+	kinematicsOptions.PTGLibrary = ms.ptgLibrary(req.ComponentName.ShortName())
+	if kinematicsOptions.Drift, err = ms.driftOptions(motionCfg); err != nil {
 		return nil, err
 	}
 
-	goalPoseAdj := spatialmath.Compose(req.Destination, motion.SLAMOrientationAdjustment)
+	fs, err := ms.fsService.FrameSystem(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	// the base is localized in the frame of the map, which is the world frame of the frame system
+	if kinematicsOptions.SafetyZones, err = ms.mapSafetyZoneSource(ctx, fs); err != nil {
+		return nil, err
+	}
 
-	// get point cloud data in the form of bytes from pcd
-	pointCloudData, err := slam.PointCloudMapFull(ctx, slamSvc, true)
+	// Create a localizer from the slam service unless another is selected, and collapse reported orientations to 2d
+	localizer, err := ms.newLocalizer(ctx, valExtra, motion.SLAMLocalizerName, slamSvc, motion.LocalizerSources{
+		BodyName: req.ComponentName.ShortName(),
+	})
 	if err != nil {
 		return nil, err
 	}
-	// store slam point cloud data  in the form of a recursive octree for collision checking
-	octree, err := pointcloud.ReadPCDToBasicOctree(bytes.NewReader(pointCloudData))
+	kb, err := kinematicbase.WrapWithKinematics(ctx, b, ms.logger, localizer, limits, kinematicsOptions)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Obstacles = append(req.Obstacles, octree)
+	goalPoseAdj := spatialmath.Compose(req.Destination, motion.SLAMOrientationAdjustment)
+	if tcp != nil {
+		// the destination is that of the tool center point, so the base is sent wherever places it there
+		if goalPoseAdj, err = baseGoalForToolCenterPoint(goalPoseAdj, tcp); err != nil {
+			return nil, err
+		}
+	}
+
+	// stream the slam point cloud into a recursive octree for collision checking, without holding the whole map in memory
+	octree := gridOctree
+	if octree == nil {
+		if octree, err = slam.PointCloudMapOctree(ctx, slamSvc, true, func(read, total int) {
+			ms.logger.CDebugf(ctx, "read %d of %d points of the SLAM map", read, total)
+		}); err != nil {
+			return nil, err
+		}
+	}
+	if valExtra.mapFilter != (mapFilter{}) {
+		// large maps are reduced so that planning around them does not take too long
+		startPose, err := kb.CurrentPosition(ctx)
+		if err != nil {
+			return nil, err
+		}
+		numPoints := octree.Size()
+		if octree, err = valExtra.mapFilter.apply(octree, startPose.Pose().Point(), goalPoseAdj.Point()); err != nil {
+			return nil, err
+		}
+		if octree == nil {
+			ms.logger.CDebugf(ctx, "filtered all %d points out of the SLAM map", numPoints)
+		} else {
+			ms.logger.CDebugf(ctx, "filtered the SLAM map from %d to %d points", numPoints, octree.Size())
+		}
+	}
+	if octree != nil {
+		req.Obstacles = append(req.Obstacles, octree)
+	}
 
 	mr, err := ms.createBaseMoveRequest(
 		ctx,
@@ -779,11 +1257,20 @@ func (ms *builtIn) newMoveOnMapRequest(
 		fs,
 		req.Obstacles,
 		valExtra,
+		obstacleDetectors,
+		degradation,
 	)
 	if err != nil {
 		return nil, err
 	}
 	mr.requestType = requestTypeMoveOnMap
+	mr.memory = ms.obstacleMemory(req.ComponentName, valExtra.obstacleMemory, replanCount)
+	ms.useRecovery(mr, recovery)
+	mr.useLocalPlanner(kinematicsOptions)
+	mr.useSpeedScaling(kinematicsOptions)
+	mr.useObstacleWait(kinematicsOptions)
+	mr.mapWatcher = watcher
+	mr.slamSvc = slamSvc
 	return mr, nil
 }
 
@@ -796,6 +1283,8 @@ func (ms *builtIn) createBaseMoveRequest(
 	fs referenceframe.FrameSystem,
 	worldObstacles []spatialmath.Geometry,
 	valExtra validatedExtra,
+	obstacleDetectors map[vision.Service][]resource.Name,
+	degradation *detectorDegradation,
 ) (*moveRequest, error) {
 	startPoseIF, err := kb.CurrentPosition(ctx)
 	if err != nil {
@@ -818,6 +1307,13 @@ func (ms *builtIn) createBaseMoveRequest(
 	if err != nil {
 		return nil, err
 	}
+	// payloads carried by the base are kept level while planning and while checking the plan during execution
+	constraints, err := valExtra.levelPayload.constrain(nil, baseOnlyFS, ms.carriedArms(baseOnlyFS))
+	if err != nil {
+		return nil, err
+	}
+	constraints = padCollisions(constraints, valExtra.collisionPadding)
+	constraints = towTrailer(constraints, valExtra.trailer, kinematicFrame.Name())
 
 	planningFS := baseOnlyFS
 	_, ok := kinematicFrame.(tpspace.PTGProvider)
@@ -863,30 +1359,15 @@ func (ms *builtIn) createBaseMoveRequest(
 	goal := referenceframe.NewPoseInFrame(referenceframe.World, goalPoseInWorld)
 
 	gif := referenceframe.NewGeometriesInFrame(referenceframe.World, worldObstacles)
-	worldState, err := referenceframe.NewWorldState([]*referenceframe.GeometriesInFrame{gif}, nil)
+	var interactionSpaces []*referenceframe.GeometriesInFrame
+	if len(valExtra.interactionSpaces) > 0 {
+		interactionSpaces = append(interactionSpaces, referenceframe.NewGeometriesInFrame(referenceframe.World, valExtra.interactionSpaces))
+	}
+	worldState, err := referenceframe.NewWorldStateWithInteractionSpaces([]*referenceframe.GeometriesInFrame{gif}, interactionSpaces, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	obstacleDetectors := make(map[vision.Service][]resource.Name)
-	for _, obstacleDetectorNamePair := range motionCfg.obstacleDetectors {
-		// get vision service
-		visionServiceName := obstacleDetectorNamePair.VisionServiceName
-		visionSvc, ok := ms.visionServices[visionServiceName]
-		if !ok {
-			return nil, resource.DependencyNotFoundError(visionServiceName)
-		}
-
-		// add camera to vision service map
-		camList, ok := obstacleDetectors[visionSvc]
-		if !ok {
-			obstacleDetectors[visionSvc] = []resource.Name{obstacleDetectorNamePair.CameraName}
-		} else {
-			camList = append(camList, obstacleDetectorNamePair.CameraName)
-			obstacleDetectors[visionSvc] = camList
-		}
-	}
-
 	currentInputs, _, err := ms.fsService.CurrentInputs(ctx)
 	if err != nil {
 		return nil, err
@@ -929,23 +1410,39 @@ func (ms *builtIn) createBaseMoveRequest(
 			FrameSystem: planningFS,
 			StartState:  startState,
 			WorldState:  worldState,
+			Constraints: constraints,
 			Options:     valExtra.extra,
 		},
 		kinematicBase:     kb,
+		corridors:         newCorridorFilter(valExtra.detectorCorridors),
+		maxSensorSkew:     valExtra.maxSensorSkew,
+		straightLineMaxMM: valExtra.straightLineMaxMM,
+		costmapThreshold:  valExtra.costmapThreshold,
 		replanCostFactor:  valExtra.replanCostFactor,
 		atGoalCheck:       atGoalCheck,
 		obstacleDetectors: obstacleDetectors,
+		degradation:       degradation,
+		finish:            func() { ms.forgetDetectorDegradation(degradation) },
+		degradedCameras:   map[string]bool{},
 		fsService:         ms.fsService,
 		localizingFS:      collisionFS,
+		metrics:           ms.metrics,
+		obstacleWait:      newObstacleWait(valExtra.obstacleWait, ms.clock),
+		stall:             newStallDetector(valExtra.stall, kb.Name().ShortName(), ms.clock),
+		slip:              newSlipDetector(valExtra.slip),
 
 		executeBackgroundWorkers: &backgroundWorkers,
 
 		responseChan: make(chan moveResponse, 1),
 	}
 
+	for _, camName := range degradation.unavailableCameras() {
+		mr.degradedCameras[camName] = true
+	}
+
 	// TODO: Change deviatedFromPlan to just query positionPollingFreq on the struct & the same for the obstaclesIntersectPlan
-	mr.position = newReplanner(positionPollingFreq, mr.deviatedFromPlan)
-	mr.obstacle = newReplanner(obstaclePollingFreq, mr.obstaclesIntersectPlan)
+	mr.position = newReplanner(positionPollingFreq, valExtra.replanDebounce, ms.clock, mr.deviatedFromPlan)
+	mr.obstacle = newReplanner(obstaclePollingFreq, valExtra.replanDebounce, ms.clock, mr.obstaclesIntersectPlan)
 	return mr, nil
 }
 
@@ -0,0 +1,180 @@
+package builtin
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang/geo/r3"
+
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/spatialmath"
+)
+
+const (
+	// obstacleMemoryExtraKey is the key of extra through which MoveOnGlobe and MoveOnMap are given the number of seconds for which
+	// transient detections are remembered, so that obstacles which leave the field of view of the cameras while the base turns are
+	// still avoided when replanning.
+	obstacleMemoryExtraKey = "obstacle_memory_s"
+	// obstacleMemoryRadiusExtraKey is the key of extra through which the distance in millimeters from the base beyond which
+	// remembered obstacles are forgotten is given.
+	obstacleMemoryRadiusExtraKey  = "obstacle_memory_radius_mm"
+	defaultObstacleMemoryRadiusMM = 5000.
+	rememberedObstaclePrefix      = "remembered_"
+)
+
+// obstacleMemoryConfig describes for how long and how far from the base transient detections are remembered.
+type obstacleMemoryConfig struct {
+	duration time.Duration
+	radiusMM float64
+}
+
+// parseObstacleMemory parses the obstacle memory from extra, returning a zero config, which remembers nothing, if it is not set.
+func parseObstacleMemory(extra map[string]interface{}) (obstacleMemoryConfig, error) {
+	cfg := obstacleMemoryConfig{}
+	if raw, ok := extra[obstacleMemoryExtraKey]; ok {
+		seconds, ok := raw.(float64)
+		if !ok {
+			return obstacleMemoryConfig{}, fmt.Errorf("could not interpret %s field as float", obstacleMemoryExtraKey)
+		}
+		if seconds <= 0 {
+			return obstacleMemoryConfig{}, fmt.Errorf("%s must be positive", obstacleMemoryExtraKey)
+		}
+		cfg.duration = time.Duration(seconds * float64(time.Second))
+		cfg.radiusMM = defaultObstacleMemoryRadiusMM
+	}
+	if raw, ok := extra[obstacleMemoryRadiusExtraKey]; ok {
+		if cfg.duration == 0 {
+			return obstacleMemoryConfig{}, fmt.Errorf("%s requires %s to be set", obstacleMemoryRadiusExtraKey, obstacleMemoryExtraKey)
+		}
+		radius, ok := raw.(float64)
+		if !ok {
+			return obstacleMemoryConfig{}, fmt.Errorf("could not interpret %s field as float", obstacleMemoryRadiusExtraKey)
+		}
+		if radius <= 0 {
+			return obstacleMemoryConfig{}, fmt.Errorf("%s must be positive", obstacleMemoryRadiusExtraKey)
+		}
+		cfg.radiusMM = radius
+	}
+	return cfg, nil
+}
+
+type rememberedObstacle struct {
+	geometry spatialmath.Geometry
+	seen     time.Time
+}
+
+// obstacleMemory remembers the world frame transient detections of an execution across its replans. A remembered obstacle is
+// forgotten once it is older than the configured duration, once the base is further than the configured radius from it, or once a
+// newer detection covers its center, in which case the newer detection replaces it.
+type obstacleMemory struct {
+	cfg obstacleMemoryConfig
+
+	mu        sync.Mutex
+	obstacles []rememberedObstacle
+	count     int
+}
+
+// obstacleMemory returns the obstacle memory of an execution on the named component, starting an empty one when the execution is
+// first planned. Returns nil if obstacles are not to be remembered.
+func (ms *builtIn) obstacleMemory(componentName resource.Name, cfg obstacleMemoryConfig, replanCount int) *obstacleMemory {
+	ms.memoryMu.Lock()
+	defer ms.memoryMu.Unlock()
+	if cfg.duration == 0 {
+		delete(ms.obstacleMemories, componentName)
+		return nil
+	}
+	if ms.obstacleMemories == nil {
+		ms.obstacleMemories = map[resource.Name]*obstacleMemory{}
+	}
+	m, ok := ms.obstacleMemories[componentName]
+	if !ok || replanCount == 0 {
+		m = &obstacleMemory{}
+		ms.obstacleMemories[componentName] = m
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cfg = cfg
+	return m
+}
+
+// remember stores copies of the world frame detections seen at the given time.
+func (m *obstacleMemory) remember(seen time.Time, detections []spatialmath.Geometry) error {
+	if m == nil || len(detections) == 0 {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// drop the obstacles which the new detections have been seen again as
+	kept := m.obstacles[:0]
+	for _, obstacle := range m.obstacles {
+		superseded := false
+		for _, detection := range detections {
+			covered, err := spatialmath.NewPoint(obstacle.geometry.Pose().Point(), "").CollidesWith(detection, 0)
+			if err != nil {
+				return err
+			}
+			if covered {
+				superseded = true
+				break
+			}
+		}
+		if !superseded {
+			kept = append(kept, obstacle)
+		}
+	}
+	m.obstacles = kept
+
+	for _, detection := range detections {
+		// the copy is relabeled so that it does not clash with the detection it was made from when both are in a world state
+		geometry := detection.Transform(spatialmath.NewZeroPose())
+		geometry.SetLabel(rememberedObstaclePrefix + strconv.Itoa(m.count) + "_" + detection.Label())
+		m.count++
+		m.obstacles = append(m.obstacles, rememberedObstacle{geometry: geometry, seen: seen})
+	}
+	return nil
+}
+
+// recall returns the remembered obstacles which have not been forgotten as of the given time and base position.
+func (m *obstacleMemory) recall(now time.Time, position r3.Vector) ([]spatialmath.Geometry, error) {
+	if m == nil {
+		return nil, nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	region, err := spatialmath.NewSphere(spatialmath.NewPoseFromPoint(position), m.cfg.radiusMM, "")
+	if err != nil {
+		return nil, err
+	}
+
+	kept := m.obstacles[:0]
+	recalled := make([]spatialmath.Geometry, 0, len(m.obstacles))
+	for _, obstacle := range m.obstacles {
+		if now.Sub(obstacle.seen) > m.cfg.duration {
+			continue
+		}
+		nearby, err := obstacle.geometry.CollidesWith(region, 0)
+		if err != nil {
+			return nil, err
+		}
+		if !nearby {
+			continue
+		}
+		kept = append(kept, obstacle)
+		recalled = append(recalled, obstacle.geometry)
+	}
+	m.obstacles = kept
+	return recalled, nil
+}
+
+// forget drops every remembered obstacle.
+func (m *obstacleMemory) forget() {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.obstacles = nil
+}
@@ -0,0 +1,107 @@
+package builtin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/spatialmath"
+)
+
+func TestObstacleMemory(t *testing.T) {
+	newBox := func(t *testing.T, pt r3.Vector, label string) spatialmath.Geometry {
+		t.Helper()
+		box, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(pt), r3.Vector{X: 200, Y: 200, Z: 200}, label)
+		test.That(t, err, test.ShouldBeNil)
+		return box
+	}
+	labels := func(geometries []spatialmath.Geometry) []string {
+		names := []string{}
+		for _, g := range geometries {
+			names = append(names, g.Label())
+		}
+		return names
+	}
+
+	t.Run("parsed from extra", func(t *testing.T) {
+		valExtra, err := newValidatedExtra(map[string]interface{}{obstacleMemoryExtraKey: 2.5})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, valExtra.obstacleMemory.duration, test.ShouldEqual, 2500*time.Millisecond)
+		test.That(t, valExtra.obstacleMemory.radiusMM, test.ShouldEqual, defaultObstacleMemoryRadiusMM)
+
+		valExtra, err = newValidatedExtra(map[string]interface{}{obstacleMemoryExtraKey: 2.5, obstacleMemoryRadiusExtraKey: 1000.})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, valExtra.obstacleMemory.radiusMM, test.ShouldEqual, 1000)
+
+		for _, bad := range []map[string]interface{}{
+			{obstacleMemoryExtraKey: -1.},
+			{obstacleMemoryExtraKey: "forever"},
+			{obstacleMemoryRadiusExtraKey: 1000.},
+			{obstacleMemoryExtraKey: 1., obstacleMemoryRadiusExtraKey: 0.},
+		} {
+			_, err := newValidatedExtra(bad)
+			test.That(t, err, test.ShouldNotBeNil)
+		}
+	})
+
+	t.Run("remembered across replans of an execution", func(t *testing.T) {
+		ms := &builtIn{}
+		cfg := obstacleMemoryConfig{duration: time.Second, radiusMM: 1000}
+		first := ms.obstacleMemory(base.Named("base"), cfg, 0)
+		test.That(t, first, test.ShouldNotBeNil)
+		test.That(t, ms.obstacleMemory(base.Named("base"), cfg, 1), test.ShouldEqual, first)
+		test.That(t, ms.obstacleMemory(base.Named("base"), cfg, 0), test.ShouldNotEqual, first)
+		test.That(t, ms.obstacleMemory(base.Named("base"), obstacleMemoryConfig{}, 1), test.ShouldBeNil)
+
+		// a nil memory remembers nothing
+		var nilMemory *obstacleMemory
+		test.That(t, nilMemory.remember(time.Now(), []spatialmath.Geometry{newBox(t, r3.Vector{}, "a")}), test.ShouldBeNil)
+		recalled, err := nilMemory.recall(time.Now(), r3.Vector{})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, recalled, test.ShouldBeEmpty)
+	})
+
+	t.Run("obstacles are forgotten by age and distance", func(t *testing.T) {
+		m := &obstacleMemory{cfg: obstacleMemoryConfig{duration: time.Second, radiusMM: 1000}}
+		start := time.Now()
+		near := newBox(t, r3.Vector{X: 500}, "near")
+		far := newBox(t, r3.Vector{X: 3000}, "far")
+		test.That(t, m.remember(start, []spatialmath.Geometry{near, far}), test.ShouldBeNil)
+
+		// the remembered copies are relabeled so they may be planned around alongside the detections they were made from
+		recalled, err := m.recall(start.Add(500*time.Millisecond), r3.Vector{})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, labels(recalled), test.ShouldResemble, []string{"remembered_0_near"})
+		test.That(t, near.Label(), test.ShouldEqual, "near")
+		test.That(t, spatialmath.GeometriesAlmostEqual(recalled[0], newBox(t, r3.Vector{X: 500}, "remembered_0_near")), test.ShouldBeTrue)
+
+		// the far obstacle was forgotten once the base was out of range, so it is not recalled when the base returns
+		recalled, err = m.recall(start.Add(500*time.Millisecond), r3.Vector{X: 2500})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, recalled, test.ShouldBeEmpty)
+
+		test.That(t, m.remember(start, []spatialmath.Geometry{near}), test.ShouldBeNil)
+		recalled, err = m.recall(start.Add(2*time.Second), r3.Vector{})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, recalled, test.ShouldBeEmpty)
+	})
+
+	t.Run("obstacles seen again replace their memories", func(t *testing.T) {
+		m := &obstacleMemory{cfg: obstacleMemoryConfig{duration: time.Second, radiusMM: 5000}}
+		start := time.Now()
+		test.That(t, m.remember(start, []spatialmath.Geometry{newBox(t, r3.Vector{X: 500}, "a"), newBox(t, r3.Vector{Y: 500}, "b")}),
+			test.ShouldBeNil)
+		test.That(t, m.remember(start.Add(time.Second), []spatialmath.Geometry{newBox(t, r3.Vector{X: 550}, "a")}), test.ShouldBeNil)
+
+		// the first sighting of a has been replaced, so a is remembered for a second from its latest sighting
+		recalled, err := m.recall(start.Add(time.Second), r3.Vector{})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, labels(recalled), test.ShouldResemble, []string{"remembered_1_b", "remembered_2_a"})
+		recalled, err = m.recall(start.Add(1500*time.Millisecond), r3.Vector{})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, labels(recalled), test.ShouldResemble, []string{"remembered_2_a"})
+	})
+}
@@ -0,0 +1,109 @@
+package builtin
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+
+	"go.viam.com/rdk/components/base/kinematicbase"
+	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/services/motion/builtin/state"
+)
+
+// obstacleWaitExtraKey is the key of extra setting how many seconds the base stops and waits for a transient obstacle blocking its
+// plan to clear before replanning around it, such as for a person crossing its path. Without it, blocked plans are replanned at once.
+const obstacleWaitExtraKey = "obstacle_wait_s"
+
+// parseObstacleWait parses how long to wait for obstacles blocking the plan to clear from extra, returning zero if it is not set.
+func parseObstacleWait(extra map[string]interface{}) (time.Duration, error) {
+	raw, ok := extra[obstacleWaitExtraKey]
+	if !ok {
+		return 0, nil
+	}
+	seconds, ok := raw.(float64)
+	if !ok {
+		return 0, fmt.Errorf("could not interpret %s field as float", obstacleWaitExtraKey)
+	}
+	if seconds < 0 {
+		return 0, fmt.Errorf("%s may not be negative", obstacleWaitExtraKey)
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// obstacleWait holds the base in place while the plan it is executing is blocked by an obstacle, for up to timeout. A nil
+// obstacleWait never waits.
+type obstacleWait struct {
+	timeout time.Duration
+	clock   clock.Clock
+
+	mu sync.Mutex
+	// since is when the plan was first seen to be blocked, and is zero while it is clear
+	since time.Time
+}
+
+// newObstacleWait returns an obstacleWait waiting up to timeout by the given clock, or by the wall clock if it is nil. It returns nil
+// if timeout is not positive.
+func newObstacleWait(timeout time.Duration, clk clock.Clock) *obstacleWait {
+	if timeout <= 0 {
+		return nil
+	}
+	if clk == nil {
+		clk = clock.New()
+	}
+	return &obstacleWait{timeout: timeout, clock: clk}
+}
+
+// useObstacleWait has the base hold in place while the plan is blocked, if the request waits for obstacles to clear. The local planner
+// steers around transient detections itself, so the base does not wait for them if it is used.
+func (mr *moveRequest) useObstacleWait(opts kinematicbase.Options) {
+	if opts.Hold == nil || mr.obstacleWait == nil {
+		return
+	}
+	if mr.localPlanning {
+		mr.logger.Warnf("%s is ignored since the local planner is used", obstacleWaitExtraKey)
+		mr.obstacleWait = nil
+		return
+	}
+	opts.Hold.Held = mr.obstacleWait.held
+}
+
+// held returns whether the base is waiting for an obstacle to clear.
+func (w *obstacleWait) held(context.Context) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return !w.since.IsZero()
+}
+
+// blocked records that the plan is blocked for the given reason, returning the response to it: no replan while the obstacle may
+// still clear, and a replan once it has been waited on for the full timeout.
+func (w *obstacleWait) blocked(reason string) state.ExecuteResponse {
+	if w == nil {
+		return state.ExecuteResponse{Replan: true, ReplanReason: reason, ReplanReasonCode: motion.ReplanReasonObstacle}
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.since.IsZero() {
+		w.since = w.clock.Now()
+	}
+	if w.clock.Since(w.since) < w.timeout {
+		return state.ExecuteResponse{}
+	}
+	return state.ExecuteResponse{
+		Replan:           true,
+		ReplanReason:     fmt.Sprintf("obstacle did not clear after waiting %v: %s", w.timeout, reason),
+		ReplanReasonCode: motion.ReplanReasonObstacle,
+	}
+}
+
+// clear records that the plan is no longer blocked, releasing the base.
+func (w *obstacleWait) clear() {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.since = time.Time{}
+}
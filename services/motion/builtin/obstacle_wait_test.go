@@ -0,0 +1,65 @@
+package builtin
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"go.viam.com/test"
+)
+
+func TestObstacleWait(t *testing.T) {
+	t.Run("parsed from extra", func(t *testing.T) {
+		valExtra, err := newValidatedExtra(map[string]interface{}{})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, valExtra.obstacleWait, test.ShouldEqual, 0)
+		test.That(t, kbOptionsFromCfg(&validatedMotionConfiguration{}, valExtra).Hold, test.ShouldBeNil)
+
+		valExtra, err = newValidatedExtra(map[string]interface{}{obstacleWaitExtraKey: 2.5})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, valExtra.obstacleWait, test.ShouldEqual, 2500*time.Millisecond)
+		test.That(t, kbOptionsFromCfg(&validatedMotionConfiguration{}, valExtra).Hold, test.ShouldNotBeNil)
+
+		_, err = newValidatedExtra(map[string]interface{}{obstacleWaitExtraKey: -1.})
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = newValidatedExtra(map[string]interface{}{obstacleWaitExtraKey: "2"})
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("replans at once without a wait", func(t *testing.T) {
+		w := newObstacleWait(0, nil)
+		test.That(t, w, test.ShouldBeNil)
+		resp := w.blocked("in the way")
+		test.That(t, resp.Replan, test.ShouldBeTrue)
+		test.That(t, resp.ReplanReason, test.ShouldEqual, "in the way")
+	})
+
+	t.Run("holds the base until the obstacle clears", func(t *testing.T) {
+		clk := clock.NewMock()
+		w := newObstacleWait(3*time.Second, clk)
+		test.That(t, w.held(context.Background()), test.ShouldBeFalse)
+
+		test.That(t, w.blocked("in the way").Replan, test.ShouldBeFalse)
+		test.That(t, w.held(context.Background()), test.ShouldBeTrue)
+		clk.Add(2 * time.Second)
+		test.That(t, w.blocked("in the way").Replan, test.ShouldBeFalse)
+
+		w.clear()
+		test.That(t, w.held(context.Background()), test.ShouldBeFalse)
+		// the wait starts over if the plan is blocked again
+		clk.Add(2 * time.Second)
+		test.That(t, w.blocked("in the way").Replan, test.ShouldBeFalse)
+	})
+
+	t.Run("replans once the obstacle persists", func(t *testing.T) {
+		clk := clock.NewMock()
+		w := newObstacleWait(3*time.Second, clk)
+		test.That(t, w.blocked("in the way").Replan, test.ShouldBeFalse)
+		clk.Add(3 * time.Second)
+		resp := w.blocked("in the way")
+		test.That(t, resp.Replan, test.ShouldBeTrue)
+		test.That(t, resp.ReplanReason, test.ShouldContainSubstring, "obstacle did not clear after waiting 3s")
+		test.That(t, resp.ReplanReason, test.ShouldContainSubstring, "in the way")
+	})
+}
@@ -0,0 +1,51 @@
+package builtin
+
+import (
+	"fmt"
+
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/referenceframe"
+)
+
+// occupancyGridExtraKey is the key of extra through which MoveOnMap is given the path of the YAML file of a ROS occupancy grid
+// map. When it is set the occupied cells of the grid are planned around in place of the point cloud map of the SLAM service, which
+// is then only used to localize the base, so the grid must be in the same frame as the SLAM map.
+const occupancyGridExtraKey = "occupancy_grid_map"
+
+// parseOccupancyGridPath parses the path of the occupancy grid map from extra, returning an empty path if it is not set.
+func parseOccupancyGridPath(extra map[string]interface{}) (string, error) {
+	raw, ok := extra[occupancyGridExtraKey]
+	if !ok {
+		return "", nil
+	}
+	path, ok := raw.(string)
+	if !ok {
+		return "", fmt.Errorf("could not interpret %s field as string", occupancyGridExtraKey)
+	}
+	if path == "" {
+		return "", fmt.Errorf("%s may not be empty", occupancyGridExtraKey)
+	}
+	return path, nil
+}
+
+// loadOccupancyGridMap reads the occupancy grid map at the given path, returning its occupied cells as an octree along with the
+// extents of those cells, which bound where the base may be planned to go as the extents of a SLAM point cloud map do.
+func loadOccupancyGridMap(path string) (*pointcloud.BasicOctree, []referenceframe.Limit, error) {
+	grid, err := pointcloud.NewOccupancyGridFromFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	octree, err := grid.ToBasicOctree()
+	if err != nil {
+		return nil, nil, err
+	}
+	if octree.Size() == 0 {
+		return nil, nil, fmt.Errorf("occupancy grid map %s has no occupied cells", path)
+	}
+	meta := octree.MetaData()
+	limits := []referenceframe.Limit{
+		{Min: meta.MinX, Max: meta.MaxX},
+		{Min: meta.MinY, Max: meta.MaxY},
+	}
+	return octree, limits, nil
+}
@@ -0,0 +1,57 @@
+package builtin
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.viam.com/test"
+)
+
+func TestOccupancyGridMap(t *testing.T) {
+	dir := t.TempDir()
+	yamlPath := filepath.Join(dir, "map.yaml")
+	// a 3x2 map with a single free cell at the top left of the image and its origin a meter below and to the left of the SLAM origin
+	pgm := append([]byte("P5\n3 2\n255\n"), 254, 0, 0, 0, 0, 0)
+	test.That(t, os.WriteFile(filepath.Join(dir, "map.pgm"), pgm, 0o600), test.ShouldBeNil)
+	yaml := "image: map.pgm\nresolution: 0.1\norigin: [-1.0, -1.0, 0.0]\n"
+	test.That(t, os.WriteFile(yamlPath, []byte(yaml), 0o600), test.ShouldBeNil)
+
+	t.Run("parsed from extra", func(t *testing.T) {
+		valExtra, err := newValidatedExtra(map[string]interface{}{occupancyGridExtraKey: yamlPath})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, valExtra.occupancyGridPath, test.ShouldEqual, yamlPath)
+		valExtra, err = newValidatedExtra(map[string]interface{}{})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, valExtra.occupancyGridPath, test.ShouldEqual, "")
+
+		_, err = newValidatedExtra(map[string]interface{}{occupancyGridExtraKey: ""})
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = newValidatedExtra(map[string]interface{}{occupancyGridExtraKey: 3})
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("loaded with its extents", func(t *testing.T) {
+		octree, limits, err := loadOccupancyGridMap(yamlPath)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, octree.Size(), test.ShouldEqual, 5)
+		_, ok := octree.At(-950, -850, 0)
+		test.That(t, ok, test.ShouldBeFalse)
+		_, ok = octree.At(-750, -950, 0)
+		test.That(t, ok, test.ShouldBeTrue)
+		test.That(t, len(limits), test.ShouldEqual, 2)
+		test.That(t, limits[0].Min, test.ShouldAlmostEqual, -950)
+		test.That(t, limits[0].Max, test.ShouldAlmostEqual, -750)
+		test.That(t, limits[1].Min, test.ShouldAlmostEqual, -950)
+		test.That(t, limits[1].Max, test.ShouldAlmostEqual, -850)
+	})
+
+	t.Run("maps without obstacles are rejected", func(t *testing.T) {
+		empty := append([]byte("P5\n1 1\n255\n"), 254)
+		test.That(t, os.WriteFile(filepath.Join(dir, "map.pgm"), empty, 0o600), test.ShouldBeNil)
+		_, _, err := loadOccupancyGridMap(yamlPath)
+		test.That(t, err, test.ShouldNotBeNil)
+		_, _, err = loadOccupancyGridMap(filepath.Join(dir, "missing.yaml"))
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,291 @@
+package builtin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/golang/geo/r3"
+	geo "github.com/kellydunn/golang-geo"
+
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+const (
+	// pathNetworkExtraKey is the key of extra through which MoveOnGlobe is given the network of paths, such as sidewalks or roads, the
+	// base must keep to, as GeoJSON LineStrings or MultiLineStrings, alone or in Features or a FeatureCollection. Lines which share a
+	// coordinate are connected there. The base is routed over the network from the point on it nearest to where the base starts to
+	// the point nearest to its destination, and is only planned for within a corridor around that route.
+	pathNetworkExtraKey = "path_network"
+	// pathNetworkCorridorExtraKey is the key of extra through which MoveOnGlobe is told the width, in millimeters, of the corridor
+	// around the route over the path network within which the base is planned for.
+	pathNetworkCorridorExtraKey = "path_network_corridor_width_mm"
+
+	defaultPathNetworkCorridorMM = 3000.
+)
+
+// geoJSON is the subset of a GeoJSON object which describes a path network.
+type geoJSON struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+	Geometry    *geoJSON        `json:"geometry"`
+	Features    []*geoJSON      `json:"features"`
+}
+
+// lines returns the lines of the GeoJSON object, each a list of longitude and latitude pairs.
+func (g *geoJSON) lines() ([][][]float64, error) {
+	switch g.Type {
+	case "FeatureCollection":
+		var lines [][][]float64
+		for _, feature := range g.Features {
+			featureLines, err := feature.lines()
+			if err != nil {
+				return nil, err
+			}
+			lines = append(lines, featureLines...)
+		}
+		return lines, nil
+	case "Feature":
+		if g.Geometry == nil {
+			return nil, nil
+		}
+		return g.Geometry.lines()
+	case "LineString":
+		var line [][]float64
+		if err := json.Unmarshal(g.Coordinates, &line); err != nil {
+			return nil, fmt.Errorf("could not interpret coordinates of LineString: %w", err)
+		}
+		return [][][]float64{line}, nil
+	case "MultiLineString":
+		var lines [][][]float64
+		if err := json.Unmarshal(g.Coordinates, &lines); err != nil {
+			return nil, fmt.Errorf("could not interpret coordinates of MultiLineString: %w", err)
+		}
+		return lines, nil
+	default:
+		return nil, fmt.Errorf("path networks are made of LineStrings and MultiLineStrings, not %q", g.Type)
+	}
+}
+
+// pathNetwork is a graph of the points at which the paths of a network bend or meet, connected by straight paths.
+type pathNetwork struct {
+	nodes []*geo.Point
+	// edges maps the index of each node to the indices of the nodes it is connected to
+	edges map[int]map[int]bool
+	// corridorMM is the width of the corridor around routes over the network
+	corridorMM float64
+}
+
+// parsePathNetwork parses the path network of a MoveOnGlobe request from extra, returning nil if the request has none.
+func parsePathNetwork(extra map[string]interface{}) (*pathNetwork, error) {
+	raw, ok := extra[pathNetworkExtraKey]
+	if !ok {
+		return nil, nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	var object geoJSON
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, fmt.Errorf("could not interpret %s field as GeoJSON: %w", pathNetworkExtraKey, err)
+	}
+	lines, err := object.lines()
+	if err != nil {
+		return nil, fmt.Errorf("could not interpret %s field: %w", pathNetworkExtraKey, err)
+	}
+
+	network := &pathNetwork{edges: map[int]map[int]bool{}, corridorMM: defaultPathNetworkCorridorMM}
+	indices := map[[2]float64]int{}
+	for _, line := range lines {
+		last := -1
+		for _, coordinate := range line {
+			if len(coordinate) < 2 {
+				return nil, fmt.Errorf("%s coordinates must be longitude and latitude pairs", pathNetworkExtraKey)
+			}
+			key := [2]float64{coordinate[0], coordinate[1]}
+			index, ok := indices[key]
+			if !ok {
+				index = len(network.nodes)
+				indices[key] = index
+				network.nodes = append(network.nodes, geo.NewPoint(coordinate[1], coordinate[0]))
+				network.edges[index] = map[int]bool{}
+			}
+			if last >= 0 && last != index {
+				network.edges[last][index] = true
+				network.edges[index][last] = true
+			}
+			last = index
+		}
+	}
+	if len(network.nodes) < 2 {
+		return nil, fmt.Errorf("%s must contain at least one path", pathNetworkExtraKey)
+	}
+
+	if corridorRaw, ok := extra[pathNetworkCorridorExtraKey]; ok {
+		corridorMM, ok := corridorRaw.(float64)
+		if !ok {
+			return nil, fmt.Errorf("could not interpret %s field as float", pathNetworkCorridorExtraKey)
+		}
+		if corridorMM <= 0 {
+			return nil, fmt.Errorf("%s must be positive", pathNetworkCorridorExtraKey)
+		}
+		network.corridorMM = corridorMM
+	}
+	return network, nil
+}
+
+// networkRoute is a route over a path network, as the points in the world frame of a MoveOnGlobe request at which it bends.
+type networkRoute struct {
+	points     []r3.Vector
+	corridorMM float64
+}
+
+// route returns the shortest route over the network from the given origin, where the base starts, to the destination in the world
+// frame of the request whose origin it is. The route joins the network at the point on it nearest to the origin, and leaves it at the
+// point nearest to the destination.
+func (n *pathNetwork) route(origin *geo.Point, destination r3.Vector) (*networkRoute, error) {
+	start := r3.Vector{}
+	points := make([]r3.Vector, 0, len(n.nodes)+2)
+	for _, node := range n.nodes {
+		points = append(points, spatialmath.GeoPointToPoint(node, origin))
+	}
+	edges := make([]map[int]float64, len(points), len(points)+2)
+	for i := range n.nodes {
+		edges[i] = map[int]float64{}
+		for j := range n.edges[i] {
+			edges[i][j] = points[i].Distance(points[j])
+		}
+	}
+
+	// the start and destination join the network as nodes on the paths nearest to them
+	join := func(p r3.Vector) (int, [2]int) {
+		var nearest r3.Vector
+		var path [2]int
+		best := math.Inf(1)
+		for i := range n.nodes {
+			for j := range n.edges[i] {
+				if j < i {
+					continue
+				}
+				q := nearestOnSegment(p, points[i], points[j])
+				if dist := q.Distance(p); dist < best {
+					best, nearest, path = dist, q, [2]int{i, j}
+				}
+			}
+		}
+		index := len(points)
+		points = append(points, nearest)
+		edges = append(edges, map[int]float64{})
+		for _, end := range path {
+			edges[index][end] = nearest.Distance(points[end])
+			edges[end][index] = edges[index][end]
+		}
+		return index, path
+	}
+	from, fromPath := join(start)
+	to, toPath := join(destination)
+	if fromPath == toPath {
+		edges[from][to] = points[from].Distance(points[to])
+		edges[to][from] = edges[from][to]
+	}
+
+	// Dijkstra's algorithm, choosing the nearest unvisited node by a linear scan as networks are small
+	distances := make([]float64, len(points))
+	previous := make([]int, len(points))
+	visited := make([]bool, len(points))
+	for i := range distances {
+		distances[i] = math.Inf(1)
+		previous[i] = -1
+	}
+	distances[from] = 0
+	for {
+		current := -1
+		for i, dist := range distances {
+			if !visited[i] && !math.IsInf(dist, 1) && (current < 0 || dist < distances[current]) {
+				current = i
+			}
+		}
+		if current < 0 || current == to {
+			break
+		}
+		visited[current] = true
+		for next, length := range edges[current] {
+			if dist := distances[current] + length; dist < distances[next] {
+				distances[next] = dist
+				previous[next] = current
+			}
+		}
+	}
+	if math.IsInf(distances[to], 1) {
+		return nil, errors.New("the destination cannot be reached over the path network")
+	}
+
+	reversed := []r3.Vector{destination}
+	for i := to; i >= 0; i = previous[i] {
+		reversed = append(reversed, points[i])
+	}
+	reversed = append(reversed, start)
+	route := &networkRoute{corridorMM: n.corridorMM}
+	for i := len(reversed) - 1; i >= 0; i-- {
+		if len(route.points) > 0 && route.points[len(route.points)-1].Distance(reversed[i]) < 1 {
+			continue
+		}
+		route.points = append(route.points, reversed[i])
+	}
+	return route, nil
+}
+
+// nearestOnSegment returns the point on the segment from a to b nearest to p.
+func nearestOnSegment(p, a, b r3.Vector) r3.Vector {
+	ab := b.Sub(a)
+	if ab.Norm2() == 0 {
+		return a
+	}
+	t := math.Max(0, math.Min(1, p.Sub(a).Dot(ab)/ab.Norm2()))
+	return a.Add(ab.Mul(t))
+}
+
+// corridors returns the regions around each leg of the route within which the base is planned for. Each overlaps the next by half
+// the width of the corridor, so that the base may turn from one to the next.
+func (r *networkRoute) corridors() ([]spatialmath.Geometry, error) {
+	corridors := make([]spatialmath.Geometry, 0, len(r.points)-1)
+	for i := 1; i < len(r.points); i++ {
+		a, b := r.points[i-1], r.points[i]
+		leg := b.Sub(a)
+		pose := spatialmath.NewPose(
+			a.Add(b).Mul(0.5),
+			&spatialmath.OrientationVectorDegrees{OZ: 1, Theta: math.Atan2(leg.Y, leg.X) * 180 / math.Pi},
+		)
+		corridor, err := spatialmath.NewBox(
+			pose,
+			r3.Vector{X: leg.Norm() + r.corridorMM, Y: r.corridorMM, Z: r.corridorMM},
+			fmt.Sprintf("path_network_corridor_%d", i-1),
+		)
+		if err != nil {
+			return nil, err
+		}
+		corridors = append(corridors, corridor)
+	}
+	return corridors, nil
+}
+
+// waypoints returns the goals of the named frame at the bends of the route, excluding its start and end, through which the base is
+// planned for on its way to the destination. Bends within the width of the corridor of the last are skipped.
+func (r *networkRoute) waypoints(frameName string) []*motionplan.PlanState {
+	var waypoints []*motionplan.PlanState
+	last := r.points[0]
+	for _, p := range r.points[1 : len(r.points)-1] {
+		if p.Distance(last) < r.corridorMM || p.Distance(r.points[len(r.points)-1]) < r.corridorMM {
+			continue
+		}
+		waypoints = append(waypoints, motionplan.NewPlanState(referenceframe.FrameSystemPoses{
+			frameName: referenceframe.NewPoseInFrame(referenceframe.World, spatialmath.NewPoseFromPoint(p)),
+		}, nil))
+		last = p
+	}
+	return waypoints
+}
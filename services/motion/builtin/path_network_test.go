@@ -0,0 +1,105 @@
+package builtin
+
+import (
+	"testing"
+
+	"github.com/golang/geo/r3"
+	geo "github.com/kellydunn/golang-geo"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/spatialmath"
+)
+
+func TestPathNetwork(t *testing.T) {
+	origin := geo.NewPoint(40.7, -74)
+	// coordinates returns the GeoJSON coordinates of the point in the world frame of a request from the origin
+	coordinates := func(x, y float64) []interface{} {
+		p := spatialmath.PoseToGeoPose(spatialmath.NewGeoPose(origin, 0), spatialmath.NewPoseFromPoint(r3.Vector{X: x, Y: y})).Location()
+		return []interface{}{p.Lng(), p.Lat()}
+	}
+	line := func(points ...[]interface{}) map[string]interface{} {
+		coordinates := make([]interface{}, 0, len(points))
+		for _, p := range points {
+			coordinates = append(coordinates, p)
+		}
+		return map[string]interface{}{"type": "Feature", "geometry": map[string]interface{}{"type": "LineString", "coordinates": coordinates}}
+	}
+	// a short path north then east, and a longer one south east then north, to the same corner
+	short := line(coordinates(0, 0), coordinates(0, 10000), coordinates(10000, 10000))
+	long := line(coordinates(0, 0), coordinates(5000, -5000), coordinates(15000, -5000), coordinates(10000, 10000))
+	extra := map[string]interface{}{pathNetworkExtraKey: map[string]interface{}{
+		"type":     "FeatureCollection",
+		"features": []interface{}{short, long},
+	}}
+
+	t.Run("invalid networks are rejected", func(t *testing.T) {
+		network, err := parsePathNetwork(map[string]interface{}{})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, network, test.ShouldBeNil)
+		for _, raw := range []interface{}{
+			"sidewalks",
+			map[string]interface{}{"type": "Point", "coordinates": []interface{}{1., 2.}},
+			map[string]interface{}{"type": "LineString", "coordinates": []interface{}{[]interface{}{1., 2.}}},
+			map[string]interface{}{"type": "LineString", "coordinates": []interface{}{[]interface{}{1.}, []interface{}{1., 2.}}},
+		} {
+			_, err := parsePathNetwork(map[string]interface{}{pathNetworkExtraKey: raw})
+			test.That(t, err, test.ShouldNotBeNil)
+		}
+		_, err = parsePathNetwork(map[string]interface{}{pathNetworkExtraKey: short, pathNetworkCorridorExtraKey: 0.})
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("bases are routed over the shortest path", func(t *testing.T) {
+		network, err := parsePathNetwork(extra)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, network.corridorMM, test.ShouldEqual, defaultPathNetworkCorridorMM)
+		route, err := network.route(origin, r3.Vector{X: 10000, Y: 10500})
+		test.That(t, err, test.ShouldBeNil)
+		expected := []r3.Vector{{}, {Y: 10000}, {X: 10000, Y: 10000}, {X: 10000, Y: 10500}}
+		test.That(t, len(route.points), test.ShouldEqual, len(expected))
+		for i, p := range route.points {
+			test.That(t, p.Distance(expected[i]), test.ShouldBeLessThan, 10)
+		}
+
+		// each leg is planned for within its own corridor
+		corridors, err := route.corridors()
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(corridors), test.ShouldEqual, 3)
+		inCorridor := func(p r3.Vector) bool {
+			for _, corridor := range corridors {
+				if inside, _ := spatialmath.NewPoint(p, "").EncompassedBy(corridor); inside {
+					return true
+				}
+			}
+			return false
+		}
+		test.That(t, inCorridor(r3.Vector{X: 1000, Y: 5000}), test.ShouldBeTrue)
+		test.That(t, inCorridor(r3.Vector{X: 5000, Y: 11000}), test.ShouldBeTrue)
+		test.That(t, inCorridor(r3.Vector{X: 5000, Y: 5000}), test.ShouldBeFalse)
+		test.That(t, inCorridor(r3.Vector{X: 10000, Y: -5000}), test.ShouldBeFalse)
+
+		// the base is planned through the bends of its route, other than those near its destination
+		waypoints := route.waypoints("base")
+		test.That(t, len(waypoints), test.ShouldEqual, 1)
+		test.That(t, waypoints[0].Poses()["base"].Pose().Point().Distance(r3.Vector{Y: 10000}), test.ShouldBeLessThan, 10)
+	})
+
+	t.Run("destinations off the network cannot be reached", func(t *testing.T) {
+		extra := map[string]interface{}{pathNetworkExtraKey: map[string]interface{}{
+			"type": "MultiLineString",
+			"coordinates": []interface{}{
+				[]interface{}{coordinates(0, 0), coordinates(0, 10000)},
+				[]interface{}{coordinates(50000, 0), coordinates(50000, 10000)},
+			},
+		}}
+		network, err := parsePathNetwork(extra)
+		test.That(t, err, test.ShouldBeNil)
+		_, err = network.route(origin, r3.Vector{X: 50000, Y: 5000})
+		test.That(t, err, test.ShouldNotBeNil)
+
+		// unless they are on the same path
+		route, err := network.route(origin, r3.Vector{X: 100, Y: 5000})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(route.points), test.ShouldEqual, 3)
+	})
+}
@@ -0,0 +1,56 @@
+package builtin
+
+import (
+	"context"
+
+	"go.viam.com/rdk/components/arm"
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/referenceframe"
+)
+
+// constrainPayloads returns a copy of the given constraints which keeps the loads on the joints of each arm moving the given frame
+// within their limits, for those arms which carry a payload. Arms which cannot be told their payload are left unconstrained.
+func (ms *builtIn) constrainPayloads(
+	ctx context.Context,
+	constraints *motionplan.Constraints,
+	frameSys referenceframe.FrameSystem,
+	movingFrame referenceframe.Frame,
+) (*motionplan.Constraints, error) {
+	chain, err := frameSys.TracebackFrame(movingFrame)
+	if err != nil {
+		return nil, err
+	}
+	var torqueConstraints []motionplan.TorqueConstraint
+	for _, frame := range chain {
+		component, ok := findByShortName(ms.components, frame.Name())
+		if !ok {
+			continue
+		}
+		carrier, ok := component.(arm.PayloadCarrier)
+		if !ok {
+			continue
+		}
+		payload, err := carrier.Payload(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		if payload == nil || payload.Mass == 0 {
+			continue
+		}
+		torqueConstraints = append(torqueConstraints, motionplan.TorqueConstraint{
+			Frame:               frame.Name(),
+			PayloadKg:           payload.Mass,
+			PayloadCenterOfMass: payload.CenterOfMass,
+		})
+	}
+	if len(torqueConstraints) == 0 {
+		return constraints, nil
+	}
+	payloadConstraints := motionplan.NewEmptyConstraints()
+	if constraints != nil {
+		*payloadConstraints = *constraints
+	}
+	payloadConstraints.TorqueConstraint = append(append([]motionplan.TorqueConstraint{}, payloadConstraints.TorqueConstraint...),
+		torqueConstraints...)
+	return payloadConstraints, nil
+}
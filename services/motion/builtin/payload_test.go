@@ -0,0 +1,67 @@
+package builtin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+// payloadArm is an arm which carries a payload.
+type payloadArm struct {
+	*inject.Arm
+	payload *referenceframe.Payload
+}
+
+func (a *payloadArm) SetPayload(ctx context.Context, mass float64, centerOfMass r3.Vector, extra map[string]interface{}) error {
+	a.payload = &referenceframe.Payload{Mass: mass, CenterOfMass: centerOfMass}
+	return nil
+}
+
+func (a *payloadArm) Payload(ctx context.Context, extra map[string]interface{}) (*referenceframe.Payload, error) {
+	return a.payload, nil
+}
+
+func TestConstrainPayloads(t *testing.T) {
+	ctx := context.Background()
+	fs := referenceframe.NewEmptyFrameSystem("test")
+	armFrame, err := referenceframe.NewRotationalFrame("arm", spatialmath.R4AA{RY: 1}, referenceframe.Limit{Min: -3, Max: 3})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(armFrame, fs.World()), test.ShouldBeNil)
+	gripperFrame, err := referenceframe.NewStaticFrame("gripper", spatialmath.NewPoseFromPoint(r3.Vector{Z: 100}))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(gripperFrame, armFrame), test.ShouldBeNil)
+	otherFrame, err := referenceframe.NewRotationalFrame("other", spatialmath.R4AA{RY: 1}, referenceframe.Limit{Min: -3, Max: 3})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(otherFrame, fs.World()), test.ShouldBeNil)
+
+	a := &payloadArm{Arm: inject.NewArm("arm")}
+	other := &payloadArm{Arm: inject.NewArm("other"), payload: &referenceframe.Payload{Mass: 5}}
+	ms := &builtIn{components: map[resource.Name]resource.Resource{a.Name(): a, other.Name(): other}}
+	existing := &motionplan.Constraints{OrientationConstraint: []motionplan.OrientationConstraint{{OrientationToleranceDegs: 5}}}
+
+	t.Run("arms without a payload are unconstrained", func(t *testing.T) {
+		constraints, err := ms.constrainPayloads(ctx, existing, fs, gripperFrame)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, constraints, test.ShouldEqual, existing)
+	})
+
+	t.Run("the arm moving the frame is constrained by its payload", func(t *testing.T) {
+		test.That(t, a.SetPayload(ctx, 2, r3.Vector{Z: 50}, nil), test.ShouldBeNil)
+		constraints, err := ms.constrainPayloads(ctx, existing, fs, gripperFrame)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, constraints.OrientationConstraint, test.ShouldResemble, existing.OrientationConstraint)
+		// the payload of the arm which does not move is not constrained
+		test.That(t, constraints.TorqueConstraint, test.ShouldResemble, []motionplan.TorqueConstraint{
+			{Frame: "arm", PayloadKg: 2, PayloadCenterOfMass: r3.Vector{Z: 50}},
+		})
+		test.That(t, existing.TorqueConstraint, test.ShouldBeEmpty)
+	})
+}
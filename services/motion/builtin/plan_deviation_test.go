@@ -0,0 +1,51 @@
+package builtin
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/components/base/kinematicbase"
+	simbase "go.viam.com/rdk/components/base/kinematicbase/fake"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/spatialmath"
+)
+
+func TestAdaptivePlanDeviation(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	limits := []referenceframe.Limit{{Min: -5000, Max: 5000}, {Min: -5000, Max: 5000}, {Min: -2 * math.Pi, Max: 2 * math.Pi}}
+
+	newRequest := func(t *testing.T, noise simbase.NoiseModel) *moveRequest {
+		t.Helper()
+		b := simbase.NewBase(base.Named("base"), &simbase.World{LocalizationNoise: noise}, spatialmath.NewZeroPose(), nil, 0, logger)
+		kb, err := b.WrapWithKinematics(ctx, limits, kinematicbase.NewKinematicBaseOptions())
+		test.That(t, err, test.ShouldBeNil)
+		return &moveRequest{
+			logger:        logger,
+			kinematicBase: kb,
+			config:        &validatedMotionConfiguration{planDeviationMM: 1000},
+		}
+	}
+
+	t.Run("the configured deviation is used when localization is certain", func(t *testing.T) {
+		test.That(t, newRequest(t, nil).planDeviationMM(ctx), test.ShouldEqual, 1000)
+		test.That(t, newRequest(t, simbase.NewGaussianPoseNoise(100, 1, 1)).planDeviationMM(ctx), test.ShouldEqual, 1000)
+	})
+
+	t.Run("the deviation widens with localization uncertainty", func(t *testing.T) {
+		test.That(t, newRequest(t, simbase.NewGaussianPoseNoise(500, 1, 1)).planDeviationMM(ctx), test.ShouldEqual, 1500)
+		// the widened deviation is bounded so that the plan is still monitored
+		test.That(t, newRequest(t, simbase.NewGaussianPoseNoise(5000, 1, 1)).planDeviationMM(ctx), test.ShouldEqual, 4000)
+	})
+
+	t.Run("confidence converts to a covariance", func(t *testing.T) {
+		cov := motion.LocalizerConfidence{PositionStdDevMM: 3, HeadingStdDevDeg: 2}.Covariance()
+		test.That(t, cov, test.ShouldResemble, motion.PoseCovariance{{9, 0, 0}, {0, 9, 0}, {0, 0, 4}})
+	})
+}
@@ -0,0 +1,156 @@
+package builtin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/go-viper/mapstructure/v2"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/components/base/kinematicbase"
+	"go.viam.com/rdk/motionplan/tpspace"
+)
+
+// ptgLibraryRequest describes the PTG library of a base to get, set, or save through DoCommand.
+type ptgLibraryRequest struct {
+	BaseName string                 `mapstructure:"base_name"`
+	Library  map[string]interface{} `mapstructure:"library"`
+}
+
+// loadPTGLibraries reads the PTG library persisted for each base at the path configured for it. A base whose file does not exist
+// yet plans with the default library until one is saved there.
+func loadPTGLibraries(paths map[string]string) (map[string]*tpspace.PTGLibrary, error) {
+	libraries := map[string]*tpspace.PTGLibrary{}
+	for baseName, path := range paths {
+		library, err := tpspace.ReadPTGLibraryFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not load ptg library of base %s: %w", baseName, err)
+		}
+		libraries[baseName] = library
+	}
+	return libraries, nil
+}
+
+// ptgLibrary returns the PTG library the named base plans with, or nil if it plans with the default library.
+func (ms *builtIn) ptgLibrary(baseName string) *tpspace.PTGLibrary {
+	ms.ptgMu.Lock()
+	defer ms.ptgMu.Unlock()
+	return ms.ptgLibraries[baseName]
+}
+
+// getPTGLibrary returns the PTG library the base plans with, which is the default library for the base if it has not been given
+// its own.
+func (ms *builtIn) getPTGLibrary(ctx context.Context, raw interface{}) (map[string]interface{}, error) {
+	req, b, err := ms.decodePTGLibraryRequest(raw)
+	if err != nil {
+		return nil, err
+	}
+	kb, err := ms.wrapWithPTGLibrary(ctx, b, ms.ptgLibrary(req.BaseName))
+	if err != nil {
+		return nil, err
+	}
+	provider, ok := kb.Kinematics().(tpspace.PTGLibraryProvider)
+	if !ok {
+		return nil, fmt.Errorf("base %s does not plan with PTGs", req.BaseName)
+	}
+	return ptgLibraryToMap(provider.PTGLibrary())
+}
+
+// setPTGLibrary replaces the PTG library the base plans with, until the motion service is reconfigured. The library is checked by
+// building the kinematics of the base from it.
+func (ms *builtIn) setPTGLibrary(ctx context.Context, raw interface{}) error {
+	req, b, err := ms.decodePTGLibraryRequest(raw)
+	if err != nil {
+		return err
+	}
+	if req.Library == nil {
+		return errors.New("library is required to set a ptg library")
+	}
+	data, err := json.Marshal(req.Library)
+	if err != nil {
+		return err
+	}
+	library := &tpspace.PTGLibrary{}
+	if err := json.Unmarshal(data, library); err != nil {
+		return err
+	}
+	if _, err := ms.wrapWithPTGLibrary(ctx, b, library); err != nil {
+		return err
+	}
+	ms.ptgMu.Lock()
+	defer ms.ptgMu.Unlock()
+	if ms.ptgLibraries == nil {
+		ms.ptgLibraries = map[string]*tpspace.PTGLibrary{}
+	}
+	ms.ptgLibraries[req.BaseName] = library
+	return nil
+}
+
+// savePTGLibrary persists the PTG library the base plans with at the path configured for it, so that it is loaded again when the
+// motion service is reconfigured.
+func (ms *builtIn) savePTGLibrary(raw interface{}) error {
+	var req ptgLibraryRequest
+	if err := mapstructure.Decode(raw, &req); err != nil {
+		return err
+	}
+	ms.ptgMu.Lock()
+	defer ms.ptgMu.Unlock()
+	path, ok := ms.ptgLibraryPaths[req.BaseName]
+	if !ok {
+		return fmt.Errorf("no ptg library file is configured for base %q", req.BaseName)
+	}
+	library, ok := ms.ptgLibraries[req.BaseName]
+	if !ok {
+		return fmt.Errorf("base %q has not been given a ptg library to save", req.BaseName)
+	}
+	return library.WriteFile(path)
+}
+
+func (ms *builtIn) decodePTGLibraryRequest(raw interface{}) (ptgLibraryRequest, base.Base, error) {
+	var req ptgLibraryRequest
+	if err := mapstructure.Decode(raw, &req); err != nil {
+		return req, nil, err
+	}
+	if req.BaseName == "" {
+		return req, nil, errors.New("base_name is required")
+	}
+	component, ok := findByShortName(ms.components, req.BaseName)
+	if !ok {
+		return req, nil, fmt.Errorf("%q is not a dependency of the motion service", req.BaseName)
+	}
+	b, ok := component.(base.Base)
+	if !ok {
+		return req, nil, fmt.Errorf("%q is not a base", req.BaseName)
+	}
+	return req, b, nil
+}
+
+// wrapWithPTGLibrary wraps the base with PTG kinematics planning with the library, or with the default library if it is nil.
+func (ms *builtIn) wrapWithPTGLibrary(
+	ctx context.Context,
+	b base.Base,
+	library *tpspace.PTGLibrary,
+) (kinematicbase.KinematicBase, error) {
+	options := kinematicbase.NewKinematicBaseOptions()
+	options.UsePTGs = true
+	options.PTGLibrary = library
+	return kinematicbase.WrapWithKinematics(ctx, b, ms.logger, nil, nil, options)
+}
+
+func ptgLibraryToMap(library *tpspace.PTGLibrary) (map[string]interface{}, error) {
+	data, err := json.Marshal(library)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
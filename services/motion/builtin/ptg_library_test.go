@@ -0,0 +1,46 @@
+package builtin
+
+import (
+	"path/filepath"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/motionplan/tpspace"
+)
+
+func TestPTGLibraryPersistence(t *testing.T) {
+	dir := t.TempDir()
+	library := &tpspace.PTGLibrary{
+		PTGs: []tpspace.PTGConfig{{Type: tpspace.PTGTypeCircle, MaxDistanceMM: 1000, RestrictedDistanceMM: 1000}},
+	}
+	paths := map[string]string{"rover": filepath.Join(dir, "rover.json"), "forklift": filepath.Join(dir, "forklift.json")}
+	test.That(t, library.WriteFile(paths["rover"]), test.ShouldBeNil)
+
+	// bases whose libraries have not been saved yet plan with the default library
+	libraries, err := loadPTGLibraries(paths)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, libraries, test.ShouldResemble, map[string]*tpspace.PTGLibrary{"rover": library})
+
+	ms := &builtIn{ptgLibraries: libraries, ptgLibraryPaths: paths}
+	test.That(t, ms.ptgLibrary("rover"), test.ShouldResemble, library)
+	test.That(t, ms.ptgLibrary("forklift"), test.ShouldBeNil)
+	test.That(t, ms.savePTGLibrary(map[string]interface{}{"base_name": "forklift"}), test.ShouldNotBeNil)
+	test.That(t, ms.savePTGLibrary(map[string]interface{}{"base_name": "crane"}), test.ShouldNotBeNil)
+
+	ms.ptgLibraries["forklift"] = library
+	test.That(t, ms.savePTGLibrary(map[string]interface{}{"base_name": "forklift"}), test.ShouldBeNil)
+	libraries, err = loadPTGLibraries(paths)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, libraries["forklift"], test.ShouldResemble, library)
+
+	m, err := ptgLibraryToMap(library)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, m["correction_index"], test.ShouldEqual, 0.)
+	test.That(t, m["ptgs"], test.ShouldHaveLength, 1)
+
+	// libraries which are not valid are not loaded
+	test.That(t, (&tpspace.PTGLibrary{}).WriteFile(paths["rover"]), test.ShouldBeNil)
+	_, err = loadPTGLibraries(paths)
+	test.That(t, err, test.ShouldNotBeNil)
+}
@@ -0,0 +1,252 @@
+package builtin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-viper/mapstructure/v2"
+
+	"go.viam.com/rdk/components/arm"
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/services/slam"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// defaultReadinessLatency is how long a localizer or obstacle detector may take to respond before it is reported as not ready.
+const defaultReadinessLatency = time.Second
+
+// readinessRequest describes the dependencies of a prospective motion request whose readiness is checked.
+type readinessRequest struct {
+	ComponentName      string                   `mapstructure:"component_name"`
+	MovementSensorName string                   `mapstructure:"movement_sensor_name"`
+	SlamServiceName    string                   `mapstructure:"slam_service_name"`
+	ObstacleDetectors  []readinessDetectorNames `mapstructure:"obstacle_detectors"`
+	MaxLatencyMS       float64                  `mapstructure:"max_latency_ms"`
+	Extra              map[string]interface{}   `mapstructure:"extra"`
+}
+
+type readinessDetectorNames struct {
+	VisionService string `mapstructure:"vision_service"`
+	Camera        string `mapstructure:"camera"`
+}
+
+// readinessCheck is the result of checking a single dependency of a prospective motion request.
+type readinessCheck struct {
+	dependency string
+	err        error
+	latency    time.Duration
+}
+
+func (c readinessCheck) toMap() map[string]interface{} {
+	m := map[string]interface{}{
+		"dependency": c.dependency,
+		"ready":      c.err == nil,
+		"latency_ms": float64(c.latency.Microseconds()) / 1e3,
+	}
+	if c.err != nil {
+		m["error"] = c.err.Error()
+	}
+	return m
+}
+
+// checkReadiness validates the wiring of a prospective motion request without moving anything. Every named dependency is
+// checked, so that all misconfigurations are reported at once rather than one per attempted request.
+func (ms *builtIn) checkReadiness(ctx context.Context, raw interface{}) (map[string]interface{}, error) {
+	var req readinessRequest
+	if err := mapstructure.Decode(raw, &req); err != nil {
+		return nil, err
+	}
+	if req.ComponentName == "" {
+		return nil, errors.New("component_name is required to check readiness")
+	}
+	if req.MaxLatencyMS < 0 {
+		return nil, errors.New("max_latency_ms may not be negative")
+	}
+	maxLatency := defaultReadinessLatency
+	if req.MaxLatencyMS > 0 {
+		maxLatency = time.Duration(req.MaxLatencyMS * float64(time.Millisecond))
+	}
+	valExtra, err := newValidatedExtra(req.Extra)
+	if err != nil {
+		return nil, err
+	}
+
+	checks := []readinessCheck{ms.checkComponent(req.ComponentName), ms.checkFrames(ctx, req.ComponentName)}
+	if req.MovementSensorName != "" {
+		checks = append(checks, ms.checkLocalizer(ctx, valExtra, req.ComponentName, req.MovementSensorName, maxLatency))
+	}
+	if req.SlamServiceName != "" {
+		checks = append(checks,
+			ms.checkSLAMMap(ctx, req.SlamServiceName),
+			ms.checkLocalizer(ctx, valExtra, req.ComponentName, req.SlamServiceName, maxLatency),
+		)
+	}
+	for _, detector := range req.ObstacleDetectors {
+		checks = append(checks, ms.checkObstacleDetector(ctx, detector, maxLatency))
+	}
+
+	ready := true
+	report := make([]interface{}, 0, len(checks))
+	for _, check := range checks {
+		ready = ready && check.err == nil
+		report = append(report, check.toMap())
+	}
+	return map[string]interface{}{"ready": ready, "checks": report}, nil
+}
+
+// checkComponent checks that the component to be moved is a dependency of the motion service and is either a base or an arm.
+func (ms *builtIn) checkComponent(componentName string) readinessCheck {
+	check := readinessCheck{dependency: "component " + componentName}
+	component, ok := findByShortName(ms.components, componentName)
+	if !ok {
+		check.err = fmt.Errorf("%q is not a dependency of the motion service", componentName)
+		return check
+	}
+	switch component.(type) {
+	case base.Base, arm.Arm:
+	default:
+		check.err = fmt.Errorf("cannot move component of type %T because it is neither a Base nor an Arm", component)
+	}
+	return check
+}
+
+// checkFrames checks that the component is in the frame system and that its pose in the world can be resolved.
+func (ms *builtIn) checkFrames(ctx context.Context, componentName string) readinessCheck {
+	check := readinessCheck{dependency: "frame system"}
+	if ms.fsService == nil {
+		check.err = errors.New("the motion service has no frame system")
+		return check
+	}
+	frameSys, err := ms.fsService.FrameSystem(ctx, nil)
+	if err != nil {
+		check.err = err
+		return check
+	}
+	if frameSys.Frame(componentName) == nil {
+		check.err = fmt.Errorf("component named %s not found in robot frame system", componentName)
+		return check
+	}
+	origin := referenceframe.NewPoseInFrame(componentName, spatialmath.NewZeroPose())
+	if _, err := ms.fsService.TransformPose(ctx, origin, referenceframe.World, nil); err != nil {
+		check.err = fmt.Errorf("cannot resolve the pose of %s in the world frame: %w", componentName, err)
+	}
+	return check
+}
+
+// checkLocalizer checks that the localizer a request would build from the named movement sensor or slam service returns a pose
+// within the latency bound.
+func (ms *builtIn) checkLocalizer(
+	ctx context.Context,
+	valExtra validatedExtra,
+	componentName, sourceName string,
+	maxLatency time.Duration,
+) readinessCheck {
+	check := readinessCheck{dependency: "localizer " + sourceName}
+	source, err := ms.localizerSource(sourceName)
+	if err != nil {
+		check.err = err
+		return check
+	}
+	sources := motion.LocalizerSources{BodyName: componentName}
+	defaultName := motion.SLAMLocalizerName
+	if movementSensor, ok := source.(movementsensor.MovementSensor); ok {
+		// as in MoveOnGlobe, poses are relative to the position of the movement sensor at the start of the request
+		defaultName = motion.MovementSensorLocalizerName
+		sources.Origin, _, err = movementSensor.Position(ctx, nil)
+		if err != nil {
+			check.err = err
+			return check
+		}
+	}
+	localizer, err := ms.newLocalizer(ctx, valExtra, defaultName, source, sources)
+	if err != nil {
+		check.err = err
+		return check
+	}
+	check.latency, check.err = timeCall(ctx, maxLatency, func(ctx context.Context) error {
+		_, err := localizer.CurrentPosition(ctx)
+		return err
+	})
+	return check
+}
+
+// checkSLAMMap checks that the slam service is in localization only mode and has a map to plan on.
+func (ms *builtIn) checkSLAMMap(ctx context.Context, slamName string) readinessCheck {
+	check := readinessCheck{dependency: "slam map " + slamName}
+	slamSvc, ok := findByShortName(ms.slamServices, slamName)
+	if !ok {
+		check.err = fmt.Errorf("%q is not a dependency of the motion service", slamName)
+		return check
+	}
+	props, err := slamSvc.Properties(ctx)
+	if err != nil {
+		check.err = err
+		return check
+	}
+	if props.MappingMode != slam.MappingModeLocalizationOnly {
+		check.err = fmt.Errorf("expected SLAM to be in localization only mode, got %v", props.MappingMode)
+		return check
+	}
+	pointCloudData, err := slam.PointCloudMapFull(ctx, slamSvc, true)
+	if err != nil {
+		check.err = err
+		return check
+	}
+	if len(pointCloudData) == 0 {
+		check.err = errors.New("the slam service returned an empty map")
+	}
+	return check
+}
+
+// checkObstacleDetector checks that the vision service is a dependency of the motion service and detects obstacles with the
+// camera within the latency bound.
+func (ms *builtIn) checkObstacleDetector(
+	ctx context.Context,
+	detector readinessDetectorNames,
+	maxLatency time.Duration,
+) readinessCheck {
+	check := readinessCheck{dependency: fmt.Sprintf("obstacle detector %s/%s", detector.VisionService, detector.Camera)}
+	visionSvc, ok := findByShortName(ms.visionServices, detector.VisionService)
+	if !ok {
+		check.err = fmt.Errorf("%q is not a dependency of the motion service", detector.VisionService)
+		return check
+	}
+	check.latency, check.err = timeCall(ctx, maxLatency, func(ctx context.Context) error {
+		_, err := visionSvc.GetObjectPointClouds(ctx, detector.Camera, nil)
+		return err
+	})
+	return check
+}
+
+// timeCall times the call, failing it if it does not return within the latency bound.
+func timeCall(ctx context.Context, maxLatency time.Duration, call func(context.Context) error) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, maxLatency)
+	defer cancel()
+	start := time.Now()
+	err := call(ctx)
+	latency := time.Since(start)
+	if err != nil {
+		return latency, err
+	}
+	if latency > maxLatency {
+		return latency, fmt.Errorf("responded in %v, exceeding the %v latency bound", latency, maxLatency)
+	}
+	return latency, nil
+}
+
+// findByShortName finds the dependency with the given short or fully qualified name.
+func findByShortName[T any](deps map[resource.Name]T, name string) (T, bool) {
+	for depName, dep := range deps {
+		if depName.ShortName() == name || depName.String() == name {
+			return dep, true
+		}
+	}
+	var zero T
+	return zero, false
+}
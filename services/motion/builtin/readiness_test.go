@@ -0,0 +1,92 @@
+package builtin
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	geo "github.com/kellydunn/golang-geo"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/services/vision"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+	viz "go.viam.com/rdk/vision"
+)
+
+func TestCheckReadiness(t *testing.T) {
+	ctx := context.Background()
+	_, ms, closeFunc := CreateMoveOnGlobeTestEnvironment(ctx, t, geo.NewPoint(0, 0), 80, spatialmath.NewZeroPose())
+	defer closeFunc(ctx)
+	injectedVis, ok := ms.(*builtIn).visionServices[vision.Named("injectedVisionSvc")].(*inject.VisionService)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	checkReadiness := func(t *testing.T, req map[string]interface{}) (bool, map[string]map[string]interface{}) {
+		t.Helper()
+		resp, err := ms.DoCommand(ctx, map[string]interface{}{DoCheckReadiness: req})
+		test.That(t, err, test.ShouldBeNil)
+		report, ok := resp[DoCheckReadiness].(map[string]interface{})
+		test.That(t, ok, test.ShouldBeTrue)
+		checks := map[string]map[string]interface{}{}
+		for _, check := range report["checks"].([]interface{}) {
+			check := check.(map[string]interface{})
+			checks[check["dependency"].(string)] = check
+		}
+		return report["ready"].(bool), checks
+	}
+	detectors := []interface{}{map[string]interface{}{"vision_service": "injectedVisionSvc", "camera": "injectedCamera"}}
+
+	t.Run("a correctly wired request is ready", func(t *testing.T) {
+		injectedVis.GetObjectPointCloudsFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]*viz.Object, error) {
+			return nil, nil
+		}
+		ready, checks := checkReadiness(t, map[string]interface{}{
+			"component_name":       "test-base",
+			"movement_sensor_name": moveSensorName,
+			"obstacle_detectors":   detectors,
+		})
+		test.That(t, ready, test.ShouldBeTrue)
+		test.That(t, len(checks), test.ShouldEqual, 4)
+		for _, check := range checks {
+			test.That(t, check["ready"], test.ShouldBeTrue)
+		}
+	})
+
+	t.Run("each misconfigured dependency is reported", func(t *testing.T) {
+		injectedVis.GetObjectPointCloudsFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]*viz.Object, error) {
+			return nil, errors.New("camera disconnected")
+		}
+		ready, checks := checkReadiness(t, map[string]interface{}{
+			"component_name":       "missing-base",
+			"movement_sensor_name": "missing-gps",
+			"obstacle_detectors":   detectors,
+		})
+		test.That(t, ready, test.ShouldBeFalse)
+		test.That(t, checks["component missing-base"]["error"], test.ShouldContainSubstring, "not a dependency")
+		test.That(t, checks["frame system"]["error"], test.ShouldContainSubstring, "not found in robot frame system")
+		test.That(t, checks["localizer missing-gps"]["ready"], test.ShouldBeFalse)
+		test.That(t, checks["obstacle detector injectedVisionSvc/injectedCamera"]["error"], test.ShouldEqual, "camera disconnected")
+	})
+
+	t.Run("slow obstacle detectors are not ready", func(t *testing.T) {
+		injectedVis.GetObjectPointCloudsFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]*viz.Object, error) {
+			time.Sleep(20 * time.Millisecond)
+			return nil, nil
+		}
+		ready, checks := checkReadiness(t, map[string]interface{}{
+			"component_name":     "test-base",
+			"obstacle_detectors": detectors,
+			"max_latency_ms":     5.,
+		})
+		test.That(t, ready, test.ShouldBeFalse)
+		check := checks["obstacle detector injectedVisionSvc/injectedCamera"]
+		test.That(t, check["error"], test.ShouldContainSubstring, "latency bound")
+		test.That(t, check["latency_ms"], test.ShouldBeGreaterThanOrEqualTo, 20)
+	})
+
+	t.Run("a component name is required", func(t *testing.T) {
+		_, err := ms.DoCommand(ctx, map[string]interface{}{DoCheckReadiness: map[string]interface{}{}})
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
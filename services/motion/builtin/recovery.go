@@ -0,0 +1,243 @@
+package builtin
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/resource"
+)
+
+const (
+	// recoveryBehaviorsExtraKey is the key of extra through which MoveOnGlobe and MoveOnMap are given the ordered list of recovery
+	// behaviors to perform when planning or replanning fails, including when the maximum number of replans is exceeded, before
+	// finally failing the execution. After each behavior is performed, planning is tried again with the full allowance of replans.
+	// Each entry is either the type of a behavior or a map holding its type and parameters, such as
+	// {"type": "back_up", "distance_mm": 500}.
+	recoveryBehaviorsExtraKey = "recovery_behaviors"
+
+	// recoveryClearObstacleMemory forgets the transient detections remembered by the execution.
+	recoveryClearObstacleMemory = "clear_obstacle_memory"
+	// recoveryRotate spins the base in place by "degrees", so that its obstacle detectors rescan its surroundings.
+	recoveryRotate = "rotate"
+	// recoveryBackUp drives the base straight backwards by "distance_mm".
+	recoveryBackUp = "back_up"
+	// recoveryRelaxConstraints drops the collision padding, interaction spaces, path network corridors and level payloads of the
+	// request for the remainder of the execution, and no longer requires the base to reach the orientation of its goal.
+	recoveryRelaxConstraints = "relax_constraints"
+
+	defaultRecoveryRotateDegs   = 360.
+	defaultRecoveryBackUpMM     = 300.
+	recoveryRotateDegsParameter = "degrees"
+	recoveryBackUpMMParameter   = "distance_mm"
+)
+
+// recoveryBehavior is a single recovery behavior, with amount the degrees or mm it rotates or backs the base up by.
+type recoveryBehavior struct {
+	kind   string
+	amount float64
+}
+
+// parseRecoveryBehaviors parses the recovery behaviors of a request from extra, in the order they are to be performed.
+func parseRecoveryBehaviors(extra map[string]interface{}) ([]recoveryBehavior, error) {
+	raw, ok := extra[recoveryBehaviorsExtraKey]
+	if !ok {
+		return nil, nil
+	}
+	entries, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("could not interpret %s field as a list", recoveryBehaviorsExtraKey)
+	}
+	behaviors := make([]recoveryBehavior, 0, len(entries))
+	for i, entry := range entries {
+		var kind string
+		var params map[string]interface{}
+		switch e := entry.(type) {
+		case string:
+			kind = e
+		case map[string]interface{}:
+			if kind, ok = e["type"].(string); !ok {
+				return nil, fmt.Errorf("%s entry %d is missing its type", recoveryBehaviorsExtraKey, i)
+			}
+			params = e
+		default:
+			return nil, fmt.Errorf("could not interpret %s entry %d as a string or map", recoveryBehaviorsExtraKey, i)
+		}
+
+		behavior := recoveryBehavior{kind: kind}
+		var parameter string
+		switch kind {
+		case recoveryClearObstacleMemory, recoveryRelaxConstraints:
+		case recoveryRotate:
+			behavior.amount, parameter = defaultRecoveryRotateDegs, recoveryRotateDegsParameter
+		case recoveryBackUp:
+			behavior.amount, parameter = defaultRecoveryBackUpMM, recoveryBackUpMMParameter
+		default:
+			return nil, fmt.Errorf("%s entry %d has unknown type %q", recoveryBehaviorsExtraKey, i, kind)
+		}
+		if amountRaw, ok := params[parameter]; ok && parameter != "" {
+			amount, ok := amountRaw.(float64)
+			if !ok {
+				return nil, fmt.Errorf("could not interpret %s of %s entry %d as float", parameter, recoveryBehaviorsExtraKey, i)
+			}
+			if amount <= 0 {
+				return nil, fmt.Errorf("%s of %s entry %d must be positive", parameter, recoveryBehaviorsExtraKey, i)
+			}
+			behavior.amount = amount
+		}
+		behaviors = append(behaviors, behavior)
+	}
+	return behaviors, nil
+}
+
+// recovery tracks the recovery behaviors an execution has performed across its replans. A nil recovery performs none.
+type recovery struct {
+	behaviors []recoveryBehavior
+
+	mu sync.Mutex
+	// next is the index of the next behavior to perform
+	next int
+	// last is the number of replans counted when the execution was last planned, and forgiven is how many of them recoveries have
+	// forgiven
+	last     int
+	forgiven int
+	relaxed  bool
+	// retryingFirstPlan is set when a behavior is performed because the first plan of the execution failed, so that the plan tried
+	// again afterwards continues the recovery rather than starting a new one
+	retryingFirstPlan bool
+}
+
+// recovery returns the recovery of an execution on the named component, starting a new one when the execution is first planned.
+// Returns nil if the request has no recovery behaviors.
+func (ms *builtIn) recovery(componentName resource.Name, behaviors []recoveryBehavior, replanCount int) *recovery {
+	ms.recoveryMu.Lock()
+	defer ms.recoveryMu.Unlock()
+	if len(behaviors) == 0 {
+		delete(ms.recoveries, componentName)
+		return nil
+	}
+	if ms.recoveries == nil {
+		ms.recoveries = map[resource.Name]*recovery{}
+	}
+	r, ok := ms.recoveries[componentName]
+	if ok && replanCount == 0 {
+		r.mu.Lock()
+		ok = r.retryingFirstPlan
+		r.retryingFirstPlan = false
+		r.mu.Unlock()
+	}
+	if !ok {
+		r = &recovery{}
+		ms.recoveries[componentName] = r
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.behaviors = behaviors
+	return r
+}
+
+// forgetRecovery forgets the recovery of an execution which has ended, unless a newer execution on the same component has already
+// replaced it.
+func (ms *builtIn) forgetRecovery(r *recovery) {
+	ms.recoveryMu.Lock()
+	defer ms.recoveryMu.Unlock()
+	for componentName, tracked := range ms.recoveries {
+		if tracked == r {
+			delete(ms.recoveries, componentName)
+		}
+	}
+}
+
+// useRecovery has the request perform the behaviors of the recovery, which is forgotten once the execution of the request ends.
+func (ms *builtIn) useRecovery(mr *moveRequest, r *recovery) {
+	mr.recovery = r
+	finish := mr.finish
+	mr.finish = func() {
+		if finish != nil {
+			finish()
+		}
+		ms.forgetRecovery(r)
+	}
+}
+
+// replans returns how many of the given replans of the execution count towards its maximum, which excludes those made before its
+// most recent recovery.
+func (r *recovery) replans(replanCount int) int {
+	if r == nil {
+		return replanCount
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.last = replanCount
+	return replanCount - r.forgiven
+}
+
+// constraintsRelaxed returns whether the execution has relaxed the constraints of its request.
+func (r *recovery) constraintsRelaxed() bool {
+	if r == nil {
+		return false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.relaxed
+}
+
+// take returns the next behavior to perform, forgiving the replans made so far so that the replan which follows it is the first to
+// count towards the maximum. Returns false once every behavior has been performed.
+func (r *recovery) take() (recoveryBehavior, bool) {
+	if r == nil {
+		return recoveryBehavior{}, false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.next >= len(r.behaviors) {
+		return recoveryBehavior{}, false
+	}
+	behavior := r.behaviors[r.next]
+	r.next++
+	if r.last > 0 {
+		r.forgiven = r.last - 1
+	} else {
+		r.retryingFirstPlan = true
+	}
+	if behavior.kind == recoveryRelaxConstraints {
+		r.relaxed = true
+	}
+	return behavior, true
+}
+
+// relaxConstraints drops the constraints of the request which a recovery relaxes.
+func (ve *validatedExtra) relaxConstraints() {
+	ve.collisionPadding = nil
+	ve.interactionSpaces = nil
+	ve.levelPayload = levelPayload{}
+	ve.motionProfile = motionplan.PositionOnlyMotionProfile
+}
+
+// Recover performs the next recovery behavior of the request after planning or replanning failed with the given cause, implementing
+// state.Recoverer. A behavior which fails is logged, and planning is tried again regardless.
+func (mr *moveRequest) Recover(ctx context.Context, cause error) bool {
+	behavior, ok := mr.recovery.take()
+	if !ok {
+		return false
+	}
+	mr.logger.CInfof(ctx, "planning failed, performing recovery behavior %s: %v", behavior.kind, cause)
+	opts := kbOptionsFromCfg(mr.config, validatedExtra{})
+	var err error
+	switch behavior.kind {
+	case recoveryClearObstacleMemory:
+		mr.memory.forget()
+	case recoveryRotate:
+		err = mr.kinematicBase.Spin(ctx, behavior.amount, opts.AngularVelocityDegsPerSec, nil)
+	case recoveryBackUp:
+		err = mr.kinematicBase.MoveStraight(ctx, -int(behavior.amount), opts.LinearVelocityMMPerSec, nil)
+	}
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		mr.logger.CWarnf(ctx, "recovery behavior %s failed: %v", behavior.kind, err)
+	}
+	return true
+}
@@ -0,0 +1,118 @@
+package builtin
+
+import (
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/spatialmath"
+)
+
+func TestRecovery(t *testing.T) {
+	t.Run("parsed from extra", func(t *testing.T) {
+		valExtra, err := newValidatedExtra(map[string]interface{}{})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, valExtra.recovery, test.ShouldBeEmpty)
+
+		valExtra, err = newValidatedExtra(map[string]interface{}{recoveryBehaviorsExtraKey: []interface{}{
+			recoveryClearObstacleMemory,
+			map[string]interface{}{"type": recoveryRotate},
+			map[string]interface{}{"type": recoveryBackUp, recoveryBackUpMMParameter: 500.},
+			recoveryRelaxConstraints,
+		}})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, valExtra.recovery, test.ShouldResemble, []recoveryBehavior{
+			{kind: recoveryClearObstacleMemory},
+			{kind: recoveryRotate, amount: defaultRecoveryRotateDegs},
+			{kind: recoveryBackUp, amount: 500},
+			{kind: recoveryRelaxConstraints},
+		})
+
+		for _, behaviors := range []interface{}{
+			recoveryBackUp,
+			[]interface{}{"dance"},
+			[]interface{}{3.},
+			[]interface{}{map[string]interface{}{recoveryBackUpMMParameter: 500.}},
+			[]interface{}{map[string]interface{}{"type": recoveryBackUp, recoveryBackUpMMParameter: -500.}},
+			[]interface{}{map[string]interface{}{"type": recoveryRotate, recoveryRotateDegsParameter: "once"}},
+		} {
+			_, err = newValidatedExtra(map[string]interface{}{recoveryBehaviorsExtraKey: behaviors})
+			test.That(t, err, test.ShouldNotBeNil)
+		}
+	})
+
+	t.Run("a nil recovery performs nothing and forgives no replans", func(t *testing.T) {
+		var r *recovery
+		_, ok := r.take()
+		test.That(t, ok, test.ShouldBeFalse)
+		test.That(t, r.replans(3), test.ShouldEqual, 3)
+		test.That(t, r.constraintsRelaxed(), test.ShouldBeFalse)
+	})
+
+	behaviors := []recoveryBehavior{{kind: recoveryBackUp, amount: 300}, {kind: recoveryRelaxConstraints}}
+	t.Run("each behavior is performed once and restores the allowance of replans", func(t *testing.T) {
+		ms := &builtIn{}
+		name := base.Named("base")
+		r := ms.recovery(name, behaviors, 0)
+		test.That(t, r.replans(0), test.ShouldEqual, 0)
+
+		// the maximum number of replans is exceeded
+		test.That(t, ms.recovery(name, behaviors, 4), test.ShouldEqual, r)
+		test.That(t, r.replans(4), test.ShouldEqual, 4)
+		behavior, ok := r.take()
+		test.That(t, ok, test.ShouldBeTrue)
+		test.That(t, behavior, test.ShouldResemble, behaviors[0])
+		test.That(t, r.constraintsRelaxed(), test.ShouldBeFalse)
+
+		// the replan tried again after the recovery is the first to count
+		test.That(t, r.replans(4), test.ShouldEqual, 1)
+		test.That(t, r.replans(5), test.ShouldEqual, 2)
+		behavior, ok = r.take()
+		test.That(t, ok, test.ShouldBeTrue)
+		test.That(t, behavior, test.ShouldResemble, behaviors[1])
+		test.That(t, r.constraintsRelaxed(), test.ShouldBeTrue)
+		test.That(t, r.replans(5), test.ShouldEqual, 1)
+
+		_, ok = r.take()
+		test.That(t, ok, test.ShouldBeFalse)
+
+		// a new execution starts over
+		fresh := ms.recovery(name, behaviors, 0)
+		test.That(t, fresh, test.ShouldNotEqual, r)
+		test.That(t, fresh.constraintsRelaxed(), test.ShouldBeFalse)
+		test.That(t, ms.recovery(name, nil, 0), test.ShouldBeNil)
+	})
+
+	t.Run("a recovery performed when the first plan fails is continued when it is tried again", func(t *testing.T) {
+		ms := &builtIn{}
+		name := base.Named("base")
+		r := ms.recovery(name, behaviors, 0)
+		test.That(t, r.replans(0), test.ShouldEqual, 0)
+		_, ok := r.take()
+		test.That(t, ok, test.ShouldBeTrue)
+		test.That(t, ms.recovery(name, behaviors, 0), test.ShouldEqual, r)
+		test.That(t, r.replans(1), test.ShouldEqual, 1)
+
+		// once the execution ends its recovery is forgotten
+		ms.forgetRecovery(r)
+		test.That(t, ms.recovery(name, behaviors, 0), test.ShouldNotEqual, r)
+	})
+
+	t.Run("relaxed constraints", func(t *testing.T) {
+		valExtra, err := newValidatedExtra(map[string]interface{}{
+			collisionPaddingExtraKey: map[string]interface{}{"rover": 150.},
+			"motion_profile":         motionplan.FreeMotionProfile,
+		})
+		test.That(t, err, test.ShouldBeNil)
+		valExtra.interactionSpaces = []spatialmath.Geometry{spatialmath.NewPoint(r3.Vector{}, "")}
+		valExtra.levelPayload = levelPayload{toleranceDegs: 5}
+		valExtra.relaxConstraints()
+		test.That(t, valExtra.collisionPadding, test.ShouldBeEmpty)
+		test.That(t, valExtra.interactionSpaces, test.ShouldBeEmpty)
+		test.That(t, valExtra.levelPayload.enabled(), test.ShouldBeFalse)
+		test.That(t, valExtra.motionProfile, test.ShouldEqual, motionplan.PositionOnlyMotionProfile)
+	})
+}
@@ -0,0 +1,77 @@
+package builtin
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/services/slam"
+)
+
+// relocalizationTimeoutExtraKey is the key of extra setting how many seconds MoveOnMap waits for a lost SLAM service to relocalize
+// before failing, in place of defaultRelocalizationTimeout.
+const relocalizationTimeoutExtraKey = "relocalization_timeout_s"
+
+const (
+	// defaultRelocalizationTimeout is how long MoveOnMap waits for a lost SLAM service to relocalize before failing.
+	defaultRelocalizationTimeout = 30 * time.Second
+	// relocalizationPollInterval is how often the localization quality of a lost SLAM service is checked.
+	relocalizationPollInterval = 500 * time.Millisecond
+)
+
+// parseRelocalizationTimeout parses how long to wait for SLAM to relocalize from extra.
+func parseRelocalizationTimeout(extra map[string]interface{}) (time.Duration, error) {
+	raw, ok := extra[relocalizationTimeoutExtraKey]
+	if !ok {
+		return defaultRelocalizationTimeout, nil
+	}
+	seconds, ok := raw.(float64)
+	if !ok {
+		return 0, fmt.Errorf("could not interpret %s field as float", relocalizationTimeoutExtraKey)
+	}
+	if seconds < 0 {
+		return 0, fmt.Errorf("%s may not be negative", relocalizationTimeoutExtraKey)
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// slamLost returns a description of the localization of the SLAM service if it has lost track of its position. SLAM services which
+// cannot report their localization quality are assumed to be tracking.
+func slamLost(ctx context.Context, slamSvc slam.Service, logger logging.Logger) string {
+	quality, err := slamSvc.LocalizationQuality(ctx)
+	if err != nil {
+		logger.CDebugf(ctx, "could not get the localization quality of SLAM, assuming it is tracking: %v", err)
+		return ""
+	}
+	if !quality.Lost() {
+		return ""
+	}
+	return fmt.Sprintf("SLAM service %s is %s", slamSvc.Name().ShortName(), quality.State)
+}
+
+// waitForRelocalization pauses until the SLAM service is no longer lost, returning an error if it does not relocalize within the
+// timeout, so that the base is not driven blind.
+func waitForRelocalization(ctx context.Context, slamSvc slam.Service, timeout time.Duration, logger logging.Logger) error {
+	reason := slamLost(ctx, slamSvc, logger)
+	if reason == "" {
+		return nil
+	}
+	logger.CInfof(ctx, "%s, waiting up to %v for it to relocalize", reason, timeout)
+	deadline := time.Now().Add(timeout)
+	ticker := time.NewTicker(relocalizationPollInterval)
+	defer ticker.Stop()
+	for {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%s and did not relocalize within %v", reason, timeout)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		if reason = slamLost(ctx, slamSvc, logger); reason == "" {
+			return nil
+		}
+	}
+}
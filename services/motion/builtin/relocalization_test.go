@@ -0,0 +1,58 @@
+package builtin
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/services/slam"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestWaitForRelocalization(t *testing.T) {
+	t.Run("parsed from extra", func(t *testing.T) {
+		valExtra, err := newValidatedExtra(map[string]interface{}{})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, valExtra.relocalizationTimeout, test.ShouldEqual, defaultRelocalizationTimeout)
+		valExtra, err = newValidatedExtra(map[string]interface{}{relocalizationTimeoutExtraKey: 2.5})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, valExtra.relocalizationTimeout, test.ShouldEqual, 2500*time.Millisecond)
+		_, err = newValidatedExtra(map[string]interface{}{relocalizationTimeoutExtraKey: "soon"})
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = newValidatedExtra(map[string]interface{}{relocalizationTimeoutExtraKey: -1.})
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	slamSvc := inject.NewSLAMService("slam")
+	calls := 0
+	slamSvc.LocalizationQualityFunc = func(ctx context.Context) (slam.LocalizationQuality, error) {
+		calls++
+		if calls < 3 {
+			return slam.LocalizationQuality{State: slam.LocalizationStateLost}, nil
+		}
+		return slam.LocalizationQuality{State: slam.LocalizationStateTracking, Confidence: 0.8}, nil
+	}
+	test.That(t, slamLost(ctx, slamSvc, logger), test.ShouldContainSubstring, "lost")
+	test.That(t, waitForRelocalization(ctx, slamSvc, time.Minute, logger), test.ShouldBeNil)
+	test.That(t, calls, test.ShouldEqual, 3)
+	test.That(t, slamLost(ctx, slamSvc, logger), test.ShouldBeEmpty)
+
+	slamSvc.LocalizationQualityFunc = func(ctx context.Context) (slam.LocalizationQuality, error) {
+		return slam.LocalizationQuality{State: slam.LocalizationStateRelocalizing}, nil
+	}
+	err := waitForRelocalization(ctx, slamSvc, relocalizationPollInterval, logger)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "did not relocalize")
+
+	// slam services which cannot report their localization quality do not pause execution
+	slamSvc.LocalizationQualityFunc = func(ctx context.Context) (slam.LocalizationQuality, error) {
+		return slam.LocalizationQuality{}, errors.New("unimplemented")
+	}
+	test.That(t, waitForRelocalization(ctx, slamSvc, 0, logger), test.ShouldBeNil)
+}
@@ -0,0 +1,70 @@
+package builtin
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	// replanMinIntervalExtraKey is the key of extra through which MoveOnGlobe and MoveOnMap are given the number of seconds for which
+	// a plan is executed before the replanners polling it for deviations and obstacles may replan it.
+	replanMinIntervalExtraKey = "replan_min_interval_s"
+	// replanConsecutivePollsExtraKey is the key of extra through which the number of consecutive polls of a replanner which must call
+	// for a replan before it replans is given, so that a single noisy detection does not replan the execution.
+	replanConsecutivePollsExtraKey = "replan_consecutive_polls"
+)
+
+// replanDebounce describes how long and how persistently the replanners of an execution must call for a replan before they replan.
+type replanDebounce struct {
+	minInterval      time.Duration
+	consecutivePolls int
+}
+
+// parseReplanDebounce parses the replan debounce from extra, returning a zero debounce, under which every poll calling for a replan
+// replans, if it is not set.
+func parseReplanDebounce(extra map[string]interface{}) (replanDebounce, error) {
+	debounce := replanDebounce{}
+	if raw, ok := extra[replanMinIntervalExtraKey]; ok {
+		seconds, ok := raw.(float64)
+		if !ok {
+			return replanDebounce{}, fmt.Errorf("could not interpret %s field as float", replanMinIntervalExtraKey)
+		}
+		if seconds < 0 {
+			return replanDebounce{}, fmt.Errorf("%s may not be negative", replanMinIntervalExtraKey)
+		}
+		debounce.minInterval = time.Duration(seconds * float64(time.Second))
+	}
+	if raw, ok := extra[replanConsecutivePollsExtraKey]; ok {
+		polls, ok := raw.(float64)
+		if !ok || polls != math.Trunc(polls) {
+			return replanDebounce{}, fmt.Errorf("could not interpret %s field as an integer", replanConsecutivePollsExtraKey)
+		}
+		if polls < 1 {
+			return replanDebounce{}, fmt.Errorf("%s must be at least 1", replanConsecutivePollsExtraKey)
+		}
+		debounce.consecutivePolls = int(polls)
+	}
+	return debounce, nil
+}
+
+// debouncer counts the polls of a replanner calling for a replan, deciding when they have done so for long enough to replan.
+type debouncer struct {
+	replanDebounce
+	start      time.Time
+	violations int
+}
+
+func (d replanDebounce) start(now time.Time) *debouncer {
+	return &debouncer{replanDebounce: d, start: now}
+}
+
+// poll records whether a poll at the given time called for a replan, returning whether the execution should be replanned.
+func (d *debouncer) poll(now time.Time, replan bool) bool {
+	if !replan {
+		d.violations = 0
+		return false
+	}
+	d.violations++
+	return d.violations >= d.consecutivePolls && now.Sub(d.start) >= d.minInterval
+}
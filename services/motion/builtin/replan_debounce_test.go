@@ -0,0 +1,80 @@
+package builtin
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/services/motion/builtin/state"
+)
+
+func TestReplanDebounce(t *testing.T) {
+	t.Run("parsed from extra", func(t *testing.T) {
+		valExtra, err := newValidatedExtra(map[string]interface{}{replanMinIntervalExtraKey: 1.5, replanConsecutivePollsExtraKey: 3.})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, valExtra.replanDebounce, test.ShouldResemble, replanDebounce{minInterval: 1500 * time.Millisecond, consecutivePolls: 3})
+
+		for _, bad := range []map[string]interface{}{
+			{replanMinIntervalExtraKey: -1.},
+			{replanMinIntervalExtraKey: "1s"},
+			{replanConsecutivePollsExtraKey: 0.},
+			{replanConsecutivePollsExtraKey: 2.5},
+			{replanConsecutivePollsExtraKey: "3"},
+		} {
+			_, err := newValidatedExtra(bad)
+			test.That(t, err, test.ShouldNotBeNil)
+		}
+	})
+
+	t.Run("every poll calling for a replan replans without a debounce", func(t *testing.T) {
+		start := time.Now()
+		d := replanDebounce{}.start(start)
+		test.That(t, d.poll(start, false), test.ShouldBeFalse)
+		test.That(t, d.poll(start, true), test.ShouldBeTrue)
+	})
+
+	t.Run("replans require consecutive polls calling for them", func(t *testing.T) {
+		start := time.Now()
+		d := replanDebounce{consecutivePolls: 3}.start(start)
+		test.That(t, d.poll(start, true), test.ShouldBeFalse)
+		test.That(t, d.poll(start, true), test.ShouldBeFalse)
+		// a poll which does not call for a replan starts the count over
+		test.That(t, d.poll(start, false), test.ShouldBeFalse)
+		test.That(t, d.poll(start, true), test.ShouldBeFalse)
+		test.That(t, d.poll(start, true), test.ShouldBeFalse)
+		test.That(t, d.poll(start, true), test.ShouldBeTrue)
+	})
+
+	t.Run("replans wait for the minimum interval", func(t *testing.T) {
+		start := time.Now()
+		d := replanDebounce{minInterval: time.Second}.start(start)
+		test.That(t, d.poll(start.Add(500*time.Millisecond), true), test.ShouldBeFalse)
+		test.That(t, d.poll(start.Add(time.Second), true), test.ShouldBeTrue)
+	})
+
+	t.Run("replanners debounce noisy polls but return errors immediately", func(t *testing.T) {
+		debounce := replanDebounce{consecutivePolls: 3}
+		polls := 0
+		r := newReplanner(time.Millisecond, debounce, nil, func(context.Context, motionplan.Plan) (state.ExecuteResponse, error) {
+			polls++
+			// every other poll calls for a replan, as a flickering detection would
+			return state.ExecuteResponse{Replan: polls%2 == 0 || polls > 10}, nil
+		})
+		r.startPolling(context.Background(), nil)
+		resp := <-r.responseChan
+		test.That(t, resp.executeResponse.Replan, test.ShouldBeTrue)
+		// polls 10 through 12 are the first three in a row to call for a replan
+		test.That(t, polls, test.ShouldEqual, 12)
+
+		r = newReplanner(time.Millisecond, debounce, nil, func(context.Context, motionplan.Plan) (state.ExecuteResponse, error) {
+			return state.ExecuteResponse{}, errors.New("cannot read sensor")
+		})
+		r.startPolling(context.Background(), nil)
+		resp = <-r.responseChan
+		test.That(t, resp.err, test.ShouldNotBeNil)
+	})
+}
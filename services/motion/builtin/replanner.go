@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/benbjohnson/clock"
+
 	"go.viam.com/rdk/motionplan"
 	"go.viam.com/rdk/services/motion/builtin/state"
 )
@@ -25,16 +27,23 @@ func (rr replanResponse) String() string {
 // replanner bundles everything needed to execute a function at a given interval and return.
 type replanner struct {
 	period       time.Duration
+	debounce     replanDebounce
+	clock        clock.Clock
 	responseChan chan replanResponse
 
 	// needReplan is a function that returns a bool describing if a replan is needed, as well as an error
 	needReplan replanFn
 }
 
-// newReplanner is a constructor for a replanner.
-func newReplanner(period time.Duration, fnToPoll replanFn) *replanner {
+// newReplanner is a constructor for a replanner which polls by the given clock, or by the wall clock if it is nil.
+func newReplanner(period time.Duration, debounce replanDebounce, clk clock.Clock, fnToPoll replanFn) *replanner {
+	if clk == nil {
+		clk = clock.New()
+	}
 	return &replanner{
 		period:       period,
+		debounce:     debounce,
+		clock:        clk,
 		needReplan:   fnToPoll,
 		responseChan: make(chan replanResponse, 1),
 	}
@@ -42,9 +51,11 @@ func newReplanner(period time.Duration, fnToPoll replanFn) *replanner {
 
 // startPolling executes the replanner's configured function at its configured period
 // The caller of this function should read from the replanner's responseChan to know when a replan is requested.
+// Polls calling for a replan are debounced, while errors are returned immediately.
 func (r *replanner) startPolling(ctx context.Context, plan motionplan.Plan) {
-	ticker := time.NewTicker(r.period)
+	ticker := r.clock.Ticker(r.period)
 	defer ticker.Stop()
+	debouncer := r.debounce.start(r.clock.Now())
 
 	// this check ensures that if the context is cancelled we always return early at the top of the loop
 	for ctx.Err() == nil {
@@ -53,7 +64,7 @@ func (r *replanner) startPolling(ctx context.Context, plan motionplan.Plan) {
 			return
 		case <-ticker.C:
 			executeResp, err := r.needReplan(ctx, plan)
-			if err != nil || executeResp.Replan {
+			if err != nil || debouncer.poll(r.clock.Now(), executeResp.Replan) {
 				res := replanResponse{executeResponse: executeResp, err: err}
 				r.responseChan <- res
 				return
@@ -0,0 +1,295 @@
+package builtin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	geo "github.com/kellydunn/golang-geo"
+
+	"go.viam.com/rdk/components/base/kinematicbase"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// SafetyZoneConfig describes a region of the frame system which the robot moves through slowly, or not at all, such as an area
+// where people work alongside it. Whenever any geometry of a moving component intersects the zone, the end effector speed of an arm
+// moved by Move is capped, as is the speed of a base moved by MoveOnMap or MoveOnGlobe, in the manner of speed and separation
+// monitoring. Bases slow down before they enter a zone, so that they never drive faster within it than it allows. A zone which stops
+// the robot fails the move before the robot enters it, or, for bases, once they are found within it.
+type SafetyZoneConfig struct {
+	Name string `json:"name"`
+	// Frame is the frame the geometry of the zone is in, which is the world frame if unset. Zones in frames which move are placed
+	// anew as they move while bases drive.
+	Frame string `json:"frame,omitempty"`
+	// Latitude and Longitude, if set, fix the zone to that point on the globe, with the X and Y axes of its geometry pointing east and
+	// north. Zones fixed to the globe are only applied by MoveOnGlobe, and zones which are not are only applied by Move and MoveOnMap,
+	// since the frame system is not fixed to the globe.
+	Latitude         *float64                    `json:"latitude,omitempty"`
+	Longitude        *float64                    `json:"longitude,omitempty"`
+	Geometry         *spatialmath.GeometryConfig `json:"geometry"`
+	MaxSpeedMMPerSec float64                     `json:"max_speed_mm_per_sec,omitempty"`
+	Stop             bool                        `json:"stop,omitempty"`
+}
+
+// validate ensures the zone has a geometry and either caps speed or stops the robot.
+func (cfg *SafetyZoneConfig) validate(path string) error {
+	if cfg.Geometry == nil {
+		return resource.NewConfigValidationFieldRequiredError(path, "geometry")
+	}
+	if _, err := cfg.Geometry.ParseConfig(); err != nil {
+		return resource.NewConfigValidationError(path, err)
+	}
+	if math.IsNaN(cfg.MaxSpeedMMPerSec) || cfg.MaxSpeedMMPerSec < 0 {
+		return resource.NewConfigValidationError(path, errors.New("max_speed_mm_per_sec cannot be negative"))
+	}
+	if cfg.MaxSpeedMMPerSec == 0 && !cfg.Stop {
+		return resource.NewConfigValidationError(path, errors.New("safety zone must either cap max_speed_mm_per_sec or stop"))
+	}
+	if (cfg.Latitude == nil) != (cfg.Longitude == nil) {
+		return resource.NewConfigValidationError(path, errors.New("must provide both latitude and longitude"))
+	}
+	if cfg.Latitude != nil && cfg.Frame != "" {
+		return resource.NewConfigValidationError(path, errors.New("a safety zone fixed to the globe cannot be in a frame"))
+	}
+	return nil
+}
+
+// safetyZone is a configured safety zone, with its geometry in the frame it was configured in, or about its location if it is fixed
+// to the globe.
+type safetyZone struct {
+	name             string
+	geometry         *referenceframe.GeometriesInFrame
+	location         *geo.Point
+	maxSpeedMMPerSec float64
+	stop             bool
+}
+
+// newSafetyZones parses the geometries of the configured safety zones, returning those in the frame system and those fixed to the
+// globe.
+func newSafetyZones(configs []SafetyZoneConfig) ([]safetyZone, []safetyZone, error) {
+	var zones, geoZones []safetyZone
+	for i, cfg := range configs {
+		geometry, err := cfg.Geometry.ParseConfig()
+		if err != nil {
+			return nil, nil, err
+		}
+		frame := cfg.Frame
+		if frame == "" {
+			frame = referenceframe.World
+		}
+		name := cfg.Name
+		if name == "" {
+			name = fmt.Sprintf("%d", i)
+		}
+		zone := safetyZone{
+			name:             name,
+			geometry:         referenceframe.NewGeometriesInFrame(frame, []spatialmath.Geometry{geometry}),
+			maxSpeedMMPerSec: cfg.MaxSpeedMMPerSec,
+			stop:             cfg.Stop,
+		}
+		if cfg.Latitude != nil {
+			zone.location = geo.NewPoint(*cfg.Latitude, *cfg.Longitude)
+			geoZones = append(geoZones, zone)
+			continue
+		}
+		zones = append(zones, zone)
+	}
+	return zones, geoZones, nil
+}
+
+// safetyZonesInWorld returns the safety zones with their geometries in the world frame of the frame system at the given inputs.
+func safetyZonesInWorld(
+	fs referenceframe.FrameSystem,
+	inputs referenceframe.FrameSystemInputs,
+	zones []safetyZone,
+) ([]kinematicbase.SafetyZone, error) {
+	worldZones := make([]kinematicbase.SafetyZone, 0, len(zones))
+	for _, zone := range zones {
+		// zones are placed relative to the pose of their frame at the inputs, which transforming them as the geometries of the frame
+		// would not do
+		framePose, err := framePoseInWorld(fs, inputs, zone.geometry.Parent())
+		if err != nil {
+			return nil, fmt.Errorf("cannot place safety zone %s in the world frame: %w", zone.name, err)
+		}
+		for _, geometry := range zone.geometry.Geometries() {
+			worldZones = append(worldZones, kinematicbase.SafetyZone{
+				Name:             zone.name,
+				Geometry:         geometry.Transform(framePose),
+				MaxSpeedMMPerSec: zone.maxSpeedMMPerSec,
+				Stop:             zone.stop,
+			})
+		}
+	}
+	return worldZones, nil
+}
+
+// zoneSpeedLimit returns the speed which the given limit is capped to by the safety zones which the geometries of the named frames
+// intersect at the given inputs, where a limit of zero is no limit. An error is returned if they intersect a zone which stops them.
+func zoneSpeedLimit(
+	fs referenceframe.FrameSystem,
+	inputs referenceframe.FrameSystemInputs,
+	frameNames []string,
+	zones []safetyZone,
+	maxSpeedMMPerSec float64,
+) (float64, error) {
+	if len(zones) == 0 {
+		return maxSpeedMMPerSec, nil
+	}
+	worldZones, err := safetyZonesInWorld(fs, inputs, zones)
+	if err != nil {
+		return 0, err
+	}
+	for _, name := range frameNames {
+		frame := fs.Frame(name)
+		if frame == nil {
+			continue
+		}
+		frameInputs, err := inputs.GetFrameInputs(frame)
+		if err != nil {
+			return 0, err
+		}
+		geometries, err := frame.Geometries(frameInputs)
+		if err != nil {
+			return 0, err
+		}
+		if len(geometries.Geometries()) == 0 {
+			continue
+		}
+		tf, err := fs.Transform(inputs, geometries, referenceframe.World)
+		if err != nil {
+			return 0, err
+		}
+		gif, ok := tf.(*referenceframe.GeometriesInFrame)
+		if !ok {
+			return 0, fmt.Errorf("could not transform the geometries of frame %s into the world frame", name)
+		}
+		for _, zone := range worldZones {
+			for _, geometry := range gif.Geometries() {
+				collides, err := geometry.CollidesWith(zone.Geometry, 0)
+				if err != nil {
+					return 0, err
+				}
+				if !collides {
+					continue
+				}
+				if zone.Stop {
+					return 0, &kinematicbase.SafetyZoneError{Zone: zone.Name}
+				}
+				if maxSpeedMMPerSec <= 0 || zone.MaxSpeedMMPerSec < maxSpeedMMPerSec {
+					maxSpeedMMPerSec = zone.MaxSpeedMMPerSec
+				}
+				break
+			}
+		}
+	}
+	return maxSpeedMMPerSec, nil
+}
+
+// movingFrames returns the names of the frames of the frame system which are moved by the trajectory, being those with inputs which
+// the trajectory gives and every frame attached to them. Only the geometries of these frames are checked against safety zones, so that
+// fixtures which sit within a zone do not slow the robot.
+func movingFrames(fs referenceframe.FrameSystem, trajectory []referenceframe.FrameSystemInputs) ([]string, error) {
+	moved := map[string]bool{}
+	for _, step := range trajectory {
+		for name, inputs := range step {
+			if len(inputs) > 0 {
+				moved[name] = true
+			}
+		}
+	}
+	var names []string
+	for _, name := range fs.FrameNames() {
+		frame := fs.Frame(name)
+		chain, err := fs.TracebackFrame(frame)
+		if err != nil {
+			return nil, err
+		}
+		for _, link := range chain {
+			if moved[link.Name()] {
+				names = append(names, name)
+				break
+			}
+		}
+	}
+	return names, nil
+}
+
+// safetyZoneSource returns the source of the safety zones in the world frame of the frame system, for a base localized in that frame
+// to drive through. Zones in frames other than the world frame are placed anew at the current inputs of the frame system whenever
+// the source is called, and are left where they were last placed if those inputs cannot be read. Returns nil if there are no zones.
+func safetyZoneSource(
+	ctx context.Context,
+	fs referenceframe.FrameSystem,
+	currentInputs func(context.Context) (referenceframe.FrameSystemInputs, error),
+	zones []safetyZone,
+	logger logging.Logger,
+) (kinematicbase.SafetyZoneSource, error) {
+	if len(zones) == 0 {
+		return nil, nil
+	}
+	inputs, err := currentInputs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	worldZones, err := safetyZonesInWorld(fs, inputs, zones)
+	if err != nil {
+		return nil, err
+	}
+	moving := false
+	for _, zone := range zones {
+		moving = moving || zone.geometry.Parent() != referenceframe.World
+	}
+	return func(ctx context.Context) ([]kinematicbase.SafetyZone, error) {
+		if !moving {
+			return worldZones, nil
+		}
+		inputs, err := currentInputs(ctx)
+		if err == nil {
+			var placed []kinematicbase.SafetyZone
+			if placed, err = safetyZonesInWorld(fs, inputs, zones); err == nil {
+				worldZones = placed
+			}
+		}
+		if err != nil {
+			logger.CDebugf(ctx, "could not place safety zones anew, leaving them where they were: %v", err)
+		}
+		return worldZones, nil
+	}, nil
+}
+
+// mapSafetyZoneSource returns the source of the safety zones for a base moved by MoveOnMap, which is localized in the frame of the
+// map, being the world frame of the frame system.
+func (ms *builtIn) mapSafetyZoneSource(ctx context.Context, fs referenceframe.FrameSystem) (kinematicbase.SafetyZoneSource, error) {
+	currentInputs := func(ctx context.Context) (referenceframe.FrameSystemInputs, error) {
+		inputs, _, err := ms.fsService.CurrentInputs(ctx)
+		return inputs, err
+	}
+	return safetyZoneSource(ctx, fs, currentInputs, ms.safetyZones, ms.logger)
+}
+
+// geoSafetyZoneSource returns the source of the safety zones fixed to the globe, for a base moved by MoveOnGlobe which is localized
+// relative to the given origin. Returns nil if there are no such zones.
+func geoSafetyZoneSource(zones []safetyZone, origin *geo.Point) kinematicbase.SafetyZoneSource {
+	if len(zones) == 0 {
+		return nil
+	}
+	placed := make([]kinematicbase.SafetyZone, 0, len(zones))
+	for _, zone := range zones {
+		geoGeometry := spatialmath.NewGeoGeometry(zone.location, zone.geometry.Geometries())
+		for _, geometry := range spatialmath.GeoGeometriesToGeometries([]*spatialmath.GeoGeometry{geoGeometry}, origin) {
+			placed = append(placed, kinematicbase.SafetyZone{
+				Name:             zone.name,
+				Geometry:         geometry,
+				MaxSpeedMMPerSec: zone.maxSpeedMMPerSec,
+				Stop:             zone.stop,
+			})
+		}
+	}
+	return func(context.Context) ([]kinematicbase.SafetyZone, error) {
+		return placed, nil
+	}
+}
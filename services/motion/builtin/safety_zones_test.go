@@ -0,0 +1,150 @@
+package builtin
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	geo "github.com/kellydunn/golang-geo"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/base/kinematicbase"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+func TestSafetyZones(t *testing.T) {
+	box := func(x float64) *spatialmath.GeometryConfig {
+		return &spatialmath.GeometryConfig{
+			Type:              spatialmath.BoxType,
+			X:                 200,
+			Y:                 200,
+			Z:                 200,
+			TranslationOffset: r3.Vector{X: x},
+		}
+	}
+
+	t.Run("invalid zones are rejected", func(t *testing.T) {
+		test.That(t, (&SafetyZoneConfig{MaxSpeedMMPerSec: 100}).validate("zone"), test.ShouldNotBeNil)
+		test.That(t, (&SafetyZoneConfig{Geometry: box(0)}).validate("zone"), test.ShouldNotBeNil)
+		test.That(t, (&SafetyZoneConfig{Geometry: box(0), MaxSpeedMMPerSec: -1}).validate("zone"), test.ShouldNotBeNil)
+		test.That(t, (&SafetyZoneConfig{Geometry: box(0), MaxSpeedMMPerSec: 100}).validate("zone"), test.ShouldBeNil)
+		test.That(t, (&SafetyZoneConfig{Geometry: box(0), Stop: true}).validate("zone"), test.ShouldBeNil)
+		lat := 40.7
+		test.That(t, (&SafetyZoneConfig{Geometry: box(0), Stop: true, Latitude: &lat}).validate("zone"), test.ShouldNotBeNil)
+		test.That(t, (&SafetyZoneConfig{Geometry: box(0), Stop: true, Latitude: &lat, Longitude: &lat}).validate("zone"), test.ShouldBeNil)
+		test.That(t,
+			(&SafetyZoneConfig{Geometry: box(0), Stop: true, Latitude: &lat, Longitude: &lat, Frame: "gantry"}).validate("zone"),
+			test.ShouldNotBeNil,
+		)
+	})
+
+	// a gantry carries a sphere along X past a table, which sits within the slow zone
+	fs := referenceframe.NewEmptyFrameSystem("test")
+	sphere, err := spatialmath.NewSphere(spatialmath.NewZeroPose(), 10, "carriage")
+	test.That(t, err, test.ShouldBeNil)
+	gantry, err := referenceframe.NewTranslationalFrameWithGeometry(
+		"gantry", r3.Vector{X: 1}, referenceframe.Limit{Min: 0, Max: 2000}, sphere,
+	)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(gantry, fs.World()), test.ShouldBeNil)
+	tableGeometry, err := spatialmath.NewBox(spatialmath.NewZeroPose(), r3.Vector{X: 50, Y: 50, Z: 50}, "table")
+	test.That(t, err, test.ShouldBeNil)
+	table, err := referenceframe.NewStaticFrameWithGeometry("table", spatialmath.NewPoseFromPoint(r3.Vector{X: 500}), tableGeometry)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(table, fs.World()), test.ShouldBeNil)
+
+	lat, lng := 40.7, -74.
+	zones, geoZones, err := newSafetyZones([]SafetyZoneConfig{
+		{Name: "slow", Geometry: box(500), MaxSpeedMMPerSec: 100},
+		{Name: "stop", Geometry: box(1500), Stop: true},
+		{Name: "field", Latitude: &lat, Longitude: &lng, Geometry: box(0), MaxSpeedMMPerSec: 200},
+	})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(zones), test.ShouldEqual, 2)
+	test.That(t, len(geoZones), test.ShouldEqual, 1)
+
+	trajectory := []referenceframe.FrameSystemInputs{{"gantry": {{0}}}, {"gantry": {{1000}}}}
+	moving, err := movingFrames(fs, trajectory)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, moving, test.ShouldResemble, []string{"gantry"})
+
+	at := func(x float64) referenceframe.FrameSystemInputs {
+		return referenceframe.FrameSystemInputs{"gantry": {{x}}, "table": {}}
+	}
+
+	t.Run("outside every zone", func(t *testing.T) {
+		speed, err := zoneSpeedLimit(fs, at(0), moving, zones, 0)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, speed, test.ShouldEqual, 0)
+		speed, err = zoneSpeedLimit(fs, at(0), moving, zones, 250)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, speed, test.ShouldEqual, 250)
+	})
+
+	t.Run("within a zone which caps speed", func(t *testing.T) {
+		speed, err := zoneSpeedLimit(fs, at(450), moving, zones, 0)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, speed, test.ShouldEqual, 100)
+		speed, err = zoneSpeedLimit(fs, at(450), moving, zones, 50)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, speed, test.ShouldEqual, 50)
+	})
+
+	t.Run("within a zone which stops", func(t *testing.T) {
+		_, err := zoneSpeedLimit(fs, at(1500), moving, zones, 0)
+		var zoneErr *kinematicbase.SafetyZoneError
+		test.That(t, errors.As(err, &zoneErr), test.ShouldBeTrue)
+		test.That(t, zoneErr.Zone, test.ShouldEqual, "stop")
+	})
+
+	t.Run("zones are placed in the world frame for bases", func(t *testing.T) {
+		worldZones, err := safetyZonesInWorld(fs, at(0), zones)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(worldZones), test.ShouldEqual, 2)
+		test.That(t, worldZones[0].MaxSpeedMMPerSec, test.ShouldEqual, 100)
+		test.That(t, worldZones[1].Stop, test.ShouldBeTrue)
+		test.That(t, worldZones[0].Geometry.Pose().Point().X, test.ShouldAlmostEqual, 500)
+	})
+
+	t.Run("zones in frames which move are placed anew as they move", func(t *testing.T) {
+		carried, _, err := newSafetyZones([]SafetyZoneConfig{{Name: "carried", Frame: "gantry", Geometry: box(0), Stop: true}})
+		test.That(t, err, test.ShouldBeNil)
+		x := 0.
+		currentInputs := func(context.Context) (referenceframe.FrameSystemInputs, error) {
+			if x < 0 {
+				return nil, errors.New("cannot read inputs")
+			}
+			return at(x), nil
+		}
+		source, err := safetyZoneSource(context.Background(), fs, currentInputs, carried, logging.NewTestLogger(t))
+		test.That(t, err, test.ShouldBeNil)
+		placed, err := source(context.Background())
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, placed[0].Geometry.Pose().Point().X, test.ShouldAlmostEqual, 0)
+
+		x = 800
+		placed, err = source(context.Background())
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, placed[0].Geometry.Pose().Point().X, test.ShouldAlmostEqual, 800)
+
+		// zones are left where they were last placed while the inputs cannot be read
+		x = -1
+		placed, err = source(context.Background())
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, placed[0].Geometry.Pose().Point().X, test.ShouldAlmostEqual, 800)
+	})
+
+	t.Run("zones fixed to the globe are placed relative to the origin of bases", func(t *testing.T) {
+		// the origin is 0.001 degrees south of the zone, so it is about 111m north of the base
+		placed, err := geoSafetyZoneSource(geoZones, geo.NewPoint(lat-0.001, lng))(context.Background())
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(placed), test.ShouldEqual, 1)
+		test.That(t, placed[0].MaxSpeedMMPerSec, test.ShouldEqual, 200)
+		test.That(t, placed[0].Geometry.Pose().Point().X, test.ShouldAlmostEqual, 0, 1)
+		test.That(t, placed[0].Geometry.Pose().Point().Y, test.ShouldAlmostEqual, 111_000, 1000)
+		test.That(t, geoSafetyZoneSource(nil, geo.NewPoint(lat, lng)), test.ShouldBeNil)
+	})
+}
@@ -0,0 +1,197 @@
+package builtin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/golang/geo/r3"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/components/base/kinematicbase"
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/spatialmath"
+)
+
+const (
+	// requireFrameTransformsExtraKey refuses MoveOnGlobe requests when the offset between the movement sensor and the base is
+	// neither in the frame system nor estimated, rather than assuming the two are coincident.
+	requireFrameTransformsExtraKey = "require_frame_transforms"
+	// defaultOffsetSpins is how many equal spins make up the full turn of the base which the offset is estimated from.
+	defaultOffsetSpins = 4
+	// defaultOffsetHeadingMM is how far the base drives straight ahead to estimate its heading when the movement sensor has
+	// no compass.
+	defaultOffsetHeadingMM = 1000.
+)
+
+// sensorMount identifies a movement sensor mounted on a base.
+type sensorMount struct {
+	movementSensor resource.Name
+	base           resource.Name
+}
+
+// sensorOffsetRequest describes an estimation of the offset between a movement sensor and the base it is mounted on.
+type sensorOffsetRequest struct {
+	BaseName           string  `mapstructure:"base_name"`
+	MovementSensorName string  `mapstructure:"movement_sensor_name"`
+	Spins              int     `mapstructure:"spins"`
+	HeadingMM          float64 `mapstructure:"heading_mm"`
+	DegsPerSec         float64 `mapstructure:"degs_per_sec"`
+	MMPerSec           float64 `mapstructure:"mm_per_sec"`
+}
+
+// estimateSensorOffset turns the base in place through a full turn of equal spins, recording the position of the movement sensor
+// after each one. The positions lie on a circle around the center of the base, so the offset of the base from the movement sensor
+// is their mean relative to the sensor pose before the turn. The sensor is assumed to be aligned with the base. The estimate is
+// kept for later MoveOnGlobe requests and returned as the frame config of the movement sensor, since the motion service cannot
+// change the config of the machine.
+func (ms *builtIn) estimateSensorOffset(ctx context.Context, raw interface{}) (map[string]interface{}, error) {
+	var req sensorOffsetRequest
+	if err := mapstructure.Decode(raw, &req); err != nil {
+		return nil, err
+	}
+	if req.BaseName == "" || req.MovementSensorName == "" {
+		return nil, errors.New("base_name and movement_sensor_name are required to estimate an offset")
+	}
+	if req.Spins == 0 {
+		req.Spins = defaultOffsetSpins
+	}
+	if req.Spins < 2 {
+		return nil, fmt.Errorf("need at least 2 spins to estimate an offset, got %d", req.Spins)
+	}
+	defaults := kinematicbase.NewKinematicBaseOptions()
+	if req.HeadingMM <= 0 {
+		req.HeadingMM = defaultOffsetHeadingMM
+	}
+	if req.DegsPerSec <= 0 {
+		req.DegsPerSec = defaults.AngularVelocityDegsPerSec
+	}
+	if req.MMPerSec <= 0 {
+		req.MMPerSec = defaults.LinearVelocityMMPerSec
+	}
+
+	component, ok := findByShortName(ms.components, req.BaseName)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a dependency of the motion service", req.BaseName)
+	}
+	b, ok := component.(base.Base)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a base", req.BaseName)
+	}
+	movementSensor, ok := findByShortName(ms.movementSensors, req.MovementSensorName)
+	if !ok {
+		return nil, resource.DependencyNotFoundError(movementsensor.Named(req.MovementSensorName))
+	}
+	properties, err := movementSensor.Properties(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	origin, _, err := movementSensor.Position(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	var localizer motion.Localizer
+	if properties.CompassHeadingSupported || properties.OrientationSupported {
+		localizer = motion.NewMovementSensorLocalizer(movementSensor, origin, nil)
+	} else {
+		// without a compass the heading of the base is estimated from its GPS track, after which the turn starts where the track
+		// ended
+		heading, end, err := estimateHeading(ctx, b, movementSensor, req.HeadingMM, req.MMPerSec)
+		if err != nil {
+			return nil, err
+		}
+		origin = end
+		localizer = motion.NewGPSTrackLocalizer(movementSensor, origin, nil, end, heading, req.HeadingMM)
+	}
+	start, err := localizer.CurrentPosition(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var center r3.Vector
+	spinDeg := 360. / float64(req.Spins)
+	for i := 0; i < req.Spins; i++ {
+		if err := b.Spin(ctx, spinDeg, req.DegsPerSec, nil); err != nil {
+			return nil, err
+		}
+		position, _, err := movementSensor.Position(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		center = center.Add(spatialmath.GeoPointToPoint(position, origin))
+	}
+	center = center.Mul(1 / float64(req.Spins))
+	calibration := spatialmath.NewPoseFromPoint(
+		spatialmath.PoseBetween(start.Pose(), spatialmath.NewPoseFromPoint(center)).Point(),
+	)
+
+	ms.offsetMu.Lock()
+	if ms.sensorOffsets == nil {
+		ms.sensorOffsets = map[sensorMount]spatialmath.Pose{}
+	}
+	ms.sensorOffsets[sensorMount{movementSensor: movementSensor.Name(), base: b.Name()}] = calibration
+	ms.offsetMu.Unlock()
+	ms.logger.CInfof(ctx, "estimated the center of %s to be %v from %s", req.BaseName, calibration.Point(), req.MovementSensorName)
+
+	// the frame of the movement sensor is the inverse of the pose of the base relative to it
+	frame := referenceframe.LinkConfig{
+		Parent:      req.BaseName,
+		Translation: spatialmath.PoseInverse(calibration).Point(),
+	}
+	var frameMap map[string]interface{}
+	if err := jsonRoundTrip(frame, &frameMap); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"frame": frameMap}, nil
+}
+
+// movementSensorToBase returns the pose of the origin of the base relative to the movement sensor. It is taken from the frame
+// system if possible, and otherwise from an earlier estimate. Without either the two are assumed to be coincident, unless the
+// request requires the transform.
+func (ms *builtIn) movementSensorToBase(
+	ctx context.Context,
+	movementSensor movementsensor.MovementSensor,
+	baseName resource.Name,
+	required bool,
+) (*referenceframe.PoseInFrame, error) {
+	baseOrigin := referenceframe.NewPoseInFrame(baseName.ShortName(), spatialmath.NewZeroPose())
+	transformed, err := ms.fsService.TransformPose(ctx, baseOrigin, movementSensor.Name().ShortName(), nil)
+	if err == nil {
+		return transformed, nil
+	}
+
+	ms.offsetMu.Lock()
+	estimate, ok := ms.sensorOffsets[sensorMount{movementSensor: movementSensor.Name(), base: baseName}]
+	ms.offsetMu.Unlock()
+	if ok {
+		ms.logger.CDebugf(ctx, "using the estimated offset of %s from %s", baseName.ShortName(), movementSensor.Name().ShortName())
+		return referenceframe.NewPoseInFrame(movementSensor.Name().ShortName(), estimate), nil
+	}
+	if required {
+		return nil, fmt.Errorf(
+			"cannot find the transform between %s and %s, add both to the frame system or estimate their offset with %q: %w",
+			movementSensor.Name().ShortName(), baseName.ShortName(), DoEstimateSensorOffset, err,
+		)
+	}
+	ms.logger.CWarnf(ctx, "cannot find the transform between %s and %s, assuming they are coincident: %v",
+		movementSensor.Name().ShortName(), baseName.ShortName(), err)
+	return baseOrigin, nil
+}
+
+// parseRequireFrameTransforms reads whether a MoveOnGlobe request refuses to assume missing frame transforms.
+func parseRequireFrameTransforms(extra map[string]interface{}) (bool, error) {
+	raw, ok := extra[requireFrameTransformsExtraKey]
+	if !ok {
+		return false, nil
+	}
+	required, ok := raw.(bool)
+	if !ok {
+		return false, fmt.Errorf("could not interpret %s field as bool", requireFrameTransformsExtraKey)
+	}
+	return required, nil
+}
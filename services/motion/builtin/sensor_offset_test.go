@@ -0,0 +1,109 @@
+package builtin
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	geo "github.com/kellydunn/golang-geo"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/base"
+	simbase "go.viam.com/rdk/components/base/kinematicbase/fake"
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestEstimateSensorOffset(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	origin := spatialmath.NewGeoPose(geo.NewPoint(40, -74), 0)
+	// the movement sensor is mounted to the right of and behind the center of the base
+	mount := spatialmath.NewPoseFromPoint(r3.Vector{X: 100, Y: -200})
+
+	// newSensor returns a movement sensor which reports the true position of its mount on the simulated base
+	newSensor := func(b *simbase.Base, compass bool) *inject.MovementSensor {
+		sensor := inject.NewMovementSensor("gps")
+		sensor.PositionFunc = func(ctx context.Context, extra map[string]interface{}) (*geo.Point, float64, error) {
+			return spatialmath.PoseToGeoPose(origin, spatialmath.Compose(b.TruePose(), mount)).Location(), 0, nil
+		}
+		sensor.CompassHeadingFunc = func(ctx context.Context, extra map[string]interface{}) (float64, error) {
+			return spatialmath.PoseToGeoPose(origin, b.TruePose()).Heading(), nil
+		}
+		sensor.PropertiesFunc = func(ctx context.Context, extra map[string]interface{}) (*movementsensor.Properties, error) {
+			return &movementsensor.Properties{PositionSupported: true, CompassHeadingSupported: compass}, nil
+		}
+		return sensor
+	}
+	newService := func(b *simbase.Base, sensor *inject.MovementSensor) *builtIn {
+		fsSvc := inject.NewFrameSystemService("fs")
+		fsSvc.TransformPoseFunc = func(
+			ctx context.Context,
+			pose *referenceframe.PoseInFrame,
+			dst string,
+			additionalTransforms []*referenceframe.LinkInFrame,
+		) (*referenceframe.PoseInFrame, error) {
+			return nil, errors.New("gps is not in the frame system")
+		}
+		return &builtIn{
+			logger:          logger,
+			fsService:       fsSvc,
+			components:      map[resource.Name]resource.Resource{b.Name(): b},
+			movementSensors: map[resource.Name]movementsensor.MovementSensor{sensor.Name(): sensor},
+		}
+	}
+	cmd := map[string]interface{}{"base_name": "base", "movement_sensor_name": "gps", "degs_per_sec": 3600., "mm_per_sec": 5000.}
+
+	for _, compass := range []bool{true, false} {
+		t.Run("offset is estimated from a turn of the base", func(t *testing.T) {
+			// the base starts facing east
+			start := spatialmath.NewPoseFromOrientation(&spatialmath.OrientationVectorDegrees{OZ: 1, Theta: -90})
+			b := simbase.NewBase(base.Named("base"), nil, start, nil, 0, logger)
+			sensor := newSensor(b, compass)
+			ms := newService(b, sensor)
+
+			// without a transform or an estimate, a request may refuse to assume the two are coincident
+			_, err := ms.movementSensorToBase(ctx, sensor, b.Name(), true)
+			test.That(t, err, test.ShouldNotBeNil)
+			test.That(t, err.Error(), test.ShouldContainSubstring, DoEstimateSensorOffset)
+			coincident, err := ms.movementSensorToBase(ctx, sensor, b.Name(), false)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, spatialmath.PoseAlmostEqual(coincident.Pose(), spatialmath.NewZeroPose()), test.ShouldBeTrue)
+
+			resp, err := ms.estimateSensorOffset(ctx, cmd)
+			test.That(t, err, test.ShouldBeNil)
+			var frame referenceframe.LinkConfig
+			test.That(t, jsonRoundTrip(resp["frame"], &frame), test.ShouldBeNil)
+			test.That(t, frame.Parent, test.ShouldEqual, "base")
+			test.That(t, frame.Translation.Distance(mount.Point()), test.ShouldBeLessThan, 10)
+
+			// the estimate is used in place of the missing transform
+			estimate, err := ms.movementSensorToBase(ctx, sensor, b.Name(), true)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, estimate.Parent(), test.ShouldEqual, "gps")
+			test.That(t, estimate.Pose().Point().Distance(spatialmath.PoseInverse(mount).Point()), test.ShouldBeLessThan, 10)
+		})
+	}
+
+	t.Run("requests are validated", func(t *testing.T) {
+		b := simbase.NewBase(base.Named("base"), nil, spatialmath.NewZeroPose(), nil, 0, logger)
+		ms := newService(b, newSensor(b, true))
+		_, err := ms.estimateSensorOffset(ctx, map[string]interface{}{"base_name": "base"})
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = ms.estimateSensorOffset(ctx, map[string]interface{}{"base_name": "base", "movement_sensor_name": "gps", "spins": 1})
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = ms.estimateSensorOffset(ctx, map[string]interface{}{"base_name": "gps", "movement_sensor_name": "gps"})
+		test.That(t, err, test.ShouldNotBeNil)
+
+		_, err = newValidatedExtra(map[string]interface{}{requireFrameTransformsExtraKey: "yes"})
+		test.That(t, err, test.ShouldNotBeNil)
+		valExtra, err := newValidatedExtra(map[string]interface{}{requireFrameTransformsExtraKey: true})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, valExtra.requireFrameTransforms, test.ShouldBeTrue)
+	})
+}
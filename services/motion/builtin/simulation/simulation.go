@@ -0,0 +1,302 @@
+//go:build !no_cgo
+
+// Package simulation runs the builtin motion service against a simulated base, SLAM service and obstacle detector which are all
+// driven by a virtual clock, so that executions which replan can be tested end to end without hardware.
+//
+// Time only passes when the simulation is stepped, so how far the base drives between the polls of the replanners depends on the
+// step rather than on how fast the machine running the simulation is. The simulation is only stepped while the base is being
+// driven, so that planning takes no simulated time, unless the base stays stopped for longer than Config.IdleTimeout of wall time,
+// as it does while a local planner waits for its way to clear.
+package simulation
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+	goutils "go.viam.com/utils"
+
+	"go.viam.com/rdk/components/base"
+	simbase "go.viam.com/rdk/components/base/kinematicbase/fake"
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot/framesystem"
+	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/services/motion/builtin"
+	"go.viam.com/rdk/services/slam"
+	"go.viam.com/rdk/services/vision"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+	viz "go.viam.com/rdk/vision"
+)
+
+const (
+	// defaultStep is the simulated time which passes each time the simulation is stepped.
+	defaultStep = 50 * time.Millisecond
+	// defaultIdleTimeout is how long the base may stay stopped, in wall time, before the simulation is stepped anyway.
+	defaultIdleTimeout = 250 * time.Millisecond
+	// idlePollInterval is how often, in wall time, a stopped base is checked for whether it is being driven again.
+	idlePollInterval = time.Millisecond
+)
+
+var (
+	// BaseName is the name of the simulated base.
+	BaseName = base.Named("base")
+	// SLAMName is the name of the simulated SLAM service which localizes the base.
+	SLAMName = slam.Named("slam")
+	// DetectorName is the name of the simulated vision service which detects the obstacles around the base.
+	DetectorName = vision.Named("detector")
+	// CameraName is the name of the camera frame the obstacles are detected in, which coincides with the frame of the base.
+	CameraName = camera.Named("camera")
+)
+
+// ScriptedObstacle is an obstacle which appears in the world once the given amount of simulated time has passed.
+type ScriptedObstacle struct {
+	At       time.Duration
+	Obstacle spatialmath.Geometry
+}
+
+// Config describes the world a simulated base drives through.
+type Config struct {
+	// Start is the pose of the base in the world frame, in which the base drives along its +Y axis. Defaults to the origin.
+	Start spatialmath.Pose
+	// BaseGeometry is the geometry of the base in its own frame, used for planning and for detecting collisions.
+	BaseGeometry spatialmath.Geometry
+	// TurningRadiusMeters is the minimum turning radius of the base. Zero allows the base to spin in place.
+	TurningRadiusMeters float64
+	// MapMin and MapMax are opposite corners of the area covered by the SLAM map, in mm.
+	MapMin, MapMax r3.Vector
+	// Obstacles are in the world from the start, and are planned around as obstacles of the map.
+	Obstacles []spatialmath.Geometry
+	// Script lists obstacles which appear partway through the simulation, which are only known from the obstacle detector.
+	Script []ScriptedObstacle
+	// DetectionRangeMM is how far from the base obstacles are detected. Zero detects every obstacle.
+	DetectionRangeMM float64
+	// LocalizationNoise perturbs the pose of the base reported by the SLAM service. If nil, localization is perfect.
+	LocalizationNoise simbase.NoiseModel
+	// Step is the simulated time which passes each time the simulation is stepped. Defaults to 50ms.
+	Step time.Duration
+	// IdleTimeout is how long the base may stay stopped, in wall time, before the simulation is stepped anyway. Defaults to 250ms.
+	IdleTimeout time.Duration
+}
+
+// Simulation is the builtin motion service wired to a simulated base, SLAM service and obstacle detector.
+type Simulation struct {
+	// Motion is the builtin motion service under test.
+	Motion motion.Service
+	// Base is the simulated base, through which its true pose and its collisions can be inspected.
+	Base *simbase.Base
+
+	cfg       Config
+	clock     *clock.Mock
+	world     *simbase.World
+	fsService framesystem.Service
+
+	mu      sync.Mutex
+	elapsed time.Duration
+	script  []ScriptedObstacle
+}
+
+// New returns a simulation of the given world, with a builtin motion service which plans and executes on the simulated base.
+func New(ctx context.Context, cfg Config, logger logging.Logger) (*Simulation, error) {
+	if cfg.Start == nil {
+		cfg.Start = spatialmath.NewZeroPose()
+	}
+	if cfg.BaseGeometry == nil {
+		geometry, err := spatialmath.NewBox(spatialmath.NewZeroPose(), r3.Vector{X: 300, Y: 300, Z: 200}, BaseName.ShortName())
+		if err != nil {
+			return nil, err
+		}
+		cfg.BaseGeometry = geometry
+	}
+	if cfg.Step <= 0 {
+		cfg.Step = defaultStep
+	}
+	if cfg.IdleTimeout <= 0 {
+		cfg.IdleTimeout = defaultIdleTimeout
+	}
+	script := append([]ScriptedObstacle{}, cfg.Script...)
+	sort.SliceStable(script, func(i, j int) bool { return script[i].At < script[j].At })
+
+	clk := clock.NewMock()
+	world := &simbase.World{
+		Obstacles:         append([]spatialmath.Geometry{}, cfg.Obstacles...),
+		LocalizationNoise: cfg.LocalizationNoise,
+		Clock:             clk,
+	}
+	b := simbase.NewBase(BaseName, world, cfg.Start, cfg.BaseGeometry, cfg.TurningRadiusMeters, logger)
+	slamSvc, err := newSLAM(cfg, b)
+	if err != nil {
+		return nil, err
+	}
+	detector := inject.NewVisionService(DetectorName.ShortName())
+	detector.GetObjectPointCloudsFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]*viz.Object, error) {
+		return b.Detections(ctx, cfg.DetectionRangeMM)
+	}
+
+	deps := resource.Dependencies{
+		BaseName:     b,
+		SLAMName:     slamSvc,
+		DetectorName: detector,
+	}
+	fsService, err := framesystem.New(ctx, deps, logger)
+	if err != nil {
+		return nil, err
+	}
+	fsParts := []*referenceframe.FrameSystemPart{
+		{FrameConfig: referenceframe.NewLinkInFrame(referenceframe.World, spatialmath.NewZeroPose(), BaseName.ShortName(), cfg.BaseGeometry)},
+		{FrameConfig: referenceframe.NewLinkInFrame(BaseName.ShortName(), spatialmath.NewZeroPose(), CameraName.ShortName(), nil)},
+	}
+	if err := fsService.Reconfigure(ctx, deps, resource.Config{ConvertedAttributes: &framesystem.Config{Parts: fsParts}}); err != nil {
+		return nil, err
+	}
+	deps[fsService.Name()] = fsService
+
+	ms, err := builtin.NewBuiltInWithClock(ctx, deps, resource.Config{ConvertedAttributes: &builtin.Config{}}, clk, logger)
+	if err != nil {
+		return nil, multierr.Combine(err, fsService.Close(ctx))
+	}
+	return &Simulation{
+		Motion:    ms,
+		Base:      b,
+		cfg:       cfg,
+		clock:     clk,
+		world:     world,
+		fsService: fsService,
+		script:    script,
+	}, nil
+}
+
+// newSLAM returns a SLAM service in localization mode which reports the pose of the simulated base, with a map which covers the
+// configured area but contains no obstacles of its own.
+func newSLAM(cfg Config, b *simbase.Base) (*inject.SLAMService, error) {
+	pc := pointcloud.New()
+	for _, corner := range []r3.Vector{cfg.MapMin, cfg.MapMax} {
+		if err := pc.Set(corner, pointcloud.NewBasicData()); err != nil {
+			return nil, err
+		}
+	}
+	var pcd bytes.Buffer
+	if err := pointcloud.ToPCD(pc, &pcd, pointcloud.PCDBinary); err != nil {
+		return nil, err
+	}
+
+	slamSvc := inject.NewSLAMService(SLAMName.ShortName())
+	slamSvc.PointCloudMapFunc = func(ctx context.Context, returnEditedMap bool) (func() ([]byte, error), error) {
+		sent := false
+		return func() ([]byte, error) {
+			if sent {
+				return nil, io.EOF
+			}
+			sent = true
+			return pcd.Bytes(), nil
+		}, nil
+	}
+	slamSvc.PositionFunc = func(ctx context.Context) (spatialmath.Pose, error) {
+		pif, err := b.CurrentPosition(ctx)
+		if err != nil {
+			return nil, err
+		}
+		// SLAM poses face along +X, which the SLAM localizer turns back to face along +Y
+		return spatialmath.Compose(pif.Pose(), spatialmath.PoseInverse(motion.SLAMOrientationAdjustment)), nil
+	}
+	slamSvc.LocalizationQualityFunc = func(ctx context.Context) (slam.LocalizationQuality, error) {
+		return slam.LocalizationQuality{State: slam.LocalizationStateTracking, Confidence: 1}, nil
+	}
+	slamSvc.PropertiesFunc = func(ctx context.Context) (slam.Properties, error) {
+		return slam.Properties{MappingMode: slam.MappingModeLocalizationOnly}, nil
+	}
+	return slamSvc, nil
+}
+
+// Elapsed returns how much simulated time has passed.
+func (s *Simulation) Elapsed() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.elapsed
+}
+
+// Step advances the simulation by one step, driving the base and polling the replanners as the clock passes, and then adding any
+// scripted obstacles which are due.
+func (s *Simulation) Step() {
+	s.clock.Add(s.cfg.Step)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.elapsed += s.cfg.Step
+	for len(s.script) > 0 && s.script[0].At <= s.elapsed {
+		s.world.AddObstacles(s.script[0].Obstacle)
+		s.script = s.script[1:]
+	}
+}
+
+// MoveOnMap starts moving the simulated base to the destination, a pose in the SLAM map, detecting obstacles with the simulated
+// detector in addition to any detectors of the motion configuration.
+func (s *Simulation) MoveOnMap(
+	ctx context.Context,
+	destination spatialmath.Pose,
+	motionCfg *motion.MotionConfiguration,
+	extra map[string]interface{},
+) (motion.ExecutionID, error) {
+	cfg := motion.MotionConfiguration{}
+	if motionCfg != nil {
+		cfg = *motionCfg
+	}
+	cfg.ObstacleDetectors = append(
+		append([]motion.ObstacleDetectorName{}, cfg.ObstacleDetectors...),
+		motion.ObstacleDetectorName{VisionServiceName: DetectorName, CameraName: CameraName},
+	)
+	return s.Motion.MoveOnMap(ctx, motion.MoveOnMapReq{
+		ComponentName: BaseName,
+		Destination:   destination,
+		SlamName:      SLAMName,
+		MotionCfg:     &cfg,
+		Obstacles:     s.cfg.Obstacles,
+		Extra:         extra,
+	})
+}
+
+// Run steps the simulation until the execution ends, returning the plans of the execution from the most recent. An error is
+// returned along with the plans if the execution has not ended once the given amount of simulated time has passed.
+func (s *Simulation) Run(ctx context.Context, executionID motion.ExecutionID, timeout time.Duration) ([]motion.PlanWithStatus, error) {
+	deadline := s.Elapsed() + timeout
+	idleSince := time.Now()
+	for {
+		history, err := s.Motion.PlanHistory(ctx, motion.PlanHistoryReq{ComponentName: BaseName, ExecutionID: executionID})
+		if err != nil {
+			return nil, err
+		}
+		if history[0].StatusHistory[0].State != motion.PlanStateInProgress {
+			return history, nil
+		}
+		if s.Elapsed() >= deadline {
+			return history, errors.Errorf("execution did not end within %v of simulated time", timeout)
+		}
+
+		moving, err := s.Base.IsMoving(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if moving || time.Since(idleSince) > s.cfg.IdleTimeout {
+			s.Step()
+			idleSince = time.Now()
+		} else if !goutils.SelectContextOrWait(ctx, idlePollInterval) {
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Close stops the motion service of the simulation.
+func (s *Simulation) Close(ctx context.Context) error {
+	return multierr.Combine(s.Motion.Close(ctx), s.fsService.Close(ctx))
+}
@@ -0,0 +1,83 @@
+//go:build !no_cgo
+
+package simulation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/spatialmath"
+)
+
+func TestSimulation(t *testing.T) {
+	ctx := context.Background()
+	goal := spatialmath.NewPoseFromPoint(r3.Vector{Y: 2000})
+	pollingFreq := 5.
+
+	newSimulation := func(t *testing.T, script ...ScriptedObstacle) *Simulation {
+		t.Helper()
+		sim, err := New(ctx, Config{
+			MapMin:           r3.Vector{X: -3000, Y: -3000},
+			MapMax:           r3.Vector{X: 3000, Y: 3000},
+			Script:           script,
+			DetectionRangeMM: 1500,
+		}, logging.NewTestLogger(t))
+		test.That(t, err, test.ShouldBeNil)
+		t.Cleanup(func() { test.That(t, sim.Close(ctx), test.ShouldBeNil) })
+		return sim
+	}
+	motionCfg := &motion.MotionConfiguration{ObstaclePollingFreqHz: &pollingFreq, PositionPollingFreqHz: &pollingFreq}
+	// a wall across the straight path to the goal which appears once the base has set off
+	wall := func(t *testing.T) ScriptedObstacle {
+		t.Helper()
+		geometry, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(r3.Vector{Y: 1200}), r3.Vector{X: 600, Y: 100, Z: 200}, "wall")
+		test.That(t, err, test.ShouldBeNil)
+		return ScriptedObstacle{At: time.Second, Obstacle: geometry}
+	}
+
+	t.Run("reaches the goal without replanning", func(t *testing.T) {
+		sim := newSimulation(t)
+		executionID, err := sim.MoveOnMap(ctx, goal, motionCfg, nil)
+		test.That(t, err, test.ShouldBeNil)
+		history, err := sim.Run(ctx, executionID, time.Minute)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(history), test.ShouldEqual, 1)
+		test.That(t, history[0].StatusHistory[0].State, test.ShouldEqual, motion.PlanStateSucceeded)
+
+		pif, err := sim.Base.CurrentPosition(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, pif.Pose().Point().Distance(goal.Point()), test.ShouldBeLessThan, 300)
+		test.That(t, sim.Base.Collisions(), test.ShouldBeEmpty)
+		test.That(t, sim.Elapsed(), test.ShouldBeGreaterThan, 0)
+	})
+
+	t.Run("replans around an obstacle which appears in the path", func(t *testing.T) {
+		sim := newSimulation(t, wall(t))
+		executionID, err := sim.MoveOnMap(ctx, goal, motionCfg, nil)
+		test.That(t, err, test.ShouldBeNil)
+		history, err := sim.Run(ctx, executionID, time.Minute)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(history), test.ShouldBeGreaterThanOrEqualTo, 2)
+		test.That(t, history[0].StatusHistory[0].State, test.ShouldEqual, motion.PlanStateSucceeded)
+		test.That(t, history[len(history)-1].StatusHistory[0].State, test.ShouldEqual, motion.PlanStateFailed)
+		test.That(t, sim.Base.Collisions(), test.ShouldBeEmpty)
+	})
+
+	t.Run("fails once the maximum number of replans is exceeded", func(t *testing.T) {
+		sim := newSimulation(t, wall(t))
+		executionID, err := sim.MoveOnMap(ctx, goal, motionCfg, map[string]interface{}{"max_replans": 0})
+		test.That(t, err, test.ShouldBeNil)
+		history, err := sim.Run(ctx, executionID, time.Minute)
+		test.That(t, err, test.ShouldBeNil)
+		status := history[0].StatusHistory[0]
+		test.That(t, status.State, test.ShouldEqual, motion.PlanStateFailed)
+		test.That(t, status.Reason, test.ShouldNotBeNil)
+		test.That(t, *status.Reason, test.ShouldContainSubstring, "exceeded maximum number of replans")
+	})
+}
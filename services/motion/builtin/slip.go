@@ -0,0 +1,179 @@
+package builtin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+const (
+	// slipThresholdExtraKey is the key of extra setting the fraction in (0, 1] of the distance a base is commanded to drive which it may
+	// fail to cover, as measured by its localizer, before it is taken to be slipping, such as when its wheels spin against an obstacle
+	// it cannot see. A slipping base backs up and replans. Without it, slip is not detected.
+	slipThresholdExtraKey = "slip_threshold"
+	// slipBackupExtraKey is the key of extra setting how far in mm a slipping base backs up before replanning, which may be zero.
+	slipBackupExtraKey = "slip_backup_mm"
+	// defaultSlipBackupMM is how far a slipping base backs up if the request does not say.
+	defaultSlipBackupMM = 300.
+	// slipWindowMM is the distance a base is commanded to drive over which its slip is measured, which is long enough that the noise
+	// of most localizers does not look like slip.
+	slipWindowMM = 500.
+)
+
+// slipConfig is how much a base may slip before it backs up by backupMM and replans.
+type slipConfig struct {
+	threshold float64
+	backupMM  float64
+}
+
+// parseSlip parses the slip detection of a request from extra, returning a zero threshold if slip is not detected.
+func parseSlip(extra map[string]interface{}) (slipConfig, error) {
+	cfg := slipConfig{backupMM: defaultSlipBackupMM}
+	if raw, ok := extra[slipThresholdExtraKey]; ok {
+		threshold, ok := raw.(float64)
+		if !ok {
+			return slipConfig{}, fmt.Errorf("could not interpret %s field as float", slipThresholdExtraKey)
+		}
+		if threshold <= 0 || threshold > 1 {
+			return slipConfig{}, fmt.Errorf("%s must be in (0, 1]", slipThresholdExtraKey)
+		}
+		cfg.threshold = threshold
+	}
+	if raw, ok := extra[slipBackupExtraKey]; ok {
+		backup, ok := raw.(float64)
+		if !ok {
+			return slipConfig{}, fmt.Errorf("could not interpret %s field as float", slipBackupExtraKey)
+		}
+		if backup < 0 {
+			return slipConfig{}, fmt.Errorf("%s may not be negative", slipBackupExtraKey)
+		}
+		cfg.backupMM = backup
+	}
+	return cfg, nil
+}
+
+// slipDetector compares the distance a base is commanded to drive along its plan with the distance its localizer sees it cover. Once
+// it is found slipping, it stays slipping, so that every poll of the position replanner calls for the replan. A nil slipDetector never
+// detects slip.
+type slipDetector struct {
+	cfg slipConfig
+
+	// the index, commanded pose and localized pose of the base when it was last observed, with lastCommanded nil before it is first
+	// observed
+	lastIndex     int
+	lastCommanded spatialmath.Pose
+	lastLocalized spatialmath.Pose
+	// the distances the base was commanded to drive and was seen to cover since its slip was last measured
+	commandedMM float64
+	localizedMM float64
+
+	mu sync.Mutex
+	// reason is why the base was found slipping, and is empty until it is
+	reason string
+}
+
+// newSlipDetector returns a slipDetector for the given configuration, or nil if slip is not detected.
+func newSlipDetector(cfg slipConfig) *slipDetector {
+	if cfg.threshold <= 0 {
+		return nil
+	}
+	return &slipDetector{cfg: cfg}
+}
+
+// observe records where the base is commanded to be along the step of its plan with the given index and where it is localized,
+// returning why it is slipping once it has covered less than its threshold of what it was commanded to over the slip window.
+// Movement between observations in different steps is not counted, since the commanded pose may jump when the plan is corrected.
+func (d *slipDetector) observe(index int, commanded, localized spatialmath.Pose) string {
+	if d == nil {
+		return ""
+	}
+	if reason := d.slipping(); reason != "" {
+		return reason
+	}
+	if d.lastCommanded != nil && index == d.lastIndex {
+		d.commandedMM += commanded.Point().Distance(d.lastCommanded.Point())
+		d.localizedMM += localized.Point().Distance(d.lastLocalized.Point())
+	}
+	d.lastIndex, d.lastCommanded, d.lastLocalized = index, commanded, localized
+	if d.commandedMM < slipWindowMM {
+		return ""
+	}
+	slip := 1 - math.Min(1, d.localizedMM/d.commandedMM)
+	commandedMM, localizedMM := d.commandedMM, d.localizedMM
+	d.commandedMM, d.localizedMM = 0, 0
+	if slip < d.cfg.threshold {
+		return ""
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.reason = fmt.Sprintf(
+		"base is slipping: it covered %.0fmm of the %.0fmm it was commanded to drive, backing up and replanning", localizedMM, commandedMM,
+	)
+	return d.reason
+}
+
+// slipping returns why the base was found slipping, or an empty string if it was not.
+func (d *slipDetector) slipping() string {
+	if d == nil {
+		return ""
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.reason
+}
+
+// checkSlip observes the base at the given execution state, returning why it is slipping if it is.
+func (mr *moveRequest) checkSlip(executionState motionplan.ExecutionState) (string, error) {
+	if mr.slip == nil {
+		return "", nil
+	}
+	localized, ok := executionState.CurrentPoses()[mr.kinematicBase.LocalizationFrame().Name()]
+	if !ok {
+		return "", errors.New("executionState.CurrentPoses() does not contain an entry for the LocalizationFrame")
+	}
+	commanded, err := commandedPose(executionState, mr.kinematicBase.Kinematics())
+	if err != nil {
+		return "", err
+	}
+	return mr.slip.observe(executionState.Index(), commanded, localized.Pose()), nil
+}
+
+// recoverFromSlip backs the base up, if it was found slipping, so that it is clear of whatever it was pushing against when it replans.
+func (mr *moveRequest) recoverFromSlip(ctx context.Context) error {
+	if mr.slip.slipping() == "" || mr.slip.cfg.backupMM <= 0 {
+		return nil
+	}
+	mr.logger.CInfof(ctx, "backing up %.0fmm after slipping", mr.slip.cfg.backupMM)
+	return mr.kinematicBase.MoveStraight(ctx, -int(mr.slip.cfg.backupMM), mr.config.linearMPerSec*1000, nil)
+}
+
+// commandedPose returns where the base is commanded to be at the given execution state, composing the pose at the start of the step
+// it is executing with its progress through the step.
+func commandedPose(e motionplan.ExecutionState, executionFrame referenceframe.Frame) (spatialmath.Pose, error) {
+	currentInputs, ok := e.CurrentInputs()[executionFrame.Name()]
+	if !ok {
+		return nil, fmt.Errorf("execution state does not contain inputs for frame %s", executionFrame.Name())
+	}
+	path := e.Plan().Path()
+	index := e.Index() - 1
+	if index < 0 || index >= len(path) {
+		return nil, fmt.Errorf("index %d out of bounds for Path of length %d", index, len(path))
+	}
+	start, ok := path[index][executionFrame.Name()]
+	if !ok {
+		return nil, fmt.Errorf("path does not contain a pose for frame %s", executionFrame.Name())
+	}
+	// the inputs of a step describe motion relative to its start, which may lie outside the limits of the frame
+	inStep, err := executionFrame.Transform(currentInputs)
+	if err != nil && !strings.Contains(err.Error(), referenceframe.OOBErrString) {
+		return nil, err
+	}
+	return spatialmath.Compose(start.Pose(), inStep), nil
+}
@@ -0,0 +1,71 @@
+package builtin
+
+import (
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/spatialmath"
+)
+
+func TestSlipDetector(t *testing.T) {
+	t.Run("parsed from extra", func(t *testing.T) {
+		valExtra, err := newValidatedExtra(map[string]interface{}{})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, newSlipDetector(valExtra.slip), test.ShouldBeNil)
+
+		valExtra, err = newValidatedExtra(map[string]interface{}{slipThresholdExtraKey: 0.5})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, valExtra.slip, test.ShouldResemble, slipConfig{threshold: 0.5, backupMM: defaultSlipBackupMM})
+
+		valExtra, err = newValidatedExtra(map[string]interface{}{slipThresholdExtraKey: 0.5, slipBackupExtraKey: 0.})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, valExtra.slip.backupMM, test.ShouldEqual, 0)
+
+		_, err = newValidatedExtra(map[string]interface{}{slipThresholdExtraKey: 1.5})
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = newValidatedExtra(map[string]interface{}{slipThresholdExtraKey: "half"})
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = newValidatedExtra(map[string]interface{}{slipBackupExtraKey: -1.})
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("a nil detector never slips", func(t *testing.T) {
+		var d *slipDetector
+		test.That(t, d.observe(1, spatialmath.NewZeroPose(), spatialmath.NewZeroPose()), test.ShouldBeEmpty)
+		test.That(t, d.slipping(), test.ShouldBeEmpty)
+	})
+
+	at := func(x float64) spatialmath.Pose {
+		return spatialmath.NewPoseFromPoint(r3.Vector{X: x})
+	}
+
+	t.Run("a base which covers what it is commanded to does not slip", func(t *testing.T) {
+		d := newSlipDetector(slipConfig{threshold: 0.5, backupMM: 300})
+		for x := 0.; x <= 2*slipWindowMM; x += 100 {
+			test.That(t, d.observe(1, at(x), at(0.9*x)), test.ShouldBeEmpty)
+		}
+		test.That(t, d.slipping(), test.ShouldBeEmpty)
+	})
+
+	t.Run("a base which covers too little of what it is commanded to slips", func(t *testing.T) {
+		d := newSlipDetector(slipConfig{threshold: 0.5, backupMM: 300})
+		var reason string
+		for x := 0.; x <= slipWindowMM; x += 100 {
+			reason = d.observe(1, at(x), at(0.2*x))
+		}
+		test.That(t, reason, test.ShouldContainSubstring, "slipping")
+		// it stays slipping
+		test.That(t, d.observe(1, at(0), at(0)), test.ShouldEqual, reason)
+		test.That(t, d.slipping(), test.ShouldEqual, reason)
+	})
+
+	t.Run("movement between steps is not counted", func(t *testing.T) {
+		d := newSlipDetector(slipConfig{threshold: 0.5, backupMM: 300})
+		test.That(t, d.observe(1, at(0), at(0)), test.ShouldBeEmpty)
+		// the plan is corrected, and the commanded pose jumps
+		test.That(t, d.observe(2, at(2*slipWindowMM), at(0)), test.ShouldBeEmpty)
+		test.That(t, d.slipping(), test.ShouldBeEmpty)
+	})
+}
@@ -0,0 +1,158 @@
+package builtin
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opencensus.io/trace"
+
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/vision"
+	viz "go.viam.com/rdk/vision"
+)
+
+const (
+	// defaultSensorSkewTarget is the span of time within which the reads making up a sensor snapshot are retaken to fall, unless the
+	// request sets a maximum skew. A snapshot which misses it is still used.
+	defaultSensorSkewTarget = 250 * time.Millisecond
+	// maxSnapshotAttempts is how many times a snapshot is taken while its reads miss the target skew.
+	maxSnapshotAttempts = 3
+)
+
+// obstacleDetector identifies a camera used by a vision service to detect transient obstacles.
+type obstacleDetector struct {
+	visSrvc vision.Service
+	camName resource.Name
+}
+
+// sensorSnapshot bundles the localization, component inputs and obstacle detections used to construct a plan or check one for
+// collisions. Every value in a snapshot is read concurrently. The sensors do not report when their values were sampled, so each
+// value is taken to have been sampled midway through the call which read it.
+type sensorSnapshot struct {
+	// timestamp is the midpoint of the span of time in which the values were sampled
+	timestamp time.Time
+	// skew is the span of time between the first and last values being sampled
+	skew time.Duration
+
+	kinematicBaseInputs []referenceframe.Input
+	executionState      motionplan.ExecutionState
+	inputs              referenceframe.FrameSystemInputs
+	detections          map[obstacleDetector][]*viz.Object
+}
+
+// snapshot reads the current state of the kinematic base, the frame system and every obstacle detector, retrying if the values are
+// not sampled within the target skew of each other. If every attempt misses the target, the one with the smallest skew is used,
+// unless the request set a maximum skew, in which case the snapshot fails.
+func (mr *moveRequest) snapshot(ctx context.Context) (*sensorSnapshot, error) {
+	ctx, span := trace.StartSpan(ctx, "motion::builtin::snapshot")
+	defer span.End()
+
+	target := mr.maxSensorSkew
+	if target == 0 {
+		target = defaultSensorSkewTarget
+	}
+	var best *sensorSnapshot
+	for attempt := 1; attempt <= maxSnapshotAttempts; attempt++ {
+		snap, err := mr.readSnapshot(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if snap.skew <= target {
+			return snap, nil
+		}
+		if best == nil || snap.skew < best.skew {
+			best = snap
+		}
+		mr.logger.CDebugf(ctx, "sensor snapshot with skew %v missed the target of %v", snap.skew, target)
+	}
+	if mr.maxSensorSkew > 0 {
+		return nil, fmt.Errorf(
+			"sensor values were sampled at least %v apart on %d consecutive attempts, exceeding the maximum skew of %v",
+			best.skew, maxSnapshotAttempts, mr.maxSensorSkew,
+		)
+	}
+	mr.logger.CWarnf(ctx,
+		"using sensor snapshot with skew %v after %d attempts missed the target of %v, obstacles may be misplaced",
+		best.skew, maxSnapshotAttempts, target,
+	)
+	return best, nil
+}
+
+func (mr *moveRequest) readSnapshot(ctx context.Context) (*sensorSnapshot, error) {
+	snap := &sensorSnapshot{detections: map[obstacleDetector][]*viz.Object{}}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+	var earliest, latest time.Time
+	read := func(fn func() error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			err := fn()
+			sampled := start.Add(time.Since(start) / 2)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+			if earliest.IsZero() || sampled.Before(earliest) {
+				earliest = sampled
+			}
+			if sampled.After(latest) {
+				latest = sampled
+			}
+		}()
+	}
+
+	read(func() error {
+		inputs, err := mr.kinematicBase.CurrentInputs(ctx)
+		snap.kinematicBaseInputs = inputs
+		return err
+	})
+	read(func() error {
+		executionState, err := mr.kinematicBase.ExecutionState(ctx)
+		snap.executionState = executionState
+		return err
+	})
+	read(func() error {
+		inputs, _, err := mr.fsService.CurrentInputs(ctx)
+		snap.inputs = inputs
+		return err
+	})
+	for visSrvc, cameraNames := range mr.obstacleDetectors {
+		for _, camName := range cameraNames {
+			detector := obstacleDetector{visSrvc: visSrvc, camName: camName}
+			read(func() error {
+				mr.logger.CDebugf(ctx,
+					"proceeding to get detections from vision service: %s with camera: %s",
+					visSrvc.Name().ShortName(),
+					camName.ShortName(),
+				)
+				detections, err := visSrvc.GetObjectPointClouds(ctx, camName.Name, nil)
+				if err != nil {
+					// optional obstacle detectors which are unavailable are left out of the snapshot
+					return mr.degradation.degrade(camName.ShortName(), err)
+				}
+				mr.degradation.restore(camName.ShortName())
+				mu.Lock()
+				snap.detections[detector] = detections
+				mu.Unlock()
+				return nil
+			})
+		}
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	snap.skew = latest.Sub(earliest)
+	snap.timestamp = earliest.Add(snap.skew / 2)
+	return snap, nil
+}
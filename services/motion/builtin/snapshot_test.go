@@ -0,0 +1,106 @@
+package builtin
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/geo/r3"
+	geo "github.com/kellydunn/golang-geo"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/services/vision"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+	viz "go.viam.com/rdk/vision"
+)
+
+func TestSensorSnapshot(t *testing.T) {
+	ctx := context.Background()
+	origin := geo.NewPoint(0, 0)
+
+	newRequest := func(t *testing.T, detectionDelay time.Duration, extra map[string]interface{}) (*moveRequest, *atomic.Int32) {
+		t.Helper()
+		_, ms, closeFunc := CreateMoveOnGlobeTestEnvironment(ctx, t, origin, 80, spatialmath.NewZeroPose())
+		t.Cleanup(func() { closeFunc(ctx) })
+
+		calls := &atomic.Int32{}
+		injectedVis, ok := ms.(*builtIn).visionServices[vision.Named("injectedVisionSvc")].(*inject.VisionService)
+		test.That(t, ok, test.ShouldBeTrue)
+		injectedVis.GetObjectPointCloudsFunc = func(
+			ctx context.Context, cameraName string, extra map[string]interface{},
+		) ([]*viz.Object, error) {
+			calls.Add(1)
+			time.Sleep(detectionDelay)
+			return []*viz.Object{}, nil
+		}
+
+		destination := spatialmath.PoseToGeoPose(spatialmath.NewGeoPose(origin, 0), spatialmath.NewPoseFromPoint(r3.Vector{Y: 3000}))
+		req := motion.MoveOnGlobeReq{
+			ComponentName:      base.Named("test-base"),
+			Destination:        destination.Location(),
+			MovementSensorName: resource.NewName(movementsensor.API, moveSensorName),
+			MotionCfg: &motion.MotionConfiguration{
+				ObstacleDetectors: []motion.ObstacleDetectorName{
+					{VisionServiceName: vision.Named("injectedVisionSvc"), CameraName: camera.Named("test-camera")},
+				},
+			},
+			Extra: extra,
+		}
+		planExecutor, err := ms.(*builtIn).newMoveOnGlobeRequest(ctx, req, nil, 0)
+		test.That(t, err, test.ShouldBeNil)
+		mr, ok := planExecutor.(*moveRequest)
+		test.That(t, ok, test.ShouldBeTrue)
+		return mr, calls
+	}
+
+	t.Run("reads are bundled", func(t *testing.T) {
+		mr, calls := newRequest(t, 0, nil)
+		test.That(t, mr.maxSensorSkew, test.ShouldEqual, 0)
+
+		before := time.Now()
+		snap, err := mr.snapshot(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, calls.Load(), test.ShouldEqual, 1)
+		test.That(t, snap.skew, test.ShouldBeLessThanOrEqualTo, defaultSensorSkewTarget)
+		test.That(t, snap.timestamp.Before(before), test.ShouldBeFalse)
+		test.That(t, len(snap.detections), test.ShouldEqual, 1)
+		test.That(t, len(snap.kinematicBaseInputs), test.ShouldEqual, len(mr.kinematicBase.Kinematics().DoF()))
+		_, ok := snap.executionState.CurrentPoses()[mr.kinematicBase.LocalizationFrame().Name()]
+		test.That(t, ok, test.ShouldBeTrue)
+	})
+
+	t.Run("snapshots missing the target skew are retaken and then the least skewed is used", func(t *testing.T) {
+		// a detection is taken to be sampled midway through its call, so this puts it past the target skew
+		mr, calls := newRequest(t, 3*defaultSensorSkewTarget, nil)
+
+		snap, err := mr.snapshot(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, calls.Load(), test.ShouldEqual, maxSnapshotAttempts)
+		test.That(t, snap.skew, test.ShouldBeGreaterThan, defaultSensorSkewTarget)
+		test.That(t, len(snap.detections), test.ShouldEqual, 1)
+	})
+
+	t.Run("snapshots exceeding the maximum skew are retaken and then rejected", func(t *testing.T) {
+		mr, calls := newRequest(t, 50*time.Millisecond, map[string]interface{}{"max_sensor_skew_ms": 5.})
+		test.That(t, mr.maxSensorSkew, test.ShouldEqual, 5*time.Millisecond)
+
+		_, err := mr.Plan(ctx)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "exceeding the maximum skew")
+		test.That(t, calls.Load(), test.ShouldEqual, maxSnapshotAttempts)
+	})
+
+	t.Run("invalid maximum skew", func(t *testing.T) {
+		_, err := newValidatedExtra(map[string]interface{}{"max_sensor_skew_ms": -1.})
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = newValidatedExtra(map[string]interface{}{"max_sensor_skew_ms": "fast"})
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,327 @@
+package builtin
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.viam.com/utils"
+
+	"go.viam.com/rdk/components/arm"
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/robot/framesystem"
+	"go.viam.com/rdk/spatialmath"
+)
+
+const (
+	// maxEndEffectorSpeedExtraKey is the extra used to limit the Cartesian speed of the moving component during a Move.
+	maxEndEffectorSpeedExtraKey = "max_end_effector_speed_mm_per_sec"
+	// speedLimitStepMM is the furthest the end effector may travel between the interpolated inputs a speed limited segment of a
+	// trajectory is divided into.
+	speedLimitStepMM = 5.
+	// maxSpeedLimitSteps is the most steps a single segment of a trajectory is divided into.
+	maxSpeedLimitSteps = 1 << 12
+	// jointSpeedGainStepRads is the rotation of each joint used to measure how fast it moves the end effector.
+	jointSpeedGainStepRads = 1e-4
+)
+
+// maxEndEffectorSpeed returns the maximum end effector speed requested in extra, or zero if the speed is not limited.
+func maxEndEffectorSpeed(extra map[string]interface{}) (float64, error) {
+	speedRaw, ok := extra[maxEndEffectorSpeedExtraKey]
+	if !ok {
+		return 0, nil
+	}
+	speed, ok := speedRaw.(float64)
+	if !ok {
+		return 0, fmt.Errorf("could not interpret %s field as float", maxEndEffectorSpeedExtraKey)
+	}
+	if math.IsNaN(speed) || speed <= 0 {
+		return 0, fmt.Errorf("%s must be positive", maxEndEffectorSpeedExtraKey)
+	}
+	return speed, nil
+}
+
+// executeSpeedLimited executes the trajectory while limiting the Cartesian speed of the named frame. Each segment of the trajectory
+// is divided into interpolated steps along which the frame travels no further than speedLimitStepMM. Arms are moved through the steps
+// with their joint velocities capped so that the frame cannot exceed the speed at any instant, however the arm profiles its joints.
+// Every step is also given at least as long as the frame takes to travel it at the speed, which bounds the average speed of the frame
+// when it is moved by components which cannot cap their velocities. Steps which end with the robot in a safety zone are limited to the
+// speed the zone allows, and the robot stops before any step which would take it into a zone which stops it. A maximum speed of zero
+// limits the speed of the frame only within safety zones.
+func (ms *builtIn) executeSpeedLimited(
+	ctx context.Context,
+	fs referenceframe.FrameSystem,
+	trajectory motionplan.Trajectory,
+	frameName string,
+	maxSpeedMMPerSec float64,
+	mobile *mobileBase,
+) error {
+	fsInputs, resources, err := ms.fsService.CurrentInputs(ctx)
+	if err != nil {
+		return err
+	}
+	mobile.addTo(fsInputs, resources)
+
+	moving, err := movingFrames(fs, trajectory)
+	if err != nil {
+		return err
+	}
+
+	last := fsInputs
+	for _, step := range trajectory {
+		steps, err := speedLimitedSteps(fs, last, mergeInputs(last, step), frameName, resources)
+		if err != nil {
+			return err
+		}
+		for i, s := range steps {
+			// stop before the first step which would take the robot into a zone which stops it
+			if s.speed, err = zoneSpeedLimit(fs, s.inputs, moving, ms.safetyZones, maxSpeedMMPerSec); err != nil {
+				if moveErr := moveSpeedLimited(ctx, resources, last, steps[:i]); moveErr != nil {
+					return moveErr
+				}
+				return err
+			}
+		}
+		if err := moveSpeedLimited(ctx, resources, last, steps); err != nil {
+			return err
+		}
+		if len(steps) > 0 {
+			last = steps[len(steps)-1].inputs
+		}
+	}
+	return nil
+}
+
+// speedLimitedStep is a step of a speed limited segment of a trajectory.
+type speedLimitedStep struct {
+	inputs referenceframe.FrameSystemInputs
+	// distanceMM is how far the limited frame travels over the step
+	distanceMM float64
+	// speed is the speed the frame is limited to over the step, or zero if it is not limited
+	speed float64
+	// gain bounds how fast the frame moves per radian per second of the velocity of any moving arm joint over the step
+	gain float64
+}
+
+// speedLimitedSteps divides the motion of the frame system between the given inputs into steps along which the named frame travels
+// no further than speedLimitStepMM. Steps are first sized by the straight line distance the frame travels, and then subdivided until
+// none is too long, so that frames which move along an arc are measured by the length of their path.
+func speedLimitedSteps(
+	fs referenceframe.FrameSystem,
+	from, to referenceframe.FrameSystemInputs,
+	frameName string,
+	resources map[string]framesystem.InputEnabled,
+) ([]speedLimitedStep, error) {
+	fromPose, err := framePoseInWorld(fs, from, frameName)
+	if err != nil {
+		return nil, err
+	}
+	toPose, err := framePoseInWorld(fs, to, frameName)
+	if err != nil {
+		return nil, err
+	}
+	numSteps := int(math.Ceil(toPose.Point().Distance(fromPose.Point()) / speedLimitStepMM))
+	if numSteps < 1 {
+		numSteps = 1
+	}
+	for {
+		steps := make([]speedLimitedStep, 0, numSteps)
+		lastPose := fromPose
+		longest := 0.
+		for i := 1; i <= numSteps; i++ {
+			next := to
+			if i < numSteps {
+				if next, err = referenceframe.InterpolateFS(fs, from, to, float64(i)/float64(numSteps)); err != nil {
+					return nil, err
+				}
+			}
+			nextPose, err := framePoseInWorld(fs, next, frameName)
+			if err != nil {
+				return nil, err
+			}
+			distance := nextPose.Point().Distance(lastPose.Point())
+			longest = math.Max(longest, distance)
+			steps = append(steps, speedLimitedStep{inputs: next, distanceMM: distance})
+			lastPose = nextPose
+		}
+		if longest > speedLimitStepMM && numSteps < maxSpeedLimitSteps {
+			numSteps = int(math.Min(math.Ceil(float64(numSteps)*longest/speedLimitStepMM), maxSpeedLimitSteps))
+			continue
+		}
+
+		last := from
+		for i := range steps {
+			if steps[i].gain, err = jointSpeedGain(fs, last, steps[i].inputs, frameName, resources); err != nil {
+				return nil, err
+			}
+			last = steps[i].inputs
+		}
+		return steps, nil
+	}
+}
+
+// jointSpeedGain returns an upper bound on how fast the named frame moves, in mm per second, for each radian per second of the
+// velocity of every joint of the arms which move between the given inputs. The frame moves no faster than the sum of how far it is
+// moved by a small rotation of each joint, which is evaluated at both ends of the step.
+func jointSpeedGain(
+	fs referenceframe.FrameSystem,
+	from, to referenceframe.FrameSystemInputs,
+	frameName string,
+	resources map[string]framesystem.InputEnabled,
+) (float64, error) {
+	gain := 0.
+	for _, inputs := range []referenceframe.FrameSystemInputs{from, to} {
+		pose, err := framePoseInWorld(fs, inputs, frameName)
+		if err != nil {
+			return 0, err
+		}
+		sum := 0.
+		for name, joints := range to {
+			if _, ok := resources[name].(arm.Arm); !ok || inputsEqual(joints, from[name]) {
+				continue
+			}
+			for i := range inputs[name] {
+				perturbed := mergeInputs(inputs, nil)
+				perturbed[name] = append([]referenceframe.Input{}, inputs[name]...)
+				perturbed[name][i].Value += jointSpeedGainStepRads
+				perturbedPose, err := framePoseInWorld(fs, perturbed, frameName)
+				if err != nil {
+					return 0, err
+				}
+				sum += perturbedPose.Point().Distance(pose.Point()) / jointSpeedGainStepRads
+			}
+		}
+		gain = math.Max(gain, sum)
+	}
+	return gain, nil
+}
+
+// moveSpeedLimited moves the robot from the given inputs through the steps. Consecutive steps limited to the same speed in which only
+// a single arm moves are given to the arm together, so that it does not stop between them.
+func moveSpeedLimited(
+	ctx context.Context,
+	resources map[string]framesystem.InputEnabled,
+	last referenceframe.FrameSystemInputs,
+	steps []speedLimitedStep,
+) error {
+	for len(steps) > 0 {
+		run := 1
+		if name, ok := soleMovingArm(resources, last, steps[0].inputs); ok {
+			for run < len(steps) && steps[run].speed == steps[0].speed {
+				if other, ok := soleMovingArm(resources, steps[run-1].inputs, steps[run].inputs); !ok || other != name {
+					break
+				}
+				run++
+			}
+		}
+
+		start := time.Now()
+		var distance, maxVelRads float64
+		for _, step := range steps[:run] {
+			distance += step.distanceMM
+			if step.speed > 0 && step.gain > 0 {
+				if vel := step.speed / step.gain; maxVelRads == 0 || vel < maxVelRads {
+					maxVelRads = vel
+				}
+			}
+		}
+		for name, inputs := range steps[run-1].inputs {
+			if len(inputs) == 0 || inputsEqual(inputs, last[name]) {
+				continue
+			}
+			r, ok := resources[name]
+			if !ok {
+				return fmt.Errorf("plan had step for resource %s but no resource with that name found in framesystem", name)
+			}
+			if a, ok := r.(arm.Arm); ok && maxVelRads > 0 {
+				positions := make([][]referenceframe.Input, 0, run)
+				for _, step := range steps[:run] {
+					positions = append(positions, step.inputs[name])
+				}
+				if err := a.MoveThroughJointPositions(ctx, positions, &arm.MoveOptions{MaxVelRads: maxVelRads}, nil); err != nil {
+					if stopErr := a.Stop(ctx, nil); stopErr != nil {
+						return errors.Wrap(err, stopErr.Error())
+					}
+					return err
+				}
+				continue
+			}
+			if err := goToInputs(ctx, r, inputs); err != nil {
+				return err
+			}
+		}
+		if speed := steps[0].speed; speed > 0 {
+			minDuration := time.Duration(distance / speed * float64(time.Second))
+			if remaining := minDuration - time.Since(start); remaining > 0 {
+				if !utils.SelectContextOrWait(ctx, remaining) {
+					return ctx.Err()
+				}
+			}
+		}
+		last, steps = steps[run-1].inputs, steps[run:]
+	}
+	return nil
+}
+
+// soleMovingArm returns the name of the only resource which moves between the given inputs if it is an arm.
+func soleMovingArm(
+	resources map[string]framesystem.InputEnabled,
+	from, to referenceframe.FrameSystemInputs,
+) (string, bool) {
+	moved := ""
+	for name, inputs := range to {
+		if len(inputs) == 0 || inputsEqual(inputs, from[name]) {
+			continue
+		}
+		if moved != "" {
+			return "", false
+		}
+		moved = name
+	}
+	if _, ok := resources[moved].(arm.Arm); !ok {
+		return "", false
+	}
+	return moved, true
+}
+
+// framePoseInWorld returns the pose of the named frame in the world frame for the given inputs.
+func framePoseInWorld(fs referenceframe.FrameSystem, inputs referenceframe.FrameSystemInputs, frameName string) (spatialmath.Pose, error) {
+	tf, err := fs.Transform(inputs, referenceframe.NewPoseInFrame(frameName, spatialmath.NewZeroPose()), referenceframe.World)
+	if err != nil {
+		return nil, err
+	}
+	pif, ok := tf.(*referenceframe.PoseInFrame)
+	if !ok {
+		return nil, fmt.Errorf("could not transform frame %s into the world frame", frameName)
+	}
+	return pif.Pose(), nil
+}
+
+// mergeInputs returns a copy of the base inputs with the inputs of every frame in the step replaced.
+func mergeInputs(base, step referenceframe.FrameSystemInputs) referenceframe.FrameSystemInputs {
+	merged := make(referenceframe.FrameSystemInputs, len(base))
+	for name, inputs := range base {
+		merged[name] = inputs
+	}
+	for name, inputs := range step {
+		if len(inputs) > 0 {
+			merged[name] = inputs
+		}
+	}
+	return merged
+}
+
+func inputsEqual(a, b []referenceframe.Input) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
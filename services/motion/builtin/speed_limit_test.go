@@ -0,0 +1,92 @@
+package builtin
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/arm"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/robot/framesystem"
+	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestEndEffectorSpeedLimit(t *testing.T) {
+	ctx := context.Background()
+	goal := spatialmath.NewPoseFromPoint(r3.Vector{X: 0, Y: -30, Z: -50})
+
+	t.Run("invalid speed limits are rejected", func(t *testing.T) {
+		_, err := maxEndEffectorSpeed(map[string]interface{}{maxEndEffectorSpeedExtraKey: -1.})
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = maxEndEffectorSpeed(map[string]interface{}{maxEndEffectorSpeedExtraKey: "fast"})
+		test.That(t, err, test.ShouldNotBeNil)
+		speed, err := maxEndEffectorSpeed(nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, speed, test.ShouldEqual, 0)
+	})
+
+	t.Run("execution is slowed to the maximum speed", func(t *testing.T) {
+		ms, teardown := setupMotionServiceFromConfig(t, "../data/moving_arm.json")
+		defer teardown()
+
+		maxSpeed := 200.
+		start := time.Now()
+		_, err := ms.Move(ctx, motion.MoveReq{
+			ComponentName: arm.Named("pieceArm"),
+			Destination:   referenceframe.NewPoseInFrame("pieceArm", goal),
+			Extra:         map[string]interface{}{maxEndEffectorSpeedExtraKey: maxSpeed},
+		})
+		test.That(t, err, test.ShouldBeNil)
+		minDuration := time.Duration(goal.Point().Norm() / maxSpeed * float64(time.Second))
+		test.That(t, time.Since(start), test.ShouldBeGreaterThanOrEqualTo, minDuration)
+	})
+	t.Run("steps follow the path of the frame and cap the joint velocities of arms", func(t *testing.T) {
+		// a single joint swings a tip 500mm from its axis through three quarters of a turn
+		fs := referenceframe.NewEmptyFrameSystem("test")
+		joint, err := referenceframe.NewRotationalFrame(
+			"joint", spatialmath.R4AA{RZ: 1}, referenceframe.Limit{Min: -2 * math.Pi, Max: 2 * math.Pi},
+		)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, fs.AddFrame(joint, fs.World()), test.ShouldBeNil)
+		tip, err := referenceframe.NewStaticFrame("tip", spatialmath.NewPoseFromPoint(r3.Vector{X: 500}))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, fs.AddFrame(tip, joint), test.ShouldBeNil)
+
+		var positions [][]referenceframe.Input
+		var options *arm.MoveOptions
+		injectedArm := &inject.Arm{}
+		injectedArm.MoveThroughJointPositionsFunc = func(
+			ctx context.Context, p [][]referenceframe.Input, o *arm.MoveOptions, extra map[string]interface{},
+		) error {
+			positions, options = p, o
+			return nil
+		}
+		resources := map[string]framesystem.InputEnabled{"joint": injectedArm}
+
+		from := referenceframe.FrameSystemInputs{"joint": {{Value: 0}}}
+		to := referenceframe.FrameSystemInputs{"joint": {{Value: 1.5 * math.Pi}}}
+		steps, err := speedLimitedSteps(fs, from, to, "tip", resources)
+		test.That(t, err, test.ShouldBeNil)
+		var distance float64
+		for _, step := range steps {
+			test.That(t, step.distanceMM, test.ShouldBeLessThanOrEqualTo, speedLimitStepMM)
+			test.That(t, step.gain, test.ShouldAlmostEqual, 500, 1)
+			distance += step.distanceMM
+		}
+		test.That(t, distance, test.ShouldAlmostEqual, 500*1.5*math.Pi, 1)
+
+		steps = steps[:4]
+		for i := range steps {
+			steps[i].speed = 1000
+		}
+		test.That(t, moveSpeedLimited(ctx, resources, from, steps), test.ShouldBeNil)
+		test.That(t, len(positions), test.ShouldEqual, len(steps))
+		test.That(t, options.MaxVelRads, test.ShouldAlmostEqual, 2, 0.01)
+	})
+}
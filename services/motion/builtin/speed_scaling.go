@@ -0,0 +1,31 @@
+package builtin
+
+import (
+	"go.viam.com/rdk/components/base/kinematicbase"
+)
+
+// speedScalingOptions returns the options with which the base is slowed near obstacles, or nil if the configuration does not slow it.
+// The obstacles which the base is slowed near are set once the move request which reads them is created.
+func (vmc *validatedMotionConfiguration) speedScalingOptions() *kinematicbase.SpeedScalingOptions {
+	if vmc.slowdownDistanceMM <= 0 {
+		return nil
+	}
+	opts := kinematicbase.NewSpeedScalingOptions(nil, vmc.slowdownDistanceMM)
+	if vmc.minSpeedScale > 0 {
+		opts.MinSpeedScale = vmc.minSpeedScale
+	}
+	return opts
+}
+
+// useSpeedScaling has the base slow down near the transient detections of the request, if the configuration slows it. The local
+// planner chooses its own velocities around obstacles, so the base is not slowed if it is used.
+func (mr *moveRequest) useSpeedScaling(opts kinematicbase.Options) {
+	if opts.SpeedScaling == nil {
+		return
+	}
+	if mr.localPlanning {
+		mr.logger.Warn("SlowdownDistanceMM is ignored since the local planner is used")
+		return
+	}
+	opts.SpeedScaling.Obstacles = mr.localObstacles
+}
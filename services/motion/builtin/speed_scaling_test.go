@@ -0,0 +1,36 @@
+package builtin
+
+import (
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/services/motion"
+)
+
+func TestSpeedScalingOptions(t *testing.T) {
+	vmc, err := newValidatedMotionCfg(&motion.MotionConfiguration{}, requestTypeMoveOnMap)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, vmc.speedScalingOptions(), test.ShouldBeNil)
+
+	vmc, err = newValidatedMotionCfg(&motion.MotionConfiguration{SlowdownDistanceMM: 800}, requestTypeMoveOnMap)
+	test.That(t, err, test.ShouldBeNil)
+	opts := vmc.speedScalingOptions()
+	test.That(t, opts, test.ShouldNotBeNil)
+	test.That(t, opts.SlowdownDistanceMM, test.ShouldEqual, 800)
+	test.That(t, opts.MinSpeedScale, test.ShouldEqual, 0.25)
+
+	vmc, err = newValidatedMotionCfg(&motion.MotionConfiguration{SlowdownDistanceMM: 800, MinSpeedScale: 0.5}, requestTypeMoveOnGlobe)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, vmc.speedScalingOptions().MinSpeedScale, test.ShouldEqual, 0.5)
+	test.That(t, kbOptionsFromCfg(vmc, validatedExtra{}).SpeedScaling, test.ShouldNotBeNil)
+
+	for _, cfg := range []*motion.MotionConfiguration{
+		{SlowdownDistanceMM: -1},
+		{SlowdownDistanceMM: 800, MinSpeedScale: -0.5},
+		{SlowdownDistanceMM: 800, MinSpeedScale: 2},
+	} {
+		_, err := newValidatedMotionCfg(cfg, requestTypeMoveOnMap)
+		test.That(t, err, test.ShouldNotBeNil)
+	}
+}
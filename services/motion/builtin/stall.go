@@ -0,0 +1,147 @@
+package builtin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/benbjohnson/clock"
+
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+const (
+	// stallTimeoutExtraKey is the key of extra setting how many seconds a base commanded to move may make no progress before its
+	// execution fails with a StalledError, such as when it is stuck against a curb. Without it, stalls are not detected.
+	stallTimeoutExtraKey = "stall_timeout_s"
+	// stallDistanceExtraKey is the key of extra setting how far in mm a base must move within the stall timeout to have made progress.
+	stallDistanceExtraKey = "stall_distance_mm"
+	// defaultStallDistanceMM is how far a base must move within the stall timeout if the request does not say, which is well beyond
+	// the noise of most localizers.
+	defaultStallDistanceMM = 50.
+	// stallAngleDegs is how far a base must turn within the stall timeout to have made progress, so that a base spinning in place
+	// is not stalled.
+	stallAngleDegs = 5.
+)
+
+// stallConfig is how long a base may make no progress for before it is stalled, and how far it must move to make progress.
+type stallConfig struct {
+	timeout    time.Duration
+	distanceMM float64
+}
+
+// parseStall parses the stall detection of a request from extra, returning a zero timeout if stalls are not detected.
+func parseStall(extra map[string]interface{}) (stallConfig, error) {
+	cfg := stallConfig{distanceMM: defaultStallDistanceMM}
+	if raw, ok := extra[stallTimeoutExtraKey]; ok {
+		seconds, ok := raw.(float64)
+		if !ok {
+			return stallConfig{}, fmt.Errorf("could not interpret %s field as float", stallTimeoutExtraKey)
+		}
+		if seconds <= 0 {
+			return stallConfig{}, fmt.Errorf("%s must be positive", stallTimeoutExtraKey)
+		}
+		cfg.timeout = time.Duration(seconds * float64(time.Second))
+	}
+	if raw, ok := extra[stallDistanceExtraKey]; ok {
+		distance, ok := raw.(float64)
+		if !ok {
+			return stallConfig{}, fmt.Errorf("could not interpret %s field as float", stallDistanceExtraKey)
+		}
+		if distance <= 0 {
+			return stallConfig{}, fmt.Errorf("%s must be positive", stallDistanceExtraKey)
+		}
+		cfg.distanceMM = distance
+	}
+	return cfg, nil
+}
+
+// IsStalledError returns whether or not the given error is a StalledError.
+func IsStalledError(err error) bool {
+	var errArt *StalledError
+	return errors.As(err, &errArt)
+}
+
+// StalledError is returned when a base commanded to move along its plan makes no progress for the stall timeout of the request, which
+// distinguishes a base which is stuck from one which is still moving. Its execution fails with it as the reason.
+type StalledError struct {
+	Component  string
+	Timeout    time.Duration
+	DistanceMM float64
+}
+
+func (e *StalledError) Error() string {
+	return fmt.Sprintf("base %s stalled: moved less than %.0fmm in %v while commanded to move", e.Component, e.DistanceMM, e.Timeout)
+}
+
+// stallDetector watches the progress of a base commanded to move. It is only checked by the position replanner, and so is not
+// safe for concurrent use. A nil stallDetector never detects a stall.
+type stallDetector struct {
+	cfg       stallConfig
+	component string
+	clock     clock.Clock
+
+	// anchor is the pose the base last made progress from, and since is when it did, or when it was last commanded to move
+	anchor spatialmath.Pose
+	since  time.Time
+}
+
+// newStallDetector returns a stallDetector for the named base timed by the given clock, or by the wall clock if it is nil. It returns
+// nil if stalls are not detected.
+func newStallDetector(cfg stallConfig, component string, clk clock.Clock) *stallDetector {
+	if cfg.timeout <= 0 {
+		return nil
+	}
+	if clk == nil {
+		clk = clock.New()
+	}
+	return &stallDetector{cfg: cfg, component: component, clock: clk}
+}
+
+// check records the pose of the base and whether it is commanded to move, returning a StalledError once it has been commanded to
+// move for the full timeout without moving or turning far enough. Time the base spends stopped, such as while it is held, does not
+// count towards a stall.
+func (d *stallDetector) check(pose spatialmath.Pose, moving bool) error {
+	if d == nil {
+		return nil
+	}
+	if !moving || d.anchor == nil || d.progressed(pose) {
+		d.anchor = pose
+		d.since = d.clock.Now()
+		return nil
+	}
+	if d.clock.Since(d.since) < d.cfg.timeout {
+		return nil
+	}
+	return &StalledError{Component: d.component, Timeout: d.cfg.timeout, DistanceMM: d.cfg.distanceMM}
+}
+
+// progressed returns whether the base has moved or turned far enough from where it last made progress.
+func (d *stallDetector) progressed(pose spatialmath.Pose) bool {
+	if pose.Point().Distance(d.anchor.Point()) >= d.cfg.distanceMM {
+		return true
+	}
+	turned := spatialmath.OrientationBetween(d.anchor.Orientation(), pose.Orientation()).AxisAngles().Theta
+	return utils.RadToDeg(turned) >= stallAngleDegs
+}
+
+// checkStall checks the base for a stall at its position in the given execution state. If whether the base is moving cannot be read,
+// it is treated as stopped, so that a stall is never reported without cause.
+func (mr *moveRequest) checkStall(ctx context.Context, executionState motionplan.ExecutionState) error {
+	if mr.stall == nil {
+		return nil
+	}
+	currentPosition, ok := executionState.CurrentPoses()[mr.kinematicBase.LocalizationFrame().Name()]
+	if !ok {
+		return errors.New("executionState.CurrentPoses() does not contain an entry for the LocalizationFrame")
+	}
+	moving, err := mr.kinematicBase.IsMoving(ctx)
+	if err != nil {
+		mr.logger.CDebugf(ctx, "could not tell whether the base is moving, not checking it for a stall: %v", err)
+		moving = false
+	}
+	return mr.stall.check(currentPosition.Pose(), moving)
+}
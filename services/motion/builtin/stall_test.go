@@ -0,0 +1,82 @@
+package builtin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/spatialmath"
+)
+
+func TestStallDetector(t *testing.T) {
+	t.Run("parsed from extra", func(t *testing.T) {
+		valExtra, err := newValidatedExtra(map[string]interface{}{})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, newStallDetector(valExtra.stall, "base", nil), test.ShouldBeNil)
+
+		valExtra, err = newValidatedExtra(map[string]interface{}{stallTimeoutExtraKey: 2.5, stallDistanceExtraKey: 20.})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, valExtra.stall, test.ShouldResemble, stallConfig{timeout: 2500 * time.Millisecond, distanceMM: 20})
+
+		valExtra, err = newValidatedExtra(map[string]interface{}{stallTimeoutExtraKey: 2.})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, valExtra.stall.distanceMM, test.ShouldEqual, defaultStallDistanceMM)
+
+		_, err = newValidatedExtra(map[string]interface{}{stallTimeoutExtraKey: 0.})
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = newValidatedExtra(map[string]interface{}{stallTimeoutExtraKey: "2"})
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = newValidatedExtra(map[string]interface{}{stallDistanceExtraKey: -1.})
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("a nil detector never stalls", func(t *testing.T) {
+		var d *stallDetector
+		test.That(t, d.check(spatialmath.NewZeroPose(), true), test.ShouldBeNil)
+	})
+
+	clk := clock.NewMock()
+	newDetector := func() *stallDetector {
+		return newStallDetector(stallConfig{timeout: 3 * time.Second, distanceMM: 50}, "base", clk)
+	}
+	start := spatialmath.NewZeroPose()
+
+	t.Run("stalls once it makes no progress for the timeout", func(t *testing.T) {
+		d := newDetector()
+		test.That(t, d.check(start, true), test.ShouldBeNil)
+		clk.Add(2 * time.Second)
+		// creeping is not progress
+		test.That(t, d.check(spatialmath.NewPoseFromPoint(r3.Vector{X: 10}), true), test.ShouldBeNil)
+		clk.Add(time.Second)
+		err := d.check(spatialmath.NewPoseFromPoint(r3.Vector{X: 20}), true)
+		test.That(t, IsStalledError(err), test.ShouldBeTrue)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "base")
+	})
+
+	t.Run("moving or turning is progress", func(t *testing.T) {
+		d := newDetector()
+		test.That(t, d.check(start, true), test.ShouldBeNil)
+		clk.Add(2 * time.Second)
+		test.That(t, d.check(spatialmath.NewPoseFromPoint(r3.Vector{Y: 100}), true), test.ShouldBeNil)
+		clk.Add(2 * time.Second)
+		turned := spatialmath.NewPose(r3.Vector{Y: 100}, &spatialmath.OrientationVectorDegrees{OZ: 1, Theta: 30})
+		test.That(t, d.check(turned, true), test.ShouldBeNil)
+		clk.Add(2 * time.Second)
+		test.That(t, d.check(turned, true), test.ShouldBeNil)
+	})
+
+	t.Run("time stopped does not count", func(t *testing.T) {
+		d := newDetector()
+		test.That(t, d.check(start, true), test.ShouldBeNil)
+		clk.Add(2 * time.Second)
+		test.That(t, d.check(start, false), test.ShouldBeNil)
+		clk.Add(time.Minute)
+		test.That(t, d.check(start, false), test.ShouldBeNil)
+		test.That(t, d.check(start, true), test.ShouldBeNil)
+		clk.Add(2 * time.Second)
+		test.That(t, d.check(start, true), test.ShouldBeNil)
+	})
+}
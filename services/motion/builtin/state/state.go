@@ -12,6 +12,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
 	"go.viam.com/utils"
 	"golang.org/x/exp/maps"
 
@@ -35,6 +36,23 @@ type ExecuteResponse struct {
 	Replan bool
 	// Set if Replan is true, describes why replanning was triggered
 	ReplanReason string
+	// Set if Replan is true, categorizes ReplanReason, and is taken to be motion.ReplanReasonUnknown if it is not set
+	ReplanReasonCode motion.ReplanReasonCode
+}
+
+// A Recoverer is a PlannerExecutor which can attempt to recover when planning or replanning with it fails, such as by backing the
+// component away from whatever it is stuck against, so that its execution does not fail at once.
+type Recoverer interface {
+	// Recover performs the next of its recovery behaviors in response to the given planning failure, after which planning is tried
+	// again. It returns false once it has no recovery behaviors left to perform, in which case the execution fails.
+	Recover(ctx context.Context, cause error) bool
+}
+
+// A Finisher is a PlannerExecutor which holds resources shared by the replans of its execution, which it releases once the
+// execution ends.
+type Finisher interface {
+	// Finish is called once the execution ends, however it ends, on the PlannerExecutor which last planned it.
+	Finish()
 }
 
 // PlannerExecutorConstructor creates a PlannerExecutor
@@ -75,11 +93,12 @@ type stateUpdateMsg struct {
 // execution has exprienced & the waitGroup & cancelFunc
 // required to shut down an execution's goroutine.
 type stateExecution struct {
-	id            motion.ExecutionID
-	componentName resource.Name
-	waitGroup     *sync.WaitGroup
-	cancelFunc    context.CancelFunc
-	history       []motion.PlanWithStatus
+	id             motion.ExecutionID
+	componentName  resource.Name
+	waitGroup      *sync.WaitGroup
+	cancelFunc     context.CancelFunc
+	replanRequests chan string
+	history        []motion.PlanWithStatus
 }
 
 func (e *stateExecution) stop() {
@@ -107,6 +126,9 @@ type execution[R any] struct {
 	componentName              resource.Name
 	req                        R
 	plannerExecutorConstructor PlannerExecutorConstructor[R]
+	reservation                *resource.Reservation
+	// replanRequests receives the reasons callers request the execution replan for
+	replanRequests chan string
 }
 
 type planWithExecutor struct {
@@ -116,18 +138,27 @@ type planWithExecutor struct {
 
 // NewPlan creates a new motion.Plan from an execution & returns an error if one was not able to be created.
 func (e *execution[R]) newPlanWithExecutor(ctx context.Context, seedPlan motionplan.Plan, replanCount int) (planWithExecutor, error) {
+	ctx, span := trace.StartSpan(ctx, "motion::state::Plan")
+	defer span.End()
+	span.AddAttributes(trace.StringAttribute("execution_id", e.id.String()), trace.Int64Attribute("replan_count", int64(replanCount)))
+
 	pe, err := e.plannerExecutorConstructor(e.cancelCtx, e.req, seedPlan, replanCount)
 	if err != nil {
+		setSpanError(span, err)
 		return planWithExecutor{}, err
 	}
 	plan, err := pe.Plan(ctx)
 	if err != nil {
-		return planWithExecutor{}, err
+		setSpanError(span, err)
+		// the executor is returned so that it can be finished if the execution ends
+		return planWithExecutor{executor: pe}, err
 	}
+	planID := uuid.New()
+	span.AddAttributes(trace.StringAttribute("plan_id", planID.String()))
 	return planWithExecutor{
 		plan: motion.PlanWithMetadata{
 			Plan:          plan,
-			ID:            uuid.New(),
+			ID:            planID,
 			ExecutionID:   e.id,
 			ComponentName: e.componentName,
 			AnchorGeoPose: pe.AnchorGeoPose(),
@@ -136,11 +167,72 @@ func (e *execution[R]) newPlanWithExecutor(ctx context.Context, seedPlan motionp
 	}, nil
 }
 
+// execute executes the given plan within a span carrying the IDs of the execution and plan.
+func (e *execution[R]) execute(ctx context.Context, pwe planWithExecutor) (ExecuteResponse, error) {
+	ctx, span := trace.StartSpan(ctx, "motion::state::Execute")
+	defer span.End()
+	span.AddAttributes(
+		trace.StringAttribute("execution_id", e.id.String()),
+		trace.StringAttribute("plan_id", pwe.plan.ID.String()),
+	)
+	resp, err := e.executeUntilReplanRequested(ctx, pwe)
+	if err != nil {
+		setSpanError(span, err)
+	} else if resp.Replan {
+		span.AddAttributes(trace.StringAttribute("replan_reason", resp.ReplanReason))
+	}
+	return resp, err
+}
+
+// executeUntilReplanRequested executes the given plan, cancelling its execution if a caller requests a replan before it finishes.
+func (e *execution[R]) executeUntilReplanRequested(ctx context.Context, pwe planWithExecutor) (ExecuteResponse, error) {
+	planCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	requested := make(chan string, 1)
+	var waitGroup sync.WaitGroup
+	waitGroup.Add(1)
+	utils.PanicCapturingGo(func() {
+		defer waitGroup.Done()
+		select {
+		case reason := <-e.replanRequests:
+			requested <- reason
+			cancel()
+		case <-planCtx.Done():
+		}
+	})
+	resp, err := pwe.executor.Execute(planCtx, pwe.plan.Plan)
+	cancel()
+	waitGroup.Wait()
+
+	select {
+	case reason := <-requested:
+		// the execution is only replanned if it was cancelled by the request, and not if it finished or was stopped
+		if ctx.Err() == nil && (resp.Replan || errors.Is(err, context.Canceled)) {
+			return ExecuteResponse{
+				Replan:           true,
+				ReplanReason:     "replan requested: " + reason,
+				ReplanReasonCode: motion.ReplanReasonManual,
+			}, nil
+		}
+	default:
+	}
+	return resp, err
+}
+
+func setSpanError(span *trace.Span, err error) {
+	span.SetStatus(trace.Status{Code: trace.StatusCodeUnknown, Message: err.Error()})
+}
+
 // Start starts an execution with a given plan.
 func (e *execution[R]) start(ctx context.Context) error {
 	var replanCount int
 	originalPlanWithExecutor, err := e.newPlanWithExecutor(ctx, nil, replanCount)
+	// the executor whose plan failed may recover from the failure before planning is tried again
+	for err != nil && e.attemptRecovery(ctx, originalPlanWithExecutor.executor, err) {
+		originalPlanWithExecutor, err = e.newPlanWithExecutor(ctx, nil, replanCount)
+	}
 	if err != nil {
+		finish(originalPlanWithExecutor.executor)
 		return err
 	}
 	e.notifyStateNewExecution(e.toStateExecution(), originalPlanWithExecutor.plan, time.Now())
@@ -158,8 +250,18 @@ func (e *execution[R]) start(ctx context.Context) error {
 		defer e.state.waitGroup.Done()
 		defer e.waitGroup.Done()
 		defer e.cancelFunc()
+		defer e.reservation.Release()
+
+		// the span of the execution outlives the request which started it, under whose span it is traced
+		execCtx, span := trace.StartSpan(e.cancelCtx, "motion::state::Execution")
+		defer span.End()
+		span.AddAttributes(
+			trace.StringAttribute("execution_id", e.id.String()),
+			trace.StringAttribute("component", e.componentName.String()),
+		)
 
 		lastPWE := originalPlanWithExecutor
+		defer func() { finish(lastPWE.executor) }()
 		// Exit conditions of this loop:
 		// 1. The execution's context was cancelled, which happens if the state's Stop() was called or
 		// StopExecutionByResource was called for this resource
@@ -167,7 +269,7 @@ func (e *execution[R]) start(ctx context.Context) error {
 		// 3. the execution failed
 		// 4. replanning failed
 		for {
-			resp, err := lastPWE.executor.Execute(e.cancelCtx, lastPWE.plan.Plan)
+			resp, err := e.execute(execCtx, lastPWE)
 
 			switch {
 			// stopped
@@ -187,8 +289,15 @@ func (e *execution[R]) start(ctx context.Context) error {
 
 			// replan
 			default:
+				if e.state.replanObserver != nil {
+					e.state.replanObserver(replanReasonCode(resp))
+				}
 				replanCount++
-				newPWE, err := e.newPlanWithExecutor(e.cancelCtx, lastPWE.plan.Plan, replanCount)
+				newPWE, err := e.newPlanWithExecutor(execCtx, lastPWE.plan.Plan, replanCount)
+				// the previous executor may recover from the failure before replanning is tried again
+				for err != nil && e.attemptRecovery(execCtx, lastPWE.executor, err) {
+					newPWE, err = e.newPlanWithExecutor(execCtx, lastPWE.plan.Plan, replanCount)
+				}
 				// replan failed
 				if err != nil {
 					msg := "failed to replan for execution %s and component: %s, " +
@@ -200,7 +309,7 @@ func (e *execution[R]) start(ctx context.Context) error {
 					return
 				}
 
-				e.notifyStateReplan(lastPWE.plan, resp.ReplanReason, newPWE.plan, time.Now())
+				e.notifyStateReplan(lastPWE.plan, resp, newPWE.plan, time.Now())
 				lastPWE = newPWE
 			}
 		}
@@ -209,12 +318,32 @@ func (e *execution[R]) start(ctx context.Context) error {
 	return nil
 }
 
+// attemptRecovery asks the given executor to recover from the failure to plan, returning whether planning should be tried again.
+func (e *execution[R]) attemptRecovery(ctx context.Context, pe PlannerExecutor, cause error) bool {
+	recoverer, ok := pe.(Recoverer)
+	if !ok || ctx.Err() != nil {
+		return false
+	}
+	ctx, span := trace.StartSpan(ctx, "motion::state::Recover")
+	defer span.End()
+	span.AddAttributes(trace.StringAttribute("execution_id", e.id.String()), trace.StringAttribute("cause", cause.Error()))
+	return recoverer.Recover(ctx, cause)
+}
+
+// finish lets the executor which last planned an execution release what it holds once the execution has ended.
+func finish(pe PlannerExecutor) {
+	if finisher, ok := pe.(Finisher); ok {
+		finisher.Finish()
+	}
+}
+
 func (e *execution[R]) toStateExecution() stateExecution {
 	return stateExecution{
-		id:            e.id,
-		componentName: e.componentName,
-		waitGroup:     e.waitGroup,
-		cancelFunc:    e.cancelFunc,
+		id:             e.id,
+		componentName:  e.componentName,
+		waitGroup:      e.waitGroup,
+		cancelFunc:     e.cancelFunc,
+		replanRequests: e.replanRequests,
 	}
 }
 
@@ -230,7 +359,21 @@ func (e *execution[R]) notifyStateNewExecution(execution stateExecution, plan mo
 	})
 }
 
-func (e *execution[R]) notifyStateReplan(lastPlan motion.PlanWithMetadata, reason string, newPlan motion.PlanWithMetadata, time time.Time) {
+// replanReasonCode returns the reason code the plan history reports for the replan the response calls for.
+func replanReasonCode(resp ExecuteResponse) motion.ReplanReasonCode {
+	if resp.ReplanReasonCode == "" {
+		return motion.ReplanReasonUnknown
+	}
+	return resp.ReplanReasonCode
+}
+
+func (e *execution[R]) notifyStateReplan(
+	lastPlan motion.PlanWithMetadata,
+	resp ExecuteResponse,
+	newPlan motion.PlanWithMetadata,
+	time time.Time,
+) {
+	reason, code := resp.ReplanReason, replanReasonCode(resp)
 	e.state.mu.Lock()
 	defer e.state.mu.Unlock()
 	// NOTE: We hold the lock for both updateStateNewExecution & updateStateNewPlan to ensure no readers
@@ -239,7 +382,7 @@ func (e *execution[R]) notifyStateReplan(lastPlan motion.PlanWithMetadata, reaso
 		componentName: e.componentName,
 		executionID:   e.id,
 		planID:        lastPlan.ID,
-		planStatus:    motion.PlanStatus{State: motion.PlanStateFailed, Timestamp: time, Reason: &reason},
+		planStatus:    motion.PlanStatus{State: motion.PlanStateFailed, Timestamp: time, Reason: &reason, ReplanReason: code},
 	})
 
 	e.state.updateStateNewPlan(planMsg{
@@ -289,6 +432,10 @@ type State struct {
 	cancelFunc context.CancelFunc
 	logger     logging.Logger
 	ttl        time.Duration
+	// reservations holds the reservations of the robot, in which components are reserved while they are executing
+	reservations *resource.Reservations
+	// replanObserver is called with the reason code of every replan an execution calls for
+	replanObserver func(motion.ReplanReasonCode)
 	// mu protects the componentStateByComponent
 	mu                        sync.RWMutex
 	componentStateByComponent map[resource.Name]componentState
@@ -297,10 +444,13 @@ type State struct {
 // NewState creates a new state.
 // Takes a [TTL](https://en.wikipedia.org/wiki/Time_to_live)
 // and an interval to delete any State data that is older than
-// the TTL.
+// the TTL, and the reservations of the robot in which components
+// are reserved while they are executing. Nothing is reserved if they
+// are nil.
 func NewState(
 	ttl time.Duration,
 	ttlCheckInterval time.Duration,
+	reservations *resource.Reservations,
 	logger logging.Logger,
 ) (*State, error) {
 	if ttl == 0 {
@@ -326,6 +476,7 @@ func NewState(
 		waitGroup:                 &sync.WaitGroup{},
 		componentStateByComponent: make(map[resource.Name]componentState),
 		ttl:                       ttl,
+		reservations:              reservations,
 		logger:                    logger,
 	}
 	s.waitGroup.Add(1)
@@ -351,6 +502,12 @@ func NewState(
 	return &s, nil
 }
 
+// ObserveReplans has observe called with the reason code of every replan an execution calls for, which is the code the plan
+// history of the execution reports for it. It must be called before any execution is started.
+func (s *State) ObserveReplans(observe func(motion.ReplanReasonCode)) {
+	s.replanObserver = observe
+}
+
 // StartExecution creates a new execution from a state.
 func StartExecution[R any](
 	ctx context.Context,
@@ -368,9 +525,16 @@ func StartExecution[R any](
 	}
 
 	// the state being cancelled should cause all executions derived from that state to also be cancelled
+	// the component is reserved for the duration of the execution so that other clients can't fight the motion service for it
+	id := uuid.New()
+	reservation := s.reservations.Reserve(componentName, fmt.Sprintf("motion execution %s", id))
+
 	cancelCtx, cancelFunc := context.WithCancel(s.cancelCtx)
+	if span := trace.FromContext(ctx); span != nil {
+		cancelCtx = trace.NewContext(cancelCtx, span)
+	}
 	e := execution[R]{
-		id:                         uuid.New(),
+		id:                         id,
 		state:                      s,
 		cancelCtx:                  cancelCtx,
 		cancelFunc:                 cancelFunc,
@@ -379,9 +543,13 @@ func StartExecution[R any](
 		req:                        req,
 		componentName:              componentName,
 		plannerExecutorConstructor: plannerExecutorConstructor,
+		reservation:                reservation,
+		replanRequests:             make(chan string, 1),
 	}
 
 	if err := e.start(ctx); err != nil {
+		reservation.Release()
+		cancelFunc()
 		return uuid.Nil, err
 	}
 
@@ -419,6 +587,39 @@ func (s *State) StopExecutionByResource(componentName resource.Name) error {
 	return nil
 }
 
+// ReplanExecutionByResource requests that the active execution of the given resource replan for the given reason, which it does
+// once its current plan is cancelled. Requests made while one is pending are dropped.
+func (s *State) ReplanExecutionByResource(componentName resource.Name, reason string) error {
+	e, err := s.activeExecution(componentName)
+	if err != nil {
+		return err
+	}
+	select {
+	case e.replanRequests <- reason:
+	default:
+	}
+	return nil
+}
+
+// StopAllExecutions stops the active execution of every resource in the State, which unlike Stop leaves the State running.
+func (s *State) StopAllExecutions() {
+	s.mu.RLock()
+	var active []stateExecution
+	for _, cs := range s.componentStateByComponent {
+		e := cs.lastExecution()
+		if _, terminal := motion.TerminalStateSet[e.history[0].StatusHistory[0].State]; !terminal {
+			active = append(active, e)
+		}
+	}
+	s.mu.RUnlock()
+
+	// lock released while waiting for the executions to stop as the executions stopping requires writing to the state
+	// which must take a lock
+	for _, e := range active {
+		e.stop()
+	}
+}
+
 // PlanHistory returns the plans with statuses of the resource
 // By default returns all plans from the most recent execution of the resoure
 // If the ExecutionID is provided, returns the plans of the ExecutionID rather
@@ -492,6 +693,7 @@ func (s *State) ListPlanStatuses(req motion.ListPlanStatusesReq) ([]motion.PlanS
 					ComponentName: e.componentName,
 					PlanID:        e.history[0].Plan.ID,
 					Status:        e.history[0].StatusHistory[0],
+					ReplanCounts:  motion.CountReplans(e.history),
 				})
 			}
 		}
@@ -508,12 +710,14 @@ func (s *State) ListPlanStatuses(req motion.ListPlanStatusesReq) ([]motion.PlanS
 			if !exists {
 				return nil, errors.New("state is corrupted")
 			}
+			replanCounts := motion.CountReplans(e.history)
 			for _, pws := range e.history {
 				statuses = append(statuses, motion.PlanStatusWithID{
 					ExecutionID:   e.id,
 					ComponentName: e.componentName,
 					PlanID:        pws.Plan.ID,
 					Status:        pws.StatusHistory[0],
+					ReplanCounts:  replanCounts,
 				})
 			}
 		}
@@ -4,11 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"go.opencensus.io/trace"
 	"go.viam.com/test"
+	"go.viam.com/utils/testutils"
 
 	"go.viam.com/rdk/components/base"
 	"go.viam.com/rdk/logging"
@@ -56,6 +60,26 @@ func (tpe *testPlannerExecutor) AnchorGeoPose() *spatialmath.GeoPose {
 	return nil
 }
 
+// testRecoverer is a mock PlannerExecutor which can recover from failures to replan.
+type testRecoverer struct {
+	testPlannerExecutor
+	recoverFunc func(context.Context, error) bool
+}
+
+func (tr *testRecoverer) Recover(ctx context.Context, cause error) bool {
+	return tr.recoverFunc(ctx, cause)
+}
+
+// testFinisher is a mock PlannerExecutor which records when it is finished.
+type testFinisher struct {
+	testPlannerExecutor
+	finished *atomic.Int32
+}
+
+func (tf *testFinisher) Finish() {
+	tf.finished.Add(1)
+}
+
 func TestState(t *testing.T) {
 	logger := logging.NewTestLogger(t)
 	myBase := base.Named("mybase")
@@ -177,42 +201,42 @@ func TestState(t *testing.T) {
 
 	t.Run("returns error if TTL is not set", func(t *testing.T) {
 		t.Parallel()
-		s, err := state.NewState(0, 0, logger)
+		s, err := state.NewState(0, 0, nil, logger)
 		test.That(t, err, test.ShouldBeError, errors.New("TTL can't be unset"))
 		test.That(t, s, test.ShouldBeNil)
 	})
 
 	t.Run("returns error if TTLCheckInterval is not set", func(t *testing.T) {
 		t.Parallel()
-		s, err := state.NewState(2, 0, logger)
+		s, err := state.NewState(2, 0, nil, logger)
 		test.That(t, err, test.ShouldBeError, errors.New("TTLCheckInterval can't be unset"))
 		test.That(t, s, test.ShouldBeNil)
 	})
 
 	t.Run("returns error if Logger is nil", func(t *testing.T) {
 		t.Parallel()
-		s, err := state.NewState(2, 1, nil)
+		s, err := state.NewState(2, 1, nil, nil)
 		test.That(t, err, test.ShouldBeError, errors.New("Logger can't be nil"))
 		test.That(t, s, test.ShouldBeNil)
 	})
 
 	t.Run("returns error if TTL < TTLCheckInterval", func(t *testing.T) {
 		t.Parallel()
-		s, err := state.NewState(1, 2, logger)
+		s, err := state.NewState(1, 2, nil, logger)
 		test.That(t, err, test.ShouldBeError, errors.New("TTL can't be lower than the TTLCheckInterval"))
 		test.That(t, s, test.ShouldBeNil)
 	})
 
 	t.Run("creating & stopping a state with no intermediary calls", func(t *testing.T) {
 		t.Parallel()
-		s, err := state.NewState(ttl, ttlCheckInterval, logger)
+		s, err := state.NewState(ttl, ttlCheckInterval, nil, logger)
 		test.That(t, err, test.ShouldBeNil)
 		defer s.Stop()
 	})
 
 	t.Run("starting a new execution & stopping the state", func(t *testing.T) {
 		t.Parallel()
-		s, err := state.NewState(ttl, ttlCheckInterval, logger)
+		s, err := state.NewState(ttl, ttlCheckInterval, nil, logger)
 		test.That(t, err, test.ShouldBeNil)
 		defer s.Stop()
 		_, err = state.StartExecution(ctx, s, emptyReq.ComponentName, emptyReq, successPlanConstructor)
@@ -221,7 +245,7 @@ func TestState(t *testing.T) {
 
 	t.Run("starting & stopping an execution & stopping the state", func(t *testing.T) {
 		t.Parallel()
-		s, err := state.NewState(ttl, ttlCheckInterval, logger)
+		s, err := state.NewState(ttl, ttlCheckInterval, nil, logger)
 		test.That(t, err, test.ShouldBeNil)
 		defer s.Stop()
 
@@ -262,9 +286,28 @@ func TestState(t *testing.T) {
 		test.That(t, err, test.ShouldBeNil)
 	})
 
+	t.Run("the component is reserved while an execution is running", func(t *testing.T) {
+		t.Parallel()
+		reservations := resource.NewReservations()
+		s, err := state.NewState(ttl, ttlCheckInterval, reservations, logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer s.Stop()
+		req := motion.MoveOnGlobeReq{ComponentName: base.Named("reservedbase")}
+		executionID, err := state.StartExecution(ctx, s, req.ComponentName, req, executionWaitingForCtxCancelledPlanConstructor)
+		test.That(t, err, test.ShouldBeNil)
+
+		err = reservations.Check(req.ComponentName, nil)
+		test.That(t, resource.IsReservedError(err), test.ShouldBeTrue)
+		test.That(t, err.Error(), test.ShouldContainSubstring, executionID.String())
+
+		err = s.StopExecutionByResource(req.ComponentName)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, reservations.Check(req.ComponentName, nil), test.ShouldBeNil)
+	})
+
 	t.Run("stopping an execution is idempotnet", func(t *testing.T) {
 		t.Parallel()
-		s, err := state.NewState(ttl, ttlCheckInterval, logger)
+		s, err := state.NewState(ttl, ttlCheckInterval, nil, logger)
 		test.That(t, err, test.ShouldBeNil)
 		defer s.Stop()
 		req := motion.MoveOnGlobeReq{ComponentName: myBase}
@@ -279,7 +322,7 @@ func TestState(t *testing.T) {
 
 	t.Run("stopping the state is idempotnet", func(t *testing.T) {
 		t.Parallel()
-		s, err := state.NewState(ttl, ttlCheckInterval, logger)
+		s, err := state.NewState(ttl, ttlCheckInterval, nil, logger)
 		test.That(t, err, test.ShouldBeNil)
 		defer s.Stop()
 		req := motion.MoveOnGlobeReq{ComponentName: myBase}
@@ -290,9 +333,253 @@ func TestState(t *testing.T) {
 		s.Stop()
 	})
 
+	t.Run("stopping all executions stops each and leaves the state running", func(t *testing.T) {
+		t.Parallel()
+		s, err := state.NewState(ttl, ttlCheckInterval, nil, logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer s.Stop()
+		otherBase := base.Named("stopallbase")
+		for _, name := range []resource.Name{myBase, otherBase} {
+			req := motion.MoveOnGlobeReq{ComponentName: name}
+			_, err = state.StartExecution(ctx, s, req.ComponentName, req, executionWaitingForCtxCancelledPlanConstructor)
+			test.That(t, err, test.ShouldBeNil)
+		}
+
+		s.StopAllExecutions()
+		for _, name := range []resource.Name{myBase, otherBase} {
+			pws, err := s.PlanHistory(motion.PlanHistoryReq{ComponentName: name})
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, pws[0].StatusHistory[0].State, test.ShouldEqual, motion.PlanStateStopped)
+		}
+
+		req := motion.MoveOnGlobeReq{ComponentName: myBase}
+		_, err = state.StartExecution(ctx, s, req.ComponentName, req, executionWaitingForCtxCancelledPlanConstructor)
+		test.That(t, err, test.ShouldBeNil)
+		s.StopAllExecutions()
+	})
+
+	t.Run("a failed replan is retried after each recovery until none are left", func(t *testing.T) {
+		t.Parallel()
+		// replanning succeeds once two recoveries have been performed
+		finalState := func(recoveries int32) motion.PlanState {
+			var recovered atomic.Int32
+			constructor := func(
+				ctx context.Context,
+				_ motion.MoveOnGlobeReq,
+				_ motionplan.Plan,
+				replanCount int,
+			) (state.PlannerExecutor, error) {
+				if replanCount == 0 {
+					return &testRecoverer{
+						testPlannerExecutor: testPlannerExecutor{
+							executeFunc: func(ctx context.Context, plan motionplan.Plan) (state.ExecuteResponse, error) {
+								return state.ExecuteResponse{Replan: true, ReplanReason: replanReason}, nil
+							},
+						},
+						recoverFunc: func(ctx context.Context, cause error) bool {
+							test.That(t, cause.Error(), test.ShouldEqual, "planning failed")
+							if recovered.Load() == recoveries {
+								return false
+							}
+							recovered.Add(1)
+							return true
+						},
+					}, nil
+				}
+				if recovered.Load() < 2 {
+					return nil, errors.New("planning failed")
+				}
+				return &testPlannerExecutor{}, nil
+			}
+
+			s, err := state.NewState(ttl, ttlCheckInterval, nil, logger)
+			test.That(t, err, test.ShouldBeNil)
+			defer s.Stop()
+			_, err = state.StartExecution(ctx, s, emptyReq.ComponentName, emptyReq, constructor)
+			test.That(t, err, test.ShouldBeNil)
+
+			var pws []motion.PlanWithStatus
+			testutils.WaitForAssertion(t, func(tb testing.TB) {
+				tb.Helper()
+				pws, err = s.PlanHistory(motion.PlanHistoryReq{ComponentName: myBase})
+				test.That(tb, err, test.ShouldBeNil)
+				test.That(tb, pws[0].StatusHistory[0].State, test.ShouldNotEqual, motion.PlanStateInProgress)
+			})
+			return pws[0].StatusHistory[0].State
+		}
+
+		test.That(t, finalState(2), test.ShouldEqual, motion.PlanStateSucceeded)
+		test.That(t, finalState(1), test.ShouldEqual, motion.PlanStateFailed)
+	})
+
+	t.Run("a failed first plan is retried after each recovery until none are left", func(t *testing.T) {
+		t.Parallel()
+		// planning succeeds once a recovery has been performed
+		start := func(recoveries int32) error {
+			var recovered atomic.Int32
+			constructor := func(
+				ctx context.Context,
+				_ motion.MoveOnGlobeReq,
+				_ motionplan.Plan,
+				_ int,
+			) (state.PlannerExecutor, error) {
+				return &testRecoverer{
+					testPlannerExecutor: testPlannerExecutor{
+						planFunc: func(context.Context) (motionplan.Plan, error) {
+							if recovered.Load() == 0 {
+								return nil, errors.New("planning failed")
+							}
+							return nil, nil
+						},
+					},
+					recoverFunc: func(ctx context.Context, cause error) bool {
+						if recovered.Load() == recoveries {
+							return false
+						}
+						recovered.Add(1)
+						return true
+					},
+				}, nil
+			}
+
+			s, err := state.NewState(ttl, ttlCheckInterval, nil, logger)
+			test.That(t, err, test.ShouldBeNil)
+			defer s.Stop()
+			req := motion.MoveOnGlobeReq{ComponentName: base.Named("recoveredbase")}
+			_, err = state.StartExecution(ctx, s, req.ComponentName, req, constructor)
+			return err
+		}
+
+		test.That(t, start(1), test.ShouldBeNil)
+		err := start(0)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldEqual, "planning failed")
+	})
+
+	t.Run("the executor which last planned an execution is finished once it ends", func(t *testing.T) {
+		t.Parallel()
+		s, err := state.NewState(ttl, ttlCheckInterval, nil, logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer s.Stop()
+
+		// the first plan is replanned once, after which the execution succeeds
+		var firstFinished, lastFinished atomic.Int32
+		constructor := func(
+			ctx context.Context,
+			_ motion.MoveOnGlobeReq,
+			_ motionplan.Plan,
+			replanCount int,
+		) (state.PlannerExecutor, error) {
+			if replanCount == 0 {
+				return &testFinisher{
+					testPlannerExecutor: testPlannerExecutor{
+						executeFunc: func(ctx context.Context, plan motionplan.Plan) (state.ExecuteResponse, error) {
+							return state.ExecuteResponse{Replan: true, ReplanReason: replanReason}, nil
+						},
+					},
+					finished: &firstFinished,
+				}, nil
+			}
+			return &testFinisher{finished: &lastFinished}, nil
+		}
+		req := motion.MoveOnGlobeReq{ComponentName: base.Named("finishedbase")}
+		_, err = state.StartExecution(ctx, s, req.ComponentName, req, constructor)
+		test.That(t, err, test.ShouldBeNil)
+		testutils.WaitForAssertion(t, func(tb testing.TB) {
+			tb.Helper()
+			test.That(tb, lastFinished.Load(), test.ShouldEqual, 1)
+		})
+		test.That(t, firstFinished.Load(), test.ShouldEqual, 0)
+
+		// an execution whose first plan fails is finished before it is started
+		var failedFinished atomic.Int32
+		failingConstructor := func(
+			ctx context.Context,
+			_ motion.MoveOnGlobeReq,
+			_ motionplan.Plan,
+			_ int,
+		) (state.PlannerExecutor, error) {
+			return &testFinisher{
+				testPlannerExecutor: testPlannerExecutor{
+					planFunc: func(context.Context) (motionplan.Plan, error) {
+						return nil, errors.New("planning failed")
+					},
+				},
+				finished: &failedFinished,
+			}, nil
+		}
+		_, err = state.StartExecution(ctx, s, req.ComponentName, req, failingConstructor)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, failedFinished.Load(), test.ShouldEqual, 1)
+	})
+
+	t.Run("a requested replan cancels the current plan and is counted by its reason", func(t *testing.T) {
+		t.Parallel()
+		s, err := state.NewState(ttl, ttlCheckInterval, nil, logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer s.Stop()
+		req := motion.MoveOnGlobeReq{ComponentName: base.Named("replannedbase")}
+		var observedMu sync.Mutex
+		observed := map[motion.ReplanReasonCode]int{}
+		s.ObserveReplans(func(code motion.ReplanReasonCode) {
+			observedMu.Lock()
+			defer observedMu.Unlock()
+			observed[code]++
+		})
+
+		err = s.ReplanExecutionByResource(req.ComponentName, "operator")
+		test.That(t, resource.IsNotFoundError(err), test.ShouldBeTrue)
+
+		var executions atomic.Int32
+		constructor := func(
+			ctx context.Context,
+			_ motion.MoveOnGlobeReq,
+			_ motionplan.Plan,
+			replanCount int,
+		) (state.PlannerExecutor, error) {
+			return &testPlannerExecutor{executeFunc: func(ctx context.Context, plan motionplan.Plan) (state.ExecuteResponse, error) {
+				// the first replan is categorized by the executor
+				if executions.Add(1) == 2 {
+					return state.ExecuteResponse{Replan: true, ReplanReason: replanReason, ReplanReasonCode: motion.ReplanReasonObstacle}, nil
+				}
+				<-ctx.Done()
+				return state.ExecuteResponse{}, ctx.Err()
+			}}, nil
+		}
+		_, err = state.StartExecution(ctx, s, req.ComponentName, req, constructor)
+		test.That(t, err, test.ShouldBeNil)
+
+		test.That(t, s.ReplanExecutionByResource(req.ComponentName, "operator"), test.ShouldBeNil)
+		var pws []motion.PlanWithStatus
+		testutils.WaitForAssertion(t, func(tb testing.TB) {
+			tb.Helper()
+			pws, err = s.PlanHistory(motion.PlanHistoryReq{ComponentName: req.ComponentName})
+			test.That(tb, err, test.ShouldBeNil)
+			test.That(tb, len(pws), test.ShouldEqual, 3)
+		})
+		test.That(t, pws[2].StatusHistory[0].State, test.ShouldEqual, motion.PlanStateFailed)
+		test.That(t, pws[2].StatusHistory[0].ReplanReason, test.ShouldEqual, motion.ReplanReasonManual)
+		test.That(t, *pws[2].StatusHistory[0].Reason, test.ShouldContainSubstring, "operator")
+		test.That(t, pws[1].StatusHistory[0].ReplanReason, test.ShouldEqual, motion.ReplanReasonObstacle)
+		test.That(t, pws[0].StatusHistory[0].State, test.ShouldEqual, motion.PlanStateInProgress)
+
+		statuses, err := s.ListPlanStatuses(motion.ListPlanStatusesReq{OnlyActivePlans: true})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(statuses), test.ShouldEqual, 1)
+		test.That(t, statuses[0].ReplanCounts, test.ShouldResemble, map[motion.ReplanReasonCode]int{
+			motion.ReplanReasonManual:   1,
+			motion.ReplanReasonObstacle: 1,
+		})
+
+		// replans are observed by the same reason codes the plan history reports
+		observedMu.Lock()
+		defer observedMu.Unlock()
+		test.That(t, observed, test.ShouldResemble, statuses[0].ReplanCounts)
+	})
+
 	t.Run("stopping an execution after stopping the state", func(t *testing.T) {
 		t.Parallel()
-		s, err := state.NewState(ttl, ttlCheckInterval, logger)
+		s, err := state.NewState(ttl, ttlCheckInterval, nil, logger)
 		test.That(t, err, test.ShouldBeNil)
 		defer s.Stop()
 		req := motion.MoveOnGlobeReq{ComponentName: myBase}
@@ -307,7 +594,7 @@ func TestState(t *testing.T) {
 
 	t.Run("querying for an unknown resource returns an unknown resource error", func(t *testing.T) {
 		t.Parallel()
-		s, err := state.NewState(ttl, ttlCheckInterval, logger)
+		s, err := state.NewState(ttl, ttlCheckInterval, nil, logger)
 		test.That(t, err, test.ShouldBeNil)
 		defer s.Stop()
 		req := motion.MoveOnGlobeReq{ComponentName: myBase}
@@ -320,7 +607,7 @@ func TestState(t *testing.T) {
 
 	t.Run("end to end test", func(t *testing.T) {
 		t.Parallel()
-		s, err := state.NewState(ttl, ttlCheckInterval, logger)
+		s, err := state.NewState(ttl, ttlCheckInterval, nil, logger)
 		test.That(t, err, test.ShouldBeNil)
 		defer s.Stop()
 
@@ -512,6 +799,8 @@ func TestState(t *testing.T) {
 		test.That(t, resPWS.pws[1].StatusHistory[0].State, test.ShouldEqual, motion.PlanStateFailed)
 		test.That(t, resPWS.pws[1].StatusHistory[0].Reason, test.ShouldNotBeNil)
 		test.That(t, *resPWS.pws[1].StatusHistory[0].Reason, test.ShouldResemble, replanReason)
+		// the executor did not categorize its replan
+		test.That(t, resPWS.pws[1].StatusHistory[0].ReplanReason, test.ShouldEqual, motion.ReplanReasonUnknown)
 		test.That(t, resPWS.pws[1].StatusHistory[0].Timestamp.After(execution2Replan1), test.ShouldBeTrue)
 		test.That(t, planStatusTimestampsInOrder(resPWS.pws[0].StatusHistory), test.ShouldBeTrue)
 		test.That(t, planStatusTimestampsInOrder(resPWS.pws[1].StatusHistory), test.ShouldBeTrue)
@@ -776,7 +1065,7 @@ func TestState(t *testing.T) {
 		ttlCheckInterval := time.Millisecond * 10
 		sleepTTLDuration := ttl * 2
 		sleepCheckDuration := ttlCheckInterval * 2
-		s, err := state.NewState(ttl, ttlCheckInterval, logger)
+		s, err := state.NewState(ttl, ttlCheckInterval, nil, logger)
 		test.That(t, err, test.ShouldBeNil)
 		defer s.Stop()
 		time.Sleep(sleepCheckDuration)
@@ -1051,3 +1340,79 @@ func pollUntil[T any](ctx context.Context, f func() (T, bool)) (T, bool) {
 		}
 	}
 }
+
+// spanRecorder is a trace exporter which records the spans of a single trace.
+type spanRecorder struct {
+	traceID trace.TraceID
+	mu      sync.Mutex
+	spans   []*trace.SpanData
+}
+
+func (r *spanRecorder) ExportSpan(s *trace.SpanData) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if s.TraceID == r.traceID {
+		r.spans = append(r.spans, s)
+	}
+}
+
+func (r *spanRecorder) named(name string) []*trace.SpanData {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var spans []*trace.SpanData
+	for _, s := range r.spans {
+		if s.Name == name {
+			spans = append(spans, s)
+		}
+	}
+	return spans
+}
+
+func TestExecutionTracing(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	s, err := state.NewState(ttl, ttlCheckInterval, nil, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer s.Stop()
+
+	ctx, requestSpan := trace.StartSpan(context.Background(), "request", trace.WithSampler(trace.AlwaysSample()))
+	recorder := &spanRecorder{traceID: requestSpan.SpanContext().TraceID}
+	trace.RegisterExporter(recorder)
+	defer trace.UnregisterExporter(recorder)
+
+	var executions atomic.Int32
+	constructor := func(context.Context, motion.MoveOnGlobeReq, motionplan.Plan, int) (state.PlannerExecutor, error) {
+		return &testPlannerExecutor{executeFunc: func(ctx context.Context, plan motionplan.Plan) (state.ExecuteResponse, error) {
+			// the first plan is replanned, and the second succeeds
+			if executions.Add(1) == 1 {
+				return state.ExecuteResponse{Replan: true, ReplanReason: replanReason}, nil
+			}
+			return state.ExecuteResponse{}, nil
+		}}, nil
+	}
+	myBase := base.Named("mybase")
+	executionID, err := state.StartExecution(ctx, s, myBase, motion.MoveOnGlobeReq{ComponentName: myBase}, constructor)
+	test.That(t, err, test.ShouldBeNil)
+	requestSpan.End()
+
+	// the execution outlives the request, and is traced under it
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		test.That(tb, len(recorder.named("motion::state::Execution")), test.ShouldEqual, 1)
+	})
+	execution := recorder.named("motion::state::Execution")[0]
+	test.That(t, execution.ParentSpanID, test.ShouldEqual, requestSpan.SpanContext().SpanID)
+	test.That(t, execution.Attributes["execution_id"], test.ShouldEqual, executionID.String())
+
+	plans := recorder.named("motion::state::Plan")
+	executes := recorder.named("motion::state::Execute")
+	test.That(t, len(plans), test.ShouldEqual, 2)
+	test.That(t, len(executes), test.ShouldEqual, 2)
+	for i, execute := range executes {
+		// each plan is executed under the span of the execution, with the ID of the plan
+		test.That(t, execute.ParentSpanID, test.ShouldEqual, execution.SpanID)
+		test.That(t, execute.Attributes["execution_id"], test.ShouldEqual, executionID.String())
+		test.That(t, execute.Attributes["plan_id"], test.ShouldEqual, plans[i].Attributes["plan_id"])
+	}
+	test.That(t, executes[0].Attributes["replan_reason"], test.ShouldEqual, replanReason)
+	test.That(t, plans[1].Attributes["replan_count"], test.ShouldEqual, int64(1))
+}
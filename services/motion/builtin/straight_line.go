@@ -0,0 +1,149 @@
+package builtin
+
+import (
+	"context"
+	"math"
+
+	"github.com/golang/geo/r3"
+
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/motionplan/tpspace"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+	rdkutils "go.viam.com/rdk/utils"
+)
+
+const (
+	// straightLineExtraKey is the key of extra through which MoveOnGlobe and MoveOnMap are given the distance in millimeters within
+	// which goals with no obstacles in the way are reached by rotating in place and driving straight, rather than by sampling
+	// based planning. This cuts the latency of small adjustments and docking approaches.
+	straightLineExtraKey = "straight_line_max_mm"
+	// footprintResolutionMM is the resolution at which the geometries of the base are sampled to find the width of its path.
+	footprintResolutionMM = 10.
+)
+
+// straightLinePlan returns a plan which rotates the base in place to face the goal, drives straight to it, and rotates in place
+// to the goal orientation unless the motion profile is position only. It returns a nil plan if the goal is too far away, the base
+// cannot rotate in place, or any obstacle lies within the path of the base, in which case sampling based planning is required.
+func (mr *moveRequest) straightLinePlan(ctx context.Context, planRequest *motionplan.PlanRequest) (motionplan.Plan, error) {
+	if mr.straightLineMaxMM <= 0 || len(planRequest.BoundingRegions) > 0 || len(planRequest.Goals) != 1 ||
+		len(planRequest.WorldState.InteractionSpaces()) > 0 {
+		return nil, nil
+	}
+	kinematics := mr.kinematicBase.Kinematics()
+	ptgProvider, ok := kinematics.(tpspace.PTGProvider)
+	if !ok || len(ptgProvider.PTGSolvers()) == 0 || !tpspace.RotatesInPlace(ptgProvider.PTGSolvers()[0]) {
+		return nil, nil
+	}
+	name := kinematics.Name()
+	startPIF, ok := planRequest.StartState.Poses()[name]
+	if !ok {
+		return nil, nil
+	}
+	goalPIF, ok := planRequest.Goals[0].Poses()[name]
+	if !ok || goalPIF.Parent() != referenceframe.World {
+		return nil, nil
+	}
+	start, goal := startPIF.Pose(), goalPIF.Pose()
+	travel := goal.Point().Sub(start.Point())
+	travel.Z = 0
+	distance := travel.Norm()
+	if distance > mr.straightLineMaxMM {
+		return nil, nil
+	}
+
+	// rotate to face the goal, drive to it, then rotate to the goal orientation
+	forward := spatialmath.Compose(start, spatialmath.NewPoseFromPoint(r3.Vector{Y: 1})).Point().Sub(start.Point())
+	turn := 0.
+	if distance > 0 {
+		turn = angleBetweenDegrees(forward, travel)
+	}
+	segments := [][2]float64{{turn, distance}}
+	if profile, _ := planRequest.Options["motion_profile"].(string); profile != motionplan.PositionOnlyMotionProfile {
+		heading := start.Orientation().OrientationVectorDegrees().Theta + turn
+		finalTurn := math.Mod(goal.Orientation().OrientationVectorDegrees().Theta-heading+540, 360) - 180
+		segments = append(segments, [2]float64{finalTurn, 0})
+	}
+
+	unobstructed, err := mr.pathIsClear(planRequest, start.Point(), goal.Point())
+	if err != nil || !unobstructed {
+		if err != nil {
+			mr.logger.CDebugf(ctx, "could not check the straight line path for obstacles, planning with sampling: %v", err)
+		}
+		return nil, nil
+	}
+
+	zeroInputs := make([]referenceframe.Input, len(kinematics.DoF()))
+	traj := motionplan.Trajectory{{name: zeroInputs}}
+	path := motionplan.Path{{name: referenceframe.NewPoseInFrame(referenceframe.World, start)}}
+	pose := start
+	for _, segment := range segments {
+		alpha, dist, err := tpspace.DiffDriveInputs(segment[0], segment[1])
+		if err != nil {
+			// turns of almost 180 degrees are left to the sampling based planner
+			return nil, nil //nolint:nilerr
+		}
+		if dist == 0 {
+			continue
+		}
+		inputs := referenceframe.FloatsToInputs([]float64{0, alpha, 0, dist})
+		relative, err := kinematics.Transform(inputs)
+		if err != nil {
+			return nil, err
+		}
+		pose = spatialmath.Compose(pose, relative)
+		traj = append(traj, referenceframe.FrameSystemInputs{name: inputs})
+		path = append(path, referenceframe.FrameSystemPoses{name: referenceframe.NewPoseInFrame(referenceframe.World, pose)})
+	}
+	mr.logger.CDebugf(ctx, "planned a straight line move of %.0fmm with a %.1f degree turn", distance, turn)
+	return motionplan.NewSimplePlan(path, traj), nil
+}
+
+// pathIsClear returns whether no obstacle lies within the area swept by the base rotating in place at the start, driving straight
+// to the goal, and rotating in place at the goal.
+func (mr *moveRequest) pathIsClear(planRequest *motionplan.PlanRequest, start, goal r3.Vector) (bool, error) {
+	kinematics := mr.kinematicBase.Kinematics()
+	footprint, err := kinematics.Geometries(make([]referenceframe.Input, len(kinematics.DoF())))
+	if err != nil {
+		return false, err
+	}
+	radius := 1.
+	for _, geometry := range footprint.Geometries() {
+		for _, pt := range geometry.ToPoints(footprintResolutionMM) {
+			radius = math.Max(radius, math.Hypot(pt.X, pt.Y))
+		}
+	}
+
+	var swept spatialmath.Geometry
+	travel := goal.Sub(start)
+	if travel.Norm() == 0 {
+		swept, err = spatialmath.NewSphere(spatialmath.NewPoseFromPoint(start), radius, "")
+	} else {
+		// capsules are aligned with the Z axis of their pose
+		center := spatialmath.NewPose(start.Add(goal).Mul(0.5), &spatialmath.OrientationVector{OX: travel.X, OY: travel.Y, OZ: travel.Z})
+		swept, err = spatialmath.NewCapsule(center, radius, travel.Norm()+2*radius, "")
+	}
+	if err != nil {
+		return false, err
+	}
+
+	obstacles, err := planRequest.WorldState.ObstaclesInWorldFrame(planRequest.FrameSystem, planRequest.StartState.Configuration())
+	if err != nil {
+		return false, err
+	}
+	for _, obstacle := range obstacles.Geometries() {
+		collides, err := obstacle.CollidesWith(swept, 0)
+		if err != nil {
+			return false, err
+		}
+		if collides {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// angleBetweenDegrees returns the counterclockwise angle in the XY plane from one vector to another, in the range [-180, 180].
+func angleBetweenDegrees(from, to r3.Vector) float64 {
+	return rdkutils.RadToDeg(math.Atan2(from.X*to.Y-from.Y*to.X, from.X*to.X+from.Y*to.Y))
+}
@@ -0,0 +1,83 @@
+package builtin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	geo "github.com/kellydunn/golang-geo"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/spatialmath"
+)
+
+func TestStraightLinePlan(t *testing.T) {
+	ctx := context.Background()
+	origin := geo.NewPoint(0, 0)
+	_, ms, closeFunc := CreateMoveOnGlobeTestEnvironment(ctx, t, origin, 80, spatialmath.NewZeroPose())
+	defer closeFunc(ctx)
+
+	// a destination 2m east of the base, which faces north
+	goal := r3.Vector{X: 2000}
+	destination := spatialmath.PoseToGeoPose(spatialmath.NewGeoPose(origin, 0), spatialmath.NewPoseFromPoint(goal))
+	newRequest := func(t *testing.T, obstacles []spatialmath.Geometry, extra map[string]interface{}) *moveRequest {
+		t.Helper()
+		req := motion.MoveOnGlobeReq{
+			ComponentName:      base.Named("test-base"),
+			Destination:        destination.Location(),
+			MovementSensorName: resource.NewName(movementsensor.API, moveSensorName),
+			Obstacles:          []*spatialmath.GeoGeometry{spatialmath.NewGeoGeometry(origin, obstacles)},
+			Extra:              extra,
+		}
+		planExecutor, err := ms.(*builtIn).newMoveOnGlobeRequest(ctx, req, nil, 0)
+		test.That(t, err, test.ShouldBeNil)
+		mr, ok := planExecutor.(*moveRequest)
+		test.That(t, ok, test.ShouldBeTrue)
+		return mr
+	}
+
+	t.Run("short unobstructed moves rotate in place and drive straight", func(t *testing.T) {
+		mr := newRequest(t, nil, map[string]interface{}{straightLineExtraKey: 5000., "motion_profile": motionplan.PositionOnlyMotionProfile})
+		plan, err := mr.Plan(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		_, ok := plan.(*motionplan.SimplePlan)
+		test.That(t, ok, test.ShouldBeTrue)
+		test.That(t, len(plan.Trajectory()), test.ShouldEqual, 2)
+
+		poses, err := plan.Path().GetFramePoses(mr.kinematicBase.Kinematics().Name())
+		test.That(t, err, test.ShouldBeNil)
+		end := poses[len(poses)-1]
+		test.That(t, end.Point().Distance(goal), test.ShouldBeLessThan, 1)
+		// the base turned right to face east
+		test.That(t, end.Orientation().OrientationVectorDegrees().Theta, test.ShouldAlmostEqual, -90, 1)
+	})
+
+	t.Run("the base rotates to the goal orientation", func(t *testing.T) {
+		mr := newRequest(t, nil, map[string]interface{}{straightLineExtraKey: 5000.})
+		plan, err := mr.Plan(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(plan.Trajectory()), test.ShouldEqual, 3)
+		poses, err := plan.Path().GetFramePoses(mr.kinematicBase.Kinematics().Name())
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, poses[len(poses)-1].Orientation().OrientationVectorDegrees().Theta, test.ShouldAlmostEqual, 0, 1)
+	})
+
+	t.Run("obstructed or distant goals are planned with sampling", func(t *testing.T) {
+		obstacle, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(r3.Vector{X: 1000}), r3.Vector{X: 100, Y: 100, Z: 100}, "box")
+		test.That(t, err, test.ShouldBeNil)
+		mr := newRequest(t, []spatialmath.Geometry{obstacle}, map[string]interface{}{straightLineExtraKey: 5000.})
+		plan, err := mr.straightLinePlan(ctx, mr.planRequest)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, plan, test.ShouldBeNil)
+
+		mr = newRequest(t, nil, map[string]interface{}{straightLineExtraKey: 1000.})
+		plan, err = mr.straightLinePlan(ctx, mr.planRequest)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, plan, test.ShouldBeNil)
+	})
+}
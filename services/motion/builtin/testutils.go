@@ -76,6 +76,9 @@ func createInjectedMovementSensor(name string, gpsPoint *geo.Point) *inject.Move
 	injectedMovementSensor.PropertiesFunc = func(ctx context.Context, extra map[string]interface{}) (*movementsensor.Properties, error) {
 		return &movementsensor.Properties{CompassHeadingSupported: true}, nil
 	}
+	injectedMovementSensor.AccuracyFunc = func(ctx context.Context, extra map[string]interface{}) (*movementsensor.Accuracy, error) {
+		return movementsensor.UnimplementedOptionalAccuracies(), nil
+	}
 
 	return injectedMovementSensor
 }
@@ -89,6 +92,9 @@ func createInjectedSlam(name string) *inject.SLAMService {
 	injectSlam.PositionFunc = func(ctx context.Context) (spatialmath.Pose, error) {
 		return spatialmath.NewZeroPose(), nil
 	}
+	injectSlam.LocalizationQualityFunc = func(ctx context.Context) (slam.LocalizationQuality, error) {
+		return slam.LocalizationQuality{State: slam.LocalizationStateTracking, Confidence: 1}, nil
+	}
 	injectSlam.PropertiesFunc = func(ctx context.Context) (slam.Properties, error) {
 		return slam.Properties{
 			CloudSlam:             false,
@@ -338,6 +344,9 @@ func createMockSlamService(name, pcdPath string, origin spatialmath.Pose, move m
 
 		return spatialmath.Compose(origin, geoPose), nil
 	}
+	injectSlam.LocalizationQualityFunc = func(ctx context.Context) (slam.LocalizationQuality, error) {
+		return slam.LocalizationQuality{State: slam.LocalizationStateTracking, Confidence: 1}, nil
+	}
 	injectSlam.PropertiesFunc = func(ctx context.Context) (slam.Properties, error) {
 		return slam.Properties{
 			CloudSlam:             false,
@@ -0,0 +1,104 @@
+package builtin
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/golang/geo/r3"
+	commonpb "go.viam.com/api/common/v1"
+
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+const (
+	// toolCenterPointExtraKey is the key of extra through which Move and MoveOnMap are told the pose, relative to the component being
+	// moved, of the point which their goals refer to, such as the tip of a gripper or of an object it holds, as a map of x, y and z
+	// in mm and o_x, o_y, o_z and theta in degrees. The point is added to the frame system for the request alone.
+	toolCenterPointExtraKey = "tool_center_point"
+	// toolCenterPointFrameSuffix follows the name of the component in the name of the frame of its tool center point.
+	toolCenterPointFrameSuffix = "_tool_center_point"
+)
+
+// toolCenterPointPose is the pose of a tool center point as given in extra.
+type toolCenterPointPose struct {
+	X     float64 `mapstructure:"x"`
+	Y     float64 `mapstructure:"y"`
+	Z     float64 `mapstructure:"z"`
+	OX    float64 `mapstructure:"o_x"`
+	OY    float64 `mapstructure:"o_y"`
+	OZ    float64 `mapstructure:"o_z"`
+	Theta float64 `mapstructure:"theta"`
+}
+
+// parseToolCenterPoint parses the pose of the tool center point of the component from extra, returning nil if the request has none.
+func parseToolCenterPoint(extra map[string]interface{}) (spatialmath.Pose, error) {
+	raw, ok := extra[toolCenterPointExtraKey]
+	if !ok {
+		return nil, nil
+	}
+	if _, ok := raw.(map[string]interface{}); !ok {
+		return nil, fmt.Errorf("could not interpret %s field as a map", toolCenterPointExtraKey)
+	}
+	var tcp toolCenterPointPose
+	if err := mapstructure.Decode(raw, &tcp); err != nil {
+		return nil, fmt.Errorf("could not interpret %s field: %w", toolCenterPointExtraKey, err)
+	}
+	if tcp.OX == 0 && tcp.OY == 0 && tcp.OZ == 0 {
+		// the tool points the same way as the component unless told otherwise
+		tcp.OZ = 1
+	}
+	return spatialmath.NewPoseFromProtobuf(&commonpb.Pose{
+		X: tcp.X, Y: tcp.Y, Z: tcp.Z, OX: tcp.OX, OY: tcp.OY, OZ: tcp.OZ, Theta: tcp.Theta,
+	}), nil
+}
+
+// aimToolCenterPoint adds a frame at the tool center point of the named frame to the frame system, and returns its name along with
+// the waypoints with each goal pose of the named frame made a goal pose of the tool center point instead.
+func aimToolCenterPoint(
+	frameSys referenceframe.FrameSystem,
+	frame string,
+	tcp spatialmath.Pose,
+	waypoints []*motionplan.PlanState,
+) (string, []*motionplan.PlanState, error) {
+	parent := frameSys.Frame(frame)
+	if parent == nil {
+		return "", nil, referenceframe.NewFrameMissingError(frame)
+	}
+	tcpFrame, err := referenceframe.NewStaticFrame(frame+toolCenterPointFrameSuffix, tcp)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := frameSys.AddFrame(tcpFrame, parent); err != nil {
+		return "", nil, err
+	}
+	aimed := make([]*motionplan.PlanState, 0, len(waypoints))
+	for _, wp := range waypoints {
+		goal, ok := wp.Poses()[frame]
+		if !ok {
+			aimed = append(aimed, wp)
+			continue
+		}
+		poses := referenceframe.FrameSystemPoses{}
+		for name, pose := range wp.Poses() {
+			poses[name] = pose
+		}
+		delete(poses, frame)
+		poses[tcpFrame.Name()] = goal
+		aimed = append(aimed, motionplan.NewPlanState(poses, wp.Configuration()).WithMetadata(wp.Metadata()))
+	}
+	return tcpFrame.Name(), aimed, nil
+}
+
+// baseGoalForToolCenterPoint returns the goal of a base which places its tool center point at the given goal. As a base moves in the
+// plane alone, its tool must point along its z axis, and the height of the tool is ignored.
+func baseGoalForToolCenterPoint(goal, tcp spatialmath.Pose) (spatialmath.Pose, error) {
+	ov := tcp.Orientation().OrientationVectorRadians()
+	if math.Abs(ov.OZ-1) > 1e-6 {
+		return nil, fmt.Errorf("the %s of a base must point along its z axis", toolCenterPointExtraKey)
+	}
+	planar := spatialmath.NewPose(r3.Vector{X: tcp.Point().X, Y: tcp.Point().Y}, &spatialmath.OrientationVector{OZ: 1, Theta: ov.Theta})
+	return spatialmath.Compose(goal, spatialmath.PoseInverse(planar)), nil
+}
@@ -0,0 +1,90 @@
+package builtin
+
+import (
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+func TestToolCenterPoint(t *testing.T) {
+	t.Run("requests have no tool center point by default", func(t *testing.T) {
+		tcp, err := parseToolCenterPoint(map[string]interface{}{})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, tcp, test.ShouldBeNil)
+
+		_, err = parseToolCenterPoint(map[string]interface{}{toolCenterPointExtraKey: "tip"})
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = parseToolCenterPoint(map[string]interface{}{toolCenterPointExtraKey: map[string]interface{}{"z": "long"}})
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("tools point the way of the component unless told otherwise", func(t *testing.T) {
+		tcp, err := parseToolCenterPoint(map[string]interface{}{toolCenterPointExtraKey: map[string]interface{}{"z": 150.}})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, spatialmath.PoseAlmostEqual(tcp, spatialmath.NewPoseFromPoint(r3.Vector{Z: 150})), test.ShouldBeTrue)
+
+		tcp, err = parseToolCenterPoint(map[string]interface{}{toolCenterPointExtraKey: map[string]interface{}{
+			"x": 10., "o_x": 1., "theta": 90.,
+		}})
+		test.That(t, err, test.ShouldBeNil)
+		expected := spatialmath.NewPose(r3.Vector{X: 10}, &spatialmath.OrientationVectorDegrees{OX: 1, Theta: 90})
+		test.That(t, spatialmath.PoseAlmostEqual(tcp, expected), test.ShouldBeTrue)
+	})
+
+	t.Run("goals of the component become goals of its tool", func(t *testing.T) {
+		frameSys := referenceframe.NewEmptyFrameSystem("test")
+		gripper, err := referenceframe.NewStaticFrame("gripper", spatialmath.NewPoseFromPoint(r3.Vector{Z: 500}))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, frameSys.AddFrame(gripper, frameSys.World()), test.ShouldBeNil)
+
+		goal := referenceframe.NewPoseInFrame(referenceframe.World, spatialmath.NewPoseFromPoint(r3.Vector{X: 300}))
+		other := referenceframe.NewPoseInFrame(referenceframe.World, spatialmath.NewPoseFromPoint(r3.Vector{Y: 300}))
+		waypoints := []*motionplan.PlanState{
+			motionplan.NewPlanState(referenceframe.FrameSystemPoses{"gripper": goal, "camera": other}, nil),
+			motionplan.NewPlanState(nil, referenceframe.FrameSystemInputs{"arm": referenceframe.FloatsToInputs([]float64{1})}),
+		}
+		tcp := spatialmath.NewPoseFromPoint(r3.Vector{Z: 150})
+		name, aimed, err := aimToolCenterPoint(frameSys, "gripper", tcp, waypoints)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, name, test.ShouldEqual, "gripper"+toolCenterPointFrameSuffix)
+		test.That(t, aimed, test.ShouldHaveLength, 2)
+		test.That(t, aimed[0].Poses(), test.ShouldResemble, referenceframe.FrameSystemPoses{name: goal, "camera": other})
+		test.That(t, aimed[1], test.ShouldEqual, waypoints[1])
+		// the waypoints given are left as they were
+		test.That(t, waypoints[0].Poses()["gripper"], test.ShouldEqual, goal)
+
+		// the tool is added to the frame system at its point on the component
+		tf, err := frameSys.Transform(
+			referenceframe.FrameSystemInputs{},
+			referenceframe.NewZeroPoseInFrame(name),
+			referenceframe.World,
+		)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, spatialmath.R3VectorAlmostEqual(tf.(*referenceframe.PoseInFrame).Pose().Point(), r3.Vector{Z: 650}, 1e-6),
+			test.ShouldBeTrue)
+
+		_, _, err = aimToolCenterPoint(frameSys, "missing", tcp, waypoints)
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("bases are sent wherever places their tool at the goal", func(t *testing.T) {
+		// a tool 200mm ahead of the base, facing to its left
+		tcp := spatialmath.NewPose(r3.Vector{Y: 200, Z: 300}, &spatialmath.OrientationVectorDegrees{OZ: 1, Theta: 90})
+		goal := spatialmath.NewPose(r3.Vector{X: 1000, Y: 1000}, &spatialmath.OrientationVectorDegrees{OZ: 1})
+		baseGoal, err := baseGoalForToolCenterPoint(goal, tcp)
+		test.That(t, err, test.ShouldBeNil)
+
+		// the base is placed such that its tool, ignoring the height of the tool, is at the goal
+		planarTool := spatialmath.Compose(baseGoal, spatialmath.NewPose(r3.Vector{Y: 200}, tcp.Orientation()))
+		test.That(t, spatialmath.PoseAlmostEqual(planarTool, goal), test.ShouldBeTrue)
+		test.That(t, baseGoal.Point().Z, test.ShouldAlmostEqual, 0)
+
+		_, err = baseGoalForToolCenterPoint(goal, spatialmath.NewPoseFromOrientation(&spatialmath.OrientationVectorDegrees{OX: 1}))
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
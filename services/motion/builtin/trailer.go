@@ -0,0 +1,73 @@
+package builtin
+
+import (
+	"fmt"
+
+	"go.viam.com/rdk/components/base/kinematicbase"
+	"go.viam.com/rdk/motionplan"
+)
+
+// trailerExtraKey is the key of extra through which MoveOnGlobe and MoveOnMap are told that the base tows a trailer, as a map of
+// hitch_offset_mm, the distance of the hitch behind the center of rotation of the base, length_mm, the distance from the hitch to the
+// axle of the trailer, and max_hitch_angle_degs, how far the trailer may swing before it jackknifes. The base is then planned for
+// only with forward arcs which cannot jackknife the trailer, and stops if the trailer swings beyond the maximum while executing.
+const trailerExtraKey = "trailer"
+
+// parseTrailer parses the trailer towed by the base from extra, returning nil if it tows none.
+func parseTrailer(extra map[string]interface{}) (*kinematicbase.TrailerOptions, error) {
+	raw, ok := extra[trailerExtraKey]
+	if !ok {
+		return nil, nil
+	}
+	fields, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("could not interpret %s field as a map", trailerExtraKey)
+	}
+	field := func(name string, required bool) (float64, error) {
+		rawValue, ok := fields[name]
+		if !ok {
+			if required {
+				return 0, fmt.Errorf("%s must set %s", trailerExtraKey, name)
+			}
+			return 0, nil
+		}
+		value, ok := rawValue.(float64)
+		if !ok {
+			return 0, fmt.Errorf("could not interpret %s entry %s as float", trailerExtraKey, name)
+		}
+		return value, nil
+	}
+	trailer := &kinematicbase.TrailerOptions{}
+	var err error
+	if trailer.HitchOffsetMM, err = field("hitch_offset_mm", false); err != nil {
+		return nil, err
+	}
+	if trailer.LengthMM, err = field("length_mm", true); err != nil {
+		return nil, err
+	}
+	if trailer.MaxHitchAngleDegs, err = field("max_hitch_angle_degs", true); err != nil {
+		return nil, err
+	}
+	if _, err := trailer.HitchConstraint("base").MinTurningRadiusMM(); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", trailerExtraKey, err)
+	}
+	return trailer, nil
+}
+
+// towTrailer returns a copy of the given constraints which keeps the trailer towed by the named frame of the base from jackknifing,
+// or the constraints themselves if the base tows no trailer.
+func towTrailer(
+	constraints *motionplan.Constraints,
+	trailer *kinematicbase.TrailerOptions,
+	frame string,
+) *motionplan.Constraints {
+	if trailer == nil {
+		return constraints
+	}
+	towing := motionplan.NewEmptyConstraints()
+	if constraints != nil {
+		*towing = *constraints
+	}
+	towing.HitchConstraint = append(append([]motionplan.HitchConstraint{}, towing.HitchConstraint...), trailer.HitchConstraint(frame))
+	return towing
+}
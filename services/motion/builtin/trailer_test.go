@@ -0,0 +1,52 @@
+package builtin
+
+import (
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/base/kinematicbase"
+	"go.viam.com/rdk/motionplan"
+)
+
+func TestTrailer(t *testing.T) {
+	t.Run("bases tow no trailer by default", func(t *testing.T) {
+		trailer, err := parseTrailer(map[string]interface{}{})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, trailer, test.ShouldBeNil)
+		existing := &motionplan.Constraints{LevelConstraint: []motionplan.LevelConstraint{{Frame: "arm", ToleranceDegs: 5}}}
+		test.That(t, towTrailer(existing, trailer, "base"), test.ShouldEqual, existing)
+	})
+
+	t.Run("invalid trailers are rejected", func(t *testing.T) {
+		for _, raw := range []interface{}{
+			"long",
+			map[string]interface{}{"max_hitch_angle_degs": 30.},
+			map[string]interface{}{"length_mm": 1000.},
+			map[string]interface{}{"length_mm": "long", "max_hitch_angle_degs": 30.},
+			map[string]interface{}{"length_mm": 1000., "max_hitch_angle_degs": 90.},
+			map[string]interface{}{"length_mm": 1000., "max_hitch_angle_degs": 30., "hitch_offset_mm": -100.},
+		} {
+			_, err := parseTrailer(map[string]interface{}{trailerExtraKey: raw})
+			test.That(t, err, test.ShouldNotBeNil)
+		}
+	})
+
+	t.Run("trailers are towed without jackknifing", func(t *testing.T) {
+		trailer, err := parseTrailer(map[string]interface{}{trailerExtraKey: map[string]interface{}{
+			"hitch_offset_mm":      200.,
+			"length_mm":            1000.,
+			"max_hitch_angle_degs": 30.,
+		}})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, *trailer, test.ShouldResemble, kinematicbase.TrailerOptions{HitchOffsetMM: 200, LengthMM: 1000, MaxHitchAngleDegs: 30})
+
+		existing := &motionplan.Constraints{LevelConstraint: []motionplan.LevelConstraint{{Frame: "arm", ToleranceDegs: 5}}}
+		constraints := towTrailer(existing, trailer, "base")
+		test.That(t, constraints.GetLevelConstraint(), test.ShouldResemble, existing.LevelConstraint)
+		test.That(t, constraints.GetHitchConstraint(), test.ShouldResemble, []motionplan.HitchConstraint{
+			{Frame: "base", HitchOffsetMM: 200, TrailerLengthMM: 1000, MaxHitchAngleDegs: 30},
+		})
+		test.That(t, existing.GetHitchConstraint(), test.ShouldBeEmpty)
+	})
+}
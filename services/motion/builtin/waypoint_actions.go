@@ -0,0 +1,132 @@
+package builtin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/go-viper/mapstructure/v2"
+	"go.viam.com/utils"
+
+	"go.viam.com/rdk/resource"
+)
+
+// waypointActionsExtraKey is the key of extra through which Move is given waypoint actions in addition to those configured on the
+// service, as a map of their names to the resource each sends its command to, the command, and whether it runs asynchronously. Actions
+// given in the request take the place of configured actions of the same name.
+const waypointActionsExtraKey = "waypoint_actions"
+
+// WaypointActionConfig describes a hook which the metadata of waypoints may name, run once they are reached by sending a command to
+// a resource, such as triggering a camera at an inspection point. Execution of the plan waits for the command to return unless the
+// action is asynchronous, in which case the plan continues while it runs.
+type WaypointActionConfig struct {
+	Resource string                 `json:"resource" mapstructure:"resource"`
+	Command  map[string]interface{} `json:"command" mapstructure:"command"`
+	Async    bool                   `json:"async,omitempty" mapstructure:"async"`
+}
+
+// waypointAction is a configured hook, with the resource it sends its command to.
+type waypointAction struct {
+	resource resource.Resource
+	command  map[string]interface{}
+	async    bool
+}
+
+// newWaypointActions resolves the resources of the configured waypoint actions from the dependencies of the motion service.
+func newWaypointActions(configs map[string]WaypointActionConfig, deps resource.Dependencies) (map[string]waypointAction, error) {
+	actions := make(map[string]waypointAction, len(configs))
+	for name, cfg := range configs {
+		res, ok := findByShortName(deps, cfg.Resource)
+		if !ok {
+			return nil, fmt.Errorf("resource %q of waypoint action %q is not a dependency of the motion service", cfg.Resource, name)
+		}
+		actions[name] = waypointAction{resource: res, command: cfg.Command, async: cfg.Async}
+	}
+	return actions, nil
+}
+
+// waypointActionsFromExtra returns the waypoint actions configured on the service together with any given in extra.
+func (ms *builtIn) waypointActionsFromExtra(extra map[string]interface{}) (map[string]waypointAction, error) {
+	raw, ok := extra[waypointActionsExtraKey]
+	if !ok {
+		return ms.waypointActions, nil
+	}
+	if _, ok := raw.(map[string]interface{}); !ok {
+		return nil, fmt.Errorf("could not interpret %s field as a map", waypointActionsExtraKey)
+	}
+	var configs map[string]WaypointActionConfig
+	if err := mapstructure.Decode(raw, &configs); err != nil {
+		return nil, fmt.Errorf("could not interpret %s field: %w", waypointActionsExtraKey, err)
+	}
+	actions := make(map[string]waypointAction, len(ms.waypointActions)+len(configs))
+	for name, action := range ms.waypointActions {
+		actions[name] = action
+	}
+	for name, cfg := range configs {
+		res, ok := ms.findResource(cfg.Resource)
+		if !ok {
+			return nil, fmt.Errorf("cannot find resource %q of waypoint action %q", cfg.Resource, name)
+		}
+		actions[name] = waypointAction{resource: res, command: cfg.Command, async: cfg.Async}
+	}
+	return actions, nil
+}
+
+// findResource returns the component or service the motion service depends on with the given short name.
+func (ms *builtIn) findResource(name string) (resource.Resource, bool) {
+	if component, ok := findByShortName(ms.components, name); ok {
+		return component, true
+	}
+	if movementSensor, ok := findByShortName(ms.movementSensors, name); ok {
+		return movementSensor, true
+	}
+	if visionSvc, ok := findByShortName(ms.visionServices, name); ok {
+		return visionSvc, true
+	}
+	if slamSvc, ok := findByShortName(ms.slamServices, name); ok {
+		return slamSvc, true
+	}
+	return nil, false
+}
+
+// pendingActions tracks the asynchronous waypoint actions still running, and the errors of those which failed.
+type pendingActions struct {
+	workers sync.WaitGroup
+	mu      sync.Mutex
+	err     error
+}
+
+// wait waits for the pending actions to finish, returning the errors of any which failed.
+func (pa *pendingActions) wait() error {
+	pa.workers.Wait()
+	pa.mu.Lock()
+	defer pa.mu.Unlock()
+	return pa.err
+}
+
+// runWaypointAction runs the named waypoint action, in the background if it is asynchronous.
+func runWaypointAction(ctx context.Context, actions map[string]waypointAction, name string, pending *pendingActions) error {
+	action, ok := actions[name]
+	if !ok {
+		return fmt.Errorf("no waypoint action named %q is configured", name)
+	}
+	run := func() error {
+		if _, err := action.resource.DoCommand(ctx, action.command); err != nil {
+			return errors.Join(fmt.Errorf("waypoint action %q failed", name), err)
+		}
+		return nil
+	}
+	if !action.async {
+		return run()
+	}
+	pending.workers.Add(1)
+	utils.ManagedGo(func() {
+		if err := run(); err != nil {
+			pending.mu.Lock()
+			pending.err = errors.Join(pending.err, err)
+			pending.mu.Unlock()
+		}
+	}, pending.workers.Done)
+	return nil
+}
@@ -0,0 +1,76 @@
+package builtin
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/generic"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestWaypointActions(t *testing.T) {
+	ctx := context.Background()
+	conf := &Config{WaypointActions: map[string]WaypointActionConfig{
+		"photograph": {Resource: "camera", Command: map[string]interface{}{"capture": true}},
+	}}
+	deps, err := conf.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, deps, test.ShouldContain, "camera")
+	_, err = (&Config{WaypointActions: map[string]WaypointActionConfig{"photograph": {}}}).Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+
+	camera := inject.NewGenericComponent("camera")
+	sprayer := inject.NewGenericComponent("sprayer")
+	ms := &builtIn{components: map[resource.Name]resource.Resource{
+		generic.Named("camera"):  camera,
+		generic.Named("sprayer"): sprayer,
+	}}
+	ms.waypointActions, err = newWaypointActions(conf.WaypointActions, resource.Dependencies{generic.Named("camera"): camera})
+	test.That(t, err, test.ShouldBeNil)
+	_, err = newWaypointActions(conf.WaypointActions, resource.Dependencies{})
+	test.That(t, err, test.ShouldNotBeNil)
+
+	t.Run("requests add actions to those configured", func(t *testing.T) {
+		actions, err := ms.waypointActionsFromExtra(nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, actions, test.ShouldResemble, ms.waypointActions)
+
+		actions, err = ms.waypointActionsFromExtra(map[string]interface{}{waypointActionsExtraKey: map[string]interface{}{
+			"spray": map[string]interface{}{"resource": "sprayer", "command": map[string]interface{}{"on": true}, "async": true},
+		}})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, actions["photograph"], test.ShouldResemble, ms.waypointActions["photograph"])
+		test.That(t, actions["spray"].resource, test.ShouldEqual, sprayer)
+		test.That(t, actions["spray"].command, test.ShouldResemble, map[string]interface{}{"on": true})
+		test.That(t, actions["spray"].async, test.ShouldBeTrue)
+
+		_, err = ms.waypointActionsFromExtra(map[string]interface{}{waypointActionsExtraKey: map[string]interface{}{
+			"spray": map[string]interface{}{"resource": "hose"},
+		}})
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = ms.waypointActionsFromExtra(map[string]interface{}{waypointActionsExtraKey: "spray"})
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("asynchronous actions are waited for", func(t *testing.T) {
+		release := make(chan struct{})
+		sprayer.DoFunc = func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+			<-release
+			return nil, errors.New("clogged")
+		}
+		actions := map[string]waypointAction{"spray": {resource: sprayer, async: true}}
+		pending := &pendingActions{}
+		// the action returns before the sprayer does
+		test.That(t, runWaypointAction(ctx, actions, "spray", pending), test.ShouldBeNil)
+		close(release)
+		err := pending.wait()
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "clogged")
+
+		test.That(t, runWaypointAction(ctx, actions, "wave", pending), test.ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,203 @@
+package builtin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"go.viam.com/utils"
+
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/referenceframe"
+)
+
+// annotateWaypoints returns the plan with the metadata of each of the waypoints it was planned through, keyed by the step of its
+// trajectory which reaches them. The final waypoint is reached by the final step, and each before it by the step, after that of the
+// waypoint before it, at which the goal frame is nearest to it.
+func annotateWaypoints(
+	fs referenceframe.FrameSystem,
+	plan motionplan.Plan,
+	goalFrame string,
+	waypoints []*motionplan.PlanState,
+) (motionplan.Plan, error) {
+	hasMetadata := false
+	for _, wp := range waypoints {
+		hasMetadata = hasMetadata || wp.Metadata() != nil
+	}
+	trajectory := plan.Trajectory()
+	if !hasMetadata || len(trajectory) == 0 {
+		return plan, nil
+	}
+
+	// states holds the inputs of every frame as of each step of the trajectory
+	states := make([]referenceframe.FrameSystemInputs, 0, len(trajectory))
+	state := referenceframe.FrameSystemInputs{}
+	for _, step := range trajectory {
+		state = mergeInputs(state, step)
+		states = append(states, state)
+	}
+	// distance returns how far the state of the given step is from the waypoint
+	distance := func(wp *motionplan.PlanState, step int) (float64, error) {
+		if goal, ok := wp.Poses()[goalFrame]; ok {
+			pose, err := framePoseInWorld(fs, states[step], goalFrame)
+			if err != nil {
+				return 0, err
+			}
+			return pose.Point().Distance(goal.Pose().Point()), nil
+		}
+		var dist float64
+		for name, inputs := range wp.Configuration() {
+			stepInputs := states[step][name]
+			for i, input := range inputs {
+				if i < len(stepInputs) {
+					dist += math.Abs(input.Value - stepInputs[i].Value)
+				}
+			}
+		}
+		return dist, nil
+	}
+
+	metadata := map[int]*motionplan.WaypointMetadata{}
+	from := 0
+	for i, wp := range waypoints {
+		reached := len(trajectory) - 1
+		if i < len(waypoints)-1 {
+			best := math.Inf(1)
+			for step := from; step < len(trajectory); step++ {
+				dist, err := distance(wp, step)
+				if err != nil {
+					return nil, err
+				}
+				if dist < best {
+					best, reached = dist, step
+				}
+			}
+		}
+		if wp.Metadata() != nil {
+			metadata[reached] = wp.Metadata()
+		}
+		from = reached
+	}
+	return motionplan.WithWaypointMetadata(plan, metadata), nil
+}
+
+// executeWaypoints executes the plan one waypoint with metadata at a time, limiting the speed of the component on the way to each as
+// its metadata asks, then checking that the component arrived within its tolerance, pausing for its dwell time, and running its
+// action from among the given actions. The speed of the component is limited to maxSpeedMMPerSec throughout if it is positive.
+func (ms *builtIn) executeWaypoints(
+	ctx context.Context,
+	fs referenceframe.FrameSystem,
+	plan motionplan.Plan,
+	componentName string,
+	maxSpeedMMPerSec float64,
+	actions map[string]waypointAction,
+	mobile *mobileBase,
+) (err error) {
+	metadata := motionplan.PlanWaypointMetadata(plan)
+	trajectory := plan.Trajectory()
+	if len(metadata) == 0 {
+		return ms.executeTrajectory(ctx, fs, trajectory, componentName, maxSpeedMMPerSec, mobile)
+	}
+	steps := make([]int, 0, len(metadata)+1)
+	for step := range metadata {
+		if step >= len(trajectory) {
+			return fmt.Errorf("waypoint metadata is for step %d of a trajectory of %d steps", step, len(trajectory))
+		}
+		steps = append(steps, step)
+	}
+	sort.Ints(steps)
+	if last := len(trajectory) - 1; steps[len(steps)-1] != last {
+		steps = append(steps, last)
+	}
+
+	// asynchronous actions run alongside the rest of the plan, which is not done until they are
+	pending := &pendingActions{}
+	defer func() {
+		err = errors.Join(err, pending.wait())
+	}()
+	from := 0
+	for _, step := range steps {
+		md := metadata[step]
+		if md == nil {
+			md = &motionplan.WaypointMetadata{}
+		}
+		// each leg starts from the step at which the last ended
+		speed := maxSpeedMMPerSec
+		if md.MaxSpeedMMPerSec > 0 && (speed <= 0 || md.MaxSpeedMMPerSec < speed) {
+			speed = md.MaxSpeedMMPerSec
+		}
+		if err := ms.executeTrajectory(ctx, fs, trajectory[from:step+1], componentName, speed, mobile); err != nil {
+			return err
+		}
+		from = step
+		if md.ToleranceMM > 0 {
+			if err := ms.checkArrival(ctx, fs, mobile, componentName, stateAt(trajectory, step), md.ToleranceMM); err != nil {
+				return err
+			}
+		}
+		if md.DwellSeconds > 0 && !utils.SelectContextOrWait(ctx, md.Dwell()) {
+			return ctx.Err()
+		}
+		if md.Action != "" {
+			if err := runWaypointAction(ctx, actions, md.Action, pending); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// executeTrajectory executes the trajectory, limiting the speed of the named frame if maxSpeedMMPerSec is positive or any safety zones
+// are configured.
+func (ms *builtIn) executeTrajectory(
+	ctx context.Context,
+	fs referenceframe.FrameSystem,
+	trajectory motionplan.Trajectory,
+	frameName string,
+	maxSpeedMMPerSec float64,
+	mobile *mobileBase,
+) error {
+	if maxSpeedMMPerSec <= 0 && len(ms.safetyZones) == 0 {
+		return ms.execute(ctx, trajectory, mobile)
+	}
+	return ms.executeSpeedLimited(ctx, fs, trajectory, frameName, maxSpeedMMPerSec, mobile)
+}
+
+// stateAt returns the inputs of every frame of the trajectory as of the given step.
+func stateAt(trajectory motionplan.Trajectory, step int) referenceframe.FrameSystemInputs {
+	state := referenceframe.FrameSystemInputs{}
+	for _, s := range trajectory[:step+1] {
+		state = mergeInputs(state, s)
+	}
+	return state
+}
+
+// checkArrival returns an error if the component is further than the tolerance from where the planned inputs place it.
+func (ms *builtIn) checkArrival(
+	ctx context.Context,
+	fs referenceframe.FrameSystem,
+	mobile *mobileBase,
+	componentName string,
+	planned referenceframe.FrameSystemInputs,
+	toleranceMM float64,
+) error {
+	current, _, err := ms.fsService.CurrentInputs(ctx)
+	if err != nil {
+		return err
+	}
+	mobile.addTo(current, nil)
+	plannedPose, err := framePoseInWorld(fs, mergeInputs(current, planned), componentName)
+	if err != nil {
+		return err
+	}
+	actualPose, err := framePoseInWorld(fs, current, componentName)
+	if err != nil {
+		return err
+	}
+	if dist := actualPose.Point().Distance(plannedPose.Point()); dist > toleranceMM {
+		return fmt.Errorf("%s arrived %.1fmm from its waypoint, outside the tolerance of %.1fmm", componentName, dist, toleranceMM)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package builtin
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/arm"
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestAnnotateWaypoints(t *testing.T) {
+	fs := referenceframe.NewEmptyFrameSystem("test")
+	slider, err := referenceframe.NewTranslationalFrame("slider", r3.Vector{X: 1}, referenceframe.Limit{Min: 0, Max: 1000})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(slider, fs.World()), test.ShouldBeNil)
+
+	trajectory := motionplan.Trajectory{}
+	for _, x := range []float64{0, 100, 200, 300, 400} {
+		trajectory = append(trajectory, referenceframe.FrameSystemInputs{"slider": referenceframe.FloatsToInputs([]float64{x})})
+	}
+	plan := motionplan.NewSimplePlan(nil, trajectory)
+	at := func(x float64) *motionplan.PlanState {
+		return motionplan.NewPlanState(referenceframe.FrameSystemPoses{
+			"slider": referenceframe.NewPoseInFrame(referenceframe.World, spatialmath.NewPoseFromPoint(r3.Vector{X: x})),
+		}, nil)
+	}
+
+	// plans through waypoints without metadata are left alone
+	annotated, err := annotateWaypoints(fs, plan, "slider", []*motionplan.PlanState{at(210), at(400)})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, annotated, test.ShouldEqual, plan)
+
+	slow := &motionplan.WaypointMetadata{MaxSpeedMMPerSec: 10}
+	photograph := &motionplan.WaypointMetadata{Action: "photograph"}
+	pause := &motionplan.WaypointMetadata{DwellSeconds: 1}
+	annotated, err = annotateWaypoints(fs, plan, "slider", []*motionplan.PlanState{
+		at(90).WithMetadata(slow),
+		at(310),
+		motionplan.NewPlanState(nil, referenceframe.FrameSystemInputs{
+			"slider": referenceframe.FloatsToInputs([]float64{290}),
+		}).WithMetadata(photograph),
+		at(400).WithMetadata(pause),
+	})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, motionplan.PlanWaypointMetadata(annotated), test.ShouldResemble, map[int]*motionplan.WaypointMetadata{
+		1: slow,
+		3: photograph,
+		4: pause,
+	})
+	test.That(t, annotated.Trajectory(), test.ShouldResemble, trajectory)
+}
+
+func TestMoveThroughWaypointsWithMetadata(t *testing.T) {
+	ctx := context.Background()
+	ms, teardown := setupMotionServiceFromConfig(t, "../data/moving_arm.json")
+	defer teardown()
+
+	var commands []map[string]interface{}
+	camera := inject.NewGenericComponent("camera")
+	camera.DoFunc = func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+		commands = append(commands, cmd)
+		return nil, nil
+	}
+	ms.(*builtIn).waypointActions = map[string]waypointAction{
+		"photograph": {resource: camera, command: map[string]interface{}{"capture": true}},
+	}
+
+	// the arm passes through a waypoint halfway to its destination
+	startPose, err := ms.GetPose(ctx, arm.Named("pieceArm"), referenceframe.World, nil, nil)
+	test.That(t, err, test.ShouldBeNil)
+	halfway := referenceframe.NewPoseInFrame(referenceframe.World, spatialmath.Compose(
+		startPose.Pose(),
+		spatialmath.NewPoseFromPoint(r3.Vector{Y: -15, Z: -25}),
+	))
+	move := func(md *motionplan.WaypointMetadata) error {
+		waypoint := motionplan.NewPlanState(referenceframe.FrameSystemPoses{"pieceArm": halfway}, nil).WithMetadata(md)
+		_, err := ms.Move(ctx, motion.MoveReq{
+			ComponentName: arm.Named("pieceArm"),
+			Destination:   referenceframe.NewPoseInFrame("pieceArm", spatialmath.NewPoseFromPoint(r3.Vector{Y: -30, Z: -50})),
+			Extra:         map[string]interface{}{"waypoints": []interface{}{waypoint.Serialize()}},
+		})
+		return err
+	}
+
+	// the arm pauses at the waypoint, then runs its action
+	start := time.Now()
+	test.That(t, move(&motionplan.WaypointMetadata{ToleranceMM: 1, DwellSeconds: 0.3, Action: "photograph"}), test.ShouldBeNil)
+	test.That(t, time.Since(start), test.ShouldBeGreaterThanOrEqualTo, 300*time.Millisecond)
+	test.That(t, commands, test.ShouldResemble, []map[string]interface{}{{"capture": true}})
+
+	// actions which are not configured fail the move
+	test.That(t, move(&motionplan.WaypointMetadata{Action: "wave"}), test.ShouldNotBeNil)
+}
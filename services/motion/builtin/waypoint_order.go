@@ -0,0 +1,45 @@
+package builtin
+
+import (
+	"fmt"
+
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// optimizeWaypointOrderExtraKey is the key of extra through which Move is told that it may visit its waypoints and destination in
+// whichever order makes the path through them shortest, rather than in the order given. Every one of them must be a pose of the
+// moving component.
+const optimizeWaypointOrderExtraKey = "optimize_waypoint_order"
+
+// parseOptimizeWaypointOrder reads whether a request may reorder its waypoints.
+func parseOptimizeWaypointOrder(extra map[string]interface{}) (bool, error) {
+	raw, ok := extra[optimizeWaypointOrderExtraKey]
+	if !ok {
+		return false, nil
+	}
+	optimize, ok := raw.(bool)
+	if !ok {
+		return false, fmt.Errorf("could not interpret %s field as bool", optimizeWaypointOrderExtraKey)
+	}
+	return optimize, nil
+}
+
+// orderWaypoints returns the waypoints, each of which must place the named frame at a pose in the world frame, in the order which
+// visits them along the shortest path from the start pose of the frame.
+func orderWaypoints(start spatialmath.Pose, waypoints []*motionplan.PlanState, frame string) ([]*motionplan.PlanState, error) {
+	goals := make([]spatialmath.Pose, 0, len(waypoints))
+	for _, wp := range waypoints {
+		goal, ok := wp.Poses()[frame]
+		if !ok || len(wp.Poses()) != 1 || len(wp.Configuration()) > 0 {
+			return nil, fmt.Errorf("can only reorder waypoints which are each a pose of %s alone, as %s requests", frame,
+				optimizeWaypointOrderExtraKey)
+		}
+		goals = append(goals, goal.Pose())
+	}
+	ordered := make([]*motionplan.PlanState, 0, len(waypoints))
+	for _, i := range motionplan.OrderGoals(start, goals) {
+		ordered = append(ordered, waypoints[i])
+	}
+	return ordered, nil
+}
@@ -0,0 +1,40 @@
+package builtin
+
+import (
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+func TestOrderWaypoints(t *testing.T) {
+	optimize, err := parseOptimizeWaypointOrder(map[string]interface{}{})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, optimize, test.ShouldBeFalse)
+	optimize, err = parseOptimizeWaypointOrder(map[string]interface{}{optimizeWaypointOrderExtraKey: true})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, optimize, test.ShouldBeTrue)
+	_, err = parseOptimizeWaypointOrder(map[string]interface{}{optimizeWaypointOrderExtraKey: "yes"})
+	test.That(t, err, test.ShouldNotBeNil)
+
+	at := func(x float64) *motionplan.PlanState {
+		return motionplan.NewPlanState(referenceframe.FrameSystemPoses{
+			"gripper": referenceframe.NewPoseInFrame(referenceframe.World, spatialmath.NewPoseFromPoint(r3.Vector{X: x})),
+		}, nil)
+	}
+	far, near, middle := at(300), at(100), at(200)
+	ordered, err := orderWaypoints(spatialmath.NewZeroPose(), []*motionplan.PlanState{far, near, middle}, "gripper")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ordered, test.ShouldResemble, []*motionplan.PlanState{near, middle, far})
+
+	// waypoints which are configurations, or poses of other frames, cannot be reordered
+	configuration := motionplan.NewPlanState(nil, referenceframe.FrameSystemInputs{"arm": {{Value: 1}}})
+	_, err = orderWaypoints(spatialmath.NewZeroPose(), []*motionplan.PlanState{far, configuration}, "gripper")
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = orderWaypoints(spatialmath.NewZeroPose(), []*motionplan.PlanState{far}, "arm")
+	test.That(t, err, test.ShouldNotBeNil)
+}
@@ -0,0 +1,184 @@
+package builtin
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/components/base/kinematicbase"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/motionplan/tpspace"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/robot/framesystem"
+	"go.viam.com/rdk/spatialmath"
+)
+
+const (
+	// wholeBodyExtraKey plans a Move request for the base carrying the component as well as the component itself.
+	wholeBodyExtraKey = "whole_body"
+	// wholeBodyReachExtraKey limits how far from its starting position the base may be planned to drive.
+	wholeBodyReachExtraKey = "whole_body_reach_mm"
+	// defaultWholeBodyReachMM is how far the base may drive when the request does not limit it.
+	defaultWholeBodyReachMM = 2000.
+)
+
+// mobileBase is the base carrying the component of a whole body Move request. Its frame is replaced in the planning frame system by
+// a frame positioning the base relative to where it started, since the robot frame system treats the base as static.
+type mobileBase struct {
+	kb          kinematicbase.KinematicBase
+	frameSystem referenceframe.FrameSystem
+
+	// last holds the most recently reached inputs of the base, relative to where it started
+	mu   sync.Mutex
+	last []referenceframe.Input
+}
+
+// wholeBodyBase returns the base carrying the moving frame if the request plans for the whole body, and nil otherwise. The frame
+// of the base in the given frame system is replaced by one which can be planned for.
+func (ms *builtIn) wholeBodyBase(
+	ctx context.Context,
+	frameSys referenceframe.FrameSystem,
+	movingFrame referenceframe.Frame,
+	extra map[string]interface{},
+) (*mobileBase, error) {
+	enabled, reachMM, err := parseWholeBody(extra)
+	if err != nil || !enabled {
+		return nil, err
+	}
+	ancestors, err := frameSys.TracebackFrame(movingFrame)
+	if err != nil {
+		return nil, err
+	}
+	for _, frame := range ancestors[1:] {
+		component, ok := findByShortName(ms.components, frame.Name())
+		if !ok {
+			continue
+		}
+		if b, ok := component.(base.Base); ok {
+			return newMobileBase(ctx, b, frameSys, reachMM, ms.logger)
+		}
+	}
+	return nil, fmt.Errorf("cannot plan for the whole body of %s, which is not mounted on a base", movingFrame.Name())
+}
+
+func newMobileBase(
+	ctx context.Context,
+	b base.Base,
+	frameSys referenceframe.FrameSystem,
+	reachMM float64,
+	logger logging.Logger,
+) (*mobileBase, error) {
+	// the heading of the base matters to the component it carries, so the base is planned for in x, y and theta
+	options := kinematicbase.NewKinematicBaseOptions()
+	options.UsePTGs = false
+	options.PositionOnlyMode = false
+	limits := []referenceframe.Limit{
+		{Min: -reachMM, Max: reachMM},
+		{Min: -reachMM, Max: reachMM},
+		{Min: -2 * math.Pi, Max: 2 * math.Pi},
+	}
+	kb, err := kinematicbase.WrapWithKinematics(ctx, b, logger, nil, limits, options)
+	if err != nil {
+		return nil, fmt.Errorf("cannot plan for the whole body on base %s: %w", b.Name().ShortName(), err)
+	}
+	if _, ok := kb.Kinematics().(tpspace.PTGProvider); ok {
+		return nil, fmt.Errorf("cannot plan for the whole body on base %s, which can only follow PTG trajectories", b.Name().ShortName())
+	}
+	if err := frameSys.ReplaceFrame(kb.Kinematics()); err != nil {
+		return nil, err
+	}
+	return &mobileBase{
+		kb:          kb,
+		frameSystem: frameSys,
+		last:        make([]referenceframe.Input, len(kb.Kinematics().DoF())),
+	}, nil
+}
+
+// addTo adds the base to the given inputs and resources of the frame system, if there is a base.
+func (mb *mobileBase) addTo(inputs referenceframe.FrameSystemInputs, resources map[string]framesystem.InputEnabled) {
+	if mb == nil {
+		return
+	}
+	name := mb.kb.Kinematics().Name()
+	if inputs != nil {
+		mb.mu.Lock()
+		inputs[name] = mb.last
+		mb.mu.Unlock()
+	}
+	if resources != nil {
+		resources[name] = mb
+	}
+}
+
+// CurrentInputs returns the most recently reached inputs of the base.
+func (mb *mobileBase) CurrentInputs(ctx context.Context) ([]referenceframe.Input, error) {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+	return mb.last, nil
+}
+
+// GoToInputs drives the base through the given inputs, which are relative to where the base started. The base has no localizer,
+// so each step is driven as a move relative to the previous one.
+func (mb *mobileBase) GoToInputs(ctx context.Context, inputSteps ...[]referenceframe.Input) error {
+	frame := mb.kb.Kinematics()
+	for _, step := range inputSteps {
+		mb.mu.Lock()
+		last := mb.last
+		mb.mu.Unlock()
+		from, err := frame.Transform(last)
+		if err != nil {
+			return err
+		}
+		to, err := frame.Transform(step)
+		if err != nil {
+			return err
+		}
+		delta := spatialmath.PoseBetween(from, to)
+		relative := []referenceframe.Input{
+			{Value: delta.Point().X},
+			{Value: delta.Point().Y},
+			{Value: delta.Orientation().OrientationVectorRadians().Theta},
+		}
+		if err := mb.kb.GoToInputs(ctx, relative); err != nil {
+			return err
+		}
+		mb.mu.Lock()
+		mb.last = step
+		mb.mu.Unlock()
+	}
+	return nil
+}
+
+// IsMoving returns whether the base is moving.
+func (mb *mobileBase) IsMoving(ctx context.Context) (bool, error) {
+	return mb.kb.IsMoving(ctx)
+}
+
+// Stop stops the base.
+func (mb *mobileBase) Stop(ctx context.Context, extra map[string]interface{}) error {
+	return mb.kb.Stop(ctx, extra)
+}
+
+// parseWholeBody reads whether a Move request plans for the base carrying the component, and how far the base may drive.
+func parseWholeBody(extra map[string]interface{}) (bool, float64, error) {
+	raw, ok := extra[wholeBodyExtraKey]
+	if !ok {
+		return false, 0, nil
+	}
+	enabled, ok := raw.(bool)
+	if !ok {
+		return false, 0, fmt.Errorf("could not interpret %s field as bool", wholeBodyExtraKey)
+	}
+	reachMM := defaultWholeBodyReachMM
+	if reachRaw, ok := extra[wholeBodyReachExtraKey]; ok {
+		if reachMM, ok = reachRaw.(float64); !ok {
+			return false, 0, fmt.Errorf("could not interpret %s field as float", wholeBodyReachExtraKey)
+		}
+		if reachMM <= 0 {
+			return false, 0, fmt.Errorf("%s must be positive", wholeBodyReachExtraKey)
+		}
+	}
+	return enabled, reachMM, nil
+}
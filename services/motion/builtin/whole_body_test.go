@@ -0,0 +1,122 @@
+package builtin
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/components/base/kinematicbase"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+// recordingKinematicBase records the inputs it is asked to go to.
+type recordingKinematicBase struct {
+	kinematicbase.KinematicBase
+	frame referenceframe.Frame
+	steps [][]referenceframe.Input
+}
+
+func (rkb *recordingKinematicBase) Kinematics() referenceframe.Frame {
+	return rkb.frame
+}
+
+func (rkb *recordingKinematicBase) GoToInputs(ctx context.Context, inputSteps ...[]referenceframe.Input) error {
+	rkb.steps = append(rkb.steps, inputSteps...)
+	return nil
+}
+
+func TestWholeBody(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+
+	// newFrameSystem returns a frame system with an arm, modeled as a single sliding joint, mounted on a base
+	newFrameSystem := func(t *testing.T) referenceframe.FrameSystem {
+		t.Helper()
+		fs := referenceframe.NewEmptyFrameSystem("test")
+		originFrame, err := referenceframe.NewStaticFrame("base_origin", spatialmath.NewPoseFromPoint(r3.Vector{X: 1000}))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, fs.AddFrame(originFrame, fs.World()), test.ShouldBeNil)
+		baseFrame := referenceframe.NewZeroStaticFrame("base")
+		test.That(t, fs.AddFrame(baseFrame, originFrame), test.ShouldBeNil)
+		armFrame, err := referenceframe.NewTranslationalFrame("arm", r3.Vector{Y: 1}, referenceframe.Limit{Min: 0, Max: 500})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, fs.AddFrame(armFrame, baseFrame), test.ShouldBeNil)
+		otherFrame, err := referenceframe.NewStaticFrame("other", spatialmath.NewZeroPose())
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, fs.AddFrame(otherFrame, fs.World()), test.ShouldBeNil)
+		return fs
+	}
+	b := inject.NewBase("base")
+	b.PropertiesFunc = func(ctx context.Context, extra map[string]interface{}) (base.Properties, error) {
+		return base.Properties{WidthMeters: 0.5}, nil
+	}
+	b.GeometriesFunc = func(ctx context.Context) ([]spatialmath.Geometry, error) {
+		return nil, nil
+	}
+	ms := &builtIn{logger: logger, components: map[resource.Name]resource.Resource{b.Name(): b}}
+
+	t.Run("the base carrying the component is planned for", func(t *testing.T) {
+		fs := newFrameSystem(t)
+		mobile, err := ms.wholeBodyBase(ctx, fs, fs.Frame("arm"), map[string]interface{}{wholeBodyExtraKey: true})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, mobile, test.ShouldNotBeNil)
+		test.That(t, len(fs.Frame("base").DoF()), test.ShouldEqual, 3)
+
+		// the base starts where the frame system places it
+		inputs := referenceframe.FrameSystemInputs{"arm": {{Value: 0}}}
+		mobile.addTo(inputs, nil)
+		pose, err := framePoseInWorld(fs, inputs, "arm")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, spatialmath.PoseAlmostCoincident(pose, spatialmath.NewPoseFromPoint(r3.Vector{X: 1000})), test.ShouldBeTrue)
+	})
+
+	t.Run("the base is only planned for when requested", func(t *testing.T) {
+		fs := newFrameSystem(t)
+		mobile, err := ms.wholeBodyBase(ctx, fs, fs.Frame("arm"), nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, mobile, test.ShouldBeNil)
+		test.That(t, fs.Frame("base").DoF(), test.ShouldBeEmpty)
+
+		_, err = ms.wholeBodyBase(ctx, fs, fs.Frame("other"), map[string]interface{}{wholeBodyExtraKey: true})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "not mounted on a base")
+		_, err = ms.wholeBodyBase(ctx, fs, fs.Frame("arm"), map[string]interface{}{wholeBodyExtraKey: "yes"})
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = ms.wholeBodyBase(ctx, fs, fs.Frame("arm"), map[string]interface{}{wholeBodyExtraKey: true, wholeBodyReachExtraKey: -1.})
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("the base drives each step relative to the last", func(t *testing.T) {
+		frame, err := referenceframe.New2DMobileModelFrame("base", []referenceframe.Limit{
+			{Min: -2000, Max: 2000}, {Min: -2000, Max: 2000}, {Min: -2 * math.Pi, Max: 2 * math.Pi},
+		}, nil)
+		test.That(t, err, test.ShouldBeNil)
+		kb := &recordingKinematicBase{frame: frame}
+		mobile := &mobileBase{kb: kb, last: make([]referenceframe.Input, 3)}
+
+		err = mobile.GoToInputs(ctx,
+			[]referenceframe.Input{{Value: 0}, {Value: 500}, {Value: 0}},
+			[]referenceframe.Input{{Value: 0}, {Value: 500}, {Value: math.Pi / 2}},
+			[]referenceframe.Input{{Value: 500}, {Value: 500}, {Value: math.Pi / 2}},
+		)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(kb.steps), test.ShouldEqual, 3)
+		expected := [][]float64{{0, 500, 0}, {0, 0, math.Pi / 2}, {0, -500, 0}}
+		for i, step := range kb.steps {
+			for j, input := range step {
+				test.That(t, input.Value, test.ShouldAlmostEqual, expected[i][j], 1e-6)
+			}
+		}
+		current, err := mobile.CurrentInputs(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, current[0].Value, test.ShouldEqual, 500)
+	})
+}
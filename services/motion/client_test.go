@@ -503,8 +503,8 @@ func TestClient(t *testing.T) {
 				Plan:          motionplan.NewSimplePlan(steps, nil),
 			}
 			statusHistory := []motion.PlanStatus{
-				{motion.PlanStateFailed, timeB, &reason},
-				{motion.PlanStateInProgress, timeA, nil},
+				{State: motion.PlanStateFailed, Timestamp: timeB, Reason: &reason},
+				{State: motion.PlanStateInProgress, Timestamp: timeA},
 			}
 			expectedResp := []motion.PlanWithStatus{{Plan: plan, StatusHistory: statusHistory}}
 			injectMS.PlanHistoryFunc = func(ctx context.Context, req motion.PlanHistoryReq) ([]motion.PlanWithStatus, error) {
@@ -537,8 +537,8 @@ func TestClient(t *testing.T) {
 				Plan:          motionplan.NewSimplePlan(steps, nil),
 			}
 			statusHistoryA := []motion.PlanStatus{
-				{motion.PlanStateFailed, timeAB, &reason},
-				{motion.PlanStateInProgress, timeAA, nil},
+				{State: motion.PlanStateFailed, Timestamp: timeAB, Reason: &reason},
+				{State: motion.PlanStateInProgress, Timestamp: timeAA},
 			}
 
 			idB := uuid.New()
@@ -552,7 +552,7 @@ func TestClient(t *testing.T) {
 			}
 
 			statusHistoryB := []motion.PlanStatus{
-				{motion.PlanStateInProgress, timeBA, nil},
+				{State: motion.PlanStateInProgress, Timestamp: timeBA},
 			}
 
 			expectedResp := []motion.PlanWithStatus{
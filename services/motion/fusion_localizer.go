@@ -0,0 +1,244 @@
+package motion
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/golang/geo/r3"
+	geo "github.com/kellydunn/golang-geo"
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// SensorFusionLocalizerName is the name of the registered FusionLocalizer. Its sources are, in order, the movement sensor reporting
+// position, the movement sensor reporting heading, and optionally the movement sensor reporting wheel odometry.
+const SensorFusionLocalizerName = "sensor_fusion"
+
+const (
+	defaultFusionPositionGain = 0.2
+	defaultFusionHeadingGain  = 0.5
+)
+
+func init() {
+	RegisterLocalizer(SensorFusionLocalizerName, newRegisteredFusionLocalizer)
+}
+
+// FusionLocalizerConfig configures the complementary filter of a FusionLocalizer.
+type FusionLocalizerConfig struct {
+	// PositionGain, in the range (0, 1], is how far each position reading moves the estimate from the position predicted by odometry.
+	PositionGain float64
+	// HeadingGain, in the range (0, 1], is how far each heading reading moves the estimate from the heading predicted by odometry.
+	HeadingGain float64
+}
+
+// FusionLocalizer is a Localizer which fuses the position reported by a GPS, the heading reported by an IMU or compass, and the
+// velocities reported by wheel odometry with a complementary filter. Odometry predicts how the base moved since the last estimate and
+// the GPS and IMU readings correct that prediction, so the localizer continues to dead reckon while either absolute sensor is failing.
+type FusionLocalizer struct {
+	gps, imu, odometry movementsensor.MovementSensor
+	origin             *geo.Point
+	calibration        spatialmath.Pose
+	cfg                FusionLocalizerConfig
+
+	mu          sync.Mutex
+	initialized bool
+	lastUpdate  time.Time
+	position    r3.Vector
+	// theta is the right-handed heading of the estimate in degrees, where 0 faces north
+	theta float64
+}
+
+// NewFusionLocalizer creates a FusionLocalizer. The odometry sensor may be nil, in which case the readings are only smoothed.
+// As with NewMovementSensorLocalizer, poses are returned relative to the origin and adjusted by the calibration pose.
+func NewFusionLocalizer(
+	gps, imu, odometry movementsensor.MovementSensor,
+	origin *geo.Point,
+	calibration spatialmath.Pose,
+	cfg FusionLocalizerConfig,
+) (*FusionLocalizer, error) {
+	if gps == nil || imu == nil {
+		return nil, errors.New("sensor fusion requires a position sensor and a heading sensor")
+	}
+	if cfg.PositionGain == 0 {
+		cfg.PositionGain = defaultFusionPositionGain
+	}
+	if cfg.HeadingGain == 0 {
+		cfg.HeadingGain = defaultFusionHeadingGain
+	}
+	if cfg.PositionGain < 0 || cfg.PositionGain > 1 || cfg.HeadingGain < 0 || cfg.HeadingGain > 1 {
+		return nil, fmt.Errorf("sensor fusion gains must be in the range (0, 1], got %v and %v", cfg.PositionGain, cfg.HeadingGain)
+	}
+	if calibration == nil {
+		calibration = spatialmath.NewZeroPose()
+	}
+	return &FusionLocalizer{gps: gps, imu: imu, odometry: odometry, origin: origin, calibration: calibration, cfg: cfg}, nil
+}
+
+func newRegisteredFusionLocalizer(ctx context.Context, sources LocalizerSources) (ConfidentLocalizer, error) {
+	var sensors []movementsensor.MovementSensor
+	for _, r := range sources.Resources {
+		ms, ok := r.(movementsensor.MovementSensor)
+		if !ok {
+			return nil, fmt.Errorf("%s localizer sources must be movement sensors, got %s", SensorFusionLocalizerName, r.Name().ShortName())
+		}
+		sensors = append(sensors, ms)
+	}
+	if len(sensors) < 2 || len(sensors) > 3 {
+		return nil, fmt.Errorf("%s localizer requires a position, a heading and optionally an odometry sensor", SensorFusionLocalizerName)
+	}
+	var odometry movementsensor.MovementSensor
+	if len(sensors) == 3 {
+		odometry = sensors[2]
+	}
+	return NewFusionLocalizer(sensors[0], sensors[1], odometry, sources.Origin, sources.Calibration, FusionLocalizerConfig{})
+}
+
+// CurrentPosition updates the fused estimate with the latest readings and returns it.
+func (f *FusionLocalizer) CurrentPosition(ctx context.Context) (*referenceframe.PoseInFrame, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := time.Now()
+	if f.initialized {
+		f.predict(ctx, now.Sub(f.lastUpdate))
+	}
+	f.lastUpdate = now
+
+	position, positionErr := f.readPosition(ctx)
+	theta, headingErr := f.readHeading(ctx)
+	if !f.initialized {
+		if err := errors.Wrap(positionErr, "cannot initialize sensor fusion"); err != nil {
+			return nil, err
+		}
+		if err := errors.Wrap(headingErr, "cannot initialize sensor fusion"); err != nil {
+			return nil, err
+		}
+		f.position, f.theta, f.initialized = position, theta, true
+	}
+	// without odometry there is nothing to dead reckon with while an absolute sensor is failing
+	if f.odometry == nil && positionErr != nil {
+		return nil, positionErr
+	}
+	if f.odometry == nil && headingErr != nil {
+		return nil, headingErr
+	}
+
+	if positionErr == nil {
+		f.position = f.position.Add(position.Sub(f.position).Mul(f.cfg.PositionGain))
+	}
+	if headingErr == nil {
+		f.theta += f.cfg.HeadingGain * angleDifferenceDegrees(theta, f.theta)
+	}
+	f.theta = math.Mod(math.Mod(f.theta, 360)+360, 360)
+
+	pose := spatialmath.NewPose(f.position, &spatialmath.OrientationVectorDegrees{OZ: 1, Theta: f.theta})
+	return referenceframe.NewPoseInFrame(referenceframe.World, spatialmath.Compose(pose, f.calibration)), nil
+}
+
+// predict advances the estimate by the velocities reported by odometry. Failed odometry reads leave the estimate unchanged.
+func (f *FusionLocalizer) predict(ctx context.Context, dt time.Duration) {
+	if f.odometry == nil {
+		return
+	}
+	angVel, err := f.odometry.AngularVelocity(ctx, nil)
+	if err != nil {
+		return
+	}
+	linVel, err := f.odometry.LinearVelocity(ctx, nil)
+	if err != nil {
+		return
+	}
+	seconds := dt.Seconds()
+	// integrate about the midpoint heading of the interval
+	midTheta := f.theta + angVel.Z*seconds/2
+	forward := r3.Vector{X: -math.Sin(midTheta * math.Pi / 180), Y: math.Cos(midTheta * math.Pi / 180)}
+	f.position = f.position.Add(forward.Mul(linVel.Y * 1000 * seconds))
+	f.theta += angVel.Z * seconds
+}
+
+func (f *FusionLocalizer) readPosition(ctx context.Context) (r3.Vector, error) {
+	gp, _, err := f.gps.Position(ctx, nil)
+	if err != nil {
+		return r3.Vector{}, err
+	}
+	return spatialmath.GeoPointToPoint(gp, f.origin), nil
+}
+
+// readHeading returns the right-handed heading in degrees reported by the heading sensor.
+func (f *FusionLocalizer) readHeading(ctx context.Context) (float64, error) {
+	properties, err := f.imu.Properties(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case properties.CompassHeadingSupported:
+		headingLeft, err := f.imu.CompassHeading(ctx, nil)
+		if err != nil {
+			return 0, err
+		}
+		// CompassHeading is a left-handed value. Convert to be right-handed. Use math.Mod to ensure that 0 reports 0 rather than 360.
+		return math.Mod(math.Abs(headingLeft-360), 360), nil
+	case properties.OrientationSupported:
+		o, err := f.imu.Orientation(ctx, nil)
+		if err != nil {
+			return 0, err
+		}
+		return o.OrientationVectorDegrees().Theta, nil
+	default:
+		return 0, fmt.Errorf("movement sensor %s does not report a heading", f.imu.Name().ShortName())
+	}
+}
+
+// Confidence returns the steady state uncertainty of the filter given the accuracy reported by the position and heading sensors.
+func (f *FusionLocalizer) Confidence(ctx context.Context) (LocalizerConfidence, error) {
+	var conf LocalizerConfidence
+	if acc, err := f.gps.Accuracy(ctx, nil); err == nil {
+		if hdop := float64(acc.Hdop); !math.IsNaN(hdop) && hdop > 0 {
+			conf.PositionStdDevMM = hdop * userEquivalentRangeErrorMM * filterStdDevScale(f.cfg.PositionGain)
+		}
+	}
+	if acc, err := f.imu.Accuracy(ctx, nil); err == nil {
+		if compassErr := float64(acc.CompassDegreeError); !math.IsNaN(compassErr) && compassErr > 0 {
+			conf.HeadingStdDevDeg = compassErr * filterStdDevScale(f.cfg.HeadingGain)
+		}
+	}
+	return conf, nil
+}
+
+// Health returns an error if neither the absolute sensors nor odometry can currently update the estimate.
+func (f *FusionLocalizer) Health(ctx context.Context) error {
+	_, positionErr := f.readPosition(ctx)
+	_, headingErr := f.readHeading(ctx)
+	if positionErr == nil && headingErr == nil {
+		return nil
+	}
+	f.mu.Lock()
+	initialized := f.initialized
+	f.mu.Unlock()
+	if f.odometry != nil && initialized {
+		if _, err := f.odometry.LinearVelocity(ctx, nil); err == nil {
+			return nil
+		}
+	}
+	if positionErr != nil {
+		return positionErr
+	}
+	return headingErr
+}
+
+// filterStdDevScale is the ratio of the steady state standard deviation of a complementary filter with the given gain to the
+// standard deviation of its measurements.
+func filterStdDevScale(gain float64) float64 {
+	return math.Sqrt(gain / (2 - gain))
+}
+
+// angleDifferenceDegrees returns the signed difference a-b wrapped into the range [-180, 180).
+func angleDifferenceDegrees(a, b float64) float64 {
+	return math.Mod(math.Mod(a-b+180, 360)+360, 360) - 180
+}
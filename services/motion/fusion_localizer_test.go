@@ -0,0 +1,137 @@
+package motion_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/geo/r3"
+	geo "github.com/kellydunn/golang-geo"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestFusionLocalizer(t *testing.T) {
+	ctx := context.Background()
+	origin := geo.NewPoint(40, -74)
+	geoOrigin := spatialmath.NewGeoPose(origin, 0)
+
+	// gps reports the given point relative to the origin until it is told to fail
+	var mu sync.Mutex
+	var gpsPoint r3.Vector
+	var gpsErr error
+	gps := inject.NewMovementSensor("gps")
+	gps.PositionFunc = func(ctx context.Context, extra map[string]interface{}) (*geo.Point, float64, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return spatialmath.PoseToGeoPose(geoOrigin, spatialmath.NewPoseFromPoint(gpsPoint)).Location(), 0, gpsErr
+	}
+	gps.AccuracyFunc = func(ctx context.Context, extra map[string]interface{}) (*movementsensor.Accuracy, error) {
+		return &movementsensor.Accuracy{Hdop: 1}, nil
+	}
+	// the imu faces east
+	imu := createInjectedCompassMovementSensor("imu", origin)
+	imu.CompassHeadingFunc = func(ctx context.Context, extra map[string]interface{}) (float64, error) {
+		return 90, nil
+	}
+	imu.AccuracyFunc = func(ctx context.Context, extra map[string]interface{}) (*movementsensor.Accuracy, error) {
+		return movementsensor.UnimplementedOptionalAccuracies(), nil
+	}
+	// odometry reports driving forwards at 1m/s
+	odometry := inject.NewMovementSensor("odometry")
+	odometry.LinearVelocityFunc = func(ctx context.Context, extra map[string]interface{}) (r3.Vector, error) {
+		return r3.Vector{Y: 1}, nil
+	}
+	odometry.AngularVelocityFunc = func(ctx context.Context, extra map[string]interface{}) (spatialmath.AngularVelocity, error) {
+		return spatialmath.AngularVelocity{}, nil
+	}
+
+	t.Run("invalid configurations are rejected", func(t *testing.T) {
+		_, err := motion.NewFusionLocalizer(gps, nil, nil, origin, nil, motion.FusionLocalizerConfig{})
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = motion.NewFusionLocalizer(gps, imu, nil, origin, nil, motion.FusionLocalizerConfig{PositionGain: 2})
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = motion.NewLocalizer(ctx, motion.SensorFusionLocalizerName, motion.LocalizerSources{
+			Resources: []resource.Resource{gps},
+			Origin:    origin,
+		})
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("absolute readings are smoothed", func(t *testing.T) {
+		l, err := motion.NewFusionLocalizer(gps, imu, nil, origin, nil, motion.FusionLocalizerConfig{PositionGain: 0.5})
+		test.That(t, err, test.ShouldBeNil)
+		pif, err := l.CurrentPosition(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, pif.Pose().Point().Norm(), test.ShouldAlmostEqual, 0, 1)
+		test.That(t, pif.Pose().Orientation().OrientationVectorDegrees().Theta, test.ShouldAlmostEqual, -90, 1e-3)
+
+		// a jump in the gps position only moves the estimate halfway
+		mu.Lock()
+		gpsPoint = r3.Vector{Y: 1000}
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			gpsPoint = r3.Vector{}
+			mu.Unlock()
+		}()
+		pif, err = l.CurrentPosition(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, pif.Pose().Point().Y, test.ShouldAlmostEqual, 500, 1)
+
+		conf, err := l.Confidence(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, conf.PositionStdDevMM, test.ShouldAlmostEqual, 5000*0.57735, 1)
+		test.That(t, conf.HeadingStdDevDeg, test.ShouldEqual, 0)
+	})
+
+	t.Run("odometry dead reckons while the gps fails", func(t *testing.T) {
+		l, err := motion.NewLocalizer(ctx, motion.SensorFusionLocalizerName, motion.LocalizerSources{
+			Resources: []resource.Resource{gps, imu, odometry},
+			Origin:    origin,
+		})
+		test.That(t, err, test.ShouldBeNil)
+		_, err = l.CurrentPosition(ctx)
+		test.That(t, err, test.ShouldBeNil)
+
+		mu.Lock()
+		gpsErr = errors.New("no signal")
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			gpsErr = nil
+			mu.Unlock()
+		}()
+		test.That(t, l.Health(ctx), test.ShouldBeNil)
+		time.Sleep(100 * time.Millisecond)
+		pif, err := l.CurrentPosition(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		// driving east at 1m/s for at least 100ms
+		test.That(t, pif.Pose().Point().X, test.ShouldBeGreaterThanOrEqualTo, 100)
+		test.That(t, pif.Pose().Point().X, test.ShouldBeLessThan, 500)
+		test.That(t, pif.Pose().Point().Y, test.ShouldAlmostEqual, 0, 1)
+	})
+
+	t.Run("a failing gps without odometry is unhealthy", func(t *testing.T) {
+		l, err := motion.NewFusionLocalizer(gps, imu, nil, origin, nil, motion.FusionLocalizerConfig{})
+		test.That(t, err, test.ShouldBeNil)
+		mu.Lock()
+		gpsErr = errors.New("no signal")
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			gpsErr = nil
+			mu.Unlock()
+		}()
+		test.That(t, l.Health(ctx), test.ShouldNotBeNil)
+		_, err = l.CurrentPosition(ctx)
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
@@ -3,6 +3,7 @@ package motion
 import (
 	"context"
 	"math"
+	"sync"
 
 	geo "github.com/kellydunn/golang-geo"
 	"github.com/pkg/errors"
@@ -21,6 +22,28 @@ var SLAMOrientationAdjustment = spatialmath.NewPoseFromOrientation(&spatialmath.
 // Localizer is an interface which both slam and movementsensor can satisfy when wrapped respectively.
 type Localizer interface {
 	CurrentPosition(context.Context) (*referenceframe.PoseInFrame, error)
+	// Confidence returns the uncertainty of the poses returned by CurrentPosition.
+	Confidence(context.Context) (LocalizerConfidence, error)
+}
+
+// LocalizerConfidence describes the uncertainty of the poses returned by a localizer as standard deviations. A value of zero means
+// the uncertainty is unknown.
+type LocalizerConfidence struct {
+	PositionStdDevMM float64
+	HeadingStdDevDeg float64
+}
+
+// PoseCovariance is the covariance of the x and y position in millimeters and heading in degrees of a 2d pose.
+type PoseCovariance [3][3]float64
+
+// Covariance returns the covariance of the x, y and heading of the localized pose, assuming the errors are independent and the
+// position error is the same in every direction.
+func (c LocalizerConfidence) Covariance() PoseCovariance {
+	return PoseCovariance{
+		{c.PositionStdDevMM * c.PositionStdDevMM, 0, 0},
+		{0, c.PositionStdDevMM * c.PositionStdDevMM, 0},
+		{0, 0, c.HeadingStdDevDeg * c.HeadingStdDevDeg},
+	}
 }
 
 // slamLocalizer is a struct which only wraps an existing slam service.
@@ -28,7 +51,8 @@ type slamLocalizer struct {
 	slam.Service
 }
 
-// NewSLAMLocalizer creates a new Localizer that relies on a slam service to report Pose.
+// NewSLAMLocalizer creates a new Localizer that relies on a slam service to report Pose. The localizer also reports the
+// LocalizationQuality of the slam service, so that callers can tell when the poses it reports are not to be relied upon.
 func NewSLAMLocalizer(slam slam.Service) Localizer {
 	return &slamLocalizer{Service: slam}
 }
@@ -51,6 +75,8 @@ type movementSensorLocalizer struct {
 	movementsensor.MovementSensor
 	origin      *geo.Point
 	calibration spatialmath.Pose
+	// originAltitude, if set, is the altitude in meters that the heights of reported poses are relative to
+	originAltitude *float64
 }
 
 // NewMovementSensorLocalizer creates a Localizer from a MovementSensor.
@@ -65,7 +91,7 @@ func NewMovementSensorLocalizer(ms movementsensor.MovementSensor, origin *geo.Po
 
 // CurrentPosition returns a movementsensor's current position.
 func (m *movementSensorLocalizer) CurrentPosition(ctx context.Context) (*referenceframe.PoseInFrame, error) {
-	gp, _, err := m.Position(ctx, nil)
+	gp, altitude, err := m.Position(ctx, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -92,10 +118,101 @@ func (m *movementSensorLocalizer) CurrentPosition(ctx context.Context) (*referen
 		return nil, errors.New("could not get orientation from Localizer")
 	}
 
-	pose := spatialmath.NewPose(spatialmath.GeoPointToPoint(gp, m.origin), o)
+	point := spatialmath.GeoPointToPoint(gp, m.origin)
+	if m.originAltitude != nil {
+		point = spatialmath.GeoPointToPointWithAltitude(gp, m.origin, altitude, *m.originAltitude)
+	}
+	pose := spatialmath.NewPose(point, o)
 	return referenceframe.NewPoseInFrame(referenceframe.World, spatialmath.Compose(pose, m.calibration)), nil
 }
 
+// GPSTrackLocalizer is a Localizer for movement sensors which report neither a compass heading nor an orientation. It derives the
+// heading of the base from the bearing between successive positions which are at least a minimum distance apart, and so assumes
+// that the base is driving forwards whenever it moves that far.
+type GPSTrackLocalizer struct {
+	movementSensor movementsensor.MovementSensor
+	origin         *geo.Point
+	calibration    spatialmath.Pose
+	minTrackMM     float64
+
+	mu             sync.Mutex
+	anchor         *geo.Point
+	compassHeading float64
+}
+
+// NewGPSTrackLocalizer creates a GPSTrackLocalizer from a MovementSensor. As with NewMovementSensorLocalizer, poses are returned
+// relative to the origin and adjusted by the calibration pose. The localizer begins with the given compass heading, which is updated
+// each time the reported position moves at least minTrackMM from the anchor position the current heading was measured from.
+func NewGPSTrackLocalizer(
+	ms movementsensor.MovementSensor,
+	origin *geo.Point,
+	calibration spatialmath.Pose,
+	anchor *geo.Point,
+	compassHeading float64,
+	minTrackMM float64,
+) *GPSTrackLocalizer {
+	if calibration == nil {
+		calibration = spatialmath.NewZeroPose()
+	}
+	return &GPSTrackLocalizer{
+		movementSensor: ms,
+		origin:         origin,
+		calibration:    calibration,
+		minTrackMM:     minTrackMM,
+		anchor:         anchor,
+		compassHeading: compassHeading,
+	}
+}
+
+// CompassHeading returns the most recently estimated heading in degrees clockwise from north.
+func (g *GPSTrackLocalizer) CompassHeading() float64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.compassHeading
+}
+
+// CurrentPosition returns the position reported by the movement sensor along with the heading estimated from its track.
+func (g *GPSTrackLocalizer) CurrentPosition(ctx context.Context) (*referenceframe.PoseInFrame, error) {
+	gp, _, err := g.movementSensor.Position(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	g.mu.Lock()
+	if g.anchor == nil {
+		g.anchor = gp
+	} else if g.anchor.GreatCircleDistance(gp)*1e6 >= g.minTrackMM {
+		g.compassHeading = BearingToCompassHeading(g.anchor.BearingTo(gp))
+		g.anchor = gp
+	}
+	heading := g.compassHeading
+	g.mu.Unlock()
+
+	// CompassHeading is a left-handed value. Convert to be right-handed. Use math.Mod to ensure that 0 reports 0 rather than 360.
+	o := &spatialmath.OrientationVectorDegrees{OZ: 1, Theta: math.Mod(math.Abs(heading-360), 360)}
+	pose := spatialmath.NewPose(spatialmath.GeoPointToPoint(gp, g.origin), o)
+	return referenceframe.NewPoseInFrame(referenceframe.World, spatialmath.Compose(pose, g.calibration)), nil
+}
+
+// Confidence estimates the position uncertainty from the horizontal dilution of precision reported by the movement sensor. The
+// uncertainty of the heading estimated from the track is unknown.
+func (g *GPSTrackLocalizer) Confidence(ctx context.Context) (LocalizerConfidence, error) {
+	acc, err := g.movementSensor.Accuracy(ctx, nil)
+	if err != nil {
+		return LocalizerConfidence{}, err
+	}
+	var conf LocalizerConfidence
+	if hdop := float64(acc.Hdop); !math.IsNaN(hdop) && hdop > 0 {
+		conf.PositionStdDevMM = hdop * userEquivalentRangeErrorMM
+	}
+	return conf, nil
+}
+
+// BearingToCompassHeading converts a bearing in degrees in the range [-180, 180], as returned by geo.Point.BearingTo, into a compass
+// heading in the range [0, 360).
+func BearingToCompassHeading(bearing float64) float64 {
+	return math.Mod(math.Mod(bearing, 360)+360, 360)
+}
+
 // TwoDLocalizer will check the orientation of the pose of a localizer, and ensure that it is normal to the XY plane.
 // If it is not, it will be altered such that it is (accounting for e.g. an ourdoor base with one wheel on a rock). If the orientation is
 // such that the base is pointed directly up or down (or is upside-down), an error is returned.
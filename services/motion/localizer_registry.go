@@ -0,0 +1,364 @@
+package motion
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/golang/geo/r3"
+	geo "github.com/kellydunn/golang-geo"
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/components/posetracker"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/slam"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// Names of the localizers registered by this package.
+const (
+	MovementSensorLocalizerName = "movement_sensor"
+	SLAMLocalizerName           = "slam"
+	PoseTrackerLocalizerName    = "pose_tracker"
+	FusedLocalizerName          = "fused"
+)
+
+const (
+	// userEquivalentRangeErrorMM scales a horizontal dilution of precision into a position standard deviation.
+	userEquivalentRangeErrorMM = 5000
+	// nmeaFixInvalid is the GGA fix quality reported by a GPS without a position fix.
+	nmeaFixInvalid = 0
+	// defaultFusedPositionStdDevMM and defaultFusedHeadingStdDevDeg weight sources which do not report their confidence.
+	defaultFusedPositionStdDevMM = 1000
+	defaultFusedHeadingStdDevDeg = 10
+)
+
+// ConfidentLocalizer is a Localizer which can also report whether it is currently able to localize.
+type ConfidentLocalizer interface {
+	Localizer
+	Health(ctx context.Context) error
+}
+
+// LocalizerSources are the resources and reference information a registered localizer is constructed from.
+type LocalizerSources struct {
+	// Resources are the resources the localizer reads from. Most localizers use the first resource of the type they support.
+	Resources []resource.Resource
+	// Origin is the point that poses derived from GPS positions are relative to.
+	Origin *geo.Point
+	// OriginAltitude, if set, is the altitude in meters of the origin. Poses derived from GPS positions then have the height of the
+	// position above the origin as their Z, rather than zero.
+	OriginAltitude *float64
+	// Calibration adjusts the pose after it is computed, for instance to account for the offset between a sensor and the base.
+	Calibration spatialmath.Pose
+	// BodyName is the name of the body whose pose is requested from a pose tracker.
+	BodyName string
+}
+
+// LocalizerConstructor constructs a ConfidentLocalizer from the given sources.
+type LocalizerConstructor func(ctx context.Context, sources LocalizerSources) (ConfidentLocalizer, error)
+
+var (
+	localizerRegistryMu sync.RWMutex
+	localizerRegistry   = map[string]LocalizerConstructor{}
+)
+
+func init() {
+	RegisterLocalizer(MovementSensorLocalizerName, newRegisteredMovementSensorLocalizer)
+	RegisterLocalizer(SLAMLocalizerName, newRegisteredSLAMLocalizer)
+	RegisterLocalizer(PoseTrackerLocalizerName, newRegisteredPoseTrackerLocalizer)
+	RegisterLocalizer(FusedLocalizerName, newFusedLocalizer)
+}
+
+// RegisterLocalizer registers a localizer constructor under the given name so that it may be selected in motion requests.
+// It panics if a localizer is already registered with the same name.
+func RegisterLocalizer(name string, constructor LocalizerConstructor) {
+	localizerRegistryMu.Lock()
+	defer localizerRegistryMu.Unlock()
+	if _, ok := localizerRegistry[name]; ok {
+		panic(fmt.Sprintf("localizer %q is already registered", name))
+	}
+	localizerRegistry[name] = constructor
+}
+
+// RegisteredLocalizers returns the sorted names of every registered localizer.
+func RegisteredLocalizers() []string {
+	localizerRegistryMu.RLock()
+	defer localizerRegistryMu.RUnlock()
+	names := make([]string, 0, len(localizerRegistry))
+	for name := range localizerRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewLocalizer constructs the localizer registered under the given name.
+func NewLocalizer(ctx context.Context, name string, sources LocalizerSources) (ConfidentLocalizer, error) {
+	localizerRegistryMu.RLock()
+	constructor, ok := localizerRegistry[name]
+	localizerRegistryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown localizer %q, expected one of %v", name, RegisteredLocalizers())
+	}
+	return constructor(ctx, sources)
+}
+
+func newRegisteredMovementSensorLocalizer(ctx context.Context, sources LocalizerSources) (ConfidentLocalizer, error) {
+	for _, r := range sources.Resources {
+		if ms, ok := r.(movementsensor.MovementSensor); ok {
+			localizer := NewMovementSensorLocalizer(ms, sources.Origin, sources.Calibration).(*movementSensorLocalizer)
+			localizer.originAltitude = sources.OriginAltitude
+			return localizer, nil
+		}
+	}
+	return nil, fmt.Errorf("%s localizer requires a movement sensor", MovementSensorLocalizerName)
+}
+
+func newRegisteredSLAMLocalizer(ctx context.Context, sources LocalizerSources) (ConfidentLocalizer, error) {
+	for _, r := range sources.Resources {
+		if svc, ok := r.(slam.Service); ok {
+			return NewSLAMLocalizer(svc).(*slamLocalizer), nil
+		}
+	}
+	return nil, fmt.Errorf("%s localizer requires a slam service", SLAMLocalizerName)
+}
+
+func newRegisteredPoseTrackerLocalizer(ctx context.Context, sources LocalizerSources) (ConfidentLocalizer, error) {
+	if sources.BodyName == "" {
+		return nil, fmt.Errorf("%s localizer requires a body name", PoseTrackerLocalizerName)
+	}
+	for _, r := range sources.Resources {
+		if pt, ok := r.(posetracker.PoseTracker); ok {
+			return NewPoseTrackerLocalizer(pt, sources.BodyName, sources.Calibration), nil
+		}
+	}
+	return nil, fmt.Errorf("%s localizer requires a pose tracker", PoseTrackerLocalizerName)
+}
+
+// Confidence estimates the position uncertainty from the horizontal dilution of precision and the heading uncertainty from the
+// compass error reported by the movement sensor. Sensors which report the standard deviation of their position directly, such as
+// wheeled odometry, are trusted over the dilution of precision.
+func (m *movementSensorLocalizer) Confidence(ctx context.Context) (LocalizerConfidence, error) {
+	acc, err := m.Accuracy(ctx, nil)
+	if err != nil {
+		return LocalizerConfidence{}, err
+	}
+	var conf LocalizerConfidence
+	if hdop := float64(acc.Hdop); !math.IsNaN(hdop) && hdop > 0 {
+		conf.PositionStdDevMM = hdop * userEquivalentRangeErrorMM
+	}
+	if stdDev := float64(acc.AccuracyMap[movementsensor.PositionStdDevMMKey]); !math.IsNaN(stdDev) && stdDev > 0 {
+		conf.PositionStdDevMM = stdDev
+	}
+	if compassErr := float64(acc.CompassDegreeError); !math.IsNaN(compassErr) && compassErr > 0 {
+		conf.HeadingStdDevDeg = compassErr
+	}
+	return conf, nil
+}
+
+// Health returns an error if the movement sensor cannot report a position or reports that it has no fix.
+func (m *movementSensorLocalizer) Health(ctx context.Context) error {
+	if _, _, err := m.Position(ctx, nil); err != nil {
+		return err
+	}
+	acc, err := m.Accuracy(ctx, nil)
+	if err != nil {
+		// sensors which cannot report their accuracy are assumed to be healthy while they report positions
+		return nil //nolint:nilerr
+	}
+	if acc.NmeaFix == nmeaFixInvalid {
+		return fmt.Errorf("movement sensor %s has no position fix", m.Name().ShortName())
+	}
+	return nil
+}
+
+// Confidence returns an unknown confidence, as slam services do not report the uncertainty of their poses.
+func (s *slamLocalizer) Confidence(ctx context.Context) (LocalizerConfidence, error) {
+	return LocalizerConfidence{}, nil
+}
+
+// Health returns an error if the slam service cannot report a position or has lost track of its position.
+func (s *slamLocalizer) Health(ctx context.Context) error {
+	if _, err := s.Position(ctx); err != nil {
+		return err
+	}
+	quality, err := s.LocalizationQuality(ctx)
+	if err != nil {
+		// slam services which cannot report their localization quality are assumed to be healthy while they report positions
+		return nil //nolint:nilerr
+	}
+	if quality.Lost() {
+		return fmt.Errorf("slam service %s is %s", s.Name().ShortName(), quality.State)
+	}
+	return nil
+}
+
+// poseTrackerLocalizer is a struct which wraps a pose tracker tracking a single body.
+type poseTrackerLocalizer struct {
+	posetracker.PoseTracker
+	bodyName    string
+	calibration spatialmath.Pose
+}
+
+// NewPoseTrackerLocalizer creates a Localizer which reports the pose of a body tracked by a pose tracker, adjusted by the
+// calibration pose. Poses are reported in the world frame, so the pose tracker's reference frame is used as the planning frame.
+func NewPoseTrackerLocalizer(pt posetracker.PoseTracker, bodyName string, calibration spatialmath.Pose) ConfidentLocalizer {
+	if calibration == nil {
+		calibration = spatialmath.NewZeroPose()
+	}
+	return &poseTrackerLocalizer{PoseTracker: pt, bodyName: bodyName, calibration: calibration}
+}
+
+// CurrentPosition returns the tracked pose of the body.
+func (p *poseTrackerLocalizer) CurrentPosition(ctx context.Context) (*referenceframe.PoseInFrame, error) {
+	poses, err := p.Poses(ctx, []string{p.bodyName}, nil)
+	if err != nil {
+		return nil, err
+	}
+	pif, ok := poses[p.bodyName]
+	if !ok || pif == nil {
+		return nil, fmt.Errorf("pose tracker %s is not tracking body %q", p.Name().ShortName(), p.bodyName)
+	}
+	return referenceframe.NewPoseInFrame(referenceframe.World, spatialmath.Compose(pif.Pose(), p.calibration)), nil
+}
+
+// Confidence returns an unknown confidence, as pose trackers do not report the uncertainty of their poses.
+func (p *poseTrackerLocalizer) Confidence(ctx context.Context) (LocalizerConfidence, error) {
+	return LocalizerConfidence{}, nil
+}
+
+// Health returns an error if the pose tracker is not currently tracking the body.
+func (p *poseTrackerLocalizer) Health(ctx context.Context) error {
+	_, err := p.CurrentPosition(ctx)
+	return err
+}
+
+// fusedLocalizer combines the poses of several localizers, weighting each by the inverse of its variance.
+type fusedLocalizer struct {
+	sources []ConfidentLocalizer
+}
+
+// newFusedLocalizer constructs a localizer for each resource it is given, selecting the movement sensor, slam or pose tracker
+// localizer by the type of the resource. Every source must report poses in the same world frame.
+func newFusedLocalizer(ctx context.Context, sources LocalizerSources) (ConfidentLocalizer, error) {
+	fused := &fusedLocalizer{}
+	for _, r := range sources.Resources {
+		var name string
+		switch r.(type) {
+		case movementsensor.MovementSensor:
+			name = MovementSensorLocalizerName
+		case slam.Service:
+			name = SLAMLocalizerName
+		case posetracker.PoseTracker:
+			name = PoseTrackerLocalizerName
+		default:
+			return nil, fmt.Errorf("cannot fuse localization from %s of type %T", r.Name().ShortName(), r)
+		}
+		sub := sources
+		sub.Resources = []resource.Resource{r}
+		l, err := NewLocalizer(ctx, name, sub)
+		if err != nil {
+			return nil, err
+		}
+		fused.sources = append(fused.sources, l)
+	}
+	if len(fused.sources) == 0 {
+		return nil, fmt.Errorf("%s localizer requires at least one source", FusedLocalizerName)
+	}
+	return fused, nil
+}
+
+// fusedReading is a pose from one healthy source of a fused localizer along with its weights.
+type fusedReading struct {
+	pose                          spatialmath.Pose
+	positionWeight, headingWeight float64
+}
+
+func (f *fusedLocalizer) readings(ctx context.Context) ([]fusedReading, error) {
+	var readings []fusedReading
+	var errs error
+	for _, l := range f.sources {
+		if err := l.Health(ctx); err != nil {
+			errs = multierr.Combine(errs, err)
+			continue
+		}
+		pif, err := l.CurrentPosition(ctx)
+		if err != nil {
+			errs = multierr.Combine(errs, err)
+			continue
+		}
+		conf, err := l.Confidence(ctx)
+		if err != nil {
+			conf = LocalizerConfidence{}
+		}
+		if conf.PositionStdDevMM <= 0 {
+			conf.PositionStdDevMM = defaultFusedPositionStdDevMM
+		}
+		if conf.HeadingStdDevDeg <= 0 {
+			conf.HeadingStdDevDeg = defaultFusedHeadingStdDevDeg
+		}
+		readings = append(readings, fusedReading{
+			pose:           pif.Pose(),
+			positionWeight: 1 / (conf.PositionStdDevMM * conf.PositionStdDevMM),
+			headingWeight:  1 / (conf.HeadingStdDevDeg * conf.HeadingStdDevDeg),
+		})
+	}
+	if len(readings) == 0 {
+		return nil, errors.Wrap(errs, "no healthy localization sources")
+	}
+	return readings, nil
+}
+
+// CurrentPosition returns the weighted mean position and weighted circular mean heading of every healthy source.
+func (f *fusedLocalizer) CurrentPosition(ctx context.Context) (*referenceframe.PoseInFrame, error) {
+	readings, err := f.readings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var point r3.Vector
+	var positionWeight, sin, cos float64
+	for _, reading := range readings {
+		point = point.Add(reading.pose.Point().Mul(reading.positionWeight))
+		positionWeight += reading.positionWeight
+		theta := reading.pose.Orientation().OrientationVectorRadians().Theta
+		sin += reading.headingWeight * math.Sin(theta)
+		cos += reading.headingWeight * math.Cos(theta)
+	}
+	o := &spatialmath.OrientationVector{OZ: 1, Theta: math.Atan2(sin, cos)}
+	return referenceframe.NewPoseInFrame(referenceframe.World, spatialmath.NewPose(point.Mul(1/positionWeight), o)), nil
+}
+
+// Confidence returns the uncertainty of the fused pose, which is lower than that of any of its healthy sources.
+func (f *fusedLocalizer) Confidence(ctx context.Context) (LocalizerConfidence, error) {
+	readings, err := f.readings(ctx)
+	if err != nil {
+		return LocalizerConfidence{}, err
+	}
+	var positionWeight, headingWeight float64
+	for _, reading := range readings {
+		positionWeight += reading.positionWeight
+		headingWeight += reading.headingWeight
+	}
+	return LocalizerConfidence{
+		PositionStdDevMM: 1 / math.Sqrt(positionWeight),
+		HeadingStdDevDeg: 1 / math.Sqrt(headingWeight),
+	}, nil
+}
+
+// Health returns an error only if none of the sources are healthy.
+func (f *fusedLocalizer) Health(ctx context.Context) error {
+	var errs error
+	for _, l := range f.sources {
+		err := l.Health(ctx)
+		if err == nil {
+			return nil
+		}
+		errs = multierr.Combine(errs, err)
+	}
+	return errors.Wrap(errs, "no healthy localization sources")
+}
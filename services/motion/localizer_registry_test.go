@@ -0,0 +1,205 @@
+package motion_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	geo "github.com/kellydunn/golang-geo"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/services/slam"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestLocalizerRegistry(t *testing.T) {
+	ctx := context.Background()
+	origin := geo.NewPoint(40, -74)
+
+	newGPS := func(name string, hdop float32, fix int32) *inject.MovementSensor {
+		gps := createInjectedCompassMovementSensor(name, origin)
+		gps.AccuracyFunc = func(ctx context.Context, extra map[string]interface{}) (*movementsensor.Accuracy, error) {
+			return &movementsensor.Accuracy{Hdop: hdop, NmeaFix: fix, CompassDegreeError: 2}, nil
+		}
+		return gps
+	}
+	newTracker := func(name string, pose spatialmath.Pose) *inject.PoseTracker {
+		pt := inject.NewPoseTracker(name)
+		pt.PosesFunc = func(ctx context.Context, bodyNames []string, extra map[string]interface{}) (referenceframe.FrameSystemPoses, error) {
+			return referenceframe.FrameSystemPoses{"base": referenceframe.NewPoseInFrame("camera", pose)}, nil
+		}
+		return pt
+	}
+
+	t.Run("built in localizers are registered", func(t *testing.T) {
+		test.That(t, motion.RegisteredLocalizers(), test.ShouldResemble, []string{
+			motion.FusedLocalizerName,
+			motion.MovementSensorLocalizerName,
+			motion.PoseTrackerLocalizerName,
+			motion.SensorFusionLocalizerName,
+			motion.SLAMLocalizerName,
+		})
+		test.That(t, func() { motion.RegisterLocalizer(motion.SLAMLocalizerName, nil) }, test.ShouldPanic)
+
+		_, err := motion.NewLocalizer(ctx, "dead_reckoning", motion.LocalizerSources{})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "unknown localizer")
+
+		_, err = motion.NewLocalizer(ctx, motion.SLAMLocalizerName, motion.LocalizerSources{
+			Resources: []resource.Resource{newGPS("gps", 1, 4)},
+		})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "requires a slam service")
+	})
+
+	t.Run("movement sensor heights", func(t *testing.T) {
+		gps := newGPS("gps", 1, 4)
+		gps.PositionFunc = func(ctx context.Context, extra map[string]interface{}) (*geo.Point, float64, error) {
+			return origin, 105, nil
+		}
+		sources := motion.LocalizerSources{Resources: []resource.Resource{gps}, Origin: origin}
+		l, err := motion.NewLocalizer(ctx, motion.MovementSensorLocalizerName, sources)
+		test.That(t, err, test.ShouldBeNil)
+		pose, err := l.CurrentPosition(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, pose.Pose().Point().Z, test.ShouldEqual, 0)
+
+		// poses are only given heights relative to an origin altitude
+		originAltitude := 100.
+		sources.OriginAltitude = &originAltitude
+		l, err = motion.NewLocalizer(ctx, motion.MovementSensorLocalizerName, sources)
+		test.That(t, err, test.ShouldBeNil)
+		pose, err = l.CurrentPosition(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, pose.Pose().Point().Z, test.ShouldAlmostEqual, 5000)
+	})
+
+	t.Run("movement sensor confidence and health", func(t *testing.T) {
+		l, err := motion.NewLocalizer(ctx, motion.MovementSensorLocalizerName, motion.LocalizerSources{
+			Resources: []resource.Resource{newGPS("gps", 0.5, 4)},
+			Origin:    origin,
+		})
+		test.That(t, err, test.ShouldBeNil)
+		conf, err := l.Confidence(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, conf.PositionStdDevMM, test.ShouldAlmostEqual, 2500)
+		test.That(t, conf.HeadingStdDevDeg, test.ShouldAlmostEqual, 2)
+		test.That(t, l.Health(ctx), test.ShouldBeNil)
+
+		noFix, err := motion.NewLocalizer(ctx, motion.MovementSensorLocalizerName, motion.LocalizerSources{
+			Resources: []resource.Resource{newGPS("gps", 0.5, 0)},
+			Origin:    origin,
+		})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, noFix.Health(ctx), test.ShouldNotBeNil)
+
+		// sensors reporting the standard deviation of their position directly are trusted over the dilution of precision
+		odometry := newGPS("odometry", 0.5, 4)
+		odometry.AccuracyFunc = func(ctx context.Context, extra map[string]interface{}) (*movementsensor.Accuracy, error) {
+			return &movementsensor.Accuracy{Hdop: 0.5, AccuracyMap: map[string]float32{movementsensor.PositionStdDevMMKey: 300}}, nil
+		}
+		l, err = motion.NewLocalizer(ctx, motion.MovementSensorLocalizerName, motion.LocalizerSources{
+			Resources: []resource.Resource{odometry},
+			Origin:    origin,
+		})
+		test.That(t, err, test.ShouldBeNil)
+		conf, err = l.Confidence(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, conf.PositionStdDevMM, test.ShouldAlmostEqual, 300)
+	})
+
+	t.Run("pose tracker", func(t *testing.T) {
+		_, err := motion.NewLocalizer(ctx, motion.PoseTrackerLocalizerName, motion.LocalizerSources{
+			Resources: []resource.Resource{newTracker("tracker", spatialmath.NewZeroPose())},
+		})
+		test.That(t, err, test.ShouldNotBeNil)
+
+		pose := spatialmath.NewPoseFromPoint(r3.Vector{X: 100, Y: 200})
+		l, err := motion.NewLocalizer(ctx, motion.PoseTrackerLocalizerName, motion.LocalizerSources{
+			Resources: []resource.Resource{newTracker("tracker", pose)},
+			BodyName:  "base",
+		})
+		test.That(t, err, test.ShouldBeNil)
+		pif, err := l.CurrentPosition(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, pif.Parent(), test.ShouldEqual, referenceframe.World)
+		test.That(t, spatialmath.PoseAlmostEqual(pif.Pose(), pose), test.ShouldBeTrue)
+		test.That(t, l.Health(ctx), test.ShouldBeNil)
+
+		lost, err := motion.NewLocalizer(ctx, motion.PoseTrackerLocalizerName, motion.LocalizerSources{
+			Resources: []resource.Resource{newTracker("tracker", pose)},
+			BodyName:  "arm",
+		})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, lost.Health(ctx), test.ShouldNotBeNil)
+	})
+
+	t.Run("slam health follows its localization quality", func(t *testing.T) {
+		slamSvc := inject.NewSLAMService("slam")
+		slamSvc.PositionFunc = func(ctx context.Context) (spatialmath.Pose, error) {
+			return spatialmath.NewZeroPose(), nil
+		}
+		quality := slam.LocalizationQuality{State: slam.LocalizationStateTracking, Confidence: 0.9}
+		slamSvc.LocalizationQualityFunc = func(ctx context.Context) (slam.LocalizationQuality, error) {
+			return quality, nil
+		}
+		l, err := motion.NewLocalizer(ctx, motion.SLAMLocalizerName, motion.LocalizerSources{
+			Resources: []resource.Resource{slamSvc},
+		})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, l.Health(ctx), test.ShouldBeNil)
+
+		quality = slam.LocalizationQuality{State: slam.LocalizationStateRelocalizing, Confidence: 0.1}
+		err = l.Health(ctx)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "relocalizing")
+
+		// slam services which cannot report their localization quality are healthy while they report positions
+		slamSvc.LocalizationQualityFunc = func(ctx context.Context) (slam.LocalizationQuality, error) {
+			return slam.LocalizationQuality{}, errors.New("unimplemented")
+		}
+		test.That(t, l.Health(ctx), test.ShouldBeNil)
+	})
+
+	t.Run("fused sources are weighted by confidence", func(t *testing.T) {
+		precise := newGPS("precise", 0.01, 4)
+		coarse := newGPS("coarse", 1, 1)
+		coarse.PositionFunc = func(ctx context.Context, extra map[string]interface{}) (*geo.Point, float64, error) {
+			return spatialmath.PoseToGeoPose(
+				spatialmath.NewGeoPose(origin, 0), spatialmath.NewPoseFromPoint(r3.Vector{X: 1000}),
+			).Location(), 0, nil
+		}
+		l, err := motion.NewLocalizer(ctx, motion.FusedLocalizerName, motion.LocalizerSources{
+			Resources: []resource.Resource{precise, coarse},
+			Origin:    origin,
+		})
+		test.That(t, err, test.ShouldBeNil)
+		pif, err := l.CurrentPosition(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, pif.Pose().Point().X, test.ShouldBeBetween, 0, 1)
+		conf, err := l.Confidence(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, conf.PositionStdDevMM, test.ShouldBeLessThan, 50)
+		test.That(t, conf.HeadingStdDevDeg, test.ShouldAlmostEqual, 2/1.4142, 0.01)
+
+		// the fused localizer keeps reporting poses while any source is healthy
+		precise.PositionFunc = func(ctx context.Context, extra map[string]interface{}) (*geo.Point, float64, error) {
+			return nil, 0, errors.New("no signal")
+		}
+		test.That(t, l.Health(ctx), test.ShouldBeNil)
+		pif, err = l.CurrentPosition(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, pif.Pose().Point().X, test.ShouldAlmostEqual, 1000, 1)
+
+		coarse.PositionFunc = precise.PositionFunc
+		test.That(t, l.Health(ctx), test.ShouldNotBeNil)
+		_, err = l.CurrentPosition(ctx)
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
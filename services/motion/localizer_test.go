@@ -4,6 +4,7 @@ import (
 	"context"
 	"math"
 	"testing"
+	"time"
 
 	geo "github.com/kellydunn/golang-geo"
 	"go.viam.com/test"
@@ -177,3 +178,50 @@ func TestCorrectStartPose(t *testing.T) {
 		test.That(t, err.Error(), test.ShouldEqual, "orientation appears to be pointing straight down, cannot project to 2d")
 	})
 }
+
+func TestLocalizerInjectedFaults(t *testing.T) {
+	ctx := context.Background()
+	origin := geo.NewPoint(-70, 40)
+
+	t.Run("noise is deterministic for a seed", func(t *testing.T) {
+		poses := make([]spatialmath.Pose, 0, 2)
+		for i := 0; i < 2; i++ {
+			movementSensor := createInjectedCompassMovementSensor("", origin)
+			movementSensor.PositionNoiseMM = inject.NewGaussianNoise(100, 1)
+			movementSensor.HeadingNoiseDeg = inject.NewUniformNoise(5, 1)
+			localizer := motion.NewMovementSensorLocalizer(movementSensor, origin, spatialmath.NewZeroPose())
+			pif, err := localizer.CurrentPosition(ctx)
+			test.That(t, err, test.ShouldBeNil)
+			poses = append(poses, pif.Pose())
+		}
+		test.That(t, spatialmath.PoseAlmostEqual(poses[0], poses[1]), test.ShouldBeTrue)
+		test.That(t, spatialmath.PoseAlmostCoincident(poses[0], spatialmath.NewZeroPose()), test.ShouldBeFalse)
+		test.That(t, poses[0].Point().Norm(), test.ShouldBeLessThan, 1000)
+		theta := poses[0].Orientation().OrientationVectorDegrees().Theta
+		test.That(t, math.Abs(theta), test.ShouldBeLessThanOrEqualTo, 5)
+	})
+
+	t.Run("intermittent errors and latency", func(t *testing.T) {
+		movementSensor := createInjectedCompassMovementSensor("", origin)
+		movementSensor.Faults = inject.NewFaults(time.Millisecond, time.Millisecond, 0.2, 1)
+		localizer := motion.NewMovementSensorLocalizer(movementSensor, origin, spatialmath.NewZeroPose())
+
+		failures := 0
+		start := time.Now()
+		for i := 0; i < 20; i++ {
+			if _, err := localizer.CurrentPosition(ctx); err != nil {
+				test.That(t, err, test.ShouldBeError, inject.ErrInjectedFault)
+				failures++
+			}
+		}
+		test.That(t, time.Since(start), test.ShouldBeGreaterThanOrEqualTo, 20*time.Millisecond)
+		test.That(t, failures, test.ShouldBeGreaterThan, 0)
+		test.That(t, failures, test.ShouldBeLessThan, 20)
+		_, fails := movementSensor.Faults.Counts()
+		test.That(t, fails, test.ShouldBeGreaterThanOrEqualTo, failures)
+
+		movementSensor.Faults.ErrorRate = 1
+		_, err := localizer.CurrentPosition(ctx)
+		test.That(t, err, test.ShouldBeError, inject.ErrInjectedFault)
+	})
+}
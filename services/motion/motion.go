@@ -64,6 +64,8 @@ type MoveOnGlobeReq struct {
 	Destination *geo.Point
 	// Heading the component should have a when it reaches the goal.
 	// Range [0-360] Left Hand Rule (N: 0, E: 90, S: 180, W: 270)
+	// The component is turned to within its heading threshold of it at the destination, unless it is NaN or the motion profile is
+	// position only.
 	Heading float64
 	// Name of the momement sensor which can be used to derive Position & Heading
 	MovementSensorName resource.Name
@@ -175,6 +177,27 @@ type PlanID = uuid.UUID
 // ExecutionID uniquely identifies an execution.
 type ExecutionID = uuid.UUID
 
+// ReplanReasonCode categorizes why an execution replanned, so that the replans of many executions can be aggregated without
+// parsing the free-text reasons of their plan statuses.
+type ReplanReasonCode string
+
+const (
+	// ReplanReasonUnknown is a replan whose cause was not categorized.
+	ReplanReasonUnknown ReplanReasonCode = "unknown"
+	// ReplanReasonDeviation is a replan because the component deviated from its plan, or finished it away from its goal.
+	ReplanReasonDeviation ReplanReasonCode = "deviation"
+	// ReplanReasonObstacle is a replan because an obstacle blocked the plan of the component, or because what it perceives of its
+	// surroundings changed, such as its map being updated or one of its obstacle detectors becoming unavailable.
+	ReplanReasonObstacle ReplanReasonCode = "obstacle"
+	// ReplanReasonManual is a replan requested by a caller of the motion service.
+	ReplanReasonManual ReplanReasonCode = "manual"
+	// ReplanReasonRecovery is a replan after the component recovered from a problem, such as its wheels slipping or its localizer
+	// losing track of it.
+	ReplanReasonRecovery ReplanReasonCode = "recovery"
+	// ReplanReasonHorizon is a replan extending the plan beyond the planning horizon it reached towards its destination.
+	ReplanReasonHorizon ReplanReasonCode = "horizon"
+)
+
 // PlanStatusWithID describes the state of a given plan at a
 // point in time plus the PlanId, ComponentName and ExecutionID
 // the status is associated with.
@@ -183,6 +206,9 @@ type PlanStatusWithID struct {
 	ComponentName resource.Name
 	ExecutionID   ExecutionID
 	Status        PlanStatus
+	// ReplanCounts are the number of times the execution has replanned so far for each reason. The API has no field for them, so
+	// they are sent over the network at the end of the reason of the status.
+	ReplanCounts map[ReplanReasonCode]int
 }
 
 // PlanStatus describes the state of a given plan at a
@@ -192,6 +218,22 @@ type PlanStatus struct {
 	State     PlanState
 	Timestamp time.Time
 	Reason    *string
+	// ReplanReason is only set on the failed status of a plan which was replaced by a replan, and categorizes its Reason. The API has no
+	// field for it, so it is sent over the network at the end of the Reason.
+	ReplanReason ReplanReasonCode
+}
+
+// CountReplans returns the number of replans in the given plan history for each reason.
+func CountReplans(history []PlanWithStatus) map[ReplanReasonCode]int {
+	counts := map[ReplanReasonCode]int{}
+	for _, pws := range history {
+		for _, status := range pws.StatusHistory {
+			if status.ReplanReason != "" {
+				counts[status.ReplanReason]++
+			}
+		}
+	}
+	return counts
 }
 
 // PlanWithStatus contains a plan, its current status, and all state changes that came prior
@@ -432,6 +474,21 @@ type MotionConfiguration struct {
 	PlanDeviationMM       float64
 	LinearMPerSec         float64
 	AngularDegsPerSec     float64
+	// HeadingCalibrationMM is how far MoveOnGlobe drives the base straight ahead to estimate its initial heading from its GPS track
+	// when the movement sensor does not support CompassHeading. Zero disables the calibration move.
+	HeadingCalibrationMM float64
+	// SlowdownDistanceMM is the distance from the nearest obstacle detected by the obstacle detectors within which a base is slowed
+	// while executing its plan, regaining full speed once clear. Zero disables the slowdown.
+	SlowdownDistanceMM float64
+	// MinSpeedScale is the fraction of its configured speed a slowed base drives at when touching an obstacle. Zero uses a default.
+	MinSpeedScale float64
+	// DriftSensor, if set, is a movement sensor whose linear velocity estimates the drift of the medium a base moves through, such as
+	// the wind carrying a drone or the current carrying a boat, in the frame of the base. The velocities commanded to the base while
+	// executing its plan are biased against the drift.
+	DriftSensor resource.Name
+	// DriftCompensation is the fraction of the drift estimated by the DriftSensor which is compensated for. Zero compensates for all
+	// of it.
+	DriftCompensation float64
 }
 
 // SubtypeName is the name of the type of service.
@@ -481,16 +538,21 @@ func (ps PlanStatusWithID) ToProto() *pb.PlanStatusWithID {
 		PlanId:        ps.PlanID.String(),
 		ComponentName: rprotoutils.ResourceNameToProto(ps.ComponentName),
 		ExecutionId:   ps.ExecutionID.String(),
-		Status:        ps.Status.ToProto(),
+		Status:        ps.Status.toProto(ps.ReplanCounts),
 	}
 }
 
 // ToProto converts a PlanStatus to a *pb.PlanStatus.
 func (ps PlanStatus) ToProto() *pb.PlanStatus {
+	return ps.toProto(nil)
+}
+
+// toProto converts a PlanStatus to a *pb.PlanStatus, carrying the given replan counts of its execution in its reason.
+func (ps PlanStatus) toProto(replanCounts map[ReplanReasonCode]int) *pb.PlanStatus {
 	return &pb.PlanStatus{
 		State:     ps.State.ToProto(),
 		Timestamp: timestamppb.New(ps.Timestamp),
-		Reason:    ps.Reason,
+		Reason:    encodeStatusReason(ps.Reason, ps.ReplanReason, replanCounts),
 	}
 }
 
@@ -6,6 +6,17 @@ import (
 	pb "go.viam.com/api/service/motion/v1"
 
 	"go.viam.com/rdk/protoutils"
+	"go.viam.com/rdk/resource"
+)
+
+// The MotionConfiguration proto has no fields for some of the fields of MotionConfiguration, so they are carried over the wire in extra
+// under these keys.
+const (
+	headingCalibrationExtraKey = "heading_calibration_mm"
+	slowdownDistanceExtraKey   = "slowdown_distance_mm"
+	minSpeedScaleExtraKey      = "min_speed_scale"
+	driftSensorExtraKey        = "drift_sensor"
+	driftCompensationExtraKey  = "drift_compensation"
 )
 
 func configurationFromProto(motionCfg *pb.MotionConfiguration) *MotionConfiguration {
@@ -79,3 +90,59 @@ func (motionCfg MotionConfiguration) toProto() *pb.MotionConfiguration {
 	}
 	return proto
 }
+
+// withExtra returns a copy of extra which carries the fields of the configuration that its proto does not have. Extra itself is
+// returned if there are none to carry.
+func (motionCfg *MotionConfiguration) withExtra(extra map[string]interface{}) map[string]interface{} {
+	if motionCfg == nil {
+		return extra
+	}
+	fields := map[string]interface{}{}
+	for key, value := range map[string]float64{
+		headingCalibrationExtraKey: motionCfg.HeadingCalibrationMM,
+		slowdownDistanceExtraKey:   motionCfg.SlowdownDistanceMM,
+		minSpeedScaleExtraKey:      motionCfg.MinSpeedScale,
+		driftCompensationExtraKey:  motionCfg.DriftCompensation,
+	} {
+		if value > 0 {
+			fields[key] = value
+		}
+	}
+	if motionCfg.DriftSensor.Name != "" {
+		fields[driftSensorExtraKey] = motionCfg.DriftSensor.String()
+	}
+	if len(fields) == 0 {
+		return extra
+	}
+	withFields := make(map[string]interface{}, len(extra)+len(fields))
+	for k, v := range extra {
+		withFields[k] = v
+	}
+	for key, value := range fields {
+		withFields[key] = value
+	}
+	return withFields
+}
+
+// fromExtra sets the fields of the configuration that its proto does not have from extra, returning extra without them.
+func (motionCfg *MotionConfiguration) fromExtra(extra map[string]interface{}) map[string]interface{} {
+	fields := map[string]*float64{
+		headingCalibrationExtraKey: &motionCfg.HeadingCalibrationMM,
+		slowdownDistanceExtraKey:   &motionCfg.SlowdownDistanceMM,
+		minSpeedScaleExtraKey:      &motionCfg.MinSpeedScale,
+		driftCompensationExtraKey:  &motionCfg.DriftCompensation,
+	}
+	for key, field := range fields {
+		if value, ok := extra[key].(float64); ok {
+			*field = value
+			delete(extra, key)
+		}
+	}
+	if value, ok := extra[driftSensorExtraKey].(string); ok {
+		if name, err := resource.NewFromString(value); err == nil {
+			motionCfg.DriftSensor = name
+			delete(extra, driftSensorExtraKey)
+		}
+	}
+	return extra
+}
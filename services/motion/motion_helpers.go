@@ -2,6 +2,7 @@ package motion
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -12,9 +13,18 @@ import (
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/motionplan"
 	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/spatialmath"
 )
 
+const (
+	// waypointsExtraKey is the key of extra through which Move is given the states to plan through on the way to its destination.
+	waypointsExtraKey = "waypoints"
+	// optimizeWaypointOrderExtraKey is the key of extra through which Move is told that it may visit its waypoints and destination in
+	// whichever order is shortest.
+	optimizeWaypointOrderExtraKey = "optimize_waypoint_order"
+)
+
 var defaultArmPlannerOptions = &motionplan.Constraints{
 	LinearConstraint: []motionplan.LinearConstraint{},
 }
@@ -86,3 +96,170 @@ func MoveArm(ctx context.Context, logger logging.Logger, a arm.Arm, dst spatialm
 	}
 	return a.MoveThroughJointPositions(ctx, plan, nil, nil)
 }
+
+// MoveArmToPositions is a helper function which moves an arm through each of the given poses in a single continuous motion. The
+// poses are planned for together rather than one at a time, and the arm does not stop between them. If optimizeOrder is set, the poses
+// are visited in whichever order makes the path through them shortest, rather than in the order given.
+func MoveArmToPositions(ctx context.Context, logger logging.Logger, a arm.Arm, dsts []spatialmath.Pose, optimizeOrder bool) error {
+	if len(dsts) == 0 {
+		return errors.New("must give at least one pose to move to")
+	}
+	inputs, err := a.CurrentInputs(ctx)
+	if err != nil {
+		return err
+	}
+	model := a.ModelFrame()
+	start, err := model.Transform(inputs)
+	if err != nil {
+		if strings.Contains(err.Error(), referenceframe.OOBErrString) {
+			return errors.New("cannot move arm: " + err.Error())
+		}
+		return err
+	}
+	if optimizeOrder {
+		ordered := make([]spatialmath.Pose, 0, len(dsts))
+		for _, i := range motionplan.OrderGoals(start, dsts) {
+			ordered = append(ordered, dsts[i])
+		}
+		dsts = ordered
+	}
+
+	plan, err := motionplan.PlanFrameMotionThrough(ctx, logger, dsts, model, inputs, defaultArmPlannerOptions, nil)
+	if err != nil {
+		return err
+	}
+	return a.MoveThroughJointPositions(ctx, plan, nil, nil)
+}
+
+// MoveToPositionsReq describes a request to move a component through each of a set of goals in a single continuous motion.
+type MoveToPositionsReq struct {
+	// ComponentName of the component to move
+	ComponentName resource.Name
+	// Goals are visited in the order given unless OptimizeOrder is set, and the component ends at the last goal visited
+	Goals []*referenceframe.PoseInFrame
+	// The external environment to be considered for the duration of the move
+	WorldState *referenceframe.WorldState
+	// Constraints which need to be satisfied during the movement
+	Constraints *motionplan.Constraints
+	// OptimizeOrder visits the goals in whichever order makes the path through them shortest
+	OptimizeOrder bool
+	Extra         map[string]interface{}
+}
+
+// MoveReq returns the request to Move which plans a single path through each of the goals, with those before the last as waypoints.
+func (r MoveToPositionsReq) MoveReq() (MoveReq, error) {
+	if len(r.Goals) == 0 {
+		return MoveReq{}, errors.New("must give at least one goal to move to")
+	}
+	extra := make(map[string]interface{}, len(r.Extra)+2)
+	for key, value := range r.Extra {
+		extra[key] = value
+	}
+	waypoints := make([]interface{}, 0, len(r.Goals)-1)
+	for _, goal := range r.Goals[:len(r.Goals)-1] {
+		// the waypoints are converted to maps of plain values so that the request may be sent over the network
+		poseJSON, err := json.Marshal(referenceframe.PoseInFrameToProtobuf(goal))
+		if err != nil {
+			return MoveReq{}, err
+		}
+		var pose map[string]interface{}
+		if err := json.Unmarshal(poseJSON, &pose); err != nil {
+			return MoveReq{}, err
+		}
+		waypoints = append(waypoints, map[string]interface{}{"poses": map[string]interface{}{r.ComponentName.ShortName(): pose}})
+	}
+	if len(waypoints) > 0 {
+		extra[waypointsExtraKey] = waypoints
+	}
+	if r.OptimizeOrder {
+		extra[optimizeWaypointOrderExtraKey] = true
+	}
+	return MoveReq{
+		ComponentName: r.ComponentName,
+		Destination:   r.Goals[len(r.Goals)-1],
+		WorldState:    r.WorldState,
+		Constraints:   r.Constraints,
+		Extra:         extra,
+	}, nil
+}
+
+// MoveToPositions moves a component through each of the goals of the request with a single call to Move, which plans one path
+// through all of them rather than planning for and stopping at each in turn, such as for a sequence of picks and places.
+func MoveToPositions(ctx context.Context, m Service, req MoveToPositionsReq) (bool, error) {
+	moveReq, err := req.MoveReq()
+	if err != nil {
+		return false, err
+	}
+	return m.Move(ctx, moveReq)
+}
+
+// MoveArmAlongPath is a helper function which moves an arm freely to the first of the given poses, and then along the Cartesian path
+// through the rest of them in a single continuous motion, deviating from the straight line between consecutive poses by no more than
+// the tolerances given. This suits tasks such as welding or dispensing along a seam given as a dense sequence of poses.
+func MoveArmAlongPath(
+	ctx context.Context,
+	logger logging.Logger,
+	a arm.Arm,
+	path []spatialmath.Pose,
+	tolerance motionplan.LinearConstraint,
+) error {
+	inputs, err := a.CurrentInputs(ctx)
+	if err != nil {
+		return err
+	}
+	model := a.ModelFrame()
+	if _, err := model.Transform(inputs); err != nil {
+		if strings.Contains(err.Error(), referenceframe.OOBErrString) {
+			return errors.New("cannot move arm: " + err.Error())
+		}
+		return err
+	}
+
+	plan, err := motionplan.PlanFrameMotionAlongPath(ctx, logger, path, model, inputs, tolerance, nil)
+	if err != nil {
+		return err
+	}
+	return a.MoveThroughJointPositions(ctx, plan, nil, nil)
+}
+
+// MoveAlongPathReq describes a request to move a component along a Cartesian path.
+type MoveAlongPathReq struct {
+	// ComponentName of the component to move
+	ComponentName resource.Name
+	// Path is the dense sequence of poses to follow, the first of which is moved to freely
+	Path []*referenceframe.PoseInFrame
+	// The external environment to be considered for the duration of the move
+	WorldState *referenceframe.WorldState
+	// Tolerance bounds how far the component may deviate from the straight line and slerped orientation between consecutive poses
+	// of the path. A tolerance left at zero takes its default.
+	Tolerance motionplan.LinearConstraint
+	Extra     map[string]interface{}
+}
+
+// MoveAlongPath moves a component to the start of the path of the request, and then along the rest of the path with a single call
+// to Move which plans one continuous motion through each of its poses within the tolerance of the request. It returns whether both
+// moves succeeded.
+func MoveAlongPath(ctx context.Context, m Service, req MoveAlongPathReq) (bool, error) {
+	if len(req.Path) < 2 {
+		return false, errors.New("a path to follow must have at least two poses")
+	}
+	if req.Tolerance.LineToleranceMm < 0 || req.Tolerance.OrientationToleranceDegs < 0 {
+		return false, errors.New("path following tolerances may not be negative")
+	}
+	success, err := m.Move(ctx, MoveReq{
+		ComponentName: req.ComponentName,
+		Destination:   req.Path[0],
+		WorldState:    req.WorldState,
+		Extra:         req.Extra,
+	})
+	if err != nil || !success {
+		return success, err
+	}
+	return MoveToPositions(ctx, m, MoveToPositionsReq{
+		ComponentName: req.ComponentName,
+		Goals:         req.Path[1:],
+		WorldState:    req.WorldState,
+		Constraints:   &motionplan.Constraints{LinearConstraint: []motionplan.LinearConstraint{req.Tolerance}},
+		Extra:         req.Extra,
+	})
+}
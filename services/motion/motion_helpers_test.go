@@ -13,6 +13,7 @@ import (
 	"go.viam.com/rdk/components/arm/fake"
 	_ "go.viam.com/rdk/components/register"
 	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/motionplan"
 	"go.viam.com/rdk/referenceframe"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/services/motion"
@@ -137,6 +138,13 @@ func TestOOBArmMotion(t *testing.T) {
 		test.That(t, err.Error(), test.ShouldContainSubstring, referenceframe.OOBErrString)
 	})
 
+	t.Run("MoveArmToPositions fails when OOB", func(t *testing.T) {
+		poses := []spatialmath.Pose{spatialmath.NewPoseFromPoint(r3.Vector{200, 200, 200})}
+		err := motion.MoveArmToPositions(context.Background(), logger, injectedArm, poses, true)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, referenceframe.OOBErrString)
+	})
+
 	t.Run("MoveToJointPositions fails OOB and moving further OOB", func(t *testing.T) {
 		err := injectedArm.MoveToJointPositions(context.Background(), referenceframe.FloatsToInputs([]float64{0, 0, 0, 0, 0, 900}), nil)
 		test.That(t, err, test.ShouldNotBeNil)
@@ -147,3 +155,103 @@ func TestOOBArmMotion(t *testing.T) {
 		test.That(t, err, test.ShouldBeNil)
 	})
 }
+
+func TestMoveToPositions(t *testing.T) {
+	ms := inject.NewMotionService("my motion")
+	var moveReq motion.MoveReq
+	ms.MoveFunc = func(ctx context.Context, req motion.MoveReq) (bool, error) {
+		moveReq = req
+		return true, nil
+	}
+	gripper := resource.NewName(resource.APINamespaceRDK.WithComponentType("gripper"), "gripper")
+	goals := []*referenceframe.PoseInFrame{
+		referenceframe.NewPoseInFrame(referenceframe.World, spatialmath.NewPoseFromPoint(r3.Vector{X: 100})),
+		referenceframe.NewPoseInFrame("table", spatialmath.NewPoseFromPoint(r3.Vector{Y: 200})),
+		referenceframe.NewPoseInFrame(referenceframe.World, spatialmath.NewPoseFromPoint(r3.Vector{Z: 300})),
+	}
+
+	_, err := motion.MoveToPositions(context.Background(), ms, motion.MoveToPositionsReq{ComponentName: gripper})
+	test.That(t, err, test.ShouldNotBeNil)
+
+	extra := map[string]interface{}{"timeout": 5.}
+	success, err := motion.MoveToPositions(context.Background(), ms, motion.MoveToPositionsReq{
+		ComponentName: gripper,
+		Goals:         goals,
+		OptimizeOrder: true,
+		Extra:         extra,
+	})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, success, test.ShouldBeTrue)
+	test.That(t, moveReq.ComponentName, test.ShouldResemble, gripper)
+	test.That(t, moveReq.Destination, test.ShouldEqual, goals[2])
+	test.That(t, moveReq.Extra["timeout"], test.ShouldEqual, 5.)
+	test.That(t, moveReq.Extra["optimize_waypoint_order"], test.ShouldEqual, true)
+	test.That(t, extra, test.ShouldHaveLength, 1)
+
+	// the goals before the last are planned through as waypoints
+	waypoints, ok := moveReq.Extra["waypoints"].([]interface{})
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, waypoints, test.ShouldHaveLength, 2)
+	for i, raw := range waypoints {
+		wp, err := motionplan.DeserializePlanState(raw.(map[string]interface{}))
+		test.That(t, err, test.ShouldBeNil)
+		pose := wp.Poses()[gripper.ShortName()]
+		test.That(t, pose.Parent(), test.ShouldEqual, goals[i].Parent())
+		test.That(t, spatialmath.PoseAlmostEqual(pose.Pose(), goals[i].Pose()), test.ShouldBeTrue)
+	}
+}
+
+func TestMoveAlongPath(t *testing.T) {
+	ms := inject.NewMotionService("my motion")
+	var moveReqs []motion.MoveReq
+	ms.MoveFunc = func(ctx context.Context, req motion.MoveReq) (bool, error) {
+		moveReqs = append(moveReqs, req)
+		return true, nil
+	}
+	gripper := resource.NewName(resource.APINamespaceRDK.WithComponentType("gripper"), "gripper")
+	path := []*referenceframe.PoseInFrame{}
+	for x := 0.; x <= 100; x += 10 {
+		path = append(path, referenceframe.NewPoseInFrame("table", spatialmath.NewPoseFromPoint(r3.Vector{X: x})))
+	}
+
+	_, err := motion.MoveAlongPath(context.Background(), ms, motion.MoveAlongPathReq{ComponentName: gripper, Path: path[:1]})
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = motion.MoveAlongPath(context.Background(), ms, motion.MoveAlongPathReq{
+		ComponentName: gripper,
+		Path:          path,
+		Tolerance:     motionplan.LinearConstraint{OrientationToleranceDegs: -1},
+	})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, moveReqs, test.ShouldBeEmpty)
+
+	tolerance := motionplan.LinearConstraint{LineToleranceMm: 0.5, OrientationToleranceDegs: 1}
+	success, err := motion.MoveAlongPath(context.Background(), ms, motion.MoveAlongPathReq{
+		ComponentName: gripper,
+		Path:          path,
+		Tolerance:     tolerance,
+	})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, success, test.ShouldBeTrue)
+	test.That(t, moveReqs, test.ShouldHaveLength, 2)
+
+	// the start of the path is approached freely
+	test.That(t, moveReqs[0].Destination, test.ShouldEqual, path[0])
+	test.That(t, moveReqs[0].Constraints, test.ShouldBeNil)
+
+	// and the rest of it is followed within tolerance in one motion
+	test.That(t, moveReqs[1].Destination, test.ShouldEqual, path[len(path)-1])
+	test.That(t, moveReqs[1].Constraints.GetLinearConstraint(), test.ShouldResemble, []motionplan.LinearConstraint{tolerance})
+	waypoints, ok := moveReqs[1].Extra["waypoints"].([]interface{})
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, waypoints, test.ShouldHaveLength, len(path)-2)
+
+	// nothing is followed if the approach fails
+	moveReqs = nil
+	ms.MoveFunc = func(ctx context.Context, req motion.MoveReq) (bool, error) {
+		moveReqs = append(moveReqs, req)
+		return false, errors.New("no path to the start")
+	}
+	_, err = motion.MoveAlongPath(context.Background(), ms, motion.MoveAlongPathReq{ComponentName: gripper, Path: path})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, moveReqs, test.ShouldHaveLength, 1)
+}
@@ -555,6 +555,61 @@ func TestPlanStatus(t *testing.T) {
 	})
 }
 
+func TestCountReplans(t *testing.T) {
+	reason := "some reason"
+	history := []PlanWithStatus{
+		{StatusHistory: []PlanStatus{{State: PlanStateInProgress}}},
+		{StatusHistory: []PlanStatus{
+			{State: PlanStateFailed, Reason: &reason, ReplanReason: ReplanReasonObstacle},
+			{State: PlanStateInProgress},
+		}},
+		{StatusHistory: []PlanStatus{
+			{State: PlanStateFailed, Reason: &reason, ReplanReason: ReplanReasonObstacle},
+			{State: PlanStateInProgress},
+		}},
+		{StatusHistory: []PlanStatus{
+			{State: PlanStateFailed, Reason: &reason, ReplanReason: ReplanReasonDeviation},
+			{State: PlanStateInProgress},
+		}},
+	}
+	test.That(t, CountReplans(history), test.ShouldResemble, map[ReplanReasonCode]int{
+		ReplanReasonObstacle:  2,
+		ReplanReasonDeviation: 1,
+	})
+	test.That(t, CountReplans(nil), test.ShouldBeEmpty)
+}
+
+func TestReplanMetadataOverNetwork(t *testing.T) {
+	timestamp := time.Now().UTC()
+	timestampb := timestamppb.New(timestamp)
+	reason := "obstacle detected"
+
+	status := PlanStatus{State: PlanStateFailed, Timestamp: timestamp, Reason: &reason, ReplanReason: ReplanReasonObstacle}
+	res, err := planStatusFromProto(status.ToProto())
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, res, test.ShouldResemble, status)
+
+	withID := PlanStatusWithID{
+		PlanID:        uuid.New(),
+		ExecutionID:   uuid.New(),
+		ComponentName: base.Named("my-base1"),
+		Status:        PlanStatus{State: PlanStateInProgress, Timestamp: timestamp},
+		ReplanCounts:  map[ReplanReasonCode]int{ReplanReasonObstacle: 2, ReplanReasonDeviation: 1},
+	}
+	withIDPB := withID.ToProto()
+	test.That(t, *withIDPB.Status.Reason, test.ShouldEqual, "[replan_counts=deviation:1,obstacle:2]")
+	resWithID, err := planStatusWithIDFromProto(withIDPB)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resWithID, test.ShouldResemble, withID)
+
+	// reasons which merely end in brackets are left alone
+	bracketed := "blocked [by a wall]"
+	res, err = planStatusFromProto(&pb.PlanStatus{State: pb.PlanState_PLAN_STATE_FAILED, Timestamp: timestampb, Reason: &bracketed})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, *res.Reason, test.ShouldEqual, bracketed)
+	test.That(t, res.ReplanReason, test.ShouldBeEmpty)
+}
+
 func TestPlan(t *testing.T) {
 	planID := uuid.New()
 	executionID := uuid.New()
@@ -1061,6 +1116,21 @@ func TestMoveOnGlobeReq(t *testing.T) {
 			test.That(t, math.IsNaN(res.Heading), test.ShouldBeTrue)
 		})
 	})
+
+	t.Run("heading calibration round trips through extra", func(t *testing.T) {
+		mogReq := validMoveOnGlobeRequest()
+		mogReq.MotionCfg.HeadingCalibrationMM = 1500
+		mogReq.Extra = map[string]interface{}{"max_replans": 2.}
+		req, err := mogReq.toProto(name)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, req.Extra.AsMap()["heading_calibration_mm"], test.ShouldEqual, 1500)
+		test.That(t, mogReq.Extra, test.ShouldResemble, map[string]interface{}{"max_replans": 2.})
+
+		res, err := moveOnGlobeRequestFromProto(req)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, res.MotionCfg.HeadingCalibrationMM, test.ShouldEqual, 1500)
+		test.That(t, res.Extra, test.ShouldResemble, map[string]interface{}{"max_replans": 2.})
+	})
 }
 
 func TestMoveOnMapReq(t *testing.T) {
@@ -1306,6 +1376,43 @@ func TestMoveOnMapReq(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("speed scaling round trips through extra", func(t *testing.T) {
+		cfg := *motionCfg
+		cfg.SlowdownDistanceMM = 800
+		cfg.MinSpeedScale = 0.5
+		momReq := validMoveOnMapReq
+		momReq.MotionCfg = &cfg
+		momReq.Extra = map[string]interface{}{"max_replans": 2.}
+		req, err := momReq.toProto("bloop")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, req.Extra.AsMap()["slowdown_distance_mm"], test.ShouldEqual, 800)
+		test.That(t, req.Extra.AsMap()["min_speed_scale"], test.ShouldEqual, 0.5)
+		test.That(t, momReq.Extra, test.ShouldResemble, map[string]interface{}{"max_replans": 2.})
+
+		res, err := moveOnMapRequestFromProto(req)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, res.MotionCfg.SlowdownDistanceMM, test.ShouldEqual, 800)
+		test.That(t, res.MotionCfg.MinSpeedScale, test.ShouldEqual, 0.5)
+		test.That(t, res.Extra, test.ShouldResemble, map[string]interface{}{"max_replans": 2.})
+	})
+
+	t.Run("drift compensation round trips through extra", func(t *testing.T) {
+		cfg := *motionCfg
+		cfg.DriftSensor = movementsensor.Named("current")
+		cfg.DriftCompensation = 0.8
+		momReq := validMoveOnMapReq
+		momReq.MotionCfg = &cfg
+		req, err := momReq.toProto("bloop")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, req.Extra.AsMap()["drift_sensor"], test.ShouldEqual, "rdk:component:movement_sensor/current")
+
+		res, err := moveOnMapRequestFromProto(req)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, res.MotionCfg.DriftSensor, test.ShouldResemble, movementsensor.Named("current"))
+		test.That(t, res.MotionCfg.DriftCompensation, test.ShouldEqual, 0.8)
+		test.That(t, res.Extra, test.ShouldBeEmpty)
+	})
 }
 
 func TestPlanHistoryReq(t *testing.T) {
@@ -1,7 +1,11 @@
 package motion
 
 import (
+	"fmt"
 	"math"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/google/uuid"
 	geo "github.com/kellydunn/golang-geo"
@@ -101,10 +105,12 @@ func planStatusFromProto(ps *pb.PlanStatus) (PlanStatus, error) {
 		return PlanStatus{}, errors.New("received nil *pb.PlanStatus")
 	}
 
+	reason, replanReason, _ := decodeStatusReason(ps.Reason)
 	return PlanStatus{
-		State:     planStateFromProto(ps.State),
-		Reason:    ps.Reason,
-		Timestamp: ps.Timestamp.AsTime(),
+		State:        planStateFromProto(ps.State),
+		Reason:       reason,
+		Timestamp:    ps.Timestamp.AsTime(),
+		ReplanReason: replanReason,
 	}, nil
 }
 
@@ -128,6 +134,7 @@ func planStatusWithIDFromProto(ps *pb.PlanStatusWithID) (PlanStatusWithID, error
 	if err != nil {
 		return PlanStatusWithID{}, err
 	}
+	_, _, replanCounts := decodeStatusReason(ps.Status.Reason)
 
 	if ps.ComponentName == nil {
 		return PlanStatusWithID{}, errors.New("received nil *commonpb.ResourceName")
@@ -138,9 +145,99 @@ func planStatusWithIDFromProto(ps *pb.PlanStatusWithID) (PlanStatusWithID, error
 		ComponentName: rprotoutils.ResourceNameFromProto(ps.ComponentName),
 		ExecutionID:   executionID,
 		Status:        status,
+		ReplanCounts:  replanCounts,
 	}, nil
 }
 
+// The API has no fields for the replan reason of a plan status or the replan counts of its execution, so they are sent over the
+// network as tags at the end of the reason of the status, as in "obstacle detected [replan_reason=obstacle]".
+const (
+	replanReasonTag = "replan_reason="
+	replanCountsTag = "replan_counts="
+)
+
+// encodeStatusReason returns the reason of a plan status to send over the network, tagged with its replan reason and the replan
+// counts of its execution.
+func encodeStatusReason(reason *string, replanReason ReplanReasonCode, replanCounts map[ReplanReasonCode]int) *string {
+	var tags []string
+	if replanReason != "" {
+		tags = append(tags, "["+replanReasonTag+string(replanReason)+"]")
+	}
+	if len(replanCounts) > 0 {
+		codes := make([]string, 0, len(replanCounts))
+		for code := range replanCounts {
+			codes = append(codes, string(code))
+		}
+		sort.Strings(codes)
+		counts := make([]string, 0, len(codes))
+		for _, code := range codes {
+			counts = append(counts, fmt.Sprintf("%s:%d", code, replanCounts[ReplanReasonCode(code)]))
+		}
+		tags = append(tags, "["+replanCountsTag+strings.Join(counts, ",")+"]")
+	}
+	if len(tags) == 0 {
+		return reason
+	}
+	encoded := strings.Join(tags, " ")
+	if reason != nil {
+		encoded = *reason + " " + encoded
+	}
+	return &encoded
+}
+
+// decodeStatusReason strips the tags added by encodeStatusReason from the reason of a plan status received over the network,
+// returning the reason along with the replan reason and replan counts they held.
+func decodeStatusReason(encoded *string) (*string, ReplanReasonCode, map[ReplanReasonCode]int) {
+	if encoded == nil {
+		return nil, "", nil
+	}
+	reason := *encoded
+	var replanReason ReplanReasonCode
+	var replanCounts map[ReplanReasonCode]int
+tags:
+	for strings.HasSuffix(reason, "]") {
+		start := strings.LastIndex(reason, "[")
+		if start < 0 {
+			break
+		}
+		tag := reason[start+1 : len(reason)-1]
+		switch {
+		case strings.HasPrefix(tag, replanReasonTag) && replanReason == "":
+			replanReason = ReplanReasonCode(strings.TrimPrefix(tag, replanReasonTag))
+		case strings.HasPrefix(tag, replanCountsTag) && replanCounts == nil:
+			counts, ok := parseReplanCounts(strings.TrimPrefix(tag, replanCountsTag))
+			if !ok {
+				break tags
+			}
+			replanCounts = counts
+		default:
+			break tags
+		}
+		reason = strings.TrimSuffix(reason[:start], " ")
+	}
+	if reason == "" && (replanReason != "" || replanCounts != nil) {
+		return nil, replanReason, replanCounts
+	}
+	return &reason, replanReason, replanCounts
+}
+
+// parseReplanCounts parses replan counts in the form "deviation:2,obstacle:1".
+func parseReplanCounts(s string) (map[ReplanReasonCode]int, bool) {
+	counts := map[ReplanReasonCode]int{}
+	for _, entry := range strings.Split(s, ",") {
+		code, countStr, ok := strings.Cut(entry, ":")
+		if !ok || code == "" {
+			return nil, false
+		}
+		count, err := strconv.Atoi(countStr)
+		if err != nil {
+			return nil, false
+		}
+		counts[ReplanReasonCode(code)] = count
+	}
+	return counts, true
+}
+
 // planFromProto converts a *pb.Plan to a Plan.
 func planFromProto(p *pb.Plan) (PlanWithMetadata, error) {
 	if p == nil {
@@ -203,7 +300,7 @@ func planStateFromProto(ps pb.PlanState) PlanState {
 
 // toProto converts a MoveOnGlobeRequest to a *pb.MoveOnGlobeRequest.
 func (r MoveOnGlobeReq) toProto(name string) (*pb.MoveOnGlobeRequest, error) {
-	ext, err := vprotoutils.StructToStructPb(r.Extra)
+	ext, err := vprotoutils.StructToStructPb(r.MotionCfg.withExtra(r.Extra))
 	if err != nil {
 		return nil, err
 	}
@@ -291,6 +388,7 @@ func moveOnGlobeRequestFromProto(req *pb.MoveOnGlobeRequest) (MoveOnGlobeReq, er
 	}
 	movementSensorName := rprotoutils.ResourceNameFromProto(protoMovementSensorName)
 	motionCfg := configurationFromProto(req.MotionConfiguration)
+	extra := motionCfg.fromExtra(req.Extra.AsMap())
 
 	return MoveOnGlobeReq{
 		ComponentName:      componentName,
@@ -300,7 +398,7 @@ func moveOnGlobeRequestFromProto(req *pb.MoveOnGlobeRequest) (MoveOnGlobeReq, er
 		Obstacles:          obstacles,
 		MotionCfg:          motionCfg,
 		BoundingRegions:    boundingRegionGeometries,
-		Extra:              req.Extra.AsMap(),
+		Extra:              extra,
 	}, nil
 }
 
@@ -369,18 +467,19 @@ func moveOnMapRequestFromProto(req *pb.MoveOnMapRequest) (MoveOnMapReq, error) {
 		}
 		geoms = convertedGeom
 	}
+	motionCfg := configurationFromProto(req.MotionConfiguration)
 	return MoveOnMapReq{
 		ComponentName: rprotoutils.ResourceNameFromProto(protoComponentName),
 		Destination:   spatialmath.NewPoseFromProtobuf(req.GetDestination()),
 		SlamName:      rprotoutils.ResourceNameFromProto(protoSlamServiceName),
-		MotionCfg:     configurationFromProto(req.MotionConfiguration),
+		MotionCfg:     motionCfg,
 		Obstacles:     geoms,
-		Extra:         req.Extra.AsMap(),
+		Extra:         motionCfg.fromExtra(req.Extra.AsMap()),
 	}, nil
 }
 
 func (r MoveOnMapReq) toProto(name string) (*pb.MoveOnMapRequest, error) {
-	ext, err := vprotoutils.StructToStructPb(r.Extra)
+	ext, err := vprotoutils.StructToStructPb(r.MotionCfg.withExtra(r.Extra))
 	if err != nil {
 		return nil, err
 	}
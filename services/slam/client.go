@@ -125,6 +125,23 @@ func (c *client) Properties(ctx context.Context) (Properties, error) {
 	return prop, err
 }
 
+// LocalizationQuality requests the localization quality of the slam service through DoCommand, as the slam proto has no method of
+// its own for it.
+func (c *client) LocalizationQuality(ctx context.Context) (LocalizationQuality, error) {
+	ctx, span := trace.StartSpan(ctx, "slam::client::LocalizationQuality")
+	defer span.End()
+
+	resp, err := rprotoutils.DoFromResourceClient(ctx, c.client, c.name, map[string]interface{}{DoLocalizationQuality: true})
+	if err != nil {
+		return LocalizationQuality{}, errors.Wrapf(err, "failure to get localization quality")
+	}
+	quality, ok := resp[DoLocalizationQuality].(map[string]interface{})
+	if !ok {
+		return LocalizationQuality{}, errors.New("slam service did not report its localization quality")
+	}
+	return mapToLocalizationQuality(quality)
+}
+
 func (c *client) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
 	ctx, span := trace.StartSpan(ctx, "slam::client::DoCommand")
 	defer span.End()
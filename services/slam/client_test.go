@@ -106,6 +106,11 @@ func TestClientWorkingService(t *testing.T) {
 		return propSucc, nil
 	}
 
+	qualitySucc := slam.LocalizationQuality{State: slam.LocalizationStateRelocalizing, Confidence: 0.25}
+	workingSLAMService.LocalizationQualityFunc = func(ctx context.Context) (slam.LocalizationQuality, error) {
+		return qualitySucc, nil
+	}
+
 	workingSvc, err := resource.NewAPIResourceCollection(slam.API, map[resource.Name]slam.Service{slam.Named(nameSucc): workingSLAMService})
 	test.That(t, err, test.ShouldBeNil)
 
@@ -168,6 +173,12 @@ func TestClientWorkingService(t *testing.T) {
 		test.That(t, prop.InternalStateFileType, test.ShouldEqual, propSucc.InternalStateFileType)
 		test.That(t, prop.SensorInfo, test.ShouldResemble, propSucc.SensorInfo)
 
+		// test localization quality
+		quality, err := workingSLAMClient.LocalizationQuality(context.Background())
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, quality, test.ShouldResemble, qualitySucc)
+		test.That(t, quality.Lost(), test.ShouldBeTrue)
+
 		test.That(t, conn.Close(), test.ShouldBeNil)
 	})
 
@@ -41,20 +41,24 @@ func init() {
 type SLAM struct {
 	resource.Named
 	resource.TriviallyReconfigurable
-	resource.TriviallyCloseable
 	dataCount    int
 	logger       logging.Logger
 	mapTimestamp time.Time
+	mapMerger    *slam.MapMerger
+	mapVersions  *slam.MapVersionTracker
 }
 
 // NewSLAM is a constructor for a fake slam service.
 func NewSLAM(name resource.Name, logger logging.Logger) *SLAM {
-	return &SLAM{
+	slamSvc := &SLAM{
 		Named:        name.AsNamed(),
 		logger:       logger,
 		dataCount:    -1,
 		mapTimestamp: time.Now().UTC(),
 	}
+	slamSvc.mapMerger = slam.NewMapMerger(slamSvc, logger)
+	slamSvc.mapVersions = slam.NewMapVersionTracker(slamSvc, 0, 0)
+	return slamSvc
 }
 
 func (slamSvc *SLAM) getCount() int {
@@ -109,6 +113,37 @@ func (slamSvc *SLAM) Properties(ctx context.Context) (slam.Properties, error) {
 	return prop, nil
 }
 
+// LocalizationQuality reports that the fake slam is always tracking its position.
+func (slamSvc *SLAM) LocalizationQuality(ctx context.Context) (slam.LocalizationQuality, error) {
+	_, span := trace.StartSpan(ctx, "slam::fake::LocalizationQuality")
+	defer span.End()
+	return slam.LocalizationQuality{State: slam.LocalizationStateTracking, Confidence: 1}, nil
+}
+
+// DoCommand merges maps from other mapping sessions into the fake map with the slam.DoMergeMaps and slam.DoMergeMapsStatus
+// commands, and reports the versions of the fake map and the changes between them with the slam.DoMapVersion and slam.DoMapDiff
+// commands.
+func (slamSvc *SLAM) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	resp, err := slamSvc.mapMerger.DoCommand(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+	versionResp, err := slamSvc.mapVersions.DoCommand(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range versionResp {
+		resp[k] = v
+	}
+	return resp, nil
+}
+
+// Close stops any map merges which are still running.
+func (slamSvc *SLAM) Close(ctx context.Context) error {
+	slamSvc.mapMerger.Close()
+	return nil
+}
+
 // incrementDataCount is not thread safe but that is ok as we only intend a single user to be interacting
 // with it at a time.
 func (slamSvc *SLAM) incrementDataCount() {
@@ -113,3 +113,35 @@ func protobufToSensorType(sensorType pb.SensorType) (SensorType, error) {
 		return 0, errors.New("sensor type unspecified")
 	}
 }
+
+// DoLocalizationQuality is the key of the command with which the localization quality of a slam service is requested over gRPC,
+// as the slam proto has no method of its own for it.
+const DoLocalizationQuality = "localization_quality"
+
+// localizationQualityToMap converts a LocalizationQuality to the result of a DoLocalizationQuality command.
+func localizationQualityToMap(quality LocalizationQuality) map[string]interface{} {
+	return map[string]interface{}{
+		"state":      quality.State.String(),
+		"confidence": quality.Confidence,
+	}
+}
+
+// mapToLocalizationQuality converts the result of a DoLocalizationQuality command to a LocalizationQuality.
+func mapToLocalizationQuality(m map[string]interface{}) (LocalizationQuality, error) {
+	stateName, ok := m["state"].(string)
+	if !ok {
+		return LocalizationQuality{}, errors.New("localization quality is missing its state")
+	}
+	confidence, ok := m["confidence"].(float64)
+	if !ok {
+		return LocalizationQuality{}, errors.New("localization quality is missing its confidence")
+	}
+	for _, state := range []LocalizationState{
+		LocalizationStateUnknown, LocalizationStateTracking, LocalizationStateLost, LocalizationStateRelocalizing,
+	} {
+		if state.String() == stateName {
+			return LocalizationQuality{State: state, Confidence: confidence}, nil
+		}
+	}
+	return LocalizationQuality{}, errors.Errorf("unknown localization state %q", stateName)
+}
@@ -0,0 +1,288 @@
+package slam
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"os"
+	"sync"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/golang/geo/r3"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	goutils "go.viam.com/utils"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// keys of the commands with which SLAM services that embed a MapMerger merge maps through DoCommand.
+const (
+	// DoMergeMaps starts merging a map from another mapping session into the map of the service. The input value is a map with
+	// the required keys "map_path", the PCD file of the other map, and "output_path", where the merged map is written as a PCD, and
+	// the optional keys "initial_guess" (a map with keys "x", "y" and "theta_degrees" placing the other map in this one),
+	// "max_iterations", "max_correspondence_distance_mm" and "voxel_size_mm". The output value is the id of the merge job.
+	DoMergeMaps = "merge_maps"
+	// DoMergeMapsStatus reports the state of the merge job with the given id, as a map with keys "state", "error" and, once the
+	// merge has succeeded, the "transform" aligning the other map to this one along with its "rms_error_mm" and "fitness".
+	DoMergeMapsStatus = "merge_maps_status"
+)
+
+// default alignment settings for merging maps, which suit maps with points every few centimeters whose sessions start within a
+// meter or so of each other once the initial guess is applied.
+const (
+	defaultMergeMaxIterations             = 50
+	defaultMergeMaxCorrespondenceDistance = 500.
+	defaultMergeConvergenceThreshold      = 1.
+)
+
+// MergeMapsConfig configures how one map is merged into another.
+type MergeMapsConfig struct {
+	// InitialGuess is the approximate pose of the other map in the base map, from which the alignment starts.
+	InitialGuess spatialmath.Pose
+	// ICP configures the alignment of the maps.
+	ICP pointcloud.ICPConfig
+	// VoxelSizeMM, when positive, downsamples the merged map so that the overlapping parts of the maps are not doubled up.
+	VoxelSizeMM float64
+}
+
+// MergeMaps aligns the other map to the base map and returns the union of the base map with the aligned other map, along with
+// the alignment found. This is useful when a site is mapped over several sessions, each of which starts a map of its own.
+func MergeMaps(base, other pointcloud.PointCloud, cfg MergeMapsConfig) (pointcloud.PointCloud, pointcloud.ICPResult, error) {
+	alignment, err := pointcloud.RegisterPointCloudICP(other, pointcloud.ToKDTree(base), cfg.InitialGuess, cfg.ICP)
+	if err != nil {
+		return nil, pointcloud.ICPResult{}, err
+	}
+	merged := pointcloud.NewWithPrealloc(base.Size() + other.Size())
+	var setErr error
+	base.Iterate(0, 0, func(p r3.Vector, d pointcloud.Data) bool {
+		setErr = merged.Set(p, d)
+		return setErr == nil
+	})
+	if setErr != nil {
+		return nil, pointcloud.ICPResult{}, setErr
+	}
+	other.Iterate(0, 0, func(p r3.Vector, d pointcloud.Data) bool {
+		aligned := spatialmath.Compose(alignment.Transform, spatialmath.NewPoseFromPoint(p)).Point()
+		// where the maps overlap exactly, the most confident observation is kept
+		if existing, ok := merged.At(aligned.X, aligned.Y, aligned.Z); ok && existing != nil && d != nil &&
+			existing.Value() >= d.Value() {
+			return true
+		}
+		setErr = merged.Set(aligned, d)
+		return setErr == nil
+	})
+	if setErr != nil {
+		return nil, pointcloud.ICPResult{}, setErr
+	}
+	if cfg.VoxelSizeMM > 0 {
+		if merged, err = pointcloud.VoxelDownsample(merged, cfg.VoxelSizeMM); err != nil {
+			return nil, pointcloud.ICPResult{}, err
+		}
+	}
+	return merged, alignment, nil
+}
+
+// MergeJobState is the state of an asynchronous map merge.
+type MergeJobState string
+
+// the states of map merge jobs.
+const (
+	MergeJobRunning   MergeJobState = "running"
+	MergeJobSucceeded MergeJobState = "succeeded"
+	MergeJobFailed    MergeJobState = "failed"
+)
+
+// MergeJobStatus is the progress of an asynchronous map merge.
+type MergeJobStatus struct {
+	State      MergeJobState
+	Err        error
+	OutputPath string
+	Alignment  pointcloud.ICPResult
+}
+
+type mergeMapsRequest struct {
+	MapPath                     string             `mapstructure:"map_path"`
+	OutputPath                  string             `mapstructure:"output_path"`
+	InitialGuess                *mergeInitialGuess `mapstructure:"initial_guess"`
+	MaxIterations               int                `mapstructure:"max_iterations"`
+	MaxCorrespondenceDistanceMM float64            `mapstructure:"max_correspondence_distance_mm"`
+	VoxelSizeMM                 float64            `mapstructure:"voxel_size_mm"`
+}
+
+type mergeInitialGuess struct {
+	X            float64 `mapstructure:"x"`
+	Y            float64 `mapstructure:"y"`
+	ThetaDegrees float64 `mapstructure:"theta_degrees"`
+}
+
+func (req mergeMapsRequest) config() (MergeMapsConfig, error) {
+	if req.MapPath == "" || req.OutputPath == "" {
+		return MergeMapsConfig{}, errors.New("map_path and output_path are required to merge maps")
+	}
+	if req.MaxIterations < 0 || req.MaxCorrespondenceDistanceMM < 0 || req.VoxelSizeMM < 0 {
+		return MergeMapsConfig{}, errors.New("max_iterations, max_correspondence_distance_mm and voxel_size_mm may not be negative")
+	}
+	cfg := MergeMapsConfig{
+		InitialGuess: spatialmath.NewZeroPose(),
+		ICP: pointcloud.ICPConfig{
+			MaxIterations:             defaultMergeMaxIterations,
+			MaxCorrespondenceDistance: defaultMergeMaxCorrespondenceDistance,
+			ConvergenceThreshold:      defaultMergeConvergenceThreshold,
+		},
+		VoxelSizeMM: req.VoxelSizeMM,
+	}
+	if req.InitialGuess != nil {
+		cfg.InitialGuess = spatialmath.NewPose(
+			r3.Vector{X: req.InitialGuess.X, Y: req.InitialGuess.Y},
+			&spatialmath.OrientationVectorDegrees{OZ: 1, Theta: req.InitialGuess.ThetaDegrees},
+		)
+	}
+	if req.MaxIterations > 0 {
+		cfg.ICP.MaxIterations = req.MaxIterations
+	}
+	if req.MaxCorrespondenceDistanceMM > 0 {
+		cfg.ICP.MaxCorrespondenceDistance = req.MaxCorrespondenceDistanceMM
+	}
+	return cfg, nil
+}
+
+// MapMerger runs merges of maps from other mapping sessions into the map of a SLAM service in the background, so that SLAM
+// services can offer merging through DoCommand without blocking the caller for the length of the alignment.
+type MapMerger struct {
+	slamSvc Service
+	logger  logging.Logger
+	workers *goutils.StoppableWorkers
+
+	mu   sync.Mutex
+	jobs map[string]*MergeJobStatus
+}
+
+// NewMapMerger returns a MapMerger which merges maps into the edited map of the given SLAM service.
+func NewMapMerger(slamSvc Service, logger logging.Logger) *MapMerger {
+	return &MapMerger{
+		slamSvc: slamSvc,
+		logger:  logger,
+		workers: goutils.NewBackgroundStoppableWorkers(),
+		jobs:    map[string]*MergeJobStatus{},
+	}
+}
+
+// Start begins merging the map in the PCD file at mapPath into the map of the SLAM service, writing the merged map to outputPath,
+// and returns the id of the job with which its status can be queried.
+func (m *MapMerger) Start(mapPath, outputPath string, cfg MergeMapsConfig) string {
+	id := uuid.NewString()
+	m.mu.Lock()
+	m.jobs[id] = &MergeJobStatus{State: MergeJobRunning, OutputPath: outputPath}
+	m.mu.Unlock()
+
+	m.workers.Add(func(ctx context.Context) {
+		alignment, err := m.merge(ctx, mapPath, outputPath, cfg)
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		job := m.jobs[id]
+		if err != nil {
+			m.logger.CWarnw(ctx, "failed to merge maps", "map_path", mapPath, "error", err)
+			job.State = MergeJobFailed
+			job.Err = err
+			return
+		}
+		job.State = MergeJobSucceeded
+		job.Alignment = alignment
+	})
+	return id
+}
+
+func (m *MapMerger) merge(ctx context.Context, mapPath, outputPath string, cfg MergeMapsConfig) (pointcloud.ICPResult, error) {
+	pcd, err := PointCloudMapFull(ctx, m.slamSvc, true)
+	if err != nil {
+		return pointcloud.ICPResult{}, err
+	}
+	base, err := pointcloud.ReadPCD(bytes.NewReader(pcd))
+	if err != nil {
+		return pointcloud.ICPResult{}, err
+	}
+	other, err := pointcloud.NewFromFile(mapPath, m.logger)
+	if err != nil {
+		return pointcloud.ICPResult{}, err
+	}
+	if err := ctx.Err(); err != nil {
+		return pointcloud.ICPResult{}, err
+	}
+	merged, alignment, err := MergeMaps(base, other, cfg)
+	if err != nil {
+		return pointcloud.ICPResult{}, err
+	}
+	//nolint:gosec
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return pointcloud.ICPResult{}, err
+	}
+	if err := pointcloud.ToPCD(merged, f, pointcloud.PCDBinary); err != nil {
+		//nolint:errcheck,gosec
+		f.Close()
+		return pointcloud.ICPResult{}, err
+	}
+	return alignment, f.Close()
+}
+
+// Status returns the status of the merge job with the given id.
+func (m *MapMerger) Status(id string) (MergeJobStatus, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[id]
+	if !ok {
+		return MergeJobStatus{}, errors.Errorf("no map merge job with id %q", id)
+	}
+	return *job, nil
+}
+
+// DoCommand handles the DoMergeMaps and DoMergeMapsStatus commands, ignoring any others.
+func (m *MapMerger) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	resp := map[string]interface{}{}
+	if raw, ok := cmd[DoMergeMaps]; ok {
+		var req mergeMapsRequest
+		if err := mapstructure.Decode(raw, &req); err != nil {
+			return nil, err
+		}
+		cfg, err := req.config()
+		if err != nil {
+			return nil, err
+		}
+		resp[DoMergeMaps] = m.Start(req.MapPath, req.OutputPath, cfg)
+	}
+	if raw, ok := cmd[DoMergeMapsStatus]; ok {
+		id, ok := raw.(string)
+		if !ok {
+			return nil, errors.Errorf("expected %s to be a job id, got %T", DoMergeMapsStatus, raw)
+		}
+		status, err := m.Status(id)
+		if err != nil {
+			return nil, err
+		}
+		resp[DoMergeMapsStatus] = status.toMap()
+	}
+	return resp, nil
+}
+
+func (s MergeJobStatus) toMap() map[string]interface{} {
+	m := map[string]interface{}{"state": string(s.State), "output_path": s.OutputPath}
+	if s.Err != nil {
+		m["error"] = s.Err.Error()
+	}
+	if s.State == MergeJobSucceeded {
+		pt := s.Alignment.Transform.Point()
+		theta := s.Alignment.Transform.Orientation().EulerAngles().Yaw * 180 / math.Pi
+		m["transform"] = map[string]interface{}{"x": pt.X, "y": pt.Y, "theta_degrees": theta}
+		m["rms_error_mm"] = s.Alignment.RMSError
+		m["fitness"] = s.Alignment.Fitness
+	}
+	return m
+}
+
+// Close stops any merges which are still running.
+func (m *MapMerger) Close() {
+	m.workers.Stop()
+}
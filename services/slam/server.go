@@ -8,6 +8,7 @@ import (
 	"go.opencensus.io/trace"
 	commonpb "go.viam.com/api/common/v1"
 	pb "go.viam.com/api/service/slam/v1"
+	"google.golang.org/protobuf/types/known/structpb"
 
 	"go.viam.com/rdk/protoutils"
 	"go.viam.com/rdk/resource"
@@ -170,5 +171,17 @@ func (server *serviceServer) DoCommand(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
+	// the localization quality has no method of its own in the slam proto, so clients request it through DoCommand
+	if _, ok := req.Command.AsMap()[DoLocalizationQuality]; ok {
+		quality, err := svc.LocalizationQuality(ctx)
+		if err != nil {
+			return nil, err
+		}
+		result, err := structpb.NewStruct(map[string]interface{}{DoLocalizationQuality: localizationQualityToMap(quality)})
+		if err != nil {
+			return nil, err
+		}
+		return &commonpb.DoCommandResponse{Result: result}, nil
+	}
 	return protoutils.DoFromResourceServer(ctx, svc, req)
 }
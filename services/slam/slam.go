@@ -104,6 +104,43 @@ type Properties struct {
 	SensorInfo            []SensorInfo
 }
 
+// LocalizationState describes whether the slam algorithm is currently able to localize within its map.
+type LocalizationState uint8
+
+// The states of the localization of a slam algorithm. Services which cannot tell report LocalizationStateUnknown.
+const (
+	LocalizationStateUnknown = LocalizationState(iota)
+	LocalizationStateTracking
+	LocalizationStateLost
+	LocalizationStateRelocalizing
+)
+
+func (s LocalizationState) String() string {
+	switch s {
+	case LocalizationStateTracking:
+		return "tracking"
+	case LocalizationStateLost:
+		return "lost"
+	case LocalizationStateRelocalizing:
+		return "relocalizing"
+	default:
+		return "unknown"
+	}
+}
+
+// LocalizationQuality reports how well the slam algorithm is currently localizing within its map.
+type LocalizationQuality struct {
+	State LocalizationState
+	// Confidence is a score in [0, 1] of how sure the slam algorithm is of its position, where 0 is unknown.
+	Confidence float64
+}
+
+// Lost returns whether the slam algorithm has lost track of its position, in which case the positions it reports should not be
+// relied upon until it is tracking again.
+func (q LocalizationQuality) Lost() bool {
+	return q.State == LocalizationStateLost || q.State == LocalizationStateRelocalizing
+}
+
 // Named is a helper for getting the named service's typed resource name.
 func Named(name string) resource.Name {
 	return resource.NewName(API, name)
@@ -159,6 +196,14 @@ func FromDependencies(deps resource.Dependencies, name string) (Service, error)
 //
 // For more information, see the [Properties method docs].
 //
+// LocalizationQuality example:
+//
+//	// Check whether the SLAM algorithm has lost track of where it is
+//	quality, err := mySLAMService.LocalizationQuality(context.Background())
+//	if quality.Lost() {
+//	    // wait for the SLAM algorithm to relocalize
+//	}
+//
 // [SLAM service docs]: https://docs.viam.com/operate/reference/services/slam/
 // [Position method docs]: https://docs.viam.com/dev/reference/apis/services/slam/#getposition
 // [PointCloudMap method docs]: https://docs.viam.com/dev/reference/apis/services/slam/#getpointcloudmap
@@ -170,6 +215,7 @@ type Service interface {
 	PointCloudMap(ctx context.Context, returnEditedMap bool) (func() ([]byte, error), error)
 	InternalState(ctx context.Context) (func() ([]byte, error), error)
 	Properties(ctx context.Context) (Properties, error)
+	LocalizationQuality(ctx context.Context) (LocalizationQuality, error)
 }
 
 // HelperConcatenateChunksToFull concatenates the chunks from a streamed grpc endpoint.
@@ -199,6 +245,74 @@ func PointCloudMapFull(ctx context.Context, slamSvc Service, returnEditedMap boo
 	return HelperConcatenateChunksToFull(callback)
 }
 
+// chunkReader presents the chunks from a streamed grpc endpoint as an io.Reader, so they can be consumed without concatenating them.
+type chunkReader struct {
+	next  func() ([]byte, error)
+	chunk []byte
+}
+
+// HelperChunksToReader returns a reader over the chunks from a streamed grpc endpoint, which only holds one chunk at a time.
+func HelperChunksToReader(f func() ([]byte, error)) io.Reader {
+	return &chunkReader{next: f}
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.chunk) == 0 {
+		chunk, err := r.next()
+		if err != nil {
+			return 0, err
+		}
+		r.chunk = chunk
+	}
+	n := copy(p, r.chunk)
+	r.chunk = r.chunk[n:]
+	return n, nil
+}
+
+// PointCloudMapOctree streams the point cloud map from PointCloudMap directly into an octree, rather than holding the full PCD
+// file in memory as PointCloudMapFull does. If progress is not nil it is called periodically with the number of points read so far
+// and the number of points in the map.
+func PointCloudMapOctree(
+	ctx context.Context,
+	slamSvc Service,
+	returnEditedMap bool,
+	progress func(read, total int),
+) (*pointcloud.BasicOctree, error) {
+	ctx, span := trace.StartSpan(ctx, "slam::PointCloudMapOctree")
+	defer span.End()
+	callback, err := slamSvc.PointCloudMap(ctx, returnEditedMap)
+	if err != nil {
+		return nil, err
+	}
+	return pointcloud.ReadPCDToBasicOctreeStreaming(HelperChunksToReader(callback), progress)
+}
+
+// HelperOccupancyGridToChunks encodes the occupied cells of an occupancy grid as a binary PCD and returns it in chunks of at most
+// chunkSize bytes, so that SLAM services which build 2d occupancy grid maps can serve them from PointCloudMap.
+func HelperOccupancyGridToChunks(grid *pointcloud.OccupancyGrid, chunkSize int) (func() ([]byte, error), error) {
+	if chunkSize <= 0 {
+		return nil, errors.New("chunk size must be positive")
+	}
+	cloud, err := grid.ToPointCloud()
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := pointcloud.ToPCD(cloud, &buf, pointcloud.PCDBinary); err != nil {
+		return nil, err
+	}
+	data := buf.Bytes()
+	return func() ([]byte, error) {
+		if len(data) == 0 {
+			return nil, io.EOF
+		}
+		n := min(chunkSize, len(data))
+		chunk := data[:n]
+		data = data[n:]
+		return chunk, nil
+	}, nil
+}
+
 // InternalStateFull concatenates the streaming responses from InternalState into
 // the internal serialized state of the slam algorithm.
 func InternalStateFull(ctx context.Context, slamSvc Service) ([]byte, error) {
@@ -0,0 +1,295 @@
+package slam_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+	"go.viam.com/utils/testutils"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/services/slam"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestPointCloudMapOctree(t *testing.T) {
+	cloud := pointcloud.New()
+	for i := 0; i < 100; i++ {
+		test.That(t, cloud.Set(r3.Vector{X: float64(i * 10), Y: float64(i * 20), Z: 3}, pointcloud.NewValueData(i)), test.ShouldBeNil)
+	}
+	var buf bytes.Buffer
+	test.That(t, pointcloud.ToPCD(cloud, &buf, pointcloud.PCDBinary), test.ShouldBeNil)
+	pcd := buf.Bytes()
+
+	slamSvc := &inject.SLAMService{}
+	slamSvc.PointCloudMapFunc = func(ctx context.Context, returnEditedMap bool) (func() ([]byte, error), error) {
+		// chunks much smaller than a point are reassembled by the reader
+		reader := bytes.NewReader(pcd)
+		chunk := make([]byte, 7)
+		return func() ([]byte, error) {
+			n, err := reader.Read(chunk)
+			if err != nil {
+				return nil, err
+			}
+			return chunk[:n], nil
+		}, nil
+	}
+
+	var read, total int
+	octree, err := slam.PointCloudMapOctree(context.Background(), slamSvc, true, func(r, tot int) {
+		read, total = r, tot
+	})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, octree.Size(), test.ShouldEqual, 100)
+	test.That(t, read, test.ShouldEqual, 100)
+	test.That(t, total, test.ShouldEqual, 100)
+	_, ok := octree.At(500, 1000, 3)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	t.Run("stream errors are returned", func(t *testing.T) {
+		slamSvc.PointCloudMapFunc = func(ctx context.Context, returnEditedMap bool) (func() ([]byte, error), error) {
+			reader := bytes.NewReader(pcd[:len(pcd)/2])
+			return func() ([]byte, error) {
+				chunk := make([]byte, 64)
+				n, err := reader.Read(chunk)
+				if errors.Is(err, io.EOF) {
+					return nil, errors.New("connection lost")
+				}
+				return chunk[:n], err
+			}, nil
+		}
+		_, err := slam.PointCloudMapOctree(context.Background(), slamSvc, true, nil)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "connection lost")
+	})
+}
+
+func TestHelperOccupancyGridToChunks(t *testing.T) {
+	grid := &pointcloud.OccupancyGrid{
+		ResolutionMM:   50,
+		Origin:         spatialmath.NewZeroPose(),
+		Width:          3,
+		Height:         2,
+		Occupancy:      []float64{1, 0, pointcloud.UnknownOccupancy, 0.9, 1, 0},
+		OccupiedThresh: 0.65,
+	}
+	_, err := slam.HelperOccupancyGridToChunks(grid, 0)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	callback, err := slam.HelperOccupancyGridToChunks(grid, 16)
+	test.That(t, err, test.ShouldBeNil)
+	cloud, err := pointcloud.ReadPCD(slam.HelperChunksToReader(callback))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cloud.Size(), test.ShouldEqual, 3)
+	d, ok := cloud.At(25, 75, 0)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, d.Value(), test.ShouldEqual, 90)
+	_, ok = cloud.At(125, 25, 0)
+	test.That(t, ok, test.ShouldBeFalse)
+}
+
+// scatteredMap returns points scattered through a room, which only align with themselves in one way.
+func scatteredMap(t *testing.T, offset spatialmath.Pose) pointcloud.PointCloud {
+	t.Helper()
+	rng := rand.New(rand.NewSource(1))
+	cloud := pointcloud.New()
+	for i := 0; i < 300; i++ {
+		p := r3.Vector{X: rng.Float64() * 2000, Y: rng.Float64() * 2000, Z: 0.5}
+		p = spatialmath.Compose(spatialmath.PoseInverse(offset), spatialmath.NewPoseFromPoint(p)).Point()
+		test.That(t, cloud.Set(p, pointcloud.NewValueData(100)), test.ShouldBeNil)
+	}
+	return cloud
+}
+
+func TestMergeMaps(t *testing.T) {
+	offset := spatialmath.NewPose(r3.Vector{X: 40, Y: -30}, &spatialmath.OrientationVectorDegrees{OZ: 1, Theta: 1})
+	base := scatteredMap(t, spatialmath.NewZeroPose())
+	other := scatteredMap(t, offset)
+	cfg := slam.MergeMapsConfig{ICP: pointcloud.ICPConfig{MaxIterations: 50, MaxCorrespondenceDistance: 300, ConvergenceThreshold: 1e-3}}
+
+	merged, alignment, err := slam.MergeMaps(base, other, cfg)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatialmath.PoseAlmostEqualEps(alignment.Transform, offset, 1e-3), test.ShouldBeTrue)
+	// the aligned points land within rounding of the base points rather than being doubled up beside them
+	test.That(t, merged.Size(), test.ShouldBeBetweenOrEqual, base.Size(), 2*base.Size())
+
+	cfg.VoxelSizeMM = 1
+	merged, _, err = slam.MergeMaps(base, other, cfg)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, merged.Size(), test.ShouldEqual, base.Size())
+
+	_, _, err = slam.MergeMaps(base, pointcloud.New(), cfg)
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestMapMerger(t *testing.T) {
+	offset := spatialmath.NewPose(r3.Vector{X: 40, Y: -30}, &spatialmath.OrientationVectorDegrees{OZ: 1, Theta: 1})
+	var buf bytes.Buffer
+	test.That(t, pointcloud.ToPCD(scatteredMap(t, spatialmath.NewZeroPose()), &buf, pointcloud.PCDBinary), test.ShouldBeNil)
+	pcd := buf.Bytes()
+	slamSvc := &inject.SLAMService{}
+	slamSvc.PointCloudMapFunc = func(ctx context.Context, returnEditedMap bool) (func() ([]byte, error), error) {
+		reader := bytes.NewReader(pcd)
+		return func() ([]byte, error) {
+			chunk := make([]byte, 1024)
+			n, err := reader.Read(chunk)
+			return chunk[:n], err
+		}, nil
+	}
+
+	dir := t.TempDir()
+	mapPath := filepath.Join(dir, "other.pcd")
+	f, err := os.Create(mapPath)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, pointcloud.ToPCD(scatteredMap(t, offset), f, pointcloud.PCDBinary), test.ShouldBeNil)
+	test.That(t, f.Close(), test.ShouldBeNil)
+	outputPath := filepath.Join(dir, "merged.pcd")
+
+	merger := slam.NewMapMerger(slamSvc, logging.NewTestLogger(t))
+	defer merger.Close()
+
+	_, err = merger.DoCommand(context.Background(), map[string]interface{}{slam.DoMergeMaps: map[string]interface{}{"map_path": mapPath}})
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = merger.DoCommand(context.Background(), map[string]interface{}{slam.DoMergeMapsStatus: "unknown"})
+	test.That(t, err, test.ShouldNotBeNil)
+
+	waitForJob := func(t *testing.T, id string) map[string]interface{} {
+		t.Helper()
+		var status map[string]interface{}
+		testutils.WaitForAssertion(t, func(tb testing.TB) {
+			tb.Helper()
+			resp, err := merger.DoCommand(context.Background(), map[string]interface{}{slam.DoMergeMapsStatus: id})
+			test.That(tb, err, test.ShouldBeNil)
+			status = resp[slam.DoMergeMapsStatus].(map[string]interface{})
+			test.That(tb, status["state"], test.ShouldNotEqual, string(slam.MergeJobRunning))
+		})
+		return status
+	}
+
+	resp, err := merger.DoCommand(context.Background(), map[string]interface{}{slam.DoMergeMaps: map[string]interface{}{
+		"map_path":                       mapPath,
+		"output_path":                    outputPath,
+		"initial_guess":                  map[string]interface{}{"x": 30., "y": -20.},
+		"max_correspondence_distance_mm": 300.,
+		"voxel_size_mm":                  1.,
+	}})
+	test.That(t, err, test.ShouldBeNil)
+	status := waitForJob(t, resp[slam.DoMergeMaps].(string))
+	test.That(t, status["state"], test.ShouldEqual, string(slam.MergeJobSucceeded))
+	transform := status["transform"].(map[string]interface{})
+	test.That(t, transform["x"], test.ShouldAlmostEqual, 40, 0.1)
+	test.That(t, transform["theta_degrees"], test.ShouldAlmostEqual, 1, 0.01)
+	merged, err := pointcloud.NewFromFile(outputPath, logging.NewTestLogger(t))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, merged.Size(), test.ShouldEqual, 300)
+
+	resp, err = merger.DoCommand(context.Background(), map[string]interface{}{slam.DoMergeMaps: map[string]interface{}{
+		"map_path":    filepath.Join(dir, "missing.pcd"),
+		"output_path": outputPath,
+	}})
+	test.That(t, err, test.ShouldBeNil)
+	status = waitForJob(t, resp[slam.DoMergeMaps].(string))
+	test.That(t, status["state"], test.ShouldEqual, string(slam.MergeJobFailed))
+	test.That(t, status["error"], test.ShouldNotBeEmpty)
+}
+
+func TestMapVersions(t *testing.T) {
+	pcdOf := func(t *testing.T, points ...r3.Vector) []byte {
+		t.Helper()
+		cloud := pointcloud.New()
+		for _, p := range points {
+			test.That(t, cloud.Set(p, pointcloud.NewValueData(100)), test.ShouldBeNil)
+		}
+		var buf bytes.Buffer
+		test.That(t, pointcloud.ToPCD(cloud, &buf, pointcloud.PCDBinary), test.ShouldBeNil)
+		return buf.Bytes()
+	}
+	original := pcdOf(t, r3.Vector{X: 10, Y: 10}, r3.Vector{X: 20, Y: 20}, r3.Vector{X: 510, Y: 10})
+	// a wall is knocked down and another put up elsewhere
+	remodeled := pcdOf(t, r3.Vector{X: 10, Y: 10}, r3.Vector{X: 20, Y: 20}, r3.Vector{X: 10, Y: 1010})
+
+	pcd := original
+	slamSvc := &inject.SLAMService{}
+	slamSvc.PointCloudMapFunc = func(ctx context.Context, returnEditedMap bool) (func() ([]byte, error), error) {
+		sent := false
+		return func() ([]byte, error) {
+			if sent {
+				return nil, io.EOF
+			}
+			sent = true
+			return pcd, nil
+		}, nil
+	}
+	slamSvc.DoCommandFunc = func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+		return nil, errors.New("DoCommand unimplemented")
+	}
+
+	t.Run("versions are the hash of the map for services which do not track them", func(t *testing.T) {
+		version, err := slam.MapVersion(context.Background(), slamSvc)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, version, test.ShouldEqual, slam.MapVersionOfPCD(original))
+		test.That(t, slam.MapVersionOfPCD(remodeled), test.ShouldNotEqual, version)
+	})
+
+	t.Run("tracked versions are diffed", func(t *testing.T) {
+		tracker := slam.NewMapVersionTracker(slamSvc, 100, 2)
+		pcd = original
+		before, err := tracker.Current(context.Background())
+		test.That(t, err, test.ShouldBeNil)
+		pcd = remodeled
+		resp, err := tracker.DoCommand(context.Background(), map[string]interface{}{
+			slam.DoMapVersion: true,
+			slam.DoMapDiff:    map[string]interface{}{"from": before},
+		})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, resp[slam.DoMapVersion], test.ShouldEqual, slam.MapVersionOfPCD(remodeled))
+		test.That(t, resp[slam.DoMapDiff], test.ShouldResemble, map[string]interface{}{
+			"added":         []interface{}{[]interface{}{50., 1050., 50.}},
+			"removed":       []interface{}{[]interface{}{550., 50., 50.}},
+			"voxel_size_mm": 100.,
+		})
+
+		diff, err := tracker.Diff(context.Background(), before, before)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, diff.Empty(), test.ShouldBeTrue)
+		diff, err = tracker.Diff(context.Background(), before, "")
+		test.That(t, err, test.ShouldBeNil)
+		geometries, err := diff.Geometries()
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(geometries), test.ShouldEqual, 2)
+		test.That(t, geometries[0].Label(), test.ShouldEqual, "added")
+
+		_, err = tracker.Diff(context.Background(), "unknown", "")
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = tracker.DoCommand(context.Background(), map[string]interface{}{slam.DoMapDiff: map[string]interface{}{}})
+		test.That(t, err, test.ShouldNotBeNil)
+
+		// the oldest versions are forgotten
+		pcd = pcdOf(t, r3.Vector{})
+		_, err = tracker.Current(context.Background())
+		test.That(t, err, test.ShouldBeNil)
+		_, err = tracker.Diff(context.Background(), before, "")
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("services which track their versions report them", func(t *testing.T) {
+		slamSvc.DoCommandFunc = func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+			return map[string]interface{}{slam.DoMapVersion: "v2"}, nil
+		}
+		version, err := slam.MapVersion(context.Background(), slamSvc)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, version, test.ShouldEqual, "v2")
+	})
+
+	_, err := slam.DiffMaps(pointcloud.New(), pointcloud.New(), 0)
+	test.That(t, err, test.ShouldNotBeNil)
+}
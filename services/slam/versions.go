@@ -0,0 +1,268 @@
+package slam
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// keys of the commands with which SLAM services that embed a MapVersionTracker report the versions of their maps through
+// DoCommand.
+const (
+	// DoMapVersion reports the version of the current edited map. The input value is ignored and the output value is the version.
+	DoMapVersion = "map_version"
+	// DoMapDiff reports the regions which changed between two versions of the map. The input value is a map with the required key
+	// "from" and the optional key "to", which defaults to the current version. The output value is a map with the keys "added" and
+	// "removed", lists of the [x, y, z] centers of the voxels which became or stopped being occupied, and "voxel_size_mm".
+	DoMapDiff = "map_diff"
+)
+
+// mapVersionLength is the number of hex characters of the hash of a map used as its version, which is plenty to tell apart the
+// versions of a map.
+const mapVersionLength = 16
+
+// defaults of MapVersionTrackers, which compare maps in 5cm voxels and remember enough versions to span a typical replanning window.
+const (
+	defaultMapDiffVoxelSizeMM = 50.
+	defaultMaxMapVersions     = 10
+)
+
+// MapVersionOfPCD returns the version identifier of the map in the given PCD, which changes whenever the map does.
+func MapVersionOfPCD(pcd []byte) string {
+	sum := sha256.Sum256(pcd)
+	return hex.EncodeToString(sum[:])[:mapVersionLength]
+}
+
+// MapVersion returns the version identifier of the edited map of the SLAM service. Services which track their map versions report
+// them through DoCommand. For any other service the version is the hash of its map, which must then be fetched in full.
+func MapVersion(ctx context.Context, slamSvc Service) (string, error) {
+	if resp, err := slamSvc.DoCommand(ctx, map[string]interface{}{DoMapVersion: true}); err == nil {
+		if version, ok := resp[DoMapVersion].(string); ok && version != "" {
+			return version, nil
+		}
+	}
+	pcd, err := PointCloudMapFull(ctx, slamSvc, true)
+	if err != nil {
+		return "", err
+	}
+	return MapVersionOfPCD(pcd), nil
+}
+
+// MapDiff describes the voxels of a map which became or stopped being occupied between two versions of the map.
+type MapDiff struct {
+	VoxelSizeMM float64
+	// Added and Removed are the centers of the voxels which became and stopped being occupied.
+	Added, Removed []r3.Vector
+}
+
+// Empty returns whether the map did not change.
+func (d MapDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0
+}
+
+// Geometries returns a box for each changed voxel, labelled "added" or "removed", so that the changed regions can be drawn.
+func (d MapDiff) Geometries() ([]spatialmath.Geometry, error) {
+	geometries := make([]spatialmath.Geometry, 0, len(d.Added)+len(d.Removed))
+	dims := r3.Vector{X: d.VoxelSizeMM, Y: d.VoxelSizeMM, Z: d.VoxelSizeMM}
+	for i, centers := range [][]r3.Vector{d.Added, d.Removed} {
+		label := "added"
+		if i == 1 {
+			label = "removed"
+		}
+		for _, center := range centers {
+			box, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(center), dims, label)
+			if err != nil {
+				return nil, err
+			}
+			geometries = append(geometries, box)
+		}
+	}
+	return geometries, nil
+}
+
+// occupiedVoxels returns the voxels of the given side length containing points of the cloud.
+func occupiedVoxels(cloud pointcloud.PointCloud, voxelSizeMM float64) map[pointcloud.VoxelCoords]bool {
+	voxels := map[pointcloud.VoxelCoords]bool{}
+	cloud.Iterate(0, 0, func(p r3.Vector, d pointcloud.Data) bool {
+		voxels[pointcloud.VoxelCoords{
+			I: int64(math.Floor(p.X / voxelSizeMM)),
+			J: int64(math.Floor(p.Y / voxelSizeMM)),
+			K: int64(math.Floor(p.Z / voxelSizeMM)),
+		}] = true
+		return true
+	})
+	return voxels
+}
+
+// diffVoxels returns the centers of the voxels occupied in after but not before, and in before but not after.
+func diffVoxels(before, after map[pointcloud.VoxelCoords]bool, voxelSizeMM float64) MapDiff {
+	centers := func(in, notIn map[pointcloud.VoxelCoords]bool) []r3.Vector {
+		var coords []pointcloud.VoxelCoords
+		for c := range in {
+			if !notIn[c] {
+				coords = append(coords, c)
+			}
+		}
+		sort.Slice(coords, func(i, j int) bool {
+			a, b := coords[i], coords[j]
+			if a.I != b.I {
+				return a.I < b.I
+			}
+			if a.J != b.J {
+				return a.J < b.J
+			}
+			return a.K < b.K
+		})
+		out := make([]r3.Vector, 0, len(coords))
+		for _, c := range coords {
+			out = append(out, r3.Vector{
+				X: (float64(c.I) + 0.5) * voxelSizeMM,
+				Y: (float64(c.J) + 0.5) * voxelSizeMM,
+				Z: (float64(c.K) + 0.5) * voxelSizeMM,
+			})
+		}
+		return out
+	}
+	return MapDiff{VoxelSizeMM: voxelSizeMM, Added: centers(after, before), Removed: centers(before, after)}
+}
+
+// DiffMaps returns the voxels of the given side length which became or stopped being occupied between the two maps.
+func DiffMaps(before, after pointcloud.PointCloud, voxelSizeMM float64) (MapDiff, error) {
+	if voxelSizeMM <= 0 {
+		return MapDiff{}, errors.Errorf("voxel size must be positive, got %.2f", voxelSizeMM)
+	}
+	return diffVoxels(occupiedVoxels(before, voxelSizeMM), occupiedVoxels(after, voxelSizeMM), voxelSizeMM), nil
+}
+
+// MapVersionTracker remembers the occupied voxels of the recent versions of the map of a SLAM service, so that SLAM services can
+// report the versions of their maps and the changes between them through DoCommand.
+type MapVersionTracker struct {
+	slamSvc     Service
+	voxelSizeMM float64
+	maxVersions int
+
+	mu sync.Mutex
+	// versions holds the occupied voxels of each remembered version, and order the remembered versions from oldest to newest
+	versions map[string]map[pointcloud.VoxelCoords]bool
+	order    []string
+}
+
+// NewMapVersionTracker returns a MapVersionTracker which remembers up to maxVersions versions of the edited map of the given SLAM
+// service, compared in voxels of the given side length. Non positive arguments are replaced with defaults.
+func NewMapVersionTracker(slamSvc Service, voxelSizeMM float64, maxVersions int) *MapVersionTracker {
+	if voxelSizeMM <= 0 {
+		voxelSizeMM = defaultMapDiffVoxelSizeMM
+	}
+	if maxVersions <= 0 {
+		maxVersions = defaultMaxMapVersions
+	}
+	return &MapVersionTracker{
+		slamSvc:     slamSvc,
+		voxelSizeMM: voxelSizeMM,
+		maxVersions: maxVersions,
+		versions:    map[string]map[pointcloud.VoxelCoords]bool{},
+	}
+}
+
+// Current fetches the map of the SLAM service and returns its version, remembering it so that it can later be compared.
+func (t *MapVersionTracker) Current(ctx context.Context) (string, error) {
+	pcd, err := PointCloudMapFull(ctx, t.slamSvc, true)
+	if err != nil {
+		return "", err
+	}
+	version := MapVersionOfPCD(pcd)
+	t.mu.Lock()
+	_, known := t.versions[version]
+	t.mu.Unlock()
+	if known {
+		return version, nil
+	}
+
+	cloud, err := pointcloud.ReadPCD(bytes.NewReader(pcd))
+	if err != nil {
+		return "", err
+	}
+	voxels := occupiedVoxels(cloud, t.voxelSizeMM)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.versions[version]; !ok {
+		t.versions[version] = voxels
+		t.order = append(t.order, version)
+		for len(t.order) > t.maxVersions {
+			delete(t.versions, t.order[0])
+			t.order = t.order[1:]
+		}
+	}
+	return version, nil
+}
+
+// Diff returns the changes to the map between the two versions, which must both be remembered. An empty to is the current version.
+func (t *MapVersionTracker) Diff(ctx context.Context, from, to string) (MapDiff, error) {
+	if to == "" {
+		var err error
+		if to, err = t.Current(ctx); err != nil {
+			return MapDiff{}, err
+		}
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	before, ok := t.versions[from]
+	if !ok {
+		return MapDiff{}, errors.Errorf("map version %q is not known", from)
+	}
+	after, ok := t.versions[to]
+	if !ok {
+		return MapDiff{}, errors.Errorf("map version %q is not known", to)
+	}
+	return diffVoxels(before, after, t.voxelSizeMM), nil
+}
+
+// DoCommand handles the DoMapVersion and DoMapDiff commands, ignoring any others.
+func (t *MapVersionTracker) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	resp := map[string]interface{}{}
+	if _, ok := cmd[DoMapVersion]; ok {
+		version, err := t.Current(ctx)
+		if err != nil {
+			return nil, err
+		}
+		resp[DoMapVersion] = version
+	}
+	if raw, ok := cmd[DoMapDiff]; ok {
+		req, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("expected %s to be a map, got %T", DoMapDiff, raw)
+		}
+		from, ok := req["from"].(string)
+		if !ok || from == "" {
+			return nil, errors.Errorf("%s requires the version to compare from", DoMapDiff)
+		}
+		to, _ := req["to"].(string)
+		diff, err := t.Diff(ctx, from, to)
+		if err != nil {
+			return nil, err
+		}
+		toLists := func(points []r3.Vector) []interface{} {
+			out := make([]interface{}, 0, len(points))
+			for _, p := range points {
+				out = append(out, []interface{}{p.X, p.Y, p.Z})
+			}
+			return out
+		}
+		resp[DoMapDiff] = map[string]interface{}{
+			"added":         toLists(diff.Added),
+			"removed":       toLists(diff.Removed),
+			"voxel_size_mm": diff.VoxelSizeMM,
+		}
+	}
+	return resp, nil
+}
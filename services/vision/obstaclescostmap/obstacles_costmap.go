@@ -0,0 +1,119 @@
+// Package obstaclescostmap uses an underlying depth camera to fulfill GetObjectPointClouds, reducing its point cloud to a 2d
+// occupancy costmap rather than clustering it into discrete objects. Costmaps scale better than per-object geometries in
+// cluttered scenes.
+package obstaclescostmap
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot"
+	svision "go.viam.com/rdk/services/vision"
+	"go.viam.com/rdk/utils"
+	vision "go.viam.com/rdk/vision"
+)
+
+var model = resource.DefaultModelFamily.WithModel("obstacles_costmap")
+
+const (
+	defaultCellSizeMM       = 100.
+	defaultMaxRangeMM       = 5000.
+	defaultSaturationPoints = 10
+)
+
+// CostmapConfig specifies the parameters for the camera to be used for the obstacle costmap service.
+type CostmapConfig struct {
+	CellSizeMM       float64 `json:"cell_size_mm,omitempty"`
+	MaxRangeMM       float64 `json:"max_range_mm,omitempty"`
+	MinHeightMM      float64 `json:"min_height_mm,omitempty"`
+	MaxHeightMM      float64 `json:"max_height_mm,omitempty"`
+	SaturationPoints int     `json:"saturation_points,omitempty"`
+}
+
+func init() {
+	resource.RegisterService(svision.API, model, resource.Registration[svision.Service, *CostmapConfig]{
+		DeprecatedRobotConstructor: func(
+			ctx context.Context, r any, c resource.Config, logger logging.Logger,
+		) (svision.Service, error) {
+			attrs, err := resource.NativeConfig[*CostmapConfig](c)
+			if err != nil {
+				return nil, err
+			}
+			actualR, err := utils.AssertType[robot.Robot](r)
+			if err != nil {
+				return nil, err
+			}
+			return registerObstaclesCostmap(ctx, c.ResourceName(), attrs, actualR)
+		},
+	})
+}
+
+// Validate ensures all parts of the config are valid.
+func (config *CostmapConfig) Validate(path string) ([]string, error) {
+	if config.CellSizeMM < 0 || config.MaxRangeMM < 0 || config.SaturationPoints < 0 {
+		return nil, errors.New("cell_size_mm, max_range_mm and saturation_points may not be negative")
+	}
+	if config.MaxHeightMM < config.MinHeightMM {
+		return nil, errors.New("max_height_mm may not be less than min_height_mm")
+	}
+	if config.CellSizeMM > config.MaxRangeMM && config.MaxRangeMM != 0 {
+		return nil, errors.New("cell_size_mm may not exceed max_range_mm")
+	}
+	return []string{}, nil
+}
+
+func (config *CostmapConfig) costmapConfig() vision.CostmapConfig {
+	cfg := vision.CostmapConfig{
+		CellSizeMM:       config.CellSizeMM,
+		MaxRangeMM:       config.MaxRangeMM,
+		MinHeightMM:      config.MinHeightMM,
+		MaxHeightMM:      config.MaxHeightMM,
+		SaturationPoints: config.SaturationPoints,
+	}
+	if cfg.CellSizeMM == 0 {
+		cfg.CellSizeMM = defaultCellSizeMM
+	}
+	if cfg.MaxRangeMM == 0 {
+		cfg.MaxRangeMM = defaultMaxRangeMM
+	}
+	if cfg.SaturationPoints == 0 {
+		cfg.SaturationPoints = defaultSaturationPoints
+	}
+	return cfg
+}
+
+func registerObstaclesCostmap(
+	ctx context.Context,
+	name resource.Name,
+	conf *CostmapConfig,
+	r robot.Robot,
+) (svision.Service, error) {
+	_, span := trace.StartSpan(ctx, "service::vision::registerObstaclesCostmap")
+	defer span.End()
+	if conf == nil {
+		return nil, errors.New("config for obstacles_costmap cannot be nil")
+	}
+	cfg := conf.costmapConfig()
+
+	segmenter := func(ctx context.Context, src camera.Camera) ([]*vision.Object, error) {
+		cloud, err := src.NextPointCloud(ctx)
+		if err != nil {
+			return nil, err
+		}
+		costmap, err := vision.NewCostmap(cloud, cfg)
+		if err != nil {
+			return nil, err
+		}
+		obj, err := costmap.ToObject()
+		if err != nil {
+			return nil, err
+		}
+		return []*vision.Object{obj}, nil
+	}
+	return svision.NewService(name, r, nil, nil, nil, segmenter)
+}
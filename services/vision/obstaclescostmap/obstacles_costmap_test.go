@@ -0,0 +1,82 @@
+package obstaclescostmap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/camera"
+	pc "go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/vision"
+	"go.viam.com/rdk/testutils/inject"
+	viz "go.viam.com/rdk/vision"
+)
+
+func TestObstaclesCostmap(t *testing.T) {
+	r := &inject.Robot{}
+	cam := &inject.Camera{}
+	cam.NextPointCloudFunc = func(ctx context.Context) (pc.PointCloud, error) {
+		return nil, errors.New("no pointcloud")
+	}
+	r.ResourceNamesFunc = func() []resource.Name {
+		return []resource.Name{camera.Named("fakeCamera")}
+	}
+	r.ResourceByNameFunc = func(n resource.Name) (resource.Resource, error) {
+		switch n.Name {
+		case "fakeCamera":
+			return cam, nil
+		default:
+			return nil, resource.NewNotFoundError(n)
+		}
+	}
+	name := vision.Named("test_costmap")
+
+	// bad registration, no parameters
+	_, err := registerObstaclesCostmap(context.Background(), name, nil, r)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "cannot be nil")
+
+	// invalid configs
+	for _, conf := range []*CostmapConfig{
+		{CellSizeMM: -1},
+		{MinHeightMM: 100, MaxHeightMM: 50},
+		{CellSizeMM: 200, MaxRangeMM: 100},
+	} {
+		_, err := conf.Validate("path")
+		test.That(t, err, test.ShouldNotBeNil)
+	}
+
+	conf := &CostmapConfig{}
+	_, err = conf.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	seg, err := registerObstaclesCostmap(context.Background(), name, conf, r)
+	test.That(t, err, test.ShouldBeNil)
+	props, err := seg.GetProperties(context.Background(), nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, props.ObjectPCDsSupported, test.ShouldBeTrue)
+
+	// fails since camera cannot generate point clouds
+	_, err = seg.GetObjectPointClouds(context.Background(), "fakeCamera", nil)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "no pointcloud")
+
+	// successful, a single costmap object regardless of how many obstacles there are
+	cam.NextPointCloudFunc = func(ctx context.Context) (pc.PointCloud, error) {
+		cloud := pc.New()
+		for i := 0; i < 20; i++ {
+			if err := cloud.Set(r3.Vector{X: float64(i * 100), Z: 1000}, nil); err != nil {
+				return nil, err
+			}
+		}
+		return cloud, nil
+	}
+	objects, err := seg.GetObjectPointClouds(context.Background(), "fakeCamera", nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(objects), test.ShouldEqual, 1)
+	test.That(t, objects[0].Geometry.Label(), test.ShouldEqual, viz.CostmapLabel)
+	test.That(t, objects[0].Size(), test.ShouldEqual, 20)
+}
@@ -0,0 +1,115 @@
+// Package obstaclesdropoff uses an underlying depth camera to fulfill GetObjectPointClouds, finding negative obstacles such as
+// cliffs and holes where the floor drops away in front of it rather than obstacles standing on the floor.
+package obstaclesdropoff
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot"
+	svision "go.viam.com/rdk/services/vision"
+	"go.viam.com/rdk/utils"
+	vision "go.viam.com/rdk/vision"
+)
+
+var model = resource.DefaultModelFamily.WithModel("obstacles_dropoff")
+
+const (
+	defaultCellSizeMM = 100.
+	defaultMaxRangeMM = 3000.
+	defaultMinDropMM  = 50.
+	defaultMinPoints  = 5
+)
+
+// DropOffConfig specifies the parameters for the camera to be used for the obstacle drop-off service. The camera is expected to be
+// level, looking out over flat floor.
+type DropOffConfig struct {
+	CameraHeightMM float64 `json:"camera_height_mm"`
+	CellSizeMM     float64 `json:"cell_size_mm,omitempty"`
+	MaxRangeMM     float64 `json:"max_range_mm,omitempty"`
+	MinDropMM      float64 `json:"min_drop_mm,omitempty"`
+	MinPoints      int     `json:"min_points,omitempty"`
+}
+
+func init() {
+	resource.RegisterService(svision.API, model, resource.Registration[svision.Service, *DropOffConfig]{
+		DeprecatedRobotConstructor: func(
+			ctx context.Context, r any, c resource.Config, logger logging.Logger,
+		) (svision.Service, error) {
+			attrs, err := resource.NativeConfig[*DropOffConfig](c)
+			if err != nil {
+				return nil, err
+			}
+			actualR, err := utils.AssertType[robot.Robot](r)
+			if err != nil {
+				return nil, err
+			}
+			return registerObstaclesDropOff(ctx, c.ResourceName(), attrs, actualR)
+		},
+	})
+}
+
+// Validate ensures all parts of the config are valid.
+func (config *DropOffConfig) Validate(path string) ([]string, error) {
+	if config.CameraHeightMM <= 0 {
+		return nil, resource.NewConfigValidationFieldRequiredError(path, "camera_height_mm")
+	}
+	if config.CellSizeMM < 0 || config.MaxRangeMM < 0 || config.MinDropMM < 0 || config.MinPoints < 0 {
+		return nil, errors.New("cell_size_mm, max_range_mm, min_drop_mm and min_points may not be negative")
+	}
+	if config.CellSizeMM > config.MaxRangeMM && config.MaxRangeMM != 0 {
+		return nil, errors.New("cell_size_mm may not exceed max_range_mm")
+	}
+	return []string{}, nil
+}
+
+func (config *DropOffConfig) dropOffConfig() vision.DropOffConfig {
+	cfg := vision.DropOffConfig{
+		CellSizeMM:     config.CellSizeMM,
+		MaxRangeMM:     config.MaxRangeMM,
+		CameraHeightMM: config.CameraHeightMM,
+		MinDropMM:      config.MinDropMM,
+		MinPoints:      config.MinPoints,
+	}
+	if cfg.CellSizeMM == 0 {
+		cfg.CellSizeMM = defaultCellSizeMM
+	}
+	if cfg.MaxRangeMM == 0 {
+		cfg.MaxRangeMM = defaultMaxRangeMM
+	}
+	if cfg.MinDropMM == 0 {
+		cfg.MinDropMM = defaultMinDropMM
+	}
+	if cfg.MinPoints == 0 {
+		cfg.MinPoints = defaultMinPoints
+	}
+	return cfg
+}
+
+func registerObstaclesDropOff(
+	ctx context.Context,
+	name resource.Name,
+	conf *DropOffConfig,
+	r robot.Robot,
+) (svision.Service, error) {
+	_, span := trace.StartSpan(ctx, "service::vision::registerObstaclesDropOff")
+	defer span.End()
+	if conf == nil {
+		return nil, errors.New("config for obstacles_dropoff cannot be nil")
+	}
+	cfg := conf.dropOffConfig()
+
+	segmenter := func(ctx context.Context, src camera.Camera) ([]*vision.Object, error) {
+		cloud, err := src.NextPointCloud(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return vision.FindDropOffs(cloud, cfg)
+	}
+	return svision.NewService(name, r, nil, nil, nil, segmenter)
+}
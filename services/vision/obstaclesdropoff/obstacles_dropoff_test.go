@@ -0,0 +1,86 @@
+package obstaclesdropoff
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/camera"
+	pc "go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/vision"
+	"go.viam.com/rdk/testutils/inject"
+	viz "go.viam.com/rdk/vision"
+)
+
+func TestObstaclesDropOff(t *testing.T) {
+	r := &inject.Robot{}
+	cam := &inject.Camera{}
+	cam.NextPointCloudFunc = func(ctx context.Context) (pc.PointCloud, error) {
+		return nil, errors.New("no pointcloud")
+	}
+	r.ResourceNamesFunc = func() []resource.Name {
+		return []resource.Name{camera.Named("fakeCamera")}
+	}
+	r.ResourceByNameFunc = func(n resource.Name) (resource.Resource, error) {
+		switch n.Name {
+		case "fakeCamera":
+			return cam, nil
+		default:
+			return nil, resource.NewNotFoundError(n)
+		}
+	}
+	name := vision.Named("test_dropoff")
+
+	// bad registration, no parameters
+	_, err := registerObstaclesDropOff(context.Background(), name, nil, r)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "cannot be nil")
+
+	// invalid configs
+	for _, conf := range []*DropOffConfig{
+		{},
+		{CameraHeightMM: 500, MinDropMM: -1},
+		{CameraHeightMM: 500, CellSizeMM: 200, MaxRangeMM: 100},
+	} {
+		_, err := conf.Validate("path")
+		test.That(t, err, test.ShouldNotBeNil)
+	}
+
+	conf := &DropOffConfig{CameraHeightMM: 500}
+	_, err = conf.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	seg, err := registerObstaclesDropOff(context.Background(), name, conf, r)
+	test.That(t, err, test.ShouldBeNil)
+
+	// fails since camera cannot generate point clouds
+	_, err = seg.GetObjectPointClouds(context.Background(), "fakeCamera", nil)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "no pointcloud")
+
+	// successful, the floor ahead of the camera ends 1m away and is seen again 1m further below it
+	cam.NextPointCloudFunc = func(ctx context.Context) (pc.PointCloud, error) {
+		cloud := pc.New()
+		for x := -90.; x < 100; x += 20 {
+			for z := 10.; z < 1000; z += 20 {
+				if err := cloud.Set(r3.Vector{X: x, Y: 500, Z: z}, nil); err != nil {
+					return nil, err
+				}
+			}
+			for z := 1010.; z < 1100; z += 20 {
+				if err := cloud.Set(r3.Vector{X: 3 * x, Y: 1500, Z: 3 * z}, nil); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return cloud, nil
+	}
+	objects, err := seg.GetObjectPointClouds(context.Background(), "fakeCamera", nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(objects), test.ShouldEqual, 1)
+	test.That(t, objects[0].Geometry.Label(), test.ShouldEqual, viz.DropOffLabel)
+	test.That(t, objects[0].Geometry.Pose().Point().Z, test.ShouldAlmostEqual, 1050)
+}
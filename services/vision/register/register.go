@@ -6,4 +6,6 @@ import (
 	_ "go.viam.com/rdk/services/vision"
 	_ "go.viam.com/rdk/services/vision/fake"
 	_ "go.viam.com/rdk/services/vision/mlvision"
+	_ "go.viam.com/rdk/services/vision/obstaclescostmap"
+	_ "go.viam.com/rdk/services/vision/obstaclesdropoff"
 )
@@ -149,6 +149,8 @@ func (b *box) CollidesWith(g Geometry, collisionBufferMM float64) (bool, error)
 	switch other := g.(type) {
 	case *Mesh:
 		return other.CollidesWith(b, collisionBufferMM)
+	case *polygon:
+		return other.CollidesWith(b, collisionBufferMM)
 	case *box:
 		return boxVsBoxCollision(b, other, collisionBufferMM), nil
 	case *sphere:
@@ -166,6 +168,8 @@ func (b *box) DistanceFrom(g Geometry) (float64, error) {
 	switch other := g.(type) {
 	case *Mesh:
 		return other.DistanceFrom(b)
+	case *polygon:
+		return other.DistanceFrom(b)
 	case *box:
 		return boxVsBoxDistance(b, other), nil
 	case *sphere:
@@ -183,6 +187,8 @@ func (b *box) EncompassedBy(g Geometry) (bool, error) {
 	switch other := g.(type) {
 	case *Mesh:
 		return false, nil // Like points, meshes have no volume and cannot encompass
+	case *polygon:
+		return geometryInPolygon(b, other)
 	case *box:
 		return boxInBox(b, other), nil
 	case *sphere:
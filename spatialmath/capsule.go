@@ -164,6 +164,8 @@ func (c *capsule) DistanceFrom(g Geometry) (float64, error) {
 	switch other := g.(type) {
 	case *Mesh:
 		return other.DistanceFrom(c)
+	case *polygon:
+		return other.DistanceFrom(c)
 	case *box:
 		return capsuleVsBoxDistance(c, other), nil
 	case *capsule:
@@ -181,6 +183,8 @@ func (c *capsule) EncompassedBy(g Geometry) (bool, error) {
 	switch other := g.(type) {
 	case *Mesh:
 		return false, nil // Like points, meshes have no volume and cannot encompass
+	case *polygon:
+		return geometryInPolygon(c, other)
 	case *capsule:
 		return capsuleInCapsule(c, other), nil
 	case *box:
@@ -139,7 +139,10 @@ func GeoPoseToPose(point, origin *GeoPose) Pose {
 
 	// subtracting the point from the origin results in a right handed angle
 	headingChange := normalizeAngle(origin.Heading() - point.Heading())
-	return NewPose(GeoPointToPoint(newPoint, origin.Location()), &OrientationVectorDegrees{OZ: 1, Theta: headingChange})
+	return NewPose(
+		GeoPointToPointWithAltitude(newPoint, origin.Location(), point.Altitude(), origin.Altitude()),
+		&OrientationVectorDegrees{OZ: 1, Theta: headingChange},
+	)
 }
 
 // GeoPointToPoint returns the point (r3.Vector) which translates the origin to the destination geopoint
@@ -160,6 +163,14 @@ func GeoPointToPoint(point, origin *geo.Point) r3.Vector {
 	}
 }
 
+// GeoPointToPointWithAltitude returns the point (r3.Vector) which translates the origin to the destination geopoint, as
+// GeoPointToPoint does, with the height of the destination above the origin, given their altitudes in meters, as its Z.
+func GeoPointToPointWithAltitude(point, origin *geo.Point, altitude, originAltitude float64) r3.Vector {
+	p := GeoPointToPoint(point, origin)
+	p.Z = (altitude - originAltitude) * 1e3
+	return p
+}
+
 // GeoGeometriesToGeometries converts a list of GeoGeometries into a list of Geometries.
 func GeoGeometriesToGeometries(obstacles []*GeoGeometry, origin *geo.Point) []Geometry {
 	// we note that there are two transformations to be accounted for
@@ -176,10 +187,11 @@ func GeoGeometriesToGeometries(obstacles []*GeoGeometry, origin *geo.Point) []Ge
 	return geoms
 }
 
-// GeoPose is a struct to store to location and heading in a geospatial environment.
+// GeoPose is a struct to store to location, heading and altitude in a geospatial environment.
 type GeoPose struct {
 	location *geo.Point
 	heading  float64
+	altitude float64
 }
 
 // NewGeoPose constructs a GeoPose from a geo.Point and float64.
@@ -190,6 +202,15 @@ func NewGeoPose(loc *geo.Point, heading float64) *GeoPose {
 	}
 }
 
+// NewGeoPoseWithAltitude constructs a GeoPose from a geo.Point, heading and altitude in meters.
+func NewGeoPoseWithAltitude(loc *geo.Point, heading, altitude float64) *GeoPose {
+	return &GeoPose{
+		location: loc,
+		heading:  heading,
+		altitude: altitude,
+	}
+}
+
 // Location returns the locating coordinates of the GeoPose.
 func (gpo *GeoPose) Location() *geo.Point {
 	return gpo.location
@@ -200,7 +221,13 @@ func (gpo *GeoPose) Heading() float64 {
 	return gpo.heading
 }
 
-// PoseToGeoPose converts a pose (which are always in mm) into a GeoPose treating relativeTo as the origin.
+// Altitude returns the altitude of the GeoPose in meters.
+func (gpo *GeoPose) Altitude() float64 {
+	return gpo.altitude
+}
+
+// PoseToGeoPose converts a pose (which are always in mm) into a GeoPose treating relativeTo as the origin. The Z of the pose is the
+// height of the GeoPose above relativeTo.
 func PoseToGeoPose(relativeTo *GeoPose, pose Pose) *GeoPose {
 	// poses are always in mm but PointAtDistanceAndBearing expects the pose to be in km so we need to convert
 	kmPoint := pose.Point().Mul(1e-6)
@@ -220,8 +247,8 @@ func PoseToGeoPose(relativeTo *GeoPose, pose Pose) *GeoPose {
 	// get the absolute bearing, i.e. the bearing of pose p from north
 	absoluteBearing := normalizeAngle(bearing + headingInWorld)
 
-	// get the new geopoint at distance poseMagnitude
-	newPosition := relativeTo.Location().PointAtDistanceAndBearing(kmPoint.Norm(), absoluteBearing)
+	// get the new geopoint at the horizontal distance of pose p
+	newPosition := relativeTo.Location().PointAtDistanceAndBearing(math.Hypot(kmPoint.X, kmPoint.Y), absoluteBearing)
 
 	// get the heading of pose p, this is a right-handed value
 	headingRight := pose.Orientation().OrientationVectorDegrees().Theta
@@ -231,8 +258,8 @@ func PoseToGeoPose(relativeTo *GeoPose, pose Pose) *GeoPose {
 
 	poseAbsoluteHeading := normalizeAngle(headingLeft + headingInWorld)
 
-	// return the GeoPose at the new position with the absolute heading of pose p, i.e. the heading in the world
-	return NewGeoPose(newPosition, poseAbsoluteHeading)
+	// return the GeoPose at the new position and altitude with the absolute heading of pose p, i.e. the heading in the world
+	return NewGeoPoseWithAltitude(newPosition, poseAbsoluteHeading, relativeTo.Altitude()+pose.Point().Z*1e-3)
 }
 
 // normalizeAngle takes in an angle in degrees and returns an equivalent angle in the domain [0,360).
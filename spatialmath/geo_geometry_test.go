@@ -249,3 +249,24 @@ func TestPoseToGeoPose(t *testing.T) {
 		})
 	}
 }
+
+func TestGeoAltitude(t *testing.T) {
+	origin := geo.NewPoint(40.7, -74)
+	north := origin.PointAtDistanceAndBearing(1, 0)
+
+	// points keep their height above the origin
+	p := GeoPointToPointWithAltitude(north, origin, 120, 100)
+	test.That(t, p.X, test.ShouldAlmostEqual, 0, 1e-3)
+	test.That(t, p.Y, test.ShouldAlmostEqual, 1e6, 1)
+	test.That(t, p.Z, test.ShouldAlmostEqual, 20000)
+
+	originPose := NewGeoPoseWithAltitude(origin, 0, 100)
+	pose := GeoPoseToPose(NewGeoPoseWithAltitude(north, 0, 80), originPose)
+	test.That(t, pose.Point().Z, test.ShouldAlmostEqual, -20000)
+
+	// and poses above the origin are at its location, however high they are
+	geoPose := PoseToGeoPose(originPose, NewPoseFromPoint(r3.Vector{Y: 1e6, Z: 50000}))
+	test.That(t, geoPose.Altitude(), test.ShouldAlmostEqual, 150)
+	test.That(t, geoPose.Location().GreatCircleDistance(north), test.ShouldBeLessThan, 1e-6)
+	test.That(t, NewGeoPose(origin, 0).Altitude(), test.ShouldEqual, 0)
+}
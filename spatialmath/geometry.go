@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/golang/geo/r2"
 	"github.com/golang/geo/r3"
 	commonpb "go.viam.com/api/common/v1"
 )
@@ -53,6 +54,7 @@ const (
 	SphereType  = GeometryType("sphere")
 	CapsuleType = GeometryType("capsule")
 	PointType   = GeometryType("point")
+	PolygonType = GeometryType("polygon")
 
 	// objects must be separated by this many mm to not be in collision.
 	defaultCollisionBufferMM = 1e-8
@@ -76,6 +78,9 @@ type GeometryConfig struct {
 	// parameter used for defining a capsule's length
 	L float64 `json:"l"`
 
+	// parameter used for defining the vertices of a polygon in the XY plane, which is extruded along Z by the Z parameter
+	Vertices []r2.Point `json:"vertices,omitempty"`
+
 	// define an offset to position the geometry
 	TranslationOffset r3.Vector         `json:"translation,omitempty"`
 	OrientationOffset OrientationConfig `json:"orientation,omitempty"`
@@ -105,6 +110,11 @@ func NewGeometryConfig(g Geometry) (*GeometryConfig, error) {
 	case *point:
 		config.Type = PointType
 		config.Label = gType.label
+	case *polygon:
+		config.Type = PolygonType
+		config.Vertices = append([]r2.Point{}, gType.vertices...)
+		config.Z = gType.height
+		config.Label = gType.label
 	default:
 		return nil, fmt.Errorf("%w %s", errGeometryTypeUnsupported, fmt.Sprintf("%T", gType))
 	}
@@ -138,6 +148,8 @@ func (config *GeometryConfig) ParseConfig() (Geometry, error) {
 		return NewCapsule(offset, config.R, config.L, config.Label)
 	case PointType:
 		return NewPoint(offset.Point(), config.Label), nil
+	case PolygonType:
+		return NewPolygon(offset, config.Vertices, config.Z, config.Label)
 	case UnknownType:
 		// no type specified, iterate through supported types and try to infer intent
 		boxDims := r3.Vector{X: config.X, Y: config.Y, Z: config.Z}
@@ -177,6 +189,8 @@ func GeometriesAlmostEqual(a, b Geometry) bool {
 		return gType.almostEqual(b)
 	case *point:
 		return gType.almostEqual(b)
+	case *polygon:
+		return gType.almostEqual(b)
 	default:
 		return false
 	}
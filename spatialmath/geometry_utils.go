@@ -1,6 +1,8 @@
 package spatialmath
 
 import (
+	"math"
+
 	"github.com/golang/geo/r3"
 )
 
@@ -92,6 +94,12 @@ func BoundingSphere(geometry Geometry) (Geometry, error) {
 		r += g.radius
 	case *capsule:
 		r += g.length / 2
+	case *polygon:
+		farthest := 0.
+		for _, v := range g.vertices {
+			farthest = math.Max(farthest, v.Norm())
+		}
+		r += math.Hypot(farthest, g.height/2)
 	case *point:
 	default:
 		return nil, errGeometryTypeUnsupported
@@ -0,0 +1,120 @@
+package spatialmath
+
+import (
+	"math"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	"gonum.org/v1/gonum/mat"
+)
+
+// minHandEyeRotation is the smallest rotation, in radians, of a pair of motions which constrains the rotation of AX=XB.
+const minHandEyeRotation = 1e-3
+
+// SolveAXXB returns the transform X satisfying A[i] X = X B[i] for every pair of motions A[i] and B[i] in the least squares
+// sense, following Park and Martin: the rotation of X is found from the axes of rotation of the motions, and then its
+// translation from the translations of the motions. At least two pairs of motions rotating about non-parallel axes are needed.
+func SolveAXXB(motionsA, motionsB []Pose) (Pose, error) {
+	if len(motionsA) != len(motionsB) {
+		return nil, errors.Errorf("got %d A motions but %d B motions", len(motionsA), len(motionsB))
+	}
+
+	// the axis-angle vectors of the rotations of each pair are related by alpha = R_X beta, which is the orthogonal Procrustes
+	// problem solved by the SVD of their covariance
+	cov := mat.NewDense(3, 3, nil)
+	rotating := 0
+	for i := range motionsA {
+		alpha := motionsA[i].Orientation().AxisAngles().ToR3()
+		beta := motionsB[i].Orientation().AxisAngles().ToR3()
+		if alpha.Norm() < minHandEyeRotation || beta.Norm() < minHandEyeRotation {
+			continue
+		}
+		rotating++
+		a := []float64{alpha.X, alpha.Y, alpha.Z}
+		b := []float64{beta.X, beta.Y, beta.Z}
+		for r := 0; r < 3; r++ {
+			for c := 0; c < 3; c++ {
+				cov.Set(r, c, cov.At(r, c)+b[r]*a[c])
+			}
+		}
+	}
+	if rotating < 2 {
+		return nil, errors.New("need at least two motions which rotate to solve AX=XB")
+	}
+	var svd mat.SVD
+	if ok := svd.Factorize(cov, mat.SVDFull); !ok {
+		return nil, errors.New("failed to factorize the covariance of the rotations")
+	}
+	values := svd.Values(nil)
+	if values[1] < minHandEyeRotation*values[0] {
+		return nil, errors.New("the motions must rotate about at least two non-parallel axes to solve AX=XB")
+	}
+	var u, v, rot mat.Dense
+	svd.UTo(&u)
+	svd.VTo(&v)
+	rot.Mul(&v, u.T())
+	if mat.Det(&rot) < 0 {
+		// flip the axis of the smallest singular value so that the result is a rotation rather than a reflection
+		flip := mat.NewDiagDense(3, []float64{1, 1, -1})
+		var vFlipped mat.Dense
+		vFlipped.Mul(&v, flip)
+		rot.Mul(&vFlipped, u.T())
+	}
+	// a RotationMatrix holds the transpose of the rotation it applies to points
+	var stored mat.Dense
+	stored.CloneFrom(rot.T())
+	rotX, err := NewRotationMatrix(stored.RawMatrix().Data)
+	if err != nil {
+		return nil, err
+	}
+
+	// with the rotation known, the translations satisfy the linear system (R_A - I) t_X = R_X t_B - t_A
+	lhs := mat.NewDense(3*len(motionsA), 3, nil)
+	rhs := mat.NewVecDense(3*len(motionsA), nil)
+	for i := range motionsA {
+		rotA := motionsA[i].Orientation().RotationMatrix()
+		tA := motionsA[i].Point()
+		tB := motionsB[i].Point()
+		var rotatedTB mat.VecDense
+		rotatedTB.MulVec(&rot, mat.NewVecDense(3, []float64{tB.X, tB.Y, tB.Z}))
+		b := []float64{rotatedTB.AtVec(0) - tA.X, rotatedTB.AtVec(1) - tA.Y, rotatedTB.AtVec(2) - tA.Z}
+		for r := 0; r < 3; r++ {
+			for c := 0; c < 3; c++ {
+				val := rotA.At(c, r)
+				if r == c {
+					val--
+				}
+				lhs.Set(3*i+r, c, val)
+			}
+			rhs.SetVec(3*i+r, b[r])
+		}
+	}
+	var trans mat.VecDense
+	if err := trans.SolveVec(lhs, rhs); err != nil {
+		return nil, errors.Wrap(err, "failed to solve for the translation of AX=XB")
+	}
+	t := r3.Vector{X: trans.AtVec(0), Y: trans.AtVec(1), Z: trans.AtVec(2)}
+	if math.IsNaN(t.X) || math.IsNaN(t.Y) || math.IsNaN(t.Z) {
+		return nil, errors.New("failed to solve for the translation of AX=XB")
+	}
+	return NewPose(t, rotX), nil
+}
+
+// HandEyeCalibration returns the pose of a camera mounted on an arm in the frame of the end of the arm, from the poses of the end
+// of the arm in the frame of its base and the poses of a fixed target, such as a fiducial tag, seen by the camera in its frame at
+// the same times. The camera must see the target from positions of the arm rotated about at least two non-parallel axes.
+func HandEyeCalibration(armPoses, targetPoses []Pose) (Pose, error) {
+	if len(armPoses) != len(targetPoses) {
+		return nil, errors.Errorf("got %d arm poses but %d target poses", len(armPoses), len(targetPoses))
+	}
+	// the target is fixed, so armPoses[i] X targetPoses[i] is the same for every i, which gives a motion of the arm and the
+	// matching motion of the target for every pair of samples
+	var motionsA, motionsB []Pose
+	for i := range armPoses {
+		for j := i + 1; j < len(armPoses); j++ {
+			motionsA = append(motionsA, PoseBetween(armPoses[j], armPoses[i]))
+			motionsB = append(motionsB, Compose(targetPoses[j], PoseInverse(targetPoses[i])))
+		}
+	}
+	return SolveAXXB(motionsA, motionsB)
+}
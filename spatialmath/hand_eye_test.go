@@ -0,0 +1,48 @@
+package spatialmath
+
+import (
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+)
+
+func TestHandEyeCalibration(t *testing.T) {
+	// the camera is 50mm out along the x axis of the end of the arm, looking back along it
+	camera := NewPose(r3.Vector{X: 50, Z: 20}, &OrientationVectorDegrees{OX: -1, Theta: 30})
+	target := NewPose(r3.Vector{X: 400, Y: 100, Z: -200}, &OrientationVectorDegrees{OZ: 1, Theta: 90})
+	armPoses := []Pose{
+		NewPose(r3.Vector{X: 300, Y: 0, Z: 300}, &OrientationVectorDegrees{OZ: -1}),
+		NewPose(r3.Vector{X: 320, Y: 50, Z: 280}, &OrientationVectorDegrees{OX: 0.2, OZ: -1, Theta: 20}),
+		NewPose(r3.Vector{X: 280, Y: -40, Z: 310}, &OrientationVectorDegrees{OY: 0.3, OZ: -1, Theta: -15}),
+		NewPose(r3.Vector{X: 350, Y: 20, Z: 250}, &OrientationVectorDegrees{OX: -0.2, OY: 0.2, OZ: -1, Theta: 45}),
+	}
+	targetPoses := make([]Pose, 0, len(armPoses))
+	for _, armPose := range armPoses {
+		// where the camera sees the target from this pose of the arm
+		targetPoses = append(targetPoses, PoseBetween(Compose(armPose, camera), target))
+	}
+
+	solved, err := HandEyeCalibration(armPoses, targetPoses)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, PoseAlmostEqualEps(solved, camera, 1e-6), test.ShouldBeTrue)
+
+	_, err = HandEyeCalibration(armPoses[:1], targetPoses[:1])
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = HandEyeCalibration(armPoses, targetPoses[:2])
+	test.That(t, err, test.ShouldNotBeNil)
+
+	t.Run("motions about parallel axes", func(t *testing.T) {
+		parallel := []Pose{
+			NewPose(r3.Vector{X: 300}, &OrientationVectorDegrees{OZ: 1}),
+			NewPose(r3.Vector{X: 310}, &OrientationVectorDegrees{OZ: 1, Theta: 20}),
+			NewPose(r3.Vector{X: 320}, &OrientationVectorDegrees{OZ: 1, Theta: 40}),
+		}
+		seen := make([]Pose, 0, len(parallel))
+		for _, armPose := range parallel {
+			seen = append(seen, PoseBetween(Compose(armPose, camera), target))
+		}
+		_, err := HandEyeCalibration(parallel, seen)
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
@@ -71,6 +71,8 @@ func (m *Mesh) CollidesWith(g Geometry, collisionBufferMM float64) (bool, error)
 		return m.collidesWithSphere(other.pose.Point(), other.radius, collisionBufferMM), nil
 	case *Mesh:
 		return m.collidesWithMesh(other, collisionBufferMM), nil
+	case *polygon:
+		return other.CollidesWith(m, collisionBufferMM)
 	default:
 		return true, newCollisionTypeUnsupportedError(m, g)
 	}
@@ -115,6 +117,8 @@ func (m *Mesh) DistanceFrom(g Geometry) (float64, error) {
 		return m.distanceFromSphere(other.pose.Point(), other.radius), nil
 	case *Mesh:
 		return m.distanceFromMesh(other), nil
+	case *polygon:
+		return other.DistanceFrom(m)
 	default:
 		return math.Inf(-1), newCollisionTypeUnsupportedError(m, g)
 	}
@@ -81,6 +81,8 @@ func (pt *point) CollidesWith(g Geometry, collisionBufferMM float64) (bool, erro
 	switch other := g.(type) {
 	case *Mesh:
 		return other.CollidesWith(pt, collisionBufferMM)
+	case *polygon:
+		return other.CollidesWith(pt, collisionBufferMM)
 	case *box:
 		return pointVsBoxCollision(pt.position, other, collisionBufferMM), nil
 	case *sphere:
@@ -99,6 +101,8 @@ func (pt *point) DistanceFrom(g Geometry) (float64, error) {
 	switch other := g.(type) {
 	case *Mesh:
 		return other.DistanceFrom(pt)
+	case *polygon:
+		return other.DistanceFrom(pt)
 	case *box:
 		return pointVsBoxDistance(pt.position, other), nil
 	case *sphere:
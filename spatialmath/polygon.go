@@ -0,0 +1,375 @@
+package spatialmath
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/golang/geo/r2"
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	commonpb "go.viam.com/api/common/v1"
+
+	"go.viam.com/rdk/utils"
+)
+
+// polygon is a collision geometry that represents a simple 2D polygon in the XY plane of its pose, extruded along Z by its height
+// and centered on the plane. Unlike the other geometries it need not be convex, so that the footprints of robots such as forklifts
+// with their tines or bases towing trailers can be represented by a single geometry.
+type polygon struct {
+	pose Pose
+	// vertices are in counterclockwise order
+	vertices []r2.Point
+	height   float64
+	label    string
+	// triangles enclose the polygon in its own frame, so that its surface may be checked against other geometries
+	triangles []*Triangle
+}
+
+// NewPolygon instantiates a new polygon Geometry, extruding the given vertices in the XY plane of the pose by the given height. The
+// vertices may be in either winding order, but the edges between them may not cross.
+func NewPolygon(pose Pose, vertices []r2.Point, height float64, label string) (Geometry, error) {
+	if len(vertices) < 3 || height <= 0 {
+		return nil, newBadGeometryDimensionsError(&polygon{})
+	}
+	vertices = append([]r2.Point{}, vertices...)
+	area := signedArea(vertices)
+	if utils.Float64AlmostEqual(area, 0, 1e-8) {
+		return nil, errors.New("polygon must enclose a nonzero area")
+	}
+	if area < 0 {
+		for i, j := 0, len(vertices)-1; i < j; i, j = i+1, j-1 {
+			vertices[i], vertices[j] = vertices[j], vertices[i]
+		}
+	}
+	if selfIntersecting(vertices) {
+		return nil, errors.New("polygon edges may not cross")
+	}
+	caps, err := triangulate(vertices)
+	if err != nil {
+		return nil, err
+	}
+
+	top := func(v r2.Point) r3.Vector { return r3.Vector{X: v.X, Y: v.Y, Z: height / 2} }
+	bottom := func(v r2.Point) r3.Vector { return r3.Vector{X: v.X, Y: v.Y, Z: -height / 2} }
+	triangles := make([]*Triangle, 0, 2*len(caps)+2*len(vertices))
+	for _, c := range caps {
+		a, b, d := vertices[c[0]], vertices[c[1]], vertices[c[2]]
+		triangles = append(triangles, NewTriangle(top(a), top(b), top(d)), NewTriangle(bottom(a), bottom(d), bottom(b)))
+	}
+	for i, a := range vertices {
+		b := vertices[(i+1)%len(vertices)]
+		triangles = append(triangles, NewTriangle(bottom(a), bottom(b), top(b)), NewTriangle(bottom(a), top(b), top(a)))
+	}
+	return &polygon{pose: pose, vertices: vertices, height: height, label: label, triangles: triangles}, nil
+}
+
+// String returns a human readable string that represents the polygon.
+func (p *polygon) String() string {
+	return fmt.Sprintf("Type: Polygon | Position: X:%.1f, Y:%.1f, Z:%.1f | Vertices: %d | Height: %.0f",
+		p.pose.Point().X, p.pose.Point().Y, p.pose.Point().Z, len(p.vertices), p.height)
+}
+
+func (p *polygon) MarshalJSON() ([]byte, error) {
+	config, err := NewGeometryConfig(p)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(config)
+}
+
+// SetLabel sets the label of this polygon.
+func (p *polygon) SetLabel(label string) {
+	p.label = label
+}
+
+// Label returns the label of this polygon.
+func (p *polygon) Label() string {
+	return p.label
+}
+
+// Pose returns the pose of the polygon.
+func (p *polygon) Pose() Pose {
+	return p.pose
+}
+
+// almostEqual compares the polygon with another geometry and checks if they are equivalent.
+func (p *polygon) almostEqual(g Geometry) bool {
+	other, ok := g.(*polygon)
+	if !ok || len(p.vertices) != len(other.vertices) || !utils.Float64AlmostEqual(p.height, other.height, 1e-8) {
+		return false
+	}
+	for i, v := range p.vertices {
+		if !utils.Float64AlmostEqual(v.X, other.vertices[i].X, 1e-8) || !utils.Float64AlmostEqual(v.Y, other.vertices[i].Y, 1e-8) {
+			return false
+		}
+	}
+	return PoseAlmostEqualEps(p.pose, other.pose, 1e-6)
+}
+
+// Transform premultiplies the polygon pose with a transform, allowing the polygon to be moved in space.
+func (p *polygon) Transform(toPremultiply Pose) Geometry {
+	return &polygon{
+		pose:      Compose(toPremultiply, p.pose),
+		vertices:  p.vertices,
+		height:    p.height,
+		label:     p.label,
+		triangles: p.triangles,
+	}
+}
+
+// ToProtobuf converts the polygon to a Geometry proto message. Since there is no polygon representation in the common proto, it is
+// converted to the box bounding it.
+func (p *polygon) ToProtobuf() *commonpb.Geometry {
+	minPt, maxPt := p.vertices[0], p.vertices[0]
+	for _, v := range p.vertices[1:] {
+		minPt = r2.Point{X: math.Min(minPt.X, v.X), Y: math.Min(minPt.Y, v.Y)}
+		maxPt = r2.Point{X: math.Max(maxPt.X, v.X), Y: math.Max(maxPt.Y, v.Y)}
+	}
+	center := minPt.Add(maxPt).Mul(0.5)
+	return &commonpb.Geometry{
+		Center: PoseToProtobuf(Compose(p.pose, NewPoseFromPoint(r3.Vector{X: center.X, Y: center.Y}))),
+		GeometryType: &commonpb.Geometry_Box{
+			Box: &commonpb.RectangularPrism{DimsMm: &commonpb.Vector3{
+				X: maxPt.X - minPt.X,
+				Y: maxPt.Y - minPt.Y,
+				Z: p.height,
+			}},
+		},
+		Label: p.label,
+	}
+}
+
+// surface returns the mesh enclosing the polygon.
+func (p *polygon) surface() *Mesh {
+	return NewMesh(p.pose, p.triangles, p.label)
+}
+
+// CollidesWith checks if the given polygon collides with the given geometry and returns true if it does.
+func (p *polygon) CollidesWith(g Geometry, collisionBufferMM float64) (bool, error) {
+	dist, err := p.DistanceFrom(g)
+	if err != nil {
+		return true, err
+	}
+	return dist <= collisionBufferMM, nil
+}
+
+// DistanceFrom returns the distance between the polygon and the given geometry. It is exact for points and spheres. For other
+// geometries the distance between their surfaces is used, and geometries which are encompassed by the polygon or which encompass it
+// report the negated distance between their surfaces as a conservative estimate of their penetration depth.
+func (p *polygon) DistanceFrom(g Geometry) (float64, error) {
+	switch other := g.(type) {
+	case *point:
+		return p.pointDistance(other.position), nil
+	case *sphere:
+		return p.pointDistance(other.pose.Point()) - other.radius, nil
+	case *box, *capsule, *polygon, *Mesh:
+	default:
+		return math.Inf(-1), newCollisionTypeUnsupportedError(p, g)
+	}
+	surface, interiorPt := surfaceOf(g)
+	dist, err := p.surface().DistanceFrom(surface)
+	if err != nil || dist <= 0 {
+		return dist, err
+	}
+	// surfaces which do not meet enclose volumes which are either apart or one within the other
+	if p.pointDistance(interiorPt) <= 0 {
+		return -dist, nil
+	}
+	if _, ok := g.(*Mesh); ok {
+		return dist, nil // meshes have no volume, so cannot encompass the polygon
+	}
+	within, err := NewPoint(p.vertex(), "").CollidesWith(g, defaultCollisionBufferMM)
+	if err != nil {
+		return math.Inf(-1), err
+	}
+	if within {
+		return -dist, nil
+	}
+	return dist, nil
+}
+
+// EncompassedBy returns whether the polygon is completely contained within another geometry.
+func (p *polygon) EncompassedBy(g Geometry) (bool, error) {
+	switch other := g.(type) {
+	case *point, *Mesh:
+		return false, nil // Like points, meshes have no volume and cannot encompass
+	case *polygon:
+		// polygons need not be convex, so containing the vertices of the polygon is not enough to contain all of it
+		return geometryInPolygon(p, other)
+	case *box, *sphere, *capsule:
+		// the other geometries are convex, so containing each vertex of the polygon contains all of it
+		for _, pt := range p.ToPoints(0) {
+			collides, err := NewPoint(pt, "").CollidesWith(g, defaultCollisionBufferMM)
+			if err != nil {
+				return false, err
+			}
+			if !collides {
+				return false, nil
+			}
+		}
+		return true, nil
+	default:
+		return false, newCollisionTypeUnsupportedError(p, g)
+	}
+}
+
+// ToPoints returns the vertices of the top and bottom faces of the polygon.
+func (p *polygon) ToPoints(density float64) []r3.Vector {
+	pts := make([]r3.Vector, 0, 2*len(p.vertices))
+	for _, v := range p.vertices {
+		pts = append(pts, r3.Vector{X: v.X, Y: v.Y, Z: p.height / 2}, r3.Vector{X: v.X, Y: v.Y, Z: -p.height / 2})
+	}
+	return transformPointsToPose(pts, p.pose)
+}
+
+// pointDistance returns the signed distance from the polygon to the point, which is negative if the point is within it.
+func (p *polygon) pointDistance(pt r3.Vector) float64 {
+	local := Compose(PoseInverse(p.pose), NewPoseFromPoint(pt)).Point()
+	dz := math.Abs(local.Z) - p.height/2
+	dxy := math.Inf(1)
+	for i, a := range p.vertices {
+		b := p.vertices[(i+1)%len(p.vertices)]
+		dxy = math.Min(dxy, DistToLineSegment(r3.Vector{X: a.X, Y: a.Y}, r3.Vector{X: b.X, Y: b.Y}, r3.Vector{X: local.X, Y: local.Y}))
+	}
+	inside := pointInPolygon(r2.Point{X: local.X, Y: local.Y}, p.vertices)
+	switch {
+	case inside && dz <= 0:
+		return math.Max(-dxy, dz)
+	case inside:
+		return dz
+	case dz <= 0:
+		return dxy
+	default:
+		return math.Hypot(dxy, dz)
+	}
+}
+
+// vertex returns the first vertex of the bottom face of the polygon, which lies within it.
+func (p *polygon) vertex() r3.Vector {
+	return Compose(p.pose, NewPoseFromPoint(r3.Vector{X: p.vertices[0].X, Y: p.vertices[0].Y, Z: -p.height / 2})).Point()
+}
+
+// surfaceOf returns the surface of a geometry whose collisions with polygons are found from the surfaces of both, along with a point
+// within the geometry.
+func surfaceOf(g Geometry) (Geometry, r3.Vector) {
+	switch other := g.(type) {
+	case *polygon:
+		return other.surface(), other.vertex()
+	case *Mesh:
+		if pts := other.ToPoints(1); len(pts) > 0 {
+			return other, pts[0]
+		}
+	}
+	return g, g.Pose().Point()
+}
+
+// geometryInPolygon returns whether the geometry is encompassed by the polygon. A geometry whose surface does not meet that of the
+// polygon is either entirely within it or entirely outside of it.
+func geometryInPolygon(g Geometry, p *polygon) (bool, error) {
+	switch other := g.(type) {
+	case *point:
+		return p.pointDistance(other.position) <= 0, nil
+	case *sphere:
+		return p.pointDistance(other.pose.Point()) <= -other.radius, nil
+	case *Mesh:
+		return false, nil
+	}
+	surface, interiorPt := surfaceOf(g)
+	dist, err := p.surface().DistanceFrom(surface)
+	if err != nil {
+		return false, err
+	}
+	return dist > 0 && p.pointDistance(interiorPt) <= 0, nil
+}
+
+// signedArea returns the area enclosed by the vertices, which is positive if they are in counterclockwise order.
+func signedArea(vertices []r2.Point) float64 {
+	area := 0.
+	for i, a := range vertices {
+		area += a.Cross(vertices[(i+1)%len(vertices)])
+	}
+	return area / 2
+}
+
+// pointInPolygon returns whether the point lies within the polygon with the given vertices, by counting the edges crossed by a ray
+// cast from it.
+func pointInPolygon(pt r2.Point, vertices []r2.Point) bool {
+	inside := false
+	for i, a := range vertices {
+		b := vertices[(i+1)%len(vertices)]
+		if (a.Y > pt.Y) != (b.Y > pt.Y) && pt.X < a.X+(pt.Y-a.Y)*(b.X-a.X)/(b.Y-a.Y) {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// selfIntersecting returns whether any two edges of the polygon which do not share a vertex cross.
+func selfIntersecting(vertices []r2.Point) bool {
+	n := len(vertices)
+	for i := 0; i < n; i++ {
+		for j := i + 2; j < n; j++ {
+			if i == 0 && j == n-1 {
+				continue
+			}
+			if segmentsCross(vertices[i], vertices[(i+1)%n], vertices[j], vertices[(j+1)%n]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// segmentsCross returns whether the segments ab and cd intersect.
+func segmentsCross(a, b, c, d r2.Point) bool {
+	orientation := func(p, q, r r2.Point) float64 { return q.Sub(p).Cross(r.Sub(p)) }
+	onSegment := func(p, q, r r2.Point) bool {
+		return math.Min(p.X, q.X) <= r.X && r.X <= math.Max(p.X, q.X) && math.Min(p.Y, q.Y) <= r.Y && r.Y <= math.Max(p.Y, q.Y)
+	}
+	d1, d2, d3, d4 := orientation(c, d, a), orientation(c, d, b), orientation(a, b, c), orientation(a, b, d)
+	if ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) && ((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0)) {
+		return true
+	}
+	return (d1 == 0 && onSegment(c, d, a)) || (d2 == 0 && onSegment(c, d, b)) ||
+		(d3 == 0 && onSegment(a, b, c)) || (d4 == 0 && onSegment(a, b, d))
+}
+
+// triangulate splits the simple polygon with the given counterclockwise vertices into triangles by ear clipping, returning the
+// indices of the vertices of each triangle.
+func triangulate(vertices []r2.Point) ([][3]int, error) {
+	remaining := make([]int, len(vertices))
+	for i := range remaining {
+		remaining[i] = i
+	}
+	triangles := make([][3]int, 0, len(vertices)-2)
+	for len(remaining) > 3 {
+		clipped := false
+		for i := range remaining {
+			prev, cur, next := remaining[(i+len(remaining)-1)%len(remaining)], remaining[i], remaining[(i+1)%len(remaining)]
+			a, b, c := vertices[prev], vertices[cur], vertices[next]
+			// reflex and collinear vertices are not ears
+			if b.Sub(a).Cross(c.Sub(b)) <= 0 {
+				continue
+			}
+			ear := true
+			for _, j := range remaining {
+				if j != prev && j != cur && j != next && pointInPolygon(vertices[j], []r2.Point{a, b, c}) {
+					ear = false
+					break
+				}
+			}
+			if !ear {
+				continue
+			}
+			triangles = append(triangles, [3]int{prev, cur, next})
+			remaining = append(remaining[:i], remaining[i+1:]...)
+			clipped = true
+			break
+		}
+		if !clipped {
+			return nil, errors.New("polygon could not be triangulated")
+		}
+	}
+	return append(triangles, [3]int{remaining[0], remaining[1], remaining[2]}), nil
+}
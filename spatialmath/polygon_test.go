@@ -0,0 +1,144 @@
+package spatialmath
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/golang/geo/r2"
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+)
+
+func TestPolygon(t *testing.T) {
+	// a forklift: a body with two tines reaching forward along +Y
+	vertices := []r2.Point{
+		{-300, 0}, {300, 0}, {300, 1000}, {150, 1000}, {150, 300}, {-150, 300}, {-150, 1000}, {-300, 1000},
+	}
+	forklift, err := NewPolygon(NewZeroPose(), vertices, 200, "forklift")
+	test.That(t, err, test.ShouldBeNil)
+
+	t.Run("invalid polygons", func(t *testing.T) {
+		_, err := NewPolygon(NewZeroPose(), vertices[:2], 200, "")
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = NewPolygon(NewZeroPose(), vertices, 0, "")
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = NewPolygon(NewZeroPose(), []r2.Point{{0, 0}, {100, 0}, {200, 0}}, 200, "")
+		test.That(t, err, test.ShouldNotBeNil)
+		// a bowtie, whose edges cross
+		_, err = NewPolygon(NewZeroPose(), []r2.Point{{0, 0}, {100, 100}, {100, 0}, {0, 100}}, 200, "")
+		test.That(t, err, test.ShouldNotBeNil)
+		// clockwise vertices are accepted
+		_, err = NewPolygon(NewZeroPose(), []r2.Point{{0, 0}, {0, 100}, {100, 100}, {100, 0}}, 200, "")
+		test.That(t, err, test.ShouldBeNil)
+	})
+
+	t.Run("points", func(t *testing.T) {
+		dist, err := forklift.DistanceFrom(NewPoint(r3.Vector{Y: 600}, ""))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, dist, test.ShouldAlmostEqual, 150)
+		dist, err = forklift.DistanceFrom(NewPoint(r3.Vector{X: 225, Y: 600}, ""))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, dist, test.ShouldAlmostEqual, -75)
+		dist, err = forklift.DistanceFrom(NewPoint(r3.Vector{Y: 600, Z: 150}, ""))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, dist, test.ShouldAlmostEqual, math.Hypot(150, 50))
+		collides, err := NewPoint(r3.Vector{X: -225, Y: 900}, "").CollidesWith(forklift, defaultCollisionBufferMM)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, collides, test.ShouldBeTrue)
+	})
+
+	t.Run("between the tines", func(t *testing.T) {
+		between, err := NewSphere(NewPoseFromPoint(r3.Vector{Y: 600}), 100, "")
+		test.That(t, err, test.ShouldBeNil)
+		boxBetween, err := NewBox(NewPoseFromPoint(r3.Vector{Y: 600}), r3.Vector{X: 200, Y: 200, Z: 400}, "")
+		test.That(t, err, test.ShouldBeNil)
+		capsuleBetween, err := NewCapsule(NewPoseFromPoint(r3.Vector{Y: 600}), 100, 600, "")
+		test.That(t, err, test.ShouldBeNil)
+		polygonBetween, err := NewPolygon(NewPoseFromPoint(r3.Vector{Y: 600}), []r2.Point{{-100, -100}, {100, -100}, {0, 100}}, 50, "")
+		test.That(t, err, test.ShouldBeNil)
+		for _, g := range []Geometry{between, boxBetween, capsuleBetween, polygonBetween} {
+			collides, err := forklift.CollidesWith(g, defaultCollisionBufferMM)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, collides, test.ShouldBeFalse)
+			collides, err = g.CollidesWith(forklift, defaultCollisionBufferMM)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, collides, test.ShouldBeFalse)
+
+			// moved onto a tine, each collides
+			onTine := g.Transform(NewPoseFromPoint(r3.Vector{X: 200}))
+			collides, err = forklift.CollidesWith(onTine, defaultCollisionBufferMM)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, collides, test.ShouldBeTrue)
+			collides, err = onTine.CollidesWith(forklift, defaultCollisionBufferMM)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, collides, test.ShouldBeTrue)
+		}
+	})
+
+	t.Run("encompassing", func(t *testing.T) {
+		// a box entirely within the body collides without its surface meeting that of the forklift
+		inBody, err := NewBox(NewPoseFromPoint(r3.Vector{Y: 150}), r3.Vector{X: 100, Y: 100, Z: 100}, "")
+		test.That(t, err, test.ShouldBeNil)
+		dist, err := forklift.DistanceFrom(inBody)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, dist, test.ShouldBeLessThan, 0)
+		encompassed, err := inBody.EncompassedBy(forklift)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, encompassed, test.ShouldBeTrue)
+
+		// a box spanning the tines contains each vertex within them but not the space between them
+		spanning, err := NewBox(NewPoseFromPoint(r3.Vector{Y: 600}), r3.Vector{X: 500, Y: 100, Z: 100}, "")
+		test.That(t, err, test.ShouldBeNil)
+		encompassed, err = spanning.EncompassedBy(forklift)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, encompassed, test.ShouldBeFalse)
+
+		// the forklift is within a box around it
+		around, err := NewBox(NewPoseFromPoint(r3.Vector{Y: 500}), r3.Vector{X: 700, Y: 1100, Z: 300}, "")
+		test.That(t, err, test.ShouldBeNil)
+		collides, err := forklift.CollidesWith(around, defaultCollisionBufferMM)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, collides, test.ShouldBeTrue)
+		encompassed, err = forklift.EncompassedBy(around)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, encompassed, test.ShouldBeTrue)
+	})
+
+	t.Run("transformed", func(t *testing.T) {
+		// turned to face -X, the tines reach out to the left
+		turned := forklift.Transform(NewPoseFromOrientation(&OrientationVectorDegrees{OZ: 1, Theta: 90}))
+		collides, err := turned.CollidesWith(NewPoint(r3.Vector{X: -900, Y: 225}, ""), defaultCollisionBufferMM)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, collides, test.ShouldBeTrue)
+		collides, err = turned.CollidesWith(NewPoint(r3.Vector{X: -900}, ""), defaultCollisionBufferMM)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, collides, test.ShouldBeFalse)
+	})
+
+	t.Run("config", func(t *testing.T) {
+		config, err := NewGeometryConfig(forklift)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, config.Type, test.ShouldEqual, PolygonType)
+		data, err := json.Marshal(forklift)
+		test.That(t, err, test.ShouldBeNil)
+		var parsedConfig GeometryConfig
+		test.That(t, json.Unmarshal(data, &parsedConfig), test.ShouldBeNil)
+		parsed, err := parsedConfig.ParseConfig()
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, GeometriesAlmostEqual(forklift, parsed), test.ShouldBeTrue)
+		test.That(t, parsed.Label(), test.ShouldEqual, "forklift")
+	})
+
+	t.Run("bounds", func(t *testing.T) {
+		proto := forklift.ToProtobuf()
+		test.That(t, proto.GetBox().GetDimsMm().X, test.ShouldAlmostEqual, 600)
+		test.That(t, proto.GetBox().GetDimsMm().Y, test.ShouldAlmostEqual, 1000)
+		test.That(t, proto.GetBox().GetDimsMm().Z, test.ShouldAlmostEqual, 200)
+		test.That(t, proto.Center.Y, test.ShouldAlmostEqual, 500)
+
+		bounding, err := BoundingSphere(forklift)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, bounding.(*sphere).radius, test.ShouldAlmostEqual, math.Hypot(math.Hypot(300, 1000), 100))
+	})
+}
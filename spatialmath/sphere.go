@@ -94,6 +94,8 @@ func (s *sphere) CollidesWith(g Geometry, collisionBufferMM float64) (bool, erro
 	switch other := g.(type) {
 	case *Mesh:
 		return other.CollidesWith(s, collisionBufferMM)
+	case *polygon:
+		return other.CollidesWith(s, collisionBufferMM)
 	case *sphere:
 		return sphereVsSphereDistance(s, other) <= collisionBufferMM, nil
 	case *capsule:
@@ -111,6 +113,8 @@ func (s *sphere) DistanceFrom(g Geometry) (float64, error) {
 	switch other := g.(type) {
 	case *Mesh:
 		return other.DistanceFrom(s)
+	case *polygon:
+		return other.DistanceFrom(s)
 	case *box:
 		return sphereVsBoxDistance(s, other), nil
 	case *sphere:
@@ -128,6 +132,8 @@ func (s *sphere) EncompassedBy(g Geometry) (bool, error) {
 	switch other := g.(type) {
 	case *Mesh:
 		return false, nil // Like points, meshes have no volume and cannot encompass
+	case *polygon:
+		return geometryInPolygon(s, other)
 	case *sphere:
 		return sphereInSphere(s, other), nil
 	case *capsule:
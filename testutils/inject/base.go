@@ -24,6 +24,9 @@ type Base struct {
 	SetVelocityFunc  func(ctx context.Context, linear, angular r3.Vector, extra map[string]interface{}) error
 	PropertiesFunc   func(ctx context.Context, extra map[string]interface{}) (base.Properties, error)
 	GeometriesFunc   func(ctx context.Context) ([]spatialmath.Geometry, error)
+
+	// Faults adds latency and intermittent errors to MoveStraight, Spin, SetPower, SetVelocity and Stop.
+	Faults *Faults
 }
 
 // NewBase returns a new injected base.
@@ -38,6 +41,9 @@ func (b *Base) Name() resource.Name {
 
 // MoveStraight calls the injected MoveStraight or the real version.
 func (b *Base) MoveStraight(ctx context.Context, distanceMm int, mmPerSec float64, extra map[string]interface{}) error {
+	if err := b.Faults.apply(ctx); err != nil {
+		return err
+	}
 	if b.MoveStraightFunc == nil {
 		return b.Base.MoveStraight(ctx, distanceMm, mmPerSec, extra)
 	}
@@ -46,6 +52,9 @@ func (b *Base) MoveStraight(ctx context.Context, distanceMm int, mmPerSec float6
 
 // Spin calls the injected Spin or the real version.
 func (b *Base) Spin(ctx context.Context, angleDeg, degsPerSec float64, extra map[string]interface{}) error {
+	if err := b.Faults.apply(ctx); err != nil {
+		return err
+	}
 	if b.SpinFunc == nil {
 		return b.Base.Spin(ctx, angleDeg, degsPerSec, extra)
 	}
@@ -54,6 +63,9 @@ func (b *Base) Spin(ctx context.Context, angleDeg, degsPerSec float64, extra map
 
 // Stop calls the injected Stop or the real version.
 func (b *Base) Stop(ctx context.Context, extra map[string]interface{}) error {
+	if err := b.Faults.apply(ctx); err != nil {
+		return err
+	}
 	if b.StopFunc == nil {
 		return b.Base.Stop(ctx, extra)
 	}
@@ -89,6 +101,9 @@ func (b *Base) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[s
 
 // SetPower calls the injected SetPower or the real version.
 func (b *Base) SetPower(ctx context.Context, linear, angular r3.Vector, extra map[string]interface{}) error {
+	if err := b.Faults.apply(ctx); err != nil {
+		return err
+	}
 	if b.SetPowerFunc == nil {
 		return b.Base.SetPower(ctx, linear, angular, extra)
 	}
@@ -97,6 +112,9 @@ func (b *Base) SetPower(ctx context.Context, linear, angular r3.Vector, extra ma
 
 // SetVelocity calls the injected SetVelocity or the real version.
 func (b *Base) SetVelocity(ctx context.Context, linear, angular r3.Vector, extra map[string]interface{}) error {
+	if err := b.Faults.apply(ctx); err != nil {
+		return err
+	}
 	if b.SetVelocityFunc == nil {
 		return b.Base.SetVelocity(ctx, linear, angular, extra)
 	}
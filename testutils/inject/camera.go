@@ -3,6 +3,7 @@ package inject
 import (
 	"context"
 
+	"github.com/golang/geo/r3"
 	"github.com/pkg/errors"
 
 	"go.viam.com/rdk/components/camera"
@@ -24,6 +25,11 @@ type Camera struct {
 	ProjectorFunc        func(ctx context.Context) (transform.Projector, error)
 	PropertiesFunc       func(ctx context.Context) (camera.Properties, error)
 	CloseFunc            func(ctx context.Context) error
+
+	// Faults adds latency and intermittent errors to Image, Images and NextPointCloud.
+	Faults *Faults
+	// PointNoiseMM perturbs each coordinate of every point returned by NextPointCloud by samples in mm.
+	PointNoiseMM NoiseDistribution
 }
 
 // NewCamera returns a new injected camera.
@@ -38,17 +44,39 @@ func (c *Camera) Name() resource.Name {
 
 // NextPointCloud calls the injected NextPointCloud or the real version.
 func (c *Camera) NextPointCloud(ctx context.Context) (pointcloud.PointCloud, error) {
-	if c.NextPointCloudFunc != nil {
-		return c.NextPointCloudFunc(ctx)
+	if err := c.Faults.apply(ctx); err != nil {
+		return nil, err
 	}
-	if c.Camera != nil {
-		return c.Camera.NextPointCloud(ctx)
+	var cloud pointcloud.PointCloud
+	var err error
+	switch {
+	case c.NextPointCloudFunc != nil:
+		cloud, err = c.NextPointCloudFunc(ctx)
+	case c.Camera != nil:
+		cloud, err = c.Camera.NextPointCloud(ctx)
+	default:
+		return nil, errors.New("NextPointCloud unimplemented")
+	}
+	if err != nil || cloud == nil || c.PointNoiseMM == nil {
+		return cloud, err
 	}
-	return nil, errors.New("NextPointCloud unimplemented")
+	noisy := pointcloud.NewWithPrealloc(cloud.Size())
+	cloud.Iterate(0, 0, func(p r3.Vector, d pointcloud.Data) bool {
+		offset := r3.Vector{X: c.PointNoiseMM.Sample(), Y: c.PointNoiseMM.Sample(), Z: c.PointNoiseMM.Sample()}
+		err = noisy.Set(p.Add(offset), d)
+		return err == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return noisy, nil
 }
 
 // Image calls the injected Image or the real version.
 func (c *Camera) Image(ctx context.Context, mimeType string, extra map[string]interface{}) ([]byte, camera.ImageMetadata, error) {
+	if err := c.Faults.apply(ctx); err != nil {
+		return nil, camera.ImageMetadata{}, err
+	}
 	if c.ImageFunc != nil {
 		return c.ImageFunc(ctx, mimeType, extra)
 	}
@@ -68,6 +96,9 @@ func (c *Camera) Properties(ctx context.Context) (camera.Properties, error) {
 
 // Images calls the injected Images or the real version.
 func (c *Camera) Images(ctx context.Context) ([]camera.NamedImage, resource.ResponseMetadata, error) {
+	if err := c.Faults.apply(ctx); err != nil {
+		return nil, resource.ResponseMetadata{}, err
+	}
 	if c.ImagesFunc != nil {
 		return c.ImagesFunc(ctx)
 	}
@@ -0,0 +1,118 @@
+package inject
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	goutils "go.viam.com/utils"
+)
+
+// ErrInjectedFault is returned by an injected resource when Faults decides that a call should fail and no other error was given.
+var ErrInjectedFault = errors.New("injected fault")
+
+// NoiseDistribution produces the noise that injected resources add to the values they return.
+type NoiseDistribution interface {
+	Sample() float64
+}
+
+type seededNoise struct {
+	mu     sync.Mutex
+	rng    *rand.Rand
+	sample func(*rand.Rand) float64
+}
+
+func newSeededNoise(seed int64, sample func(*rand.Rand) float64) *seededNoise {
+	//nolint:gosec
+	return &seededNoise{rng: rand.New(rand.NewSource(seed)), sample: sample}
+}
+
+func (n *seededNoise) Sample() float64 {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.sample(n.rng)
+}
+
+// NewGaussianNoise returns a zero-mean normal distribution whose samples are deterministic for a given seed.
+func NewGaussianNoise(stdDev float64, seed int64) NoiseDistribution {
+	return newSeededNoise(seed, func(rng *rand.Rand) float64 { return rng.NormFloat64() * stdDev })
+}
+
+// NewUniformNoise returns a distribution uniform over [-halfWidth, halfWidth] whose samples are deterministic for a given seed.
+func NewUniformNoise(halfWidth float64, seed int64) NoiseDistribution {
+	return newSeededNoise(seed, func(rng *rand.Rand) float64 { return (2*rng.Float64() - 1) * halfWidth })
+}
+
+// Faults describes the latency and intermittent failures an injected resource exhibits. Faults apply whether a call is handled
+// by an injected function or passed through to the wrapped resource. A nil *Faults injects nothing.
+type Faults struct {
+	// Latency is added to every call.
+	Latency time.Duration
+	// MaxJitter adds a further uniformly distributed delay of up to this duration to every call.
+	MaxJitter time.Duration
+	// ErrorRate is the probability in [0, 1] that a call fails.
+	ErrorRate float64
+	// Err is returned by failed calls. If nil, ErrInjectedFault is returned.
+	Err error
+
+	mu    sync.Mutex
+	rng   *rand.Rand
+	calls int
+	fails int
+}
+
+// NewFaults returns Faults whose jitter and failures are deterministic for a given seed.
+func NewFaults(latency, maxJitter time.Duration, errorRate float64, seed int64) *Faults {
+	return &Faults{
+		Latency:   latency,
+		MaxJitter: maxJitter,
+		ErrorRate: errorRate,
+		//nolint:gosec
+		rng: rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Counts returns the number of calls Faults has been applied to and how many of them were failed.
+func (f *Faults) Counts() (calls, fails int) {
+	if f == nil {
+		return 0, 0
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls, f.fails
+}
+
+// apply delays the caller by the configured latency and returns an error if the call should fail.
+func (f *Faults) apply(ctx context.Context) error {
+	if f == nil {
+		return nil
+	}
+	f.mu.Lock()
+	if f.rng == nil {
+		//nolint:gosec
+		f.rng = rand.New(rand.NewSource(0))
+	}
+	delay := f.Latency
+	if f.MaxJitter > 0 {
+		delay += time.Duration(f.rng.Int63n(int64(f.MaxJitter)))
+	}
+	fail := f.ErrorRate > 0 && f.rng.Float64() < f.ErrorRate
+	f.calls++
+	if fail {
+		f.fails++
+	}
+	f.mu.Unlock()
+
+	if delay > 0 && !goutils.SelectContextOrWait(ctx, delay) {
+		return ctx.Err()
+	}
+	if fail {
+		if f.Err != nil {
+			return f.Err
+		}
+		return ErrInjectedFault
+	}
+	return nil
+}
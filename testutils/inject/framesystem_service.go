@@ -7,6 +7,7 @@ import (
 	"go.viam.com/rdk/referenceframe"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/robot/framesystem"
+	"go.viam.com/rdk/spatialmath"
 )
 
 // FrameSystemService represents a fake instance of a framesystem service.
@@ -26,8 +27,23 @@ type FrameSystemService struct {
 		srcpc pointcloud.PointCloud,
 		srcName, dstName string,
 	) (pointcloud.PointCloud, error)
-	CurrentInputsFunc func(ctx context.Context) (referenceframe.FrameSystemInputs, map[string]framesystem.InputEnabled, error)
-	FrameSystemFunc   func(
+	TransformPosesFunc func(
+		ctx context.Context,
+		poses []*referenceframe.PoseInFrame,
+		dst string,
+		additionalTransforms []*referenceframe.LinkInFrame,
+	) ([]*referenceframe.PoseInFrame, error)
+	TransformPointCloudsFunc func(
+		ctx context.Context,
+		srcpcs []framesystem.PointCloudInFrame,
+		dstName string,
+	) ([]pointcloud.PointCloud, error)
+	AttachGeometryFunc     func(ctx context.Context, frame string, geometry spatialmath.Geometry) error
+	DetachGeometryFunc     func(ctx context.Context, label string) error
+	AddTrackedFrameFunc    func(ctx context.Context, name, poseTrackerName, body string) error
+	RemoveTrackedFrameFunc func(ctx context.Context, name string) error
+	CurrentInputsFunc      func(ctx context.Context) (referenceframe.FrameSystemInputs, map[string]framesystem.InputEnabled, error)
+	FrameSystemFunc        func(
 		ctx context.Context,
 		additionalTransforms []*referenceframe.LinkInFrame,
 	) (referenceframe.FrameSystem, error)
@@ -77,6 +93,63 @@ func (fs *FrameSystemService) TransformPointCloud(
 	return fs.TransformPointCloudFunc(ctx, srcpc, srcName, dstName)
 }
 
+// TransformPoses calls the injected method or the real variant.
+func (fs *FrameSystemService) TransformPoses(
+	ctx context.Context,
+	poses []*referenceframe.PoseInFrame,
+	dst string,
+	additionalTransforms []*referenceframe.LinkInFrame,
+) ([]*referenceframe.PoseInFrame, error) {
+	if fs.TransformPosesFunc == nil {
+		return fs.Service.TransformPoses(ctx, poses, dst, additionalTransforms)
+	}
+	return fs.TransformPosesFunc(ctx, poses, dst, additionalTransforms)
+}
+
+// TransformPointClouds calls the injected method or the real variant.
+func (fs *FrameSystemService) TransformPointClouds(
+	ctx context.Context,
+	srcpcs []framesystem.PointCloudInFrame,
+	dstName string,
+) ([]pointcloud.PointCloud, error) {
+	if fs.TransformPointCloudsFunc == nil {
+		return fs.Service.TransformPointClouds(ctx, srcpcs, dstName)
+	}
+	return fs.TransformPointCloudsFunc(ctx, srcpcs, dstName)
+}
+
+// AttachGeometry calls the injected method or the real variant.
+func (fs *FrameSystemService) AttachGeometry(ctx context.Context, frame string, geometry spatialmath.Geometry) error {
+	if fs.AttachGeometryFunc == nil {
+		return fs.Service.AttachGeometry(ctx, frame, geometry)
+	}
+	return fs.AttachGeometryFunc(ctx, frame, geometry)
+}
+
+// DetachGeometry calls the injected method or the real variant.
+func (fs *FrameSystemService) DetachGeometry(ctx context.Context, label string) error {
+	if fs.DetachGeometryFunc == nil {
+		return fs.Service.DetachGeometry(ctx, label)
+	}
+	return fs.DetachGeometryFunc(ctx, label)
+}
+
+// AddTrackedFrame calls the injected method or the real variant.
+func (fs *FrameSystemService) AddTrackedFrame(ctx context.Context, name, poseTrackerName, body string) error {
+	if fs.AddTrackedFrameFunc == nil {
+		return fs.Service.AddTrackedFrame(ctx, name, poseTrackerName, body)
+	}
+	return fs.AddTrackedFrameFunc(ctx, name, poseTrackerName, body)
+}
+
+// RemoveTrackedFrame calls the injected method or the real variant.
+func (fs *FrameSystemService) RemoveTrackedFrame(ctx context.Context, name string) error {
+	if fs.RemoveTrackedFrameFunc == nil {
+		return fs.Service.RemoveTrackedFrame(ctx, name)
+	}
+	return fs.RemoveTrackedFrameFunc(ctx, name)
+}
+
 // CurrentInputs calls the injected method or the real variant.
 func (fs *FrameSystemService) CurrentInputs(
 	ctx context.Context,
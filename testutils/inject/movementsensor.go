@@ -2,6 +2,7 @@ package inject
 
 import (
 	"context"
+	"math"
 	"sync"
 
 	"github.com/golang/geo/r3"
@@ -10,6 +11,7 @@ import (
 	"go.viam.com/rdk/components/movementsensor"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/spatialmath"
+	rdkutils "go.viam.com/rdk/utils"
 )
 
 // MovementSensor is an injected MovementSensor.
@@ -37,6 +39,13 @@ type MovementSensor struct {
 	ReadingsFunc                func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error)
 	DoFunc                      func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error)
 	CloseFunc                   func() error
+
+	// Faults adds latency and intermittent errors to Position, CompassHeading, Orientation, LinearVelocity and AngularVelocity.
+	Faults *Faults
+	// PositionNoiseMM perturbs the north and east components of returned positions by samples in mm.
+	PositionNoiseMM NoiseDistribution
+	// HeadingNoiseDeg perturbs returned compass headings by samples in degrees.
+	HeadingNoiseDeg NoiseDistribution
 }
 
 // NewMovementSensor returns a new injected movement sensor.
@@ -70,17 +79,33 @@ func (i *MovementSensor) DoCommand(ctx context.Context, cmd map[string]interface
 
 // Position func or passthrough.
 func (i *MovementSensor) Position(ctx context.Context, extra map[string]interface{}) (*geo.Point, float64, error) {
+	if err := i.Faults.apply(ctx); err != nil {
+		return nil, 0, err
+	}
 	i.Mu.Lock()
 	defer i.Mu.Unlock()
+	var point *geo.Point
+	var alt float64
+	var err error
 	if i.PositionFunc == nil {
-		return i.MovementSensor.Position(ctx, extra)
+		point, alt, err = i.MovementSensor.Position(ctx, extra)
+	} else {
+		i.PositionFuncExtraCap = extra
+		point, alt, err = i.PositionFunc(ctx, extra)
 	}
-	i.PositionFuncExtraCap = extra
-	return i.PositionFunc(ctx, extra)
+	if err != nil || point == nil || i.PositionNoiseMM == nil {
+		return point, alt, err
+	}
+	north, east := i.PositionNoiseMM.Sample(), i.PositionNoiseMM.Sample()
+	bearing := rdkutils.RadToDeg(math.Atan2(east, north))
+	return point.PointAtDistanceAndBearing(math.Hypot(north, east)*1e-6, bearing), alt, nil
 }
 
 // LinearVelocity func or passthrough.
 func (i *MovementSensor) LinearVelocity(ctx context.Context, extra map[string]interface{}) (r3.Vector, error) {
+	if err := i.Faults.apply(ctx); err != nil {
+		return r3.Vector{}, err
+	}
 	if i.LinearVelocityFunc == nil {
 		return i.MovementSensor.LinearVelocity(ctx, extra)
 	}
@@ -90,6 +115,9 @@ func (i *MovementSensor) LinearVelocity(ctx context.Context, extra map[string]in
 
 // AngularVelocity func or passthrough.
 func (i *MovementSensor) AngularVelocity(ctx context.Context, extra map[string]interface{}) (spatialmath.AngularVelocity, error) {
+	if err := i.Faults.apply(ctx); err != nil {
+		return spatialmath.AngularVelocity{}, err
+	}
 	if i.AngularVelocityFunc == nil {
 		return i.MovementSensor.AngularVelocity(ctx, extra)
 	}
@@ -108,6 +136,9 @@ func (i *MovementSensor) LinearAcceleration(ctx context.Context, extra map[strin
 
 // Orientation func or passthrough.
 func (i *MovementSensor) Orientation(ctx context.Context, extra map[string]interface{}) (spatialmath.Orientation, error) {
+	if err := i.Faults.apply(ctx); err != nil {
+		return nil, err
+	}
 	if i.OrientationFunc == nil {
 		return i.MovementSensor.Orientation(ctx, extra)
 	}
@@ -117,11 +148,21 @@ func (i *MovementSensor) Orientation(ctx context.Context, extra map[string]inter
 
 // CompassHeading func or passthrough.
 func (i *MovementSensor) CompassHeading(ctx context.Context, extra map[string]interface{}) (float64, error) {
+	if err := i.Faults.apply(ctx); err != nil {
+		return 0, err
+	}
+	var heading float64
+	var err error
 	if i.CompassHeadingFunc == nil {
-		return i.MovementSensor.CompassHeading(ctx, extra)
+		heading, err = i.MovementSensor.CompassHeading(ctx, extra)
+	} else {
+		i.CompassHeadingFuncExtraCap = extra
+		heading, err = i.CompassHeadingFunc(ctx, extra)
+	}
+	if err != nil || i.HeadingNoiseDeg == nil {
+		return heading, err
 	}
-	i.CompassHeadingFuncExtraCap = extra
-	return i.CompassHeadingFunc(ctx, extra)
+	return math.Mod(math.Mod(heading+i.HeadingNoiseDeg.Sample(), 360)+360, 360), nil
 }
 
 // Properties func or passthrough.
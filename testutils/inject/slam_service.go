@@ -11,13 +11,14 @@ import (
 // SLAMService represents a fake instance of a slam service.
 type SLAMService struct {
 	slam.Service
-	name              resource.Name
-	PositionFunc      func(ctx context.Context) (spatialmath.Pose, error)
-	PointCloudMapFunc func(ctx context.Context, returnEditedMap bool) (func() ([]byte, error), error)
-	InternalStateFunc func(ctx context.Context) (func() ([]byte, error), error)
-	PropertiesFunc    func(ctx context.Context) (slam.Properties, error)
-	DoCommandFunc     func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error)
-	CloseFunc         func(ctx context.Context) error
+	name                    resource.Name
+	PositionFunc            func(ctx context.Context) (spatialmath.Pose, error)
+	PointCloudMapFunc       func(ctx context.Context, returnEditedMap bool) (func() ([]byte, error), error)
+	InternalStateFunc       func(ctx context.Context) (func() ([]byte, error), error)
+	PropertiesFunc          func(ctx context.Context) (slam.Properties, error)
+	LocalizationQualityFunc func(ctx context.Context) (slam.LocalizationQuality, error)
+	DoCommandFunc           func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error)
+	CloseFunc               func(ctx context.Context) error
 }
 
 // NewSLAMService returns a new injected SLAM service.
@@ -62,6 +63,14 @@ func (slamSvc *SLAMService) Properties(ctx context.Context) (slam.Properties, er
 	return slamSvc.PropertiesFunc(ctx)
 }
 
+// LocalizationQuality calls the injected LocalizationQualityFunc or the real version.
+func (slamSvc *SLAMService) LocalizationQuality(ctx context.Context) (slam.LocalizationQuality, error) {
+	if slamSvc.LocalizationQualityFunc == nil {
+		return slamSvc.Service.LocalizationQuality(ctx)
+	}
+	return slamSvc.LocalizationQualityFunc(ctx)
+}
+
 // DoCommand calls the injected DoCommand or the real variant.
 func (slamSvc *SLAMService) DoCommand(ctx context.Context,
 	cmd map[string]interface{},
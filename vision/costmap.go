@@ -0,0 +1,151 @@
+package vision
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/golang/geo/r3"
+
+	pc "go.viam.com/rdk/pointcloud"
+)
+
+// CostmapLabel is the label of the objects through which vision services report a 2d occupancy costmap rather than discrete
+// objects. The geometry of such an object is an octree holding the center of every occupied cell, with the cost of the cell
+// scaled to [0, 100] as its value.
+const CostmapLabel = "costmap"
+
+// CostmapConfig describes how a point cloud in a camera frame is reduced to a costmap.
+type CostmapConfig struct {
+	// CellSizeMM is the side length of each square cell.
+	CellSizeMM float64
+	// MaxRangeMM bounds the grid to points at most this far in front of and to either side of the camera.
+	MaxRangeMM float64
+	// MinHeightMM and MaxHeightMM bound the heights above the camera, along -Y, of the points counted as obstacles, so that the
+	// ground and overhangs can be excluded. No points are excluded by height if they are equal.
+	MinHeightMM float64
+	MaxHeightMM float64
+	// SaturationPoints is the number of points in a cell at which its cost reaches 1.
+	SaturationPoints int
+}
+
+// Costmap is a 2d occupancy grid in the XZ plane of a camera frame, where Z points out of the camera and X to its right.
+// The grid spans [-MaxRangeMM, MaxRangeMM] in X and [0, MaxRangeMM] in Z.
+type Costmap struct {
+	CellSizeMM float64
+	// Columns is the number of cells along X and Rows the number along Z.
+	Columns, Rows int
+	// Costs holds the cost in [0, 1] of each cell, indexed by row * Columns + column.
+	Costs []float64
+	// Heights holds the mean Y coordinate of the points in each cell, so that occupied cells can be placed at the height of the
+	// obstacles they represent.
+	Heights []float64
+
+	minX float64
+}
+
+// NewCostmap bins the points of the cloud into a costmap.
+func NewCostmap(cloud pc.PointCloud, cfg CostmapConfig) (*Costmap, error) {
+	if cfg.CellSizeMM <= 0 || cfg.MaxRangeMM <= 0 {
+		return nil, errors.New("costmap cell size and range must be positive")
+	}
+	if cfg.SaturationPoints <= 0 {
+		return nil, errors.New("costmap saturation points must be positive")
+	}
+	columns := int(math.Ceil(2 * cfg.MaxRangeMM / cfg.CellSizeMM))
+	rows := int(math.Ceil(cfg.MaxRangeMM / cfg.CellSizeMM))
+	c := &Costmap{
+		CellSizeMM: cfg.CellSizeMM,
+		Columns:    columns,
+		Rows:       rows,
+		Costs:      make([]float64, columns*rows),
+		Heights:    make([]float64, columns*rows),
+		minX:       -cfg.MaxRangeMM,
+	}
+
+	counts := make([]int, columns*rows)
+	filterHeight := cfg.MaxHeightMM > cfg.MinHeightMM
+	cloud.Iterate(0, 0, func(p r3.Vector, d pc.Data) bool {
+		if filterHeight && (-p.Y < cfg.MinHeightMM || -p.Y > cfg.MaxHeightMM) {
+			return true
+		}
+		column, row, ok := c.cell(p)
+		if !ok {
+			return true
+		}
+		i := row*columns + column
+		counts[i]++
+		c.Heights[i] += (p.Y - c.Heights[i]) / float64(counts[i])
+		return true
+	})
+	for i, count := range counts {
+		c.Costs[i] = math.Min(1, float64(count)/float64(cfg.SaturationPoints))
+	}
+	return c, nil
+}
+
+// cell returns the column and row of the cell containing the point, and whether the point lies within the grid.
+func (c *Costmap) cell(p r3.Vector) (int, int, bool) {
+	if p.Z < 0 {
+		return 0, 0, false
+	}
+	column := int(math.Floor((p.X - c.minX) / c.CellSizeMM))
+	row := int(math.Floor(p.Z / c.CellSizeMM))
+	if column < 0 || column >= c.Columns || row >= c.Rows {
+		return 0, 0, false
+	}
+	return column, row, true
+}
+
+// Cost returns the cost of the cell containing the given point in the camera frame, or zero if it lies outside the grid.
+func (c *Costmap) Cost(p r3.Vector) float64 {
+	column, row, ok := c.cell(p)
+	if !ok {
+		return 0
+	}
+	return c.Costs[row*c.Columns+column]
+}
+
+// center returns the center of the cell at the height of the points within it.
+func (c *Costmap) center(column, row int) r3.Vector {
+	return r3.Vector{
+		X: c.minX + (float64(column)+0.5)*c.CellSizeMM,
+		Y: c.Heights[row*c.Columns+column],
+		Z: (float64(row) + 0.5) * c.CellSizeMM,
+	}
+}
+
+// ToImage renders the costmap as a grayscale image with the camera at the bottom center, where brighter pixels are costlier.
+func (c *Costmap) ToImage() *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, c.Columns, c.Rows))
+	for row := 0; row < c.Rows; row++ {
+		for column := 0; column < c.Columns; column++ {
+			img.SetGray(column, c.Rows-1-row, color.Gray{Y: uint8(math.Round(255 * c.Costs[row*c.Columns+column]))})
+		}
+	}
+	return img
+}
+
+// ToObject returns the costmap as an object labeled CostmapLabel whose geometry and point cloud are an octree holding the center
+// of every cell with a cost.
+func (c *Costmap) ToObject() (*Object, error) {
+	side := 2 * math.Max(float64(c.Columns), float64(c.Rows)) * c.CellSizeMM
+	octree, err := pc.NewBasicOctree(r3.Vector{Z: float64(c.Rows) * c.CellSizeMM / 2}, side)
+	if err != nil {
+		return nil, err
+	}
+	for row := 0; row < c.Rows; row++ {
+		for column := 0; column < c.Columns; column++ {
+			cost := c.Costs[row*c.Columns+column]
+			if cost == 0 {
+				continue
+			}
+			if err := octree.Set(c.center(column, row), pc.NewValueData(int(math.Round(100*cost)))); err != nil {
+				return nil, err
+			}
+		}
+	}
+	octree.SetLabel(CostmapLabel)
+	return &Object{PointCloud: octree, Geometry: octree}, nil
+}
@@ -0,0 +1,70 @@
+package vision
+
+import (
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	pc "go.viam.com/rdk/pointcloud"
+)
+
+func TestCostmap(t *testing.T) {
+	cloud := pc.New()
+	// a dense obstacle 1m ahead of the camera, a sparse one 2m ahead and to the right, and the ground below the camera
+	for i := 0; i < 10; i++ {
+		test.That(t, cloud.Set(r3.Vector{X: 10, Y: -float64(i * 10), Z: 1010}, nil), test.ShouldBeNil)
+	}
+	test.That(t, cloud.Set(r3.Vector{X: 1510, Y: -50, Z: 2010}, nil), test.ShouldBeNil)
+	test.That(t, cloud.Set(r3.Vector{X: -1510, Y: 300, Z: 1510}, nil), test.ShouldBeNil)
+	// behind the camera and out of range
+	test.That(t, cloud.Set(r3.Vector{Z: -100}, nil), test.ShouldBeNil)
+	test.That(t, cloud.Set(r3.Vector{Z: 10000}, nil), test.ShouldBeNil)
+
+	cfg := CostmapConfig{CellSizeMM: 100, MaxRangeMM: 3000, SaturationPoints: 5}
+	costmap, err := NewCostmap(cloud, cfg)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, costmap.Columns, test.ShouldEqual, 60)
+	test.That(t, costmap.Rows, test.ShouldEqual, 30)
+	test.That(t, costmap.Cost(r3.Vector{X: 50, Z: 1050}), test.ShouldEqual, 1)
+	test.That(t, costmap.Cost(r3.Vector{X: 1550, Z: 2050}), test.ShouldAlmostEqual, 0.2)
+	test.That(t, costmap.Cost(r3.Vector{X: -1550, Z: 1550}), test.ShouldAlmostEqual, 0.2)
+	test.That(t, costmap.Cost(r3.Vector{X: 500, Z: 500}), test.ShouldEqual, 0)
+	test.That(t, costmap.Cost(r3.Vector{Z: 10000}), test.ShouldEqual, 0)
+
+	t.Run("points outside the height bounds are excluded", func(t *testing.T) {
+		cfg := cfg
+		cfg.MinHeightMM = 0
+		cfg.MaxHeightMM = 1000
+		costmap, err := NewCostmap(cloud, cfg)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, costmap.Cost(r3.Vector{X: 50, Z: 1050}), test.ShouldEqual, 1)
+		test.That(t, costmap.Cost(r3.Vector{X: -1550, Z: 1550}), test.ShouldEqual, 0)
+	})
+
+	t.Run("rendered as an image", func(t *testing.T) {
+		img := costmap.ToImage()
+		test.That(t, img.Bounds().Dx(), test.ShouldEqual, 60)
+		test.That(t, img.Bounds().Dy(), test.ShouldEqual, 30)
+		// the camera is at the bottom center of the image
+		test.That(t, img.GrayAt(30, 29-10).Y, test.ShouldEqual, 255)
+		test.That(t, img.GrayAt(30, 29).Y, test.ShouldEqual, 0)
+	})
+
+	t.Run("reported as a single object", func(t *testing.T) {
+		obj, err := costmap.ToObject()
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, obj.Geometry.Label(), test.ShouldEqual, CostmapLabel)
+		test.That(t, obj.Size(), test.ShouldEqual, 3)
+		d, ok := obj.At(50, -45, 1050)
+		test.That(t, ok, test.ShouldBeTrue)
+		test.That(t, d.Value(), test.ShouldEqual, 100)
+	})
+
+	t.Run("invalid configs", func(t *testing.T) {
+		_, err := NewCostmap(cloud, CostmapConfig{MaxRangeMM: 3000, SaturationPoints: 5})
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = NewCostmap(cloud, CostmapConfig{CellSizeMM: 100, MaxRangeMM: 3000})
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
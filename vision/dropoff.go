@@ -0,0 +1,104 @@
+package vision
+
+import (
+	"errors"
+	"math"
+
+	"github.com/golang/geo/r3"
+
+	pc "go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// DropOffLabel is the label of the objects through which vision services report negative obstacles, such as cliffs, holes and
+// stairs going down, where the floor drops away. Their geometries are boxes over the missing floor which extend from the floor up
+// to the camera, so that anything driving over the drop-off collides with them.
+const DropOffLabel = "drop_off"
+
+// DropOffConfig describes how drop-offs are found in a point cloud in the frame of a level camera looking out over the floor, with Z
+// pointing out of the camera, X to its right and Y down.
+type DropOffConfig struct {
+	// CellSizeMM is the side length of the square cells of the floor which are each classified as floor or drop-off.
+	CellSizeMM float64
+	// MaxRangeMM bounds the search to the floor at most this far in front of and to either side of the camera.
+	MaxRangeMM float64
+	// CameraHeightMM is the height of the camera above the floor, which is assumed to be flat.
+	CameraHeightMM float64
+	// MinDropMM is how far below the floor points must be for the floor to be considered to drop away.
+	MinDropMM float64
+	// MinPoints is the number of points seen through the floor of a cell for it to be a drop-off.
+	MinPoints int
+}
+
+// FindDropOffs finds where the floor drops away in front of the camera. A point below the floor was seen along a ray which passed
+// through the floor plane without hitting the floor, so the floor is missing where the ray crossed the plane rather than where the
+// point is. Each such crossing is binned into a grid of cells, and cells through which more points were seen than hit the floor are
+// drop-offs. Consecutive drop-off cells across the view of the camera are reported together as a single object.
+func FindDropOffs(cloud pc.PointCloud, cfg DropOffConfig) ([]*Object, error) {
+	if cfg.CellSizeMM <= 0 || cfg.MaxRangeMM <= 0 || cfg.CameraHeightMM <= 0 {
+		return nil, errors.New("drop-off cell size, range and camera height must be positive")
+	}
+	if cfg.MinDropMM < 0 || cfg.MinPoints <= 0 {
+		return nil, errors.New("drop-off minimum drop may not be negative and minimum points must be positive")
+	}
+	columns := int(math.Ceil(2 * cfg.MaxRangeMM / cfg.CellSizeMM))
+	rows := int(math.Ceil(cfg.MaxRangeMM / cfg.CellSizeMM))
+	cell := func(p r3.Vector) (int, bool) {
+		column := int(math.Floor((p.X + cfg.MaxRangeMM) / cfg.CellSizeMM))
+		row := int(math.Floor(p.Z / cfg.CellSizeMM))
+		if p.Z < 0 || column < 0 || column >= columns || row >= rows {
+			return 0, false
+		}
+		return row*columns + column, true
+	}
+
+	floor := make([]int, columns*rows)
+	below := make([][]r3.Vector, columns*rows)
+	cloud.Iterate(0, 0, func(p r3.Vector, d pc.Data) bool {
+		switch {
+		case p.Y > cfg.CameraHeightMM+cfg.MinDropMM:
+			if i, ok := cell(p.Mul(cfg.CameraHeightMM / p.Y)); ok {
+				below[i] = append(below[i], p)
+			}
+		case p.Y >= cfg.CameraHeightMM-cfg.MinDropMM:
+			if i, ok := cell(p); ok {
+				floor[i]++
+			}
+		}
+		return true
+	})
+	isDropOff := func(row, column int) bool {
+		i := row*columns + column
+		return len(below[i]) >= cfg.MinPoints && len(below[i]) > floor[i]
+	}
+
+	objects := []*Object{}
+	for row := 0; row < rows; row++ {
+		for column := 0; column < columns; column++ {
+			if !isDropOff(row, column) {
+				continue
+			}
+			start := column
+			seen := pc.New()
+			for ; column < columns && isDropOff(row, column); column++ {
+				for _, p := range below[row*columns+column] {
+					if err := seen.Set(p, nil); err != nil {
+						return nil, err
+					}
+				}
+			}
+			center := r3.Vector{
+				X: -cfg.MaxRangeMM + float64(start+column)*cfg.CellSizeMM/2,
+				Y: cfg.CameraHeightMM / 2,
+				Z: (float64(row) + 0.5) * cfg.CellSizeMM,
+			}
+			dims := r3.Vector{X: float64(column-start) * cfg.CellSizeMM, Y: cfg.CameraHeightMM, Z: cfg.CellSizeMM}
+			box, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(center), dims, DropOffLabel)
+			if err != nil {
+				return nil, err
+			}
+			objects = append(objects, &Object{PointCloud: seen, Geometry: box})
+		}
+	}
+	return objects, nil
+}
@@ -0,0 +1,58 @@
+package vision
+
+import (
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	pc "go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/spatialmath"
+)
+
+func TestFindDropOffs(t *testing.T) {
+	const cameraHeight = 500.
+	inHole := func(x, z float64) bool { return x > -200 && x < 200 && z > 1100 && z < 1400 }
+	cloud := pc.New()
+	for x := -975.; x < 1000; x += 50 {
+		for z := 25.; z < 2000; z += 50 {
+			if inHole(x, z) {
+				// the floor through the hole is seen 500mm below it, twice as far away along the same ray
+				test.That(t, cloud.Set(r3.Vector{X: 2 * x, Y: 2 * cameraHeight, Z: 2 * z}, nil), test.ShouldBeNil)
+				continue
+			}
+			test.That(t, cloud.Set(r3.Vector{X: x, Y: cameraHeight, Z: z}, nil), test.ShouldBeNil)
+		}
+	}
+	// an obstacle standing on the floor and a single noisy point below it are not drop-offs
+	test.That(t, cloud.Set(r3.Vector{X: 500, Y: 0, Z: 500}, nil), test.ShouldBeNil)
+	test.That(t, cloud.Set(r3.Vector{X: -1000, Y: 1000, Z: 1000}, nil), test.ShouldBeNil)
+
+	cfg := DropOffConfig{CellSizeMM: 100, MaxRangeMM: 3000, CameraHeightMM: cameraHeight, MinDropMM: 50, MinPoints: 3}
+	objects, err := FindDropOffs(cloud, cfg)
+	test.That(t, err, test.ShouldBeNil)
+	// one object for each row of cells across the hole
+	test.That(t, len(objects), test.ShouldEqual, 3)
+	for i, obj := range objects {
+		test.That(t, obj.Geometry.Label(), test.ShouldEqual, DropOffLabel)
+		test.That(t, obj.Size(), test.ShouldEqual, 16)
+		expected, err := spatialmath.NewBox(
+			spatialmath.NewPoseFromPoint(r3.Vector{Y: cameraHeight / 2, Z: 1150 + 100*float64(i)}),
+			r3.Vector{X: 400, Y: cameraHeight, Z: 100},
+			DropOffLabel,
+		)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, spatialmath.GeometriesAlmostEqual(obj.Geometry, expected), test.ShouldBeTrue)
+	}
+
+	t.Run("invalid config", func(t *testing.T) {
+		for _, cfg := range []DropOffConfig{
+			{CellSizeMM: 100, MaxRangeMM: 3000, MinPoints: 3},
+			{CellSizeMM: 100, MaxRangeMM: 3000, CameraHeightMM: 500},
+			{CellSizeMM: 100, MaxRangeMM: 3000, CameraHeightMM: 500, MinDropMM: -1, MinPoints: 3},
+		} {
+			_, err := FindDropOffs(cloud, cfg)
+			test.That(t, err, test.ShouldNotBeNil)
+		}
+	})
+}
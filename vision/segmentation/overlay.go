@@ -0,0 +1,75 @@
+package segmentation
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+
+	"github.com/fogleman/gg"
+	"github.com/golang/geo/r3"
+	"github.com/lucasb-eyer/go-colorful"
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/rimage"
+	"go.viam.com/rdk/rimage/transform"
+	"go.viam.com/rdk/vision"
+)
+
+// overlayAlpha is the opacity of the masks drawn over the image, low enough that the segmented objects remain visible.
+const overlayAlpha = 128
+
+// Overlay returns a color image with the points of each segmented object projected onto the original image as a colored mask,
+// labelled at the center of the object. The objects must be in the frame of the camera with the given intrinsics.
+func Overlay(img image.Image, objects []*vision.Object, intrinsics *transform.PinholeCameraIntrinsics) (image.Image, error) {
+	if intrinsics == nil {
+		return nil, transform.NewNoIntrinsicsError("cannot project segmented objects onto the image")
+	}
+	bounds := img.Bounds()
+	resultImg := image.NewNRGBA(bounds) // to keep the original image intact
+	draw.Draw(resultImg, bounds, img, image.Point{}, draw.Src)
+	if len(objects) == 0 {
+		return resultImg, nil
+	}
+
+	masks := image.NewNRGBA(bounds)
+	labels := gg.NewContext(bounds.Dx(), bounds.Dy())
+	palette := colorful.FastWarmPalette(len(objects))
+	for i, obj := range objects {
+		if obj == nil || obj.PointCloud == nil {
+			return nil, errors.Errorf("segmented object %d has no point cloud", i)
+		}
+		r, g, b := palette[i].RGB255()
+		c := color.NRGBA{r, g, b, overlayAlpha}
+		var center r3.Vector
+		projected := 0
+		obj.Iterate(0, 0, func(p r3.Vector, d pointcloud.Data) bool {
+			// points behind the camera do not appear in the image
+			if p.Z <= 0 {
+				return true
+			}
+			x, y := intrinsics.PointToPixel(p.X, p.Y, p.Z)
+			pt := image.Point{int(x), int(y)}
+			if pt.In(bounds) {
+				masks.SetNRGBA(pt.X, pt.Y, c)
+				center = center.Add(r3.Vector{X: x, Y: y})
+				projected++
+			}
+			return true
+		})
+		if projected == 0 {
+			continue
+		}
+		center = center.Mul(1 / float64(projected))
+		label := fmt.Sprintf("object %d", i)
+		if obj.Geometry != nil && obj.Geometry.Label() != "" {
+			label = obj.Geometry.Label()
+		}
+		rimage.DrawString(labels, label, image.Point{int(center.X), int(center.Y)}, color.NRGBA{r, g, b, 255}, 20)
+	}
+	draw.Draw(resultImg, bounds, masks, bounds.Min, draw.Over)
+	labelImg := labels.Image()
+	draw.DrawMask(resultImg, bounds, labelImg, image.Point{}, labelImg, image.Point{}, draw.Over)
+	return resultImg, nil
+}
@@ -0,0 +1,40 @@
+package segmentation
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/rimage/transform"
+	"go.viam.com/rdk/vision"
+)
+
+func TestOverlay(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 20, 10))
+	intrinsics := &transform.PinholeCameraIntrinsics{Width: 20, Height: 10, Fx: 10, Fy: 10, Ppx: 10, Ppy: 5}
+
+	_, err := Overlay(img, nil, nil)
+	test.That(t, err, test.ShouldWrap, transform.ErrNoIntrinsics)
+	out, err := Overlay(img, nil, intrinsics)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, out, test.ShouldResemble, img)
+	_, err = Overlay(img, []*vision.Object{{}}, intrinsics)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	cloud := pointcloud.New()
+	test.That(t, cloud.Set(pointcloud.NewVector(0, 0, 100), nil), test.ShouldBeNil)
+	// points behind the camera and outside of the image are left out
+	test.That(t, cloud.Set(pointcloud.NewVector(0, 0, -100), nil), test.ShouldBeNil)
+	test.That(t, cloud.Set(pointcloud.NewVector(1000, 0, 100), nil), test.ShouldBeNil)
+	obj, err := vision.NewObject(cloud)
+	test.That(t, err, test.ShouldBeNil)
+	out, err = Overlay(img, []*vision.Object{obj}, intrinsics)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, out.At(10, 5), test.ShouldNotResemble, color.NRGBA{})
+	test.That(t, out.At(0, 9), test.ShouldResemble, color.NRGBA{})
+	// the original image is left intact
+	test.That(t, img.At(10, 5), test.ShouldResemble, color.NRGBA{})
+}